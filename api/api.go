@@ -7,7 +7,7 @@ import (
 	"time"
 )
 
-//go:generate go tool mockgen -package api -destination mock.go github.com/evcc-io/evcc/api Charger,ChargeState,CurrentLimiter,CurrentGetter,PhaseSwitcher,PhaseGetter,FeatureDescriber,Identifier,Meter,MeterEnergy,PhaseCurrents,Vehicle,ConnectionTimer,ChargeRater,Battery,BatteryController,BatterySocLimiter,Circuit,Dimmer,Tariff
+//go:generate go tool mockgen -package api -destination mock.go github.com/evcc-io/evcc/api Charger,ChargeState,CurrentLimiter,CurrentGetter,PhaseSwitcher,PhaseGetter,FeatureDescriber,Identifier,Meter,MeterEnergy,PhaseCurrents,Vehicle,ConnectionTimer,ChargeRater,Battery,BatteryController,BatterySocLimiter,Circuit,Dimmer,Tariff,Reserver,ChargingPlanner
 
 // Meter provides total active power in W
 type Meter interface {
@@ -110,6 +110,11 @@ type Diagnosis interface {
 	Diagnose()
 }
 
+// FaultDiagnosis provides a human-readable description of the charger's current fault, empty if there is none
+type FaultDiagnosis interface {
+	Fault() (string, error)
+}
+
 // ChargeTimer provides current charge cycle duration
 type ChargeTimer interface {
 	ChargeDuration() (time.Duration, error)
@@ -135,6 +140,22 @@ type Authorizer interface {
 	Authorize(key string) error
 }
 
+// Reserver reserves the charger for a vehicle's identifier until expiry
+type Reserver interface {
+	Reserve(expiry time.Time, id string) error
+	CancelReservation() error
+}
+
+// ConnectorUnlocker unlocks the charger's connector, e.g. to release a cable stuck after a failed session
+type ConnectorUnlocker interface {
+	UnlockConnector() error
+}
+
+// ChargingPlanner follows the charging plan's slots autonomously while not controlled by evcc
+type ChargingPlanner interface {
+	SetChargingPlan(slots Rates, current float64) error
+}
+
 // PhaseDescriber returns the number of physically connected phases
 // Used for vehicles and to limit switch sockets to 1p only
 type PhaseDescriber interface {
@@ -19,18 +19,27 @@ import (
 
 type All struct {
 	Network      Network
+	Tls          Tls
 	Log          string
+	LogFormat    string // text or json
 	SponsorToken string
 	Plant        string // telemetry plant id
 	Telemetry    bool
 	Mcp          bool
 	Metrics      bool
 	Profile      bool
+	LowFootprint bool
 	Levels       map[string]string
 	Interval     time.Duration
 	Database     DB
 	Mqtt         Mqtt
 	ModbusProxy  []ModbusProxy
+	ModbusServer ModbusServer
+	Grpc         Grpc
+	Remote       Remote
+	Ocpi         Ocpi
+	Ocpp         Ocpp
+	Kiosk        Kiosk
 	Javascript   []Javascript
 	Go           []Go
 	Influx       Influx
@@ -63,6 +72,116 @@ type ModbusProxy struct {
 	modbus.Settings `mapstructure:",squash" yaml:",inline,omitempty" json:"settings,omitempty"`
 }
 
+// ModbusServer exposes site and loadpoint data as modbus registers
+type ModbusServer struct {
+	Port     int  `json:"port"`
+	Writable bool `json:"writable,omitempty"`
+}
+
+// Grpc exposes site state streaming and control via grpc
+type Grpc struct {
+	Port int `json:"port"`
+}
+
+// Remote connects to a relay for accessing the UI and api from outside the local network
+type Remote struct {
+	Relay string `json:"relay"` // relay websocket url
+	Token string `json:"token"`
+}
+
+var _ api.Redactor = (*Remote)(nil)
+
+// Redacted implements the redactor interface used by the tee publisher
+func (c Remote) Redacted() any {
+	return Remote{
+		Relay: c.Relay,
+		Token: masked(c.Token),
+	}
+}
+
+// Ocpi exposes the loadpoints as OCPI 2.2 charge point operator to roaming platforms
+type Ocpi struct {
+	Token       string       `json:"token"`       // token A for initial registration
+	CountryCode string       `json:"countryCode"` // ISO 3166-1 alpha-2
+	PartyId     string       `json:"partyId"`
+	Currency    string       `json:"currency,omitempty"`
+	Location    OcpiLocation `json:"location"`
+}
+
+// OcpiLocation describes the charging location
+type OcpiLocation struct {
+	Id         string `json:"id"`
+	Name       string `json:"name"`
+	Address    string `json:"address"`
+	City       string `json:"city"`
+	PostalCode string `json:"postalCode"`
+	Country    string `json:"country"` // ISO 3166-1 alpha-3
+	Latitude   string `json:"latitude"`
+	Longitude  string `json:"longitude"`
+}
+
+var _ api.Redactor = (*Ocpi)(nil)
+
+// Redacted implements the redactor interface used by the tee publisher
+func (c Ocpi) Redacted() any {
+	res := c
+	res.Token = masked(c.Token)
+	return res
+}
+
+// Ocpp configures the central system for OCPP chargers
+type Ocpp struct {
+	Port          int       `json:"port,omitempty"`   // defaults to 8887
+	Listen        []string  `json:"listen,omitempty"` // addresses or interfaces, network listen addresses if empty
+	Prefix        string    `json:"prefix,omitempty"` // url path preceding the station id, e.g. behind reverse proxies
+	Tls           OcppTls   `json:"tls"`
+	Secret        string    `json:"secret,omitempty"`        // basic auth password of charge points without credentials
+	CA            string    `json:"ca,omitempty"`            // directory of the certificate authority signing charge point certificates
+	Firmware      string    `json:"firmware,omitempty"`      // directory of firmware files served to charge points
+	Diagnostics   string    `json:"diagnostics,omitempty"`   // directory of diagnostics files uploaded by charge points
+	Trace         string    `json:"trace,omitempty"`         // directory of message trace files per charge point
+	Authorization string    `json:"authorization,omitempty"` // id tags accepted by charge points: all (default) or known vehicle identifiers
+	Shutdown      string    `json:"shutdown,omitempty"`      // action applied to running transactions on exit: stop or suspend
+	Queue         OcppQueue `json:"queue"`
+}
+
+var _ api.Redactor = (*Ocpp)(nil)
+
+// Redacted implements the redactor interface used by the tee publisher
+func (c Ocpp) Redacted() any {
+	res := c
+	res.Secret = masked(c.Secret)
+	return res
+}
+
+// OcppTls serves wss:// for security profiles 2 and 3. Files are reloaded when changed.
+type OcppTls struct {
+	Certificate string `json:"certificate,omitempty"` // pem file
+	Key         string `json:"key,omitempty"`
+	ClientCA    string `json:"clientCA,omitempty"` // pem file, enables client certificate verification (mutual tls)
+}
+
+type OcppQueue struct {
+	Size   int    `json:"size,omitempty"`   // requests queued per charge point
+	Policy string `json:"policy,omitempty"` // applied to new requests when full: reject (default) or drop the oldest
+}
+
+// Kiosk provides a public read-only status page for displays
+type Kiosk struct {
+	Token string `json:"token"`
+	Limit int    `json:"limit"` // requests per minute and client
+}
+
+var _ api.Redactor = (*Kiosk)(nil)
+
+// Redacted implements the redactor interface used by the tee publisher
+func (c Kiosk) Redacted() any {
+	return Kiosk{
+		Token: masked(c.Token),
+		Limit: c.Limit,
+	}
+}
+
 var _ api.Redactor = (*Hems)(nil)
 
 type Hems config.Typed
@@ -86,7 +205,18 @@ func masked(s any) string {
 
 type Mqtt struct {
 	mqtt.Config `mapstructure:",squash"`
-	Topic       string `json:"topic"`
+	Topic       string     `json:"topic"`
+	Schema      MqttSchema `json:"schema,omitempty"`
+	Discovery   string     `json:"discovery,omitempty"` // Home Assistant discovery prefix, empty to disable
+	Homie       string     `json:"homie,omitempty"`     // Homie device id, empty to disable
+	Source      string     `json:"source,omitempty"`    // local address or interface of the broker connection
+}
+
+// MqttSchema is the MQTT topic layout. Topics must start with the {root} placeholder.
+type MqttSchema struct {
+	Site      string `json:"site,omitempty"`      // default {root}/site
+	Loadpoint string `json:"loadpoint,omitempty"` // default {root}/loadpoints/{id}
+	Vehicle   string `json:"vehicle,omitempty"`   // default {root}/vehicles/{name}
 }
 
 // Redacted implements the redactor interface used by the tee publisher
@@ -102,7 +232,11 @@ func (m Mqtt) Redacted() any {
 			ClientCert: masked(m.ClientCert),
 			ClientKey:  masked(m.ClientKey),
 		},
-		Topic: m.Topic,
+		Topic:     m.Topic,
+		Schema:    m.Schema,
+		Discovery: m.Discovery,
+		Homie:     m.Homie,
+		Source:    m.Source,
 	}
 }
 
@@ -131,13 +265,25 @@ func (c Influx) Redacted() any {
 }
 
 type DB struct {
-	Type string
-	Dsn  string
+	Type      string
+	Dsn       string
+	Sqlite    string        // sqlite database copied into an empty postgres or mysql database, defaults to ~/.evcc/evcc.db
+	Flush     time.Duration // interval for persisting coalesced writes
+	Retention Retention
+}
+
+// Retention configures how long internally stored data is kept, zero keeps data forever
+type Retention struct {
+	Metrics  time.Duration // raw 15min metrics, older values are downsampled to hourly aggregates
+	Sessions time.Duration // charging sessions
+	Audit    time.Duration // audit log
 }
 
 type Messaging struct {
 	Events   map[string]MessagingEventTemplate
+	Language string // template value formatting, defaults to system language
 	Services []config.Typed
+	Webhooks []Webhook
 }
 
 // MessagingEventTemplate is the push message configuration for an event
@@ -145,8 +291,20 @@ type MessagingEventTemplate struct {
 	Title, Msg string
 }
 
+// Webhook is the outbound http request configuration for events
+type Webhook struct {
+	Uri     string
+	Method  string
+	Events  []string // empty for all events
+	Headers map[string]string
+	Body    string // payload template, default json of all event attributes
+	Secret  string // HMAC-SHA256 signing secret
+	Retries int
+	Timeout time.Duration
+}
+
 func (c Messaging) Configured() bool {
-	return len(c.Services) > 0 || len(c.Events) > 0
+	return len(c.Services) > 0 || len(c.Events) > 0 || len(c.Webhooks) > 0
 }
 
 type Tariffs struct {
@@ -159,10 +317,29 @@ type Tariffs struct {
 }
 
 type Network struct {
-	Schema_     string `json:"schema,omitempty" mapstructure:"schema"` // TODO deprecated
-	ExternalUrl string `json:"externalUrl"`
-	Host        string `json:"host"`
+	Schema_     string   `json:"schema,omitempty" mapstructure:"schema"` // TODO deprecated
+	ExternalUrl string   `json:"externalUrl"`
+	Host        string   `json:"host"`
+	Port        int      `json:"port"`
+	Listen      []string `json:"listen,omitempty"` // addresses or interfaces of all listeners, all interfaces if empty
+}
+
+// Tls configures the https listener for UI and api
+type Tls struct {
 	Port        int    `json:"port"`
+	Certificate string `json:"certificate,omitempty"` // pem file, takes precedence over acme
+	Key         string `json:"key,omitempty"`
+	Cache       string `json:"cache,omitempty"` // certificate storage directory
+	Acme        Acme   `json:"acme"`
+}
+
+// Acme configures automatic certificates. Self-signed certificates are used if no domains are given.
+type Acme struct {
+	Domains   []string `json:"domains,omitempty"`
+	Email     string   `json:"email,omitempty"`
+	Challenge string   `json:"challenge,omitempty"` // http (default), tls-alpn or dns
+	DnsHook   string   `json:"dnsHook,omitempty"`   // script creating the dns-01 TXT record
+	Directory string   `json:"directory,omitempty"` // defaults to Let's Encrypt
 }
 
 func (c Network) HostPort() string {
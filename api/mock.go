@@ -1,9 +1,9 @@
 // Code generated by MockGen. DO NOT EDIT.
-// Source: github.com/evcc-io/evcc/api (interfaces: Charger,ChargeState,CurrentLimiter,CurrentGetter,PhaseSwitcher,PhaseGetter,FeatureDescriber,Identifier,Meter,MeterEnergy,PhaseCurrents,Vehicle,ConnectionTimer,ChargeRater,Battery,BatteryController,BatterySocLimiter,Circuit,Dimmer,Tariff)
+// Source: github.com/evcc-io/evcc/api (interfaces: Charger,ChargeState,CurrentLimiter,CurrentGetter,PhaseSwitcher,PhaseGetter,FeatureDescriber,Identifier,Meter,MeterEnergy,PhaseCurrents,Vehicle,ConnectionTimer,ChargeRater,Battery,BatteryController,BatterySocLimiter,Circuit,Dimmer,Tariff,Reserver,ChargingPlanner)
 //
 // Generated by this command:
 //
-//	mockgen -package api -destination mock.go github.com/evcc-io/evcc/api Charger,ChargeState,CurrentLimiter,CurrentGetter,PhaseSwitcher,PhaseGetter,FeatureDescriber,Identifier,Meter,MeterEnergy,PhaseCurrents,Vehicle,ConnectionTimer,ChargeRater,Battery,BatteryController,BatterySocLimiter,Circuit,Dimmer,Tariff
+//	mockgen -package api -destination mock.go github.com/evcc-io/evcc/api Charger,ChargeState,CurrentLimiter,CurrentGetter,PhaseSwitcher,PhaseGetter,FeatureDescriber,Identifier,Meter,MeterEnergy,PhaseCurrents,Vehicle,ConnectionTimer,ChargeRater,Battery,BatteryController,BatterySocLimiter,Circuit,Dimmer,Tariff,Reserver,ChargingPlanner
 //
 
 // Package api is a generated GoMock package.
@@ -1189,3 +1189,93 @@ func (mr *MockTariffMockRecorder) Type() *gomock.Call {
 	mr.mock.ctrl.T.Helper()
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Type", reflect.TypeOf((*MockTariff)(nil).Type))
 }
+
+// MockReserver is a mock of Reserver interface.
+type MockReserver struct {
+	ctrl     *gomock.Controller
+	recorder *MockReserverMockRecorder
+	isgomock struct{}
+}
+
+// MockReserverMockRecorder is the mock recorder for MockReserver.
+type MockReserverMockRecorder struct {
+	mock *MockReserver
+}
+
+// NewMockReserver creates a new mock instance.
+func NewMockReserver(ctrl *gomock.Controller) *MockReserver {
+	mock := &MockReserver{ctrl: ctrl}
+	mock.recorder = &MockReserverMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockReserver) EXPECT() *MockReserverMockRecorder {
+	return m.recorder
+}
+
+// CancelReservation mocks base method.
+func (m *MockReserver) CancelReservation() error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CancelReservation")
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// CancelReservation indicates an expected call of CancelReservation.
+func (mr *MockReserverMockRecorder) CancelReservation() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CancelReservation", reflect.TypeOf((*MockReserver)(nil).CancelReservation))
+}
+
+// Reserve mocks base method.
+func (m *MockReserver) Reserve(expiry time.Time, id string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Reserve", expiry, id)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Reserve indicates an expected call of Reserve.
+func (mr *MockReserverMockRecorder) Reserve(expiry, id any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Reserve", reflect.TypeOf((*MockReserver)(nil).Reserve), expiry, id)
+}
+
+// MockChargingPlanner is a mock of ChargingPlanner interface.
+type MockChargingPlanner struct {
+	ctrl     *gomock.Controller
+	recorder *MockChargingPlannerMockRecorder
+	isgomock struct{}
+}
+
+// MockChargingPlannerMockRecorder is the mock recorder for MockChargingPlanner.
+type MockChargingPlannerMockRecorder struct {
+	mock *MockChargingPlanner
+}
+
+// NewMockChargingPlanner creates a new mock instance.
+func NewMockChargingPlanner(ctrl *gomock.Controller) *MockChargingPlanner {
+	mock := &MockChargingPlanner{ctrl: ctrl}
+	mock.recorder = &MockChargingPlannerMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockChargingPlanner) EXPECT() *MockChargingPlannerMockRecorder {
+	return m.recorder
+}
+
+// SetChargingPlan mocks base method.
+func (m *MockChargingPlanner) SetChargingPlan(slots Rates, current float64) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SetChargingPlan", slots, current)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// SetChargingPlan indicates an expected call of SetChargingPlan.
+func (mr *MockChargingPlannerMockRecorder) SetChargingPlan(slots, current any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SetChargingPlan", reflect.TypeOf((*MockChargingPlanner)(nil).SetChargingPlan), slots, current)
+}
@@ -0,0 +1,469 @@
+// Code generated by protoc-gen-go. DO NOT EDIT.
+// versions:
+// 	protoc-gen-go v1.36.10
+// 	protoc        v3.21.12
+// source: proto/site.proto
+
+package pb
+
+import (
+	protoreflect "google.golang.org/protobuf/reflect/protoreflect"
+	protoimpl "google.golang.org/protobuf/runtime/protoimpl"
+	reflect "reflect"
+	sync "sync"
+	unsafe "unsafe"
+)
+
+const (
+	// Verify that this generated code is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(20 - protoimpl.MinVersion)
+	// Verify that runtime/protoimpl is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(protoimpl.MaxVersion - 20)
+)
+
+type SubscribeRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Keys          []string               `protobuf:"bytes,1,rep,name=keys,proto3" json:"keys,omitempty"`
+	Loadpoints    []int32                `protobuf:"varint,2,rep,packed,name=loadpoints,proto3" json:"loadpoints,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *SubscribeRequest) Reset() {
+	*x = SubscribeRequest{}
+	mi := &file_proto_site_proto_msgTypes[0]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *SubscribeRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*SubscribeRequest) ProtoMessage() {}
+
+func (x *SubscribeRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_site_proto_msgTypes[0]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use SubscribeRequest.ProtoReflect.Descriptor instead.
+func (*SubscribeRequest) Descriptor() ([]byte, []int) {
+	return file_proto_site_proto_rawDescGZIP(), []int{0}
+}
+
+func (x *SubscribeRequest) GetKeys() []string {
+	if x != nil {
+		return x.Keys
+	}
+	return nil
+}
+
+func (x *SubscribeRequest) GetLoadpoints() []int32 {
+	if x != nil {
+		return x.Loadpoints
+	}
+	return nil
+}
+
+type Update struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Loadpoint     int32                  `protobuf:"varint,1,opt,name=loadpoint,proto3" json:"loadpoint,omitempty"`
+	Key           string                 `protobuf:"bytes,2,opt,name=key,proto3" json:"key,omitempty"`
+	Value         string                 `protobuf:"bytes,3,opt,name=value,proto3" json:"value,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *Update) Reset() {
+	*x = Update{}
+	mi := &file_proto_site_proto_msgTypes[1]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Update) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Update) ProtoMessage() {}
+
+func (x *Update) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_site_proto_msgTypes[1]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Update.ProtoReflect.Descriptor instead.
+func (*Update) Descriptor() ([]byte, []int) {
+	return file_proto_site_proto_rawDescGZIP(), []int{1}
+}
+
+func (x *Update) GetLoadpoint() int32 {
+	if x != nil {
+		return x.Loadpoint
+	}
+	return 0
+}
+
+func (x *Update) GetKey() string {
+	if x != nil {
+		return x.Key
+	}
+	return ""
+}
+
+func (x *Update) GetValue() string {
+	if x != nil {
+		return x.Value
+	}
+	return ""
+}
+
+type ModeRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Loadpoint     int32                  `protobuf:"varint,1,opt,name=loadpoint,proto3" json:"loadpoint,omitempty"`
+	Mode          string                 `protobuf:"bytes,2,opt,name=mode,proto3" json:"mode,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ModeRequest) Reset() {
+	*x = ModeRequest{}
+	mi := &file_proto_site_proto_msgTypes[2]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ModeRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ModeRequest) ProtoMessage() {}
+
+func (x *ModeRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_site_proto_msgTypes[2]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ModeRequest.ProtoReflect.Descriptor instead.
+func (*ModeRequest) Descriptor() ([]byte, []int) {
+	return file_proto_site_proto_rawDescGZIP(), []int{2}
+}
+
+func (x *ModeRequest) GetLoadpoint() int32 {
+	if x != nil {
+		return x.Loadpoint
+	}
+	return 0
+}
+
+func (x *ModeRequest) GetMode() string {
+	if x != nil {
+		return x.Mode
+	}
+	return ""
+}
+
+type LimitSocRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Loadpoint     int32                  `protobuf:"varint,1,opt,name=loadpoint,proto3" json:"loadpoint,omitempty"`
+	Soc           int32                  `protobuf:"varint,2,opt,name=soc,proto3" json:"soc,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *LimitSocRequest) Reset() {
+	*x = LimitSocRequest{}
+	mi := &file_proto_site_proto_msgTypes[3]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *LimitSocRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*LimitSocRequest) ProtoMessage() {}
+
+func (x *LimitSocRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_site_proto_msgTypes[3]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use LimitSocRequest.ProtoReflect.Descriptor instead.
+func (*LimitSocRequest) Descriptor() ([]byte, []int) {
+	return file_proto_site_proto_rawDescGZIP(), []int{3}
+}
+
+func (x *LimitSocRequest) GetLoadpoint() int32 {
+	if x != nil {
+		return x.Loadpoint
+	}
+	return 0
+}
+
+func (x *LimitSocRequest) GetSoc() int32 {
+	if x != nil {
+		return x.Soc
+	}
+	return 0
+}
+
+type LimitEnergyRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Loadpoint     int32                  `protobuf:"varint,1,opt,name=loadpoint,proto3" json:"loadpoint,omitempty"`
+	Energy        float64                `protobuf:"fixed64,2,opt,name=energy,proto3" json:"energy,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *LimitEnergyRequest) Reset() {
+	*x = LimitEnergyRequest{}
+	mi := &file_proto_site_proto_msgTypes[4]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *LimitEnergyRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*LimitEnergyRequest) ProtoMessage() {}
+
+func (x *LimitEnergyRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_site_proto_msgTypes[4]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use LimitEnergyRequest.ProtoReflect.Descriptor instead.
+func (*LimitEnergyRequest) Descriptor() ([]byte, []int) {
+	return file_proto_site_proto_rawDescGZIP(), []int{4}
+}
+
+func (x *LimitEnergyRequest) GetLoadpoint() int32 {
+	if x != nil {
+		return x.Loadpoint
+	}
+	return 0
+}
+
+func (x *LimitEnergyRequest) GetEnergy() float64 {
+	if x != nil {
+		return x.Energy
+	}
+	return 0
+}
+
+type SocRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Soc           float64                `protobuf:"fixed64,1,opt,name=soc,proto3" json:"soc,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *SocRequest) Reset() {
+	*x = SocRequest{}
+	mi := &file_proto_site_proto_msgTypes[5]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *SocRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*SocRequest) ProtoMessage() {}
+
+func (x *SocRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_site_proto_msgTypes[5]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use SocRequest.ProtoReflect.Descriptor instead.
+func (*SocRequest) Descriptor() ([]byte, []int) {
+	return file_proto_site_proto_rawDescGZIP(), []int{5}
+}
+
+func (x *SocRequest) GetSoc() float64 {
+	if x != nil {
+		return x.Soc
+	}
+	return 0
+}
+
+type ControlReply struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ControlReply) Reset() {
+	*x = ControlReply{}
+	mi := &file_proto_site_proto_msgTypes[6]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ControlReply) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ControlReply) ProtoMessage() {}
+
+func (x *ControlReply) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_site_proto_msgTypes[6]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ControlReply.ProtoReflect.Descriptor instead.
+func (*ControlReply) Descriptor() ([]byte, []int) {
+	return file_proto_site_proto_rawDescGZIP(), []int{6}
+}
+
+var File_proto_site_proto protoreflect.FileDescriptor
+
+const file_proto_site_proto_rawDesc = "" +
+	"\n" +
+	"\x10proto/site.proto\"F\n" +
+	"\x10SubscribeRequest\x12\x12\n" +
+	"\x04keys\x18\x01 \x03(\tR\x04keys\x12\x1e\n" +
+	"\n" +
+	"loadpoints\x18\x02 \x03(\x05R\n" +
+	"loadpoints\"N\n" +
+	"\x06Update\x12\x1c\n" +
+	"\tloadpoint\x18\x01 \x01(\x05R\tloadpoint\x12\x10\n" +
+	"\x03key\x18\x02 \x01(\tR\x03key\x12\x14\n" +
+	"\x05value\x18\x03 \x01(\tR\x05value\"?\n" +
+	"\vModeRequest\x12\x1c\n" +
+	"\tloadpoint\x18\x01 \x01(\x05R\tloadpoint\x12\x12\n" +
+	"\x04mode\x18\x02 \x01(\tR\x04mode\"A\n" +
+	"\x0fLimitSocRequest\x12\x1c\n" +
+	"\tloadpoint\x18\x01 \x01(\x05R\tloadpoint\x12\x10\n" +
+	"\x03soc\x18\x02 \x01(\x05R\x03soc\"J\n" +
+	"\x12LimitEnergyRequest\x12\x1c\n" +
+	"\tloadpoint\x18\x01 \x01(\x05R\tloadpoint\x12\x16\n" +
+	"\x06energy\x18\x02 \x01(\x01R\x06energy\"\x1e\n" +
+	"\n" +
+	"SocRequest\x12\x10\n" +
+	"\x03soc\x18\x01 \x01(\x01R\x03soc\"\x0e\n" +
+	"\fControlReply2\xf5\x01\n" +
+	"\x04Site\x12+\n" +
+	"\tSubscribe\x12\x11.SubscribeRequest\x1a\a.Update\"\x000\x01\x12(\n" +
+	"\aSetMode\x12\f.ModeRequest\x1a\r.ControlReply\"\x00\x120\n" +
+	"\vSetLimitSoc\x12\x10.LimitSocRequest\x1a\r.ControlReply\"\x00\x126\n" +
+	"\x0eSetLimitEnergy\x12\x13.LimitEnergyRequest\x1a\r.ControlReply\"\x00\x12,\n" +
+	"\fSetBufferSoc\x12\v.SocRequest\x1a\r.ControlReply\"\x00B\n" +
+	"Z\bproto/pbb\x06proto3"
+
+var (
+	file_proto_site_proto_rawDescOnce sync.Once
+	file_proto_site_proto_rawDescData []byte
+)
+
+func file_proto_site_proto_rawDescGZIP() []byte {
+	file_proto_site_proto_rawDescOnce.Do(func() {
+		file_proto_site_proto_rawDescData = protoimpl.X.CompressGZIP(unsafe.Slice(unsafe.StringData(file_proto_site_proto_rawDesc), len(file_proto_site_proto_rawDesc)))
+	})
+	return file_proto_site_proto_rawDescData
+}
+
+var file_proto_site_proto_msgTypes = make([]protoimpl.MessageInfo, 7)
+var file_proto_site_proto_goTypes = []any{
+	(*SubscribeRequest)(nil),   // 0: SubscribeRequest
+	(*Update)(nil),             // 1: Update
+	(*ModeRequest)(nil),        // 2: ModeRequest
+	(*LimitSocRequest)(nil),    // 3: LimitSocRequest
+	(*LimitEnergyRequest)(nil), // 4: LimitEnergyRequest
+	(*SocRequest)(nil),         // 5: SocRequest
+	(*ControlReply)(nil),       // 6: ControlReply
+}
+var file_proto_site_proto_depIdxs = []int32{
+	0, // 0: Site.Subscribe:input_type -> SubscribeRequest
+	2, // 1: Site.SetMode:input_type -> ModeRequest
+	3, // 2: Site.SetLimitSoc:input_type -> LimitSocRequest
+	4, // 3: Site.SetLimitEnergy:input_type -> LimitEnergyRequest
+	5, // 4: Site.SetBufferSoc:input_type -> SocRequest
+	1, // 5: Site.Subscribe:output_type -> Update
+	6, // 6: Site.SetMode:output_type -> ControlReply
+	6, // 7: Site.SetLimitSoc:output_type -> ControlReply
+	6, // 8: Site.SetLimitEnergy:output_type -> ControlReply
+	6, // 9: Site.SetBufferSoc:output_type -> ControlReply
+	5, // [5:10] is the sub-list for method output_type
+	0, // [0:5] is the sub-list for method input_type
+	0, // [0:0] is the sub-list for extension type_name
+	0, // [0:0] is the sub-list for extension extendee
+	0, // [0:0] is the sub-list for field type_name
+}
+
+func init() { file_proto_site_proto_init() }
+func file_proto_site_proto_init() {
+	if File_proto_site_proto != nil {
+		return
+	}
+	type x struct{}
+	out := protoimpl.TypeBuilder{
+		File: protoimpl.DescBuilder{
+			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
+			RawDescriptor: unsafe.Slice(unsafe.StringData(file_proto_site_proto_rawDesc), len(file_proto_site_proto_rawDesc)),
+			NumEnums:      0,
+			NumMessages:   7,
+			NumExtensions: 0,
+			NumServices:   1,
+		},
+		GoTypes:           file_proto_site_proto_goTypes,
+		DependencyIndexes: file_proto_site_proto_depIdxs,
+		MessageInfos:      file_proto_site_proto_msgTypes,
+	}.Build()
+	File_proto_site_proto = out.File
+	file_proto_site_proto_goTypes = nil
+	file_proto_site_proto_depIdxs = nil
+}
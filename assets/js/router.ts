@@ -94,6 +94,17 @@ export default function setupRouter(i18n: VueI18nInstance) {
           };
         },
       },
+      {
+        path: "/ocpp",
+        component: () => import("./views/OcppMessages.vue"),
+        beforeEnter: ensureAuth,
+        props: (route) => {
+          const { id } = route.query;
+          return {
+            id: typeof id === "string" ? id : undefined,
+          };
+        },
+      },
       {
         path: "/issue",
         component: () => import("./views/Issue.vue"),
@@ -82,6 +82,35 @@ export interface State {
   authDisabled?: boolean;
   config?: string;
   database?: string;
+  ocppPending?: Record<string, OcppPendingChargePoint>;
+  ocppConnectivity?: Record<string, OcppConnectivity>;
+}
+
+export interface OcppPendingChargePoint {
+  protocol: string;
+  vendor?: string;
+  model?: string;
+  serial?: string;
+  firmware?: string;
+  connected: boolean;
+  seen: string;
+}
+
+export interface OcppConnectivity {
+  online: boolean;
+  lastSeen?: string;
+  lastHeartbeat?: string;
+  timeouts: number;
+  disconnects: number;
+}
+
+export interface OcppMessage {
+  time: string;
+  direction: "send" | "recv";
+  type?: "call" | "result" | "error";
+  action?: string;
+  errorCode?: string;
+  message: unknown;
 }
 
 export interface Config {
@@ -197,6 +226,8 @@ export interface Loadpoint {
   chargerPhases1p3p: boolean;
   chargerSinglePhase: boolean;
   chargerStatusReason: CHARGER_STATUS_REASON | null;
+  chargerUnlock: string | null;
+  chargerFault: string | null;
   charging: boolean;
   connected: boolean;
   connectedDuration: number;
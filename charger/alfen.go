@@ -28,6 +28,7 @@ import (
 	"github.com/evcc-io/evcc/util"
 	"github.com/evcc-io/evcc/util/modbus"
 	"github.com/evcc-io/evcc/util/sponsor"
+	"github.com/evcc-io/evcc/util/supervisor"
 	"github.com/volkszaehler/mbmd/meters/rs485"
 )
 
@@ -90,7 +91,7 @@ func NewAlfen(ctx context.Context, uri string, slaveID uint8) (api.Charger, erro
 		conn: conn,
 	}
 
-	go wb.heartbeat(ctx)
+	wb.heartbeat(ctx)
 
 	_, v2, v3, err := wb.Voltages()
 	if err != nil {
@@ -113,13 +114,7 @@ func NewAlfen(ctx context.Context, uri string, slaveID uint8) (api.Charger, erro
 }
 
 func (wb *Alfen) heartbeat(ctx context.Context) {
-	for tick := time.Tick(25 * time.Second); ; {
-		select {
-		case <-tick:
-		case <-ctx.Done():
-			return
-		}
-
+	supervisor.Every(ctx, "heartbeat", 25*time.Second, func(ctx context.Context) error {
 		wb.mu.Lock()
 		var curr float64
 		if wb.enabled {
@@ -127,10 +122,8 @@ func (wb *Alfen) heartbeat(ctx context.Context) {
 		}
 		wb.mu.Unlock()
 
-		if err := wb.setCurrent(curr); err != nil {
-			wb.log.ERROR.Println("heartbeat:", err)
-		}
-	}
+		return wb.setCurrent(wb.conn.WithContext(ctx), curr)
+	})
 }
 
 // Status implements the api.Charger interface
@@ -162,7 +155,7 @@ func (wb *Alfen) Enable(enable bool) error {
 		wb.mu.Unlock()
 	}
 
-	err := wb.setCurrent(curr)
+	err := wb.setCurrent(wb.conn, curr)
 	if err == nil {
 		wb.mu.Lock()
 		wb.enabled = enable
@@ -180,18 +173,18 @@ func (wb *Alfen) MaxCurrent(current int64) error {
 var _ api.ChargerEx = (*Alfen)(nil)
 
 // setCurrent sets the current in milliamps without modifying the stored current value
-func (wb *Alfen) setCurrent(current float64) error {
+func (wb *Alfen) setCurrent(conn *modbus.Connection, current float64) error {
 	b := make([]byte, 4)
 	binary.BigEndian.PutUint32(b, math.Float32bits(float32(current)))
 
-	_, err := wb.conn.WriteMultipleRegisters(alfenRegAmpsConfig, 2, b)
+	_, err := conn.WriteMultipleRegisters(alfenRegAmpsConfig, 2, b)
 
 	return err
 }
 
 // MaxCurrent implements the api.ChargerEx interface
 func (wb *Alfen) MaxCurrentMillis(current float64) error {
-	err := wb.setCurrent(current)
+	err := wb.setCurrent(wb.conn, current)
 	if err == nil {
 		wb.mu.Lock()
 		wb.curr = current
@@ -28,6 +28,7 @@ import (
 	"github.com/evcc-io/evcc/util"
 	"github.com/evcc-io/evcc/util/modbus"
 	"github.com/evcc-io/evcc/util/sponsor"
+	"github.com/evcc-io/evcc/util/supervisor"
 )
 
 // Amperfied charger implementation
@@ -105,7 +106,7 @@ func NewAmperfied(ctx context.Context, uri string, slaveID uint8, phases bool) (
 		return nil, fmt.Errorf("failsafe timeout: %w", err)
 	}
 	if u := binary.BigEndian.Uint16(b); u > 0 {
-		go wb.heartbeat(ctx, time.Duration(u)*time.Millisecond/2)
+		wb.heartbeat(ctx, time.Duration(u)*time.Millisecond/2)
 	}
 
 	var phases1p3p func(int) error
@@ -119,17 +120,10 @@ func NewAmperfied(ctx context.Context, uri string, slaveID uint8, phases bool) (
 }
 
 func (wb *Amperfied) heartbeat(ctx context.Context, timeout time.Duration) {
-	for tick := time.Tick(timeout); ; {
-		select {
-		case <-tick:
-		case <-ctx.Done():
-			return
-		}
-
-		if _, err := wb.Status(); err != nil {
-			wb.log.ERROR.Println("heartbeat:", err)
-		}
-	}
+	supervisor.Every(ctx, "heartbeat", timeout, func(context.Context) error {
+		_, err := wb.Status()
+		return err
+	})
 }
 
 func (wb *Amperfied) set(reg, val uint16) error {
@@ -36,6 +36,7 @@ import (
 	"github.com/evcc-io/evcc/util"
 	"github.com/evcc-io/evcc/util/modbus"
 	"github.com/evcc-io/evcc/util/sponsor"
+	"github.com/evcc-io/evcc/util/supervisor"
 )
 
 type sempHandler struct {
@@ -193,7 +194,7 @@ func NewBenderCC(ctx context.Context, uri string, id uint8, cache time.Duration)
 				phases1p3p = wb.phases1p3pSEMP
 				getPhases = wb.getPhases
 				// start heartbeat to keep connection alive
-				go wb.heartbeat(ctx)
+				wb.heartbeat(ctx)
 			} else {
 				log.ERROR.Println("SEMP phase switching: could not set initial SEMP power limit:", err)
 			}
@@ -210,20 +211,16 @@ func NewBenderCC(ctx context.Context, uri string, id uint8, cache time.Duration)
 
 // heartbeat ensures that SEMP device control updates are sent about once per minute
 func (wb *BenderCC) heartbeat(ctx context.Context) {
-	for tick := time.Tick(5 * time.Second); ; {
-		select {
-		case <-tick:
-		case <-ctx.Done():
-			return
-		}
+	supervisor.Every(ctx, "heartbeat", 5*time.Second, func(ctx context.Context) error {
 		if time.Since(wb.semp.conn.Updated()) >= time.Minute {
 			// Send a very high power value to allow full control between 6 and 16A via modbus
 			// Note: This will not trigger a phase switch, as the value is above the max. power consumption
-			if err := wb.semp.conn.SendDeviceControl(wb.semp.deviceID, 0xffff); err != nil {
-				wb.log.ERROR.Printf("heartbeat: failed to send update: %v", err)
+			if err := wb.semp.conn.SendDeviceControlContext(ctx, wb.semp.deviceID, 0xffff); err != nil {
+				return fmt.Errorf("failed to send update: %w", err)
 			}
 		}
-	}
+		return nil
+	})
 }
 
 // supportsSEMPPhaseSwitching checks if SEMP phase switching is supported by querying device info
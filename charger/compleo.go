@@ -28,6 +28,7 @@ import (
 	"github.com/evcc-io/evcc/util"
 	"github.com/evcc-io/evcc/util/modbus"
 	"github.com/evcc-io/evcc/util/sponsor"
+	"github.com/evcc-io/evcc/util/supervisor"
 )
 
 // Compleo charger implementation
@@ -114,24 +115,17 @@ func NewCompleo(ctx context.Context, uri string, slaveID uint8, connector uint16
 		return nil, fmt.Errorf("failsafe timeout: %w", err)
 	}
 	if u := binary.BigEndian.Uint16(b); u > 0 {
-		go wb.heartbeat(ctx, log, time.Duration(u)*time.Second/2)
+		wb.heartbeat(ctx, time.Duration(u)*time.Second/2)
 	}
 
 	return wb, nil
 }
 
-func (wb *Compleo) heartbeat(ctx context.Context, log *util.Logger, timeout time.Duration) {
-	for tick := time.Tick(timeout); ; {
-		select {
-		case <-tick:
-		case <-ctx.Done():
-			return
-		}
-
-		if _, err := wb.status(); err != nil {
-			log.ERROR.Println("heartbeat:", err)
-		}
-	}
+func (wb *Compleo) heartbeat(ctx context.Context, timeout time.Duration) {
+	supervisor.Every(ctx, "heartbeat", timeout, func(context.Context) error {
+		_, err := wb.status()
+		return err
+	})
 }
 
 func (wb *Compleo) reg(addr uint16) uint16 {
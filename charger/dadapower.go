@@ -10,6 +10,7 @@ import (
 	"github.com/evcc-io/evcc/util"
 	"github.com/evcc-io/evcc/util/modbus"
 	"github.com/evcc-io/evcc/util/sponsor"
+	"github.com/evcc-io/evcc/util/supervisor"
 )
 
 const (
@@ -80,23 +81,16 @@ func NewDadapower(ctx context.Context, uri string, id uint8) (*Dadapower, error)
 		wb.regOffset = (uint16(id) - 1) * 1000
 	}
 
-	go wb.heartbeat(ctx)
+	wb.heartbeat(ctx)
 
 	return wb, nil
 }
 
 func (wb *Dadapower) heartbeat(ctx context.Context) {
-	for tick := time.Tick(time.Minute); ; {
-		select {
-		case <-tick:
-		case <-ctx.Done():
-			return
-		}
-
-		if _, err := wb.conn.ReadInputRegisters(dadapowerRegFailsafeTimeout, 1); err != nil {
-			wb.log.ERROR.Println("heartbeat:", err)
-		}
-	}
+	supervisor.Every(ctx, "heartbeat", time.Minute, func(ctx context.Context) error {
+		_, err := wb.conn.WithContext(ctx).ReadInputRegisters(dadapowerRegFailsafeTimeout, 1)
+		return err
+	})
 }
 
 // Status implements the api.Charger interface
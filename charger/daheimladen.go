@@ -26,6 +26,7 @@ import (
 	"github.com/evcc-io/evcc/api"
 	"github.com/evcc-io/evcc/util"
 	"github.com/evcc-io/evcc/util/modbus"
+	"github.com/evcc-io/evcc/util/supervisor"
 )
 
 // DaheimLaden charger implementation
@@ -116,7 +117,7 @@ func NewDaheimLaden(ctx context.Context, uri string, id uint8, phases bool) (api
 		return nil, fmt.Errorf("failsafe timeout: %w", err)
 	}
 	if u := binary.BigEndian.Uint16(b); u > 0 {
-		go wb.heartbeat(ctx, time.Duration(u)*time.Second/2)
+		wb.heartbeat(ctx, time.Duration(u)*time.Second/2)
 	}
 
 	var phases1p3p func(int) error
@@ -130,17 +131,10 @@ func NewDaheimLaden(ctx context.Context, uri string, id uint8, phases bool) (api
 }
 
 func (wb *DaheimLaden) heartbeat(ctx context.Context, timeout time.Duration) {
-	for tick := time.Tick(timeout); ; {
-		select {
-		case <-tick:
-		case <-ctx.Done():
-			return
-		}
-
-		if _, err := wb.conn.ReadHoldingRegisters(dlRegSafeCurrent, 1); err != nil {
-			wb.log.ERROR.Println("heartbeat:", err)
-		}
-	}
+	supervisor.Every(ctx, "heartbeat", timeout, func(ctx context.Context) error {
+		_, err := wb.conn.WithContext(ctx).ReadHoldingRegisters(dlRegSafeCurrent, 1)
+		return err
+	})
 }
 
 func (wb *DaheimLaden) setCurrent(current uint16) error {
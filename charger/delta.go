@@ -12,6 +12,7 @@ import (
 	"github.com/evcc-io/evcc/util"
 	"github.com/evcc-io/evcc/util/modbus"
 	"github.com/evcc-io/evcc/util/sponsor"
+	"github.com/evcc-io/evcc/util/supervisor"
 	"github.com/volkszaehler/mbmd/encoding"
 )
 
@@ -125,7 +126,7 @@ func NewDelta(ctx context.Context, uri, device, comset string, baudrate int, pro
 			return nil, fmt.Errorf("failsafe timeout: %w", err)
 		}
 		if u := encoding.Uint16(b); u > 0 {
-			go wb.heartbeat(ctx, time.Duration(u)*time.Second/2)
+			wb.heartbeat(ctx, time.Duration(u)*time.Second/2)
 		}
 	}
 
@@ -133,23 +134,16 @@ func NewDelta(ctx context.Context, uri, device, comset string, baudrate int, pro
 }
 
 func (wb *Delta) heartbeat(ctx context.Context, timeout time.Duration) {
-	for tick := time.Tick(timeout); ; {
-		select {
-		case <-tick:
-		case <-ctx.Done():
-			return
-		}
-
+	supervisor.Every(ctx, "heartbeat", timeout, func(ctx context.Context) error {
 		wb.mu.Lock()
 		var curr float64
 		if wb.enabled {
 			curr = wb.curr
 		}
 		wb.mu.Unlock()
-		if err := wb.setCurrent(curr); err != nil {
-			wb.log.ERROR.Println("heartbeat:", err)
-		}
-	}
+
+		return wb.setCurrent(wb.conn.WithContext(ctx), curr)
+	})
 }
 
 // Status implements the api.Charger interface
@@ -274,7 +268,7 @@ func (wb *Delta) Enable(enable bool) error {
 		wb.mu.Unlock()
 	}
 
-	err := wb.setCurrent(curr)
+	err := wb.setCurrent(wb.conn, curr)
 	if err == nil {
 		wb.mu.Lock()
 		wb.enabled = enable
@@ -285,7 +279,7 @@ func (wb *Delta) Enable(enable bool) error {
 }
 
 // setCurrent writes the current limit in A
-func (wb *Delta) setCurrent(current float64) error {
+func (wb *Delta) setCurrent(conn *modbus.Connection, current float64) error {
 	activePhases := 3
 	if wb.lp != nil {
 		activePhases = wb.lp.ActivePhases()
@@ -294,7 +288,7 @@ func (wb *Delta) setCurrent(current float64) error {
 	b := make([]byte, 4)
 	encoding.PutUint32(b, uint32(math.Trunc(230.0*current*float64(activePhases))))
 
-	_, err := wb.conn.WriteMultipleRegisters(wb.base+deltaRegEvseChargingPowerLimit, 2, b)
+	_, err := conn.WriteMultipleRegisters(wb.base+deltaRegEvseChargingPowerLimit, 2, b)
 
 	return err
 }
@@ -312,7 +306,7 @@ func (wb *Delta) MaxCurrentMillis(current float64) error {
 		return fmt.Errorf("invalid current %.1f", current)
 	}
 
-	err := wb.setCurrent(current)
+	err := wb.setCurrent(wb.conn, current)
 	if err == nil {
 		wb.mu.Lock()
 		wb.curr = current
@@ -26,6 +26,7 @@ import (
 	"github.com/evcc-io/evcc/api"
 	"github.com/evcc-io/evcc/util"
 	"github.com/evcc-io/evcc/util/modbus"
+	"github.com/evcc-io/evcc/util/supervisor"
 	"github.com/volkszaehler/mbmd/meters/rs485"
 )
 
@@ -78,25 +79,18 @@ func NewEProWallbox(ctx context.Context, uri, device, comset string, baudrate in
 		log:  log,
 	}
 
-	go wb.heartbeat(ctx)
+	wb.heartbeat(ctx)
 
 	return wb, nil
 }
 
 func (wb *EProWallbox) heartbeat(ctx context.Context) {
-	for tick := time.Tick(10 * time.Second); ; {
-		select {
-		case <-tick:
-		case <-ctx.Done():
-			return
-		}
-
+	supervisor.Every(ctx, "heartbeat", 10*time.Second, func(ctx context.Context) error {
 		b := make([]byte, 2)
 		binary.BigEndian.PutUint16(b, 0x5555)
-		if _, err := wb.conn.WriteMultipleRegisters(eproRegResetWatchdog, 1, b); err != nil {
-			wb.log.ERROR.Println("heartbeat:", err)
-		}
-	}
+		_, err := wb.conn.WithContext(ctx).WriteMultipleRegisters(eproRegResetWatchdog, 1, b)
+		return err
+	})
 }
 
 // Status implements the api.Charger interface
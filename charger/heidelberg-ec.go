@@ -27,6 +27,7 @@ import (
 	"github.com/evcc-io/evcc/util"
 	"github.com/evcc-io/evcc/util/modbus"
 	"github.com/evcc-io/evcc/util/sponsor"
+	"github.com/evcc-io/evcc/util/supervisor"
 )
 
 // HeidelbergEC charger implementation
@@ -107,24 +108,17 @@ func NewHeidelbergEC(ctx context.Context, uri, device, comset string, baudrate i
 		return nil, fmt.Errorf("failsafe timeout: %w", err)
 	}
 	if u := binary.BigEndian.Uint16(b) / 4; u > 0 {
-		go wb.heartbeat(ctx, time.Duration(u)*time.Millisecond)
+		wb.heartbeat(ctx, time.Duration(u)*time.Millisecond)
 	}
 
 	return wb, nil
 }
 
 func (wb *HeidelbergEC) heartbeat(ctx context.Context, timeout time.Duration) {
-	for tick := time.Tick(timeout); ; {
-		select {
-		case <-tick:
-		case <-ctx.Done():
-			return
-		}
-
-		if _, err := wb.Status(); err != nil {
-			wb.log.ERROR.Println("heartbeat:", err)
-		}
-	}
+	supervisor.Every(ctx, "heartbeat", timeout, func(context.Context) error {
+		_, err := wb.Status()
+		return err
+	})
 }
 
 func (wb *HeidelbergEC) set(reg, val uint16) error {
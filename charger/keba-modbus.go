@@ -30,6 +30,7 @@ import (
 	"github.com/evcc-io/evcc/util"
 	"github.com/evcc-io/evcc/util/modbus"
 	"github.com/evcc-io/evcc/util/sponsor"
+	"github.com/evcc-io/evcc/util/supervisor"
 )
 
 // https://www.keba.com/en/emobility/service-support/downloads/Downloads
@@ -165,7 +166,7 @@ func NewKebaFromConfig(ctx context.Context, other map[string]any) (api.Charger,
 	}
 
 	if u := binary.BigEndian.Uint32(b); u > 0 {
-		go wb.heartbeat(ctx, u)
+		wb.heartbeat(ctx, u)
 	}
 
 	return decorateKeba(wb, currentPower, totalEnergy, currents, identify, reason, phasesS, phasesG), nil
@@ -199,17 +200,10 @@ func NewKeba(ctx context.Context, embed embed, uri string, slaveID uint8) (*Keba
 func (wb *Keba) heartbeat(ctx context.Context, u uint32) {
 	timeout := time.Duration(u) * time.Second / 2
 
-	for tick := time.Tick(timeout); ; {
-		select {
-		case <-tick:
-		case <-ctx.Done():
-			return
-		}
-
-		if _, err := wb.conn.WriteSingleRegister(kebaRegWriteFailsafeTimeout, uint16(u)); err != nil {
-			wb.log.ERROR.Println("heartbeat:", err)
-		}
-	}
+	supervisor.Every(ctx, "heartbeat", timeout, func(ctx context.Context) error {
+		_, err := wb.conn.WithContext(ctx).WriteSingleRegister(kebaRegWriteFailsafeTimeout, uint16(u))
+		return err
+	})
 }
 
 func (wb *Keba) isConnected() (bool, error) {
@@ -28,6 +28,7 @@ import (
 	"github.com/evcc-io/evcc/util"
 	"github.com/evcc-io/evcc/util/modbus"
 	"github.com/evcc-io/evcc/util/sponsor"
+	"github.com/evcc-io/evcc/util/supervisor"
 	"github.com/volkszaehler/mbmd/encoding"
 )
 
@@ -119,23 +120,16 @@ func NewMennekesCompact(ctx context.Context, uri, device, comset string, baudrat
 	}
 
 	// failsafe
-	go wb.heartbeat(ctx, mennekesHeartbeatInterval)
+	wb.heartbeat(ctx, mennekesHeartbeatInterval)
 
 	return decorateMennekesCompact(wb, phasesS), nil
 }
 
 func (wb *MennekesCompact) heartbeat(ctx context.Context, timeout time.Duration) {
-	for tick := time.Tick(timeout); ; {
-		select {
-		case <-tick:
-		case <-ctx.Done():
-			return
-		}
-
-		if _, err := wb.conn.WriteSingleRegister(mennekesRegHeartbeat, mennekesHeartbeatToken); err != nil {
-			wb.log.ERROR.Println("heartbeat:", err)
-		}
-	}
+	supervisor.Every(ctx, "heartbeat", timeout, func(ctx context.Context) error {
+		_, err := wb.conn.WithContext(ctx).WriteSingleRegister(mennekesRegHeartbeat, mennekesHeartbeatToken)
+		return err
+	})
 }
 
 // Status implements the api.Charger interface
@@ -30,6 +30,7 @@ import (
 	"github.com/evcc-io/evcc/util"
 	"github.com/evcc-io/evcc/util/modbus"
 	"github.com/evcc-io/evcc/util/sponsor"
+	"github.com/evcc-io/evcc/util/supervisor"
 )
 
 // MyPv charger implementation
@@ -122,7 +123,7 @@ func NewMyPv(ctx context.Context, name, uri string, slaveID uint8, tempSource in
 		regTemp: elwaTemp[tempSource-1],
 	}
 
-	go wb.heartbeat(ctx, 30*time.Second)
+	wb.heartbeat(ctx, 30*time.Second)
 
 	return wb, nil
 }
@@ -142,23 +143,16 @@ func (wb *MyPv) Features() []api.Feature {
 }
 
 func (wb *MyPv) heartbeat(ctx context.Context, timeout time.Duration) {
-	for tick := time.Tick(timeout); ; {
-		select {
-		case <-tick:
-		case <-ctx.Done():
-			return
-		}
-
+	supervisor.Every(ctx, "heartbeat", timeout, func(ctx context.Context) error {
 		if power := uint16(atomic.LoadUint32(&wb.power)); power > 0 {
 			enabled, err := wb.Enabled()
 			if err == nil && enabled {
-				err = wb.setPower(power)
-			}
-			if err != nil {
-				wb.log.ERROR.Println("heartbeat:", err)
+				err = wb.setPower(wb.conn.WithContext(ctx), power)
 			}
+			return err
 		}
-	}
+		return nil
+	})
 }
 
 // Status implements the api.Charger interface
@@ -228,11 +222,11 @@ func (wb *MyPv) Enabled() (bool, error) {
 	return wb.enabled, nil
 }
 
-func (wb *MyPv) setPower(power uint16) error {
+func (wb *MyPv) setPower(conn *modbus.Connection, power uint16) error {
 	b := make([]byte, 2)
 	binary.BigEndian.PutUint16(b, uint16(wb.scale*float64(power)))
 
-	_, err := wb.conn.WriteMultipleRegisters(elwaRegSetPower, 1, b)
+	_, err := conn.WriteMultipleRegisters(elwaRegSetPower, 1, b)
 	return err
 }
 
@@ -243,7 +237,7 @@ func (wb *MyPv) Enable(enable bool) error {
 		power = uint16(atomic.LoadUint32(&wb.power))
 	}
 
-	res := wb.setPower(power)
+	res := wb.setPower(wb.conn, power)
 	if res == nil {
 		wb.enabled = enable
 	}
@@ -268,7 +262,7 @@ func (wb *MyPv) MaxCurrentMillis(current float64) error {
 	}
 	power := uint16(voltage * current * float64(phases))
 
-	err := wb.setPower(power)
+	err := wb.setPower(wb.conn, power)
 	if err == nil {
 		atomic.StoreUint32(&wb.power, uint32(power))
 	}
@@ -9,6 +9,7 @@ import (
 	"github.com/evcc-io/evcc/api"
 	"github.com/evcc-io/evcc/util"
 	"github.com/evcc-io/evcc/util/modbus"
+	"github.com/evcc-io/evcc/util/supervisor"
 )
 
 // Obo charger implementation
@@ -64,7 +65,7 @@ func NewObo(ctx context.Context, uri, device, comset string, baudrate int, proto
 		return nil, fmt.Errorf("failsafe timeout: %w", err)
 	}
 	if u := binary.BigEndian.Uint16(b); u > 0 {
-		go wb.heartbeat(ctx, time.Duration(u)*time.Millisecond/2)
+		wb.heartbeat(ctx, time.Duration(u)*time.Millisecond/2)
 	}
 
 	// lightshow
@@ -84,17 +85,10 @@ func NewObo(ctx context.Context, uri, device, comset string, baudrate int, proto
 }
 
 func (wb *Obo) heartbeat(ctx context.Context, timeout time.Duration) {
-	for tick := time.Tick(timeout); ; {
-		select {
-		case <-tick:
-		case <-ctx.Done():
-			return
-		}
-
-		if _, err := wb.conn.ReadHoldingRegisters(dlRegSafeCurrent, 1); err != nil {
-			wb.log.ERROR.Println("heartbeat:", err)
-		}
-	}
+	supervisor.Every(ctx, "heartbeat", timeout, func(ctx context.Context) error {
+		_, err := wb.conn.WithContext(ctx).ReadHoldingRegisters(dlRegSafeCurrent, 1)
+		return err
+	})
 }
 
 // Status implements the api.Charger interface
@@ -20,9 +20,11 @@ package charger
 import (
 	"cmp"
 	"context"
+	"errors"
 	"fmt"
 	"math"
 	"slices"
+	"strings"
 	"time"
 
 	"github.com/evcc-io/evcc/api"
@@ -40,12 +42,22 @@ type OCPP struct {
 	log     *util.Logger
 	cp      *ocpp.CP
 	conn    *ocpp.Connector
+	cancel  context.CancelFunc // releases the connector
 	phases  int
 	enabled bool
 	current float64
 
 	stackLevelZero      bool
 	profileKindRelative bool
+	txProfile           bool // limit running transactions using TxProfile
+	stackLevel          *int // stack level of the current limit, overrides stackLevelZero
+	planSlots           api.Rates
+	planCurrent         float64
+	maxLimit            float64             // ChargePointMaxProfile limit
+	limiter             func(float64) error // sets the current without charging profiles
+	inoperativeAfter    time.Duration       // take the connector out of service when off for this duration
+	inoperative         bool                // connector taken out of service
+	offSince            time.Time
 	lp                  loadpoint.API
 }
 
@@ -59,6 +71,7 @@ func init() {
 func NewOCPPFromConfig(ctx context.Context, other map[string]any) (api.Charger, error) {
 	cc := struct {
 		StationId      string
+		User, Password string // basic auth, user defaults to station id
 		IdTag          string
 		Connector      int
 		MeterInterval  time.Duration
@@ -75,7 +88,18 @@ func NewOCPPFromConfig(ctx context.Context, other map[string]any) (api.Charger,
 		ForcePowerCtrl      bool
 		StackLevelZero      *bool
 		ProfileKindRelative bool
+		ProfilePurpose      string    // purpose of the current limit: txdefault (default) or tx
+		StackLevel          *int      // stack level of the current limit, defaults to the charge point's maximum
+		MaxCurrent          float64   // static charge point limit, e.g. the site fuse
+		MaxStackLevel       int       // stack level of the static charge point limit
+		Limit               ocppLimit // current limiting without charging profiles
 		RemoteStart         bool
+		Provisioning        []struct{ Key, Value string } // configuration keys applied on boot
+		Authorization       string                        // overrides the central system's authorization policy
+		Inoperative         time.Duration                 // take the connector out of service when the loadpoint is off for this duration
+		FaultReset          int                           // soft reset the charge point after this number of consecutive faulted status notifications
+		Quirks              map[string]bool               // enable or disable firmware workarounds overriding the detected ones
+		PhaseToUse          int                           // phase used when charging on a single phase (OCPP 2.0.1 only)
 	}{
 		Connector:      1,
 		MeterInterval:  10 * time.Second,
@@ -86,9 +110,32 @@ func NewOCPPFromConfig(ctx context.Context, other map[string]any) (api.Charger,
 		return nil, err
 	}
 
+	if cc.PhaseToUse < 0 || cc.PhaseToUse > 3 {
+		return nil, fmt.Errorf("invalid phase to use: %d", cc.PhaseToUse)
+	}
+
+	authorization, err := ocpp.ParseAuthorizationPolicy(cc.Authorization)
+	if err != nil {
+		return nil, err
+	}
+
+	var txProfile bool
+	switch strings.ToLower(cc.ProfilePurpose) {
+	case "", "txdefault":
+	case "tx":
+		txProfile = true
+	default:
+		return nil, fmt.Errorf("invalid profile purpose: %s", cc.ProfilePurpose)
+	}
+
 	stackLevelZero := cc.StackLevelZero != nil && *cc.StackLevelZero
 	profileKindRelative := cc.ProfileKindRelative
 
+	// require basic auth before the charge point can connect
+	if cc.Password != "" {
+		ocpp.Instance().SetCredentials(cc.StationId, cmp.Or(cc.User, cc.StationId), cc.Password)
+	}
+
 	c, err := NewOCPP(ctx,
 		cc.StationId, cc.Connector, cc.IdTag,
 		cc.MeterValues, cc.MeterInterval,
@@ -102,6 +149,56 @@ func NewOCPPFromConfig(ctx context.Context, other map[string]any) (api.Charger,
 		return nil, api.ErrSponsorRequired
 	}
 
+	if len(cc.Provisioning) > 0 {
+		keys := make(map[string]string, len(cc.Provisioning))
+		for _, kv := range cc.Provisioning {
+			keys[kv.Key] = kv.Value
+		}
+		c.cp.SetProvisioning(keys)
+	}
+
+	if authorization != "" {
+		c.conn.SetAuthorization(authorization)
+	}
+
+	if cc.FaultReset > 0 {
+		c.conn.SetFaultReset(cc.FaultReset)
+	}
+
+	if cc.PhaseToUse > 0 {
+		if c.cp.Protocol() != ocpp.ProtocolV201 {
+			c.log.WARN.Println("phase to use requires OCPP 2.0.1, ignored")
+		}
+		c.conn.SetPhaseToUse(cc.PhaseToUse)
+	}
+
+	if len(cc.Quirks) > 0 {
+		quirks := c.conn.Quirks()
+		if err := quirks.Override(cc.Quirks); err != nil {
+			return nil, err
+		}
+		c.conn.SetQuirks(quirks)
+	}
+
+	c.txProfile = txProfile
+	c.stackLevel = cc.StackLevel
+	c.inoperativeAfter = cc.Inoperative
+
+	if c.limiter, err = cc.Limit.limiter(c); err != nil {
+		return nil, err
+	}
+
+	// charging profiles not supported
+	if c.limiter != nil {
+		c.conn.SetSuspendProfile(nil)
+	}
+
+	if cc.MaxCurrent > 0 {
+		if err := c.setMaxCurrent(cc.MaxCurrent, cc.MaxStackLevel); err != nil {
+			return nil, err
+		}
+	}
+
 	var (
 		powerG, totalEnergyG, socG func() (float64, error)
 		currentsG, voltagesG       func() (float64, float64, float64, error)
@@ -111,7 +208,7 @@ func NewOCPPFromConfig(ctx context.Context, other map[string]any) (api.Charger,
 		powerG = c.conn.CurrentPower
 	}
 
-	if c.cp.HasMeasurement(types.MeasurandEnergyActiveImportRegister) {
+	if c.cp.HasMeasurement(types.MeasurandEnergyActiveImportRegister) || c.cp.HasMeasurement(types.MeasurandEnergyActiveImportInterval) {
 		totalEnergyG = c.conn.TotalEnergy
 	}
 
@@ -149,6 +246,11 @@ func NewOCPP(ctx context.Context,
 	forcePowerCtrl, stackLevelZero, profileKindRelative, remoteStart bool,
 	connectTimeout time.Duration,
 ) (*OCPP, error) {
+	// connector 0 refers to the charge point as a whole
+	if connector < 1 {
+		return nil, fmt.Errorf("invalid connector: %d", connector)
+	}
+
 	log := util.NewLogger(fmt.Sprintf("%s-%d", lo.CoalesceOrEmpty(id, "ocpp"), connector))
 
 	cp, err := ocpp.Instance().RegisterChargepoint(id,
@@ -181,8 +283,12 @@ func NewOCPP(ctx context.Context,
 		idTag = lo.CoalesceOrEmpty(idTag, cp.IdTag, defaultIdTag)
 	}
 
+	// the connector is released when the charger is closed
+	ctx, cancel := context.WithCancel(ctx)
+
 	conn, err := ocpp.NewConnector(ctx, log, connector, cp, idTag, meterInterval)
 	if err != nil {
+		cancel()
 		return nil, err
 	}
 
@@ -190,17 +296,30 @@ func NewOCPP(ctx context.Context,
 		log:                 log,
 		cp:                  cp,
 		conn:                conn,
+		cancel:              cancel,
 		stackLevelZero:      stackLevelZero,
 		profileKindRelative: profileKindRelative,
 	}
 
+	// suspend charging while evcc is not running
+	conn.SetSuspendProfile(func() *types.ChargingProfile {
+		return c.createChargingProfile(0)
+	})
+
 	if cp.HasRemoteTriggerFeature {
-		go conn.WatchDog(ctx, 10*time.Second)
+		conn.WatchDog(ctx, 10*time.Second)
 	}
 
 	return c, conn.Initialized()
 }
 
+// Close releases the connector when the charger is replaced or deleted, keeping the charge point connected.
+// A connector re-registered by the replacing charger continues with the released connector's state.
+func (c *OCPP) Close() error {
+	c.cancel()
+	return nil
+}
+
 // Connector returns the connector instance
 func (c *OCPP) Connector() *ocpp.Connector {
 	return c.conn
@@ -210,9 +329,18 @@ func (c *OCPP) Connector() *ocpp.Connector {
 func (c *OCPP) Status() (api.ChargeStatus, error) {
 	status, err := c.conn.Status()
 	if err != nil {
+		var fault *ocpp.Fault
+		if errors.As(err, &fault) {
+			if fault.Vehicle() {
+				return api.StatusE, err
+			}
+			return api.StatusF, err
+		}
 		return api.StatusNone, err
 	}
 
+	c.updateAvailability(status)
+
 	switch status {
 	case
 		core.ChargePointStatusAvailable,   // "Available"
@@ -232,6 +360,16 @@ func (c *OCPP) Status() (api.ChargeStatus, error) {
 	}
 }
 
+var _ api.FaultDiagnosis = (*OCPP)(nil)
+
+// Fault implements the api.FaultDiagnosis interface
+func (c *OCPP) Fault() (string, error) {
+	if fault := c.conn.Fault(); fault != nil {
+		return fault.Error(), nil
+	}
+	return "", nil
+}
+
 var _ api.StatusReasoner = (*OCPP)(nil)
 
 func (c *OCPP) StatusReason() (api.Reason, error) {
@@ -259,7 +397,10 @@ func (c *OCPP) Enabled() (bool, error) {
 		switch s {
 		case
 			core.ChargePointStatusSuspendedEVSE:
-			return false, nil
+			// phantom status, fall back to the charging limit
+			if !c.conn.Quirks().PhantomSuspendedEVSE {
+				return false, nil
+			}
 		case
 			core.ChargePointStatusCharging,
 			core.ChargePointStatusSuspendedEV:
@@ -304,9 +445,21 @@ func (c *OCPP) Enable(enable bool) error {
 	return err
 }
 
-// setCurrent sets the TxDefaultChargingProfile with given current
+// setCurrent sets the charging profile with given current
 func (c *OCPP) setCurrent(current float64) error {
-	err := c.conn.SetChargingProfileRequest(c.createTxDefaultChargingProfile(math.Trunc(10*current) / 10))
+	if c.limiter != nil {
+		if err := c.limiter(math.Trunc(10*current) / 10); err != nil {
+			return fmt.Errorf("set current: %w", err)
+		}
+		return nil
+	}
+
+	profile := c.createChargingProfile(math.Trunc(10*current) / 10)
+
+	err := c.conn.SetChargingProfileRequest(profile)
+	if err == nil {
+		err = c.verifyProfile(profile)
+	}
 	if err != nil {
 		err = fmt.Errorf("set charging profile: %w", err)
 	}
@@ -314,38 +467,154 @@ func (c *OCPP) setCurrent(current float64) error {
 	return err
 }
 
-// createTxDefaultChargingProfile returns a TxDefaultChargingProfile with given current
-func (c *OCPP) createTxDefaultChargingProfile(current float64) *types.ChargingProfile {
-	phases := c.phases
+// profileRetries is the number of times a profile not applied by the charge point is resent
+const profileRetries = 1
+
+// verifyProfile checks that the charge point applies the profile's limit and resends the profile otherwise
+func (c *OCPP) verifyProfile(profile *types.ChargingProfile) error {
+	limit := profile.ChargingSchedule.ChargingSchedulePeriod[0].Limit
+	if c.maxLimit > 0 {
+		limit = min(limit, c.maxLimit)
+	}
+
+	unit := profile.ChargingSchedule.ChargingRateUnit
+
+	err := c.conn.VerifyLimit(limit, unit)
+	for range profileRetries {
+		if !errors.Is(err, ocpp.ErrLimitDeviation) {
+			break
+		}
+
+		c.log.WARN.Printf("%v, resending charging profile", err)
+
+		if err = c.conn.SetChargingProfileRequest(profile); err == nil {
+			err = c.conn.VerifyLimit(limit, unit)
+		}
+	}
+
+	return err
+}
+
+// setMaxCurrent sets the ChargePointMaxProfile limiting all connectors regardless of the current limit
+func (c *OCPP) setMaxCurrent(current float64, stackLevel int) error {
+	// limit applies per phase
 	period := types.NewChargingSchedulePeriod(0, current)
+	if c.cp.ChargingRateUnit == types.ChargingRateUnitWatts {
+		period = types.NewChargingSchedulePeriod(0, math.Trunc(230.0*current*3))
+	}
+
+	profile := &types.ChargingProfile{
+		ChargingProfileId:      c.cp.ChargingProfileId + maxProfileIdOffset,
+		StackLevel:             stackLevel,
+		ChargingProfilePurpose: types.ChargingProfilePurposeChargePointMaxProfile,
+		ChargingProfileKind:    types.ChargingProfileKindAbsolute,
+		ChargingSchedule: &types.ChargingSchedule{
+			StartSchedule:          types.NewDateTime(time.Now().Add(-time.Minute)),
+			ChargingRateUnit:       c.cp.ChargingRateUnit,
+			ChargingSchedulePeriod: []types.ChargingSchedulePeriod{period},
+		},
+	}
+
+	if err := c.cp.SetChargingProfileRequest(0, profile); err != nil {
+		return fmt.Errorf("set max charging profile: %w", err)
+	}
+
+	c.maxLimit = period.Limit
+
+	return nil
+}
+
+// charging profile ids are offset per purpose since profiles of same id replace each other
+const (
+	txProfileIdOffset  = 1
+	maxProfileIdOffset = 2
+)
+
+// createChargingProfile returns the profile limiting the connector to the given current.
+// TxProfile only applies to the running transaction, TxDefaultProfile is used otherwise.
+func (c *OCPP) createChargingProfile(current float64) *types.ChargingProfile {
+	res := c.createTxDefaultChargingProfile(current)
+
+	if c.txProfile {
+		if txn, err := c.conn.TransactionID(); err == nil && txn > 0 {
+			res.ChargingProfileId += txProfileIdOffset
+			res.ChargingProfilePurpose = types.ChargingProfilePurposeTxProfile
+			res.TransactionId = txn
+		}
+	}
+
+	return res
+}
+
+// schedulePeriod returns the schedule period with given current starting after offset seconds.
+// Periods limited to a single phase charge on the connector's phaseToUse (OCPP 2.0.1 only).
+func (c *OCPP) schedulePeriod(offset int, current float64) types.ChargingSchedulePeriod {
+	phases := c.phases
+	period := types.NewChargingSchedulePeriod(offset, current)
 
 	if c.cp.ChargingRateUnit == types.ChargingRateUnitWatts {
-		period = types.NewChargingSchedulePeriod(0, math.Trunc(230.0*current*float64(phases)))
-	} else {
-		// OCPP assumes phases == 3 if not set
-		if phases != 0 {
-			// set explicit phase configuration
-			period.NumberPhases = &phases
+		period = types.NewChargingSchedulePeriod(offset, math.Trunc(230.0*current*float64(phases)))
+	}
+
+	// OCPP assumes phases == 3 if not set
+	if phases != 0 {
+		// set explicit phase configuration
+		period.NumberPhases = &phases
+	}
+
+	return period
+}
+
+// planPeriods returns the periods following the plan's pending slots after the given current
+// as far as supported by the charge point.
+func (c *OCPP) planPeriods(start time.Time, current float64) []types.ChargingSchedulePeriod {
+	res := []types.ChargingSchedulePeriod{c.schedulePeriod(0, current)}
+
+	for _, slot := range c.planSlots {
+		if len(res)+2 > c.cp.MaxSchedulePeriods {
+			break
 		}
+
+		// active slots are already followed by the given current
+		if !slot.Start.After(start) {
+			continue
+		}
+
+		res = append(res,
+			c.schedulePeriod(int(slot.Start.Sub(start).Seconds()), c.planCurrent),
+			c.schedulePeriod(int(slot.End.Sub(start).Seconds()), current),
+		)
 	}
 
+	return res
+}
+
+// createTxDefaultChargingProfile returns a TxDefaultChargingProfile with given current
+func (c *OCPP) createTxDefaultChargingProfile(current float64) *types.ChargingProfile {
 	res := &types.ChargingProfile{
 		ChargingProfileId:      c.cp.ChargingProfileId,
 		ChargingProfilePurpose: types.ChargingProfilePurposeTxDefaultProfile,
 		ChargingSchedule: &types.ChargingSchedule{
 			ChargingRateUnit:       c.cp.ChargingRateUnit,
-			ChargingSchedulePeriod: []types.ChargingSchedulePeriod{period},
+			ChargingSchedulePeriod: []types.ChargingSchedulePeriod{c.schedulePeriod(0, current)},
 		},
 	}
 
 	if c.profileKindRelative {
 		res.ChargingProfileKind = types.ChargingProfileKindRelative
 	} else {
+		// absolute schedules follow the charging plan
+		start := time.Now().Add(-time.Minute)
+
 		res.ChargingProfileKind = types.ChargingProfileKindAbsolute
-		res.ChargingSchedule.StartSchedule = types.NewDateTime(time.Now().Add(-time.Minute))
+		res.ChargingSchedule.StartSchedule = types.NewDateTime(start)
+		res.ChargingSchedule.ChargingSchedulePeriod = c.planPeriods(start, current)
 	}
 
-	if !c.stackLevelZero {
+	switch {
+	case c.stackLevel != nil:
+		res.StackLevel = *c.stackLevel
+	case !c.stackLevelZero:
 		res.StackLevel = c.cp.StackLevel
 	}
 
@@ -385,6 +654,25 @@ func (c *OCPP) phases1p3p(phases int) error {
 	return c.setCurrent(current)
 }
 
+var _ api.ChargingPlanner = (*OCPP)(nil)
+
+// SetChargingPlan implements the api.ChargingPlanner interface
+func (c *OCPP) SetChargingPlan(slots api.Rates, planCurrent float64) error {
+	c.planSlots, c.planCurrent = slots, planCurrent
+
+	enabled, err := c.Enabled()
+	if err != nil {
+		return err
+	}
+
+	var current float64
+	if enabled {
+		current = c.current
+	}
+
+	return c.setCurrent(current)
+}
+
 var _ api.Identifier = (*OCPP)(nil)
 
 // Identify implements the api.Identifier interface
@@ -392,6 +680,25 @@ func (c *OCPP) Identify() (string, error) {
 	return c.conn.IdTag(), nil
 }
 
+var _ api.Reserver = (*OCPP)(nil)
+
+// Reserve implements the api.Reserver interface
+func (c *OCPP) Reserve(expiry time.Time, id string) error {
+	return c.conn.Reserve(expiry, id)
+}
+
+// CancelReservation implements the api.Reserver interface
+func (c *OCPP) CancelReservation() error {
+	return c.conn.CancelReservation()
+}
+
+var _ api.ConnectorUnlocker = (*OCPP)(nil)
+
+// UnlockConnector implements the api.ConnectorUnlocker interface
+func (c *OCPP) UnlockConnector() error {
+	return c.conn.UnlockConnectorRequest()
+}
+
 var _ api.Diagnosis = (*OCPP)(nil)
 
 // Diagnose implements the api.Diagnosis interface
@@ -406,6 +713,10 @@ func (c *OCPP) Diagnose() {
 		fmt.Printf("\t\tFirmwareVersion: %s\n", c.cp.BootNotificationResult.FirmwareVersion)
 	}
 
+	if quirks := c.conn.Quirks(); quirks != (ocpp.Quirks{}) {
+		fmt.Printf("\tQuirks: %s\n", quirks)
+	}
+
 	fmt.Printf("\tConfiguration:\n")
 	if resp, err := c.cp.GetConfigurationRequest(); err == nil {
 		// sort configuration keys for printing
@@ -430,4 +741,19 @@ var _ loadpoint.Controller = (*OCPP)(nil)
 // LoadpointControl implements loadpoint.Controller
 func (c *OCPP) LoadpointControl(lp loadpoint.API) {
 	c.lp = lp
+
+	// transaction meter readings are authoritative for the session energy
+	c.conn.OnTransactionStopped(func(event ocpp.TransactionEvent) {
+		// record sessions replayed after being offline separately from the loadpoint's session
+		if event.Replayed {
+			go lp.RecordSession(event.Start, event.Stop, event.IdTag, event.MeterStart, event.MeterStop)
+			return
+		}
+
+		// meter start is unknown for transactions not started while running
+		if !event.Start.IsZero() && event.MeterStop > 0 {
+			// don't block the charge point while the loadpoint is waiting for its response
+			go lp.SetSessionMeter(event.MeterStart, event.MeterStop)
+		}
+	})
 }
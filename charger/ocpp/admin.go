@@ -0,0 +1,142 @@
+package ocpp
+
+import (
+	"cmp"
+	"fmt"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/lorenzodonini/ocpp-go/ocpp1.6/core"
+	"github.com/lorenzodonini/ocpp-go/ocpp1.6/firmware"
+	"github.com/lorenzodonini/ocpp-go/ocpp1.6/remotetrigger"
+)
+
+// ConnectorStatus is the connector's status as last reported by the charge point. Connector 0 is the charge point as a whole.
+type ConnectorStatus struct {
+	Connector     int                    `json:"connector"`
+	Status        core.ChargePointStatus `json:"status,omitempty"`
+	Fault         *Fault                 `json:"fault,omitempty"`
+	TransactionId int                    `json:"transactionId,omitempty"`
+	IdTag         string                 `json:"idTag,omitempty"`
+	Updated       time.Time              `json:"updated,omitzero"`
+}
+
+// ChargePointInfo summarizes a configured charge point
+type ChargePointInfo struct {
+	ID           string            `json:"id"`
+	Protocol     string            `json:"protocol,omitempty"`
+	Vendor       string            `json:"vendor,omitempty"`
+	Model        string            `json:"model,omitempty"`
+	Firmware     string            `json:"firmware,omitempty"`
+	Connectivity Connectivity      `json:"connectivity"`
+	Connectors   []ConnectorStatus `json:"connectors"`
+}
+
+// ChargePoints returns the configured charge points ordered by id
+func ChargePoints() []ChargePointInfo {
+	res := make([]ChargePointInfo, 0)
+	if !running.Load() {
+		return res
+	}
+
+	caps := ChargePointCapabilities()
+	conns := Connectivities()
+
+	for _, cp := range Instance().chargepoints() {
+		id := cp.ID()
+		c := caps[id]
+
+		res = append(res, ChargePointInfo{
+			ID:           id,
+			Protocol:     cp.Protocol(),
+			Vendor:       c.Vendor,
+			Model:        c.Model,
+			Firmware:     c.Firmware,
+			Connectivity: conns[id],
+			Connectors:   cp.Connectors(),
+		})
+	}
+
+	slices.SortFunc(res, func(a, b ChargePointInfo) int {
+		return cmp.Compare(a.ID, b.ID)
+	})
+
+	return res
+}
+
+// Connectors returns the status of the charge point and its registered connectors
+func (cp *CP) Connectors() []ConnectorStatus {
+	cp.mu.RLock()
+	res := make([]ConnectorStatus, 0, len(cp.connectors)+1)
+	if cp.status != nil {
+		res = append(res, newConnectorStatus(cp.status))
+	}
+	conns := make([]*Connector, 0, len(cp.connectors))
+	for _, conn := range cp.connectors {
+		conns = append(conns, conn)
+	}
+	cp.mu.RUnlock()
+
+	for _, conn := range conns {
+		res = append(res, conn.connectorStatus())
+	}
+
+	slices.SortFunc(res, func(a, b ConnectorStatus) int {
+		return cmp.Compare(a.Connector, b.Connector)
+	})
+
+	return res
+}
+
+func newConnectorStatus(status *core.StatusNotificationRequest) ConnectorStatus {
+	res := ConnectorStatus{
+		Connector: status.ConnectorId,
+		Status:    status.Status,
+		Fault:     newFault(status),
+	}
+	if status.Timestamp != nil {
+		res.Updated = status.Timestamp.Time
+	}
+	return res
+}
+
+func (conn *Connector) connectorStatus() ConnectorStatus {
+	conn.mu.Lock()
+	defer conn.mu.Unlock()
+
+	res := ConnectorStatus{Connector: conn.id}
+	if conn.status != nil {
+		res = newConnectorStatus(conn.status)
+		res.Connector = conn.id
+	}
+
+	res.TransactionId = conn.txnId
+	res.IdTag = cmp.Or(conn.authIdTag, conn.idTag)
+	if res.Updated.IsZero() {
+		res.Updated = conn.statusUpdated
+	}
+
+	return res
+}
+
+// messageTriggers are the messages charge points can be requested to send
+var messageTriggers = []remotetrigger.MessageTrigger{
+	core.BootNotificationFeatureName,
+	firmware.DiagnosticsStatusNotificationFeatureName,
+	firmware.FirmwareStatusNotificationFeatureName,
+	core.HeartbeatFeatureName,
+	core.MeterValuesFeatureName,
+	core.StatusNotificationFeatureName,
+}
+
+// ParseMessageTrigger validates the message the charge point is requested to send
+func ParseMessageTrigger(s string) (remotetrigger.MessageTrigger, error) {
+	for _, t := range messageTriggers {
+		if strings.EqualFold(string(t), s) {
+			return t, nil
+		}
+	}
+
+	return "", fmt.Errorf("invalid message: %s", s)
+}
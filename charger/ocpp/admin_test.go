@@ -0,0 +1,54 @@
+package ocpp
+
+import (
+	"testing"
+
+	"github.com/lorenzodonini/ocpp-go/ocpp1.6/core"
+	"github.com/lorenzodonini/ocpp-go/ocpp1.6/firmware"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseMessageTrigger(t *testing.T) {
+	res, err := ParseMessageTrigger("statusnotification")
+	require.NoError(t, err)
+	assert.Equal(t, core.StatusNotificationFeatureName, string(res))
+
+	res, err = ParseMessageTrigger(firmware.DiagnosticsStatusNotificationFeatureName)
+	require.NoError(t, err)
+	assert.Equal(t, firmware.DiagnosticsStatusNotificationFeatureName, string(res))
+
+	_, err = ParseMessageTrigger("RemoteStartTransaction")
+	assert.Error(t, err)
+}
+
+func (suite *connTestSuite) TestConnectors() {
+	suite.Equal([]ConnectorStatus{{Connector: 1}}, suite.cp.Connectors())
+
+	_, err := suite.cp.OnStatusNotification(&core.StatusNotificationRequest{
+		ConnectorId: 0,
+		Status:      core.ChargePointStatusFaulted,
+		ErrorCode:   core.GroundFailure,
+	})
+	suite.Require().NoError(err)
+
+	_, err = suite.cp.OnStatusNotification(&core.StatusNotificationRequest{
+		ConnectorId: 1,
+		Status:      core.ChargePointStatusCharging,
+		ErrorCode:   core.NoError,
+	})
+	suite.Require().NoError(err)
+
+	res := suite.cp.Connectors()
+	suite.Require().Len(res, 2)
+
+	suite.Equal(0, res[0].Connector)
+	suite.Equal(core.ChargePointStatusFaulted, res[0].Status)
+	suite.Require().NotNil(res[0].Fault)
+	suite.Equal(core.GroundFailure, res[0].Fault.ErrorCode)
+
+	suite.Equal(1, res[1].Connector)
+	suite.Equal(core.ChargePointStatusCharging, res[1].Status)
+	suite.Nil(res[1].Fault)
+	suite.Equal(suite.clock.Now(), res[1].Updated)
+}
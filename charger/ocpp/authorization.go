@@ -0,0 +1,170 @@
+package ocpp
+
+import (
+	"cmp"
+	"fmt"
+	"maps"
+	"regexp"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+)
+
+// AuthorizationPolicy decides which id tags presented at charge points are accepted
+type AuthorizationPolicy string
+
+const (
+	AuthorizationAll   AuthorizationPolicy = "all"   // accept any id tag
+	AuthorizationKnown AuthorizationPolicy = "known" // accept identifiers of configured vehicles only
+)
+
+// ParseAuthorizationPolicy validates the policy, empty policy is returned unchanged
+func ParseAuthorizationPolicy(s string) (AuthorizationPolicy, error) {
+	switch res := AuthorizationPolicy(strings.ToLower(s)); res {
+	case "", AuthorizationAll, AuthorizationKnown:
+		return res, nil
+	default:
+		return "", fmt.Errorf("invalid authorization policy: %s", s)
+	}
+}
+
+// RejectedAuthorization is an id tag rejected by the authorization policy
+type RejectedAuthorization struct {
+	Station   string    `json:"station"`
+	Connector int       `json:"connector,omitempty"`
+	IdTag     string    `json:"idTag"`
+	Timestamp time.Time `json:"timestamp"`
+}
+
+// maxRejectedAuthorizations is the number of rejected authorizations retained
+const maxRejectedAuthorizations = 50
+
+var (
+	authMu          sync.Mutex
+	authIdentifiers []*regexp.Regexp
+	rejectedHandler func(RejectedAuthorization)
+	rejectedAuths   []RejectedAuthorization
+)
+
+// SetAuthorizedIdentifiers sets the identifiers accepted by the known authorization policy.
+// Placeholders (*) match any characters, matching is case-insensitive like vehicle identification.
+func SetAuthorizedIdentifiers(ids []string) {
+	var res []*regexp.Regexp
+	for _, id := range ids {
+		if id == "" {
+			continue
+		}
+
+		parts := strings.Split(id, "*")
+		for i, part := range parts {
+			parts[i] = regexp.QuoteMeta(part)
+		}
+
+		res = append(res, regexp.MustCompile("(?i)^"+strings.Join(parts, ".*")+"$"))
+	}
+
+	authMu.Lock()
+	defer authMu.Unlock()
+	authIdentifiers = res
+}
+
+// knownIdTag checks if the id tag matches an authorized identifier
+func knownIdTag(idTag string) bool {
+	authMu.Lock()
+	defer authMu.Unlock()
+
+	return idTag != "" && slices.ContainsFunc(authIdentifiers, func(re *regexp.Regexp) bool {
+		return re.MatchString(idTag)
+	})
+}
+
+// SetRejectedAuthorizationHandler registers the handler receiving rejected authorizations
+func SetRejectedAuthorizationHandler(fun func(RejectedAuthorization)) {
+	authMu.Lock()
+	defer authMu.Unlock()
+	rejectedHandler = fun
+}
+
+func addRejectedAuthorization(rejected RejectedAuthorization) {
+	authMu.Lock()
+
+	rejectedAuths = append(rejectedAuths, rejected)
+	if len(rejectedAuths) > maxRejectedAuthorizations {
+		rejectedAuths = slices.Clone(rejectedAuths[len(rejectedAuths)-maxRejectedAuthorizations:])
+	}
+
+	fun := rejectedHandler
+	authMu.Unlock()
+
+	if fun != nil {
+		fun(rejected)
+	}
+}
+
+// RejectedAuthorizations returns the recently rejected authorizations, oldest first
+func RejectedAuthorizations() []RejectedAuthorization {
+	authMu.Lock()
+	defer authMu.Unlock()
+
+	return slices.Clone(rejectedAuths)
+}
+
+// SetAuthorization overrides the central system's authorization policy for the connector
+func (conn *Connector) SetAuthorization(policy AuthorizationPolicy) {
+	conn.mu.Lock()
+	defer conn.mu.Unlock()
+	conn.authorization = policy
+}
+
+// accepts checks the id tag against the connector's authorization policy.
+// The connector's remote start id tag is always accepted.
+func (conn *Connector) accepts(policy AuthorizationPolicy, idTag string) bool {
+	conn.mu.Lock()
+	policy = cmp.Or(conn.authorization, policy)
+	remoteIdTag := conn.remoteIdTag
+	conn.mu.Unlock()
+
+	return policy != AuthorizationKnown || idTag != "" && idTag == remoteIdTag || knownIdTag(idTag)
+}
+
+// idTagAccepted checks the id tag against the authorization policy of the charge point's connector.
+// Connector 0 accepts id tags accepted by any of the charge point's connectors.
+func (cs *CS) idTagAccepted(id string, connector int, idTag string) bool {
+	var conns []*Connector
+	if cp, err := cs.ChargepointByID(id); err == nil {
+		if connector == 0 {
+			cp.mu.RLock()
+			conns = slices.Collect(maps.Values(cp.connectors))
+			cp.mu.RUnlock()
+		} else if conn := cp.connectorByID(connector); conn != nil {
+			conns = []*Connector{conn}
+		}
+	}
+
+	if len(conns) == 0 {
+		return cs.authorization != AuthorizationKnown || knownIdTag(idTag)
+	}
+
+	return slices.ContainsFunc(conns, func(conn *Connector) bool {
+		return conn.accepts(cs.authorization, idTag)
+	})
+}
+
+// authorizeIdTag applies the authorization policy and publishes rejected id tags
+func (cs *CS) authorizeIdTag(id string, connector int, idTag string) bool {
+	if cs.idTagAccepted(id, connector, idTag) {
+		return true
+	}
+
+	cs.log.WARN.Printf("%s: rejected id tag: %s", id, idTag)
+
+	addRejectedAuthorization(RejectedAuthorization{
+		Station:   id,
+		Connector: connector,
+		IdTag:     idTag,
+		Timestamp: time.Now(),
+	})
+
+	return false
+}
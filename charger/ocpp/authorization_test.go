@@ -0,0 +1,73 @@
+package ocpp
+
+import (
+	"testing"
+
+	"github.com/evcc-io/evcc/util"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseAuthorizationPolicy(t *testing.T) {
+	for _, s := range []string{"", "all", "Known"} {
+		_, err := ParseAuthorizationPolicy(s)
+		assert.NoError(t, err, s)
+	}
+
+	_, err := ParseAuthorizationPolicy("foo")
+	assert.Error(t, err)
+}
+
+func TestKnownIdTag(t *testing.T) {
+	SetAuthorizedIdentifiers([]string{"", "12345", "abc*", "a.c"})
+	defer SetAuthorizedIdentifiers(nil)
+
+	assert.True(t, knownIdTag("12345"))
+	assert.True(t, knownIdTag("ABCdef"))
+	assert.True(t, knownIdTag("a.c"))
+	assert.False(t, knownIdTag("x12345"))
+	assert.False(t, knownIdTag("axc"))
+	assert.False(t, knownIdTag(""))
+}
+
+func TestAuthorizeIdTag(t *testing.T) {
+	var handled []RejectedAuthorization
+	SetRejectedAuthorizationHandler(func(rejected RejectedAuthorization) {
+		handled = append(handled, rejected)
+	})
+	defer SetRejectedAuthorizationHandler(nil)
+
+	SetAuthorizedIdentifiers([]string{"12345"})
+	defer SetAuthorizedIdentifiers(nil)
+
+	cp := NewChargePoint(util.NewLogger("foo"), "cp")
+	conn1 := &Connector{cp: cp, remoteIdTag: "evcc"}
+	conn2 := &Connector{cp: cp}
+	cp.connectors[1], cp.connectors[2] = conn1, conn2
+
+	reg := newRegistration()
+	reg.cp = cp
+
+	cs := &CS{
+		log:           util.NewLogger("foo"),
+		regs:          map[string]*registration{"cp": reg},
+		authorization: AuthorizationKnown,
+	}
+
+	// known and remote start id tags
+	assert.True(t, cs.authorizeIdTag("cp", 1, "12345"))
+	assert.True(t, cs.authorizeIdTag("cp", 1, "evcc"))
+	assert.False(t, cs.authorizeIdTag("cp", 2, "evcc"))
+	assert.False(t, cs.authorizeIdTag("cp", 1, "stranger"))
+	assert.False(t, cs.authorizeIdTag("other", 0, "stranger"))
+
+	// connector override, connector 0 accepts if any connector accepts
+	conn2.SetAuthorization(AuthorizationAll)
+	assert.False(t, cs.authorizeIdTag("cp", 1, "stranger"))
+	assert.True(t, cs.authorizeIdTag("cp", 2, "stranger"))
+	assert.True(t, cs.authorizeIdTag("cp", 0, "stranger"))
+
+	require.Len(t, handled, 4)
+	assert.Equal(t, RejectedAuthorization{Station: "cp", Connector: 2, IdTag: "evcc", Timestamp: handled[0].Timestamp}, handled[0])
+	assert.Equal(t, handled, RejectedAuthorizations()[len(RejectedAuthorizations())-4:])
+}
@@ -0,0 +1,202 @@
+package ocpp
+
+import (
+	"bytes"
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/base64"
+	"encoding/pem"
+	"errors"
+	"fmt"
+	"math/big"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+)
+
+// Signer signs certificate signing requests of charge points (security profile 3)
+type Signer interface {
+	// Sign returns the pem encoded certificate chain
+	Sign(id string, csr *x509.CertificateRequest) (string, error)
+}
+
+const (
+	caValidity   = 20 * 365 * 24 * time.Hour
+	certValidity = 2 * 365 * 24 * time.Hour
+)
+
+// CA is a file based certificate authority. Issued certificates are persisted in the issued subdirectory.
+type CA struct {
+	dir  string
+	cert *x509.Certificate
+	key  crypto.Signer
+}
+
+var _ Signer = (*CA)(nil)
+
+// NewCA loads the certificate authority from ca.pem and ca.key in the given directory or creates a new one
+func NewCA(dir string) (*CA, error) {
+	if err := os.MkdirAll(filepath.Join(dir, "issued"), 0o700); err != nil {
+		return nil, err
+	}
+
+	certFile, keyFile := filepath.Join(dir, "ca.pem"), filepath.Join(dir, "ca.key")
+
+	if _, err := os.Stat(certFile); errors.Is(err, os.ErrNotExist) {
+		if err := createCA(certFile, keyFile); err != nil {
+			return nil, err
+		}
+	}
+
+	b, err := os.ReadFile(certFile)
+	if err != nil {
+		return nil, err
+	}
+
+	block, _ := pem.Decode(b)
+	if block == nil {
+		return nil, fmt.Errorf("no certificate found: %s", certFile)
+	}
+
+	cert, err := x509.ParseCertificate(block.Bytes)
+	if err != nil {
+		return nil, err
+	}
+
+	if b, err = os.ReadFile(keyFile); err != nil {
+		return nil, err
+	}
+
+	key, err := parsePrivateKey(b)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", keyFile, err)
+	}
+
+	return &CA{dir: dir, cert: cert, key: key}, nil
+}
+
+func createCA(certFile, keyFile string) error {
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		return err
+	}
+
+	serial, err := serialNumber()
+	if err != nil {
+		return err
+	}
+
+	template := &x509.Certificate{
+		SerialNumber:          serial,
+		Subject:               pkix.Name{CommonName: "evcc ocpp ca"},
+		NotBefore:             time.Now().Add(-time.Hour),
+		NotAfter:              time.Now().Add(caValidity),
+		KeyUsage:              x509.KeyUsageCertSign | x509.KeyUsageCRLSign | x509.KeyUsageDigitalSignature,
+		BasicConstraintsValid: true,
+		IsCA:                  true,
+	}
+
+	der, err := x509.CreateCertificate(rand.Reader, template, template, &key.PublicKey, key)
+	if err != nil {
+		return err
+	}
+
+	keyDer, err := x509.MarshalECPrivateKey(key)
+	if err != nil {
+		return err
+	}
+
+	if err := os.WriteFile(keyFile, pem.EncodeToMemory(&pem.Block{Type: "EC PRIVATE KEY", Bytes: keyDer}), 0o600); err != nil {
+		return err
+	}
+
+	return os.WriteFile(certFile, pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der}), 0o644)
+}
+
+func parsePrivateKey(b []byte) (crypto.Signer, error) {
+	block, _ := pem.Decode(b)
+	if block == nil {
+		return nil, errors.New("no private key found")
+	}
+
+	if key, err := x509.ParsePKCS8PrivateKey(block.Bytes); err == nil {
+		if signer, ok := key.(crypto.Signer); ok {
+			return signer, nil
+		}
+		return nil, errors.New("unsupported private key")
+	}
+
+	if key, err := x509.ParseECPrivateKey(block.Bytes); err == nil {
+		return key, nil
+	}
+
+	return x509.ParsePKCS1PrivateKey(block.Bytes)
+}
+
+func serialNumber() (*big.Int, error) {
+	return rand.Int(rand.Reader, new(big.Int).Lsh(big.NewInt(1), 128))
+}
+
+// parseCSR decodes a pem encoded certificate signing request and verifies its signature
+func parseCSR(csr string) (*x509.CertificateRequest, error) {
+	var der []byte
+	if block, _ := pem.Decode([]byte(csr)); block != nil {
+		der = block.Bytes
+	} else {
+		// some charge points omit the pem armor
+		b, err := base64.StdEncoding.DecodeString(strings.TrimSpace(csr))
+		if err != nil {
+			return nil, errors.New("no certificate signing request found")
+		}
+		der = b
+	}
+
+	req, err := x509.ParseCertificateRequest(der)
+	if err != nil {
+		return nil, err
+	}
+
+	return req, req.CheckSignature()
+}
+
+// Sign implements the Signer interface. The common name must match the charge point id.
+func (ca *CA) Sign(id string, csr *x509.CertificateRequest) (string, error) {
+	if csr.Subject.CommonName != id {
+		return "", fmt.Errorf("common name does not match station id: %s", csr.Subject.CommonName)
+	}
+
+	serial, err := serialNumber()
+	if err != nil {
+		return "", err
+	}
+
+	template := &x509.Certificate{
+		SerialNumber: serial,
+		Subject:      csr.Subject,
+		NotBefore:    time.Now().Add(-time.Hour),
+		NotAfter:     time.Now().Add(certValidity),
+		KeyUsage:     x509.KeyUsageDigitalSignature | x509.KeyUsageKeyAgreement,
+		ExtKeyUsage:  []x509.ExtKeyUsage{x509.ExtKeyUsageClientAuth, x509.ExtKeyUsageServerAuth},
+	}
+
+	der, err := x509.CreateCertificate(rand.Reader, template, ca.cert, csr.PublicKey, ca.key)
+	if err != nil {
+		return "", err
+	}
+
+	var chain bytes.Buffer
+	_ = pem.Encode(&chain, &pem.Block{Type: "CERTIFICATE", Bytes: der})
+	_ = pem.Encode(&chain, &pem.Block{Type: "CERTIFICATE", Bytes: ca.cert.Raw})
+
+	file := filepath.Join(ca.dir, "issued", fmt.Sprintf("%s-%x.pem", filepath.Base(id), serial))
+	if err := os.WriteFile(file, chain.Bytes(), 0o644); err != nil {
+		return "", err
+	}
+
+	return chain.String(), nil
+}
@@ -0,0 +1,89 @@
+package ocpp
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/base64"
+	"encoding/pem"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func createCSR(t *testing.T, cn string) []byte {
+	t.Helper()
+
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.NoError(t, err)
+
+	der, err := x509.CreateCertificateRequest(rand.Reader, &x509.CertificateRequest{
+		Subject: pkix.Name{CommonName: cn, Organization: []string{"vendor"}},
+	}, key)
+	require.NoError(t, err)
+
+	return der
+}
+
+func TestCA(t *testing.T) {
+	dir := t.TempDir()
+
+	ca, err := NewCA(dir)
+	require.NoError(t, err)
+
+	// existing ca is loaded
+	ca2, err := NewCA(dir)
+	require.NoError(t, err)
+	assert.Equal(t, ca.cert.Raw, ca2.cert.Raw)
+
+	der := createCSR(t, "cp-1")
+
+	csr, err := parseCSR(string(pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE REQUEST", Bytes: der})))
+	require.NoError(t, err)
+
+	chain, err := ca.Sign("cp-1", csr)
+	require.NoError(t, err)
+
+	block, rest := pem.Decode([]byte(chain))
+	require.NotNil(t, block)
+
+	cert, err := x509.ParseCertificate(block.Bytes)
+	require.NoError(t, err)
+	assert.Equal(t, "cp-1", cert.Subject.CommonName)
+
+	// issued certificate is verified by the ca contained in the chain
+	pool := x509.NewCertPool()
+	require.True(t, pool.AppendCertsFromPEM(rest))
+
+	_, err = cert.Verify(x509.VerifyOptions{Roots: pool, KeyUsages: []x509.ExtKeyUsage{x509.ExtKeyUsageClientAuth}})
+	require.NoError(t, err)
+
+	issued, err := filepath.Glob(filepath.Join(dir, "issued", "cp-1-*.pem"))
+	require.NoError(t, err)
+	require.Len(t, issued, 1)
+
+	b, err := os.ReadFile(issued[0])
+	require.NoError(t, err)
+	assert.Equal(t, chain, string(b))
+
+	// common name must match station id
+	_, err = ca.Sign("cp-2", csr)
+	assert.Error(t, err)
+}
+
+func TestParseCSR(t *testing.T) {
+	der := createCSR(t, "cp-1")
+
+	// without pem armor
+	csr, err := parseCSR(base64.StdEncoding.EncodeToString(der))
+	require.NoError(t, err)
+	assert.Equal(t, "cp-1", csr.Subject.CommonName)
+
+	_, err = parseCSR("invalid")
+	assert.Error(t, err)
+}
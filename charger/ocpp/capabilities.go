@@ -0,0 +1,187 @@
+package ocpp
+
+import (
+	"maps"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/lorenzodonini/ocpp-go/ocpp1.6/core"
+	"github.com/lorenzodonini/ocpp-go/ocpp1.6/remotetrigger"
+	"github.com/lorenzodonini/ocpp-go/ocpp1.6/smartcharging"
+	"github.com/lorenzodonini/ocpp-go/ocpp1.6/types"
+)
+
+// Capabilities is the charge point's capability profile detected from its boot notification and configuration
+type Capabilities struct {
+	Vendor   string `json:"vendor,omitempty"`
+	Model    string `json:"model,omitempty"`
+	Serial   string `json:"serial,omitempty"`
+	Firmware string `json:"firmware,omitempty"`
+
+	FeatureProfiles     []string                   `json:"featureProfiles,omitempty"`
+	RemoteTrigger       bool                       `json:"remoteTrigger"`
+	SmartCharging       bool                       `json:"smartCharging"`
+	ChargingRateUnit    types.ChargingRateUnitType `json:"chargingRateUnit"`
+	PhaseSwitching      bool                       `json:"phaseSwitching"`
+	NumberOfConnectors  int                        `json:"numberOfConnectors,omitempty"`
+	StackLevel          int                        `json:"stackLevel,omitempty"`
+	MaxSchedulePeriods  int                        `json:"maxSchedulePeriods,omitempty"`
+	MaxChargingProfiles int                        `json:"maxChargingProfiles,omitempty"`
+	Measurands          []string                   `json:"measurands,omitempty"`
+	Quirks              Quirks                     `json:"quirks"`
+
+	Updated time.Time `json:"updated"`
+}
+
+var (
+	capabilitiesMu      sync.Mutex
+	capabilitiesHandler func(id string, capabilities Capabilities)
+	capabilities        = make(map[string]Capabilities)
+)
+
+// SetCapabilitiesHandler registers the handler receiving capability profile changes
+func SetCapabilitiesHandler(fun func(id string, capabilities Capabilities)) {
+	capabilitiesMu.Lock()
+	defer capabilitiesMu.Unlock()
+	capabilitiesHandler = fun
+}
+
+// updateCapabilities updates the charge point's capability profile if fun returns true
+func updateCapabilities(id string, fun func(*Capabilities) bool) {
+	capabilitiesMu.Lock()
+
+	res := capabilities[id]
+	if !fun(&res) {
+		capabilitiesMu.Unlock()
+		return
+	}
+	res.Updated = time.Now()
+	capabilities[id] = res
+
+	handler := capabilitiesHandler
+	capabilitiesMu.Unlock()
+
+	if handler != nil {
+		handler(id, res)
+	}
+}
+
+// ChargePointCapabilities returns the capability profile per charge point
+func ChargePointCapabilities() map[string]Capabilities {
+	capabilitiesMu.Lock()
+	defer capabilitiesMu.Unlock()
+
+	return maps.Clone(capabilities)
+}
+
+// newCapabilities returns the default capabilities assumed until the configuration has been read
+func newCapabilities() Capabilities {
+	return Capabilities{
+		RemoteTrigger:    true, // assume remote trigger feature is available
+		SmartCharging:    true,
+		ChargingRateUnit: types.ChargingRateUnitAmperes,
+	}
+}
+
+// parse applies the configuration key to the capabilities and returns false if the key is not capability related
+func (c *Capabilities) parse(opt core.ConfigurationKey) bool {
+	match := func(s string) bool {
+		return strings.EqualFold(opt.Key, s)
+	}
+
+	value := *opt.Value
+
+	switch {
+	case match(KeyChargeProfileMaxStackLevel):
+		if val, err := strconv.Atoi(value); err == nil {
+			c.StackLevel = val
+		}
+
+	case match(KeyChargingScheduleMaxPeriods):
+		if val, err := strconv.Atoi(value); err == nil {
+			c.MaxSchedulePeriods = val
+		}
+
+	case match(KeyChargingScheduleAllowedChargingRateUnit):
+		if value == "Power" || value == "W" { // "W" is not allowed by spec but used by some CPs
+			c.ChargingRateUnit = types.ChargingRateUnitWatts
+			c.PhaseSwitching = true // assume phase switching is available for power-based charging
+		}
+
+	case match(KeyConnectorSwitch3to1PhaseSupported) || match(KeyChargeAmpsPhaseSwitchingSupported):
+		if val, err := strconv.ParseBool(value); err == nil {
+			c.PhaseSwitching = val
+		}
+
+	case match(KeyMaxChargingProfilesInstalled):
+		if val, err := strconv.Atoi(value); err == nil {
+			c.MaxChargingProfiles = val
+		}
+
+	case match(KeyNumberOfConnectors):
+		if val, err := strconv.Atoi(value); err == nil {
+			c.NumberOfConnectors = val
+		}
+
+	case match(KeySupportedFeatureProfiles):
+		c.FeatureProfiles = nil
+		for p := range strings.SplitSeq(value, ",") {
+			if p = strings.TrimSpace(p); p != "" {
+				c.FeatureProfiles = append(c.FeatureProfiles, p)
+			}
+		}
+
+		c.SmartCharging = hasProperty(value, smartcharging.ProfileName)
+
+		// correct the availability assumption of RemoteTrigger only in case of a valid looking FeatureProfile list
+		if hasProperty(value, core.ProfileName) {
+			c.RemoteTrigger = hasProperty(value, remotetrigger.ProfileName)
+		}
+
+	default:
+		return false
+	}
+
+	return true
+}
+
+// booted applies the charge point's identity from its boot notification
+func (c *Capabilities) booted(request *core.BootNotificationRequest) {
+	c.Vendor = request.ChargePointVendor
+	c.Model = request.ChargePointModel
+	c.Serial = request.ChargePointSerialNumber
+	c.Firmware = request.FirmwareVersion
+}
+
+// applyCapabilities configures the charge point according to its capabilities
+func (cp *CP) applyCapabilities(c Capabilities) {
+	cp.HasRemoteTriggerFeature = c.RemoteTrigger
+	cp.ChargingRateUnit = c.ChargingRateUnit
+	cp.PhaseSwitching = c.PhaseSwitching
+	cp.NumberOfConnectors = c.NumberOfConnectors
+	cp.StackLevel = c.StackLevel
+	cp.MaxSchedulePeriods = c.MaxSchedulePeriods
+	cp.ChargingProfileId = c.MaxChargingProfiles
+
+	cp.mu.Lock()
+	cp.quirks = c.Quirks
+	cp.mu.Unlock()
+}
+
+// Quirks returns the quirks detected for the charge point's firmware
+func (cp *CP) Quirks() Quirks {
+	cp.mu.RLock()
+	defer cp.mu.RUnlock()
+
+	return cp.quirks
+}
+
+// publishCapabilities caches and publishes the charge point's capability profile
+func (cp *CP) publishCapabilities(c Capabilities) {
+	updateCapabilities(cp.ID(), func(res *Capabilities) bool {
+		*res = c
+		return true
+	})
+}
@@ -0,0 +1,33 @@
+package ocpp
+
+import (
+	"testing"
+
+	"github.com/lorenzodonini/ocpp-go/ocpp1.6/core"
+	"github.com/lorenzodonini/ocpp-go/ocpp1.6/types"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCapabilitiesParse(t *testing.T) {
+	key := func(k, v string) core.ConfigurationKey {
+		return core.ConfigurationKey{Key: k, Value: &v}
+	}
+
+	c := newCapabilities()
+
+	assert.True(t, c.parse(key(KeyNumberOfConnectors, "2")))
+	assert.True(t, c.parse(key("chargingscheduleallowedchargingrateunit", "Power")))
+	assert.True(t, c.parse(key(KeySupportedFeatureProfiles, "Core, FirmwareManagement")))
+	assert.False(t, c.parse(key(KeyMeterValuesSampledData, "Power.Active.Import")))
+
+	assert.Equal(t, 2, c.NumberOfConnectors)
+	assert.Equal(t, types.ChargingRateUnitWatts, c.ChargingRateUnit)
+	assert.True(t, c.PhaseSwitching)
+	assert.Equal(t, []string{"Core", "FirmwareManagement"}, c.FeatureProfiles)
+	assert.False(t, c.RemoteTrigger)
+	assert.False(t, c.SmartCharging)
+
+	c.booted(&core.BootNotificationRequest{ChargePointVendor: "vendor", ChargePointModel: "model", FirmwareVersion: "1.0"})
+	assert.Equal(t, "vendor", c.Vendor)
+	assert.Equal(t, "1.0", c.Firmware)
+}
@@ -0,0 +1,57 @@
+package ocpp
+
+import (
+	"slices"
+	"time"
+)
+
+const (
+	clockSkewSamples = 10             // recent timestamps the skew is estimated from
+	clockSkewWarning = time.Minute    // skew exceeding the threshold is logged
+	clockSkewLimit   = 24 * time.Hour // larger offsets are considered invalid timestamps
+)
+
+// observeTimestamp estimates the charge point's clock skew from the timestamp of a message received at now.
+// Messages can be delayed but never arrive early, the skew is therefore the largest recent offset.
+func (cp *CP) observeTimestamp(ts, now time.Time) {
+	sample := ts.Sub(now)
+	if sample.Abs() > clockSkewLimit {
+		return
+	}
+
+	cp.mu.Lock()
+
+	cp.skewSamples = append(cp.skewSamples, sample)
+	if len(cp.skewSamples) > clockSkewSamples {
+		cp.skewSamples = cp.skewSamples[1:]
+	}
+	cp.skew = slices.Max(cp.skewSamples)
+
+	skew, exceeded := cp.skew, cp.skew.Abs() > clockSkewWarning
+	changed := exceeded != cp.skewWarned
+	cp.skewWarned = exceeded
+
+	cp.mu.Unlock()
+
+	if changed && exceeded {
+		cp.log.WARN.Printf("clock skew: %s, correcting timestamps", skew.Round(time.Second))
+	} else if changed {
+		cp.log.INFO.Printf("clock skew: %s", skew.Round(time.Second))
+	}
+}
+
+// resetClockSkew discards the skew estimate after the charge point may have synchronized its clock
+func (cp *CP) resetClockSkew() {
+	cp.mu.Lock()
+	defer cp.mu.Unlock()
+
+	cp.skewSamples, cp.skew = nil, 0
+}
+
+// correctTime converts the charge point's timestamp to central system time
+func (cp *CP) correctTime(t time.Time) time.Time {
+	cp.mu.RLock()
+	defer cp.mu.RUnlock()
+
+	return t.Add(-cp.skew)
+}
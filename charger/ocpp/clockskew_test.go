@@ -0,0 +1,35 @@
+package ocpp
+
+import (
+	"testing"
+	"time"
+
+	"github.com/evcc-io/evcc/util"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestClockSkew(t *testing.T) {
+	cp := NewChargePoint(util.NewLogger("foo"), "skew")
+	now := time.Now()
+
+	// delayed messages don't reduce the estimate
+	cp.observeTimestamp(now.Add(2*time.Minute), now)
+	cp.observeTimestamp(now.Add(time.Minute), now)
+	assert.Equal(t, 2*time.Minute, cp.skew)
+	assert.True(t, cp.skewWarned)
+	assert.Equal(t, now, cp.correctTime(now.Add(2*time.Minute)))
+
+	// implausible timestamps are ignored
+	cp.observeTimestamp(now.Add(-48*time.Hour), now)
+	assert.Equal(t, 2*time.Minute, cp.skew)
+
+	// outdated samples expire
+	for range clockSkewSamples {
+		cp.observeTimestamp(now.Add(-time.Minute), now)
+	}
+	assert.Equal(t, -time.Minute, cp.skew)
+
+	// clock synchronized after boot
+	cp.resetClockSkew()
+	assert.Equal(t, now, cp.correctTime(now))
+}
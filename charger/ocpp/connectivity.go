@@ -0,0 +1,150 @@
+package ocpp
+
+import (
+	"context"
+	"fmt"
+	"maps"
+	"sync"
+	"time"
+
+	"github.com/evcc-io/evcc/api"
+	"github.com/evcc-io/evcc/util/supervisor"
+)
+
+const (
+	heartbeatInterval  = 60 * time.Second // interval requested from charge points on boot
+	heartbeatTolerance = 3                // missed heartbeat intervals before a charge point is considered offline
+)
+
+// Connectivity is a charge point's connection state as seen by the central system
+type Connectivity struct {
+	Online        bool      `json:"online"`
+	LastSeen      time.Time `json:"lastSeen,omitzero"`
+	LastHeartbeat time.Time `json:"lastHeartbeat,omitzero"`
+	Timeouts      int       `json:"timeouts"`    // heartbeat timeouts while connected
+	Disconnects   int       `json:"disconnects"` // websocket disconnects
+}
+
+var (
+	connectivityMu      sync.Mutex
+	connectivityHandler func(id string, connectivity Connectivity)
+	connectivity        = make(map[string]Connectivity)
+)
+
+// SetConnectivityHandler registers the handler receiving charge point connectivity changes
+func SetConnectivityHandler(fun func(id string, connectivity Connectivity)) {
+	connectivityMu.Lock()
+	defer connectivityMu.Unlock()
+	connectivityHandler = fun
+}
+
+func updateConnectivity(id string, fun func(*Connectivity)) {
+	connectivityMu.Lock()
+
+	res := connectivity[id]
+	fun(&res)
+	connectivity[id] = res
+
+	handler := connectivityHandler
+	connectivityMu.Unlock()
+
+	if handler != nil {
+		handler(id, res)
+	}
+}
+
+// Connectivities returns the connectivity per charge point
+func Connectivities() map[string]Connectivity {
+	connectivityMu.Lock()
+	defer connectivityMu.Unlock()
+
+	return maps.Clone(connectivity)
+}
+
+// received records traffic from the charge point
+func (cp *CP) received(heartbeat bool) {
+	cp.mu.Lock()
+	now := time.Now()
+	cp.lastSeen = now
+	if heartbeat {
+		cp.lastHeartbeat = now
+	}
+	recovered := cp.timedOut
+	cp.timedOut = false
+	cp.mu.Unlock()
+
+	if recovered {
+		cp.log.INFO.Println("charge point online")
+	}
+
+	// publishing every message would be too chatty
+	if heartbeat || recovered {
+		cp.publishConnectivity(nil)
+	}
+}
+
+// checkHeartbeat marks the charge point offline if no message has been received within the heartbeat tolerance.
+// The check applies only to charge points sending heartbeats.
+func (cp *CP) checkHeartbeat(now time.Time) {
+	cp.mu.Lock()
+	timeout := cp.connected && !cp.timedOut && !cp.lastHeartbeat.IsZero() &&
+		now.Sub(cp.lastSeen) > heartbeatTolerance*cp.heartbeatInterval
+	if timeout {
+		cp.timedOut = true
+	}
+	lastSeen := cp.lastSeen
+	cp.mu.Unlock()
+
+	if timeout {
+		cp.log.WARN.Printf("charge point offline: no message since %s", lastSeen.Format(time.DateTime))
+		cp.publishConnectivity(func(c *Connectivity) {
+			c.Timeouts++
+		})
+	}
+}
+
+// heartbeatTimeout returns api.ErrTimeout if the charge point stopped sending messages
+func (cp *CP) heartbeatTimeout() error {
+	cp.mu.RLock()
+	defer cp.mu.RUnlock()
+
+	if cp.timedOut {
+		return fmt.Errorf("no message since %s: %w", cp.lastSeen.Format(time.DateTime), api.ErrTimeout)
+	}
+
+	return nil
+}
+
+// publishConnectivity publishes the charge point's connectivity, applying the optional event
+func (cp *CP) publishConnectivity(event func(*Connectivity)) {
+	cp.mu.RLock()
+	id, online, lastSeen, lastHeartbeat := cp.id, cp.connected && !cp.timedOut, cp.lastSeen, cp.lastHeartbeat
+	cp.mu.RUnlock()
+
+	updateConnectivity(id, func(c *Connectivity) {
+		c.Online = online
+		c.LastSeen = lastSeen
+		c.LastHeartbeat = lastHeartbeat
+		if event != nil {
+			event(c)
+		}
+	})
+}
+
+// watchHeartbeats marks charge points offline that stopped sending messages
+func (cs *CS) watchHeartbeats() {
+	supervisor.Every(context.Background(), "ocpp heartbeats", heartbeatInterval/6, func(context.Context) error {
+		now := time.Now()
+		for _, cp := range cs.chargepoints() {
+			cp.checkHeartbeat(now)
+		}
+		return nil
+	})
+}
+
+// received records traffic from the charge point
+func (cs *CS) received(id string) {
+	if cp, err := cs.ChargepointByID(id); err == nil {
+		cp.received(false)
+	}
+}
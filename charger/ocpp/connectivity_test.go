@@ -0,0 +1,51 @@
+package ocpp
+
+import (
+	"testing"
+	"time"
+
+	"github.com/evcc-io/evcc/api"
+	"github.com/evcc-io/evcc/util"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestHeartbeatTimeout(t *testing.T) {
+	var published []Connectivity
+	SetConnectivityHandler(func(id string, connectivity Connectivity) {
+		if id == "heartbeat" {
+			published = append(published, connectivity)
+		}
+	})
+	defer SetConnectivityHandler(nil)
+
+	cp := NewChargePoint(util.NewLogger("foo"), "heartbeat")
+	cp.connect(true)
+
+	// no timeout before the first heartbeat
+	cp.checkHeartbeat(time.Now().Add(time.Hour))
+	require.NoError(t, cp.heartbeatTimeout())
+
+	cp.received(true)
+	cp.checkHeartbeat(time.Now().Add(heartbeatTolerance * heartbeatInterval / 2))
+	require.NoError(t, cp.heartbeatTimeout())
+
+	cp.checkHeartbeat(time.Now().Add(heartbeatTolerance*heartbeatInterval + time.Second))
+	assert.ErrorIs(t, cp.heartbeatTimeout(), api.ErrTimeout)
+	assert.False(t, Connectivities()["heartbeat"].Online)
+	assert.Equal(t, 1, Connectivities()["heartbeat"].Timeouts)
+
+	// any message recovers
+	cp.received(false)
+	require.NoError(t, cp.heartbeatTimeout())
+	assert.True(t, Connectivities()["heartbeat"].Online)
+
+	cp.connect(false)
+	assert.Equal(t, Connectivity{
+		LastSeen:      published[len(published)-1].LastSeen,
+		LastHeartbeat: published[len(published)-1].LastHeartbeat,
+		Timeouts:      1,
+		Disconnects:   1,
+	}, Connectivities()["heartbeat"])
+	assert.Equal(t, Connectivities()["heartbeat"], published[len(published)-1])
+}
@@ -1,10 +1,12 @@
 package ocpp
 
 import (
+	"cmp"
 	"context"
 	"fmt"
+	"maps"
+	"math"
 	"strconv"
-	"strings"
 	"sync"
 	"time"
 
@@ -15,6 +17,9 @@ import (
 	"github.com/lorenzodonini/ocpp-go/ocpp1.6/types"
 )
 
+// socTimeout is the maximum age of the vehicle's soc reported by the charge point
+const socTimeout = 5 * time.Minute
+
 type Connector struct {
 	log   *util.Logger
 	mu    sync.Mutex
@@ -22,18 +27,42 @@ type Connector struct {
 	cp    *CP
 	id    int
 
-	status  *core.StatusNotificationRequest
-	statusC chan struct{}
+	status        *core.StatusNotificationRequest
+	statusC       chan struct{}
+	statusUpdated time.Time
+
+	faults       int // consecutive faulted status notifications
+	faultReset   int // faults before resetting the charge point, zero disables
+	faultResetAt time.Time
 
 	meterUpdated time.Time
+	socUpdated   time.Time
 	measurements map[types.Measurand]types.SampledValue
+	energy       energyCounter
+
+	txnId         int
+	idTag         string
+	txnStarted    time.Time
+	txnMeterStart int  // Wh
+	txnReplayed   bool // transaction started while the charge point was offline
+
+	remoteIdTag   string
+	authIdTag     string              // id tag authorized by the charge point for the current session
+	authorization AuthorizationPolicy // overrides the central system's policy
+
+	stoppedHandler func(TransactionEvent)
+	suspendProfile func() *types.ChargingProfile // zero current profile applied on shutdown
 
-	txnId int
-	idTag string
+	reservationId     int
+	reservationExpiry time.Time
 
-	remoteIdTag string
+	compositeUnsupported bool // composite schedules can't be used for verifying limits
+	quirks               Quirks
+	phaseToUse           int // phase used by charging profiles limited to a single phase, OCPP 2.0.1 only
 
 	meterInterval time.Duration
+
+	prev *Connector // superseded connector, guarded by the charge point's mutex
 }
 
 func NewConnector(ctx context.Context, log *util.Logger, id int, cp *CP, idTag string, meterInterval time.Duration) (*Connector, error) {
@@ -47,16 +76,28 @@ func NewConnector(ctx context.Context, log *util.Logger, id int, cp *CP, idTag s
 
 		remoteIdTag:   idTag,
 		meterInterval: meterInterval,
+		quirks:        cp.Quirks(),
 	}
 
-	if err := cp.registerConnector(id, conn); err != nil {
-		return nil, err
+	// continue transaction and energy total after unexpected restart
+	conn.resumeTransaction()
+	conn.resumeEnergy()
+
+	// continue with the state of the reconfigured charger's connector
+	if prev := cp.registerConnector(id, conn); prev != nil {
+		log.DEBUG.Printf("connector %d re-registered", id)
+		conn.takeOver(prev)
 	}
 
 	go func() {
 		// deregister connector when the context is cancelled
 		<-ctx.Done()
-		cp.deregisterConnector(conn.id)
+
+		if prev, last := cp.releaseConnector(conn); prev != nil {
+			prev.takeOver(conn)
+		} else if last {
+			instance.deregisterChargepoint(cp)
+		}
 	}()
 
 	// trigger status for all connectors
@@ -69,9 +110,20 @@ func NewConnector(ctx context.Context, log *util.Logger, id int, cp *CP, idTag s
 		}
 	})
 
+	// continue with the last known status after unexpected restart
+	if !ok {
+		conn.resumeStatus()
+	}
+
 	// only trigger if we don't already have a status
 	if !ok && cp.HasRemoteTriggerFeature {
-		if err := cp.TriggerMessageRequest(0, core.StatusNotificationFeatureName); err != nil {
+		// ocpp 2.0.1 requires the evse
+		var evse int
+		if cp.Protocol() == ProtocolV201 {
+			evse = id
+		}
+
+		if err := cp.TriggerMessageRequest(evse, core.StatusNotificationFeatureName); err != nil {
 			cp.log.WARN.Printf("failed triggering StatusNotification: %v", err)
 		}
 	}
@@ -79,10 +131,65 @@ func NewConnector(ctx context.Context, log *util.Logger, id int, cp *CP, idTag s
 	return conn, nil
 }
 
+// takeOver continues with the runtime state of the given connector
+func (conn *Connector) takeOver(from *Connector) {
+	from.mu.Lock()
+	status, statusUpdated := from.status, from.statusUpdated
+	faults, faultResetAt := from.faults, from.faultResetAt
+	meterUpdated, socUpdated := from.meterUpdated, from.socUpdated
+	measurements, energy := maps.Clone(from.measurements), from.energy
+	txnId, idTag, txnStarted, txnMeterStart, txnReplayed := from.txnId, from.idTag, from.txnStarted, from.txnMeterStart, from.txnReplayed
+	authIdTag := from.authIdTag
+	reservationId, reservationExpiry := from.reservationId, from.reservationExpiry
+	from.mu.Unlock()
+
+	conn.mu.Lock()
+	defer conn.mu.Unlock()
+
+	if status != nil {
+		if conn.status == nil {
+			close(conn.statusC) // signal initial status received
+		}
+		conn.status, conn.statusUpdated = status, statusUpdated
+	}
+
+	conn.faults, conn.faultResetAt = faults, faultResetAt
+	conn.meterUpdated, conn.socUpdated = meterUpdated, socUpdated
+	conn.measurements, conn.energy = measurements, energy
+	conn.txnId, conn.idTag, conn.txnStarted, conn.txnMeterStart, conn.txnReplayed = txnId, idTag, txnStarted, txnMeterStart, txnReplayed
+	conn.authIdTag = authIdTag
+	conn.reservationId, conn.reservationExpiry = reservationId, reservationExpiry
+}
+
 func (conn *Connector) TestClock(clock clock.Clock) {
 	conn.clock = clock
 }
 
+// SetQuirks overrides the firmware workarounds detected for the charge point
+func (conn *Connector) SetQuirks(quirks Quirks) {
+	conn.mu.Lock()
+	defer conn.mu.Unlock()
+
+	conn.quirks = quirks
+}
+
+// SetPhaseToUse sets the phase used by charging profiles limited to a single phase, zero leaves it to the charge point.
+// OCPP 1.6 does not support selecting the phase.
+func (conn *Connector) SetPhaseToUse(phase int) {
+	conn.mu.Lock()
+	defer conn.mu.Unlock()
+
+	conn.phaseToUse = phase
+}
+
+// Quirks returns the connector's firmware workarounds
+func (conn *Connector) Quirks() Quirks {
+	conn.mu.Lock()
+	defer conn.mu.Unlock()
+
+	return conn.quirks
+}
+
 func (conn *Connector) ID() int {
 	return conn.id
 }
@@ -90,7 +197,9 @@ func (conn *Connector) ID() int {
 func (conn *Connector) IdTag() string {
 	conn.mu.Lock()
 	defer conn.mu.Unlock()
-	return conn.idTag
+
+	// prefer authorized id tag over the remote start id tag
+	return cmp.Or(conn.authIdTag, conn.idTag)
 }
 
 // getScheduleLimit queries the current or power limit the charge point is currently set to offer
@@ -108,25 +217,40 @@ func (conn *Connector) GetScheduleLimit(duration int) (float64, error) {
 	return 0, fmt.Errorf("invalid ChargingSchedule")
 }
 
-// WatchDog triggers meter values messages if older than timeout.
-// Must be wrapped in a goroutine.
-func (conn *Connector) WatchDog(ctx context.Context, timeout time.Duration) {
-	tick := time.NewTicker(2 * time.Second)
-	for {
-		conn.mu.Lock()
-		update := conn.clock.Since(conn.meterUpdated) > timeout
-		conn.mu.Unlock()
+// VerifyLimit compares the limit the charge point currently applies according to its composite schedule
+// with the expected limit. Limits can't be verified if the charge point doesn't support composite
+// schedules or reports them using a different unit.
+func (conn *Connector) VerifyLimit(limit float64, unit types.ChargingRateUnitType) error {
+	conn.mu.Lock()
+	unsupported := conn.compositeUnsupported
+	conn.mu.Unlock()
 
-		if update {
-			conn.TriggerMessageRequest(core.MeterValuesFeatureName)
-		}
+	if unsupported {
+		return nil
+	}
 
-		select {
-		case <-ctx.Done():
-			return
-		case <-tick.C:
+	schedule, err := conn.cp.GetCompositeScheduleRequest(conn.id, 60)
+	if err != nil {
+		if notSupported(err) {
+			conn.mu.Lock()
+			conn.compositeUnsupported = true
+			conn.mu.Unlock()
 		}
+
+		conn.log.DEBUG.Printf("unverified limit: %v", err)
+		return nil
+	}
+
+	if schedule == nil || schedule.ChargingSchedule == nil || len(schedule.ChargingSchedule.ChargingSchedulePeriod) == 0 ||
+		schedule.ChargingSchedule.ChargingRateUnit != unit {
+		return nil
+	}
+
+	if applied := schedule.ChargingSchedule.ChargingSchedulePeriod[0].Limit; math.Abs(applied-limit) > max(0.1, limit/100) {
+		return fmt.Errorf("%w: %.3g%s instead of %.3g%s", ErrLimitDeviation, applied, unit, limit, unit)
 	}
+
+	return nil
 }
 
 // Initialized waits for initial charge point status notification
@@ -165,6 +289,14 @@ func (conn *Connector) Status() (core.ChargePointStatus, error) {
 		return "", api.ErrTimeout
 	}
 
+	if err := conn.cp.heartbeatTimeout(); err != nil {
+		return "", err
+	}
+
+	if fault := conn.cp.fault(); fault != nil && !fault.Warning() {
+		return "", fault
+	}
+
 	conn.mu.Lock()
 	defer conn.mu.Unlock()
 
@@ -172,13 +304,26 @@ func (conn *Connector) Status() (core.ChargePointStatus, error) {
 		return core.ChargePointStatusUnavailable, nil
 	}
 
-	if conn.status.ErrorCode != core.NoError {
-		return "", fmt.Errorf("%s: %s", conn.status.ErrorCode, conn.status.Info)
+	if fault := newFault(conn.status); fault != nil && !fault.Warning() {
+		return "", fault
 	}
 
 	return conn.status.Status, nil
 }
 
+// Fault returns the connector's or the charge point's current fault or nil
+func (conn *Connector) Fault() *Fault {
+	conn.mu.Lock()
+	fault := newFault(conn.status)
+	conn.mu.Unlock()
+
+	if fault == nil {
+		fault = conn.cp.fault()
+	}
+
+	return fault
+}
+
 // NeedsAuthentication checks if local authentication or an initial RemoteStartTransaction is required
 func (conn *Connector) NeedsAuthentication() bool {
 	if !conn.cp.Connected() {
@@ -220,8 +365,7 @@ func (conn *Connector) GetMaxCurrent() (float64, error) {
 	}
 
 	if m, ok := conn.measurements[types.MeasurandCurrentOffered]; ok {
-		f, err := strconv.ParseFloat(m.Value, 64)
-		return scale(f, m.Unit), err
+		return strconv.ParseFloat(m.Value, 64)
 	}
 
 	return 0, api.ErrNotAvailable
@@ -242,8 +386,7 @@ func (conn *Connector) GetMaxPower() (float64, error) {
 	}
 
 	if m, ok := conn.measurements[types.MeasurandPowerOffered]; ok {
-		f, err := strconv.ParseFloat(m.Value, 64)
-		return scale(f, m.Unit), err
+		return strconv.ParseFloat(m.Value, 64)
 	}
 
 	return 0, api.ErrNotAvailable
@@ -269,7 +412,7 @@ func (conn *Connector) phaseMeasurements(measurement, suffix types.Measurand) ([
 			return res, found, fmt.Errorf("invalid phase value %s: %w", key, err)
 		}
 
-		res[i] = scale(f, m.Unit)
+		res[i] = f
 	}
 
 	return res, found, nil
@@ -295,8 +438,7 @@ func (conn *Connector) CurrentPower() (float64, error) {
 	}
 
 	if m, ok := conn.measurements[types.MeasurandPowerActiveImport]; ok {
-		f, err := strconv.ParseFloat(m.Value, 64)
-		return scale(f, m.Unit), err
+		return strconv.ParseFloat(m.Value, 64)
 	}
 
 	// fallback for missing total power
@@ -322,9 +464,13 @@ func (conn *Connector) TotalEnergy() (float64, error) {
 		return 0, api.ErrTimeout
 	}
 
+	if conn.energy.valid {
+		return conn.energy.total() / 1e3, nil
+	}
+
 	if m, ok := conn.measurements[types.MeasurandEnergyActiveImportRegister]; ok {
 		f, err := strconv.ParseFloat(m.Value, 64)
-		return scale(f, m.Unit) / 1e3, err
+		return f / 1e3, err
 	}
 
 	// fallback for missing total energy
@@ -350,26 +496,25 @@ func (conn *Connector) Soc() (float64, error) {
 		return 0, api.ErrTimeout
 	}
 
-	if m, ok := conn.measurements[types.MeasurandSoC]; ok {
-		return strconv.ParseFloat(m.Value, 64)
+	m, ok := conn.measurements[types.MeasurandSoC]
+	if !ok || conn.isSocStale() {
+		return 0, api.ErrNotAvailable
 	}
 
-	return 0, api.ErrNotAvailable
+	return strconv.ParseFloat(m.Value, 64)
 }
 
-func scale(f float64, scale types.UnitOfMeasure) float64 {
-	switch {
-	case strings.HasPrefix(string(scale), "k"):
-		return f * 1e3
-	case strings.HasPrefix(string(scale), "m"):
-		return f / 1e3
-	default:
-		return f
-	}
+// isSocStale checks if the vehicle's soc has not been reported recently, e.g. after ISO 15118 communication ended.
+// Must only be called while holding lock.
+func (conn *Connector) isSocStale() bool {
+	return conn.clock.Since(conn.socUpdated) > max(socTimeout, 2*conn.meterInterval)
 }
 
+// phaseSuffixes avoid formatting phase keys on each read
+var phaseSuffixes = [...]types.Measurand{".L1", ".L2", ".L3"}
+
 func getPhaseKey(key types.Measurand, phase int) types.Measurand {
-	return key + types.Measurand(".L"+strconv.Itoa(phase))
+	return key + phaseSuffixes[phase-1]
 }
 
 func (conn *Connector) Currents() (float64, float64, float64, error) {
@@ -1,9 +1,12 @@
 package ocpp
 
 import (
+	"fmt"
+	"strconv"
 	"strings"
 	"time"
 
+	"github.com/evcc-io/evcc/server/db/journal"
 	"github.com/lorenzodonini/ocpp-go/ocpp1.6/core"
 	"github.com/lorenzodonini/ocpp-go/ocpp1.6/types"
 )
@@ -11,7 +14,7 @@ import (
 // timestampValid returns false if status timestamps are outdated
 func (conn *Connector) timestampValid(t time.Time) bool {
 	// reject if expired
-	if conn.clock.Since(t) > Timeout {
+	if conn.clock.Since(conn.cp.correctTime(t)) > Timeout {
 		return false
 	}
 
@@ -24,10 +27,130 @@ func (conn *Connector) timestampValid(t time.Time) bool {
 	return !t.Before(conn.status.Timestamp.Time)
 }
 
+// transaction is the journaled state of a running transaction
+type transaction struct {
+	ID         int       `json:"id"`
+	IdTag      string    `json:"idTag"`
+	Started    time.Time `json:"started,omitzero"`
+	MeterStart int       `json:"meterStart,omitempty"`
+	Replayed   bool      `json:"replayed,omitempty"`
+}
+
+func (conn *Connector) journalKey() string {
+	return fmt.Sprintf("ocpp.%s.%d.transaction", conn.cp.ID(), conn.id)
+}
+
+func (conn *Connector) statusJournalKey() string {
+	return fmt.Sprintf("ocpp.%s.%d.status", conn.cp.ID(), conn.id)
+}
+
+func (conn *Connector) energyJournalKey() string {
+	return fmt.Sprintf("ocpp.%s.%d.energy", conn.cp.ID(), conn.id)
+}
+
+// setTransaction updates the transaction and journals it for resuming after unexpected restarts.
+// Lock must be held.
+func (conn *Connector) setTransaction(id int, idTag string) {
+	conn.txnId, conn.idTag = id, idTag
+	if id == 0 {
+		conn.authIdTag = ""
+		conn.txnStarted, conn.txnMeterStart, conn.txnReplayed = time.Time{}, 0, false
+	}
+
+	var err error
+	if id == 0 {
+		err = journal.Instance.Delete(conn.journalKey())
+	} else {
+		err = journal.Instance.Set(conn.journalKey(), transaction{ID: id, IdTag: idTag, Started: conn.txnStarted, MeterStart: conn.txnMeterStart, Replayed: conn.txnReplayed})
+	}
+
+	if err != nil {
+		conn.log.ERROR.Printf("journal: %v", err)
+	}
+}
+
+// resumeTransaction restores the journaled transaction
+func (conn *Connector) resumeTransaction() {
+	conn.mu.Lock()
+	defer conn.mu.Unlock()
+
+	var txn transaction
+	if err := journal.Instance.Get(conn.journalKey(), &txn); err == nil && txn.ID != 0 {
+		conn.log.DEBUG.Printf("resumed transaction: %d", txn.ID)
+		conn.txnId, conn.idTag = txn.ID, txn.IdTag
+		conn.txnStarted, conn.txnMeterStart, conn.txnReplayed = txn.Started, txn.MeterStart, txn.Replayed
+	}
+}
+
+// resumeEnergy restores the journaled energy total, keeping it monotonic across restarts
+func (conn *Connector) resumeEnergy() {
+	conn.mu.Lock()
+	defer conn.mu.Unlock()
+
+	var state energyState
+	if err := journal.Instance.Get(conn.energyJournalKey(), &state); err == nil {
+		conn.energy.restore(state)
+	}
+}
+
+// resumeStatus restores the journaled status until the charge point reports its current status
+func (conn *Connector) resumeStatus() {
+	conn.mu.Lock()
+	defer conn.mu.Unlock()
+
+	var status core.StatusNotificationRequest
+	if err := journal.Instance.Get(conn.statusJournalKey(), &status); err == nil && conn.status == nil {
+		conn.log.DEBUG.Printf("resumed status: %s", status.Status)
+		conn.status = &status
+		close(conn.statusC) // signal initial status received
+	}
+}
+
+// finishJournaledTransaction removes the journaled transaction stopped before its connector has been
+// registered after restart and publishes the stop event
+func finishJournaledTransaction(id string, request *core.StopTransactionRequest) {
+	prefix := fmt.Sprintf("ocpp.%s.", id)
+
+	for _, key := range journal.Instance.Keys(prefix) {
+		connector, err := strconv.Atoi(strings.TrimSuffix(strings.TrimPrefix(key, prefix), ".transaction"))
+		if err != nil {
+			continue
+		}
+
+		var txn transaction
+		if err := journal.Instance.Get(key, &txn); err != nil || txn.ID != request.TransactionId {
+			continue
+		}
+
+		if err := journal.Instance.Delete(key); err != nil {
+			continue
+		}
+
+		event := TransactionEvent{
+			Station:       id,
+			Connector:     connector,
+			TransactionId: txn.ID,
+			IdTag:         txn.IdTag,
+			Start:         txn.Started,
+			MeterStart:    float64(txn.MeterStart) / 1e3,
+		}
+		event.stopped(request, time.Now())
+
+		publishTransactionEvent(event)
+	}
+}
+
 func (conn *Connector) OnStatusNotification(request *core.StatusNotificationRequest) (*core.StatusNotificationConfirmation, error) {
 	conn.mu.Lock()
 	defer conn.mu.Unlock()
 
+	conn.statusUpdated = conn.clock.Now()
+	prevFault := newFault(conn.status)
+
+	if request.Timestamp != nil {
+		conn.cp.observeTimestamp(request.Timestamp.Time, conn.statusUpdated)
+	}
+
 	if conn.status == nil {
 		conn.status = request
 		close(conn.statusC) // signal initial status received
@@ -37,9 +160,49 @@ func (conn *Connector) OnStatusNotification(request *core.StatusNotificationRequ
 		conn.log.TRACE.Printf("ignoring status: %s < %s", request.Timestamp.Time, conn.status.Timestamp)
 	}
 
+	// journal occupied connectors only, there is nothing to resume for available connectors
+	if conn.status == request {
+		var err error
+		if request.Status == core.ChargePointStatusAvailable {
+			err = journal.Instance.Delete(conn.statusJournalKey())
+		} else {
+			err = journal.Instance.Set(conn.statusJournalKey(), request)
+		}
+
+		if err != nil {
+			conn.log.ERROR.Printf("journal: %v", err)
+		}
+	}
+
+	if fault := newFault(conn.status); fault != nil && (prevFault == nil || *fault != *prevFault) {
+		conn.log.WARN.Printf("connector fault: %v", fault)
+	}
+
+	// recover charge points hanging in faulted state
+	if conn.status == request && conn.countFault(request.Status) {
+		go conn.faultedReset()
+	}
+
+	// resumed transaction has been finished in the meantime
+	if conn.txnId != 0 && conn.status.Status == core.ChargePointStatusAvailable {
+		conn.log.DEBUG.Printf("dropping finished transaction: %d", conn.txnId)
+		conn.setTransaction(0, "")
+	}
+
+	// vehicle has left
+	if conn.status.Status == core.ChargePointStatusAvailable {
+		conn.authIdTag = ""
+		delete(conn.measurements, types.MeasurandSoC)
+	}
+
+	// start transaction before the vehicle is connected
+	if conn.quirks.RemoteStartEarly && conn.remoteIdTag != "" && conn.txnId == 0 && conn.status.Status == core.ChargePointStatusAvailable {
+		go conn.remoteStart(conn.remoteIdTag)
+	}
+
 	if conn.isWaitingForAuth() {
 		if conn.remoteIdTag != "" {
-			conn.RemoteStartTransactionRequest(conn.remoteIdTag)
+			go conn.remoteStart(conn.remoteIdTag)
 		} else {
 			conn.log.DEBUG.Printf("waiting for local authentication")
 		}
@@ -48,6 +211,13 @@ func (conn *Connector) OnStatusNotification(request *core.StatusNotificationRequ
 	return new(core.StatusNotificationConfirmation), nil
 }
 
+// remoteStart starts the transaction for the remote id tag. Failures are logged as there is no caller to return them to.
+func (conn *Connector) remoteStart(idTag string) {
+	if err := conn.RemoteStartTransactionRequest(idTag); err != nil {
+		conn.log.ERROR.Printf("remote start: %v", err)
+	}
+}
+
 func getSampleKey(s types.SampledValue) types.Measurand {
 	if s.Phase != "" {
 		return s.Measurand + types.Measurand("."+string(s.Phase))
@@ -66,20 +236,42 @@ func (conn *Connector) OnMeterValues(request *core.MeterValuesRequest) (*core.Me
 			conn.status.Status == core.ChargePointStatusSuspendedEV ||
 			conn.status.Status == core.ChargePointStatusSuspendedEVSE) {
 		conn.log.DEBUG.Printf("recovered transaction: %d", *request.TransactionId)
-		conn.txnId = *request.TransactionId
+		conn.setTransaction(*request.TransactionId, conn.idTag)
 	}
 
 	for _, meterValue := range sortByAge(request.MeterValue) {
+		if meterValue.Timestamp != nil {
+			conn.cp.observeTimestamp(meterValue.Timestamp.Time, conn.clock.Now())
+		}
+
+		// queued samples don't reflect the current state
+		if conn.cp.isReplay(meterValue.Timestamp) {
+			conn.log.TRACE.Printf("ignoring replayed meter values: %s", meterValue.Timestamp.Time)
+			continue
+		}
+
 		if meterValue.Timestamp == nil {
 			// this should be done before the sorting, but lets assume either all or no sample has a timestamp
 			meterValue.Timestamp = types.NewDateTime(conn.clock.Now())
+		} else {
+			meterValue.Timestamp = types.NewDateTime(conn.cp.correctTime(meterValue.Timestamp.Time))
 		}
 
 		// ignore old meter value requests
 		if !meterValue.Timestamp.Time.Before(conn.meterUpdated) {
 			for _, sample := range meterValue.SampledValue {
-				sample.Value = strings.TrimSpace(sample.Value)
+				sample = normalizeSample(sample, conn.quirks)
 				conn.measurements[getSampleKey(sample)] = sample
+
+				if sample.Measurand == types.MeasurandSoC {
+					conn.socUpdated = meterValue.Timestamp.Time
+				}
+
+				if conn.energy.add(sample, meterValue.Timestamp.Time) {
+					if err := journal.Instance.Set(conn.energyJournalKey(), conn.energy.state()); err != nil {
+						conn.log.ERROR.Printf("journal: %v", err)
+					}
+				}
 				conn.meterUpdated = meterValue.Timestamp.Time
 			}
 		}
@@ -88,12 +280,31 @@ func (conn *Connector) OnMeterValues(request *core.MeterValuesRequest) (*core.Me
 	return new(core.MeterValuesConfirmation), nil
 }
 
+// OnAuthorize remembers the id tag authorized for the upcoming or running transaction
+func (conn *Connector) OnAuthorize(request *core.AuthorizeRequest) {
+	conn.mu.Lock()
+	defer conn.mu.Unlock()
+
+	if conn.authIdTag != request.IdTag {
+		conn.log.DEBUG.Printf("authorized id tag: %s", request.IdTag)
+		conn.authIdTag = request.IdTag
+	}
+}
+
 func (conn *Connector) OnStartTransaction(request *core.StartTransactionRequest) (*core.StartTransactionConfirmation, error) {
 	conn.mu.Lock()
 	defer conn.mu.Unlock()
 
-	conn.txnId = int(instance.txnId.Add(1))
-	conn.idTag = request.IdTag
+	conn.txnStarted, conn.txnMeterStart = conn.clock.Now(), request.MeterStart
+	if request.Timestamp != nil {
+		conn.txnStarted = request.Timestamp.Time
+	}
+
+	if conn.txnReplayed = conn.cp.isReplay(request.Timestamp); conn.txnReplayed {
+		conn.log.DEBUG.Printf("replayed transaction started at %s", conn.txnStarted)
+	}
+
+	conn.setTransaction(int(instance.txnId.Add(1)), request.IdTag)
 
 	res := &core.StartTransactionConfirmation{
 		IdTagInfo: &types.IdTagInfo{
@@ -142,8 +353,7 @@ func (conn *Connector) OnStopTransaction(request *core.StopTransactionRequest) (
 	conn.mu.Lock()
 	defer conn.mu.Unlock()
 
-	conn.txnId = 0
-	conn.idTag = ""
+	conn.setTransaction(0, "")
 
 	res := &core.StopTransactionConfirmation{
 		IdTagInfo: &types.IdTagInfo{
@@ -151,7 +361,10 @@ func (conn *Connector) OnStopTransaction(request *core.StopTransactionRequest) (
 		},
 	}
 
-	conn.assumeMeterStopped()
+	// live measurements are not affected by transactions stopped while offline
+	if request == nil || !conn.cp.isReplay(request.Timestamp) {
+		conn.assumeMeterStopped()
+	}
 
 	return res, nil
 }
@@ -0,0 +1,119 @@
+package ocpp
+
+import (
+	"strconv"
+	"time"
+
+	"github.com/lorenzodonini/ocpp-go/ocpp1.6/types"
+)
+
+// registerResetMax is the highest register value considered a reset when the register drops
+const registerResetMax = 1000 // Wh
+
+// energyCounter accumulates energy register or interval samples into a monotonic total,
+// compensating charge points that reset their register per transaction.
+// A drop to near zero is a reset only once the next sample confirms the register restarted,
+// other drops are ignored as outliers.
+type energyCounter struct {
+	offset   float64 // Wh, energy counted before the last register reset or from interval samples
+	register float64 // Wh, last register value
+	low      float64 // Wh, register value suspected to be a reset
+	pending  bool    // register reset awaiting confirmation
+	valid    bool    // total has been initialized
+	interval bool    // total accumulated from interval samples
+	updated  time.Time
+}
+
+// energyState is the journaled energy counter surviving restarts
+type energyState struct {
+	Offset   float64 `json:"offset"`
+	Register float64 `json:"register"`
+}
+
+// total returns the accumulated energy in Wh
+func (e *energyCounter) total() float64 {
+	if e.interval {
+		return e.offset
+	}
+	return e.offset + e.register
+}
+
+func (e *energyCounter) state() energyState {
+	return energyState{Offset: e.offset, Register: e.register}
+}
+
+// restore continues counting from the journaled state. The restored register is replaced by the next sample.
+func (e *energyCounter) restore(s energyState) {
+	e.offset, e.register = s.Offset, s.Register
+}
+
+// add applies the normalized energy sample and returns true if the total has been updated
+func (e *energyCounter) add(sample types.SampledValue, ts time.Time) bool {
+	if sample.Phase != "" {
+		return false
+	}
+
+	f, err := strconv.ParseFloat(sample.Value, 64)
+	if err != nil || f < 0 {
+		return false
+	}
+
+	switch sample.Measurand {
+	case types.MeasurandEnergyActiveImportRegister:
+		switch {
+		case !e.valid && f < e.register:
+			if f <= registerResetMax {
+				// register has been reset while not connected
+				e.offset += e.register
+			} else {
+				// register inconsistent with the journaled state, keep the total
+				e.offset += e.register - f
+			}
+		case !e.valid:
+			// first sample continues the restored total
+		case e.interval:
+			// register takes precedence over interval samples, continue counting from here
+			e.offset -= f
+			e.interval = false
+		case e.pending:
+			e.pending = false
+			switch {
+			case f >= e.register:
+				// suspected reset was an outlier
+			case f >= e.low:
+				// register continues counting from the reset
+				e.offset += e.register
+			default:
+				return false
+			}
+		case f < e.register:
+			// wait for confirmation if the register restarts, ignore outliers otherwise
+			if f <= registerResetMax {
+				e.pending, e.low = true, f
+			}
+			return false
+		}
+
+		e.register, e.valid = f, true
+
+		return true
+
+	case types.MeasurandEnergyActiveImportInterval:
+		// ignore intervals if the register is available, skip duplicate samples
+		if e.valid && !e.interval || !ts.After(e.updated) {
+			return false
+		}
+
+		if !e.valid {
+			e.offset += e.register
+			e.register = 0
+		}
+
+		e.offset += f
+		e.valid, e.interval, e.updated = true, true, ts
+
+		return true
+	}
+
+	return false
+}
@@ -0,0 +1,29 @@
+package ocpp
+
+import (
+	"testing"
+	"time"
+
+	"github.com/lorenzodonini/ocpp-go/ocpp1.6/types"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestEnergyRestore(t *testing.T) {
+	sample := func(v string) types.SampledValue {
+		return types.SampledValue{Measurand: types.MeasurandEnergyActiveImportRegister, Value: v}
+	}
+
+	var e energyCounter
+	e.restore(energyState{Offset: 1500, Register: 700})
+
+	assert.True(t, e.add(sample("800"), time.Now()))
+	assert.Equal(t, 2300.0, e.total())
+
+	// register reset while not connected
+	e = energyCounter{}
+	e.restore(energyState{Offset: 1500, Register: 700})
+
+	assert.True(t, e.add(sample("100"), time.Now()))
+	assert.Equal(t, 2300.0, e.total())
+	assert.Equal(t, energyState{Offset: 2200, Register: 100}, e.state())
+}
@@ -1,6 +1,8 @@
 package ocpp
 
 import (
+	"encoding/json"
+
 	"github.com/lorenzodonini/ocpp-go/ocpp1.6/core"
 	"github.com/lorenzodonini/ocpp-go/ocpp1.6/remotetrigger"
 	"github.com/lorenzodonini/ocpp-go/ocpp1.6/smartcharging"
@@ -19,10 +21,26 @@ func (conn *Connector) RemoteStartTransactionRequest(idTag string) error {
 	return conn.cp.RemoteStartTransactionRequest(conn.id, idTag)
 }
 
+func (conn *Connector) RemoteStopTransactionRequest(transactionId int) error {
+	return conn.cp.RemoteStopTransactionRequest(conn.id, transactionId)
+}
+
+func (conn *Connector) UnlockConnectorRequest() error {
+	return conn.cp.UnlockConnectorRequest(conn.id)
+}
+
 func (conn *Connector) SetChargingProfileRequest(profile *types.ChargingProfile) error {
-	return conn.cp.SetChargingProfileRequest(conn.id, profile)
+	conn.mu.Lock()
+	phaseToUse := conn.phaseToUse
+	conn.mu.Unlock()
+
+	return conn.cp.setChargingProfileRequest(conn.id, profile, phaseToUse)
 }
 
 func (conn *Connector) TriggerMessageRequest(requestedMessage remotetrigger.MessageTrigger) error {
 	return conn.cp.TriggerMessageRequest(conn.id, requestedMessage)
 }
+
+func (conn *Connector) DataTransferRequest(vendorId, messageId string, data any) (json.RawMessage, error) {
+	return conn.cp.DataTransferRequest(vendorId, messageId, data)
+}
@@ -0,0 +1,67 @@
+package ocpp
+
+import (
+	"errors"
+	"time"
+)
+
+// NewReservationID returns a new unique reservation id
+func NewReservationID() int {
+	return int(Instance().txnId.Add(1))
+}
+
+// Reserve reserves the connector for the id tag until expiry, replacing a previous reservation
+func (conn *Connector) Reserve(expiry time.Time, idTag string) error {
+	conn.mu.Lock()
+	id := conn.reservationId
+	if id == 0 || !conn.clock.Now().Before(conn.reservationExpiry) {
+		id = NewReservationID()
+	}
+	conn.mu.Unlock()
+
+	if err := conn.cp.ReserveNowRequest(conn.id, expiry, idTag, id); err != nil {
+		return err
+	}
+
+	conn.mu.Lock()
+	conn.reservationId, conn.reservationExpiry = id, expiry
+	conn.mu.Unlock()
+
+	return nil
+}
+
+// CancelReservation cancels the connector's reservation
+func (conn *Connector) CancelReservation() error {
+	conn.mu.Lock()
+	id, expiry := conn.reservationId, conn.reservationExpiry
+	conn.reservationId, conn.reservationExpiry = 0, time.Time{}
+	conn.mu.Unlock()
+
+	if id == 0 || !conn.clock.Now().Before(expiry) {
+		return errors.New("no reservation")
+	}
+
+	return conn.cp.CancelReservationRequest(id)
+}
+
+// Reservation returns the connector's reservation id and expiry if reserved
+func (conn *Connector) Reservation() (int, time.Time) {
+	conn.mu.Lock()
+	defer conn.mu.Unlock()
+
+	if conn.reservationId == 0 || !conn.clock.Now().Before(conn.reservationExpiry) {
+		return 0, time.Time{}
+	}
+
+	return conn.reservationId, conn.reservationExpiry
+}
+
+// reservationEnded clears the reservation after the charge point reported its expiry or removal
+func (conn *Connector) reservationEnded(reservationId int) {
+	conn.mu.Lock()
+	defer conn.mu.Unlock()
+
+	if conn.reservationId == reservationId {
+		conn.reservationId, conn.reservationExpiry = 0, time.Time{}
+	}
+}
@@ -1,12 +1,16 @@
 package ocpp
 
 import (
+	"context"
+	"path/filepath"
 	"testing"
 	"time"
 
 	"github.com/benbjohnson/clock"
 	"github.com/evcc-io/evcc/api"
+	"github.com/evcc-io/evcc/server/db/journal"
 	"github.com/evcc-io/evcc/util"
+	"github.com/lorenzodonini/ocpp-go/ocpp1.6/core"
 	"github.com/lorenzodonini/ocpp-go/ocpp1.6/types"
 	"github.com/stretchr/testify/suite"
 )
@@ -101,9 +105,10 @@ func (suite *connTestSuite) TestConnectorMeasurementsNoTxn() {
 	res, err = suite.conn.TotalEnergy()
 	suite.NoError(err, "TotalEnergy")
 	suite.Equal(res, 0.001, "TotalEnergy")
-	res, err = suite.conn.Soc()
-	suite.NoError(err, "Soc")
-	suite.Equal(res, 1.0, "Soc")
+
+	// stale soc
+	_, err = suite.conn.Soc()
+	suite.Equal(api.ErrNotAvailable, err, "Soc")
 	res1, res2, res3, err = suite.conn.Voltages()
 	suite.NoError(err, "Voltages")
 	suite.Equal(res1, 1.0, "Voltages")
@@ -136,6 +141,7 @@ func (suite *connTestSuite) TestConnectorMeasurementsRunningTxn() {
 	suite.addMeasurements()
 	suite.clock.Add(time.Hour)
 	suite.conn.meterUpdated = suite.clock.Now()
+	suite.conn.socUpdated = suite.clock.Now()
 	suite.conn.txnId = 1
 
 	_, err := suite.conn.CurrentPower()
@@ -171,3 +177,419 @@ func (suite *connTestSuite) TestOnStopTransactionResetsReportedPower() {
 	suite.NoError(err, "CurrentPower")
 	suite.Equal(res, 0.0, "CurrentPower")
 }
+
+func (suite *connTestSuite) TestConnectorResumeTransaction() {
+	suite.Require().NoError(journal.NewInstance(filepath.Join(suite.T().TempDir(), "evcc.journal")))
+	suite.T().Cleanup(func() { journal.Instance = nil })
+
+	res, err := suite.conn.OnStartTransaction(&core.StartTransactionRequest{IdTag: "tag"})
+	suite.Require().NoError(err)
+
+	// restart
+	conn, err := NewConnector(suite.T().Context(), util.NewLogger("foo"), 2, suite.cp, "", Timeout)
+	suite.Require().NoError(err)
+	suite.Zero(conn.txnId, "other connector")
+
+	suite.cp.deregisterConnector(1)
+	conn, err = NewConnector(suite.T().Context(), util.NewLogger("foo"), 1, suite.cp, "", Timeout)
+	suite.Require().NoError(err)
+	suite.Equal(res.TransactionId, conn.txnId)
+	suite.Equal("tag", conn.IdTag())
+
+	// finished while offline
+	_, err = conn.OnStatusNotification(&core.StatusNotificationRequest{ConnectorId: 1, Status: core.ChargePointStatusAvailable})
+	suite.Require().NoError(err)
+	suite.Zero(conn.txnId)
+	suite.Empty(journal.Instance.Keys("ocpp."))
+}
+
+func (suite *connTestSuite) TestConnectorAuthorizedIdTag() {
+	suite.conn.remoteIdTag = "evcc"
+	suite.cp.OnAuthorize(&core.AuthorizeRequest{IdTag: "rfid"})
+	suite.Equal("rfid", suite.conn.IdTag())
+
+	// authorized id tag is preferred over remote start id tag
+	_, err := suite.conn.OnStartTransaction(&core.StartTransactionRequest{IdTag: "evcc"})
+	suite.Require().NoError(err)
+	suite.Equal("rfid", suite.conn.IdTag())
+
+	_, err = suite.conn.OnStopTransaction(&core.StopTransactionRequest{TransactionId: suite.conn.txnId})
+	suite.Require().NoError(err)
+	suite.Empty(suite.conn.IdTag())
+
+	// vehicle has left
+	suite.cp.OnAuthorize(&core.AuthorizeRequest{IdTag: "rfid"})
+	_, err = suite.conn.OnStatusNotification(&core.StatusNotificationRequest{ConnectorId: 1, Status: core.ChargePointStatusAvailable})
+	suite.Require().NoError(err)
+	suite.Empty(suite.conn.IdTag())
+}
+
+func (suite *connTestSuite) TestTransactionEvents() {
+	var events []TransactionEvent
+	SetTransactionEventHandler(func(event TransactionEvent) {
+		events = append(events, event)
+	})
+	defer SetTransactionEventHandler(nil)
+
+	var stopped TransactionEvent
+	suite.conn.OnTransactionStopped(func(event TransactionEvent) {
+		stopped = event
+	})
+
+	start := suite.clock.Now()
+	res, err := suite.cp.OnStartTransaction(&core.StartTransactionRequest{
+		ConnectorId: 1,
+		IdTag:       "tag",
+		MeterStart:  1000,
+		Timestamp:   types.NewDateTime(start),
+	})
+	suite.Require().NoError(err)
+
+	_, err = suite.cp.OnStopTransaction(&core.StopTransactionRequest{
+		TransactionId: res.TransactionId,
+		MeterStop:     11500,
+		Timestamp:     types.NewDateTime(start.Add(time.Hour)),
+		Reason:        core.ReasonEVDisconnected,
+	})
+	suite.Require().NoError(err)
+
+	suite.Require().Len(events, 2)
+	suite.Equal(TransactionEvent{
+		Station:       "abc",
+		Connector:     1,
+		Event:         TransactionStarted,
+		TransactionId: res.TransactionId,
+		IdTag:         "tag",
+		Start:         start,
+		MeterStart:    1,
+	}, events[0])
+	suite.Equal(TransactionEvent{
+		Station:       "abc",
+		Connector:     1,
+		Event:         TransactionStopped,
+		TransactionId: res.TransactionId,
+		IdTag:         "tag",
+		Start:         start,
+		Stop:          start.Add(time.Hour),
+		MeterStart:    1,
+		MeterStop:     11.5,
+		Duration:      time.Hour,
+		Reason:        string(core.ReasonEVDisconnected),
+	}, events[1])
+	suite.Equal(events[1], stopped)
+}
+
+func (suite *connTestSuite) TestReplayedTransaction() {
+	var stopped TransactionEvent
+	suite.conn.OnTransactionStopped(func(event TransactionEvent) {
+		stopped = event
+	})
+
+	// live measurements
+	suite.conn.cp.connectedAt = time.Now()
+	suite.conn.meterUpdated = suite.clock.Now()
+	suite.conn.measurements[types.MeasurandPowerActiveImport] = types.SampledValue{Value: "1000"}
+
+	// transaction queued while offline
+	start := suite.conn.cp.connectedAt.Add(-2 * time.Hour)
+	res, err := suite.cp.OnStartTransaction(&core.StartTransactionRequest{
+		ConnectorId: 1,
+		IdTag:       "tag",
+		MeterStart:  1000,
+		Timestamp:   types.NewDateTime(start),
+	})
+	suite.Require().NoError(err)
+
+	_, err = suite.cp.OnMeterValues(&core.MeterValuesRequest{
+		ConnectorId:   1,
+		TransactionId: &res.TransactionId,
+		MeterValue: []types.MeterValue{{
+			Timestamp: types.NewDateTime(start.Add(time.Minute)),
+			SampledValue: []types.SampledValue{
+				{Measurand: types.MeasurandPowerActiveImport, Value: "11000"},
+			},
+		}},
+	})
+	suite.Require().NoError(err)
+
+	_, err = suite.cp.OnStopTransaction(&core.StopTransactionRequest{
+		TransactionId: res.TransactionId,
+		MeterStop:     11500,
+		Timestamp:     types.NewDateTime(start.Add(time.Hour)),
+	})
+	suite.Require().NoError(err)
+
+	suite.True(stopped.Replayed)
+	suite.Equal(start, stopped.Start)
+	suite.Equal(10.5, stopped.MeterStop-stopped.MeterStart)
+
+	// live measurements are untouched
+	power, err := suite.conn.CurrentPower()
+	suite.NoError(err)
+	suite.Equal(1000.0, power)
+
+	// transaction started while offline but stopped after reconnecting
+	res, err = suite.cp.OnStartTransaction(&core.StartTransactionRequest{
+		ConnectorId: 1,
+		Timestamp:   types.NewDateTime(start),
+	})
+	suite.Require().NoError(err)
+
+	_, err = suite.cp.OnStopTransaction(&core.StopTransactionRequest{
+		TransactionId: res.TransactionId,
+		MeterStop:     12000,
+		Timestamp:     types.NewDateTime(time.Now()),
+	})
+	suite.Require().NoError(err)
+
+	suite.False(stopped.Replayed)
+}
+
+func (suite *connTestSuite) TestClockSkewStatus() {
+	// charge point clock is behind more than the status timeout
+	ts := suite.clock.Now().Add(-2 * Timeout)
+
+	for _, status := range []core.ChargePointStatus{core.ChargePointStatusPreparing, core.ChargePointStatusCharging} {
+		_, err := suite.conn.OnStatusNotification(&core.StatusNotificationRequest{
+			ConnectorId: 1,
+			Status:      status,
+			Timestamp:   types.NewDateTime(ts),
+		})
+		suite.Require().NoError(err)
+
+		ts = ts.Add(time.Second)
+		suite.clock.Add(time.Second)
+	}
+
+	suite.Equal(core.ChargePointStatusCharging, suite.conn.status.Status)
+	suite.Equal(-2*Timeout, suite.cp.skew)
+}
+
+func (suite *connTestSuite) TestConnectorResumeStatus() {
+	suite.Require().NoError(journal.NewInstance(filepath.Join(suite.T().TempDir(), "evcc.journal")))
+	suite.T().Cleanup(func() { journal.Instance = nil })
+
+	_, err := suite.conn.OnStatusNotification(&core.StatusNotificationRequest{ConnectorId: 1, Status: core.ChargePointStatusCharging})
+	suite.Require().NoError(err)
+
+	// restart
+	suite.cp.deregisterConnector(1)
+	conn, err := NewConnector(suite.T().Context(), util.NewLogger("foo"), 1, suite.cp, "", Timeout)
+	suite.Require().NoError(err)
+	suite.Require().NoError(conn.Initialized())
+	suite.Equal(core.ChargePointStatusCharging, conn.status.Status)
+}
+
+func (suite *connTestSuite) TestFinishJournaledTransaction() {
+	suite.Require().NoError(journal.NewInstance(filepath.Join(suite.T().TempDir(), "evcc.journal")))
+	suite.T().Cleanup(func() { journal.Instance = nil })
+
+	res, err := suite.conn.OnStartTransaction(&core.StartTransactionRequest{ConnectorId: 1, IdTag: "tag", MeterStart: 1000})
+	suite.Require().NoError(err)
+
+	var events []TransactionEvent
+	SetTransactionEventHandler(func(event TransactionEvent) {
+		events = append(events, event)
+	})
+	defer SetTransactionEventHandler(nil)
+
+	// stopped after restart before the connector has been registered
+	suite.cp.deregisterConnector(1)
+	_, err = suite.cp.OnStopTransaction(&core.StopTransactionRequest{TransactionId: res.TransactionId, MeterStop: 2000})
+	suite.Require().NoError(err)
+
+	suite.Empty(journal.Instance.Keys("ocpp.abc.1.transaction"))
+	suite.Require().Len(events, 1)
+	suite.Equal(1, events[0].Connector)
+	suite.Equal("tag", events[0].IdTag)
+	suite.Equal(1.0, events[0].MeterStop-events[0].MeterStart)
+}
+
+func (suite *connTestSuite) TestRefreshBackoff() {
+	t := &staleTrigger{message: core.MeterValuesFeatureName}
+	now := suite.clock.Now()
+
+	// charge point is not registered, request fails and backs off
+	suite.conn.refresh(t, true, now)
+	suite.Equal(triggerBackoff, t.backoff)
+	suite.Equal(now.Add(triggerBackoff), t.next)
+
+	// no request while backing off
+	suite.conn.refresh(t, true, now.Add(time.Second))
+	suite.Equal(triggerBackoff, t.backoff)
+
+	suite.conn.refresh(t, true, t.next)
+	suite.Equal(2*triggerBackoff, t.backoff)
+
+	for range 10 {
+		suite.conn.refresh(t, true, t.next)
+	}
+	suite.Equal(triggerMaxBackoff, t.backoff)
+
+	// fresh data resets back-off
+	suite.conn.refresh(t, false, t.next)
+	suite.Zero(t.backoff)
+
+	// unsupported messages are not triggered
+	suite.cp.setTriggerUnsupported(core.MeterValuesFeatureName)
+	suite.False(suite.cp.triggerSupported(core.MeterValuesFeatureName))
+	suite.conn.refresh(t, true, now)
+	suite.Zero(t.backoff)
+}
+
+func (suite *connTestSuite) TestFaultReset() {
+	// disabled
+	suite.False(suite.conn.countFault(core.ChargePointStatusFaulted))
+	suite.False(suite.conn.countFault(core.ChargePointStatusAvailable))
+
+	suite.conn.SetFaultReset(2)
+	suite.clock.Add(faultResetInterval)
+
+	suite.False(suite.conn.countFault(core.ChargePointStatusFaulted))
+	suite.False(suite.conn.countFault(core.ChargePointStatusAvailable))
+	suite.False(suite.conn.countFault(core.ChargePointStatusFaulted))
+	suite.True(suite.conn.countFault(core.ChargePointStatusFaulted))
+
+	// not again within reset interval
+	suite.False(suite.conn.countFault(core.ChargePointStatusFaulted))
+	suite.False(suite.conn.countFault(core.ChargePointStatusFaulted))
+
+	suite.clock.Add(faultResetInterval)
+	suite.True(suite.conn.countFault(core.ChargePointStatusFaulted))
+}
+
+func (suite *connTestSuite) TestQuirkEnergyWh() {
+	suite.conn.SetQuirks(Quirks{EnergyWh: true})
+
+	_, err := suite.conn.OnMeterValues(&core.MeterValuesRequest{
+		MeterValue: []types.MeterValue{{
+			Timestamp: types.NewDateTime(suite.clock.Now()),
+			SampledValue: []types.SampledValue{
+				{Measurand: types.MeasurandEnergyActiveImportRegister, Value: "1500", Unit: types.UnitOfMeasureKWh},
+			},
+		}},
+	})
+	suite.Require().NoError(err)
+
+	res, err := suite.conn.TotalEnergy()
+	suite.NoError(err)
+	suite.Equal(1.5, res)
+}
+
+func (suite *connTestSuite) sendEnergy(measurand types.Measurand, value string) {
+	suite.clock.Add(time.Minute)
+
+	_, err := suite.conn.OnMeterValues(&core.MeterValuesRequest{
+		MeterValue: []types.MeterValue{{
+			Timestamp: types.NewDateTime(suite.clock.Now()),
+			SampledValue: []types.SampledValue{
+				{Measurand: measurand, Value: value, Unit: types.UnitOfMeasureWh},
+			},
+		}},
+	})
+	suite.Require().NoError(err)
+}
+
+func (suite *connTestSuite) TestEnergyRegisterReset() {
+	for _, v := range []string{"1000", "1500", "200", "700"} {
+		suite.sendEnergy(types.MeasurandEnergyActiveImportRegister, v)
+	}
+
+	// 1500 Wh before reset, 700 Wh after
+	res, err := suite.conn.TotalEnergy()
+	suite.NoError(err)
+	suite.Equal(2.2, res)
+}
+
+func (suite *connTestSuite) TestEnergyInterval() {
+	for _, v := range []string{"100", "250", "50"} {
+		suite.sendEnergy(types.MeasurandEnergyActiveImportInterval, v)
+	}
+
+	res, err := suite.conn.TotalEnergy()
+	suite.NoError(err)
+	suite.Equal(0.4, res)
+
+	// register takes over
+	suite.sendEnergy(types.MeasurandEnergyActiveImportRegister, "5000")
+	suite.sendEnergy(types.MeasurandEnergyActiveImportInterval, "100")
+	suite.sendEnergy(types.MeasurandEnergyActiveImportRegister, "5100")
+
+	res, err = suite.conn.TotalEnergy()
+	suite.NoError(err)
+	suite.Equal(0.5, res)
+}
+
+func (suite *connTestSuite) TestSoc() {
+	_, err := suite.conn.OnMeterValues(&core.MeterValuesRequest{
+		MeterValue: []types.MeterValue{{
+			Timestamp: types.NewDateTime(suite.clock.Now()),
+			SampledValue: []types.SampledValue{
+				{Measurand: types.MeasurandSoC, Value: "42", Unit: types.UnitOfMeasurePercent},
+			},
+		}},
+	})
+	suite.Require().NoError(err)
+
+	res, err := suite.conn.Soc()
+	suite.NoError(err)
+	suite.Equal(42.0, res)
+
+	// stale
+	suite.clock.Add(socTimeout + time.Second)
+	_, err = suite.conn.Soc()
+	suite.Equal(api.ErrNotAvailable, err)
+
+	// vehicle has left
+	suite.conn.socUpdated = suite.clock.Now()
+	_, err = suite.conn.OnStatusNotification(&core.StatusNotificationRequest{ConnectorId: 1, Status: core.ChargePointStatusAvailable})
+	suite.Require().NoError(err)
+
+	_, err = suite.conn.Soc()
+	suite.Equal(api.ErrNotAvailable, err)
+}
+
+func (suite *connTestSuite) TestConnectorSupersede() {
+	res, err := suite.conn.OnStartTransaction(&core.StartTransactionRequest{ConnectorId: 1, IdTag: "tag", MeterStart: 1000})
+	suite.Require().NoError(err)
+
+	// reconfigured connector continues the transaction
+	ctx2, cancel2 := context.WithCancel(suite.T().Context())
+	conn2, err := NewConnector(ctx2, util.NewLogger("foo"), 1, suite.cp, "", Timeout)
+	suite.Require().NoError(err)
+	suite.Same(conn2, suite.cp.connectorByID(1))
+
+	txn, err := conn2.TransactionID()
+	suite.Require().NoError(err)
+	suite.Equal(res.TransactionId, txn)
+
+	ctx3, cancel3 := context.WithCancel(suite.T().Context())
+	conn3, err := NewConnector(ctx3, util.NewLogger("foo"), 1, suite.cp, "", Timeout)
+	suite.Require().NoError(err)
+
+	// superseded connector released
+	cancel2()
+	suite.Eventually(func() bool {
+		suite.cp.mu.RLock()
+		defer suite.cp.mu.RUnlock()
+		return conn3.prev == suite.conn
+	}, time.Second, time.Millisecond)
+	suite.Same(conn3, suite.cp.connectorByID(1))
+
+	// superseding connector released before replacing the previous one
+	cancel3()
+	suite.Eventually(func() bool {
+		return suite.cp.connectorByID(1) == suite.conn
+	}, time.Second, time.Millisecond)
+}
+
+func (suite *connTestSuite) TestEnergyRegisterOutlier() {
+	for _, v := range []string{"1000", "0", "1001", "500", "1002"} {
+		suite.sendEnergy(types.MeasurandEnergyActiveImportRegister, v)
+	}
+
+	// single drops are ignored
+	res, err := suite.conn.TotalEnergy()
+	suite.NoError(err)
+	suite.Equal(1.002, res)
+}
@@ -0,0 +1,86 @@
+package ocpp
+
+import (
+	"context"
+	"time"
+
+	"github.com/evcc-io/evcc/util/supervisor"
+	"github.com/lorenzodonini/ocpp-go/ocpp1.6/core"
+	"github.com/lorenzodonini/ocpp-go/ocpp1.6/remotetrigger"
+)
+
+const (
+	statusRefresh     = 15 * time.Minute // status notifications are only sent on change
+	triggerBackoff    = 10 * time.Second
+	triggerMaxBackoff = 5 * time.Minute
+)
+
+// staleTrigger triggers a message while its data is stale, backing off while the charge point doesn't send it
+type staleTrigger struct {
+	message remotetrigger.MessageTrigger
+	backoff time.Duration
+	next    time.Time
+}
+
+// triggerNotSupported checks if the charge point can't trigger the message
+func triggerNotSupported(err error) bool {
+	return notSupported(err) || err.Error() == string(remotetrigger.TriggerMessageStatusNotImplemented)
+}
+
+// WatchDog triggers meter values messages if older than timeout and refreshes the status periodically.
+// It is restarted by the supervisor if the charge point stops responding to requests.
+func (conn *Connector) WatchDog(ctx context.Context, timeout time.Duration) {
+	meter := &staleTrigger{message: core.MeterValuesFeatureName}
+	status := &staleTrigger{message: core.StatusNotificationFeatureName}
+
+	supervisor.Now(ctx, "watchdog", 2*time.Second, func(context.Context) error {
+		conn.mu.Lock()
+		now := conn.clock.Now()
+		meterStale := now.Sub(conn.meterUpdated) > timeout
+		statusStale := now.Sub(conn.statusUpdated) > statusRefresh
+		conn.mu.Unlock()
+
+		conn.refresh(meter, meterStale, now)
+		conn.refresh(status, statusStale, now)
+
+		return nil
+	})
+}
+
+// refresh triggers the message if stale unless backing off or not supported by the charge point
+func (conn *Connector) refresh(t *staleTrigger, stale bool, now time.Time) {
+	if !stale {
+		t.backoff, t.next = 0, time.Time{}
+		return
+	}
+
+	if now.Before(t.next) || !conn.cp.triggerSupported(t.message) {
+		return
+	}
+
+	t.backoff = min(max(2*t.backoff, triggerBackoff), triggerMaxBackoff)
+	t.next = now.Add(t.backoff)
+
+	if err := conn.TriggerMessageRequest(t.message); err != nil {
+		if triggerNotSupported(err) {
+			conn.cp.setTriggerUnsupported(t.message)
+			conn.log.WARN.Printf("triggering %s not supported, values may be outdated", t.message)
+			return
+		}
+
+		conn.log.DEBUG.Printf("failed triggering %s: %v", t.message, err)
+	}
+}
+
+// triggerSupported checks if the charge point has not rejected triggering the message as unsupported
+func (cp *CP) triggerSupported(message remotetrigger.MessageTrigger) bool {
+	cp.mu.RLock()
+	defer cp.mu.RUnlock()
+	return !cp.triggerUnsupported[message]
+}
+
+func (cp *CP) setTriggerUnsupported(message remotetrigger.MessageTrigger) {
+	cp.mu.Lock()
+	defer cp.mu.Unlock()
+	cp.triggerUnsupported[message] = true
+}
@@ -1,11 +1,23 @@
 package ocpp
 
-import "time"
+import (
+	"time"
+
+	"github.com/lorenzodonini/ocpp-go/ocpp1.6/types"
+	types201 "github.com/lorenzodonini/ocpp-go/ocpp2.0.1/types"
+)
 
 var Timeout = 30 * time.Second // default request / response timeout on protocol level
 
+// Supported protocol versions, negotiated using the websocket subprotocol
+const (
+	ProtocolV16  = types.V16Subprotocol
+	ProtocolV201 = types201.V201Subprotocol
+)
+
 const (
 	// Core profile keys
+	KeyHeartbeatInterval               = "HeartbeatInterval"
 	KeyMeterValueSampleInterval        = "MeterValueSampleInterval"
 	KeyMeterValuesSampledData          = "MeterValuesSampledData"
 	KeyMeterValuesSampledDataMaxLength = "MeterValuesSampledDataMaxLength"
@@ -16,6 +28,7 @@ const (
 	// SmartCharging profile keys
 	KeyChargeProfileMaxStackLevel              = "ChargeProfileMaxStackLevel"
 	KeyChargingScheduleAllowedChargingRateUnit = "ChargingScheduleAllowedChargingRateUnit"
+	KeyChargingScheduleMaxPeriods              = "ChargingScheduleMaxPeriods"
 	KeyConnectorSwitch3to1PhaseSupported       = "ConnectorSwitch3to1PhaseSupported"
 	KeyMaxChargingProfilesInstalled            = "MaxChargingProfilesInstalled"
 
@@ -0,0 +1,249 @@
+package ocpp
+
+import (
+	"math"
+	"strconv"
+
+	"github.com/lorenzodonini/ocpp-go/ocpp1.6/core"
+	"github.com/lorenzodonini/ocpp-go/ocpp1.6/types"
+	"github.com/lorenzodonini/ocpp-go/ocpp2.0.1/availability"
+	smartcharging201 "github.com/lorenzodonini/ocpp-go/ocpp2.0.1/smartcharging"
+	"github.com/lorenzodonini/ocpp-go/ocpp2.0.1/transactions"
+	types201 "github.com/lorenzodonini/ocpp-go/ocpp2.0.1/types"
+)
+
+// OCPP 2.0.1 messages are translated to their 1.6 equivalents. Each EVSE is mapped to the connector of the same id.
+
+// evse is the OCPP 2.0.1 EVSE state. Unlike 1.6, the connector status does not contain the charging state
+// which is reported by transaction events instead.
+type evse struct {
+	connector availability.ConnectorStatus
+	charging  transactions.ChargingState
+	txn       string // running transaction
+	finished  bool   // transaction has ended while connector is still occupied
+}
+
+// transaction201 maps OCPP 2.0.1 transactions to their EVSE and 1.6 transaction id
+type transaction201 struct {
+	evse int
+	id   int
+}
+
+// status returns the equivalent 1.6 connector status
+func (e *evse) status() core.ChargePointStatus {
+	switch e.connector {
+	case availability.ConnectorStatusAvailable:
+		return core.ChargePointStatusAvailable
+	case availability.ConnectorStatusReserved:
+		return core.ChargePointStatusReserved
+	case availability.ConnectorStatusUnavailable:
+		return core.ChargePointStatusUnavailable
+	case availability.ConnectorStatusFaulted:
+		return core.ChargePointStatusFaulted
+	case availability.ConnectorStatusOccupied:
+	default:
+		// status not yet received, transaction implies occupied connector
+		if e.txn == "" && !e.finished {
+			return core.ChargePointStatusAvailable
+		}
+	}
+
+	// occupied
+	if e.txn == "" {
+		if e.finished {
+			return core.ChargePointStatusFinishing
+		}
+		return core.ChargePointStatusPreparing
+	}
+
+	switch e.charging {
+	case transactions.ChargingStateCharging:
+		return core.ChargePointStatusCharging
+	case transactions.ChargingStateSuspendedEV:
+		return core.ChargePointStatusSuspendedEV
+	case transactions.ChargingStateSuspendedEVSE, transactions.ChargingStateEVConnected:
+		return core.ChargePointStatusSuspendedEVSE
+	default:
+		return core.ChargePointStatusPreparing
+	}
+}
+
+// statusNotification returns the equivalent 1.6 status notification
+func (e *evse) statusNotification(id int, timestamp *types201.DateTime) *core.StatusNotificationRequest {
+	res := &core.StatusNotificationRequest{
+		ConnectorId: id,
+		ErrorCode:   core.NoError,
+		Status:      e.status(),
+	}
+
+	if res.Status == core.ChargePointStatusFaulted {
+		res.ErrorCode = core.OtherError
+	}
+
+	if timestamp != nil {
+		res.Timestamp = types.NewDateTime(timestamp.Time)
+	}
+
+	return res
+}
+
+func meterValues16(values []types201.MeterValue) []types.MeterValue {
+	res := make([]types.MeterValue, 0, len(values))
+
+	for _, mv := range values {
+		samples := make([]types.SampledValue, 0, len(mv.SampledValue))
+
+		for _, sv := range mv.SampledValue {
+			value := sv.Value
+
+			var unit string
+			if u := sv.UnitOfMeasure; u != nil {
+				unit = u.Unit
+				if u.Multiplier != nil {
+					value *= math.Pow10(*u.Multiplier)
+				}
+			}
+
+			measurand := types.Measurand(sv.Measurand)
+			if measurand == "" {
+				measurand = types.MeasurandEnergyActiveImportRegister
+			}
+
+			samples = append(samples, types.SampledValue{
+				Value:     strconv.FormatFloat(value, 'f', -1, 64),
+				Context:   types.ReadingContext(sv.Context),
+				Measurand: measurand,
+				Phase:     types.Phase(sv.Phase),
+				Location:  types.Location(sv.Location),
+				Unit:      types.UnitOfMeasure(unit),
+			})
+		}
+
+		res = append(res, types.MeterValue{
+			Timestamp:    types.NewDateTime(mv.Timestamp.Time),
+			SampledValue: samples,
+		})
+	}
+
+	return res
+}
+
+func chargingProfile201(profile *types.ChargingProfile) *types201.ChargingProfile {
+	res := &types201.ChargingProfile{
+		ID:                     profile.ChargingProfileId,
+		StackLevel:             profile.StackLevel,
+		ChargingProfilePurpose: types201.ChargingProfilePurposeType(profile.ChargingProfilePurpose),
+		ChargingProfileKind:    types201.ChargingProfileKindType(profile.ChargingProfileKind),
+		RecurrencyKind:         types201.RecurrencyKindType(profile.RecurrencyKind),
+	}
+
+	if profile.ChargingProfilePurpose == types.ChargingProfilePurposeChargePointMaxProfile {
+		res.ChargingProfilePurpose = types201.ChargingProfilePurposeChargingStationMaxProfile
+	}
+
+	if profile.ValidFrom != nil {
+		res.ValidFrom = types201.NewDateTime(profile.ValidFrom.Time)
+	}
+	if profile.ValidTo != nil {
+		res.ValidTo = types201.NewDateTime(profile.ValidTo.Time)
+	}
+
+	if s := profile.ChargingSchedule; s != nil {
+		schedule := types201.ChargingSchedule{
+			ID:               profile.ChargingProfileId,
+			Duration:         s.Duration,
+			ChargingRateUnit: types201.ChargingRateUnitType(s.ChargingRateUnit),
+			MinChargingRate:  s.MinChargingRate,
+		}
+
+		if s.StartSchedule != nil {
+			schedule.StartSchedule = types201.NewDateTime(s.StartSchedule.Time)
+		}
+
+		for _, p := range s.ChargingSchedulePeriod {
+			schedule.ChargingSchedulePeriod = append(schedule.ChargingSchedulePeriod, types201.ChargingSchedulePeriod{
+				StartPeriod:  p.StartPeriod,
+				Limit:        p.Limit,
+				NumberPhases: p.NumberPhases,
+			})
+		}
+
+		res.ChargingSchedule = []types201.ChargingSchedule{schedule}
+	}
+
+	return res
+}
+
+// The library's 2.0.1 schedule periods lack phaseToUse. Fields of the following types shadow
+// the embedded fields of the same json name, adding phaseToUse to the request.
+
+type chargingSchedulePeriod201 struct {
+	types201.ChargingSchedulePeriod
+	PhaseToUse *int `json:"phaseToUse,omitempty" validate:"omitempty,min=1,max=3"`
+}
+
+type chargingSchedule201 struct {
+	types201.ChargingSchedule
+	ChargingSchedulePeriod []chargingSchedulePeriod201 `json:"chargingSchedulePeriod"`
+}
+
+type chargingProfilePhase201 struct {
+	types201.ChargingProfile
+	ChargingSchedule []chargingSchedule201 `json:"chargingSchedule"`
+}
+
+type setChargingProfileRequest201 struct {
+	smartcharging201.SetChargingProfileRequest
+	ChargingProfile chargingProfilePhase201 `json:"chargingProfile"`
+}
+
+// phaseToUseRequest201 returns the request charging on the given phase during periods limited to a single phase
+func phaseToUseRequest201(req *smartcharging201.SetChargingProfileRequest, phase int) *setChargingProfileRequest201 {
+	res := &setChargingProfileRequest201{
+		SetChargingProfileRequest: *req,
+		ChargingProfile:           chargingProfilePhase201{ChargingProfile: *req.ChargingProfile},
+	}
+
+	for _, s := range req.ChargingProfile.ChargingSchedule {
+		schedule := chargingSchedule201{ChargingSchedule: s}
+
+		for _, p := range s.ChargingSchedulePeriod {
+			period := chargingSchedulePeriod201{ChargingSchedulePeriod: p}
+			if p.NumberPhases != nil && *p.NumberPhases == 1 {
+				period.PhaseToUse = &phase
+			}
+
+			schedule.ChargingSchedulePeriod = append(schedule.ChargingSchedulePeriod, period)
+		}
+
+		res.ChargingProfile.ChargingSchedule = append(res.ChargingProfile.ChargingSchedule, schedule)
+	}
+
+	return res
+}
+
+func chargingSchedule16(s *types201.ChargingSchedule) *types.ChargingSchedule {
+	if s == nil {
+		return nil
+	}
+
+	res := &types.ChargingSchedule{
+		Duration:         s.Duration,
+		ChargingRateUnit: types.ChargingRateUnitType(s.ChargingRateUnit),
+		MinChargingRate:  s.MinChargingRate,
+	}
+
+	if s.StartSchedule != nil {
+		res.StartSchedule = types.NewDateTime(s.StartSchedule.Time)
+	}
+
+	for _, p := range s.ChargingSchedulePeriod {
+		res.ChargingSchedulePeriod = append(res.ChargingSchedulePeriod, types.ChargingSchedulePeriod{
+			StartPeriod:  p.StartPeriod,
+			Limit:        p.Limit,
+			NumberPhases: p.NumberPhases,
+		})
+	}
+
+	return res
+}
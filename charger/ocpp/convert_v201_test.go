@@ -0,0 +1,57 @@
+package ocpp
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/lorenzodonini/ocpp-go/ocpp1.6/types"
+	"github.com/lorenzodonini/ocpp-go/ocpp2.0.1/smartcharging"
+	"github.com/lorenzodonini/ocpp-go/ocppj"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestPhaseToUseRequest201(t *testing.T) {
+	one, three := 1, 3
+
+	profile := &types.ChargingProfile{
+		ChargingProfileId:      1,
+		ChargingProfilePurpose: types.ChargingProfilePurposeTxDefaultProfile,
+		ChargingProfileKind:    types.ChargingProfileKindRelative,
+		ChargingSchedule: &types.ChargingSchedule{
+			ChargingRateUnit: types.ChargingRateUnitAmperes,
+			ChargingSchedulePeriod: []types.ChargingSchedulePeriod{
+				{StartPeriod: 0, Limit: 16, NumberPhases: &one},
+				{StartPeriod: 3600, Limit: 16, NumberPhases: &three},
+			},
+		},
+	}
+
+	req := phaseToUseRequest201(smartcharging.NewSetChargingProfileRequest(1, chargingProfile201(profile)), 2)
+	assert.Equal(t, smartcharging.SetChargingProfileFeatureName, req.GetFeatureName())
+	require.NoError(t, ocppj.Validate.Struct(req))
+
+	b, err := json.Marshal(req)
+	require.NoError(t, err)
+
+	var res struct {
+		EvseID          int `json:"evseId"`
+		ChargingProfile struct {
+			ID               int `json:"id"`
+			ChargingSchedule []struct {
+				ChargingSchedulePeriod []map[string]any `json:"chargingSchedulePeriod"`
+			} `json:"chargingSchedule"`
+		} `json:"chargingProfile"`
+	}
+	require.NoError(t, json.Unmarshal(b, &res))
+
+	assert.Equal(t, 1, res.EvseID)
+	assert.Equal(t, 1, res.ChargingProfile.ID)
+	require.Len(t, res.ChargingProfile.ChargingSchedule, 1)
+
+	// only single phase periods select the phase
+	periods := res.ChargingProfile.ChargingSchedule[0].ChargingSchedulePeriod
+	require.Len(t, periods, 2)
+	assert.Equal(t, map[string]any{"startPeriod": 0.0, "limit": 16.0, "numberPhases": 1.0, "phaseToUse": 2.0}, periods[0])
+	assert.Equal(t, map[string]any{"startPeriod": 3600.0, "limit": 16.0, "numberPhases": 3.0}, periods[1])
+}
@@ -1,11 +1,13 @@
 package ocpp
 
 import (
-	"fmt"
+	"context"
 	"sync"
+	"time"
 
 	"github.com/evcc-io/evcc/util"
 	"github.com/lorenzodonini/ocpp-go/ocpp1.6/core"
+	"github.com/lorenzodonini/ocpp-go/ocpp1.6/remotetrigger"
 	"github.com/lorenzodonini/ocpp-go/ocpp1.6/types"
 )
 
@@ -17,7 +19,8 @@ type CP struct {
 	onceConnect sync.Once
 	onceBoot    sync.Once
 
-	id string
+	id       string
+	protocol string
 
 	connected bool
 	connectC  chan struct{}
@@ -29,6 +32,7 @@ type CP struct {
 	ChargingRateUnit        types.ChargingRateUnitType
 	ChargingProfileId       int
 	StackLevel              int
+	MaxSchedulePeriods      int // periods per charging schedule, single period if unknown
 	NumberOfConnectors      int
 	IdTag                   string
 
@@ -36,7 +40,31 @@ type CP struct {
 	bootNotificationRequestC chan *core.BootNotificationRequest
 	BootNotificationResult   *core.BootNotificationRequest
 
+	provisioning    map[string]string // configuration keys applied on boot
+	provisionCancel context.CancelFunc
+
+	localAuthVersion int // local authorization list version sent to the charge point
+	localAuthCancel  context.CancelFunc
+
+	lastSeen          time.Time // last message received
+	connectedAt       time.Time // messages timestamped before have been queued while offline
+	lastHeartbeat     time.Time
+	heartbeatInterval time.Duration
+	timedOut          bool // no message received within heartbeat tolerance
+
+	skewSamples []time.Duration // recent offsets of message timestamps
+	skew        time.Duration   // charge point clock ahead of central system
+	skewWarned  bool
+
+	triggerUnsupported map[remotetrigger.MessageTrigger]bool // messages the charge point can't trigger
+	quirks             Quirks                                // firmware workarounds
+
+	status     *core.StatusNotificationRequest // charge point status reported for connector 0
 	connectors map[int]*Connector
+
+	inflightMu  sync.Mutex
+	inflight    map[string]*inflightRequest // requests retried until answered
+	retryPolicy retryPolicy
 }
 
 func NewChargePoint(log *util.Logger, id string) *CP {
@@ -44,7 +72,12 @@ func NewChargePoint(log *util.Logger, id string) *CP {
 		log: log,
 		id:  id,
 
-		connectors: make(map[int]*Connector),
+		heartbeatInterval: heartbeatInterval,
+
+		connectors:         make(map[int]*Connector),
+		triggerUnsupported: make(map[remotetrigger.MessageTrigger]bool),
+		inflight:           make(map[string]*inflightRequest),
+		retryPolicy:        defaultRetryPolicy,
 
 		connectC:                 make(chan struct{}, 1),
 		meterC:                   make(chan struct{}, 1),
@@ -55,16 +88,48 @@ func NewChargePoint(log *util.Logger, id string) *CP {
 	}
 }
 
-func (cp *CP) registerConnector(id int, conn *Connector) error {
+// registerConnector registers the connector and returns the connector it supersedes, if any.
+// Connectors are superseded when their charger is reconfigured and restored if the superseding
+// connector is released first.
+func (cp *CP) registerConnector(id int, conn *Connector) *Connector {
+	cp.mu.Lock()
+	defer cp.mu.Unlock()
+
+	prev := cp.connectors[id]
+	conn.prev = prev
+	cp.connectors[id] = conn
+
+	return prev
+}
+
+// releaseConnector deregisters the connector and returns the restored connector it superseded, if any.
+// Returns true if the charge point has no connectors left.
+func (cp *CP) releaseConnector(conn *Connector) (*Connector, bool) {
 	cp.mu.Lock()
 	defer cp.mu.Unlock()
 
-	if _, ok := cp.connectors[id]; ok {
-		return fmt.Errorf("connector already registered: %d", id)
+	var restored *Connector
+
+	if active := cp.connectors[conn.id]; active == conn {
+		restored = conn.prev
+		if restored != nil {
+			cp.connectors[conn.id] = restored
+		} else {
+			delete(cp.connectors, conn.id)
+		}
+	} else {
+		// unlink superseded connector
+		for c := active; c != nil; c = c.prev {
+			if c.prev == conn {
+				c.prev = conn.prev
+				break
+			}
+		}
 	}
 
-	cp.connectors[id] = conn
-	return nil
+	conn.prev = nil
+
+	return restored, len(cp.connectors) == 0
 }
 
 func (cp *CP) deregisterConnector(id int) {
@@ -74,6 +139,13 @@ func (cp *CP) deregisterConnector(id int) {
 	delete(cp.connectors, id)
 }
 
+func (cp *CP) hasConnectors() bool {
+	cp.mu.RLock()
+	defer cp.mu.RUnlock()
+
+	return len(cp.connectors) > 0
+}
+
 func (cp *CP) connectorByID(id int) *Connector {
 	cp.mu.RLock()
 	defer cp.mu.RUnlock()
@@ -112,17 +184,45 @@ func (cp *CP) RegisterID(id string) {
 	cp.id = id
 }
 
-func (cp *CP) connect(connect bool) {
+func (cp *CP) setProtocol(protocol string) {
 	cp.mu.Lock()
 	defer cp.mu.Unlock()
 
+	cp.protocol = protocol
+}
+
+// Protocol returns the negotiated protocol version
+func (cp *CP) Protocol() string {
+	cp.mu.RLock()
+	defer cp.mu.RUnlock()
+
+	return cp.protocol
+}
+
+func (cp *CP) connect(connect bool) {
+	cp.mu.Lock()
 	cp.connected = connect
+	cp.timedOut = false
 
 	if connect {
+		cp.lastSeen = time.Now()
+		cp.connectedAt = cp.lastSeen
+		cp.skewSamples, cp.skew = nil, 0
 		cp.onceConnect.Do(func() {
 			close(cp.connectC)
 		})
 	}
+	cp.mu.Unlock()
+
+	if !connect {
+		cp.failInflight()
+	}
+
+	cp.publishConnectivity(func(c *Connectivity) {
+		if !connect {
+			c.Disconnects++
+		}
+	})
 }
 
 func (cp *CP) Connected() bool {
@@ -2,6 +2,8 @@ package ocpp
 
 import (
 	"errors"
+	"maps"
+	"slices"
 
 	"github.com/lorenzodonini/ocpp-go/ocpp1.6/core"
 	"github.com/lorenzodonini/ocpp-go/ocpp1.6/types"
@@ -11,19 +13,52 @@ var (
 	ErrInvalidRequest     = errors.New("invalid request")
 	ErrInvalidConnector   = errors.New("invalid connector")
 	ErrInvalidTransaction = errors.New("invalid transaction")
+	ErrLimitDeviation     = errors.New("limit deviates from composite schedule")
 )
 
 func (cp *CP) OnBootNotification(request *core.BootNotificationRequest) (*core.BootNotificationConfirmation, error) {
 	res := &core.BootNotificationConfirmation{
 		CurrentTime: types.Now(),
-		Interval:    60,
+		Interval:    int(heartbeatInterval.Seconds()),
 		Status:      core.RegistrationStatusAccepted,
 	}
 
+	cp.mu.Lock()
+	cp.heartbeatInterval = heartbeatInterval
+	cp.mu.Unlock()
+
 	cp.onceBoot.Do(func() {
 		cp.bootNotificationRequestC <- request
 	})
 
+	cp.resetCompleted()
+
+	// charge point synchronizes its clock to the confirmation's current time
+	cp.resetClockSkew()
+
+	// firmware may have changed, capabilities are published once setup has completed
+	updateCapabilities(cp.ID(), func(c *Capabilities) bool {
+		if c.Updated.IsZero() {
+			return false
+		}
+		c.booted(request)
+		return true
+	})
+
+	// re-apply configuration and local authorization list after reboot or factory reset
+	cp.provision()
+	cp.syncLocalAuthList()
+
+	return res, nil
+}
+
+func (cp *CP) OnHeartbeat(request *core.HeartbeatRequest) (*core.HeartbeatConfirmation, error) {
+	cp.received(true)
+
+	res := &core.HeartbeatConfirmation{
+		CurrentTime: types.Now(),
+	}
+
 	return res, nil
 }
 
@@ -32,6 +67,11 @@ func (cp *CP) OnStatusNotification(request *core.StatusNotificationRequest) (*co
 		return nil, ErrInvalidRequest
 	}
 
+	// connector 0 reports the charge point as a whole, shared by all connectors
+	if request.ConnectorId == 0 {
+		cp.onStationStatus(request)
+	}
+
 	if conn := cp.connectorByID(request.ConnectorId); conn != nil {
 		return conn.OnStatusNotification(request)
 	}
@@ -39,6 +79,25 @@ func (cp *CP) OnStatusNotification(request *core.StatusNotificationRequest) (*co
 	return new(core.StatusNotificationConfirmation), nil
 }
 
+func (cp *CP) onStationStatus(request *core.StatusNotificationRequest) {
+	cp.mu.Lock()
+	prevFault := newFault(cp.status)
+	cp.status = request
+	cp.mu.Unlock()
+
+	if fault := newFault(request); fault != nil && (prevFault == nil || *fault != *prevFault) {
+		cp.log.WARN.Printf("charge point fault: %v", fault)
+	}
+}
+
+// fault returns the fault reported for the charge point as a whole or nil
+func (cp *CP) fault() *Fault {
+	cp.mu.RLock()
+	defer cp.mu.RUnlock()
+
+	return newFault(cp.status)
+}
+
 func (cp *CP) OnMeterValues(request *core.MeterValuesRequest) (*core.MeterValuesConfirmation, error) {
 	if request == nil {
 		return nil, ErrInvalidRequest
@@ -57,13 +116,42 @@ func (cp *CP) OnMeterValues(request *core.MeterValuesRequest) (*core.MeterValues
 	return new(core.MeterValuesConfirmation), nil
 }
 
+// OnAuthorize assigns the authorized id tag to the connector awaiting authorization.
+// Id tags are ignored if it is ambiguous which connector has been authorized.
+func (cp *CP) OnAuthorize(request *core.AuthorizeRequest) {
+	if request == nil {
+		return
+	}
+
+	cp.mu.RLock()
+	conns := slices.Collect(maps.Values(cp.connectors))
+	cp.mu.RUnlock()
+
+	if len(conns) > 1 {
+		conns = slices.DeleteFunc(conns, func(conn *Connector) bool {
+			return !conn.NeedsAuthentication()
+		})
+	}
+
+	if len(conns) != 1 {
+		cp.log.DEBUG.Printf("ignoring id tag for unknown connector: %s", request.IdTag)
+		return
+	}
+
+	conns[0].OnAuthorize(request)
+}
+
 func (cp *CP) OnStartTransaction(request *core.StartTransactionRequest) (*core.StartTransactionConfirmation, error) {
 	if request == nil {
 		return nil, ErrInvalidRequest
 	}
 
 	if conn := cp.connectorByID(request.ConnectorId); conn != nil {
-		return conn.OnStartTransaction(request)
+		res, err := conn.OnStartTransaction(request)
+		if err == nil {
+			publishTransactionEvent(conn.transactionEvent(TransactionStarted))
+		}
+		return res, err
 	}
 
 	res := &core.StartTransactionConfirmation{
@@ -81,9 +169,21 @@ func (cp *CP) OnStopTransaction(request *core.StopTransactionRequest) (*core.Sto
 	}
 
 	if conn := cp.connectorByTransactionID(request.TransactionId); conn != nil {
-		return conn.OnStopTransaction(request)
+		event := conn.transactionEvent(TransactionStopped)
+
+		res, err := conn.OnStopTransaction(request)
+		if err == nil {
+			event.stopped(request, conn.clock.Now())
+			// session happened entirely while offline
+			event.Replayed = event.Replayed && cp.isReplay(request.Timestamp)
+			conn.transactionStopped(event)
+		}
+		return res, err
 	}
 
+	// transaction stopped before the connector has been registered
+	finishJournaledTransaction(cp.ID(), request)
+
 	res := &core.StopTransactionConfirmation{
 		IdTagInfo: &types.IdTagInfo{
 			Status: types.AuthorizationStatusAccepted, // accept old pending stop message during startup
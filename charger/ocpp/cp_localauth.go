@@ -0,0 +1,185 @@
+package ocpp
+
+import (
+	"context"
+	"errors"
+	"hash/crc32"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/cenkalti/backoff/v4"
+	"github.com/lorenzodonini/ocpp-go/ocpp1.6/localauth"
+	"github.com/lorenzodonini/ocpp-go/ocpp1.6/types"
+)
+
+// maxIdTagLength is the maximum length of an OCPP 1.6 id tag
+const maxIdTagLength = 20
+
+var (
+	localAuthMu      sync.Mutex
+	localAuthList    []string
+	localAuthVersion int
+)
+
+// SetLocalAuthList sets the identifiers sent to charge points as local authorization list and
+// synchronises configured charge points. Placeholders and identifiers exceeding the id tag length are skipped.
+func SetLocalAuthList(ids []string) {
+	var list []string
+	for _, id := range ids {
+		if id == "" || strings.Contains(id, "*") || len(id) > maxIdTagLength || slices.Contains(list, id) {
+			continue
+		}
+		list = append(list, id)
+	}
+	slices.Sort(list)
+
+	localAuthMu.Lock()
+	localAuthList, localAuthVersion = list, listVersion(list)
+	localAuthMu.Unlock()
+
+	if !running.Load() {
+		return
+	}
+
+	for _, cp := range Instance().chargepoints() {
+		cp.syncLocalAuthList()
+	}
+}
+
+// listVersion derives a stable, positive list version from the identifiers
+func listVersion(list []string) int {
+	if len(list) == 0 {
+		return 0
+	}
+
+	return int(crc32.ChecksumIEEE([]byte(strings.Join(list, "\n")))&0x7fffffff) | 1
+}
+
+// LocalAuthListVersion returns the version of the local authorization list last sent to the charge point
+func (cp *CP) LocalAuthListVersion() int {
+	cp.mu.RLock()
+	defer cp.mu.RUnlock()
+
+	return cp.localAuthVersion
+}
+
+// syncLocalAuthList sends the local authorization list in background unless already up to date
+func (cp *CP) syncLocalAuthList() {
+	localAuthMu.Lock()
+	list, version := localAuthList, localAuthVersion
+	localAuthMu.Unlock()
+
+	// never clear lists not managed by evcc
+	if len(list) == 0 {
+		return
+	}
+
+	cp.mu.Lock()
+	defer cp.mu.Unlock()
+
+	if cp.localAuthCancel != nil {
+		cp.localAuthCancel()
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cp.localAuthCancel = cancel
+
+	go cp.applyLocalAuthList(ctx, list, version)
+}
+
+// applyLocalAuthList sends the full list if the charge point's list version differs, retrying until accepted
+func (cp *CP) applyLocalAuthList(ctx context.Context, list []string, version int) {
+	bo := backoff.NewExponentialBackOff(
+		backoff.WithInitialInterval(5*time.Second),
+		backoff.WithMaxInterval(5*time.Minute),
+		backoff.WithMaxElapsedTime(0),
+	)
+
+	if err := backoff.Retry(func() error {
+		current, err := cp.GetLocalListVersionRequest()
+		if err != nil {
+			if notSupported(err) {
+				return backoff.Permanent(err)
+			}
+			return err
+		}
+
+		if current != version {
+			err = cp.SendLocalListRequest(version, list)
+		}
+
+		switch {
+		case err == nil:
+			return nil
+		case notSupported(err):
+			return backoff.Permanent(err)
+		default:
+			cp.log.DEBUG.Printf("local auth list: %v", err)
+			return err
+		}
+	}, backoff.WithContext(bo, ctx)); err != nil {
+		if ctx.Err() == nil {
+			cp.log.WARN.Printf("failed sending local auth list: %v", err)
+		}
+		return
+	}
+
+	cp.mu.Lock()
+	cp.localAuthVersion = version
+	cp.mu.Unlock()
+
+	cp.log.DEBUG.Printf("local auth list version %d: %d identifiers", version, len(list))
+}
+
+// GetLocalListVersionRequest returns the charge point's local authorization list version
+func (cp *CP) GetLocalListVersionRequest() (int, error) {
+	if cp.Protocol() == ProtocolV201 {
+		return cp.getLocalListVersion201()
+	}
+
+	var res int
+	rc := make(chan error, 1)
+
+	err := Instance().GetLocalListVersion(cp.id, func(request *localauth.GetLocalListVersionConfirmation, err error) {
+		if err == nil && request != nil {
+			res = request.ListVersion
+		}
+
+		rc <- err
+	})
+
+	// -1 indicates missing local authorization list support
+	if err = wait(err, rc); err == nil && res < 0 {
+		err = errors.New(string(localauth.UpdateStatusNotSupported))
+	}
+
+	return res, err
+}
+
+// SendLocalListRequest replaces the charge point's local authorization list
+func (cp *CP) SendLocalListRequest(version int, ids []string) error {
+	if cp.Protocol() == ProtocolV201 {
+		return cp.sendLocalList201(version, ids)
+	}
+
+	rc := make(chan error, 1)
+
+	err := Instance().SendLocalList(cp.id, func(request *localauth.SendLocalListConfirmation, err error) {
+		if err == nil && request != nil && request.Status != localauth.UpdateStatusAccepted {
+			err = errors.New(string(request.Status))
+		}
+
+		rc <- err
+	}, version, localauth.UpdateTypeFull, func(request *localauth.SendLocalListRequest) {
+		for _, id := range ids {
+			request.LocalAuthorizationList = append(request.LocalAuthorizationList, localauth.AuthorizationData{
+				IdTag:     id,
+				IdTagInfo: types.NewIdTagInfo(types.AuthorizationStatusAccepted),
+			})
+		}
+	})
+
+	return wait(err, rc)
+}
@@ -0,0 +1,27 @@
+package ocpp
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestLocalAuthListVersion(t *testing.T) {
+	assert.Equal(t, 0, listVersion(nil))
+
+	v := listVersion([]string{"a", "b"})
+	assert.Positive(t, v)
+	assert.Equal(t, v, listVersion([]string{"a", "b"}))
+	assert.NotEqual(t, v, listVersion([]string{"a", "c"}))
+}
+
+func TestSetLocalAuthList(t *testing.T) {
+	SetLocalAuthList([]string{"b", "a", "b", "", "abc*", "012345678901234567890"})
+	defer SetLocalAuthList(nil)
+
+	localAuthMu.Lock()
+	defer localAuthMu.Unlock()
+
+	assert.Equal(t, []string{"a", "b"}, localAuthList)
+	assert.Equal(t, listVersion([]string{"a", "b"}), localAuthVersion)
+}
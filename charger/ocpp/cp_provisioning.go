@@ -0,0 +1,77 @@
+package ocpp
+
+import (
+	"context"
+	"maps"
+	"slices"
+	"time"
+
+	"github.com/cenkalti/backoff/v4"
+	"github.com/lorenzodonini/ocpp-go/ocpp1.6/core"
+)
+
+// SetProvisioning sets the configuration keys applied whenever the charge point boots and applies them immediately
+func (cp *CP) SetProvisioning(keys map[string]string) {
+	cp.mu.Lock()
+	cp.provisioning = maps.Clone(keys)
+	cp.mu.Unlock()
+
+	cp.provision()
+}
+
+// provision applies the provisioning keys in background, cancelling a still running provisioning
+func (cp *CP) provision() {
+	cp.mu.Lock()
+	defer cp.mu.Unlock()
+
+	if cp.provisionCancel != nil {
+		cp.provisionCancel()
+		cp.provisionCancel = nil
+	}
+
+	if len(cp.provisioning) == 0 {
+		return
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cp.provisionCancel = cancel
+
+	go cp.applyProvisioning(ctx, cp.provisioning)
+}
+
+// applyProvisioning changes the configuration keys, retrying until accepted
+func (cp *CP) applyProvisioning(ctx context.Context, keys map[string]string) {
+	for _, key := range slices.Sorted(maps.Keys(keys)) {
+		value := keys[key]
+
+		bo := backoff.NewExponentialBackOff(
+			backoff.WithInitialInterval(5*time.Second),
+			backoff.WithMaxInterval(5*time.Minute),
+			backoff.WithMaxElapsedTime(0),
+		)
+
+		if err := backoff.Retry(func() error {
+			err := cp.ChangeConfigurationRequest(key, value)
+
+			switch {
+			case err == nil:
+				return nil
+			case err.Error() == string(core.ConfigurationStatusRebootRequired):
+				cp.log.WARN.Printf("provisioning %s requires reboot", key)
+				return nil
+			case err.Error() == string(core.ConfigurationStatusNotSupported):
+				return backoff.Permanent(err)
+			default:
+				cp.log.DEBUG.Printf("provisioning %s: %v", key, err)
+				return err
+			}
+		}, backoff.WithContext(bo, ctx)); err != nil {
+			if ctx.Err() == nil {
+				cp.log.WARN.Printf("failed provisioning %s: %v", key, err)
+			}
+			continue
+		}
+
+		cp.log.DEBUG.Printf("provisioned %s: %s", key, value)
+	}
+}
@@ -1,15 +1,23 @@
 package ocpp
 
 import (
+	"encoding/json"
 	"errors"
+	"time"
 
 	"github.com/lorenzodonini/ocpp-go/ocpp1.6/core"
+	"github.com/lorenzodonini/ocpp-go/ocpp1.6/firmware"
 	"github.com/lorenzodonini/ocpp-go/ocpp1.6/remotetrigger"
+	"github.com/lorenzodonini/ocpp-go/ocpp1.6/reservation"
 	"github.com/lorenzodonini/ocpp-go/ocpp1.6/smartcharging"
 	"github.com/lorenzodonini/ocpp-go/ocpp1.6/types"
 )
 
 func (cp *CP) ChangeAvailabilityRequest(connectorId int, availabilityType core.AvailabilityType) error {
+	if cp.Protocol() == ProtocolV201 {
+		return cp.changeAvailability201(connectorId, availabilityType)
+	}
+
 	rc := make(chan error, 1)
 
 	err := Instance().ChangeAvailability(cp.id, func(request *core.ChangeAvailabilityConfirmation, err error) {
@@ -24,6 +32,10 @@ func (cp *CP) ChangeAvailabilityRequest(connectorId int, availabilityType core.A
 }
 
 func (cp *CP) GetCompositeScheduleRequest(connectorId int, duration int) (*smartcharging.GetCompositeScheduleConfirmation, error) {
+	if cp.Protocol() == ProtocolV201 {
+		return cp.getCompositeSchedule201(connectorId, duration)
+	}
+
 	var res *smartcharging.GetCompositeScheduleConfirmation
 	rc := make(chan error, 1)
 
@@ -40,7 +52,18 @@ func (cp *CP) GetCompositeScheduleRequest(connectorId int, duration int) (*smart
 	return res, wait(err, rc)
 }
 
+// RemoteStartTransactionRequest starts a transaction, retrying while the charge point does not answer
 func (cp *CP) RemoteStartTransactionRequest(connectorId int, idTag string) error {
+	return cp.retry(core.RemoteStartTransactionFeatureName, requestKey(core.RemoteStartTransactionFeatureName, connectorId, idTag), func() error {
+		return cp.remoteStartTransaction(connectorId, idTag)
+	})
+}
+
+func (cp *CP) remoteStartTransaction(connectorId int, idTag string) error {
+	if cp.Protocol() == ProtocolV201 {
+		return cp.requestStartTransaction201(connectorId, idTag)
+	}
+
 	rc := make(chan error, 1)
 	err := Instance().RemoteStartTransaction(cp.id, func(request *core.RemoteStartTransactionConfirmation, err error) {
 		if err == nil && request != nil && request.Status != types.RemoteStartStopStatusAccepted {
@@ -57,7 +80,83 @@ func (cp *CP) RemoteStartTransactionRequest(connectorId int, idTag string) error
 	return wait(err, rc)
 }
 
+// RemoteStopTransactionRequest stops the transaction, retrying while the charge point does not answer
+func (cp *CP) RemoteStopTransactionRequest(connectorId, transactionId int) error {
+	return cp.retry(core.RemoteStopTransactionFeatureName, requestKey(core.RemoteStopTransactionFeatureName, connectorId, transactionId), func() error {
+		return cp.remoteStopTransaction(connectorId, transactionId)
+	})
+}
+
+func (cp *CP) remoteStopTransaction(connectorId, transactionId int) error {
+	if cp.Protocol() == ProtocolV201 {
+		return cp.requestStopTransaction201(connectorId)
+	}
+
+	rc := make(chan error, 1)
+	err := Instance().RemoteStopTransaction(cp.id, func(request *core.RemoteStopTransactionConfirmation, err error) {
+		if err == nil && request != nil && request.Status != types.RemoteStartStopStatusAccepted {
+			err = errors.New(string(request.Status))
+		}
+
+		rc <- err
+	}, transactionId)
+
+	return wait(err, rc)
+}
+
+func (cp *CP) UnlockConnectorRequest(connectorId int) error {
+	if cp.Protocol() == ProtocolV201 {
+		return cp.unlockConnector201(connectorId)
+	}
+
+	rc := make(chan error, 1)
+
+	err := Instance().UnlockConnector(cp.id, func(request *core.UnlockConnectorConfirmation, err error) {
+		if err == nil && request != nil && request.Status != core.UnlockStatusUnlocked {
+			err = errors.New(string(request.Status))
+		}
+
+		rc <- err
+	}, connectorId)
+
+	return wait(err, rc)
+}
+
+func (cp *CP) ResetRequest(resetType core.ResetType) error {
+	if cp.Protocol() == ProtocolV201 {
+		return cp.reset201(resetType)
+	}
+
+	rc := make(chan error, 1)
+
+	err := Instance().Reset(cp.id, func(request *core.ResetConfirmation, err error) {
+		if err == nil && request != nil && request.Status != core.ResetStatusAccepted {
+			err = errors.New(string(request.Status))
+		}
+
+		rc <- err
+	}, resetType)
+
+	return wait(err, rc)
+}
+
+// SetChargingProfileRequest sets the charging profile, retrying while the charge point does not answer
 func (cp *CP) SetChargingProfileRequest(connectorId int, profile *types.ChargingProfile) error {
+	return cp.setChargingProfileRequest(connectorId, profile, 0)
+}
+
+// setChargingProfileRequest sets the charging profile using the given phase for periods limited to a single phase
+func (cp *CP) setChargingProfileRequest(connectorId int, profile *types.ChargingProfile, phaseToUse int) error {
+	return cp.retry(smartcharging.SetChargingProfileFeatureName, requestKey(smartcharging.SetChargingProfileFeatureName, connectorId, profile, phaseToUse), func() error {
+		return cp.setChargingProfile(connectorId, profile, phaseToUse)
+	})
+}
+
+func (cp *CP) setChargingProfile(connectorId int, profile *types.ChargingProfile, phaseToUse int) error {
+	if cp.Protocol() == ProtocolV201 {
+		return cp.setChargingProfile201(connectorId, profile, phaseToUse)
+	}
+
 	rc := make(chan error, 1)
 
 	err := Instance().SetChargingProfile(cp.id, func(request *smartcharging.SetChargingProfileConfirmation, err error) {
@@ -72,6 +171,10 @@ func (cp *CP) SetChargingProfileRequest(connectorId int, profile *types.Charging
 }
 
 func (cp *CP) TriggerMessageRequest(connectorId int, requestedMessage remotetrigger.MessageTrigger) error {
+	if cp.Protocol() == ProtocolV201 {
+		return cp.triggerMessage201(connectorId, requestedMessage)
+	}
+
 	rc := make(chan error, 1)
 
 	err := Instance().TriggerMessage(cp.id, func(request *remotetrigger.TriggerMessageConfirmation, err error) {
@@ -89,7 +192,18 @@ func (cp *CP) TriggerMessageRequest(connectorId int, requestedMessage remotetrig
 	return wait(err, rc)
 }
 
+// ChangeConfigurationRequest changes the configuration key, retrying while the charge point does not answer
 func (cp *CP) ChangeConfigurationRequest(key, value string) error {
+	return cp.retry(core.ChangeConfigurationFeatureName, requestKey(core.ChangeConfigurationFeatureName, 0, key, value), func() error {
+		return cp.changeConfiguration(key, value)
+	})
+}
+
+func (cp *CP) changeConfiguration(key, value string) error {
+	if cp.Protocol() == ProtocolV201 {
+		return cp.setVariables201(key, value)
+	}
+
 	rc := make(chan error, 1)
 
 	err := Instance().ChangeConfiguration(cp.id, func(request *core.ChangeConfigurationConfirmation, err error) {
@@ -103,7 +217,12 @@ func (cp *CP) ChangeConfigurationRequest(key, value string) error {
 	return wait(err, rc)
 }
 
-func (cp *CP) GetConfigurationRequest() (*core.GetConfigurationConfirmation, error) {
+// GetConfigurationRequest returns the given configuration keys or all keys if none given
+func (cp *CP) GetConfigurationRequest(keys ...string) (*core.GetConfigurationConfirmation, error) {
+	if cp.Protocol() == ProtocolV201 {
+		return cp.getVariables201(keys...)
+	}
+
 	rc := make(chan error, 1)
 
 	var res *core.GetConfigurationConfirmation
@@ -111,7 +230,166 @@ func (cp *CP) GetConfigurationRequest() (*core.GetConfigurationConfirmation, err
 		res = request
 
 		rc <- err
-	}, nil)
+	}, keys)
 
 	return res, wait(err, rc)
 }
+
+// DataTransferRequest sends a vendor specific message. The response data is returned as json.
+func (cp *CP) DataTransferRequest(vendorId, messageId string, data any) (json.RawMessage, error) {
+	if cp.Protocol() == ProtocolV201 {
+		return cp.dataTransfer201(vendorId, messageId, data)
+	}
+
+	var res json.RawMessage
+	rc := make(chan error, 1)
+
+	err := Instance().DataTransfer(cp.id, func(request *core.DataTransferConfirmation, err error) {
+		if err == nil && request != nil && request.Status != core.DataTransferStatusAccepted {
+			err = errors.New(string(request.Status))
+		}
+
+		if err == nil && request != nil && request.Data != nil {
+			res, err = json.Marshal(request.Data)
+		}
+
+		rc <- err
+	}, vendorId, func(request *core.DataTransferRequest) {
+		request.MessageId = messageId
+		request.Data = data
+	})
+
+	return res, wait(err, rc)
+}
+
+// DataTransfer sends a vendor specific message and decodes the response data.
+// Data sent as json encoded string is decoded from the string's content.
+func DataTransfer[T any](cp *CP, vendorId, messageId string, data any) (T, error) {
+	var res T
+
+	b, err := cp.DataTransferRequest(vendorId, messageId, data)
+	if err != nil || len(b) == 0 {
+		return res, err
+	}
+
+	if err := json.Unmarshal(b, &res); err != nil {
+		var s string
+		if json.Unmarshal(b, &s) != nil {
+			return res, err
+		}
+
+		return res, json.Unmarshal([]byte(s), &res)
+	}
+
+	return res, nil
+}
+
+// UpdateFirmwareRequest instructs the charge point to install the firmware downloaded from location after retrieveDate.
+// Zero retries or retry interval leave the download retries to the charge point.
+func (cp *CP) UpdateFirmwareRequest(location string, retrieveDate time.Time, retries int, retryInterval time.Duration) error {
+	var err error
+	if cp.Protocol() == ProtocolV201 {
+		err = cp.updateFirmware201(location, retrieveDate, retries, retryInterval)
+	} else {
+		rc := make(chan error, 1)
+
+		err = Instance().UpdateFirmware(cp.id, func(request *firmware.UpdateFirmwareConfirmation, err error) {
+			rc <- err
+		}, location, types.NewDateTime(retrieveDate), func(request *firmware.UpdateFirmwareRequest) {
+			if retries > 0 {
+				request.Retries = &retries
+			}
+			if interval := int(retryInterval.Seconds()); interval > 0 {
+				request.RetryInterval = &interval
+			}
+		})
+
+		err = wait(err, rc)
+	}
+
+	if err == nil {
+		updateFirmwareStatus(cp.id, "Requested", location)
+	}
+
+	return err
+}
+
+// GetDiagnosticsRequest instructs the charge point to upload its diagnostics of the given period to location.
+// It returns the name of the file to be uploaded if known.
+func (cp *CP) GetDiagnosticsRequest(location string, start, stop time.Time, retries int, retryInterval time.Duration) (string, error) {
+	if cp.Protocol() == ProtocolV201 {
+		file, err := cp.getLog201(location, start, stop, retries, retryInterval)
+		if err == nil {
+			updateDiagnosticsStatus(cp.id, "Requested", file)
+		}
+		return file, err
+	}
+
+	var file string
+	rc := make(chan error, 1)
+
+	err := Instance().GetDiagnostics(cp.id, func(request *firmware.GetDiagnosticsConfirmation, err error) {
+		if err == nil && request != nil {
+			file = request.FileName
+		}
+
+		rc <- err
+	}, location, func(request *firmware.GetDiagnosticsRequest) {
+		if !start.IsZero() {
+			request.StartTime = types.NewDateTime(start)
+		}
+		if !stop.IsZero() {
+			request.StopTime = types.NewDateTime(stop)
+		}
+		if retries > 0 {
+			request.Retries = &retries
+		}
+		if interval := int(retryInterval.Seconds()); interval > 0 {
+			request.RetryInterval = &interval
+		}
+	})
+
+	if err = wait(err, rc); err == nil {
+		updateDiagnosticsStatus(cp.id, "Requested", file)
+	}
+
+	return file, err
+}
+
+// ReserveNowRequest reserves the connector for the id tag until expiry. An existing reservation with the same id is replaced.
+func (cp *CP) ReserveNowRequest(connectorId int, expiry time.Time, idTag string, reservationId int) error {
+	if cp.Protocol() == ProtocolV201 {
+		return cp.reserveNow201(connectorId, expiry, idTag, reservationId)
+	}
+
+	rc := make(chan error, 1)
+
+	err := Instance().ReserveNow(cp.id, func(request *reservation.ReserveNowConfirmation, err error) {
+		if err == nil && request != nil && request.Status != reservation.ReservationStatusAccepted {
+			err = errors.New(string(request.Status))
+		}
+
+		rc <- err
+	}, connectorId, types.NewDateTime(expiry), idTag, reservationId)
+
+	return wait(err, rc)
+}
+
+// CancelReservationRequest cancels the reservation
+func (cp *CP) CancelReservationRequest(reservationId int) error {
+	if cp.Protocol() == ProtocolV201 {
+		return cp.cancelReservation201(reservationId)
+	}
+
+	rc := make(chan error, 1)
+
+	err := Instance().CancelReservation(cp.id, func(request *reservation.CancelReservationConfirmation, err error) {
+		if err == nil && request != nil && request.Status != reservation.CancelReservationStatusAccepted {
+			err = errors.New(string(request.Status))
+		}
+
+		rc <- err
+	}, reservationId)
+
+	return wait(err, rc)
+}
@@ -8,8 +8,8 @@ import (
 
 	"github.com/lorenzodonini/ocpp-go/ocpp1.6/core"
 	"github.com/lorenzodonini/ocpp-go/ocpp1.6/remotetrigger"
-	"github.com/lorenzodonini/ocpp-go/ocpp1.6/smartcharging"
 	"github.com/lorenzodonini/ocpp-go/ocpp1.6/types"
+	"github.com/lorenzodonini/ocpp-go/ocpp2.0.1/remotecontrol"
 	"github.com/samber/lo"
 )
 
@@ -34,8 +34,10 @@ func (cp *CP) Setup(ctx context.Context, meterValues string, meterInterval time.
 		return err
 	}
 
+	caps := newCapabilities()
+
 	for _, opt := range resp.ConfigurationKey {
-		if opt.Value == nil {
+		if opt.Value == nil || caps.parse(opt) {
 			continue
 		}
 
@@ -44,26 +46,12 @@ func (cp *CP) Setup(ctx context.Context, meterValues string, meterInterval time.
 		}
 
 		switch {
-		case match(KeyChargeProfileMaxStackLevel):
-			if val, err := strconv.Atoi(*opt.Value); err == nil {
-				cp.StackLevel = val
-			}
-
-		case match(KeyChargingScheduleAllowedChargingRateUnit):
-			if *opt.Value == "Power" || *opt.Value == "W" { // "W" is not allowed by spec but used by some CPs
-				cp.ChargingRateUnit = types.ChargingRateUnitWatts
-				cp.PhaseSwitching = true // assume phase switching is available for power-based charging
-			}
-
-		case match(KeyConnectorSwitch3to1PhaseSupported) || match(KeyChargeAmpsPhaseSwitchingSupported):
-			var val bool
-			if val, err = strconv.ParseBool(*opt.Value); err == nil {
-				cp.PhaseSwitching = val
-			}
-
-		case match(KeyMaxChargingProfilesInstalled):
-			if val, err := strconv.Atoi(*opt.Value); err == nil {
-				cp.ChargingProfileId = val
+		// heartbeat interval until the next boot notification
+		case match(KeyHeartbeatInterval):
+			if val, err := strconv.Atoi(*opt.Value); err == nil && val > 0 {
+				cp.mu.Lock()
+				cp.heartbeatInterval = time.Duration(val) * time.Second
+				cp.mu.Unlock()
 			}
 
 		case match(KeyMeterValuesSampledData):
@@ -79,20 +67,6 @@ func (cp *CP) Setup(ctx context.Context, meterValues string, meterInterval time.
 				meterValuesSampledDataMaxLength = val
 			}
 
-		case match(KeyNumberOfConnectors):
-			if val, err := strconv.Atoi(*opt.Value); err == nil {
-				cp.NumberOfConnectors = val
-			}
-
-		case match(KeySupportedFeatureProfiles):
-			if !hasProperty(*opt.Value, smartcharging.ProfileName) {
-				cp.log.WARN.Printf("the required SmartCharging feature profile is not indicated as supported")
-			}
-			// correct the availability assumption of RemoteTrigger only in case of a valid looking FeatureProfile list
-			if hasProperty(*opt.Value, core.ProfileName) {
-				cp.HasRemoteTriggerFeature = hasProperty(*opt.Value, remotetrigger.ProfileName)
-			}
-
 		// vendor-specific keys
 		case match(KeyAlfenPlugAndChargeIdentifier):
 			cp.IdTag = *opt.Value
@@ -105,6 +79,12 @@ func (cp *CP) Setup(ctx context.Context, meterValues string, meterInterval time.
 		}
 	}
 
+	if !caps.SmartCharging {
+		cp.log.WARN.Printf("the required SmartCharging feature profile is not indicated as supported")
+	}
+
+	cp.applyCapabilities(caps)
+
 	// see who's there
 	if cp.HasRemoteTriggerFeature {
 		if err := cp.TriggerMessageRequest(0, core.BootNotificationFeatureName); err != nil {
@@ -116,6 +96,14 @@ func (cp *CP) Setup(ctx context.Context, meterValues string, meterInterval time.
 			cp.log.DEBUG.Printf("BootNotification timeout")
 		case res := <-cp.bootNotificationRequestC:
 			cp.BootNotificationResult = res
+			caps.booted(res)
+		}
+	}
+
+	// ocpp 2.0.1 reports the charging state of running transactions with transaction events only
+	if cp.Protocol() == ProtocolV201 {
+		if err := cp.TriggerMessageRequest(0, remotetrigger.MessageTrigger(remotecontrol.MessageTriggerTransactionEvent)); err != nil {
+			cp.log.DEBUG.Printf("failed triggering TransactionEvent: %v", err)
 		}
 	}
 
@@ -160,10 +148,21 @@ func (cp *CP) Setup(ctx context.Context, meterValues string, meterInterval time.
 	}
 
 	if forcePowerCtrl {
-		cp.ChargingRateUnit = types.ChargingRateUnitWatts
-		cp.PhaseSwitching = true // assume phase switching is available for power-based charging
+		caps.ChargingRateUnit = types.ChargingRateUnitWatts
+		caps.PhaseSwitching = true // assume phase switching is available for power-based charging
+	}
+
+	if cp.meterValuesSample != "" {
+		caps.Measurands = strings.Split(cp.meterValuesSample, ",")
 	}
 
+	if caps.Quirks = detectQuirks(caps); caps.Quirks != (Quirks{}) {
+		cp.log.INFO.Printf("detected firmware quirks: %s", caps.Quirks)
+	}
+
+	cp.applyCapabilities(caps)
+	cp.publishCapabilities(caps)
+
 	return nil
 }
 
@@ -0,0 +1,499 @@
+package ocpp
+
+import (
+	"encoding/json"
+	"errors"
+	"maps"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/lorenzodonini/ocpp-go/ocpp"
+	"github.com/lorenzodonini/ocpp-go/ocpp1.6/core"
+	"github.com/lorenzodonini/ocpp-go/ocpp1.6/remotetrigger"
+	"github.com/lorenzodonini/ocpp-go/ocpp1.6/smartcharging"
+	"github.com/lorenzodonini/ocpp-go/ocpp1.6/types"
+	"github.com/lorenzodonini/ocpp-go/ocpp2.0.1/availability"
+	"github.com/lorenzodonini/ocpp-go/ocpp2.0.1/data"
+	"github.com/lorenzodonini/ocpp-go/ocpp2.0.1/diagnostics"
+	"github.com/lorenzodonini/ocpp-go/ocpp2.0.1/firmware"
+	"github.com/lorenzodonini/ocpp-go/ocpp2.0.1/localauth"
+	"github.com/lorenzodonini/ocpp-go/ocpp2.0.1/provisioning"
+	"github.com/lorenzodonini/ocpp-go/ocpp2.0.1/remotecontrol"
+	"github.com/lorenzodonini/ocpp-go/ocpp2.0.1/reservation"
+	smartcharging201 "github.com/lorenzodonini/ocpp-go/ocpp2.0.1/smartcharging"
+	types201 "github.com/lorenzodonini/ocpp-go/ocpp2.0.1/types"
+)
+
+// variables maps 1.6 configuration keys to OCPP 2.0.1 device model variables.
+// Values are written to all variables and read from the first one.
+var variables = map[string][]types201.ComponentVariable{
+	KeyMeterValuesSampledData: {
+		variable("SampledDataCtrlr", "TxUpdatedMeasurands"),
+		variable("AlignedDataCtrlr", "Measurands"),
+	},
+	KeyMeterValueSampleInterval: {
+		variable("SampledDataCtrlr", "TxUpdatedInterval"),
+		variable("AlignedDataCtrlr", "Interval"),
+	},
+	KeyHeartbeatInterval:                       {variable("OCPPCommCtrlr", "HeartbeatInterval")},
+	KeyWebSocketPingInterval:                   {variable("OCPPCommCtrlr", "WebSocketPingInterval")},
+	KeyChargeProfileMaxStackLevel:              {variable("SmartChargingCtrlr", "ProfileStackLevel")},
+	KeyChargingScheduleAllowedChargingRateUnit: {variable("SmartChargingCtrlr", "RateUnit")},
+	KeyChargingScheduleMaxPeriods:              {variable("SmartChargingCtrlr", "PeriodsPerSchedule")},
+	KeyConnectorSwitch3to1PhaseSupported:       {variable("SmartChargingCtrlr", "Phases3to1")},
+}
+
+func variable(component, name string) types201.ComponentVariable {
+	return types201.ComponentVariable{
+		Component: types201.Component{Name: component},
+		Variable:  types201.Variable{Name: name},
+	}
+}
+
+func (cp *CP) changeAvailability201(connectorId int, availabilityType core.AvailabilityType) error {
+	rc := make(chan error, 1)
+
+	err := Instance().csms.ChangeAvailability(cp.id, func(request *availability.ChangeAvailabilityResponse, err error) {
+		if err == nil && request != nil && request.Status != availability.ChangeAvailabilityStatusAccepted && request.Status != availability.ChangeAvailabilityStatusScheduled {
+			err = errors.New(string(request.Status))
+		}
+
+		rc <- err
+	}, availability.OperationalStatus(availabilityType), func(request *availability.ChangeAvailabilityRequest) {
+		if connectorId > 0 {
+			request.Evse = &types201.EVSE{ID: connectorId}
+		}
+	})
+
+	return wait(err, rc)
+}
+
+func (cp *CP) getCompositeSchedule201(connectorId int, duration int) (*smartcharging.GetCompositeScheduleConfirmation, error) {
+	var res *smartcharging.GetCompositeScheduleConfirmation
+	rc := make(chan error, 1)
+
+	err := Instance().csms.GetCompositeSchedule(cp.id, func(request *smartcharging201.GetCompositeScheduleResponse, err error) {
+		if err == nil && request != nil && request.Status != smartcharging201.GetCompositeScheduleStatusAccepted {
+			err = errors.New(string(request.Status))
+		}
+
+		if request != nil {
+			res = &smartcharging.GetCompositeScheduleConfirmation{
+				Status:      smartcharging.GetCompositeScheduleStatus(request.Status),
+				ConnectorId: &request.EvseID,
+			}
+
+			if s := request.Schedule; s != nil {
+				if s.StartDateTime != nil {
+					res.ScheduleStart = types.NewDateTime(s.StartDateTime.Time)
+				}
+				res.ChargingSchedule = chargingSchedule16(s.ChargingSchedule)
+			}
+		}
+
+		rc <- err
+	}, duration, connectorId)
+
+	return res, wait(err, rc)
+}
+
+func (cp *CP) requestStartTransaction201(connectorId int, idTag string) error {
+	rc := make(chan error, 1)
+
+	idToken := types201.IdToken{
+		IdToken: idTag,
+		Type:    types201.IdTokenTypeCentral,
+	}
+
+	err := Instance().csms.RequestStartTransaction(cp.id, func(request *remotecontrol.RequestStartTransactionResponse, err error) {
+		if err == nil && request != nil && request.Status != remotecontrol.RequestStartStopStatusAccepted {
+			err = errors.New(string(request.Status))
+		}
+
+		rc <- err
+	}, int(Instance().txnId.Add(1)), idToken, func(request *remotecontrol.RequestStartTransactionRequest) {
+		if connectorId > 0 {
+			request.EvseID = &connectorId
+		}
+	})
+
+	return wait(err, rc)
+}
+
+// requestStopTransaction201 stops the evse's running transaction
+func (cp *CP) requestStopTransaction201(evseId int) error {
+	var txn string
+	if reg := Instance().registration(cp.id); reg != nil {
+		reg.mu.RLock()
+		if e, ok := reg.evses[evseId]; ok {
+			txn = e.txn
+		}
+		reg.mu.RUnlock()
+	}
+
+	if txn == "" {
+		return ErrInvalidTransaction
+	}
+
+	rc := make(chan error, 1)
+
+	err := Instance().csms.RequestStopTransaction(cp.id, func(request *remotecontrol.RequestStopTransactionResponse, err error) {
+		if err == nil && request != nil && request.Status != remotecontrol.RequestStartStopStatusAccepted {
+			err = errors.New(string(request.Status))
+		}
+
+		rc <- err
+	}, txn)
+
+	return wait(err, rc)
+}
+
+// reset201 resets the charging station, soft resets wait for running transactions to finish
+func (cp *CP) reset201(resetType core.ResetType) error {
+	typ := provisioning.ResetTypeImmediate
+	if resetType == core.ResetTypeSoft {
+		typ = provisioning.ResetTypeOnIdle
+	}
+
+	rc := make(chan error, 1)
+
+	err := Instance().csms.Reset(cp.id, func(request *provisioning.ResetResponse, err error) {
+		if err == nil && request != nil && request.Status != provisioning.ResetStatusAccepted && request.Status != provisioning.ResetStatusScheduled {
+			err = errors.New(string(request.Status))
+		}
+
+		rc <- err
+	}, typ)
+
+	return wait(err, rc)
+}
+
+// unlockConnector201 unlocks the evse's connector, evses are assumed to have a single connector
+func (cp *CP) unlockConnector201(evseId int) error {
+	rc := make(chan error, 1)
+
+	err := Instance().csms.UnlockConnector(cp.id, func(request *remotecontrol.UnlockConnectorResponse, err error) {
+		if err == nil && request != nil && request.Status != remotecontrol.UnlockStatusUnlocked {
+			err = errors.New(string(request.Status))
+		}
+
+		rc <- err
+	}, evseId, 1)
+
+	return wait(err, rc)
+}
+
+func (cp *CP) setChargingProfile201(connectorId int, profile *types.ChargingProfile, phaseToUse int) error {
+	res := chargingProfile201(profile)
+
+	// tx profiles refer to the transaction by its 2.0.1 id
+	if profile.TransactionId != 0 {
+		if reg := Instance().registration(cp.id); reg != nil {
+			reg.mu.RLock()
+			for id, txn := range reg.txns {
+				if txn.id == profile.TransactionId {
+					res.TransactionID = id
+				}
+			}
+			reg.mu.RUnlock()
+		}
+
+		if res.TransactionID == "" {
+			return ErrInvalidTransaction
+		}
+	}
+
+	req := smartcharging201.NewSetChargingProfileRequest(connectorId, res)
+
+	var request ocpp.Request = req
+	if phaseToUse > 0 {
+		request = phaseToUseRequest201(req, phaseToUse)
+	}
+
+	rc := make(chan error, 1)
+
+	err := Instance().csms.SendRequestAsync(cp.id, request, func(response ocpp.Response, err error) {
+		if res, ok := response.(*smartcharging201.SetChargingProfileResponse); err == nil && ok && res.Status != smartcharging201.ChargingProfileStatusAccepted {
+			err = errors.New(string(res.Status))
+		}
+
+		rc <- err
+	})
+
+	return wait(err, rc)
+}
+
+func (cp *CP) triggerMessage201(connectorId int, requestedMessage remotetrigger.MessageTrigger) error {
+	rc := make(chan error, 1)
+
+	err := Instance().csms.TriggerMessage(cp.id, func(request *remotecontrol.TriggerMessageResponse, err error) {
+		if err == nil && request != nil && request.Status != remotecontrol.TriggerMessageStatusAccepted {
+			err = errors.New(string(request.Status))
+		}
+
+		rc <- err
+	}, remotecontrol.MessageTrigger(requestedMessage), func(request *remotecontrol.TriggerMessageRequest) {
+		if connectorId > 0 {
+			request.Evse = &types201.EVSE{ID: connectorId}
+
+			// status notifications are sent per connector
+			if request.RequestedMessage == remotecontrol.MessageTriggerStatusNotification {
+				connector := 1
+				request.Evse.ConnectorID = &connector
+			}
+		}
+	})
+
+	return wait(err, rc)
+}
+
+func (cp *CP) setVariables201(key, value string) error {
+	vars, ok := variables[key]
+	if !ok {
+		return errors.New(string(core.ConfigurationStatusNotSupported))
+	}
+
+	data := make([]provisioning.SetVariableData, 0, len(vars))
+	for _, v := range vars {
+		data = append(data, provisioning.SetVariableData{
+			AttributeValue: value,
+			Component:      v.Component,
+			Variable:       v.Variable,
+		})
+	}
+
+	rc := make(chan error, 1)
+
+	err := Instance().csms.SetVariables(cp.id, func(request *provisioning.SetVariablesResponse, err error) {
+		// only the first variable is required
+		if err == nil && request != nil && len(request.SetVariableResult) > 0 && request.SetVariableResult[0].AttributeStatus != provisioning.SetVariableStatusAccepted {
+			err = errors.New(string(request.SetVariableResult[0].AttributeStatus))
+		}
+
+		rc <- err
+	}, data)
+
+	return wait(err, rc)
+}
+
+func (cp *CP) getVariables201(requested ...string) (*core.GetConfigurationConfirmation, error) {
+	keys := slices.Sorted(maps.Keys(variables))
+
+	var unknown []string
+	if len(requested) > 0 {
+		keys = keys[:0]
+		for _, key := range requested {
+			if _, ok := variables[key]; ok {
+				keys = append(keys, key)
+			} else {
+				unknown = append(unknown, key)
+			}
+		}
+
+		if len(keys) == 0 {
+			return &core.GetConfigurationConfirmation{UnknownKey: unknown}, nil
+		}
+	}
+
+	data := make([]provisioning.GetVariableData, 0, len(keys))
+	for _, key := range keys {
+		v := variables[key][0]
+		data = append(data, provisioning.GetVariableData{
+			Component: v.Component,
+			Variable:  v.Variable,
+		})
+	}
+
+	var res *core.GetConfigurationConfirmation
+	rc := make(chan error, 1)
+
+	err := Instance().csms.GetVariables(cp.id, func(request *provisioning.GetVariablesResponse, err error) {
+		if request != nil {
+			res = &core.GetConfigurationConfirmation{UnknownKey: unknown}
+
+			for _, r := range request.GetVariableResult {
+				idx := slices.IndexFunc(data, func(d provisioning.GetVariableData) bool {
+					return strings.EqualFold(d.Component.Name, r.Component.Name) && strings.EqualFold(d.Variable.Name, r.Variable.Name)
+				})
+				if idx < 0 {
+					continue
+				}
+
+				if r.AttributeStatus != provisioning.GetVariableStatusAccepted {
+					res.UnknownKey = append(res.UnknownKey, keys[idx])
+					continue
+				}
+
+				res.ConfigurationKey = append(res.ConfigurationKey, core.ConfigurationKey{
+					Key:   keys[idx],
+					Value: &r.AttributeValue,
+				})
+			}
+		}
+
+		rc <- err
+	}, data)
+
+	return res, wait(err, rc)
+}
+
+func (cp *CP) dataTransfer201(vendorId, messageId string, payload any) (json.RawMessage, error) {
+	var res json.RawMessage
+	rc := make(chan error, 1)
+
+	err := Instance().csms.DataTransfer(cp.id, func(request *data.DataTransferResponse, err error) {
+		if err == nil && request != nil && request.Status != data.DataTransferStatusAccepted {
+			err = errors.New(string(request.Status))
+		}
+
+		if err == nil && request != nil && request.Data != nil {
+			res, err = json.Marshal(request.Data)
+		}
+
+		rc <- err
+	}, vendorId, func(request *data.DataTransferRequest) {
+		request.MessageID = messageId
+		request.Data = payload
+	})
+
+	return res, wait(err, rc)
+}
+
+func (cp *CP) updateFirmware201(location string, retrieveDate time.Time, retries int, retryInterval time.Duration) error {
+	rc := make(chan error, 1)
+
+	err := Instance().csms.UpdateFirmware(cp.id, func(request *firmware.UpdateFirmwareResponse, err error) {
+		if err == nil && request != nil && request.Status != firmware.UpdateFirmwareStatusAccepted && request.Status != firmware.UpdateFirmwareStatusAcceptedCanceled {
+			err = errors.New(string(request.Status))
+		}
+
+		rc <- err
+	}, int(Instance().txnId.Add(1)), firmware.Firmware{
+		Location:         location,
+		RetrieveDateTime: types201.NewDateTime(retrieveDate),
+	}, func(request *firmware.UpdateFirmwareRequest) {
+		if retries > 0 {
+			request.Retries = &retries
+		}
+		if interval := int(retryInterval.Seconds()); interval > 0 {
+			request.RetryInterval = &interval
+		}
+	})
+
+	return wait(err, rc)
+}
+
+func (cp *CP) getLog201(location string, start, stop time.Time, retries int, retryInterval time.Duration) (string, error) {
+	var file string
+	rc := make(chan error, 1)
+
+	params := diagnostics.LogParameters{
+		RemoteLocation: location,
+	}
+	if !start.IsZero() {
+		params.OldestTimestamp = types201.NewDateTime(start)
+	}
+	if !stop.IsZero() {
+		params.LatestTimestamp = types201.NewDateTime(stop)
+	}
+
+	err := Instance().csms.GetLog(cp.id, func(request *diagnostics.GetLogResponse, err error) {
+		if err == nil && request != nil && request.Status != diagnostics.LogStatusAccepted && request.Status != diagnostics.LogStatusAcceptedCanceled {
+			err = errors.New(string(request.Status))
+		}
+
+		if err == nil && request != nil {
+			file = request.Filename
+		}
+
+		rc <- err
+	}, diagnostics.LogTypeDiagnostics, int(Instance().txnId.Add(1)), params, func(request *diagnostics.GetLogRequest) {
+		if retries > 0 {
+			request.Retries = &retries
+		}
+		if interval := int(retryInterval.Seconds()); interval > 0 {
+			request.RetryInterval = &interval
+		}
+	})
+
+	return file, wait(err, rc)
+}
+
+func (cp *CP) getLocalListVersion201() (int, error) {
+	var res int
+	rc := make(chan error, 1)
+
+	err := Instance().csms.GetLocalListVersion(cp.id, func(request *localauth.GetLocalListVersionResponse, err error) {
+		if err == nil && request != nil {
+			res = request.VersionNumber
+		}
+
+		rc <- err
+	})
+
+	return res, wait(err, rc)
+}
+
+func (cp *CP) sendLocalList201(version int, ids []string) error {
+	rc := make(chan error, 1)
+
+	err := Instance().csms.SendLocalList(cp.id, func(request *localauth.SendLocalListResponse, err error) {
+		if err == nil && request != nil && request.Status != localauth.SendLocalListStatusAccepted {
+			err = errors.New(string(request.Status))
+		}
+
+		rc <- err
+	}, version, localauth.UpdateTypeFull, func(request *localauth.SendLocalListRequest) {
+		for _, id := range ids {
+			request.LocalAuthorizationList = append(request.LocalAuthorizationList, localauth.AuthorizationData{
+				IdToken: types201.IdToken{
+					IdToken: id,
+					Type:    types201.IdTokenTypeISO14443,
+				},
+				IdTokenInfo: &types201.IdTokenInfo{
+					Status: types201.AuthorizationStatusAccepted,
+				},
+			})
+		}
+	})
+
+	return wait(err, rc)
+}
+
+func (cp *CP) reserveNow201(connectorId int, expiry time.Time, idTag string, reservationId int) error {
+	rc := make(chan error, 1)
+
+	idToken := types201.IdToken{
+		IdToken: idTag,
+		Type:    types201.IdTokenTypeISO14443,
+	}
+
+	err := Instance().csms.ReserveNow(cp.id, func(request *reservation.ReserveNowResponse, err error) {
+		if err == nil && request != nil && request.Status != reservation.ReserveNowStatusAccepted {
+			err = errors.New(string(request.Status))
+		}
+
+		rc <- err
+	}, reservationId, types201.NewDateTime(expiry), idToken, func(request *reservation.ReserveNowRequest) {
+		if connectorId > 0 {
+			request.EvseID = &connectorId
+		}
+	})
+
+	return wait(err, rc)
+}
+
+func (cp *CP) cancelReservation201(reservationId int) error {
+	rc := make(chan error, 1)
+
+	err := Instance().csms.CancelReservation(cp.id, func(request *reservation.CancelReservationResponse, err error) {
+		if err == nil && request != nil && request.Status != reservation.CancelReservationStatusAccepted {
+			err = errors.New(string(request.Status))
+		}
+
+		rc <- err
+	}, reservationId)
+
+	return wait(err, rc)
+}
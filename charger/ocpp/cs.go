@@ -9,25 +9,52 @@ import (
 	"github.com/evcc-io/evcc/util"
 	ocpp16 "github.com/lorenzodonini/ocpp-go/ocpp1.6"
 	"github.com/lorenzodonini/ocpp-go/ocpp1.6/core"
+	ocpp2 "github.com/lorenzodonini/ocpp-go/ocpp2.0.1"
+	"github.com/lorenzodonini/ocpp-go/ws"
 )
 
 type registration struct {
-	mu     sync.RWMutex
-	setup  sync.RWMutex                            // serialises chargepoint setup
-	cp     *CP                                     // guarded by setup and CS mutexes
-	status map[int]*core.StatusNotificationRequest // guarded by mu mutex
+	mu       sync.RWMutex
+	setup    sync.RWMutex                            // serialises chargepoint setup
+	cp       *CP                                     // guarded by setup and CS mutexes
+	ready    bool                                    // shared setup completed, guarded by setup mutex
+	protocol string                                  // guarded by CS mutex
+	status   map[int]*core.StatusNotificationRequest // guarded by mu mutex
+	evses    map[int]*evse                           // ocpp 2.0.1 only, guarded by mu mutex
+	txns     map[string]transaction201               // ocpp 2.0.1 only, guarded by mu mutex
 }
 
 func newRegistration() *registration {
-	return &registration{status: make(map[int]*core.StatusNotificationRequest)}
+	return &registration{
+		status: make(map[int]*core.StatusNotificationRequest),
+		evses:  make(map[int]*evse),
+		txns:   make(map[string]transaction201),
+	}
 }
 
 type CS struct {
 	ocpp16.CentralSystem
-	mu    sync.Mutex
-	log   *util.Logger
-	regs  map[string]*registration // guarded by mu mutex
-	txnId atomic.Int64
+	csms            ocpp2.CSMS
+	server          ws.Server
+	mu              sync.Mutex
+	log             *util.Logger
+	regs            map[string]*registration // guarded by mu mutex
+	credentials     map[string]credentials   // guarded by mu mutex
+	supplied        map[string]credentials   // credentials of connected charge points, guarded by mu mutex
+	secret          string                   // shared password of charge points without credentials
+	signer          Signer                   // signs charge point certificates, optional
+	hosts           map[string]string        // host used by connected charge points, guarded by mu mutex
+	tls             bool
+	firmwareDir     string // locally hosted firmware files, optional
+	firmwarePath    string
+	diagnosticsDir  string // uploaded diagnostics files, optional
+	diagnosticsPath string
+	uploads         map[string]diagnosticsUpload // pending diagnostics uploads by token, guarded by mu mutex
+	authorization   AuthorizationPolicy          // id tags accepted unless overridden by connector
+	shutdown        ShutdownAction               // applied to running transactions on stop, optional
+	tracer          *tracer                      // message trace files, optional
+	queues          []*queueMap                  // dispatcher request queues
+	txnId           atomic.Int64
 }
 
 // errorHandler logs error channel
@@ -37,6 +64,13 @@ func (cs *CS) errorHandler(errC <-chan error) {
 	}
 }
 
+func (cs *CS) registration(id string) *registration {
+	cs.mu.Lock()
+	defer cs.mu.Unlock()
+
+	return cs.regs[id]
+}
+
 func (cs *CS) ChargepointByID(id string) (*CP, error) {
 	cs.mu.Lock()
 	defer cs.mu.Unlock()
@@ -51,6 +85,21 @@ func (cs *CS) ChargepointByID(id string) (*CP, error) {
 	return reg.cp, nil
 }
 
+// chargepoints returns the configured charge points
+func (cs *CS) chargepoints() []*CP {
+	cs.mu.Lock()
+	defer cs.mu.Unlock()
+
+	var res []*CP
+	for _, reg := range cs.regs {
+		if reg.cp != nil {
+			res = append(res, reg.cp)
+		}
+	}
+
+	return res
+}
+
 func (cs *CS) WithConnectorStatus(id string, connector int, fun func(status *core.StatusNotificationRequest)) {
 	cs.mu.Lock()
 	defer cs.mu.Unlock()
@@ -92,54 +141,129 @@ func (cs *CS) RegisterChargepoint(id string, newfun func() *CP, init func(*CP) e
 			return nil, errors.New("cannot have >1 charge point with empty station id")
 		}
 
+		// further connectors share the charge point's setup, retry if it failed before
+		if !reg.ready {
+			err := init(cp)
+			reg.ready = err == nil
+			return cp, err
+		}
+
 		return cp, nil
 	}
 
 	// first time- create the charge point
 	cp = newfun()
+	removePending(id)
 
 	cs.mu.Lock()
 	reg.cp = cp
 	cs.mu.Unlock()
 
 	if registered {
+		cs.mu.Lock()
+		protocol := reg.protocol
+		cs.mu.Unlock()
+
+		cp.setProtocol(protocol)
 		cp.connect(true)
 	}
 
-	return cp, init(cp)
+	err := init(cp)
+	reg.ready = err == nil
+
+	return cp, err
+}
+
+// deregisterChargepoint removes the charge point's configuration once its last connector has been released,
+// e.g. after its station id has been changed. A connected charge point is offered for adoption again.
+func (cs *CS) deregisterChargepoint(cp *CP) {
+	id := cp.ID()
+
+	reg := cs.registration(id)
+	if reg == nil {
+		return
+	}
+
+	// serialise with registration of the same station id
+	reg.setup.Lock()
+	defer reg.setup.Unlock()
+
+	cs.mu.Lock()
+	defer cs.mu.Unlock()
+
+	// registered again in the meantime
+	if reg.cp != cp || cp.hasConnectors() {
+		return
+	}
+
+	cs.log.DEBUG.Printf("charge point deregistered: %s", id)
+
+	reg.cp = nil
+	reg.ready = false
+
+	if cp.Connected() {
+		pendingConnected(id, reg.protocol, true)
+	} else {
+		delete(cs.regs, id)
+	}
 }
 
 // NewChargePoint implements ocpp16.ChargePointConnectionHandler
 func (cs *CS) NewChargePoint(chargePoint ocpp16.ChargePointConnection) {
+	cs.connect(chargePoint.ID(), ProtocolV16)
+}
+
+// ChargePointDisconnected implements ocpp16.ChargePointConnectionHandler
+func (cs *CS) ChargePointDisconnected(chargePoint ocpp16.ChargePointConnection) {
+	cs.disconnect(chargePoint.ID())
+}
+
+// NewChargingStation implements ocpp2.ChargingStationConnectionHandler
+func (cs *CS) NewChargingStation(chargingStation ocpp2.ChargingStationConnection) {
+	cs.connect(chargingStation.ID(), ProtocolV201)
+}
+
+// ChargingStationDisconnected implements ocpp2.ChargingStationConnectionHandler
+func (cs *CS) ChargingStationDisconnected(chargingStation ocpp2.ChargingStationConnection) {
+	cs.disconnect(chargingStation.ID())
+}
+
+func (cs *CS) connect(id, protocol string) {
 	cs.mu.Lock()
 	defer cs.mu.Unlock()
 
 	// check for configured charge point
-	reg, ok := cs.regs[chargePoint.ID()]
+	reg, ok := cs.regs[id]
 	if ok {
-		cs.log.DEBUG.Printf("charge point connected: %s", chargePoint.ID())
+		cs.log.DEBUG.Printf("charge point connected: %s (%s)", id, protocol)
+		reg.protocol = protocol
 
 		// trigger initial connection if charge point is already setup
 		if cp := reg.cp; cp != nil {
+			cp.setProtocol(protocol)
 			cp.connect(true)
+		} else {
+			pendingConnected(id, protocol, true)
 		}
 
 		return
 	}
 
-	cs.log.WARN.Printf("unknown charge point connected: %s", chargePoint.ID())
+	cs.log.WARN.Printf("unknown charge point connected: %s (%s)", id, protocol)
 
 	// check for configured anonymous charge point
 	reg, ok = cs.regs[""]
 	if ok && reg.cp != nil {
 		cp := reg.cp
-		cs.log.INFO.Printf("charge point connected, registering: %s", chargePoint.ID())
+		cs.log.INFO.Printf("charge point connected, registering: %s", id)
 
 		// update id
-		cp.RegisterID(chargePoint.ID())
-		cs.regs[chargePoint.ID()] = reg
+		cp.RegisterID(id)
+		cs.regs[id] = reg
 		delete(cs.regs, "")
 
+		reg.protocol = protocol
+		cp.setProtocol(protocol)
 		cp.connect(true)
 
 		return
@@ -147,14 +271,24 @@ func (cs *CS) NewChargePoint(chargePoint ocpp16.ChargePointConnection) {
 
 	// register unknown charge point
 	// when charge point setup is complete, it will eventually be associated with the connected id
-	cs.regs[chargePoint.ID()] = newRegistration()
+	reg = newRegistration()
+	reg.protocol = protocol
+	cs.regs[id] = reg
+
+	// offer for adoption into the configuration
+	pendingConnected(id, protocol, true)
 }
 
-// ChargePointDisconnected implements ocpp16.ChargePointConnectionHandler
-func (cs *CS) ChargePointDisconnected(chargePoint ocpp16.ChargePointConnection) {
-	cs.log.DEBUG.Printf("charge point disconnected: %s", chargePoint.ID())
+func (cs *CS) disconnect(id string) {
+	cs.log.DEBUG.Printf("charge point disconnected: %s", id)
+
+	cs.mu.Lock()
+	delete(cs.supplied, id)
+	cs.mu.Unlock()
 
-	if cp, err := cs.ChargepointByID(chargePoint.ID()); err == nil {
+	if cp, err := cs.ChargepointByID(id); err == nil {
 		cp.connect(false)
+	} else {
+		pendingConnected(id, "", false)
 	}
 }
@@ -0,0 +1,93 @@
+package ocpp
+
+import (
+	"crypto/subtle"
+	"net/http"
+
+	"github.com/gorilla/websocket"
+)
+
+// credentials are the http basic auth credentials of a charge point (security profile 1)
+type credentials struct {
+	user, password string
+}
+
+func (c credentials) match(user, password string) bool {
+	return subtle.ConstantTimeCompare([]byte(c.user), []byte(user)) == 1 &&
+		subtle.ConstantTimeCompare([]byte(c.password), []byte(password)) == 1
+}
+
+// SetCredentials requires the charge point to authenticate using the given basic auth credentials.
+// A charge point already connected using different credentials is disconnected.
+func (cs *CS) SetCredentials(id, user, password string) {
+	c := credentials{user: user, password: password}
+
+	cs.mu.Lock()
+	cs.credentials[id] = c
+	supplied, connected := cs.supplied[id]
+	cs.mu.Unlock()
+
+	if connected && !c.match(supplied.user, supplied.password) {
+		cs.log.WARN.Printf("invalid credentials, disconnecting: %s", id)
+
+		if err := cs.server.StopConnection(id, websocket.CloseError{Code: websocket.ClosePolicyViolation, Text: "invalid credentials"}); err != nil {
+			cs.log.DEBUG.Printf("disconnecting %s: %v", id, err)
+		}
+	}
+}
+
+// signCertificate signs the charge point's certificate signing request. The certificate chain is sent
+// asynchronously as the charge point expects it after the response.
+func (cs *CS) signCertificate(id, csr string, send func(chain string) error) bool {
+	if cs.signer == nil {
+		cs.log.DEBUG.Printf("no signer, rejecting certificate signing request: %s", id)
+		return false
+	}
+
+	var chain string
+	req, err := parseCSR(csr)
+	if err == nil {
+		chain, err = cs.signer.Sign(id, req)
+	}
+
+	if err != nil {
+		cs.log.WARN.Printf("rejecting certificate signing request: %s: %v", id, err)
+		return false
+	}
+
+	go func() {
+		if err := send(chain); err != nil {
+			cs.log.ERROR.Printf("sending certificate: %s: %v", id, err)
+			return
+		}
+
+		cs.log.INFO.Printf("certificate installed: %s", id)
+	}()
+
+	return true
+}
+
+// authorize validates the basic auth credentials of the websocket upgrade request. Charge points without
+// credentials must use the shared secret as password if configured.
+func (cs *CS) authorize(id string, r *http.Request) bool {
+	user, password, _ := r.BasicAuth()
+
+	cs.mu.Lock()
+	defer cs.mu.Unlock()
+
+	var valid bool
+	if c, ok := cs.credentials[id]; ok {
+		valid = c.match(user, password)
+	} else {
+		valid = cs.secret == "" || subtle.ConstantTimeCompare([]byte(cs.secret), []byte(password)) == 1
+	}
+
+	if !valid {
+		cs.log.WARN.Printf("invalid credentials, rejecting: %s (%s)", id, r.RemoteAddr)
+		return false
+	}
+
+	cs.supplied[id] = credentials{user: user, password: password}
+
+	return true
+}
@@ -1,7 +1,12 @@
 package ocpp
 
 import (
+	"errors"
+	"slices"
+	"time"
+
 	"github.com/lorenzodonini/ocpp-go/ocpp1.6/core"
+	"github.com/lorenzodonini/ocpp-go/ocpp1.6/firmware"
 	"github.com/lorenzodonini/ocpp-go/ocpp1.6/security"
 	"github.com/lorenzodonini/ocpp-go/ocpp1.6/types"
 )
@@ -9,11 +14,18 @@ import (
 // cp actions
 
 func (cs *CS) OnAuthorize(id string, request *core.AuthorizeRequest) (*core.AuthorizeConfirmation, error) {
-	// no cp handler
+	status := types.AuthorizationStatusInvalid
+	if cs.authorizeIdTag(id, 0, request.IdTag) {
+		status = types.AuthorizationStatusAccepted
+
+		if cp, err := cs.ChargepointByID(id); err == nil {
+			cp.OnAuthorize(request)
+		}
+	}
 
 	res := &core.AuthorizeConfirmation{
 		IdTagInfo: &types.IdTagInfo{
-			Status: types.AuthorizationStatusAccepted,
+			Status: status,
 		},
 	}
 
@@ -25,6 +37,10 @@ func (cs *CS) OnBootNotification(id string, request *core.BootNotificationReques
 		return cp.OnBootNotification(request)
 	}
 
+	if request != nil {
+		pendingBooted(id, request)
+	}
+
 	res := &core.BootNotificationConfirmation{
 		CurrentTime: types.Now(),
 		Interval:    int(Timeout.Seconds()),
@@ -35,17 +51,20 @@ func (cs *CS) OnBootNotification(id string, request *core.BootNotificationReques
 }
 
 func (cs *CS) OnDataTransfer(id string, request *core.DataTransferRequest) (*core.DataTransferConfirmation, error) {
-	// no cp handler
+	status, data := cs.dataTransfer(id, request.VendorId, request.MessageId, request.Data)
 
 	res := &core.DataTransferConfirmation{
-		Status: core.DataTransferStatusAccepted,
+		Status: status,
+		Data:   data,
 	}
 
 	return res, nil
 }
 
 func (cs *CS) OnHeartbeat(id string, request *core.HeartbeatRequest) (*core.HeartbeatConfirmation, error) {
-	// no cp handler
+	if cp, err := cs.ChargepointByID(id); err == nil {
+		return cp.OnHeartbeat(request)
+	}
 
 	res := &core.HeartbeatConfirmation{
 		CurrentTime: types.Now(),
@@ -80,13 +99,23 @@ func (cs *CS) OnStatusNotification(id string, request *core.StatusNotificationRe
 }
 
 func (cs *CS) OnStartTransaction(id string, request *core.StartTransactionRequest) (*core.StartTransactionConfirmation, error) {
+	// the charge point is expected to stop transactions with rejected id tags
+	status := types.AuthorizationStatusAccepted
+	if !cs.authorizeIdTag(id, request.ConnectorId, request.IdTag) {
+		status = types.AuthorizationStatusInvalid
+	}
+
 	if cp, err := cs.ChargepointByID(id); err == nil {
-		return cp.OnStartTransaction(request)
+		res, err := cp.OnStartTransaction(request)
+		if err == nil && res != nil && res.IdTagInfo != nil {
+			res.IdTagInfo.Status = status
+		}
+		return res, err
 	}
 
 	res := &core.StartTransactionConfirmation{
 		IdTagInfo: &types.IdTagInfo{
-			Status: types.AuthorizationStatusAccepted,
+			Status: status,
 		},
 	}
 
@@ -96,6 +125,9 @@ func (cs *CS) OnStartTransaction(id string, request *core.StartTransactionReques
 func (cs *CS) OnStopTransaction(id string, request *core.StopTransactionRequest) (*core.StopTransactionConfirmation, error) {
 	if cp, err := cs.ChargepointByID(id); err == nil {
 		cp.OnStopTransaction(request)
+	} else {
+		// transaction stopped while restarting
+		finishJournaledTransaction(id, request)
 	}
 
 	res := &core.StopTransactionConfirmation{
@@ -108,14 +140,47 @@ func (cs *CS) OnStopTransaction(id string, request *core.StopTransactionRequest)
 }
 
 func (cs *CS) OnSecurityEventNotification(id string, request *security.SecurityEventNotificationRequest) (*security.SecurityEventNotificationResponse, error) {
+	cs.log.WARN.Printf("security event: %s: %s %s", id, request.Type, request.TechInfo)
+
+	event := SecurityEvent{
+		Station:   id,
+		Timestamp: time.Now(),
+		Type:      request.Type,
+		TechInfo:  request.TechInfo,
+		Critical:  slices.Contains(criticalSecurityEvents, request.Type),
+	}
+
+	if request.Timestamp != nil {
+		event.Timestamp = request.Timestamp.Time
+	}
+
+	addSecurityEvent(event)
+
 	// Acknowledge any security event
 	return &security.SecurityEventNotificationResponse{}, nil
 }
 
 func (cs *CS) OnSignCertificate(id string, request *security.SignCertificateRequest) (*security.SignCertificateResponse, error) {
-	// Reject any certificate signing request
+	status := types.GenericStatusRejected
+
+	if cs.signCertificate(id, request.CSR, func(chain string) error {
+		rc := make(chan error, 1)
+
+		err := cs.CertificateSigned(id, func(request *security.CertificateSignedResponse, err error) {
+			if err == nil && request != nil && request.Status != security.CertificateSignedStatusAccepted {
+				err = errors.New(string(request.Status))
+			}
+
+			rc <- err
+		}, chain)
+
+		return wait(err, rc)
+	}) {
+		status = types.GenericStatusAccepted
+	}
+
 	return &security.SignCertificateResponse{
-		Status: types.GenericStatusRejected,
+		Status: status,
 	}, nil
 }
 
@@ -125,3 +190,17 @@ func (cs *CS) OnCertificateSigned(id string, request *security.CertificateSigned
 		Status: security.CertificateSignedStatusAccepted,
 	}, nil
 }
+
+func (cs *CS) OnFirmwareStatusNotification(id string, request *firmware.FirmwareStatusNotificationRequest) (*firmware.FirmwareStatusNotificationConfirmation, error) {
+	cs.log.DEBUG.Printf("firmware status: %s: %s", id, request.Status)
+	updateFirmwareStatus(id, string(request.Status), "")
+
+	return new(firmware.FirmwareStatusNotificationConfirmation), nil
+}
+
+func (cs *CS) OnDiagnosticsStatusNotification(id string, request *firmware.DiagnosticsStatusNotificationRequest) (*firmware.DiagnosticsStatusNotificationConfirmation, error) {
+	cs.log.DEBUG.Printf("diagnostics status: %s: %s", id, request.Status)
+	updateDiagnosticsStatus(id, string(request.Status), "")
+
+	return new(firmware.DiagnosticsStatusNotificationConfirmation), nil
+}
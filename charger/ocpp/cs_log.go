@@ -5,25 +5,24 @@ import (
 	"strings"
 )
 
-func (cs *CS) print(s string) {
-	// for _, p := range []string{
-	// 	"completed request",
-	// 	"dispatched request",
-	// 	"enqueued CALL",
-	// 	"handling incoming",
-	// 	"sent CALL",
-	// 	"started timeout",
-	// 	"timeout canceled",
-	// } {
-	// 	if strings.HasPrefix(s, p) {
-	// 		return
-	// 	}
-	// }
+// logPrefixes are the ocpp-go log messages that are logged, all others are discarded
+var logPrefixes = []string{"sent JSON message to", "received JSON message from"}
+
+// logged checks if the message will be logged before formatting it
+func logged(f string) bool {
+	for _, p := range logPrefixes {
+		if strings.HasPrefix(f, p) {
+			return true
+		}
+	}
+	return false
+}
 
+func (cs *CS) print(s string) {
 	var ok bool
-	if s, ok = strings.CutPrefix(s, "sent JSON message to"); ok {
+	if s, ok = strings.CutPrefix(s, logPrefixes[0]); ok {
 		s = "send" + s
-	} else if s, ok = strings.CutPrefix(s, "received JSON message from"); ok {
+	} else if s, ok = strings.CutPrefix(s, logPrefixes[1]); ok {
 		s = "recv" + s
 	}
 	if ok {
@@ -31,26 +30,58 @@ func (cs *CS) print(s string) {
 	}
 }
 
-func (cs *CS) Debug(args ...any) {
+func (cs *CS) println(args ...any) {
+	if len(args) > 0 {
+		if f, ok := args[0].(string); ok && !logged(f) {
+			return
+		}
+	}
 	cs.print(fmt.Sprintln(args...))
 }
 
-func (cs *CS) Debugf(f string, args ...any) {
+func (cs *CS) printf(f string, args ...any) {
+	if !logged(f) {
+		return
+	}
+
+	// central system messages are logged with station id, charge point client messages without
+	if len(args) == 2 {
+		id, _ := args[0].(string)
+		raw, _ := args[1].(string)
+
+		direction := TraceRecv
+		if strings.HasPrefix(f, logPrefixes[0]) {
+			direction = TraceSend
+		}
+
+		msg := newTraceMessage(id, direction, raw)
+		countMessage(msg)
+		cs.trace(id, msg)
+	}
+
 	cs.print(fmt.Sprintf(f, args...))
 }
 
+func (cs *CS) Debug(args ...any) {
+	cs.println(args...)
+}
+
+func (cs *CS) Debugf(f string, args ...any) {
+	cs.printf(f, args...)
+}
+
 func (cs *CS) Info(args ...any) {
-	cs.print(fmt.Sprintln(args...))
+	cs.println(args...)
 }
 
 func (cs *CS) Infof(f string, args ...any) {
-	cs.print(fmt.Sprintf(f, args...))
+	cs.printf(f, args...)
 }
 
 func (cs *CS) Error(args ...any) {
-	cs.print(fmt.Sprintln(args...))
+	cs.println(args...)
 }
 
 func (cs *CS) Errorf(f string, args ...any) {
-	cs.print(fmt.Sprintf(f, args...))
+	cs.printf(f, args...)
 }
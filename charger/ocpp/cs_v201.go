@@ -0,0 +1,411 @@
+package ocpp
+
+import (
+	"errors"
+
+	"github.com/lorenzodonini/ocpp-go/ocpp1.6/core"
+	security16 "github.com/lorenzodonini/ocpp-go/ocpp1.6/security"
+	"github.com/lorenzodonini/ocpp-go/ocpp1.6/types"
+	"github.com/lorenzodonini/ocpp-go/ocpp2.0.1/authorization"
+	"github.com/lorenzodonini/ocpp-go/ocpp2.0.1/availability"
+	"github.com/lorenzodonini/ocpp-go/ocpp2.0.1/data"
+	"github.com/lorenzodonini/ocpp-go/ocpp2.0.1/diagnostics"
+	"github.com/lorenzodonini/ocpp-go/ocpp2.0.1/firmware"
+	"github.com/lorenzodonini/ocpp-go/ocpp2.0.1/meter"
+	"github.com/lorenzodonini/ocpp-go/ocpp2.0.1/provisioning"
+	"github.com/lorenzodonini/ocpp-go/ocpp2.0.1/reservation"
+	"github.com/lorenzodonini/ocpp-go/ocpp2.0.1/security"
+	"github.com/lorenzodonini/ocpp-go/ocpp2.0.1/transactions"
+	types201 "github.com/lorenzodonini/ocpp-go/ocpp2.0.1/types"
+)
+
+// csms201 handles OCPP 2.0.1 charging station messages by translating them to the 1.6 central system handlers
+type csms201 struct {
+	cs *CS
+}
+
+// cs actions
+
+func (h *csms201) OnBootNotification(id string, request *provisioning.BootNotificationRequest) (*provisioning.BootNotificationResponse, error) {
+	station := request.ChargingStation
+
+	res, err := h.cs.OnBootNotification(id, &core.BootNotificationRequest{
+		ChargePointVendor:       station.VendorName,
+		ChargePointModel:        station.Model,
+		ChargePointSerialNumber: station.SerialNumber,
+		FirmwareVersion:         station.FirmwareVersion,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return &provisioning.BootNotificationResponse{
+		CurrentTime: types201.NewDateTime(res.CurrentTime.Time),
+		Interval:    res.Interval,
+		Status:      provisioning.RegistrationStatus(res.Status),
+	}, nil
+}
+
+func (h *csms201) OnNotifyReport(id string, request *provisioning.NotifyReportRequest) (*provisioning.NotifyReportResponse, error) {
+	// no cp handler
+
+	return new(provisioning.NotifyReportResponse), nil
+}
+
+func (h *csms201) OnHeartbeat(id string, request *availability.HeartbeatRequest) (*availability.HeartbeatResponse, error) {
+	res, err := h.cs.OnHeartbeat(id, new(core.HeartbeatRequest))
+	if err != nil {
+		return nil, err
+	}
+
+	return &availability.HeartbeatResponse{
+		CurrentTime: *types201.NewDateTime(res.CurrentTime.Time),
+	}, nil
+}
+
+func (h *csms201) OnStatusNotification(id string, request *availability.StatusNotificationRequest) (*availability.StatusNotificationResponse, error) {
+	reg := h.cs.registration(id)
+	if reg == nil {
+		return new(availability.StatusNotificationResponse), nil
+	}
+
+	reg.mu.Lock()
+	e := reg.evse(request.EvseID)
+	e.connector = request.ConnectorStatus
+	if e.connector == availability.ConnectorStatusAvailable {
+		e.finished = false
+	}
+	status := e.statusNotification(request.EvseID, request.Timestamp)
+	reg.mu.Unlock()
+
+	if _, err := h.cs.OnStatusNotification(id, status); err != nil {
+		return nil, err
+	}
+
+	return new(availability.StatusNotificationResponse), nil
+}
+
+func (h *csms201) OnMeterValues(id string, request *meter.MeterValuesRequest) (*meter.MeterValuesResponse, error) {
+	req := &core.MeterValuesRequest{
+		ConnectorId: request.EvseID,
+		MeterValue:  meterValues16(request.MeterValue),
+	}
+
+	// recover running transaction
+	if reg := h.cs.registration(id); reg != nil {
+		var txnID string
+		reg.mu.Lock()
+		if e, ok := reg.evses[request.EvseID]; ok {
+			txnID = e.txn
+		}
+		txn := reg.txns[txnID]
+		reg.mu.Unlock()
+
+		if txnID != "" {
+			var err error
+			if txn, err = h.startTransaction(id, reg, txnID, txn, "", nil); err != nil {
+				return nil, err
+			}
+		}
+
+		if txn.id != 0 {
+			req.TransactionId = &txn.id
+		}
+	}
+
+	if _, err := h.cs.OnMeterValues(id, req); err != nil {
+		return nil, err
+	}
+
+	return new(meter.MeterValuesResponse), nil
+}
+
+func (h *csms201) OnAuthorize(id string, request *authorization.AuthorizeRequest) (*authorization.AuthorizeResponse, error) {
+	conf, err := h.cs.OnAuthorize(id, &core.AuthorizeRequest{
+		IdTag: request.IdToken.IdToken,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return &authorization.AuthorizeResponse{
+		IdTokenInfo: types201.IdTokenInfo{
+			Status: types201.AuthorizationStatus(conf.IdTagInfo.Status),
+		},
+	}, nil
+}
+
+// OnTransactionEvent translates the transaction event into 1.6 start and stop transaction, meter values and
+// status notification messages
+func (h *csms201) OnTransactionEvent(id string, request *transactions.TransactionEventRequest) (*transactions.TransactionEventResponse, error) {
+	res := new(transactions.TransactionEventResponse)
+
+	// rejected id tokens are published when starting the transaction
+	if request.IDToken != nil {
+		var evse int
+		if request.Evse != nil {
+			evse = request.Evse.ID
+		}
+
+		status := types201.AuthorizationStatusAccepted
+		if !h.cs.idTagAccepted(id, evse, request.IDToken.IdToken) {
+			status = types201.AuthorizationStatusInvalid
+		}
+
+		res.IDTokenInfo = &types201.IdTokenInfo{
+			Status: status,
+		}
+	}
+
+	reg := h.cs.registration(id)
+	if reg == nil {
+		return res, nil
+	}
+
+	info := request.TransactionInfo
+
+	reg.mu.Lock()
+	txn := reg.txns[info.TransactionID]
+	if request.Evse != nil {
+		txn.evse = request.Evse.ID
+	}
+
+	if txn.evse == 0 {
+		reg.mu.Unlock()
+		h.cs.log.DEBUG.Printf("%s: unknown evse for transaction %s", id, info.TransactionID)
+		return res, nil
+	}
+
+	e := reg.evse(txn.evse)
+	if request.EventType == transactions.TransactionEventEnded {
+		e.txn, e.finished = "", true
+		delete(reg.txns, info.TransactionID)
+	} else {
+		e.txn, e.finished = info.TransactionID, false
+		reg.txns[info.TransactionID] = txn
+	}
+	if info.ChargingState != "" {
+		e.charging = info.ChargingState
+	}
+
+	status := e.statusNotification(txn.evse, request.Timestamp)
+	reg.mu.Unlock()
+
+	var idTag string
+	if request.IDToken != nil {
+		idTag = request.IDToken.IdToken
+	}
+
+	var timestamp *types.DateTime
+	if request.Timestamp != nil {
+		timestamp = types.NewDateTime(request.Timestamp.Time)
+	}
+
+	if request.EventType != transactions.TransactionEventEnded {
+		var err error
+		if txn, err = h.startTransaction(id, reg, info.TransactionID, txn, idTag, timestamp); err != nil {
+			return nil, err
+		}
+
+		// id token presented after the transaction has been started
+		if idTag != "" && res.IDTokenInfo.Status == types201.AuthorizationStatusAccepted {
+			if cp, err := h.cs.ChargepointByID(id); err == nil {
+				if conn := cp.connectorByID(txn.evse); conn != nil {
+					conn.OnAuthorize(&core.AuthorizeRequest{IdTag: idTag})
+				}
+			}
+		}
+	}
+
+	if len(request.MeterValue) > 0 {
+		req := &core.MeterValuesRequest{
+			ConnectorId: txn.evse,
+			MeterValue:  meterValues16(request.MeterValue),
+		}
+		if txn.id != 0 {
+			req.TransactionId = &txn.id
+		}
+
+		if _, err := h.cs.OnMeterValues(id, req); err != nil {
+			return nil, err
+		}
+	}
+
+	if request.EventType == transactions.TransactionEventEnded {
+		if txn.id == 0 {
+			txn.id = h.cs.connectorTransactionID(id, txn.evse)
+		}
+
+		if _, err := h.cs.OnStopTransaction(id, &core.StopTransactionRequest{
+			IdTag:         idTag,
+			Timestamp:     timestamp,
+			TransactionId: txn.id,
+			Reason:        core.Reason(info.StoppedReason),
+		}); err != nil {
+			return nil, err
+		}
+	}
+
+	if _, err := h.cs.OnStatusNotification(id, status); err != nil {
+		return nil, err
+	}
+
+	return res, nil
+}
+
+// startTransaction resumes the transaction of an already running connector or starts a new transaction.
+// It is retried until the connector has been configured.
+func (h *csms201) startTransaction(id string, reg *registration, txnID string, txn transaction201, idTag string, timestamp *types.DateTime) (transaction201, error) {
+	if txn.id != 0 {
+		return txn, nil
+	}
+
+	txn.id = h.cs.connectorTransactionID(id, txn.evse)
+
+	if txn.id == 0 {
+		conf, err := h.cs.OnStartTransaction(id, &core.StartTransactionRequest{
+			ConnectorId: txn.evse,
+			IdTag:       idTag,
+			Timestamp:   timestamp,
+		})
+		if err != nil {
+			return txn, err
+		}
+
+		txn.id = conf.TransactionId
+	}
+
+	if txn.id != 0 {
+		reg.mu.Lock()
+		if _, ok := reg.txns[txnID]; ok {
+			reg.txns[txnID] = txn
+		}
+		reg.mu.Unlock()
+	}
+
+	return txn, nil
+}
+
+func (h *csms201) OnDataTransfer(id string, request *data.DataTransferRequest) (*data.DataTransferResponse, error) {
+	status, res := h.cs.dataTransfer(id, request.VendorID, request.MessageID, request.Data)
+
+	return &data.DataTransferResponse{
+		Status: data.DataTransferStatus(status),
+		Data:   res,
+	}, nil
+}
+
+func (h *csms201) OnFirmwareStatusNotification(id string, request *firmware.FirmwareStatusNotificationRequest) (*firmware.FirmwareStatusNotificationResponse, error) {
+	h.cs.log.DEBUG.Printf("firmware status: %s: %s", id, request.Status)
+	updateFirmwareStatus(id, string(request.Status), "")
+
+	return new(firmware.FirmwareStatusNotificationResponse), nil
+}
+
+func (h *csms201) OnPublishFirmwareStatusNotification(id string, request *firmware.PublishFirmwareStatusNotificationRequest) (*firmware.PublishFirmwareStatusNotificationResponse, error) {
+	// no local controller support
+
+	return new(firmware.PublishFirmwareStatusNotificationResponse), nil
+}
+
+func (h *csms201) OnLogStatusNotification(id string, request *diagnostics.LogStatusNotificationRequest) (*diagnostics.LogStatusNotificationResponse, error) {
+	h.cs.log.DEBUG.Printf("diagnostics status: %s: %s", id, request.Status)
+	updateDiagnosticsStatus(id, string(request.Status), "")
+
+	return new(diagnostics.LogStatusNotificationResponse), nil
+}
+
+func (h *csms201) OnNotifyCustomerInformation(id string, request *diagnostics.NotifyCustomerInformationRequest) (*diagnostics.NotifyCustomerInformationResponse, error) {
+	// no cp handler
+
+	return new(diagnostics.NotifyCustomerInformationResponse), nil
+}
+
+func (h *csms201) OnNotifyEvent(id string, request *diagnostics.NotifyEventRequest) (*diagnostics.NotifyEventResponse, error) {
+	// no cp handler
+
+	return new(diagnostics.NotifyEventResponse), nil
+}
+
+func (h *csms201) OnNotifyMonitoringReport(id string, request *diagnostics.NotifyMonitoringReportRequest) (*diagnostics.NotifyMonitoringReportResponse, error) {
+	// no cp handler
+
+	return new(diagnostics.NotifyMonitoringReportResponse), nil
+}
+
+func (h *csms201) OnReservationStatusUpdate(id string, request *reservation.ReservationStatusUpdateRequest) (*reservation.ReservationStatusUpdateResponse, error) {
+	h.cs.log.DEBUG.Printf("reservation %d: %s: %s", request.ReservationID, id, request.Status)
+
+	if cp, err := h.cs.ChargepointByID(id); err == nil {
+		cp.mu.RLock()
+		for _, conn := range cp.connectors {
+			conn.reservationEnded(request.ReservationID)
+		}
+		cp.mu.RUnlock()
+	}
+
+	return new(reservation.ReservationStatusUpdateResponse), nil
+}
+
+func (h *csms201) OnSecurityEventNotification(id string, request *security.SecurityEventNotificationRequest) (*security.SecurityEventNotificationResponse, error) {
+	req := &security16.SecurityEventNotificationRequest{
+		Type:     request.Type,
+		TechInfo: request.TechInfo,
+	}
+	if request.Timestamp != nil {
+		req.Timestamp = types.NewDateTime(request.Timestamp.Time)
+	}
+
+	if _, err := h.cs.OnSecurityEventNotification(id, req); err != nil {
+		return nil, err
+	}
+
+	return new(security.SecurityEventNotificationResponse), nil
+}
+
+func (h *csms201) OnSignCertificate(id string, request *security.SignCertificateRequest) (*security.SignCertificateResponse, error) {
+	status := types201.GenericStatusRejected
+
+	if h.cs.signCertificate(id, request.CSR, func(chain string) error {
+		rc := make(chan error, 1)
+
+		err := h.cs.csms.CertificateSigned(id, func(request *security.CertificateSignedResponse, err error) {
+			if err == nil && request != nil && request.Status != security.CertificateSignedStatusAccepted {
+				err = errors.New(string(request.Status))
+			}
+
+			rc <- err
+		}, chain)
+
+		return wait(err, rc)
+	}) {
+		status = types201.GenericStatusAccepted
+	}
+
+	return &security.SignCertificateResponse{
+		Status: status,
+	}, nil
+}
+
+// evse returns the EVSE state. Lock must be held.
+func (reg *registration) evse(id int) *evse {
+	e, ok := reg.evses[id]
+	if !ok {
+		e = new(evse)
+		reg.evses[id] = e
+	}
+	return e
+}
+
+// connectorTransactionID returns the transaction id of a configured connector
+func (cs *CS) connectorTransactionID(id string, connector int) int {
+	if cp, err := cs.ChargepointByID(id); err == nil {
+		if conn := cp.connectorByID(connector); conn != nil {
+			if txn, err := conn.TransactionID(); err == nil {
+				return txn
+			}
+		}
+	}
+
+	return 0
+}
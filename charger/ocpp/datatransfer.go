@@ -0,0 +1,81 @@
+package ocpp
+
+import (
+	"encoding/json"
+	"sync"
+
+	"github.com/lorenzodonini/ocpp-go/ocpp1.6/core"
+)
+
+// DataTransferHandler handles vendor specific data transfer messages of a charge point.
+// The returned data is sent with the response, errors reject the message.
+type DataTransferHandler func(id string, data json.RawMessage) (any, error)
+
+type dataTransferKey struct {
+	vendorId, messageId string
+}
+
+var (
+	dataTransferMu       sync.RWMutex
+	dataTransferHandlers = make(map[dataTransferKey]DataTransferHandler)
+)
+
+// RegisterDataTransferHandler registers the handler for the vendor's message id.
+// An empty message id handles all messages of the vendor not handled otherwise.
+func RegisterDataTransferHandler(vendorId, messageId string, handler DataTransferHandler) {
+	dataTransferMu.Lock()
+	defer dataTransferMu.Unlock()
+
+	dataTransferHandlers[dataTransferKey{vendorId, messageId}] = handler
+}
+
+// dataTransferHandler returns the message's handler and if any handler for the vendor is registered
+func dataTransferHandler(vendorId, messageId string) (DataTransferHandler, bool) {
+	dataTransferMu.RLock()
+	defer dataTransferMu.RUnlock()
+
+	if h, ok := dataTransferHandlers[dataTransferKey{vendorId, messageId}]; ok {
+		return h, true
+	}
+
+	if h, ok := dataTransferHandlers[dataTransferKey{vendorId, ""}]; ok {
+		return h, true
+	}
+
+	for key := range dataTransferHandlers {
+		if key.vendorId == vendorId {
+			return nil, true
+		}
+	}
+
+	return nil, false
+}
+
+// dataTransfer dispatches the message to the registered handler. Messages of vendors without handler are accepted.
+func (cs *CS) dataTransfer(id, vendorId, messageId string, data any) (core.DataTransferStatus, any) {
+	handler, vendor := dataTransferHandler(vendorId, messageId)
+
+	switch {
+	case handler != nil:
+		// the payload is already decoded, handlers receive it as json
+		b, err := json.Marshal(data)
+		if err != nil {
+			cs.log.WARN.Printf("data transfer: %s: %s %s: %v", id, vendorId, messageId, err)
+			return core.DataTransferStatusRejected, nil
+		}
+
+		res, err := handler(id, b)
+		if err != nil {
+			cs.log.WARN.Printf("data transfer: %s: %s %s: %v", id, vendorId, messageId, err)
+			return core.DataTransferStatusRejected, nil
+		}
+
+		return core.DataTransferStatusAccepted, res
+
+	case vendor:
+		return core.DataTransferStatusUnknownMessageId, nil
+
+	default:
+		return core.DataTransferStatusAccepted, nil
+	}
+}
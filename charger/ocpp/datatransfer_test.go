@@ -0,0 +1,53 @@
+package ocpp
+
+import (
+	"encoding/json"
+	"errors"
+	"testing"
+
+	"github.com/evcc-io/evcc/util"
+	"github.com/lorenzodonini/ocpp-go/ocpp1.6/core"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestDataTransfer(t *testing.T) {
+	cs := &CS{log: util.NewLogger("foo")}
+
+	RegisterDataTransferHandler("vendor", "status", func(id string, data json.RawMessage) (any, error) {
+		var payload struct{ Value int }
+		if err := json.Unmarshal(data, &payload); err != nil {
+			return nil, err
+		}
+		return payload.Value * 2, nil
+	})
+	RegisterDataTransferHandler("vendor", "fail", func(id string, data json.RawMessage) (any, error) {
+		return nil, errors.New("fail")
+	})
+	defer func() {
+		clear(dataTransferHandlers)
+	}()
+
+	res, err := cs.OnDataTransfer("cp", &core.DataTransferRequest{VendorId: "vendor", MessageId: "status", Data: map[string]any{"value": 21}})
+	require.NoError(t, err)
+	assert.Equal(t, core.DataTransferStatusAccepted, res.Status)
+	assert.Equal(t, 42, res.Data)
+
+	status, _ := cs.dataTransfer("cp", "vendor", "fail", nil)
+	assert.Equal(t, core.DataTransferStatusRejected, status)
+
+	status, _ = cs.dataTransfer("cp", "vendor", "unknown", nil)
+	assert.Equal(t, core.DataTransferStatusUnknownMessageId, status)
+
+	// unhandled vendors are accepted
+	status, _ = cs.dataTransfer("cp", "other", "status", nil)
+	assert.Equal(t, core.DataTransferStatusAccepted, status)
+
+	// vendor wide handler
+	RegisterDataTransferHandler("vendor", "", func(id string, data json.RawMessage) (any, error) {
+		return nil, nil
+	})
+
+	status, _ = cs.dataTransfer("cp", "vendor", "unknown", nil)
+	assert.Equal(t, core.DataTransferStatusAccepted, status)
+}
@@ -0,0 +1,347 @@
+package ocpp
+
+import (
+	"cmp"
+	"crypto/rand"
+	"errors"
+	"fmt"
+	"io"
+	"maps"
+	"mime"
+	"net"
+	"net/http"
+	"os"
+	"path"
+	"path/filepath"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+)
+
+const (
+	maxDiagnosticsSize      = 100 << 20      // limits the size of uploaded diagnostics files
+	maxDiagnosticsDirSize   = 1 << 30        // limits the size of all diagnostics files, oldest files are removed first
+	diagnosticsUploadExpiry = 24 * time.Hour // validity of the upload url
+)
+
+// DiagnosticsStatus is the progress of a charge point's diagnostics upload
+type DiagnosticsStatus struct {
+	Status  string    `json:"status"`
+	File    string    `json:"file,omitempty"`
+	Updated time.Time `json:"updated"`
+}
+
+// DiagnosticsFile is a diagnostics file uploaded by a charge point
+type DiagnosticsFile struct {
+	Station  string    `json:"station"`
+	Name     string    `json:"name"`
+	Size     int64     `json:"size"`
+	Modified time.Time `json:"modified"`
+}
+
+// diagnosticsUpload is an upload pending for a GetDiagnostics request
+type diagnosticsUpload struct {
+	id      string
+	expires time.Time
+}
+
+var (
+	diagnosticsMu      sync.Mutex
+	diagnosticsHandler func(id string, status DiagnosticsStatus)
+	diagnosticsStatus  = make(map[string]DiagnosticsStatus)
+)
+
+// SetDiagnosticsStatusHandler registers the handler receiving diagnostics status changes and uploads
+func SetDiagnosticsStatusHandler(fun func(id string, status DiagnosticsStatus)) {
+	diagnosticsMu.Lock()
+	defer diagnosticsMu.Unlock()
+	diagnosticsHandler = fun
+}
+
+// updateDiagnosticsStatus updates the charge point's diagnostics status. Empty file keeps the current one.
+func updateDiagnosticsStatus(id, status, file string) {
+	diagnosticsMu.Lock()
+
+	res := diagnosticsStatus[id]
+	if status != "" {
+		res.Status = status
+	}
+	if file != "" {
+		res.File = file
+	}
+	res.Updated = time.Now()
+	diagnosticsStatus[id] = res
+
+	fun := diagnosticsHandler
+	diagnosticsMu.Unlock()
+
+	if fun != nil {
+		fun(id, res)
+	}
+}
+
+// DiagnosticsStatuses returns the diagnostics status per charge point
+func DiagnosticsStatuses() map[string]DiagnosticsStatus {
+	diagnosticsMu.Lock()
+	defer diagnosticsMu.Unlock()
+
+	return maps.Clone(diagnosticsStatus)
+}
+
+// DiagnosticsFiles returns the uploaded diagnostics files, latest first
+func DiagnosticsFiles() ([]DiagnosticsFile, error) {
+	if !running.Load() {
+		return nil, nil
+	}
+
+	return Instance().DiagnosticsFiles()
+}
+
+// DiagnosticsFileName returns the path of the charge point's uploaded diagnostics file
+func DiagnosticsFileName(id, file string) (string, error) {
+	if !running.Load() {
+		return "", errors.New("central system not running")
+	}
+
+	return Instance().diagnosticsFile(id, file)
+}
+
+// validName checks that the name can be used as single path element
+func validName(name string) bool {
+	return name != "" && name != "." && name != ".." && name == filepath.Base(name)
+}
+
+// DiagnosticsURL returns the url the charge point uploads its diagnostics to.
+// The url contains a one-time token valid for a single upload.
+func (cs *CS) DiagnosticsURL(id string) (string, error) {
+	if cs.diagnosticsDir == "" {
+		return "", errors.New("diagnostics directory not configured")
+	}
+
+	token := rand.Text()
+
+	u, err := cs.localURL(id, path.Join(cs.diagnosticsPath, id, token))
+	if err != nil {
+		return "", err
+	}
+
+	cs.mu.Lock()
+	defer cs.mu.Unlock()
+
+	now := time.Now()
+	maps.DeleteFunc(cs.uploads, func(_ string, u diagnosticsUpload) bool {
+		return now.After(u.expires)
+	})
+
+	cs.uploads[token] = diagnosticsUpload{id: id, expires: now.Add(diagnosticsUploadExpiry)}
+
+	return u, nil
+}
+
+// pendingUpload checks that the token belongs to a pending upload of the charge point
+func (cs *CS) pendingUpload(id, token string) bool {
+	cs.mu.Lock()
+	defer cs.mu.Unlock()
+
+	u, ok := cs.uploads[token]
+	return ok && u.id == id && time.Now().Before(u.expires)
+}
+
+// completeUpload invalidates the upload token
+func (cs *CS) completeUpload(token string) {
+	cs.mu.Lock()
+	defer cs.mu.Unlock()
+
+	delete(cs.uploads, token)
+}
+
+// pruneDiagnostics removes the oldest diagnostics files exceeding the directory size limit
+func (cs *CS) pruneDiagnostics() error {
+	files, err := cs.DiagnosticsFiles()
+	if err != nil {
+		return err
+	}
+
+	var size int64
+	for _, f := range files {
+		size += f.Size
+	}
+
+	// keep the latest file
+	for i := len(files) - 1; i > 0 && size > maxDiagnosticsDirSize; i-- {
+		f := files[i]
+		if err := os.Remove(filepath.Join(cs.diagnosticsDir, f.Station, f.Name)); err != nil {
+			return err
+		}
+
+		cs.log.DEBUG.Printf("removed diagnostics %s from %s", f.Name, f.Station)
+		size -= f.Size
+	}
+
+	return nil
+}
+
+// diagnosticsFile returns the path of the charge point's uploaded diagnostics file
+func (cs *CS) diagnosticsFile(id, file string) (string, error) {
+	if cs.diagnosticsDir == "" {
+		return "", errors.New("diagnostics directory not configured")
+	}
+
+	if !validName(id) || !validName(file) {
+		return "", fmt.Errorf("invalid diagnostics file: %s", file)
+	}
+
+	res := filepath.Join(cs.diagnosticsDir, id, file)
+	if fi, err := os.Stat(res); err != nil || fi.IsDir() {
+		return "", fmt.Errorf("diagnostics file not found: %s", file)
+	}
+
+	return res, nil
+}
+
+// DiagnosticsFiles returns the uploaded diagnostics files, latest first
+func (cs *CS) DiagnosticsFiles() ([]DiagnosticsFile, error) {
+	if cs.diagnosticsDir == "" {
+		return nil, nil
+	}
+
+	stations, err := os.ReadDir(cs.diagnosticsDir)
+	if err != nil {
+		if errors.Is(err, os.ErrNotExist) {
+			return nil, nil
+		}
+		return nil, err
+	}
+
+	var res []DiagnosticsFile
+	for _, station := range stations {
+		if !station.IsDir() {
+			continue
+		}
+
+		files, err := os.ReadDir(filepath.Join(cs.diagnosticsDir, station.Name()))
+		if err != nil {
+			return nil, err
+		}
+
+		for _, file := range files {
+			fi, err := file.Info()
+			if err != nil || !fi.Mode().IsRegular() {
+				continue
+			}
+
+			res = append(res, DiagnosticsFile{
+				Station:  station.Name(),
+				Name:     file.Name(),
+				Size:     fi.Size(),
+				Modified: fi.ModTime(),
+			})
+		}
+	}
+
+	slices.SortFunc(res, func(a, b DiagnosticsFile) int {
+		return cmp.Compare(b.Modified.UnixNano(), a.Modified.UnixNano())
+	})
+
+	return res, nil
+}
+
+// receiveDiagnostics stores diagnostics files uploaded by connected charge points to <path>/<id>/<token>[/<file>].
+// Files are accepted as multipart form (POST) or raw request body (POST, PUT). Uploads require the token
+// of a pending GetDiagnostics request.
+func (cs *CS) receiveDiagnostics(isListenAddr func(net.Addr) bool) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		addr, _ := r.Context().Value(http.LocalAddrContextKey).(net.Addr)
+		if !isListenAddr(addr) {
+			http.NotFound(w, r)
+			return
+		}
+
+		if r.Method != http.MethodPost && r.Method != http.MethodPut {
+			http.Error(w, http.StatusText(http.StatusMethodNotAllowed), http.StatusMethodNotAllowed)
+			return
+		}
+
+		id, rest, _ := strings.Cut(strings.TrimPrefix(r.URL.Path, cs.diagnosticsPath+"/"), "/")
+		token, file, _ := strings.Cut(rest, "/")
+
+		cs.mu.Lock()
+		_, ok := cs.hosts[id]
+		cs.mu.Unlock()
+
+		if !ok || !validName(id) || !cs.pendingUpload(id, token) {
+			http.NotFound(w, r)
+			return
+		}
+
+		r.Body = http.MaxBytesReader(w, r.Body, maxDiagnosticsSize)
+
+		var body io.Reader = r.Body
+		if mt, _, _ := mime.ParseMediaType(r.Header.Get("Content-Type")); mt == "multipart/form-data" {
+			mr, err := r.MultipartReader()
+			if err != nil {
+				http.Error(w, err.Error(), http.StatusBadRequest)
+				return
+			}
+
+			for {
+				part, err := mr.NextPart()
+				if err != nil {
+					http.Error(w, "missing file", http.StatusBadRequest)
+					return
+				}
+
+				if part.FileName() != "" {
+					file, body = part.FileName(), part
+					break
+				}
+			}
+		}
+
+		if file = filepath.Base(file); !validName(file) {
+			file = fmt.Sprintf("diagnostics-%s", time.Now().Format("20060102-150405"))
+		}
+
+		dir := filepath.Join(cs.diagnosticsDir, id)
+		if err := os.MkdirAll(dir, 0o755); err != nil {
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+			return
+		}
+
+		if err := writeDiagnostics(filepath.Join(dir, file), body); err != nil {
+			cs.log.ERROR.Printf("diagnostics upload: %s: %v", id, err)
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+
+		cs.log.DEBUG.Printf("received diagnostics %s from %s", file, id)
+		cs.completeUpload(token)
+		updateDiagnosticsStatus(id, "", file)
+
+		if err := cs.pruneDiagnostics(); err != nil {
+			cs.log.ERROR.Printf("diagnostics cleanup: %v", err)
+		}
+
+		w.WriteHeader(http.StatusCreated)
+	}
+}
+
+// writeDiagnostics writes the file and removes it on failure
+func writeDiagnostics(name string, body io.Reader) error {
+	f, err := os.Create(name)
+	if err != nil {
+		return err
+	}
+
+	_, err = io.Copy(f, body)
+	if cerr := f.Close(); err == nil {
+		err = cerr
+	}
+
+	if err != nil {
+		os.Remove(name)
+	}
+
+	return err
+}
@@ -0,0 +1,143 @@
+package ocpp
+
+import (
+	"bytes"
+	"io"
+	"mime/multipart"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/evcc-io/evcc/util"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestDiagnosticsStatus(t *testing.T) {
+	var handled []DiagnosticsStatus
+	SetDiagnosticsStatusHandler(func(id string, status DiagnosticsStatus) {
+		handled = append(handled, status)
+	})
+	defer SetDiagnosticsStatusHandler(nil)
+
+	updateDiagnosticsStatus("cp-diag", "Requested", "diag.zip")
+	updateDiagnosticsStatus("cp-diag", "Uploading", "")
+
+	require.Len(t, handled, 2)
+
+	// file is kept on status updates
+	status := DiagnosticsStatuses()["cp-diag"]
+	assert.Equal(t, "Uploading", status.Status)
+	assert.Equal(t, "diag.zip", status.File)
+}
+
+func TestReceiveDiagnostics(t *testing.T) {
+	dir := t.TempDir()
+
+	cs := &CS{
+		log:             util.NewLogger("ocpp"),
+		hosts:           map[string]string{"cp-1": "192.168.0.2:8887", "cp-2": "192.168.0.3:8887"},
+		diagnosticsDir:  dir,
+		diagnosticsPath: "/ocpp/diagnostics",
+		uploads:         make(map[string]diagnosticsUpload),
+	}
+
+	srv := httptest.NewServer(cs.receiveDiagnostics(func(net.Addr) bool { return true }))
+	defer srv.Close()
+
+	// local upload url with one-time token
+	uploadPath := func(id string) string {
+		u, err := cs.DiagnosticsURL(id)
+		require.NoError(t, err)
+		require.True(t, strings.HasPrefix(u, "http://"+cs.hosts[id]+"/ocpp/diagnostics/"+id+"/"))
+		return strings.TrimPrefix(u, "http://"+cs.hosts[id])
+	}
+
+	post := func(path, contentType string, body io.Reader) int {
+		resp, err := http.Post(srv.URL+path, contentType, body)
+		require.NoError(t, err)
+		resp.Body.Close()
+		return resp.StatusCode
+	}
+
+	// multipart upload
+	var b bytes.Buffer
+	mw := multipart.NewWriter(&b)
+	fw, err := mw.CreateFormFile("file", "diag.zip")
+	require.NoError(t, err)
+	_, _ = fw.Write([]byte("multipart"))
+	require.NoError(t, mw.Close())
+
+	p := uploadPath("cp-1")
+	assert.Equal(t, http.StatusCreated, post(p, mw.FormDataContentType(), &b))
+
+	// token is valid for a single upload
+	assert.Equal(t, http.StatusNotFound, post(p, "application/octet-stream", strings.NewReader("raw")))
+
+	// raw upload
+	req, err := http.NewRequest(http.MethodPut, srv.URL+uploadPath("cp-1")+"/diag.log", strings.NewReader("raw"))
+	require.NoError(t, err)
+	resp, err := http.DefaultClient.Do(req)
+	require.NoError(t, err)
+	resp.Body.Close()
+	assert.Equal(t, http.StatusCreated, resp.StatusCode)
+
+	// no pending request, token of other charge point, unknown charge point
+	assert.Equal(t, http.StatusNotFound, post("/ocpp/diagnostics/cp-1", "application/octet-stream", strings.NewReader("raw")))
+	assert.Equal(t, http.StatusNotFound, post("/ocpp/diagnostics/cp-1/invalid", "application/octet-stream", strings.NewReader("raw")))
+	assert.Equal(t, http.StatusNotFound, post(strings.Replace(uploadPath("cp-2"), "cp-2", "cp-1", 1), "application/octet-stream", strings.NewReader("raw")))
+	assert.Equal(t, http.StatusNotFound, post("/ocpp/diagnostics/cp-3/token", "application/octet-stream", strings.NewReader("raw")))
+
+	data, err := os.ReadFile(filepath.Join(dir, "cp-1", "diag.zip"))
+	require.NoError(t, err)
+	assert.Equal(t, "multipart", string(data))
+
+	files, err := cs.DiagnosticsFiles()
+	require.NoError(t, err)
+	require.Len(t, files, 2)
+	assert.Equal(t, "cp-1", files[0].Station)
+
+	_, err = cs.diagnosticsFile("cp-1", "diag.log")
+	assert.NoError(t, err)
+
+	for _, tc := range []struct{ id, file string }{
+		{"cp-1", "missing.zip"},
+		{"cp-1", "../cp-1/diag.log"},
+		{"..", "diag.log"},
+	} {
+		_, err := cs.diagnosticsFile(tc.id, tc.file)
+		assert.Error(t, err, tc)
+	}
+}
+
+func TestPruneDiagnostics(t *testing.T) {
+	dir := t.TempDir()
+
+	cs := &CS{
+		log:            util.NewLogger("ocpp"),
+		diagnosticsDir: dir,
+	}
+
+	require.NoError(t, os.MkdirAll(filepath.Join(dir, "cp-1"), 0o755))
+
+	// files exceeding the size limit, oldest first
+	now := time.Now()
+	for i, name := range []string{"old.zip", "new.zip"} {
+		file := filepath.Join(dir, "cp-1", name)
+		require.NoError(t, os.WriteFile(file, nil, 0o644))
+		require.NoError(t, os.Truncate(file, maxDiagnosticsDirSize/2+1))
+		require.NoError(t, os.Chtimes(file, now, now.Add(time.Duration(i)*time.Minute)))
+	}
+
+	require.NoError(t, cs.pruneDiagnostics())
+
+	files, err := cs.DiagnosticsFiles()
+	require.NoError(t, err)
+	require.Len(t, files, 1)
+	assert.Equal(t, "new.zip", files[0].Name)
+}
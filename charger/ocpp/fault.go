@@ -0,0 +1,97 @@
+package ocpp
+
+import (
+	"fmt"
+	"regexp"
+	"slices"
+	"strings"
+
+	"github.com/lorenzodonini/ocpp-go/ocpp1.6/core"
+)
+
+// Fault is a charge point error reported by status notification
+type Fault struct {
+	ErrorCode       core.ChargePointErrorCode `json:"errorCode"`
+	Info            string                    `json:"info,omitempty"`
+	VendorId        string                    `json:"vendorId,omitempty"`
+	VendorErrorCode string                    `json:"vendorErrorCode,omitempty"`
+}
+
+// vehicleErrorCodes are caused by the vehicle or cable (IEC 61851 state E)
+var vehicleErrorCodes = []core.ChargePointErrorCode{
+	core.ConnectorLockFailure,
+	core.EVCommunicationError,
+	core.GroundFailure,
+	core.OverCurrentFailure,
+}
+
+// warningErrorCodes don't prevent charging
+var warningErrorCodes = []core.ChargePointErrorCode{
+	core.LocalListConflict,
+	core.ReaderFailure,
+	core.WeakSignal,
+}
+
+// newFault returns the fault reported by the status notification or nil
+func newFault(status *core.StatusNotificationRequest) *Fault {
+	if status == nil {
+		return nil
+	}
+
+	code := status.ErrorCode
+	if code == core.NoError {
+		if status.Status != core.ChargePointStatusFaulted {
+			return nil
+		}
+		code = core.OtherError
+	}
+
+	return &Fault{
+		ErrorCode:       code,
+		Info:            status.Info,
+		VendorId:        status.VendorId,
+		VendorErrorCode: status.VendorErrorCode,
+	}
+}
+
+// Vehicle returns true if the fault is caused by the vehicle or cable
+func (f *Fault) Vehicle() bool {
+	return slices.Contains(vehicleErrorCodes, f.ErrorCode)
+}
+
+// Warning returns true if the fault doesn't prevent charging
+func (f *Fault) Warning() bool {
+	return slices.Contains(warningErrorCodes, f.ErrorCode)
+}
+
+var (
+	acronym   = regexp.MustCompile(`([A-Z]+)([A-Z][a-z])`)
+	camelCase = regexp.MustCompile(`([a-z])([A-Z])`)
+)
+
+// Error implements the error interface and returns a human-readable description
+func (f *Fault) Error() string {
+	words := strings.Fields(camelCase.ReplaceAllString(acronym.ReplaceAllString(string(f.ErrorCode), "$1 $2"), "$1 $2"))
+	for i, w := range words {
+		if w != strings.ToUpper(w) {
+			words[i] = strings.ToLower(w)
+		}
+	}
+	res := strings.Join(words, " ")
+
+	var vendor []string
+	for _, s := range []string{f.VendorId, f.VendorErrorCode} {
+		if s != "" {
+			vendor = append(vendor, s)
+		}
+	}
+	if len(vendor) > 0 {
+		res = fmt.Sprintf("%s (%s)", res, strings.Join(vendor, " "))
+	}
+
+	if f.Info != "" {
+		res += ": " + f.Info
+	}
+
+	return res
+}
@@ -0,0 +1,39 @@
+package ocpp
+
+import (
+	"testing"
+
+	"github.com/lorenzodonini/ocpp-go/ocpp1.6/core"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestFault(t *testing.T) {
+	assert.Nil(t, newFault(nil))
+	assert.Nil(t, newFault(&core.StatusNotificationRequest{ErrorCode: core.NoError, Status: core.ChargePointStatusCharging}))
+
+	// faulted without error code
+	fault := newFault(&core.StatusNotificationRequest{ErrorCode: core.NoError, Status: core.ChargePointStatusFaulted})
+	require.NotNil(t, fault)
+	assert.Equal(t, core.OtherError, fault.ErrorCode)
+	assert.Equal(t, "other error", fault.Error())
+
+	fault = newFault(&core.StatusNotificationRequest{
+		ErrorCode:       core.EVCommunicationError,
+		Status:          core.ChargePointStatusFaulted,
+		Info:            "CP short circuit",
+		VendorId:        "acme",
+		VendorErrorCode: "E42",
+	})
+	require.NotNil(t, fault)
+	assert.True(t, fault.Vehicle())
+	assert.False(t, fault.Warning())
+	assert.Equal(t, "EV communication error (acme E42): CP short circuit", fault.Error())
+
+	fault = newFault(&core.StatusNotificationRequest{ErrorCode: core.PowerSwitchFailure, Status: core.ChargePointStatusFaulted})
+	assert.False(t, fault.Vehicle())
+	assert.Equal(t, "power switch failure", fault.Error())
+
+	fault = newFault(&core.StatusNotificationRequest{ErrorCode: core.WeakSignal, Status: core.ChargePointStatusAvailable})
+	assert.True(t, fault.Warning())
+}
@@ -0,0 +1,133 @@
+package ocpp
+
+import (
+	"errors"
+	"fmt"
+	"maps"
+	"net"
+	"net/http"
+	"net/url"
+	"os"
+	"path"
+	"path/filepath"
+	"sync"
+	"time"
+)
+
+// FirmwareStatus is the progress of a charge point's firmware update
+type FirmwareStatus struct {
+	Status   string    `json:"status"`
+	Location string    `json:"location,omitempty"`
+	Updated  time.Time `json:"updated"`
+}
+
+var (
+	firmwareMu      sync.Mutex
+	firmwareHandler func(id string, status FirmwareStatus)
+	firmwareStatus  = make(map[string]FirmwareStatus)
+)
+
+// SetFirmwareStatusHandler registers the handler receiving firmware status changes
+func SetFirmwareStatusHandler(fun func(id string, status FirmwareStatus)) {
+	firmwareMu.Lock()
+	defer firmwareMu.Unlock()
+	firmwareHandler = fun
+}
+
+// updateFirmwareStatus updates the charge point's firmware status. Empty location keeps the current one.
+func updateFirmwareStatus(id, status, location string) {
+	firmwareMu.Lock()
+
+	res := firmwareStatus[id]
+	res.Status = status
+	if location != "" {
+		res.Location = location
+	}
+	res.Updated = time.Now()
+	firmwareStatus[id] = res
+
+	fun := firmwareHandler
+	firmwareMu.Unlock()
+
+	if fun != nil {
+		fun(id, res)
+	}
+}
+
+// FirmwareStatuses returns the firmware status per charge point
+func FirmwareStatuses() map[string]FirmwareStatus {
+	firmwareMu.Lock()
+	defer firmwareMu.Unlock()
+
+	return maps.Clone(firmwareStatus)
+}
+
+// setHost records the host the charge point used for connecting
+func (cs *CS) setHost(id string, r *http.Request) {
+	cs.mu.Lock()
+	defer cs.mu.Unlock()
+
+	cs.hosts[id] = r.Host
+}
+
+// FirmwareURL returns the url of the locally hosted firmware file as reachable by the charge point
+func (cs *CS) FirmwareURL(id, file string) (string, error) {
+	if cs.firmwareDir == "" {
+		return "", errors.New("firmware directory not configured")
+	}
+
+	if file != filepath.Base(file) {
+		return "", fmt.Errorf("invalid firmware file: %s", file)
+	}
+
+	if fi, err := os.Stat(filepath.Join(cs.firmwareDir, file)); err != nil || fi.IsDir() {
+		return "", fmt.Errorf("firmware file not found: %s", file)
+	}
+
+	return cs.localURL(id, path.Join(cs.firmwarePath, file))
+}
+
+// localURL returns the url of the path using the host the charge point connected to
+func (cs *CS) localURL(id, p string) (string, error) {
+	cs.mu.Lock()
+	host, ok := cs.hosts[id]
+	cs.mu.Unlock()
+
+	if !ok {
+		return "", fmt.Errorf("unknown charge point: %s", id)
+	}
+
+	scheme := "http"
+	if cs.tls {
+		scheme = "https"
+	}
+
+	u := url.URL{
+		Scheme: scheme,
+		Host:   host,
+		Path:   p,
+	}
+
+	return u.String(), nil
+}
+
+// serveFirmware serves the firmware directory's files
+func (cs *CS) serveFirmware(isListenAddr func(net.Addr) bool) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		addr, _ := r.Context().Value(http.LocalAddrContextKey).(net.Addr)
+		if !isListenAddr(addr) {
+			http.NotFound(w, r)
+			return
+		}
+
+		file := filepath.Join(cs.firmwareDir, path.Base(r.URL.Path))
+		if fi, err := os.Stat(file); err != nil || fi.IsDir() {
+			http.NotFound(w, r)
+			return
+		}
+
+		cs.log.DEBUG.Printf("serving firmware %s to %s", file, r.RemoteAddr)
+
+		http.ServeFile(w, r, file)
+	}
+}
@@ -0,0 +1,93 @@
+package ocpp
+
+import (
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/evcc-io/evcc/util"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestFirmwareStatus(t *testing.T) {
+	var handled []FirmwareStatus
+	SetFirmwareStatusHandler(func(id string, status FirmwareStatus) {
+		handled = append(handled, status)
+	})
+	defer SetFirmwareStatusHandler(nil)
+
+	updateFirmwareStatus("cp-fw", "Requested", "http://example.com/fw.bin")
+	updateFirmwareStatus("cp-fw", "Downloading", "")
+
+	require.Len(t, handled, 2)
+
+	// location is kept on status updates
+	status := FirmwareStatuses()["cp-fw"]
+	assert.Equal(t, "Downloading", status.Status)
+	assert.Equal(t, "http://example.com/fw.bin", status.Location)
+}
+
+func TestFirmwareURL(t *testing.T) {
+	dir := t.TempDir()
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "fw.bin"), []byte("firmware"), 0o644))
+
+	cs := &CS{
+		log:          util.NewLogger("ocpp"),
+		hosts:        map[string]string{"cp-1": "192.168.0.2:8887"},
+		firmwareDir:  dir,
+		firmwarePath: "/ocpp/firmware",
+	}
+
+	u, err := cs.FirmwareURL("cp-1", "fw.bin")
+	require.NoError(t, err)
+	assert.Equal(t, "http://192.168.0.2:8887/ocpp/firmware/fw.bin", u)
+
+	cs.tls = true
+	u, err = cs.FirmwareURL("cp-1", "fw.bin")
+	require.NoError(t, err)
+	assert.Equal(t, "https://192.168.0.2:8887/ocpp/firmware/fw.bin", u)
+
+	for _, tc := range []struct{ id, file string }{
+		{"cp-1", "missing.bin"},
+		{"cp-1", "../fw.bin"},
+		{"cp-2", "fw.bin"},
+	} {
+		_, err := cs.FirmwareURL(tc.id, tc.file)
+		assert.Error(t, err, tc)
+	}
+}
+
+func TestServeFirmware(t *testing.T) {
+	dir := t.TempDir()
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "fw.bin"), []byte("firmware"), 0o644))
+
+	cs := &CS{
+		log:         util.NewLogger("ocpp"),
+		firmwareDir: dir,
+	}
+
+	listen := true
+	srv := httptest.NewServer(cs.serveFirmware(func(net.Addr) bool { return listen }))
+	defer srv.Close()
+
+	resp, err := http.Get(srv.URL + "/ocpp/firmware/fw.bin")
+	require.NoError(t, err)
+	resp.Body.Close()
+	assert.Equal(t, http.StatusOK, resp.StatusCode)
+
+	resp, err = http.Get(srv.URL + "/ocpp/firmware/missing.bin")
+	require.NoError(t, err)
+	resp.Body.Close()
+	assert.Equal(t, http.StatusNotFound, resp.StatusCode)
+
+	// not served on other listeners
+	listen = false
+	resp, err = http.Get(srv.URL + "/ocpp/firmware/fw.bin")
+	require.NoError(t, err)
+	resp.Body.Close()
+	assert.Equal(t, http.StatusNotFound, resp.StatusCode)
+}
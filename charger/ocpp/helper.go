@@ -12,12 +12,19 @@ import (
 	"github.com/lorenzodonini/ocpp-go/ocppj"
 )
 
-// wait waits for a CP roundtrip with timeout
+// wait waits for a CP roundtrip with timeout. Requests are answered or failed by the dispatcher
+// within queueing and response timeout, the deadline guards against callbacks never called.
 func wait(err error, rc chan error) error {
 	if err == nil {
+		timer := time.NewTimer(2 * Timeout)
+		defer timer.Stop()
+
 		select {
 		case err = <-rc:
 			close(rc)
+		case <-timer.C:
+			// rc is buffered, a late callback does not block
+			err = api.ErrTimeout
 		}
 
 		if oe := new(ocpp.Error); errors.As(err, &oe) && oe.Code == ocppj.GenericError {
@@ -27,6 +34,15 @@ func wait(err error, rc chan error) error {
 	return err
 }
 
+// notSupported checks if the request failed since the charge point does not support it
+func notSupported(err error) bool {
+	if oe := new(ocpp.Error); errors.As(err, &oe) {
+		return oe.Code == ocppj.NotImplemented || oe.Code == ocppj.NotSupported
+	}
+
+	return err != nil && err.Error() == "NotSupported"
+}
+
 func sortByAge(values []types.MeterValue) []types.MeterValue {
 	return slices.SortedFunc(slices.Values(values), func(a, b types.MeterValue) int {
 		var at, bt time.Time
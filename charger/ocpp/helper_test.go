@@ -21,3 +21,9 @@ func TestSortByAge(t *testing.T) {
 		{Timestamp: types.NewDateTime(time.UnixMilli(2))},
 	}))
 }
+
+func TestLogged(t *testing.T) {
+	assert.True(t, logged("sent JSON message to %s: %s"))
+	assert.True(t, logged("received JSON message from %s: %s"))
+	assert.False(t, logged("handling incoming CALL [%s, %s] from %s"))
+}
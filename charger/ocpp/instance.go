@@ -1,69 +1,366 @@
 package ocpp
 
 import (
+	"cmp"
+	"errors"
+	"net"
 	"net/http"
+	"path"
+	"strings"
 	"sync"
+	"sync/atomic"
 	"time"
 
 	"github.com/evcc-io/evcc/util"
 	"github.com/lorenzodonini/ocpp-go/ocpp"
 	ocpp16 "github.com/lorenzodonini/ocpp-go/ocpp1.6"
 	"github.com/lorenzodonini/ocpp-go/ocpp1.6/core"
+	"github.com/lorenzodonini/ocpp-go/ocpp1.6/firmware"
+	"github.com/lorenzodonini/ocpp-go/ocpp1.6/localauth"
 	"github.com/lorenzodonini/ocpp-go/ocpp1.6/remotetrigger"
+	"github.com/lorenzodonini/ocpp-go/ocpp1.6/reservation"
 	"github.com/lorenzodonini/ocpp-go/ocpp1.6/security"
 	"github.com/lorenzodonini/ocpp-go/ocpp1.6/smartcharging"
+	ocpp2 "github.com/lorenzodonini/ocpp-go/ocpp2.0.1"
+	"github.com/lorenzodonini/ocpp-go/ocpp2.0.1/authorization"
+	"github.com/lorenzodonini/ocpp-go/ocpp2.0.1/availability"
+	"github.com/lorenzodonini/ocpp-go/ocpp2.0.1/data"
+	"github.com/lorenzodonini/ocpp-go/ocpp2.0.1/diagnostics"
+	firmware201 "github.com/lorenzodonini/ocpp-go/ocpp2.0.1/firmware"
+	localauth201 "github.com/lorenzodonini/ocpp-go/ocpp2.0.1/localauth"
+	"github.com/lorenzodonini/ocpp-go/ocpp2.0.1/meter"
+	"github.com/lorenzodonini/ocpp-go/ocpp2.0.1/provisioning"
+	"github.com/lorenzodonini/ocpp-go/ocpp2.0.1/remotecontrol"
+	reservation201 "github.com/lorenzodonini/ocpp-go/ocpp2.0.1/reservation"
+	security201 "github.com/lorenzodonini/ocpp-go/ocpp2.0.1/security"
+	smartcharging201 "github.com/lorenzodonini/ocpp-go/ocpp2.0.1/smartcharging"
+	"github.com/lorenzodonini/ocpp-go/ocpp2.0.1/transactions"
 	"github.com/lorenzodonini/ocpp-go/ocppj"
 	"github.com/lorenzodonini/ocpp-go/ws"
 )
 
+// DefaultPort is the central system's default listen port
+const DefaultPort = 8887
+
 var (
 	once     sync.Once
 	instance *CS
+	running  atomic.Bool
 )
 
+type config struct {
+	port                      int
+	listen                    []string
+	prefix                    string
+	certFile, keyFile, caFile string
+	certs                     *certificates
+	secret                    string
+	signer                    Signer
+	firmwareDir               string
+	diagnosticsDir            string
+	traceDir                  string
+	authorization             AuthorizationPolicy
+	shutdown                  ShutdownAction
+	queueSize                 int
+	queuePolicy               QueuePolicy
+}
+
+// Option configures the central system
+type Option func(*config)
+
+// WithPort sets the listen port
+func WithPort(port int) Option {
+	return func(c *config) {
+		if port != 0 {
+			c.port = port
+		}
+	}
+}
+
+// WithListen restricts connections to the given ip addresses instead of the global listen addresses
+func WithListen(addrs ...string) Option {
+	return func(c *config) {
+		c.listen = addrs
+	}
+}
+
+// WithPrefix sets the url prefix preceding the station id, e.g. for running behind a reverse proxy
+func WithPrefix(prefix string) Option {
+	return func(c *config) {
+		c.prefix = prefix
+	}
+}
+
+// WithTLS serves wss:// using the given certificate and key files. Client certificates are verified
+// against the certificate authorities file if given. Files are reloaded when changed.
+func WithTLS(certFile, keyFile, caFile string) Option {
+	return func(c *config) {
+		c.certFile, c.keyFile, c.caFile = certFile, keyFile, caFile
+	}
+}
+
+// WithSecret requires charge points without credentials to authenticate using the shared password
+func WithSecret(password string) Option {
+	return func(c *config) {
+		c.secret = password
+	}
+}
+
+// WithSigner signs certificate signing requests of charge points instead of rejecting them
+func WithSigner(signer Signer) Option {
+	return func(c *config) {
+		c.signer = signer
+	}
+}
+
+// WithFirmware serves the directory's firmware files to charge points at <prefix>/firmware/<file>
+func WithFirmware(dir string) Option {
+	return func(c *config) {
+		c.firmwareDir = dir
+	}
+}
+
+// WithDiagnostics stores diagnostics files uploaded by charge points to <prefix>/diagnostics/<station id> in the directory
+func WithDiagnostics(dir string) Option {
+	return func(c *config) {
+		c.diagnosticsDir = dir
+	}
+}
+
+// WithTrace records all messages to rotating <station id>.jsonl files in the directory
+func WithTrace(dir string) Option {
+	return func(c *config) {
+		c.traceDir = dir
+	}
+}
+
+// WithAuthorization sets the policy for id tags presented at charge points
+func WithAuthorization(policy AuthorizationPolicy) Option {
+	return func(c *config) {
+		c.authorization = policy
+	}
+}
+
+// WithQueue limits the requests queued per charge point, applying the policy to new requests when full
+func WithQueue(size int, policy QueuePolicy) Option {
+	return func(c *config) {
+		c.queueSize, c.queuePolicy = size, policy
+	}
+}
+
+// WithShutdown sets the action applied to running transactions when the central system stops
+func WithShutdown(action ShutdownAction) Option {
+	return func(c *config) {
+		c.shutdown = action
+	}
+}
+
+// listenPath returns the websocket route including the station id
+func (c *config) listenPath() string {
+	return path.Join("/", strings.Trim(c.prefix, "/"), "{ws}")
+}
+
+func (c *config) firmwarePath() string {
+	return path.Join("/", strings.Trim(c.prefix, "/"), "firmware")
+}
+
+func (c *config) diagnosticsPath() string {
+	return path.Join("/", strings.Trim(c.prefix, "/"), "diagnostics")
+}
+
+// Start starts the central system with the given options.
+// It must be called before the central system is first used by a charger.
+func Start(opts ...Option) error {
+	conf := config{port: DefaultPort}
+	for _, o := range opts {
+		o(&conf)
+	}
+
+	log := util.NewLogger("ocpp")
+
+	if conf.certFile != "" || conf.keyFile != "" {
+		certs, err := newCertificates(log, conf.certFile, conf.keyFile, conf.caFile)
+		if err != nil {
+			return err
+		}
+		conf.certs = certs
+	}
+
+	var started bool
+	once.Do(func() {
+		instance = start(log, conf)
+		running.Store(true)
+		started = true
+	})
+
+	if !started {
+		return errors.New("central system already started")
+	}
+
+	return nil
+}
+
+// Instance returns the central system, starting it with default options if required
 func Instance() *CS {
 	once.Do(func() {
-		log := util.NewLogger("ocpp")
+		instance = start(util.NewLogger("ocpp"), config{port: DefaultPort})
+		running.Store(true)
+	})
 
-		server := ws.NewServer()
-		server.SetCheckOriginHandler(func(r *http.Request) bool { return true })
+	return instance
+}
 
-		dispatcher := ocppj.NewDefaultServerDispatcher(ocppj.NewFIFOQueueMap(0))
-		dispatcher.SetTimeout(Timeout)
+// ChargepointByID returns the configured charge point without starting the central system
+func ChargepointByID(id string) (*CP, error) {
+	if !running.Load() {
+		return nil, errors.New("central system not running")
+	}
 
-		endpoint := ocppj.NewServer(server, dispatcher, nil, core.Profile, remotetrigger.Profile, smartcharging.Profile, security.Profile)
-		endpoint.SetInvalidMessageHook(func(client ws.Channel, err *ocpp.Error, rawMessage string, parsedFields []any) *ocpp.Error {
-			log.ERROR.Printf("%v (%s)", err, rawMessage)
-			return nil
-		})
+	return Instance().ChargepointByID(id)
+}
+
+func start(log *util.Logger, conf config) *CS {
+	var opts []ws.ServerOpt
+	if conf.certs != nil {
+		opts = append(opts, ws.WithServerTLSConfig(conf.certFile, conf.keyFile, conf.certs.TLSConfig()))
+	}
+
+	server := ws.NewServer(opts...)
+	server.SetCheckOriginHandler(func(r *http.Request) bool { return true })
 
-		cs := ocpp16.NewCentralSystem(endpoint, server)
+	res := &CS{
+		log:             log,
+		regs:            make(map[string]*registration),
+		credentials:     make(map[string]credentials),
+		supplied:        make(map[string]credentials),
+		secret:          conf.secret,
+		signer:          conf.signer,
+		hosts:           make(map[string]string),
+		tls:             conf.certs != nil,
+		firmwareDir:     conf.firmwareDir,
+		firmwarePath:    conf.firmwarePath(),
+		diagnosticsDir:  conf.diagnosticsDir,
+		diagnosticsPath: conf.diagnosticsPath(),
+		uploads:         make(map[string]diagnosticsUpload),
+		authorization:   conf.authorization,
+		shutdown:        conf.shutdown,
+	}
 
-		instance = &CS{
-			log:           log,
-			regs:          make(map[string]*registration),
-			CentralSystem: cs,
+	if conf.traceDir != "" {
+		res.tracer = newTracer(log, conf.traceDir)
+	}
+
+	// websocket server always binds all interfaces, reject connections on other addresses
+	isListenAddr := util.IsListenAddr
+	if len(conf.listen) > 0 {
+		isListenAddr = func(addr net.Addr) bool {
+			return util.MatchAddr(addr, conf.listen)
 		}
+	}
 
-		instance.txnId.Store(time.Now().UTC().Unix())
+	mux := newMux(server, func(id string, r *http.Request) bool {
+		addr, _ := r.Context().Value(http.LocalAddrContextKey).(net.Addr)
+		if !isListenAddr(addr) || !res.authorize(id, r) {
+			return false
+		}
 
-		ocppj.SetLogger(instance)
+		res.setHost(id, r)
+		return true
+	})
 
-		cs.SetCoreHandler(instance)
-		cs.SetSecurityHandler(instance)
-		cs.SetNewChargePointHandler(instance.NewChargePoint)
-		cs.SetChargePointDisconnectedHandler(instance.ChargePointDisconnected)
+	mux.received = res.received
 
-		go instance.errorHandler(cs.Errors())
-		go cs.Start(8887, "/{ws}")
+	// firmware and diagnostics are handled by the websocket server's router
+	if hs, ok := server.(interface {
+		AddHttpHandler(string, func(http.ResponseWriter, *http.Request))
+	}); ok {
+		if conf.firmwareDir != "" {
+			hs.AddHttpHandler(path.Join(conf.firmwarePath(), "{file}"), res.serveFirmware(isListenAddr))
+		}
 
-		// wait for server to start
-		for range time.Tick(10 * time.Millisecond) {
-			if dispatcher.IsRunning() {
-				break
-			}
+		if conf.diagnosticsDir != "" {
+			hs.AddHttpHandler(path.Join(conf.diagnosticsPath(), "{id}", "{token}"), res.receiveDiagnostics(isListenAddr))
+			hs.AddHttpHandler(path.Join(conf.diagnosticsPath(), "{id}", "{token}", "{file}"), res.receiveDiagnostics(isListenAddr))
 		}
-	})
+	}
 
-	return instance
+	invalidMessageHook := func(client ws.Channel, err *ocpp.Error, rawMessage string, parsedFields []any) *ocpp.Error {
+		log.ERROR.Printf("%v (%s)", err, rawMessage)
+		return nil
+	}
+
+	// requests waiting longer than the response timeout are failed instead of being sent late
+	queueSize := cmp.Or(conf.queueSize, DefaultQueueSize)
+
+	// ocpp 1.6
+	queues := newQueueMap(log, queueSize, conf.queuePolicy, Timeout)
+	dispatcher := newDispatcher(queues)
+	dispatcher.SetTimeout(Timeout)
+
+	server16 := mux.Endpoint(ProtocolV16)
+	endpoint := ocppj.NewServer(server16, dispatcher, nil, core.Profile, remotetrigger.Profile, smartcharging.Profile, security.Profile, firmware.Profile, localauth.Profile, reservation.Profile)
+	endpoint.SetInvalidMessageHook(invalidMessageHook)
+
+	cs := ocpp16.NewCentralSystem(endpoint, server16)
+
+	// ocpp 2.0.1
+	queues201 := newQueueMap(log, queueSize, conf.queuePolicy, Timeout)
+	dispatcher201 := newDispatcher(queues201)
+	dispatcher201.SetTimeout(Timeout)
+
+	server201 := mux.Endpoint(ProtocolV201)
+	endpoint201 := ocppj.NewServer(server201, dispatcher201, nil,
+		provisioning.Profile, availability.Profile, transactions.Profile, meter.Profile,
+		authorization.Profile, remotecontrol.Profile, smartcharging201.Profile, security201.Profile, data.Profile, firmware201.Profile, diagnostics.Profile, localauth201.Profile, reservation201.Profile)
+	endpoint201.SetInvalidMessageHook(invalidMessageHook)
+
+	csms := ocpp2.NewCSMS(endpoint201, server201)
+
+	res.CentralSystem = cs
+	res.csms = csms
+	res.server = mux
+	res.queues = []*queueMap{queues, queues201}
+
+	res.txnId.Store(time.Now().UTC().Unix())
+
+	ocppj.SetLogger(res)
+
+	cs.SetCoreHandler(res)
+	cs.SetSecurityHandler(res)
+	cs.SetFirmwareManagementHandler(res)
+	cs.SetNewChargePointHandler(res.NewChargePoint)
+	cs.SetChargePointDisconnectedHandler(res.ChargePointDisconnected)
+
+	handler := &csms201{res}
+	csms.SetProvisioningHandler(handler)
+	csms.SetAvailabilityHandler(handler)
+	csms.SetTransactionsHandler(handler)
+	csms.SetMeterHandler(handler)
+	csms.SetAuthorizationHandler(handler)
+	csms.SetSecurityHandler(handler)
+	csms.SetDataHandler(handler)
+	csms.SetFirmwareHandler(handler)
+	csms.SetDiagnosticsHandler(handler)
+	csms.SetReservationHandler(handler)
+	csms.SetNewChargingStationHandler(res.NewChargingStation)
+	csms.SetChargingStationDisconnectedHandler(res.ChargingStationDisconnected)
+
+	go res.errorHandler(cs.Errors())
+	go res.errorHandler(csms.Errors())
+	res.watchHeartbeats()
+
+	// websocket server is started by the last endpoint
+	go cs.Start(conf.port, conf.listenPath())
+	go csms.Start(conf.port, conf.listenPath())
+
+	// wait for server to start
+	for range time.Tick(10 * time.Millisecond) {
+		if dispatcher.IsRunning() && dispatcher201.IsRunning() {
+			break
+		}
+	}
+
+	log.DEBUG.Printf("listening at :%d%s", conf.port, conf.listenPath())
+
+	return res
 }
@@ -0,0 +1,22 @@
+package ocpp
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestListenPath(t *testing.T) {
+	for _, tc := range []struct {
+		prefix, path string
+	}{
+		{"", "/{ws}"},
+		{"/", "/{ws}"},
+		{"ocpp", "/ocpp/{ws}"},
+		{"/evcc/ocpp/", "/evcc/ocpp/{ws}"},
+	} {
+		c := config{port: DefaultPort}
+		WithPrefix(tc.prefix)(&c)
+		assert.Equal(t, tc.path, c.listenPath(), tc.prefix)
+	}
+}
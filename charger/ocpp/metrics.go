@@ -0,0 +1,115 @@
+package ocpp
+
+import (
+	"strconv"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+var (
+	messageMetric     *prometheus.CounterVec
+	errorMetric       *prometheus.CounterVec
+	transactionMetric *prometheus.CounterVec
+
+	connectedDesc = prometheus.NewDesc("evcc_ocpp_chargepoints_connected", "Number of connected charge points", []string{"configured"}, nil)
+	queueDesc     = prometheus.NewDesc("evcc_ocpp_dispatcher_queue_depth", "Requests queued for the charge point", []string{"station"}, nil)
+	meterAgeDesc  = prometheus.NewDesc("evcc_ocpp_meter_value_age_seconds", "Age of the connector's last meter value", []string{"station", "connector"}, nil)
+)
+
+func init() {
+	messageMetric = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: "evcc",
+		Subsystem: "ocpp",
+		Name:      "messages_total",
+		Help:      "Total count of OCPP messages",
+	}, []string{"direction", "type", "action"})
+
+	errorMetric = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: "evcc",
+		Subsystem: "ocpp",
+		Name:      "call_errors_total",
+		Help:      "Total count of OCPP call errors",
+	}, []string{"direction", "action", "code"})
+
+	transactionMetric = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: "evcc",
+		Subsystem: "ocpp",
+		Name:      "transactions_total",
+		Help:      "Total count of OCPP transaction events",
+	}, []string{"event"})
+
+	prometheus.MustRegister(messageMetric, errorMetric, transactionMetric, new(collector))
+}
+
+// countMessage updates the message metrics
+func countMessage(msg TraceMessage) {
+	dir := map[string]string{TraceRecv: "in", TraceSend: "out"}[msg.Direction]
+
+	switch msg.Type {
+	case "":
+		return
+	case MessageCallError:
+		errorMetric.WithLabelValues(dir, msg.Action, msg.ErrorCode).Inc()
+	}
+
+	messageMetric.WithLabelValues(dir, msg.Type, msg.Action).Inc()
+}
+
+// collector collects the central system's state when scraped
+type collector struct{}
+
+func (c *collector) Describe(ch chan<- *prometheus.Desc) {
+	ch <- connectedDesc
+	ch <- queueDesc
+	ch <- meterAgeDesc
+}
+
+func (c *collector) Collect(ch chan<- prometheus.Metric) {
+	if !running.Load() {
+		return
+	}
+
+	cs := Instance()
+
+	var connected, pending int
+	for _, p := range PendingChargePoints() {
+		if p.Connected {
+			pending++
+		}
+	}
+
+	for _, cp := range cs.chargepoints() {
+		if cp.Connected() {
+			connected++
+		}
+
+		var depth int
+		for _, queues := range cs.queues {
+			if q, ok := queues.Get(cp.ID()); ok {
+				depth += q.Size()
+			}
+		}
+		ch <- prometheus.MustNewConstMetric(queueDesc, prometheus.GaugeValue, float64(depth), cp.ID())
+
+		cp.mu.RLock()
+		conns := make([]*Connector, 0, len(cp.connectors))
+		for _, conn := range cp.connectors {
+			conns = append(conns, conn)
+		}
+		cp.mu.RUnlock()
+
+		for _, conn := range conns {
+			conn.mu.Lock()
+			updated := conn.meterUpdated
+			conn.mu.Unlock()
+
+			if !updated.IsZero() {
+				ch <- prometheus.MustNewConstMetric(meterAgeDesc, prometheus.GaugeValue, time.Since(updated).Seconds(), cp.ID(), strconv.Itoa(conn.id))
+			}
+		}
+	}
+
+	ch <- prometheus.MustNewConstMetric(connectedDesc, prometheus.GaugeValue, float64(connected), "true")
+	ch <- prometheus.MustNewConstMetric(connectedDesc, prometheus.GaugeValue, float64(pending), "false")
+}
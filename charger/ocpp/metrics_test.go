@@ -0,0 +1,37 @@
+package ocpp
+
+import (
+	"testing"
+
+	"github.com/prometheus/client_golang/prometheus"
+	dto "github.com/prometheus/client_model/go"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func counterValue(t *testing.T, c prometheus.Counter) float64 {
+	var m dto.Metric
+	require.NoError(t, c.Write(&m))
+	return m.GetCounter().GetValue()
+}
+
+func TestMessageMetrics(t *testing.T) {
+	count := func(direction, message string) {
+		countMessage(newTraceMessage("metrics", direction, message))
+	}
+
+	count(TraceRecv, `[2,"1","Heartbeat",{}]`)
+	count(TraceSend, `[3,"1",{"currentTime":"2026-01-01T00:00:00Z"}]`)
+
+	count(TraceSend, `[2,"a","RemoteStartTransaction",{"idTag":"tag"}]`)
+	count(TraceRecv, `[4,"a","NotSupported","",{}]`)
+
+	// invalid messages are ignored
+	count(TraceRecv, `invalid`)
+
+	assert.Equal(t, 1.0, counterValue(t, messageMetric.WithLabelValues("in", "call", "Heartbeat")))
+	assert.Equal(t, 1.0, counterValue(t, messageMetric.WithLabelValues("out", "result", "Heartbeat")))
+	assert.Equal(t, 1.0, counterValue(t, messageMetric.WithLabelValues("in", "error", "RemoteStartTransaction")))
+	assert.Equal(t, 1.0, counterValue(t, errorMetric.WithLabelValues("in", "RemoteStartTransaction", "NotSupported")))
+	assert.Empty(t, pendingCalls)
+}
@@ -0,0 +1,214 @@
+package ocpp
+
+import (
+	"bytes"
+	"net/http"
+	"sync"
+
+	"github.com/gorilla/websocket"
+	"github.com/lorenzodonini/ocpp-go/ws"
+)
+
+// mux shares a single websocket server between ocpp protocol versions.
+// The subprotocol negotiated during connection setup determines the endpoint receiving the client's messages.
+type mux struct {
+	ws.Server
+	mu        sync.Mutex
+	check     ws.CheckClientHandler
+	endpoints map[string]*muxEndpoint
+	clients   map[string]string // client id to negotiated protocol
+	started   int
+	received  func(id string) // called for every message received
+}
+
+// muxEndpoint is the websocket server as seen by a single protocol's ocppj endpoint
+type muxEndpoint struct {
+	ws.Server
+	mux          *mux
+	onMessage    ws.MessageHandler
+	onConnect    ws.ConnectedHandler
+	onDisconnect func(ws.Channel)
+
+	sentMu sync.Mutex
+	sent   map[string][]byte // last request written per client
+}
+
+func newMux(server ws.Server, check ws.CheckClientHandler) *mux {
+	m := &mux{
+		Server:    server,
+		check:     check,
+		endpoints: make(map[string]*muxEndpoint),
+		clients:   make(map[string]string),
+	}
+
+	server.SetCheckClientHandler(m.checkClient)
+	server.SetMessageHandler(m.message)
+	server.SetNewClientHandler(m.connect)
+	server.SetDisconnectedClientHandler(m.disconnect)
+
+	return m
+}
+
+// Endpoint returns the websocket server for given protocol
+func (m *mux) Endpoint(protocol string) ws.Server {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	ep := &muxEndpoint{Server: m.Server, mux: m}
+	m.endpoints[protocol] = ep
+	m.Server.AddSupportedSubprotocol(protocol)
+
+	return ep
+}
+
+// negotiate replicates the websocket server's subprotocol selection
+func (m *mux) negotiate(r *http.Request) string {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	for _, proto := range websocket.Subprotocols(r) {
+		if _, ok := m.endpoints[proto]; ok {
+			return proto
+		}
+	}
+
+	return ""
+}
+
+func (m *mux) checkClient(id string, r *http.Request) bool {
+	// duplicate connections are rejected by the server, don't overwrite the existing client's state
+	if _, ok := m.Server.GetChannel(id); ok {
+		return false
+	}
+
+	if m.check != nil && !m.check(id, r) {
+		return false
+	}
+
+	// unsupported protocols are rejected by the server
+	if proto := m.negotiate(r); proto != "" {
+		m.mu.Lock()
+		m.clients[id] = proto
+		m.mu.Unlock()
+	}
+
+	return true
+}
+
+// endpoint returns the client's endpoint
+func (m *mux) endpoint(id string) *muxEndpoint {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	return m.endpoints[m.clients[id]]
+}
+
+func (m *mux) message(c ws.Channel, data []byte) error {
+	if m.received != nil {
+		m.received(c.ID())
+	}
+
+	if ep := m.endpoint(c.ID()); ep != nil && ep.onMessage != nil {
+		return ep.onMessage(c, data)
+	}
+	return nil
+}
+
+func (m *mux) connect(c ws.Channel) {
+	if ep := m.endpoint(c.ID()); ep != nil && ep.onConnect != nil {
+		ep.onConnect(c)
+	}
+}
+
+func (m *mux) disconnect(c ws.Channel) {
+	if ep := m.endpoint(c.ID()); ep != nil {
+		if ep.onDisconnect != nil {
+			ep.onDisconnect(c)
+		}
+		ep.forget(c.ID())
+	}
+
+	m.mu.Lock()
+	delete(m.clients, c.ID())
+	m.mu.Unlock()
+}
+
+// Start starts the websocket server once all endpoints have been started. It blocks for the last endpoint only.
+func (ep *muxEndpoint) Start(port int, listenPath string) {
+	m := ep.mux
+
+	m.mu.Lock()
+	m.started++
+	last := m.started == len(m.endpoints)
+	m.mu.Unlock()
+
+	if last {
+		m.Server.Start(port, listenPath)
+	}
+}
+
+func (ep *muxEndpoint) SetMessageHandler(handler ws.MessageHandler) {
+	ep.mux.mu.Lock()
+	defer ep.mux.mu.Unlock()
+	ep.onMessage = handler
+}
+
+func (ep *muxEndpoint) SetNewClientHandler(handler ws.ConnectedHandler) {
+	ep.mux.mu.Lock()
+	defer ep.mux.mu.Unlock()
+	ep.onConnect = handler
+}
+
+func (ep *muxEndpoint) SetDisconnectedClientHandler(handler func(ws.Channel)) {
+	ep.mux.mu.Lock()
+	defer ep.mux.mu.Unlock()
+	ep.onDisconnect = handler
+}
+
+// Write fails requests dropped from the request queue instead of sending them.
+//
+// Repeated requests are not sent again. This works around the ocpp-go DefaultServerDispatcher dispatching a request
+// twice after a failed write: dispatchNextRequest completes the failed request from within messagePump, which signals
+// readyForDispatch. If requests pushed meanwhile are pending on the request channel, the pump finds no active timeout
+// context, dispatches the next request and then dispatches it again on the stale ready signal. Request ids are unique,
+// so an identical request message written again to the same client is always such a repeated dispatch.
+func (ep *muxEndpoint) Write(id string, data []byte) error {
+	if len(data) == 0 {
+		return errRequestDropped
+	}
+
+	if bytes.HasPrefix(data, []byte("[2,")) {
+		ep.sentMu.Lock()
+		repeated := bytes.Equal(ep.sent[id], data)
+		if ep.sent == nil {
+			ep.sent = make(map[string][]byte)
+		}
+		ep.sent[id] = data
+		ep.sentMu.Unlock()
+
+		if repeated {
+			return nil
+		}
+	}
+
+	return ep.Server.Write(id, data)
+}
+
+// forget removes the client's last request
+func (ep *muxEndpoint) forget(id string) {
+	ep.sentMu.Lock()
+	defer ep.sentMu.Unlock()
+
+	delete(ep.sent, id)
+}
+
+// SetCheckClientHandler is ignored, clients are checked by the mux
+func (ep *muxEndpoint) SetCheckClientHandler(handler ws.CheckClientHandler) {}
+
+// AddSupportedSubprotocol is ignored, protocols are registered by the mux
+func (ep *muxEndpoint) AddSupportedSubprotocol(subProto string) {}
+
+// Stop is ignored, the shared server is never stopped
+func (ep *muxEndpoint) Stop() {}
+
+var _ ws.Server = (*muxEndpoint)(nil)
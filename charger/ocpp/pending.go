@@ -0,0 +1,107 @@
+package ocpp
+
+import (
+	"maps"
+	"sync"
+	"time"
+
+	"github.com/lorenzodonini/ocpp-go/ocpp1.6/core"
+)
+
+// PendingChargePoint is an unconfigured charge point that has connected to the central system
+type PendingChargePoint struct {
+	Protocol  string    `json:"protocol"`
+	Vendor    string    `json:"vendor,omitempty"`
+	Model     string    `json:"model,omitempty"`
+	Serial    string    `json:"serial,omitempty"`
+	Firmware  string    `json:"firmware,omitempty"`
+	Connected bool      `json:"connected"`
+	Seen      time.Time `json:"seen"`
+}
+
+var (
+	pendingMu      sync.Mutex
+	pendingHandler func(id string)
+	pending        = make(map[string]PendingChargePoint)
+)
+
+// SetPendingHandler registers the handler receiving the ids of added, updated or removed pending charge points
+func SetPendingHandler(fun func(id string)) {
+	pendingMu.Lock()
+	defer pendingMu.Unlock()
+	pendingHandler = fun
+}
+
+// updatePending updates the pending charge point if fun returns true
+func updatePending(id string, fun func(p *PendingChargePoint, ok bool) bool) {
+	pendingMu.Lock()
+
+	res, ok := pending[id]
+	if !fun(&res, ok) {
+		pendingMu.Unlock()
+		return
+	}
+	pending[id] = res
+
+	handler := pendingHandler
+	pendingMu.Unlock()
+
+	if handler != nil {
+		handler(id)
+	}
+}
+
+// removePending removes the charge point once it has been configured
+func removePending(id string) {
+	pendingMu.Lock()
+
+	_, ok := pending[id]
+	delete(pending, id)
+
+	handler := pendingHandler
+	pendingMu.Unlock()
+
+	if ok && handler != nil {
+		handler(id)
+	}
+}
+
+// PendingChargePoints returns the unconfigured charge points by id
+func PendingChargePoints() map[string]PendingChargePoint {
+	pendingMu.Lock()
+	defer pendingMu.Unlock()
+
+	return maps.Clone(pending)
+}
+
+// pendingConnected records the unconfigured charge point's connection state
+func pendingConnected(id, protocol string, connected bool) {
+	updatePending(id, func(p *PendingChargePoint, ok bool) bool {
+		// disconnect of configured charge point
+		if !ok && !connected {
+			return false
+		}
+
+		if protocol != "" {
+			p.Protocol = protocol
+		}
+		p.Connected = connected
+		p.Seen = time.Now()
+
+		return true
+	})
+}
+
+// pendingBooted records the unconfigured charge point's details from its boot notification
+func pendingBooted(id string, request *core.BootNotificationRequest) {
+	updatePending(id, func(p *PendingChargePoint, _ bool) bool {
+		p.Vendor = request.ChargePointVendor
+		p.Model = request.ChargePointModel
+		p.Serial = request.ChargePointSerialNumber
+		p.Firmware = request.FirmwareVersion
+		p.Connected = true
+		p.Seen = time.Now()
+
+		return true
+	})
+}
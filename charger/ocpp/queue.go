@@ -0,0 +1,334 @@
+package ocpp
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/evcc-io/evcc/util"
+	"github.com/lorenzodonini/ocpp-go/ocpp"
+	"github.com/lorenzodonini/ocpp-go/ocppj"
+)
+
+// DefaultQueueSize is the number of requests queued per charge point unless configured
+const DefaultQueueSize = 20
+
+// QueuePolicy is applied to new requests when the charge point's request queue is full
+type QueuePolicy string
+
+const (
+	QueueReject QueuePolicy = "reject" // fail the new request
+	QueueDrop   QueuePolicy = "drop"   // fail the oldest waiting request
+)
+
+// ParseQueuePolicy validates the policy, empty policy is returned unchanged
+func ParseQueuePolicy(s string) (QueuePolicy, error) {
+	switch res := QueuePolicy(strings.ToLower(s)); res {
+	case "", QueueReject, QueueDrop:
+		return res, nil
+	default:
+		return "", fmt.Errorf("invalid queue policy: %s", s)
+	}
+}
+
+var (
+	// errRequestDropped fails dropped requests when they are due to be sent
+	errRequestDropped = errors.New("request dropped from queue")
+
+	// errRequestFlushed fails requests queued for a charge point when it disconnects
+	errRequestFlushed = errors.New("charge point disconnected, request not answered")
+)
+
+type queuedRequest struct {
+	bundle  ocppj.RequestBundle
+	queued  time.Time
+	dropped bool
+}
+
+// requestQueue is a bounded request queue per charge point. The central system matches responses to
+// requests in order, therefore dropped requests remain queued and are failed by the endpoint instead of
+// being sent once due. Requests waiting longer than maxAge, e.g. behind requests of an unresponsive
+// charge point, are failed the same way instead of being sent late.
+type requestQueue struct {
+	mu       sync.Mutex
+	size     int
+	policy   QueuePolicy
+	maxAge   time.Duration
+	elements []*queuedRequest
+}
+
+var _ ocppj.RequestQueue = (*requestQueue)(nil)
+
+func newRequestQueue(size int, policy QueuePolicy, maxAge time.Duration) *requestQueue {
+	return &requestQueue{
+		size:   size,
+		policy: policy,
+		maxAge: maxAge,
+	}
+}
+
+// live returns the number of requests not dropped
+func (q *requestQueue) live() int {
+	var res int
+	for _, e := range q.elements {
+		if !e.dropped {
+			res++
+		}
+	}
+	return res
+}
+
+func (q *requestQueue) Init() {
+	q.flush()
+}
+
+// flush empties the queue and returns the queued requests
+func (q *requestQueue) flush() []ocppj.RequestBundle {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+
+	res := make([]ocppj.RequestBundle, 0, len(q.elements))
+	for _, e := range q.elements {
+		res = append(res, e.bundle)
+	}
+	q.elements = nil
+
+	return res
+}
+
+func (q *requestQueue) Push(element any) error {
+	bundle, ok := element.(ocppj.RequestBundle)
+	if !ok {
+		return fmt.Errorf("invalid request: %T", element)
+	}
+
+	q.mu.Lock()
+	defer q.mu.Unlock()
+
+	if q.size > 0 && q.live() >= q.size {
+		e := q.droppable()
+		if e == nil {
+			return fmt.Errorf("request queue full: %d requests", q.size)
+		}
+
+		e.dropped = true
+		e.bundle.Data = nil
+	}
+
+	q.elements = append(q.elements, &queuedRequest{
+		bundle: bundle,
+		queued: time.Now(),
+	})
+
+	return nil
+}
+
+// droppable returns the oldest waiting request if the policy allows dropping it
+func (q *requestQueue) droppable() *queuedRequest {
+	if q.policy != QueueDrop {
+		return nil
+	}
+
+	// the first request may already have been sent
+	for _, e := range q.elements[min(1, len(q.elements)):] {
+		if !e.dropped {
+			return e
+		}
+	}
+
+	return nil
+}
+
+// Peek returns the first request. Dropped or outdated requests are returned without data for the endpoint to fail them.
+func (q *requestQueue) Peek() any {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+
+	if len(q.elements) == 0 {
+		return nil
+	}
+
+	e := q.elements[0]
+	if e.dropped || q.maxAge > 0 && time.Since(e.queued) > q.maxAge {
+		return ocppj.RequestBundle{Call: e.bundle.Call}
+	}
+
+	return e.bundle
+}
+
+func (q *requestQueue) Pop() any {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+
+	if len(q.elements) == 0 {
+		return nil
+	}
+
+	e := q.elements[0]
+	q.elements = q.elements[1:]
+
+	return e.bundle
+}
+
+func (q *requestQueue) Size() int {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+
+	return len(q.elements)
+}
+
+func (q *requestQueue) IsFull() bool {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+
+	return q.size > 0 && q.live() >= q.size && q.droppable() == nil
+}
+
+func (q *requestQueue) IsEmpty() bool {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+
+	return len(q.elements) == 0
+}
+
+// queueMap creates the bounded request queues per charge point
+type queueMap struct {
+	mu      sync.RWMutex
+	log     *util.Logger
+	size    int
+	policy  QueuePolicy
+	maxAge  time.Duration
+	queues  map[string]ocppj.RequestQueue
+	onFlush func(id string, bundles []ocppj.RequestBundle) // fails flushed requests
+}
+
+var _ ocppj.ServerQueueMap = (*queueMap)(nil)
+
+func newQueueMap(log *util.Logger, size int, policy QueuePolicy, maxAge time.Duration) *queueMap {
+	return &queueMap{
+		log:    log,
+		size:   size,
+		policy: policy,
+		maxAge: maxAge,
+		queues: make(map[string]ocppj.RequestQueue),
+	}
+}
+
+// Init flushes all queues when the dispatcher is started or stopped
+func (m *queueMap) Init() {
+	m.mu.Lock()
+	queues := m.queues
+	m.queues = make(map[string]ocppj.RequestQueue)
+	m.mu.Unlock()
+
+	for id, q := range queues {
+		m.flush(id, q)
+	}
+}
+
+func (m *queueMap) Get(id string) (ocppj.RequestQueue, bool) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	q, ok := m.queues[id]
+	return q, ok
+}
+
+func (m *queueMap) GetOrCreate(id string) ocppj.RequestQueue {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	q, ok := m.queues[id]
+	if !ok {
+		q = newRequestQueue(m.size, m.policy, m.maxAge)
+		m.queues[id] = q
+	}
+
+	return q
+}
+
+// Remove flushes the queue of the disconnected charge point. Queued requests are never sent after reconnecting.
+func (m *queueMap) Remove(id string) {
+	m.mu.Lock()
+	q, ok := m.queues[id]
+	delete(m.queues, id)
+	m.mu.Unlock()
+
+	if ok {
+		m.flush(id, q)
+	}
+}
+
+// flush fails the queued requests, including the request waiting for its response
+func (m *queueMap) flush(id string, q ocppj.RequestQueue) {
+	rq, ok := q.(*requestQueue)
+	if !ok {
+		q.Init()
+		return
+	}
+
+	bundles := rq.flush()
+	if len(bundles) == 0 {
+		return
+	}
+
+	m.log.DEBUG.Printf("%s: flushed %d queued requests", id, len(bundles))
+
+	if m.onFlush != nil {
+		m.onFlush(id, bundles)
+	}
+}
+
+func (m *queueMap) Add(id string, queue ocppj.RequestQueue) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	m.queues[id] = queue
+}
+
+// queued returns the number of requests queued for all charge points
+func (m *queueMap) queued() int {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	var res int
+	for _, q := range m.queues {
+		res += q.Size()
+	}
+
+	return res
+}
+
+// dispatcher fails the flushed requests of disconnected charge points through the cancel callback.
+// Otherwise their response callbacks never fire and callers waiting for the response hang.
+type dispatcher struct {
+	*ocppj.DefaultServerDispatcher
+	onCancel ocppj.CanceledRequestHandler
+}
+
+func newDispatcher(queues *queueMap) *dispatcher {
+	d := &dispatcher{
+		DefaultServerDispatcher: ocppj.NewDefaultServerDispatcher(queues),
+	}
+	queues.onFlush = d.cancel
+	return d
+}
+
+// SetOnRequestCanceled captures the central system's cancel callback
+func (d *dispatcher) SetOnRequestCanceled(cb ocppj.CanceledRequestHandler) {
+	d.onCancel = cb
+	d.DefaultServerDispatcher.SetOnRequestCanceled(cb)
+}
+
+// cancel fails the requests as timed out, callers see api.ErrTimeout
+func (d *dispatcher) cancel(id string, bundles []ocppj.RequestBundle) {
+	if d.onCancel == nil {
+		return
+	}
+
+	for _, b := range bundles {
+		d.onCancel(id, b.Call.UniqueId, b.Call.Payload, ocpp.NewError(ocppj.GenericError, errRequestFlushed.Error(), b.Call.UniqueId))
+	}
+}
@@ -0,0 +1,192 @@
+package ocpp
+
+import (
+	"fmt"
+	"slices"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/evcc-io/evcc/util"
+	"github.com/lorenzodonini/ocpp-go/ocpp"
+	"github.com/lorenzodonini/ocpp-go/ocpp1.6/core"
+	"github.com/lorenzodonini/ocpp-go/ocppj"
+	"github.com/lorenzodonini/ocpp-go/ws"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func testRequest(id string) ocppj.RequestBundle {
+	return ocppj.RequestBundle{
+		Call: &ocppj.Call{MessageTypeId: ocppj.CALL, UniqueId: id, Action: core.ResetFeatureName, Payload: core.NewResetRequest(core.ResetTypeSoft)},
+		Data: testData(id),
+	}
+}
+
+func testData(id string) []byte {
+	return fmt.Appendf(nil, `[2,"%s","Reset",{"type":"Soft"}]`, id)
+}
+
+func TestQueuePolicy(t *testing.T) {
+	res, err := ParseQueuePolicy("Drop")
+	require.NoError(t, err)
+	assert.Equal(t, QueueDrop, res)
+
+	_, err = ParseQueuePolicy("foo")
+	assert.Error(t, err)
+}
+
+func TestQueueReject(t *testing.T) {
+	q := newRequestQueue(2, QueueReject, 0)
+
+	require.NoError(t, q.Push(testRequest("1")))
+	require.NoError(t, q.Push(testRequest("2")))
+	assert.True(t, q.IsFull())
+	assert.Error(t, q.Push(testRequest("3")))
+	assert.Equal(t, 2, q.Size())
+}
+
+func TestQueueDrop(t *testing.T) {
+	q := newRequestQueue(2, QueueDrop, 0)
+
+	for _, id := range []string{"1", "2", "3"} {
+		require.NoError(t, q.Push(testRequest(id)))
+	}
+	assert.False(t, q.IsFull())
+
+	// first request may have been sent already, oldest waiting request is dropped
+	for _, tc := range []struct {
+		id      string
+		dropped bool
+	}{
+		{"1", false},
+		{"2", true},
+		{"3", false},
+	} {
+		b := q.Peek().(ocppj.RequestBundle)
+		assert.Equal(t, tc.id, b.Call.UniqueId)
+		assert.Equal(t, tc.dropped, b.Data == nil)
+		q.Pop()
+	}
+
+	assert.True(t, q.IsEmpty())
+
+	// sent request can't be dropped
+	q = newRequestQueue(1, QueueDrop, 0)
+	require.NoError(t, q.Push(testRequest("1")))
+	assert.True(t, q.IsFull())
+	assert.Error(t, q.Push(testRequest("2")))
+	assert.Equal(t, 1, q.Size())
+}
+
+func TestQueueMaxAge(t *testing.T) {
+	q := newRequestQueue(0, "", time.Minute)
+
+	require.NoError(t, q.Push(testRequest("1")))
+	assert.NotEmpty(t, q.Peek().(ocppj.RequestBundle).Data)
+
+	q.elements[0].queued = time.Now().Add(-2 * time.Minute)
+	assert.Empty(t, q.Peek().(ocppj.RequestBundle).Data)
+}
+
+func TestQueueMapFlush(t *testing.T) {
+	m := newQueueMap(util.NewLogger("foo"), 2, QueueReject, 0)
+
+	q := m.GetOrCreate("cp")
+	require.NoError(t, q.Push(testRequest("1")))
+	assert.Equal(t, 1, m.queued())
+
+	m.Remove("cp")
+	assert.True(t, q.IsEmpty())
+
+	_, ok := m.Get("cp")
+	assert.False(t, ok)
+	assert.Equal(t, 0, m.queued())
+}
+
+type testNetwork struct {
+	ws.Server
+	mu      sync.Mutex
+	written []string
+}
+
+func (n *testNetwork) Write(id string, data []byte) error {
+	n.mu.Lock()
+	defer n.mu.Unlock()
+	n.written = append(n.written, string(data))
+	return nil
+}
+
+func (n *testNetwork) Written() []string {
+	n.mu.Lock()
+	defer n.mu.Unlock()
+	return slices.Clone(n.written)
+}
+
+func TestQueueDispatcher(t *testing.T) {
+	network := new(testNetwork)
+
+	d := ocppj.NewDefaultServerDispatcher(newQueueMap(util.NewLogger("foo"), 2, QueueDrop, 0))
+	d.SetNetworkServer(&muxEndpoint{Server: network})
+
+	canceled := make(chan string, 1)
+	d.SetOnRequestCanceled(func(id, requestID string, request ocpp.Request, err *ocpp.Error) {
+		canceled <- requestID
+	})
+
+	d.Start()
+	defer d.Stop()
+	d.CreateClient("cp")
+
+	require.NoError(t, d.SendRequest("cp", testRequest("1")))
+	require.Eventually(t, func() bool { return len(network.Written()) == 1 }, time.Second, time.Millisecond)
+
+	// waiting request dropped by newer request
+	require.NoError(t, d.SendRequest("cp", testRequest("2")))
+	require.NoError(t, d.SendRequest("cp", testRequest("3")))
+
+	d.CompleteRequest("cp", "1")
+
+	select {
+	case id := <-canceled:
+		assert.Equal(t, "2", id)
+	case <-time.After(time.Second):
+		require.Fail(t, "dropped request not canceled")
+	}
+
+	// request following the dropped request is sent once
+	require.Eventually(t, func() bool { return len(network.Written()) == 2 }, time.Second, time.Millisecond)
+	time.Sleep(50 * time.Millisecond)
+	assert.Equal(t, []string{string(testData("1")), string(testData("3"))}, network.Written())
+}
+
+func TestQueueDisconnect(t *testing.T) {
+	network := new(testNetwork)
+
+	d := newDispatcher(newQueueMap(util.NewLogger("foo"), 0, "", 0))
+	d.SetNetworkServer(&muxEndpoint{Server: network})
+
+	var mu sync.Mutex
+	var canceled []string
+	d.SetOnRequestCanceled(func(id, requestID string, request ocpp.Request, err *ocpp.Error) {
+		mu.Lock()
+		defer mu.Unlock()
+		assert.Equal(t, ocppj.GenericError, err.Code)
+		canceled = append(canceled, requestID)
+	})
+
+	d.Start()
+	defer d.Stop()
+	d.CreateClient("cp")
+
+	require.NoError(t, d.SendRequest("cp", testRequest("1")))
+	require.NoError(t, d.SendRequest("cp", testRequest("2")))
+	require.Eventually(t, func() bool { return len(network.Written()) == 1 }, time.Second, time.Millisecond)
+
+	// sent and queued requests are failed
+	d.DeleteClient("cp")
+
+	mu.Lock()
+	defer mu.Unlock()
+	assert.Equal(t, []string{"1", "2"}, canceled)
+}
@@ -0,0 +1,87 @@
+package ocpp
+
+import (
+	"fmt"
+	"regexp"
+	"slices"
+	"strings"
+)
+
+// Quirks are workarounds for charge point firmware deviating from the specification
+type Quirks struct {
+	EnergyWh             bool `json:"energyWh,omitempty"`             // energy register sampled in Wh although the unit claims kWh
+	RemoteStartEarly     bool `json:"remoteStartEarly,omitempty"`     // requires RemoteStartTransaction before the vehicle is connected
+	PhantomSuspendedEVSE bool `json:"phantomSuspendedEVSE,omitempty"` // reports SuspendedEVSE although charging is enabled
+	DeciAmps             bool `json:"deciAmps,omitempty"`             // current sampled in 0.1 A although the unit claims A
+}
+
+// quirkProfile applies quirks to charge points matching vendor, model and firmware. Empty expressions match any value.
+type quirkProfile struct {
+	Vendor, Model, Firmware *regexp.Regexp
+	Quirks                  Quirks
+}
+
+// knownQuirks are the quirk profiles of known broken firmware
+var knownQuirks []quirkProfile
+
+func (p quirkProfile) matches(c Capabilities) bool {
+	match := func(re *regexp.Regexp, s string) bool {
+		return re == nil || re.MatchString(s)
+	}
+
+	return match(p.Vendor, c.Vendor) && match(p.Model, c.Model) && match(p.Firmware, c.Firmware)
+}
+
+// detectQuirks returns the combined quirks of all profiles matching the charge point
+func detectQuirks(c Capabilities) Quirks {
+	var res Quirks
+
+	for _, p := range knownQuirks {
+		if p.matches(c) {
+			res.EnergyWh = res.EnergyWh || p.Quirks.EnergyWh
+			res.RemoteStartEarly = res.RemoteStartEarly || p.Quirks.RemoteStartEarly
+			res.PhantomSuspendedEVSE = res.PhantomSuspendedEVSE || p.Quirks.PhantomSuspendedEVSE
+			res.DeciAmps = res.DeciAmps || p.Quirks.DeciAmps
+		}
+	}
+
+	return res
+}
+
+// Override enables or disables quirks by name
+func (q *Quirks) Override(quirks map[string]bool) error {
+	for name, enable := range quirks {
+		switch strings.ToLower(name) {
+		case "energywh":
+			q.EnergyWh = enable
+		case "remotestartearly":
+			q.RemoteStartEarly = enable
+		case "phantomsuspendedevse":
+			q.PhantomSuspendedEVSE = enable
+		case "deciamps":
+			q.DeciAmps = enable
+		default:
+			return fmt.Errorf("invalid quirk: %s", name)
+		}
+	}
+
+	return nil
+}
+
+// String returns the names of the enabled quirks
+func (q Quirks) String() string {
+	var res []string
+	for name, enabled := range map[string]bool{
+		"energyWh":             q.EnergyWh,
+		"remoteStartEarly":     q.RemoteStartEarly,
+		"phantomSuspendedEVSE": q.PhantomSuspendedEVSE,
+		"deciAmps":             q.DeciAmps,
+	} {
+		if enabled {
+			res = append(res, name)
+		}
+	}
+	slices.Sort(res)
+
+	return strings.Join(res, ",")
+}
@@ -0,0 +1,31 @@
+package ocpp
+
+import (
+	"regexp"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestDetectQuirks(t *testing.T) {
+	defer func(q []quirkProfile) { knownQuirks = q }(knownQuirks)
+
+	knownQuirks = []quirkProfile{
+		{Vendor: regexp.MustCompile(`^acme$`), Firmware: regexp.MustCompile(`^1\.`), Quirks: Quirks{EnergyWh: true}},
+		{Vendor: regexp.MustCompile(`^acme$`), Model: regexp.MustCompile(`^wallbox`), Quirks: Quirks{PhantomSuspendedEVSE: true}},
+	}
+
+	assert.Equal(t, Quirks{EnergyWh: true, PhantomSuspendedEVSE: true}, detectQuirks(Capabilities{Vendor: "acme", Model: "wallbox 2", Firmware: "1.2"}))
+	assert.Equal(t, Quirks{PhantomSuspendedEVSE: true}, detectQuirks(Capabilities{Vendor: "acme", Model: "wallbox 2", Firmware: "2.0"}))
+	assert.Equal(t, Quirks{}, detectQuirks(Capabilities{Vendor: "other", Firmware: "1.2"}))
+}
+
+func TestQuirksOverride(t *testing.T) {
+	q := Quirks{EnergyWh: true}
+
+	assert.NoError(t, q.Override(map[string]bool{"energywh": false, "remoteStartEarly": true}))
+	assert.Equal(t, Quirks{RemoteStartEarly: true}, q)
+	assert.Equal(t, "remoteStartEarly", q.String())
+
+	assert.Error(t, q.Override(map[string]bool{"foo": true}))
+}
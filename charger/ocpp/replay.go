@@ -0,0 +1,23 @@
+package ocpp
+
+import (
+	"time"
+
+	"github.com/lorenzodonini/ocpp-go/ocpp1.6/types"
+)
+
+// replayTolerance is the clock skew tolerated before messages are considered queued while offline
+const replayTolerance = time.Minute
+
+// isReplay returns true if the timestamped message has been queued while the charge point was offline
+// and is replayed after reconnecting. Messages without timestamp are never considered replayed.
+func (cp *CP) isReplay(ts *types.DateTime) bool {
+	cp.mu.RLock()
+	defer cp.mu.RUnlock()
+
+	if ts == nil || cp.connectedAt.IsZero() {
+		return false
+	}
+
+	return ts.Time.Add(-cp.skew).Before(cp.connectedAt.Add(-replayTolerance))
+}
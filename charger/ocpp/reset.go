@@ -0,0 +1,157 @@
+package ocpp
+
+import (
+	"fmt"
+	"maps"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/lorenzodonini/ocpp-go/ocpp1.6/core"
+)
+
+const (
+	ResetRequested = "Requested"
+	ResetAccepted  = "Accepted"
+	ResetRejected  = "Rejected"
+	ResetCompleted = "Completed" // charge point booted after accepting the reset
+)
+
+// faultResetInterval is the minimum interval between automatic resets of faulted charge points
+const faultResetInterval = time.Hour
+
+// ResetStatus is the progress of a charge point's reset
+type ResetStatus struct {
+	Type      core.ResetType `json:"type"`
+	Status    string         `json:"status"`
+	Error     string         `json:"error,omitempty"`
+	Faulted   bool           `json:"faulted,omitempty"` // automatic reset after repeated faults
+	Requested time.Time      `json:"requested"`
+	Completed time.Time      `json:"completed,omitzero"`
+}
+
+var (
+	resetMu      sync.Mutex
+	resetHandler func(id string, status ResetStatus)
+	resetStatus  = make(map[string]ResetStatus)
+)
+
+// SetResetStatusHandler registers the handler receiving reset status changes
+func SetResetStatusHandler(fun func(id string, status ResetStatus)) {
+	resetMu.Lock()
+	defer resetMu.Unlock()
+	resetHandler = fun
+}
+
+func updateResetStatus(id string, fun func(*ResetStatus) bool) {
+	resetMu.Lock()
+
+	res := resetStatus[id]
+	if !fun(&res) {
+		resetMu.Unlock()
+		return
+	}
+	resetStatus[id] = res
+
+	handler := resetHandler
+	resetMu.Unlock()
+
+	if handler != nil {
+		handler(id, res)
+	}
+}
+
+// ResetStatuses returns the latest reset status per charge point
+func ResetStatuses() map[string]ResetStatus {
+	resetMu.Lock()
+	defer resetMu.Unlock()
+
+	return maps.Clone(resetStatus)
+}
+
+// ParseResetType validates the reset type, defaulting to soft reset
+func ParseResetType(s string) (core.ResetType, error) {
+	switch strings.ToLower(s) {
+	case "", "soft":
+		return core.ResetTypeSoft, nil
+	case "hard":
+		return core.ResetTypeHard, nil
+	default:
+		return "", fmt.Errorf("invalid reset type: %s", s)
+	}
+}
+
+// Reset resets the charge point and tracks the reset's status until the charge point has booted
+func (cp *CP) Reset(resetType core.ResetType) error {
+	return cp.reset(resetType, false)
+}
+
+func (cp *CP) reset(resetType core.ResetType, faulted bool) error {
+	id := cp.ID()
+
+	updateResetStatus(id, func(s *ResetStatus) bool {
+		*s = ResetStatus{Type: resetType, Status: ResetRequested, Faulted: faulted, Requested: time.Now()}
+		return true
+	})
+
+	err := cp.ResetRequest(resetType)
+
+	updateResetStatus(id, func(s *ResetStatus) bool {
+		s.Status = ResetAccepted
+		if err != nil {
+			s.Status, s.Error = ResetRejected, err.Error()
+		}
+		return true
+	})
+
+	return err
+}
+
+// resetCompleted completes an accepted reset once the charge point has booted
+func (cp *CP) resetCompleted() {
+	updateResetStatus(cp.ID(), func(s *ResetStatus) bool {
+		if s.Status != ResetAccepted {
+			return false
+		}
+
+		s.Status = ResetCompleted
+		s.Completed = time.Now()
+		return true
+	})
+}
+
+// SetFaultReset soft resets the charge point after the given number of consecutive Faulted status notifications, zero disables
+func (conn *Connector) SetFaultReset(faults int) {
+	conn.mu.Lock()
+	defer conn.mu.Unlock()
+	conn.faultReset = faults
+}
+
+// countFault counts consecutive Faulted status notifications and returns true if the charge point should be reset.
+// The connector's mutex must be held.
+func (conn *Connector) countFault(status core.ChargePointStatus) bool {
+	if status != core.ChargePointStatusFaulted {
+		conn.faults = 0
+		return false
+	}
+
+	conn.faults++
+
+	if conn.faultReset == 0 || conn.faults < conn.faultReset || conn.clock.Since(conn.faultResetAt) < faultResetInterval {
+		return false
+	}
+
+	conn.faults = 0
+	conn.faultResetAt = conn.clock.Now()
+
+	return true
+}
+
+// faultedReset soft resets the faulted charge point
+func (conn *Connector) faultedReset() {
+	conn.log.WARN.Println("repeatedly faulted, resetting charge point")
+
+	if err := conn.cp.reset(core.ResetTypeSoft, true); err != nil {
+		conn.log.ERROR.Printf("reset: %v", err)
+	}
+}
@@ -0,0 +1,37 @@
+package ocpp
+
+import (
+	"testing"
+
+	"github.com/evcc-io/evcc/util"
+	"github.com/lorenzodonini/ocpp-go/ocpp1.6/core"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParseResetType(t *testing.T) {
+	for s, expected := range map[string]core.ResetType{"": core.ResetTypeSoft, "soft": core.ResetTypeSoft, "Hard": core.ResetTypeHard} {
+		res, err := ParseResetType(s)
+		assert.NoError(t, err, s)
+		assert.Equal(t, expected, res, s)
+	}
+
+	_, err := ParseResetType("foo")
+	assert.Error(t, err)
+}
+
+func TestResetCompleted(t *testing.T) {
+	cp := NewChargePoint(util.NewLogger("foo"), "reset")
+
+	// not reset
+	cp.resetCompleted()
+	assert.NotContains(t, ResetStatuses(), "reset")
+
+	updateResetStatus("reset", func(s *ResetStatus) bool {
+		s.Type, s.Status = core.ResetTypeSoft, ResetAccepted
+		return true
+	})
+
+	cp.resetCompleted()
+	assert.Equal(t, ResetCompleted, ResetStatuses()["reset"].Status)
+	assert.False(t, ResetStatuses()["reset"].Completed.IsZero())
+}
@@ -0,0 +1,159 @@
+package ocpp
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/cenkalti/backoff/v4"
+	"github.com/evcc-io/evcc/api"
+	"github.com/evcc-io/evcc/util/supervisor"
+)
+
+// retryPolicy controls resending requests timing out
+type retryPolicy struct {
+	retries  int           // number of times a request timing out is resent
+	backoff  time.Duration // initial delay before resending, doubled per retry
+	deadline time.Duration // time callers wait for a request including its retries, derived from Timeout if zero
+}
+
+// defaultRetryPolicy resends requests twice after one and two seconds
+var defaultRetryPolicy = retryPolicy{retries: 2, backoff: time.Second}
+
+// duration returns the time callers wait for a request including its retries.
+// Unless configured, each attempt may use the protocol timeout plus its backoff.
+func (p retryPolicy) duration() time.Duration {
+	if p.deadline > 0 {
+		return p.deadline
+	}
+
+	res := Timeout
+	for i, d := 0, p.backoff; i < p.retries; i, d = i+1, 2*d {
+		res += d + Timeout
+	}
+
+	return res
+}
+
+// RequestError is returned when the charge point did not answer a request including its retries
+type RequestError struct {
+	Action   string
+	Attempts int
+	Err      error
+}
+
+func (e *RequestError) Error() string {
+	return fmt.Sprintf("%s: no response after %d attempts: %v", e.Action, e.Attempts, e.Err)
+}
+
+func (e *RequestError) Unwrap() error {
+	return e.Err
+}
+
+// inflightRequest is the request sent for all identical requests until answered
+type inflightRequest struct {
+	once     sync.Once
+	done     chan struct{}
+	err      error
+	attempts atomic.Int32
+}
+
+// finish completes the request for all waiting callers
+func (req *inflightRequest) finish(err error) {
+	req.once.Do(func() {
+		req.err = err
+		close(req.done)
+	})
+}
+
+// requestKey identifies identical requests by action, connector and payload
+func requestKey(action string, connectorId int, payload ...any) string {
+	b, _ := json.Marshal(payload)
+	return fmt.Sprintf("%s/%d/%s", action, connectorId, b)
+}
+
+// retry sends the request and resends it with exponential backoff while it times out.
+// Identical requests made meanwhile share the result instead of being sent again.
+// Callers wait at most the policy's duration, the request may still be answered afterwards.
+func (cp *CP) retry(action string, key string, send func() error) error {
+	cp.inflightMu.Lock()
+	policy := cp.retryPolicy
+	req, ok := cp.inflight[key]
+	if ok {
+		cp.log.DEBUG.Printf("%s: waiting for identical request", action)
+	} else {
+		req = &inflightRequest{done: make(chan struct{})}
+		cp.inflight[key] = req
+
+		go cp.resend(action, key, req, policy, send)
+	}
+	cp.inflightMu.Unlock()
+
+	timer := time.NewTimer(policy.duration())
+	defer timer.Stop()
+
+	select {
+	case <-req.done:
+		return req.err
+	case <-timer.C:
+		return &RequestError{Action: action, Attempts: int(req.attempts.Load()), Err: api.ErrTimeout}
+	}
+}
+
+// resend sends the request until answered, not timing out or retries are exhausted
+func (cp *CP) resend(action string, key string, req *inflightRequest, policy retryPolicy, send func() error) {
+	// no retry started after callers have given up
+	bo := backoff.WithMaxRetries(backoff.NewExponentialBackOff(
+		backoff.WithInitialInterval(policy.backoff),
+		backoff.WithMaxElapsedTime(policy.duration())), uint64(policy.retries))
+
+	err := backoff.RetryNotify(func() error {
+		req.attempts.Add(1)
+
+		done := supervisor.Track("ocpp " + cp.ID() + " " + action)
+		err := send()
+		done()
+		if errors.Is(err, api.ErrTimeout) && cp.Connected() {
+			return err
+		}
+
+		return backoff.Permanent(err)
+	}, bo, func(err error, d time.Duration) {
+		cp.log.DEBUG.Printf("%s: %v, retrying in %v", action, err, d.Round(time.Millisecond))
+	})
+
+	if attempts := int(req.attempts.Load()); errors.Is(err, api.ErrTimeout) && attempts > 1 {
+		err = &RequestError{Action: action, Attempts: attempts, Err: err}
+		cp.log.WARN.Println(err)
+	}
+
+	cp.inflightMu.Lock()
+	if cp.inflight[key] == req {
+		delete(cp.inflight, key)
+	}
+	cp.inflightMu.Unlock()
+
+	req.finish(err)
+}
+
+// setRetryPolicy sets the policy of requests made from now on
+func (cp *CP) setRetryPolicy(policy retryPolicy) {
+	cp.inflightMu.Lock()
+	defer cp.inflightMu.Unlock()
+
+	cp.retryPolicy = policy
+}
+
+// failInflight fails the requests waiting for an answer when the charge point disconnects
+func (cp *CP) failInflight() {
+	cp.inflightMu.Lock()
+	defer cp.inflightMu.Unlock()
+
+	for key, req := range cp.inflight {
+		req.finish(fmt.Errorf("charge point disconnected: %w", api.ErrTimeout))
+		delete(cp.inflight, key)
+	}
+}
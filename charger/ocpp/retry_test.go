@@ -0,0 +1,168 @@
+package ocpp
+
+import (
+	"errors"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/evcc-io/evcc/api"
+	"github.com/evcc-io/evcc/util"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRetryPolicy(t *testing.T) {
+	// each attempt may use the protocol timeout
+	assert.Equal(t, 3*Timeout+3*time.Second, defaultRetryPolicy.duration())
+	assert.Equal(t, time.Second, retryPolicy{deadline: time.Second}.duration())
+}
+
+func TestRetry(t *testing.T) {
+	cp := NewChargePoint(util.NewLogger("foo"), "retry")
+	cp.setRetryPolicy(retryPolicy{retries: 2, backoff: time.Millisecond})
+	cp.connect(true)
+
+	requestRetries := cp.retryPolicy.retries
+
+	// answered after retrying
+	var attempts int
+	require.NoError(t, cp.retry("Test", "answered", func() error {
+		if attempts++; attempts <= requestRetries {
+			return api.ErrTimeout
+		}
+		return nil
+	}))
+	assert.Equal(t, requestRetries+1, attempts)
+
+	// terminal failure
+	attempts = 0
+	err := cp.retry("Test", "timeout", func() error {
+		attempts++
+		return api.ErrTimeout
+	})
+	assert.Equal(t, requestRetries+1, attempts)
+	assert.ErrorIs(t, err, api.ErrTimeout)
+
+	var re *RequestError
+	require.ErrorAs(t, err, &re)
+	assert.Equal(t, requestRetries+1, re.Attempts)
+
+	// rejected requests are not resent
+	attempts = 0
+	err = cp.retry("Test", "rejected", func() error {
+		attempts++
+		return errors.New("Rejected")
+	})
+	assert.EqualError(t, err, "Rejected")
+	assert.Equal(t, 1, attempts)
+
+	// disconnected charge point
+	cp.connect(false)
+
+	attempts = 0
+	err = cp.retry("Test", "disconnected", func() error {
+		attempts++
+		return api.ErrTimeout
+	})
+	assert.Equal(t, api.ErrTimeout, err)
+	assert.Equal(t, 1, attempts)
+}
+
+func TestRetryInflight(t *testing.T) {
+	cp := NewChargePoint(util.NewLogger("foo"), "inflight")
+	cp.connect(true)
+
+	var sent atomic.Int32
+	sending := make(chan struct{})
+	answer := make(chan error)
+
+	send := func() error {
+		sent.Add(1)
+		close(sending)
+		return <-answer
+	}
+
+	key := requestKey("Test", 1, "foo")
+
+	var wg sync.WaitGroup
+	errs := make([]error, 2)
+
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+		errs[0] = cp.retry("Test", key, send)
+	}()
+
+	<-sending
+
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+		errs[1] = cp.retry("Test", key, send)
+	}()
+
+	// identical request waits for the answer
+	time.Sleep(50 * time.Millisecond)
+
+	// different payload is sent separately
+	require.NoError(t, cp.retry("Test", requestKey("Test", 1, "bar"), func() error { return nil }))
+
+	answer <- errors.New("Rejected")
+	wg.Wait()
+
+	assert.Equal(t, int32(1), sent.Load())
+	assert.EqualError(t, errs[0], "Rejected")
+	assert.EqualError(t, errs[1], "Rejected")
+	assert.Empty(t, cp.inflight)
+}
+
+func TestRetryDeadline(t *testing.T) {
+	cp := NewChargePoint(util.NewLogger("foo"), "deadline")
+	cp.setRetryPolicy(retryPolicy{retries: 2, backoff: time.Second, deadline: 50 * time.Millisecond})
+	cp.connect(true)
+
+	answer := make(chan error)
+	send := func() error {
+		return <-answer
+	}
+
+	// caller gives up while the request is unanswered
+	err := cp.retry("Test", "key", send)
+	assert.ErrorIs(t, err, api.ErrTimeout)
+
+	var re *RequestError
+	require.ErrorAs(t, err, &re)
+	assert.Equal(t, 1, re.Attempts)
+
+	// identical request keeps waiting for the unanswered request
+	assert.ErrorIs(t, cp.retry("Test", "key", func() error {
+		require.Fail(t, "identical request sent")
+		return nil
+	}), api.ErrTimeout)
+
+	// disconnect fails the unanswered requests
+	errC := make(chan error, 1)
+	cp.setRetryPolicy(retryPolicy{retries: 2, backoff: time.Second, deadline: time.Minute})
+	go func() {
+		errC <- cp.retry("Test", "other", send)
+	}()
+
+	require.Eventually(t, func() bool {
+		cp.inflightMu.Lock()
+		defer cp.inflightMu.Unlock()
+		return len(cp.inflight) == 2
+	}, time.Second, time.Millisecond)
+
+	cp.connect(false)
+
+	select {
+	case err := <-errC:
+		assert.ErrorIs(t, err, api.ErrTimeout)
+	case <-time.After(time.Second):
+		require.Fail(t, "request not failed on disconnect")
+	}
+
+	close(answer)
+}
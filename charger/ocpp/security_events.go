@@ -0,0 +1,95 @@
+package ocpp
+
+import (
+	"maps"
+	"slices"
+	"sync"
+	"time"
+)
+
+// SecurityEvent is a security event reported by a charge point
+type SecurityEvent struct {
+	Station   string    `json:"station"`
+	Timestamp time.Time `json:"timestamp"`
+	Type      string    `json:"type"`
+	TechInfo  string    `json:"techInfo,omitempty"`
+	Critical  bool      `json:"critical"`
+}
+
+// criticalSecurityEvents raise a warning until acknowledged
+var criticalSecurityEvents = []string{
+	"TamperDetectionActivated",
+	"InvalidFirmwareSignature",
+	"InvalidFirmwareSigningCertificate",
+	"AttemptedReplayAttacks",
+}
+
+// maxSecurityEvents is the number of events retained per charge point
+const maxSecurityEvents = 50
+
+var (
+	securityMu       sync.Mutex
+	securityHandler  func(SecurityEvent)
+	securityEvents   = make(map[string][]SecurityEvent)
+	securityWarnings []SecurityEvent
+)
+
+// SetSecurityEventHandler registers the handler receiving charge point security events
+func SetSecurityEventHandler(fun func(SecurityEvent)) {
+	securityMu.Lock()
+	defer securityMu.Unlock()
+	securityHandler = fun
+}
+
+func addSecurityEvent(event SecurityEvent) {
+	securityMu.Lock()
+
+	events := append(securityEvents[event.Station], event)
+	if len(events) > maxSecurityEvents {
+		events = slices.Clone(events[len(events)-maxSecurityEvents:])
+	}
+	securityEvents[event.Station] = events
+
+	if event.Critical {
+		securityWarnings = append(securityWarnings, event)
+		if len(securityWarnings) > maxSecurityEvents {
+			securityWarnings = slices.Clone(securityWarnings[len(securityWarnings)-maxSecurityEvents:])
+		}
+	}
+
+	fun := securityHandler
+	securityMu.Unlock()
+
+	if fun != nil {
+		fun(event)
+	}
+}
+
+// SecurityEvents returns the recent security events per charge point, oldest first
+func SecurityEvents() map[string][]SecurityEvent {
+	securityMu.Lock()
+	defer securityMu.Unlock()
+
+	res := maps.Clone(securityEvents)
+	for id, events := range res {
+		res[id] = slices.Clone(events)
+	}
+
+	return res
+}
+
+// SecurityWarnings returns the critical security events which have not been acknowledged
+func SecurityWarnings() []SecurityEvent {
+	securityMu.Lock()
+	defer securityMu.Unlock()
+
+	return slices.Clone(securityWarnings)
+}
+
+// AcknowledgeSecurityWarnings clears the critical security event warnings
+func AcknowledgeSecurityWarnings() {
+	securityMu.Lock()
+	defer securityMu.Unlock()
+
+	securityWarnings = nil
+}
@@ -0,0 +1,37 @@
+package ocpp
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSecurityEvents(t *testing.T) {
+	var handled []SecurityEvent
+	SetSecurityEventHandler(func(event SecurityEvent) {
+		handled = append(handled, event)
+	})
+	defer SetSecurityEventHandler(nil)
+
+	for i := range maxSecurityEvents + 1 {
+		addSecurityEvent(SecurityEvent{Station: "cp-1", Type: fmt.Sprintf("Event%d", i)})
+	}
+	addSecurityEvent(SecurityEvent{Station: "cp-2", Type: "TamperDetectionActivated", Critical: true})
+
+	assert.Len(t, handled, maxSecurityEvents+2)
+
+	// ring buffer keeps the latest events
+	events := SecurityEvents()
+	require.Len(t, events["cp-1"], maxSecurityEvents)
+	assert.Equal(t, "Event1", events["cp-1"][0].Type)
+	assert.Len(t, events["cp-2"], 1)
+
+	// critical events raise warnings until acknowledged
+	require.Len(t, SecurityWarnings(), 1)
+	assert.Equal(t, "cp-2", SecurityWarnings()[0].Station)
+
+	AcknowledgeSecurityWarnings()
+	assert.Empty(t, SecurityWarnings())
+}
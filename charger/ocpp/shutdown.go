@@ -0,0 +1,127 @@
+package ocpp
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/lorenzodonini/ocpp-go/ocpp1.6/types"
+)
+
+// drainTimeout limits waiting for queued requests when the central system stops
+const drainTimeout = 5 * time.Second
+
+// ShutdownAction is applied to running transactions when the central system stops
+type ShutdownAction string
+
+const (
+	ShutdownStop    ShutdownAction = "stop"    // stop transactions remotely
+	ShutdownSuspend ShutdownAction = "suspend" // limit transactions to zero current
+)
+
+// ParseShutdownAction validates the action, empty action is returned unchanged
+func ParseShutdownAction(s string) (ShutdownAction, error) {
+	switch res := ShutdownAction(strings.ToLower(s)); res {
+	case "", ShutdownStop, ShutdownSuspend:
+		return res, nil
+	default:
+		return "", fmt.Errorf("invalid shutdown action: %s", s)
+	}
+}
+
+// SetSuspendProfile registers the charging profile suspending the connector's transaction on shutdown
+func (conn *Connector) SetSuspendProfile(fun func() *types.ChargingProfile) {
+	conn.mu.Lock()
+	defer conn.mu.Unlock()
+	conn.suspendProfile = fun
+}
+
+// shutdown applies the action to the connector's running transaction
+func (conn *Connector) shutdown(action ShutdownAction) error {
+	conn.mu.Lock()
+	txnId, fun := conn.txnId, conn.suspendProfile
+	conn.mu.Unlock()
+
+	if txnId == 0 {
+		return nil
+	}
+
+	switch action {
+	case ShutdownStop:
+		return conn.RemoteStopTransactionRequest(txnId)
+
+	case ShutdownSuspend:
+		if fun == nil {
+			return errors.New("missing suspend profile")
+		}
+		return conn.SetChargingProfileRequest(fun())
+	}
+
+	return nil
+}
+
+// Stop applies the shutdown action to running transactions and stops the central system
+func Stop() {
+	if running.Load() {
+		Instance().stop()
+	}
+}
+
+func (cs *CS) stop() {
+	if cs.shutdown != "" {
+		var wg sync.WaitGroup
+
+		for _, cp := range cs.chargepoints() {
+			if !cp.Connected() {
+				continue
+			}
+
+			cp.mu.RLock()
+			for _, conn := range cp.connectors {
+				wg.Go(func() {
+					if err := conn.shutdown(cs.shutdown); err != nil {
+						cs.log.ERROR.Printf("%s: connector %d: %s: %v", cp.ID(), conn.id, cs.shutdown, err)
+					}
+				})
+			}
+			cp.mu.RUnlock()
+		}
+
+		wg.Wait()
+	}
+
+	cs.drain(drainTimeout)
+
+	running.Store(false)
+
+	cs.CentralSystem.Stop()
+	cs.csms.Stop()
+
+	// endpoints don't stop the shared websocket server
+	cs.server.Stop()
+
+	if cs.tracer != nil {
+		cs.tracer.close()
+	}
+}
+
+// drain waits for queued requests to be completed before the dispatchers are stopped
+func (cs *CS) drain(timeout time.Duration) {
+	queued := func() int {
+		var res int
+		for _, q := range cs.queues {
+			res += q.queued()
+		}
+		return res
+	}
+
+	for deadline := time.Now().Add(timeout); queued() > 0; {
+		if time.Now().After(deadline) {
+			cs.log.WARN.Printf("stopping with %d queued requests", queued())
+			return
+		}
+		time.Sleep(100 * time.Millisecond)
+	}
+}
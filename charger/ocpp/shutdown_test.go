@@ -0,0 +1,26 @@
+package ocpp
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParseShutdownAction(t *testing.T) {
+	for _, s := range []string{"", "stop", "Suspend"} {
+		_, err := ParseShutdownAction(s)
+		assert.NoError(t, err, s)
+	}
+
+	_, err := ParseShutdownAction("foo")
+	assert.Error(t, err)
+}
+
+func TestConnectorShutdown(t *testing.T) {
+	conn := &Connector{}
+	assert.NoError(t, conn.shutdown(ShutdownStop))
+	assert.NoError(t, conn.shutdown(ShutdownSuspend))
+
+	conn.txnId = 1
+	assert.Error(t, conn.shutdown(ShutdownSuspend))
+}
@@ -0,0 +1,133 @@
+package ocpp
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"fmt"
+	"os"
+	"sync"
+	"time"
+
+	"github.com/evcc-io/evcc/util"
+)
+
+// certificates provides the tls configuration for each client connection. Certificate, key and client
+// certificate authorities are reloaded once any of the files has changed.
+type certificates struct {
+	mu                        sync.Mutex
+	log                       *util.Logger
+	certFile, keyFile, caFile string
+	modTime                   time.Time
+	config                    *tls.Config
+}
+
+func newCertificates(log *util.Logger, certFile, keyFile, caFile string) (*certificates, error) {
+	c := &certificates{
+		log:      log,
+		certFile: certFile,
+		keyFile:  keyFile,
+		caFile:   caFile,
+	}
+
+	if _, err := c.load(); err != nil {
+		return nil, err
+	}
+
+	return c, nil
+}
+
+// TLSConfig returns the server's tls configuration
+func (c *certificates) TLSConfig() *tls.Config {
+	return &tls.Config{
+		MinVersion: tls.VersionTLS12,
+		GetConfigForClient: func(*tls.ClientHelloInfo) (*tls.Config, error) {
+			return c.load()
+		},
+	}
+}
+
+// modified returns the latest modification time of all files
+func (c *certificates) modified() (time.Time, error) {
+	var res time.Time
+
+	for _, file := range []string{c.certFile, c.keyFile, c.caFile} {
+		if file == "" {
+			continue
+		}
+
+		fi, err := os.Stat(file)
+		if err != nil {
+			return res, err
+		}
+
+		if fi.ModTime().After(res) {
+			res = fi.ModTime()
+		}
+	}
+
+	return res, nil
+}
+
+// load returns the current tls configuration, reloading it if files have changed
+func (c *certificates) load() (*tls.Config, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	modTime, err := c.modified()
+	if err == nil && c.config != nil && modTime.Equal(c.modTime) {
+		return c.config, nil
+	}
+
+	var config *tls.Config
+	if err == nil {
+		config, err = c.read()
+	}
+
+	if err != nil {
+		// keep previous configuration while files are being replaced
+		if c.config != nil {
+			c.log.WARN.Printf("tls: %v", err)
+			return c.config, nil
+		}
+
+		return nil, fmt.Errorf("tls: %w", err)
+	}
+
+	if c.config != nil {
+		c.log.INFO.Println("tls: certificate reloaded")
+	}
+
+	c.config, c.modTime = config, modTime
+
+	return config, nil
+}
+
+func (c *certificates) read() (*tls.Config, error) {
+	cert, err := tls.LoadX509KeyPair(c.certFile, c.keyFile)
+	if err != nil {
+		return nil, err
+	}
+
+	res := &tls.Config{
+		MinVersion:   tls.VersionTLS12,
+		Certificates: []tls.Certificate{cert},
+	}
+
+	// mutual tls
+	if c.caFile != "" {
+		b, err := os.ReadFile(c.caFile)
+		if err != nil {
+			return nil, err
+		}
+
+		pool := x509.NewCertPool()
+		if !pool.AppendCertsFromPEM(b) {
+			return nil, fmt.Errorf("no certificates found: %s", c.caFile)
+		}
+
+		res.ClientCAs = pool
+		res.ClientAuth = tls.RequireAndVerifyClientCert
+	}
+
+	return res, nil
+}
@@ -0,0 +1,107 @@
+package ocpp
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"math/big"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/evcc-io/evcc/util"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func writeCertificate(t *testing.T, dir, name string, modTime time.Time) (string, string) {
+	t.Helper()
+
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.NoError(t, err)
+
+	template := &x509.Certificate{
+		SerialNumber:          big.NewInt(time.Now().UnixNano()),
+		Subject:               pkix.Name{CommonName: name},
+		NotBefore:             time.Now().Add(-time.Hour),
+		NotAfter:              time.Now().Add(time.Hour),
+		IsCA:                  true,
+		BasicConstraintsValid: true,
+	}
+
+	der, err := x509.CreateCertificate(rand.Reader, template, template, &key.PublicKey, key)
+	require.NoError(t, err)
+
+	keyDer, err := x509.MarshalECPrivateKey(key)
+	require.NoError(t, err)
+
+	certFile := filepath.Join(dir, "cert.pem")
+	keyFile := filepath.Join(dir, "key.pem")
+
+	require.NoError(t, os.WriteFile(certFile, pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der}), 0o600))
+	require.NoError(t, os.WriteFile(keyFile, pem.EncodeToMemory(&pem.Block{Type: "EC PRIVATE KEY", Bytes: keyDer}), 0o600))
+
+	for _, file := range []string{certFile, keyFile} {
+		require.NoError(t, os.Chtimes(file, modTime, modTime))
+	}
+
+	return certFile, keyFile
+}
+
+func commonName(t *testing.T, config *tls.Config) string {
+	t.Helper()
+
+	require.Len(t, config.Certificates, 1)
+	return config.Certificates[0].Leaf.Subject.CommonName
+}
+
+func TestCertificatesReload(t *testing.T) {
+	dir := t.TempDir()
+	now := time.Now().Truncate(time.Second)
+
+	certFile, keyFile := writeCertificate(t, dir, "first", now)
+
+	c, err := newCertificates(util.NewLogger("foo"), certFile, keyFile, "")
+	require.NoError(t, err)
+
+	config, err := c.TLSConfig().GetConfigForClient(nil)
+	require.NoError(t, err)
+	assert.Equal(t, "first", commonName(t, config))
+	assert.Equal(t, tls.NoClientCert, config.ClientAuth)
+
+	// changed files are reloaded
+	writeCertificate(t, dir, "second", now.Add(time.Minute))
+
+	config, err = c.load()
+	require.NoError(t, err)
+	assert.Equal(t, "second", commonName(t, config))
+
+	// invalid files keep the previous certificate
+	require.NoError(t, os.WriteFile(keyFile, []byte("invalid"), 0o600))
+	require.NoError(t, os.Chtimes(keyFile, now.Add(2*time.Minute), now.Add(2*time.Minute)))
+
+	config, err = c.load()
+	require.NoError(t, err)
+	assert.Equal(t, "second", commonName(t, config))
+}
+
+func TestCertificatesClientCA(t *testing.T) {
+	dir := t.TempDir()
+	certFile, keyFile := writeCertificate(t, dir, "server", time.Now())
+
+	_, err := newCertificates(util.NewLogger("foo"), certFile, keyFile, keyFile)
+	assert.Error(t, err)
+
+	c, err := newCertificates(util.NewLogger("foo"), certFile, keyFile, certFile)
+	require.NoError(t, err)
+
+	config, err := c.load()
+	require.NoError(t, err)
+	assert.Equal(t, tls.RequireAndVerifyClientCert, config.ClientAuth)
+	assert.NotNil(t, config.ClientCAs)
+}
@@ -0,0 +1,267 @@
+package ocpp
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"slices"
+	"sync"
+	"time"
+
+	"github.com/evcc-io/evcc/util"
+)
+
+const (
+	traceSize     = 200      // recent messages kept per charge point
+	traceFileSize = 10 << 20 // trace files are rotated when exceeding the size
+)
+
+const (
+	TraceSend = "send"
+	TraceRecv = "recv"
+)
+
+// OCPP-J message types
+const (
+	MessageCall      = "call"
+	MessageResult    = "result"
+	MessageCallError = "error"
+
+	messageTypeCall      = 2
+	messageTypeResult    = 3
+	messageTypeCallError = 4
+)
+
+// maxPendingCalls limits the calls awaiting their result for correlating the action
+const maxPendingCalls = 1000
+
+// TraceMessage is an OCPP message exchanged with a charge point. Results and errors carry the action of their call.
+type TraceMessage struct {
+	Time      time.Time       `json:"time"`
+	Direction string          `json:"direction"`
+	Type      string          `json:"type,omitempty"`
+	Action    string          `json:"action,omitempty"`
+	ErrorCode string          `json:"errorCode,omitempty"`
+	Message   json.RawMessage `json:"message"`
+}
+
+var (
+	traceMu          sync.Mutex
+	traces           = make(map[string][]TraceMessage)
+	traceSubscribers = make(map[chan TraceMessage]string)
+
+	pendingCallsMu sync.Mutex
+	pendingCalls   = make(map[string]string) // action by call direction, station and message id
+)
+
+// Traces returns the charge point's recent messages, oldest first
+func Traces(id string) []TraceMessage {
+	traceMu.Lock()
+	defer traceMu.Unlock()
+
+	return slices.Clone(traces[id])
+}
+
+// SubscribeTraces streams the charge point's messages until cancelled. Messages are dropped if the subscriber is too slow.
+func SubscribeTraces(id string) (<-chan TraceMessage, func()) {
+	ch := make(chan TraceMessage, traceSize)
+
+	traceMu.Lock()
+	traceSubscribers[ch] = id
+	traceMu.Unlock()
+
+	return ch, func() {
+		traceMu.Lock()
+		defer traceMu.Unlock()
+
+		if _, ok := traceSubscribers[ch]; ok {
+			delete(traceSubscribers, ch)
+			close(ch)
+		}
+	}
+}
+
+// newTraceMessage parses the raw OCPP-J message exchanged with the charge point
+func newTraceMessage(id, direction, message string) TraceMessage {
+	res := TraceMessage{
+		Time:      time.Now(),
+		Direction: direction,
+		Message:   json.RawMessage(message),
+	}
+
+	if !json.Valid(res.Message) {
+		res.Message, _ = json.Marshal(message)
+		return res
+	}
+
+	var fields []json.RawMessage
+	if err := json.Unmarshal(res.Message, &fields); err != nil || len(fields) < 3 {
+		return res
+	}
+
+	var typ int
+	var uid string
+	if json.Unmarshal(fields[0], &typ) != nil || json.Unmarshal(fields[1], &uid) != nil {
+		return res
+	}
+
+	// calls are answered in the opposite direction
+	callDirection := direction
+	if typ != messageTypeCall {
+		callDirection = map[string]string{TraceSend: TraceRecv, TraceRecv: TraceSend}[direction]
+	}
+	callKey := callDirection + "/" + id + "/" + uid
+
+	pendingCallsMu.Lock()
+	defer pendingCallsMu.Unlock()
+
+	switch typ {
+	case messageTypeCall:
+		res.Type = MessageCall
+		_ = json.Unmarshal(fields[2], &res.Action)
+
+		if len(pendingCalls) >= maxPendingCalls {
+			clear(pendingCalls)
+		}
+		pendingCalls[callKey] = res.Action
+
+	case messageTypeResult, messageTypeCallError:
+		res.Type = MessageResult
+		if typ == messageTypeCallError {
+			res.Type = MessageCallError
+			_ = json.Unmarshal(fields[2], &res.ErrorCode)
+		}
+
+		res.Action = pendingCalls[callKey]
+		delete(pendingCalls, callKey)
+	}
+
+	return res
+}
+
+// trace records the message exchanged with the charge point
+func (cs *CS) trace(id string, msg TraceMessage) {
+	traceMu.Lock()
+	res := append(traces[id], msg)
+	if len(res) > traceSize {
+		res = slices.Delete(res, 0, len(res)-traceSize)
+	}
+	traces[id] = res
+
+	for ch, sid := range traceSubscribers {
+		if sid == id {
+			select {
+			case ch <- msg:
+			default:
+			}
+		}
+	}
+	traceMu.Unlock()
+
+	if cs.tracer != nil {
+		cs.tracer.write(id, msg)
+	}
+}
+
+type traceFile struct {
+	file *os.File
+	size int64
+}
+
+// tracer records messages to rotating JSONL files per charge point
+type tracer struct {
+	log   *util.Logger
+	mu    sync.Mutex
+	dir   string
+	files map[string]*traceFile
+}
+
+func newTracer(log *util.Logger, dir string) *tracer {
+	return &tracer{
+		log:   log,
+		dir:   dir,
+		files: make(map[string]*traceFile),
+	}
+}
+
+// open returns the charge point's trace file, rotating it if exceeding the size limit
+func (t *tracer) open(id string, size int) (*traceFile, error) {
+	tf, ok := t.files[id]
+	if ok && tf.size+int64(size) <= traceFileSize {
+		return tf, nil
+	}
+
+	name := filepath.Join(t.dir, id+".jsonl")
+
+	if ok {
+		delete(t.files, id)
+		if err := tf.file.Close(); err != nil {
+			return nil, err
+		}
+		if err := os.Rename(name, name+".1"); err != nil {
+			return nil, err
+		}
+	}
+
+	if err := os.MkdirAll(t.dir, 0o755); err != nil {
+		return nil, err
+	}
+
+	file, err := os.OpenFile(name, os.O_CREATE|os.O_APPEND|os.O_WRONLY, 0o644)
+	if err != nil {
+		return nil, err
+	}
+
+	fi, err := file.Stat()
+	if err != nil {
+		file.Close()
+		return nil, err
+	}
+
+	tf = &traceFile{file: file, size: fi.Size()}
+	t.files[id] = tf
+
+	// existing file exceeding the limit
+	if tf.size > 0 && tf.size+int64(size) > traceFileSize {
+		return t.open(id, size)
+	}
+
+	return tf, nil
+}
+
+func (t *tracer) write(id string, msg TraceMessage) {
+	if !validName(id) {
+		return
+	}
+
+	b, err := json.Marshal(msg)
+	if err != nil {
+		return
+	}
+	b = append(b, '\n')
+
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	tf, err := t.open(id, len(b))
+	if err == nil {
+		var n int
+		n, err = tf.file.Write(b)
+		tf.size += int64(n)
+	}
+
+	if err != nil {
+		t.log.ERROR.Printf("trace: %s: %v", id, err)
+	}
+}
+
+// close closes all trace files
+func (t *tracer) close() {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	for id, tf := range t.files {
+		tf.file.Close()
+		delete(t.files, id)
+	}
+}
@@ -0,0 +1,116 @@
+package ocpp
+
+import (
+	"bufio"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/evcc-io/evcc/util"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestTrace(t *testing.T) {
+	dir := t.TempDir()
+	cs := &CS{log: util.NewLogger("foo"), tracer: newTracer(util.NewLogger("foo"), dir)}
+	defer cs.tracer.close()
+
+	cs.printf("received JSON message from %s: %s", "trace", `[2,"1","Heartbeat",{}]`)
+	cs.printf("sent JSON message to %s: %s", "trace", `[3,"1",{}]`)
+
+	// charge point client messages are not traced
+	cs.printf("sent JSON message to server: %s", `[2,"2","Heartbeat",{}]`)
+
+	res := Traces("trace")
+	require.Len(t, res, 2)
+	assert.Equal(t, TraceRecv, res[0].Direction)
+	assert.JSONEq(t, `[2,"1","Heartbeat",{}]`, string(res[0].Message))
+	assert.Equal(t, TraceSend, res[1].Direction)
+
+	f, err := os.Open(filepath.Join(dir, "trace.jsonl"))
+	require.NoError(t, err)
+	defer f.Close()
+
+	var lines int
+	for scanner := bufio.NewScanner(f); scanner.Scan(); lines++ {
+		var msg TraceMessage
+		require.NoError(t, json.Unmarshal(scanner.Bytes(), &msg))
+		assert.Equal(t, res[lines].Direction, msg.Direction)
+	}
+	assert.Equal(t, 2, lines)
+}
+
+func TestTraceRingBuffer(t *testing.T) {
+	cs := &CS{log: util.NewLogger("foo")}
+
+	for range traceSize + 10 {
+		cs.trace("ring", newTraceMessage("ring", TraceRecv, "invalid json"))
+	}
+
+	res := Traces("ring")
+	assert.Len(t, res, traceSize)
+	assert.JSONEq(t, `"invalid json"`, string(res[0].Message))
+}
+
+func TestTraceMessage(t *testing.T) {
+	msg := newTraceMessage("parse", TraceSend, `[2,"a","RemoteStartTransaction",{"idTag":"tag"}]`)
+	assert.Equal(t, MessageCall, msg.Type)
+	assert.Equal(t, "RemoteStartTransaction", msg.Action)
+
+	// error is attributed to the call
+	msg = newTraceMessage("parse", TraceRecv, `[4,"a","NotSupported","",{}]`)
+	assert.Equal(t, MessageCallError, msg.Type)
+	assert.Equal(t, "RemoteStartTransaction", msg.Action)
+	assert.Equal(t, "NotSupported", msg.ErrorCode)
+
+	// unknown call
+	msg = newTraceMessage("parse", TraceSend, `[3,"a",{}]`)
+	assert.Equal(t, MessageResult, msg.Type)
+	assert.Empty(t, msg.Action)
+}
+
+func TestTraceSubscription(t *testing.T) {
+	cs := &CS{log: util.NewLogger("foo")}
+
+	ch, cancel := SubscribeTraces("sub")
+
+	cs.trace("other", newTraceMessage("other", TraceRecv, `[2,"1","Heartbeat",{}]`))
+	cs.trace("sub", newTraceMessage("sub", TraceRecv, `[2,"1","Heartbeat",{}]`))
+
+	msg := <-ch
+	assert.Equal(t, "Heartbeat", msg.Action)
+
+	cancel()
+	_, ok := <-ch
+	assert.False(t, ok)
+
+	// cancel is idempotent
+	cancel()
+}
+
+func TestTraceRotation(t *testing.T) {
+	dir := t.TempDir()
+	name := filepath.Join(dir, "rotate.jsonl")
+	require.NoError(t, os.WriteFile(name, nil, 0o644))
+	require.NoError(t, os.Truncate(name, traceFileSize))
+
+	tr := newTracer(util.NewLogger("foo"), dir)
+	defer tr.close()
+
+	tr.write("rotate", TraceMessage{Direction: TraceSend, Message: json.RawMessage(`{}`)})
+
+	fi, err := os.Stat(name + ".1")
+	require.NoError(t, err)
+	assert.EqualValues(t, traceFileSize, fi.Size())
+
+	fi, err = os.Stat(name)
+	require.NoError(t, err)
+	assert.Less(t, fi.Size(), int64(100))
+
+	// invalid station ids are not written
+	tr.write("..", TraceMessage{Direction: TraceSend, Message: json.RawMessage(`{}`)})
+	_, err = os.Stat(filepath.Join(dir, "...jsonl"))
+	assert.True(t, os.IsNotExist(err))
+}
@@ -0,0 +1,106 @@
+package ocpp
+
+import (
+	"cmp"
+	"sync"
+	"time"
+
+	"github.com/lorenzodonini/ocpp-go/ocpp1.6/core"
+)
+
+const (
+	TransactionStarted = "started"
+	TransactionStopped = "stopped"
+)
+
+// TransactionEvent is a transaction started or stopped at a charge point connector,
+// regardless of whether the transaction has been initiated by evcc
+type TransactionEvent struct {
+	Station       string        `json:"station"`
+	Connector     int           `json:"connector"`
+	Event         string        `json:"event"`
+	TransactionId int           `json:"transactionId"`
+	IdTag         string        `json:"idTag,omitempty"`
+	Start         time.Time     `json:"start,omitzero"`
+	Stop          time.Time     `json:"stop,omitzero"`
+	MeterStart    float64       `json:"meterStart"`          // kWh
+	MeterStop     float64       `json:"meterStop,omitempty"` // kWh
+	Duration      time.Duration `json:"duration,omitempty"`
+	Reason        string        `json:"reason,omitempty"`
+	Replayed      bool          `json:"replayed,omitempty"` // transaction queued by the charge point while offline
+}
+
+var (
+	transactionMu      sync.Mutex
+	transactionHandler func(TransactionEvent)
+)
+
+// SetTransactionEventHandler registers the handler receiving transaction events
+func SetTransactionEventHandler(fun func(TransactionEvent)) {
+	transactionMu.Lock()
+	defer transactionMu.Unlock()
+	transactionHandler = fun
+}
+
+func publishTransactionEvent(event TransactionEvent) {
+	transactionMetric.WithLabelValues(event.Event).Inc()
+
+	transactionMu.Lock()
+	fun := transactionHandler
+	transactionMu.Unlock()
+
+	if fun != nil {
+		fun(event)
+	}
+}
+
+// stopped completes the event from the stop transaction request
+func (event *TransactionEvent) stopped(request *core.StopTransactionRequest, now time.Time) {
+	event.Event = TransactionStopped
+	event.Stop = now
+	if request.Timestamp != nil {
+		event.Stop = request.Timestamp.Time
+	}
+	if !event.Start.IsZero() {
+		event.Duration = event.Stop.Sub(event.Start)
+	}
+	event.MeterStop = float64(request.MeterStop) / 1e3
+	event.Reason = string(request.Reason)
+}
+
+// OnTransactionStopped registers the handler receiving the connector's stopped transactions
+func (conn *Connector) OnTransactionStopped(fun func(TransactionEvent)) {
+	conn.mu.Lock()
+	defer conn.mu.Unlock()
+	conn.stoppedHandler = fun
+}
+
+// transactionStopped publishes the stopped transaction and notifies the connector's handler
+func (conn *Connector) transactionStopped(event TransactionEvent) {
+	publishTransactionEvent(event)
+
+	conn.mu.Lock()
+	fun := conn.stoppedHandler
+	conn.mu.Unlock()
+
+	if fun != nil {
+		fun(event)
+	}
+}
+
+// transactionEvent returns the event describing the connector's running transaction
+func (conn *Connector) transactionEvent(event string) TransactionEvent {
+	conn.mu.Lock()
+	defer conn.mu.Unlock()
+
+	return TransactionEvent{
+		Station:       conn.cp.ID(),
+		Connector:     conn.id,
+		Event:         event,
+		TransactionId: conn.txnId,
+		IdTag:         cmp.Or(conn.authIdTag, conn.idTag),
+		Start:         conn.txnStarted,
+		MeterStart:    float64(conn.txnMeterStart) / 1e3,
+		Replayed:      conn.txnReplayed,
+	}
+}
@@ -0,0 +1,76 @@
+package ocpp
+
+import (
+	"strconv"
+	"strings"
+
+	"github.com/lorenzodonini/ocpp-go/ocpp1.6/types"
+)
+
+// measurandUnit returns the canonical unit measurements are stored in
+func measurandUnit(m types.Measurand) types.UnitOfMeasure {
+	switch s := string(m); {
+	case strings.HasPrefix(s, "Energy.Reactive"):
+		return types.UnitOfMeasureVarh
+	case strings.HasPrefix(s, "Energy"):
+		return types.UnitOfMeasureWh
+	case strings.HasPrefix(s, "Power.Reactive"):
+		return types.UnitOfMeasureVar
+	case strings.HasPrefix(s, "Power.Factor"):
+		return ""
+	case strings.HasPrefix(s, "Power"):
+		return types.UnitOfMeasureW
+	case strings.HasPrefix(s, "Current"):
+		return types.UnitOfMeasureA
+	case strings.HasPrefix(s, "Voltage"):
+		return types.UnitOfMeasureV
+	case m == types.MeasurandSoC:
+		return types.UnitOfMeasurePercent
+	case m == types.MeasurandTemperature:
+		return types.UnitOfMeasureCelsius
+	default:
+		return ""
+	}
+}
+
+// normalizeSample converts the sampled value to the measurand's canonical unit, e.g. kWh to Wh.
+// Samples without unit are assumed to use the canonical unit. Unparsable values are kept as is.
+func normalizeSample(sample types.SampledValue, quirks Quirks) types.SampledValue {
+	sample.Value = strings.TrimSpace(sample.Value)
+
+	f, err := strconv.ParseFloat(sample.Value, 64)
+	if err != nil {
+		return sample
+	}
+
+	unit := sample.Unit
+	canonical := measurandUnit(sample.Measurand)
+
+	// energy sampled in Wh although the unit claims kWh
+	if quirks.EnergyWh && unit == types.UnitOfMeasureKWh && sample.Measurand == types.MeasurandEnergyActiveImportRegister {
+		unit = types.UnitOfMeasureWh
+	}
+
+	// current sampled in deci-amps
+	if quirks.DeciAmps && canonical == types.UnitOfMeasureA && (unit == "" || unit == types.UnitOfMeasureA) {
+		f /= 10
+	}
+
+	switch {
+	case unit == "" || unit == canonical || canonical == "":
+	case strings.HasPrefix(string(unit), "k") && types.UnitOfMeasure(unit[1:]) == canonical:
+		f *= 1e3
+	case strings.HasPrefix(string(unit), "m") && types.UnitOfMeasure(unit[1:]) == canonical:
+		f /= 1e3
+	default:
+		// unknown unit, keep as is
+		return sample
+	}
+
+	if canonical != "" {
+		sample.Unit = canonical
+	}
+	sample.Value = strconv.FormatFloat(f, 'f', -1, 64)
+
+	return sample
+}
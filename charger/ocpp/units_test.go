@@ -0,0 +1,36 @@
+package ocpp
+
+import (
+	"testing"
+
+	"github.com/lorenzodonini/ocpp-go/ocpp1.6/types"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNormalizeSample(t *testing.T) {
+	for _, tc := range []struct {
+		measurand types.Measurand
+		value     string
+		unit      types.UnitOfMeasure
+		quirks    Quirks
+		expected  string
+		expUnit   types.UnitOfMeasure
+	}{
+		{types.MeasurandEnergyActiveImportRegister, " 1.2 ", types.UnitOfMeasureKWh, Quirks{}, "1200", types.UnitOfMeasureWh},
+		{types.MeasurandEnergyActiveImportRegister, "1200", "", Quirks{}, "1200", types.UnitOfMeasureWh},
+		{types.MeasurandEnergyActiveImportRegister, "1200", types.UnitOfMeasureKWh, Quirks{EnergyWh: true}, "1200", types.UnitOfMeasureWh},
+		{types.MeasurandPowerActiveImport, "11", types.UnitOfMeasureKW, Quirks{}, "11000", types.UnitOfMeasureW},
+		{types.MeasurandPowerReactiveImport, "2", types.UnitOfMeasureKvar, Quirks{}, "2000", types.UnitOfMeasureVar},
+		{types.MeasurandCurrentImport, "160", types.UnitOfMeasureA, Quirks{DeciAmps: true}, "16", types.UnitOfMeasureA},
+		{types.MeasurandCurrentImport, "16", "", Quirks{}, "16", types.UnitOfMeasureA},
+		{types.MeasurandSoC, "80", types.UnitOfMeasurePercent, Quirks{}, "80", types.UnitOfMeasurePercent},
+		// power mislabelled with the spec's default unit
+		{types.MeasurandPowerActiveImport, "1000", types.UnitOfMeasureWh, Quirks{}, "1000", types.UnitOfMeasureWh},
+		// unparsable
+		{types.MeasurandPowerActiveImport, "foo", types.UnitOfMeasureKW, Quirks{}, "foo", types.UnitOfMeasureKW},
+	} {
+		res := normalizeSample(types.SampledValue{Measurand: tc.measurand, Value: tc.value, Unit: tc.unit}, tc.quirks)
+		assert.Equal(t, tc.expected, res.Value, tc)
+		assert.Equal(t, tc.expUnit, res.Unit, tc)
+	}
+}
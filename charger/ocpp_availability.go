@@ -0,0 +1,49 @@
+package charger
+
+import (
+	"time"
+
+	"github.com/evcc-io/evcc/api"
+	"github.com/lorenzodonini/ocpp-go/ocpp1.6/core"
+)
+
+// updateAvailability takes the idle connector out of service once the loadpoint has been off for the
+// configured duration and returns it into operation when the loadpoint is switched on again
+func (c *OCPP) updateAvailability(status core.ChargePointStatus) {
+	if c.inoperativeAfter == 0 || c.lp == nil {
+		return
+	}
+
+	if c.lp.GetMode() != api.ModeOff {
+		c.offSince = time.Time{}
+
+		if c.inoperative {
+			if err := c.conn.ChangeAvailabilityRequest(core.AvailabilityTypeOperative); err != nil {
+				c.log.ERROR.Printf("change availability: %v", err)
+				return
+			}
+
+			c.log.DEBUG.Println("connector operative")
+			c.inoperative = false
+		}
+
+		return
+	}
+
+	if c.offSince.IsZero() {
+		c.offSince = time.Now()
+	}
+
+	// transactions would delay the change until finished
+	if c.inoperative || status != core.ChargePointStatusAvailable || time.Since(c.offSince) < c.inoperativeAfter {
+		return
+	}
+
+	if err := c.conn.ChangeAvailabilityRequest(core.AvailabilityTypeInoperative); err != nil {
+		c.log.ERROR.Printf("change availability: %v", err)
+		return
+	}
+
+	c.log.DEBUG.Println("connector inoperative")
+	c.inoperative = true
+}
@@ -0,0 +1,63 @@
+package charger
+
+import (
+	"cmp"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/evcc-io/evcc/util"
+)
+
+// ocppLimit configures current limiting for charge points not supporting smart charging
+type ocppLimit struct {
+	Mode      string // profile (default), config or datatransfer
+	Key       string // configuration key receiving the current (config)
+	VendorId  string // data transfer vendor (datatransfer)
+	MessageId string // data transfer message (datatransfer)
+	Value     string // value template, defaults to ${current}
+}
+
+// limiter returns the function applying the current unless limited using charging profiles
+func (l ocppLimit) limiter(c *OCPP) (func(float64) error, error) {
+	value := cmp.Or(l.Value, "${current}")
+
+	format := func(current float64) (string, error) {
+		return util.ReplaceFormatted(value, map[string]any{"current": current})
+	}
+
+	switch strings.ToLower(l.Mode) {
+	case "", "profile":
+		return nil, nil
+
+	case "config":
+		if l.Key == "" {
+			return nil, errors.New("missing limit configuration key")
+		}
+
+		// configuration keys apply to the charge point as a whole
+		return func(current float64) error {
+			val, err := format(current)
+			if err == nil {
+				err = c.cp.ChangeConfigurationRequest(l.Key, val)
+			}
+			return err
+		}, nil
+
+	case "datatransfer":
+		if l.VendorId == "" {
+			return nil, errors.New("missing limit data transfer vendor id")
+		}
+
+		return func(current float64) error {
+			val, err := format(current)
+			if err == nil {
+				_, err = c.conn.DataTransferRequest(l.VendorId, l.MessageId, val)
+			}
+			return err
+		}, nil
+
+	default:
+		return nil, fmt.Errorf("invalid limit mode: %s", l.Mode)
+	}
+}
@@ -8,6 +8,8 @@ import (
 	"github.com/benbjohnson/clock"
 	"github.com/evcc-io/evcc/api"
 	"github.com/evcc-io/evcc/charger/ocpp"
+	"github.com/evcc-io/evcc/core/loadpoint"
+	"github.com/evcc-io/evcc/util"
 	ocppapi "github.com/lorenzodonini/ocpp-go/ocpp"
 	ocpp16 "github.com/lorenzodonini/ocpp-go/ocpp1.6"
 	"github.com/lorenzodonini/ocpp-go/ocpp1.6/core"
@@ -19,7 +21,10 @@ import (
 	"github.com/lorenzodonini/ocpp-go/ocpp1.6/types"
 	"github.com/lorenzodonini/ocpp-go/ocppj"
 	"github.com/lorenzodonini/ocpp-go/ws"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 	"github.com/stretchr/testify/suite"
+	"go.uber.org/mock/gomock"
 )
 
 const (
@@ -48,6 +53,11 @@ func (suite *ocppTestSuite) SetupSuite() {
 }
 
 func (suite *ocppTestSuite) startChargePoint(id string, connectorId int) (ocpp16.ChargePoint, *ocppj.Client) {
+	cp, endpoint, _ := suite.startChargePointWithHandler(id, connectorId)
+	return cp, endpoint
+}
+
+func (suite *ocppTestSuite) startChargePointWithHandler(id string, connectorId int) (ocpp16.ChargePoint, *ocppj.Client, *ChargePointHandler) {
 	// set a handler for all callback functions
 	handler := &ChargePointHandler{
 		triggerC: make(chan remotetrigger.MessageTrigger, 1),
@@ -64,6 +74,9 @@ func (suite *ocppTestSuite) startChargePoint(id string, connectorId int) (ocpp16
 	cp.SetCoreHandler(handler)
 	cp.SetRemoteTriggerHandler(handler)
 	cp.SetSmartChargingHandler(handler)
+	cp.SetFirmwareManagementHandler(handler)
+	cp.SetLocalAuthListHandler(handler)
+	cp.SetReservationHandler(handler)
 
 	// let cs handle the trigger messages
 	go func() {
@@ -72,7 +85,7 @@ func (suite *ocppTestSuite) startChargePoint(id string, connectorId int) (ocpp16
 		}
 	}()
 
-	return cp, endpoint
+	return cp, endpoint, handler
 }
 
 func (suite *ocppTestSuite) handleTrigger(cp ocpp16.ChargePoint, connectorId int, msg remotetrigger.MessageTrigger) {
@@ -254,3 +267,548 @@ func (suite *ocppTestSuite) TestTimeout() {
 
 	suite.Require().NoError(err)
 }
+
+func (suite *ocppTestSuite) TestBasicAuth() {
+	ocpp.Instance().SetCredentials("test-auth", "user", "secret")
+
+	// rejected without credentials
+	cp1, _ := suite.startChargePoint("test-auth", 1)
+	suite.Require().Error(cp1.Start(ocppTestUrl))
+
+	// rejected with invalid credentials
+	client := ws.NewClient()
+	client.SetRequestedSubProtocol(types.V16Subprotocol)
+	client.SetBasicAuth("user", "invalid")
+	suite.Require().Error(client.Start(ocppTestUrl + "/test-auth"))
+
+	// accepted with valid credentials
+	client.SetBasicAuth("user", "secret")
+	suite.Require().NoError(client.Start(ocppTestUrl + "/test-auth"))
+	client.Stop()
+}
+
+func (suite *ocppTestSuite) TestDataTransfer() {
+	cp1, _ := suite.startChargePoint("test-dt", 1)
+	suite.Require().NoError(cp1.Start(ocppTestUrl))
+	suite.Require().True(cp1.IsConnected())
+
+	c, err := NewOCPP(suite.T().Context(), "test-dt", 1, "", "", 0, false, false, false, false, ocppTestConnectTimeout)
+	suite.Require().NoError(err)
+
+	type payload struct {
+		Enabled bool `json:"enabled"`
+	}
+
+	res, err := ocpp.DataTransfer[payload](c.cp, "test", "enable", payload{Enabled: true})
+	suite.Require().NoError(err)
+	suite.True(res.Enabled)
+
+	// json encoded as string
+	res, err = ocpp.DataTransfer[payload](c.cp, "test", "enable", `{"enabled":true}`)
+	suite.Require().NoError(err)
+	suite.True(res.Enabled)
+
+	_, err = c.Connector().DataTransferRequest("unknown", "enable", nil)
+	suite.Require().Error(err)
+}
+
+func (suite *ocppTestSuite) TestProvisioning() {
+	cp1, _, handler := suite.startChargePointWithHandler("test-provisioning", 1)
+	suite.Require().NoError(cp1.Start(ocppTestUrl))
+	suite.Require().True(cp1.IsConnected())
+
+	c, err := NewOCPP(suite.T().Context(), "test-provisioning", 1, "", "", 0, false, false, false, false, ocppTestConnectTimeout)
+	suite.Require().NoError(err)
+
+	provisioned := func() bool {
+		v, ok := handler.config.Load("HeartbeatInterval")
+		return ok && v == "60"
+	}
+
+	c.cp.SetProvisioning(map[string]string{"HeartbeatInterval": "60"})
+	suite.Eventually(provisioned, ocpp.Timeout, 10*time.Millisecond)
+
+	// factory reset
+	handler.config.Delete("HeartbeatInterval")
+
+	_, err = cp1.BootNotification("model", "vendor")
+	suite.Require().NoError(err)
+	suite.Eventually(provisioned, ocpp.Timeout, 10*time.Millisecond)
+}
+
+func (suite *ocppTestSuite) TestUpdateFirmware() {
+	cp1, _, handler := suite.startChargePointWithHandler("test-firmware", 1)
+	handler.firmware = make(chan string, 1)
+	suite.Require().NoError(cp1.Start(ocppTestUrl))
+	suite.Require().True(cp1.IsConnected())
+
+	c, err := NewOCPP(suite.T().Context(), "test-firmware", 1, "", "", 0, false, false, false, false, ocppTestConnectTimeout)
+	suite.Require().NoError(err)
+
+	location := "http://example.com/firmware.bin"
+	suite.Require().NoError(c.cp.UpdateFirmwareRequest(location, time.Now(), 3, time.Minute))
+	suite.Equal(location, <-handler.firmware)
+
+	_, err = cp1.FirmwareStatusNotification(firmware.FirmwareStatusDownloading)
+	suite.Require().NoError(err)
+
+	status := ocpp.FirmwareStatuses()["test-firmware"]
+	suite.Equal(string(firmware.FirmwareStatusDownloading), status.Status)
+	suite.Equal(location, status.Location)
+}
+
+func (suite *ocppTestSuite) TestGetDiagnostics() {
+	cp1, _ := suite.startChargePoint("test-diagnostics", 1)
+	suite.Require().NoError(cp1.Start(ocppTestUrl))
+	suite.Require().True(cp1.IsConnected())
+
+	c, err := NewOCPP(suite.T().Context(), "test-diagnostics", 1, "", "", 0, false, false, false, false, ocppTestConnectTimeout)
+	suite.Require().NoError(err)
+
+	file, err := c.cp.GetDiagnosticsRequest("http://example.com/upload", time.Now().Add(-time.Hour), time.Now(), 0, 0)
+	suite.Require().NoError(err)
+	suite.Equal("diagnostics.zip", file)
+
+	_, err = cp1.DiagnosticsStatusNotification(firmware.DiagnosticsStatusUploaded)
+	suite.Require().NoError(err)
+
+	status := ocpp.DiagnosticsStatuses()["test-diagnostics"]
+	suite.Equal(string(firmware.DiagnosticsStatusUploaded), status.Status)
+	suite.Equal(file, status.File)
+}
+
+func (suite *ocppTestSuite) TestLocalAuthList() {
+	cp1, _, handler := suite.startChargePointWithHandler("test-localauth", 1)
+	suite.Require().NoError(cp1.Start(ocppTestUrl))
+	suite.Require().True(cp1.IsConnected())
+
+	c, err := NewOCPP(suite.T().Context(), "test-localauth", 1, "", "", 0, false, false, false, false, ocppTestConnectTimeout)
+	suite.Require().NoError(err)
+
+	ocpp.SetLocalAuthList([]string{"12345", "12345", "placeholder*", "abc"})
+	defer ocpp.SetLocalAuthList(nil)
+
+	suite.Eventually(func() bool {
+		return c.cp.LocalAuthListVersion() != 0
+	}, ocpp.Timeout, 10*time.Millisecond)
+
+	suite.Equal(int32(c.cp.LocalAuthListVersion()), handler.localListVersion.Load())
+	suite.Equal(int32(2), handler.localList.Load())
+
+	// list is re-sent after factory reset
+	handler.localListVersion.Store(0)
+	handler.localList.Store(0)
+
+	_, err = cp1.BootNotification("model", "vendor")
+	suite.Require().NoError(err)
+
+	suite.Eventually(func() bool {
+		return handler.localList.Load() == 2
+	}, ocpp.Timeout, 10*time.Millisecond)
+}
+
+func (suite *ocppTestSuite) TestReservation() {
+	cp1, _, handler := suite.startChargePointWithHandler("test-reservation", 1)
+	suite.Require().NoError(cp1.Start(ocppTestUrl))
+	suite.Require().True(cp1.IsConnected())
+
+	c, err := NewOCPP(suite.T().Context(), "test-reservation", 1, "", "", 0, false, false, false, false, ocppTestConnectTimeout)
+	suite.Require().NoError(err)
+
+	expiry := time.Now().Add(time.Hour)
+	suite.Require().NoError(c.Reserve(expiry, "12345"))
+
+	id, _ := c.conn.Reservation()
+	suite.NotZero(id)
+	suite.Equal(int32(id), handler.reservationId.Load())
+
+	// replacing keeps the reservation id
+	suite.Require().NoError(c.Reserve(expiry.Add(time.Hour), "12345"))
+	id2, _ := c.conn.Reservation()
+	suite.Equal(id, id2)
+
+	suite.Require().NoError(c.CancelReservation())
+	suite.Zero(handler.reservationId.Load())
+	suite.Error(c.CancelReservation())
+}
+
+func (suite *ocppTestSuite) TestAuthorization() {
+	cp1, _ := suite.startChargePoint("test-authorization", 1)
+	suite.Require().NoError(cp1.Start(ocppTestUrl))
+	suite.Require().True(cp1.IsConnected())
+
+	c, err := NewOCPP(suite.T().Context(), "test-authorization", 1, "", "", 0, false, false, false, false, ocppTestConnectTimeout)
+	suite.Require().NoError(err)
+
+	ocpp.SetAuthorizedIdentifiers([]string{"known*"})
+	defer ocpp.SetAuthorizedIdentifiers(nil)
+
+	c.conn.SetAuthorization(ocpp.AuthorizationKnown)
+
+	res, err := cp1.Authorize("known-1")
+	suite.Require().NoError(err)
+	suite.Equal(types.AuthorizationStatusAccepted, res.IdTagInfo.Status)
+
+	res, err = cp1.Authorize("stranger")
+	suite.Require().NoError(err)
+	suite.Equal(types.AuthorizationStatusInvalid, res.IdTagInfo.Status)
+
+	rejected := ocpp.RejectedAuthorizations()
+	suite.Require().NotEmpty(rejected)
+	suite.Equal("stranger", rejected[len(rejected)-1].IdTag)
+}
+
+func (suite *ocppTestSuite) TestIdentify() {
+	cp1, _ := suite.startChargePoint("test-identify", 1)
+	suite.Require().NoError(cp1.Start(ocppTestUrl))
+	suite.Require().True(cp1.IsConnected())
+
+	c, err := NewOCPP(suite.T().Context(), "test-identify", 1, "", "", 0, false, false, false, false, ocppTestConnectTimeout)
+	suite.Require().NoError(err)
+
+	_, err = cp1.Authorize("rfid-1")
+	suite.Require().NoError(err)
+
+	id, err := c.Identify()
+	suite.Require().NoError(err)
+	suite.Equal("rfid-1", id)
+}
+
+func (suite *ocppTestSuite) TestRemoteStopTransaction() {
+	cp1, _, handler := suite.startChargePointWithHandler("test-remotestop", 1)
+	suite.Require().NoError(cp1.Start(ocppTestUrl))
+	suite.Require().True(cp1.IsConnected())
+
+	c, err := NewOCPP(suite.T().Context(), "test-remotestop", 1, "", "", 0, false, false, false, false, ocppTestConnectTimeout)
+	suite.Require().NoError(err)
+
+	res, err := cp1.StartTransaction(1, "12345", 1000, types.NewDateTime(time.Now()))
+	suite.Require().NoError(err)
+
+	txnId, err := c.conn.TransactionID()
+	suite.Require().NoError(err)
+	suite.Equal(res.TransactionId, txnId)
+
+	suite.Require().NoError(c.conn.RemoteStopTransactionRequest(txnId))
+	suite.Equal(int32(txnId), handler.stoppedTxnId.Load())
+}
+
+func (suite *ocppTestSuite) TestChargingProfiles() {
+	cp1, _, handler := suite.startChargePointWithHandler("test-profiles", 1)
+	suite.Require().NoError(cp1.Start(ocppTestUrl))
+	suite.Require().True(cp1.IsConnected())
+
+	c, err := NewOCPP(suite.T().Context(), "test-profiles", 1, "", "", 0, false, false, false, false, ocppTestConnectTimeout)
+	suite.Require().NoError(err)
+
+	profile := func(purpose types.ChargingProfilePurposeType) *types.ChargingProfile {
+		res, ok := handler.profiles.Load(purpose)
+		suite.Require().True(ok, purpose)
+		return res.(*types.ChargingProfile)
+	}
+
+	// static limit
+	suite.Require().NoError(c.setMaxCurrent(32, 0))
+	suite.Equal(32.0, profile(types.ChargingProfilePurposeChargePointMaxProfile).ChargingSchedule.ChargingSchedulePeriod[0].Limit)
+
+	// tx profile falls back to tx default profile without transaction
+	level := 2
+	c.txProfile, c.stackLevel = true, &level
+
+	suite.Require().NoError(c.setCurrent(10))
+	def := profile(types.ChargingProfilePurposeTxDefaultProfile)
+	suite.Equal(2, def.StackLevel)
+	suite.Equal(10.0, def.ChargingSchedule.ChargingSchedulePeriod[0].Limit)
+
+	res, err := cp1.StartTransaction(1, "12345", 1000, types.NewDateTime(time.Now()))
+	suite.Require().NoError(err)
+
+	suite.Require().NoError(c.setCurrent(6))
+	tx := profile(types.ChargingProfilePurposeTxProfile)
+	suite.Equal(res.TransactionId, tx.TransactionId)
+	suite.NotEqual(def.ChargingProfileId, tx.ChargingProfileId)
+	suite.Equal(6.0, tx.ChargingSchedule.ChargingSchedulePeriod[0].Limit)
+}
+
+func (suite *ocppTestSuite) TestVerifyProfile() {
+	cp1, _, handler := suite.startChargePointWithHandler("test-verify", 1)
+	suite.Require().NoError(cp1.Start(ocppTestUrl))
+	suite.Require().True(cp1.IsConnected())
+
+	c, err := NewOCPP(suite.T().Context(), "test-verify", 1, "", "", 0, false, false, false, false, ocppTestConnectTimeout)
+	suite.Require().NoError(err)
+
+	// applied limit
+	handler.compositeLimit.Store(10)
+	suite.NoError(c.setCurrent(10))
+
+	// deviating limit
+	suite.ErrorIs(c.setCurrent(16), ocpp.ErrLimitDeviation)
+
+	// limited by charge point max profile
+	suite.Require().NoError(c.setMaxCurrent(10, 0))
+	suite.NoError(c.setCurrent(16))
+}
+
+func (suite *ocppTestSuite) TestLimit() {
+	cp1, _, handler := suite.startChargePointWithHandler("test-limit", 1)
+	suite.Require().NoError(cp1.Start(ocppTestUrl))
+	suite.Require().True(cp1.IsConnected())
+
+	c, err := NewOCPP(suite.T().Context(), "test-limit", 1, "", "", 0, false, false, false, false, ocppTestConnectTimeout)
+	suite.Require().NoError(err)
+
+	// configuration key
+	c.limiter, err = ocppLimit{Mode: "config", Key: "MaxCurrent", Value: "${current:%.0fm}"}.limiter(c)
+	suite.Require().NoError(err)
+
+	suite.Require().NoError(c.MaxCurrentMillis(12.5))
+	val, _ := handler.config.Load("MaxCurrent")
+	suite.Equal("12500", val)
+
+	// data transfer
+	c.limiter, err = ocppLimit{Mode: "datatransfer", VendorId: "test"}.limiter(c)
+	suite.Require().NoError(err)
+	suite.NoError(c.MaxCurrentMillis(16))
+
+	c.limiter, err = ocppLimit{Mode: "datatransfer", VendorId: "unknown"}.limiter(c)
+	suite.Require().NoError(err)
+	suite.Error(c.MaxCurrentMillis(16))
+}
+
+func (suite *ocppTestSuite) TestUnlockConnector() {
+	cp1, _ := suite.startChargePoint("test-unlock", 1)
+	suite.Require().NoError(cp1.Start(ocppTestUrl))
+	suite.Require().True(cp1.IsConnected())
+
+	c, err := NewOCPP(suite.T().Context(), "test-unlock", 1, "", "", 0, false, false, false, false, ocppTestConnectTimeout)
+	suite.Require().NoError(err)
+
+	suite.NoError(c.UnlockConnector())
+}
+
+func (suite *ocppTestSuite) TestReset() {
+	cp1, _ := suite.startChargePoint("test-reset", 1)
+	suite.Require().NoError(cp1.Start(ocppTestUrl))
+	suite.Require().True(cp1.IsConnected())
+
+	c, err := NewOCPP(suite.T().Context(), "test-reset", 1, "", "", 0, false, false, false, false, ocppTestConnectTimeout)
+	suite.Require().NoError(err)
+
+	suite.Require().NoError(c.cp.Reset(core.ResetTypeHard))
+	suite.Equal(ocpp.ResetAccepted, ocpp.ResetStatuses()["test-reset"].Status)
+	suite.Equal(core.ResetTypeHard, ocpp.ResetStatuses()["test-reset"].Type)
+
+	// charge point booted
+	_, err = cp1.BootNotification("model", "vendor")
+	suite.Require().NoError(err)
+	suite.Equal(ocpp.ResetCompleted, ocpp.ResetStatuses()["test-reset"].Status)
+}
+
+func (suite *ocppTestSuite) TestAvailability() {
+	cp1, _, handler := suite.startChargePointWithHandler("test-availability", 1)
+	suite.Require().NoError(cp1.Start(ocppTestUrl))
+	suite.Require().True(cp1.IsConnected())
+
+	c, err := NewOCPP(suite.T().Context(), "test-availability", 1, "", "", 0, false, false, false, false, ocppTestConnectTimeout)
+	suite.Require().NoError(err)
+
+	ctrl := gomock.NewController(suite.T())
+	lp := loadpoint.NewMockAPI(ctrl)
+	c.lp = lp
+	c.inoperativeAfter = time.Hour
+
+	// off, but not long enough
+	lp.EXPECT().GetMode().Return(api.ModeOff).Times(2)
+	c.updateAvailability(core.ChargePointStatusAvailable)
+	suite.False(c.inoperative)
+
+	c.offSince = time.Now().Add(-time.Hour)
+	c.updateAvailability(core.ChargePointStatusAvailable)
+	suite.True(c.inoperative)
+	suite.Equal(core.AvailabilityTypeInoperative, handler.availability.Load())
+
+	// switched on
+	lp.EXPECT().GetMode().Return(api.ModeNow)
+	c.updateAvailability(core.ChargePointStatusUnavailable)
+	suite.False(c.inoperative)
+	suite.True(c.offSince.IsZero())
+	suite.Equal(core.AvailabilityTypeOperative, handler.availability.Load())
+}
+
+func (suite *ocppTestSuite) TestMultipleConnectors() {
+	cp1, _, handler := suite.startChargePointWithHandler("test-connectors", 1)
+	handler.connectors = 2
+	suite.Require().NoError(cp1.Start(ocppTestUrl))
+	suite.Require().True(cp1.IsConnected())
+
+	c1, err := NewOCPP(suite.T().Context(), "test-connectors", 1, "", "", 0, false, false, false, false, ocppTestConnectTimeout)
+	suite.Require().NoError(err)
+
+	// status of 2nd connector before it has been configured
+	_, err = cp1.StatusNotification(2, core.NoError, core.ChargePointStatusPreparing)
+	suite.Require().NoError(err)
+
+	c2, err := NewOCPP(suite.T().Context(), "test-connectors", 2, "", "", 0, false, false, false, false, ocppTestConnectTimeout)
+	suite.Require().NoError(err)
+	suite.Same(c1.cp, c2.cp)
+	suite.Equal(2, ocpp.ChargePointCapabilities()["test-connectors"].NumberOfConnectors)
+
+	// connectors can't exceed the charge point's connectors
+	_, err = NewOCPP(suite.T().Context(), "test-connectors", 3, "", "", 0, false, false, false, false, ocppTestConnectTimeout)
+	suite.Error(err)
+
+	status, err := c1.Status()
+	suite.Require().NoError(err)
+	suite.Equal(api.StatusC, status)
+
+	status, err = c2.Status()
+	suite.Require().NoError(err)
+	suite.Equal(api.StatusB, status)
+
+	// meter values are routed by connector
+	_, err = cp1.MeterValues(2, []types.MeterValue{
+		{
+			Timestamp: types.NewDateTime(time.Now()),
+			SampledValue: []types.SampledValue{
+				{Measurand: types.MeasurandPowerActiveImport, Value: "2000"},
+			},
+		},
+	})
+	suite.Require().NoError(err)
+
+	power, err := c2.conn.CurrentPower()
+	suite.Require().NoError(err)
+	suite.Equal(2000.0, power)
+
+	// charge point faults apply to all connectors
+	_, err = cp1.StatusNotification(0, core.PowerSwitchFailure, core.ChargePointStatusFaulted)
+	suite.Require().NoError(err)
+
+	for _, c := range []*OCPP{c1, c2} {
+		status, err := c.Status()
+		suite.Error(err)
+		suite.Equal(api.StatusF, status)
+	}
+
+	_, err = cp1.StatusNotification(0, core.NoError, core.ChargePointStatusAvailable)
+	suite.Require().NoError(err)
+
+	status, err = c2.Status()
+	suite.Require().NoError(err)
+	suite.Equal(api.StatusB, status)
+}
+
+func (suite *ocppTestSuite) TestReconfigure() {
+	cp1, _ := suite.startChargePoint("test-reconfigure", 1)
+	suite.Require().NoError(cp1.Start(ocppTestUrl))
+	suite.Require().True(cp1.IsConnected())
+
+	c1, err := NewOCPP(suite.T().Context(), "test-reconfigure", 1, "", "", 0, false, false, false, false, ocppTestConnectTimeout)
+	suite.Require().NoError(err)
+
+	_, err = cp1.StatusNotification(1, core.NoError, core.ChargePointStatusCharging)
+	suite.Require().NoError(err)
+
+	// reconfigured charger continues with the connector's state
+	c2, err := NewOCPP(suite.T().Context(), "test-reconfigure", 1, "", "", 0, false, false, false, false, ocppTestConnectTimeout)
+	suite.Require().NoError(err)
+	suite.Same(c1.cp, c2.cp)
+
+	suite.Require().NoError(c1.Close())
+
+	status, err := c2.Status()
+	suite.Require().NoError(err)
+	suite.Equal(api.StatusC, status)
+
+	_, err = cp1.StatusNotification(1, core.NoError, core.ChargePointStatusSuspendedEV)
+	suite.Require().NoError(err)
+
+	status, err = c2.Status()
+	suite.Require().NoError(err)
+	suite.Equal(api.StatusB, status)
+
+	// changed station id releases the previous charge point, keeping it connected
+	cp2, _ := suite.startChargePoint("test-reconfigure-2", 1)
+	suite.Require().NoError(cp2.Start(ocppTestUrl))
+	suite.Require().True(cp2.IsConnected())
+
+	c3, err := NewOCPP(suite.T().Context(), "test-reconfigure-2", 1, "", "", 0, false, false, false, false, ocppTestConnectTimeout)
+	suite.Require().NoError(err)
+	suite.Require().NoError(c2.Close())
+
+	suite.Eventually(func() bool {
+		_, ok := ocpp.PendingChargePoints()["test-reconfigure"]
+		return ok
+	}, time.Second, 10*time.Millisecond)
+
+	_, err = ocpp.Instance().ChargepointByID("test-reconfigure")
+	suite.Error(err)
+	suite.True(cp1.IsConnected())
+
+	cp, err := ocpp.Instance().ChargepointByID("test-reconfigure-2")
+	suite.Require().NoError(err)
+	suite.Same(c3.cp, cp)
+}
+
+func (suite *ocppTestSuite) TestPending() {
+	cp1, _ := suite.startChargePoint("test-pending", 1)
+	suite.Require().NoError(cp1.Start(ocppTestUrl))
+	suite.Require().True(cp1.IsConnected())
+
+	// unknown charge point
+	res, err := cp1.BootNotification("model", "vendor")
+	suite.Require().NoError(err)
+	suite.Equal(core.RegistrationStatusPending, res.Status)
+
+	pending, ok := ocpp.PendingChargePoints()["test-pending"]
+	suite.Require().True(ok)
+	suite.Equal("vendor", pending.Vendor)
+	suite.Equal("model", pending.Model)
+	suite.True(pending.Connected)
+
+	// adopted
+	_, err = NewOCPP(suite.T().Context(), "test-pending", 1, "", "", 0, false, false, false, false, ocppTestConnectTimeout)
+	suite.Require().NoError(err)
+	suite.NotContains(ocpp.PendingChargePoints(), "test-pending")
+}
+
+func TestOcppLimitMode(t *testing.T) {
+	for _, l := range []ocppLimit{{}, {Mode: "Profile"}} {
+		fun, err := l.limiter(nil)
+		assert.NoError(t, err)
+		assert.Nil(t, fun)
+	}
+
+	for _, l := range []ocppLimit{{Mode: "config"}, {Mode: "datatransfer"}, {Mode: "foo"}} {
+		_, err := l.limiter(nil)
+		assert.Error(t, err, l.Mode)
+	}
+}
+
+func TestOcppPlanPeriods(t *testing.T) {
+	cp := ocpp.NewChargePoint(util.NewLogger("foo"), "plan")
+	cp.MaxSchedulePeriods = 4
+
+	start := time.Now()
+	c := &OCPP{
+		cp:          cp,
+		planCurrent: 16,
+		planSlots: api.Rates{
+			{Start: start.Add(-time.Hour), End: start.Add(time.Hour)}, // active
+			{Start: start.Add(2 * time.Hour), End: start.Add(3 * time.Hour)},
+			{Start: start.Add(4 * time.Hour), End: start.Add(5 * time.Hour)}, // exceeds max periods
+		},
+	}
+
+	periods := c.planPeriods(start, 6)
+	require.Len(t, periods, 3)
+
+	assert.Equal(t, types.NewChargingSchedulePeriod(0, 6), periods[0])
+	assert.Equal(t, types.NewChargingSchedulePeriod(7200, 16), periods[1])
+	assert.Equal(t, types.NewChargingSchedulePeriod(10800, 6), periods[2])
+
+	// single period without schedule support
+	cp.MaxSchedulePeriods = 0
+	assert.Len(t, c.planPeriods(start, 6), 1)
+}
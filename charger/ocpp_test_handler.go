@@ -1,24 +1,48 @@
 package charger
 
 import (
+	"strconv"
+	"sync"
+	"sync/atomic"
+
 	"github.com/lorenzodonini/ocpp-go/ocpp1.6/core"
+	"github.com/lorenzodonini/ocpp-go/ocpp1.6/firmware"
+	"github.com/lorenzodonini/ocpp-go/ocpp1.6/localauth"
 	"github.com/lorenzodonini/ocpp-go/ocpp1.6/remotetrigger"
+	"github.com/lorenzodonini/ocpp-go/ocpp1.6/reservation"
 	"github.com/lorenzodonini/ocpp-go/ocpp1.6/smartcharging"
 	"github.com/lorenzodonini/ocpp-go/ocpp1.6/types"
 )
 
 type ChargePointHandler struct {
 	triggerC chan remotetrigger.MessageTrigger
+	config   sync.Map // changed configuration keys
+	firmware chan string
+
+	connectors int // reported number of connectors, single connector if zero
+
+	localListVersion atomic.Int32
+	localList        atomic.Int32 // number of local list entries
+
+	reservationId atomic.Int32 // active reservation
+	stoppedTxnId  atomic.Int32 // remotely stopped transaction
+
+	availability atomic.Value // last availability type
+
+	profiles       sync.Map     // charging profiles by purpose
+	compositeLimit atomic.Int32 // composite schedule limit, none if zero
 }
 
 // core
 
 func (handler *ChargePointHandler) OnChangeAvailability(request *core.ChangeAvailabilityRequest) (confirmation *core.ChangeAvailabilityConfirmation, err error) {
+	handler.availability.Store(request.Type)
 	defer func() { handler.triggerC <- core.ChangeAvailabilityFeatureName }()
 	return core.NewChangeAvailabilityConfirmation(core.AvailabilityStatusAccepted), nil
 }
 
 func (handler *ChargePointHandler) OnChangeConfiguration(request *core.ChangeConfigurationRequest) (confirmation *core.ChangeConfigurationConfirmation, err error) {
+	handler.config.Store(request.Key, request.Value)
 	return core.NewChangeConfigurationConfirmation(core.ConfigurationStatusAccepted), nil
 }
 
@@ -27,15 +51,23 @@ func (handler *ChargePointHandler) OnClearCache(request *core.ClearCacheRequest)
 }
 
 func (handler *ChargePointHandler) OnDataTransfer(request *core.DataTransferRequest) (confirmation *core.DataTransferConfirmation, err error) {
-	return core.NewDataTransferConfirmation(core.DataTransferStatusAccepted), nil
+	if request.VendorId != "test" {
+		return core.NewDataTransferConfirmation(core.DataTransferStatusUnknownVendorId), nil
+	}
+
+	// echo
+	res := core.NewDataTransferConfirmation(core.DataTransferStatusAccepted)
+	res.Data = request.Data
+	return res, nil
 }
 
 func (handler *ChargePointHandler) OnGetConfiguration(request *core.GetConfigurationRequest) (confirmation *core.GetConfigurationConfirmation, err error) {
 	one := "1"
+	connectors := strconv.Itoa(max(handler.connectors, 1))
 	meter := "Power.Active.Import,Energy.Active.Import.Register"
 	return core.NewGetConfigurationConfirmation([]core.ConfigurationKey{
 		{Key: "AuthorizationKey"},
-		{Key: "NumberOfConnectors", Value: &one},
+		{Key: "NumberOfConnectors", Value: &connectors},
 		{Key: "ChargeProfileMaxStackLevel", Value: &one},
 		{Key: "ChargingScheduleMaxPeriods", Value: &one},
 		{Key: "MaxChargingProfilesInstalled", Value: &one},
@@ -49,6 +81,7 @@ func (handler *ChargePointHandler) OnRemoteStartTransaction(request *core.Remote
 }
 
 func (handler *ChargePointHandler) OnRemoteStopTransaction(request *core.RemoteStopTransactionRequest) (confirmation *core.RemoteStopTransactionConfirmation, err error) {
+	handler.stoppedTxnId.Store(int32(request.TransactionId))
 	return core.NewRemoteStopTransactionConfirmation(types.RemoteStartStopStatusAccepted), nil
 }
 
@@ -65,9 +98,37 @@ func (handler *ChargePointHandler) OnTriggerMessage(request *remotetrigger.Trigg
 	return remotetrigger.NewTriggerMessageConfirmation(remotetrigger.TriggerMessageStatusAccepted), nil
 }
 
+// firmware
+
+func (handler *ChargePointHandler) OnUpdateFirmware(request *firmware.UpdateFirmwareRequest) (*firmware.UpdateFirmwareConfirmation, error) {
+	if handler.firmware != nil {
+		handler.firmware <- request.Location
+	}
+	return firmware.NewUpdateFirmwareConfirmation(), nil
+}
+
+func (handler *ChargePointHandler) OnGetDiagnostics(request *firmware.GetDiagnosticsRequest) (*firmware.GetDiagnosticsConfirmation, error) {
+	res := firmware.NewGetDiagnosticsConfirmation()
+	res.FileName = "diagnostics.zip"
+	return res, nil
+}
+
+// local auth list
+
+func (handler *ChargePointHandler) OnGetLocalListVersion(request *localauth.GetLocalListVersionRequest) (*localauth.GetLocalListVersionConfirmation, error) {
+	return localauth.NewGetLocalListVersionConfirmation(int(handler.localListVersion.Load())), nil
+}
+
+func (handler *ChargePointHandler) OnSendLocalList(request *localauth.SendLocalListRequest) (*localauth.SendLocalListConfirmation, error) {
+	handler.localListVersion.Store(int32(request.ListVersion))
+	handler.localList.Store(int32(len(request.LocalAuthorizationList)))
+	return localauth.NewSendLocalListConfirmation(localauth.UpdateStatusAccepted), nil
+}
+
 // smart charging
 
 func (handler *ChargePointHandler) OnSetChargingProfile(request *smartcharging.SetChargingProfileRequest) (*smartcharging.SetChargingProfileConfirmation, error) {
+	handler.profiles.Store(request.ChargingProfile.ChargingProfilePurpose, request.ChargingProfile)
 	return smartcharging.NewSetChargingProfileConfirmation(smartcharging.ChargingProfileStatusAccepted), nil
 }
 
@@ -76,5 +137,25 @@ func (handler *ChargePointHandler) OnClearChargingProfile(request *smartcharging
 }
 
 func (handler *ChargePointHandler) OnGetCompositeSchedule(request *smartcharging.GetCompositeScheduleRequest) (*smartcharging.GetCompositeScheduleConfirmation, error) {
-	return smartcharging.NewGetCompositeScheduleConfirmation(smartcharging.GetCompositeScheduleStatusAccepted), nil
+	res := smartcharging.NewGetCompositeScheduleConfirmation(smartcharging.GetCompositeScheduleStatusAccepted)
+
+	if limit := handler.compositeLimit.Load(); limit > 0 {
+		res.ChargingSchedule = types.NewChargingSchedule(types.ChargingRateUnitAmperes, types.NewChargingSchedulePeriod(0, float64(limit)))
+	}
+
+	return res, nil
+}
+
+// reservation
+
+func (handler *ChargePointHandler) OnReserveNow(request *reservation.ReserveNowRequest) (*reservation.ReserveNowConfirmation, error) {
+	handler.reservationId.Store(int32(request.ReservationId))
+	return reservation.NewReserveNowConfirmation(reservation.ReservationStatusAccepted), nil
+}
+
+func (handler *ChargePointHandler) OnCancelReservation(request *reservation.CancelReservationRequest) (*reservation.CancelReservationConfirmation, error) {
+	if !handler.reservationId.CompareAndSwap(int32(request.ReservationId), 0) {
+		return reservation.NewCancelReservationConfirmation(reservation.CancelReservationStatusRejected), nil
+	}
+	return reservation.NewCancelReservationConfirmation(reservation.CancelReservationStatusAccepted), nil
 }
@@ -0,0 +1,242 @@
+package charger
+
+import (
+	"time"
+
+	"github.com/evcc-io/evcc/api"
+	"github.com/evcc-io/evcc/charger/ocpp"
+	"github.com/lorenzodonini/ocpp-go/ocpp1.6/core"
+	ocpp2 "github.com/lorenzodonini/ocpp-go/ocpp2.0.1"
+	"github.com/lorenzodonini/ocpp-go/ocpp2.0.1/authorization"
+	"github.com/lorenzodonini/ocpp-go/ocpp2.0.1/availability"
+	"github.com/lorenzodonini/ocpp-go/ocpp2.0.1/meter"
+	"github.com/lorenzodonini/ocpp-go/ocpp2.0.1/provisioning"
+	"github.com/lorenzodonini/ocpp-go/ocpp2.0.1/remotecontrol"
+	"github.com/lorenzodonini/ocpp-go/ocpp2.0.1/security"
+	"github.com/lorenzodonini/ocpp-go/ocpp2.0.1/smartcharging"
+	"github.com/lorenzodonini/ocpp-go/ocpp2.0.1/transactions"
+	types201 "github.com/lorenzodonini/ocpp-go/ocpp2.0.1/types"
+	"github.com/lorenzodonini/ocpp-go/ocppj"
+	"github.com/lorenzodonini/ocpp-go/ws"
+)
+
+// chargingStationHandler is an OCPP 2.0.1 charging station with a running transaction on evse 1
+type chargingStationHandler struct {
+	triggerC chan remotecontrol.MessageTrigger
+	profileC chan *types201.ChargingProfile
+}
+
+func (handler *chargingStationHandler) OnChangeAvailability(request *availability.ChangeAvailabilityRequest) (*availability.ChangeAvailabilityResponse, error) {
+	return availability.NewChangeAvailabilityResponse(availability.ChangeAvailabilityStatusAccepted), nil
+}
+
+func (handler *chargingStationHandler) OnGetBaseReport(request *provisioning.GetBaseReportRequest) (*provisioning.GetBaseReportResponse, error) {
+	return provisioning.NewGetBaseReportResponse(types201.GenericDeviceModelStatusNotSupported), nil
+}
+
+func (handler *chargingStationHandler) OnGetReport(request *provisioning.GetReportRequest) (*provisioning.GetReportResponse, error) {
+	return provisioning.NewGetReportResponse(types201.GenericDeviceModelStatusNotSupported), nil
+}
+
+func (handler *chargingStationHandler) OnGetVariables(request *provisioning.GetVariablesRequest) (*provisioning.GetVariablesResponse, error) {
+	values := map[string]string{
+		"TxUpdatedMeasurands": "Power.Active.Import,Energy.Active.Import.Register",
+		"ProfileStackLevel":   "1",
+		"RateUnit":            "A",
+	}
+
+	var res []provisioning.GetVariableResult
+	for _, data := range request.GetVariableData {
+		r := provisioning.GetVariableResult{
+			AttributeStatus: provisioning.GetVariableStatusUnknownVariable,
+			Component:       data.Component,
+			Variable:        data.Variable,
+		}
+
+		if value, ok := values[data.Variable.Name]; ok {
+			r.AttributeStatus = provisioning.GetVariableStatusAccepted
+			r.AttributeValue = value
+		}
+
+		res = append(res, r)
+	}
+
+	return provisioning.NewGetVariablesResponse(res), nil
+}
+
+func (handler *chargingStationHandler) OnReset(request *provisioning.ResetRequest) (*provisioning.ResetResponse, error) {
+	return provisioning.NewResetResponse(provisioning.ResetStatusRejected), nil
+}
+
+func (handler *chargingStationHandler) OnSetNetworkProfile(request *provisioning.SetNetworkProfileRequest) (*provisioning.SetNetworkProfileResponse, error) {
+	return provisioning.NewSetNetworkProfileResponse(provisioning.SetNetworkProfileStatusRejected), nil
+}
+
+func (handler *chargingStationHandler) OnSetVariables(request *provisioning.SetVariablesRequest) (*provisioning.SetVariablesResponse, error) {
+	var res []provisioning.SetVariableResult
+	for _, data := range request.SetVariableData {
+		res = append(res, provisioning.SetVariableResult{
+			AttributeStatus: provisioning.SetVariableStatusAccepted,
+			Component:       data.Component,
+			Variable:        data.Variable,
+		})
+	}
+
+	return provisioning.NewSetVariablesResponse(res), nil
+}
+
+func (handler *chargingStationHandler) OnRequestStartTransaction(request *remotecontrol.RequestStartTransactionRequest) (*remotecontrol.RequestStartTransactionResponse, error) {
+	return remotecontrol.NewRequestStartTransactionResponse(remotecontrol.RequestStartStopStatusAccepted), nil
+}
+
+func (handler *chargingStationHandler) OnRequestStopTransaction(request *remotecontrol.RequestStopTransactionRequest) (*remotecontrol.RequestStopTransactionResponse, error) {
+	return remotecontrol.NewRequestStopTransactionResponse(remotecontrol.RequestStartStopStatusAccepted), nil
+}
+
+func (handler *chargingStationHandler) OnTriggerMessage(request *remotecontrol.TriggerMessageRequest) (*remotecontrol.TriggerMessageResponse, error) {
+	defer func() { handler.triggerC <- request.RequestedMessage }()
+	return remotecontrol.NewTriggerMessageResponse(remotecontrol.TriggerMessageStatusAccepted), nil
+}
+
+func (handler *chargingStationHandler) OnUnlockConnector(request *remotecontrol.UnlockConnectorRequest) (*remotecontrol.UnlockConnectorResponse, error) {
+	return remotecontrol.NewUnlockConnectorResponse(remotecontrol.UnlockStatusUnlockFailed), nil
+}
+
+func (handler *chargingStationHandler) OnClearChargingProfile(request *smartcharging.ClearChargingProfileRequest) (*smartcharging.ClearChargingProfileResponse, error) {
+	return smartcharging.NewClearChargingProfileResponse(smartcharging.ClearChargingProfileStatusAccepted), nil
+}
+
+func (handler *chargingStationHandler) OnGetChargingProfiles(request *smartcharging.GetChargingProfilesRequest) (*smartcharging.GetChargingProfilesResponse, error) {
+	return smartcharging.NewGetChargingProfilesResponse(smartcharging.GetChargingProfileStatusNoProfiles), nil
+}
+
+func (handler *chargingStationHandler) OnGetCompositeSchedule(request *smartcharging.GetCompositeScheduleRequest) (*smartcharging.GetCompositeScheduleResponse, error) {
+	return smartcharging.NewGetCompositeScheduleResponse(smartcharging.GetCompositeScheduleStatusRejected, request.EvseID), nil
+}
+
+func (handler *chargingStationHandler) OnSetChargingProfile(request *smartcharging.SetChargingProfileRequest) (*smartcharging.SetChargingProfileResponse, error) {
+	defer func() { handler.profileC <- request.ChargingProfile }()
+	return smartcharging.NewSetChargingProfileResponse(smartcharging.ChargingProfileStatusAccepted), nil
+}
+
+func (suite *ocppTestSuite) startChargingStation(id string) (ocpp2.ChargingStation, *chargingStationHandler) {
+	handler := &chargingStationHandler{
+		triggerC: make(chan remotecontrol.MessageTrigger, 1),
+		profileC: make(chan *types201.ChargingProfile, 1),
+	}
+
+	client := ws.NewClient()
+	client.SetRequestedSubProtocol(types201.V201Subprotocol)
+	dispatcher := ocppj.NewDefaultClientDispatcher(ocppj.NewFIFOClientQueue(0))
+	endpoint := ocppj.NewClient(id, client, dispatcher, nil,
+		provisioning.Profile, availability.Profile, transactions.Profile, meter.Profile,
+		authorization.Profile, remotecontrol.Profile, smartcharging.Profile, security.Profile)
+
+	cs := ocpp2.NewChargingStation(id, endpoint, client)
+	cs.SetProvisioningHandler(handler)
+	cs.SetAvailabilityHandler(handler)
+	cs.SetRemoteControlHandler(handler)
+	cs.SetSmartChargingHandler(handler)
+
+	// let csms handle the trigger messages
+	go func() {
+		for msg := range handler.triggerC {
+			suite.handleTrigger201(cs, msg)
+		}
+	}()
+
+	return cs, handler
+}
+
+func (suite *ocppTestSuite) handleTrigger201(cs ocpp2.ChargingStation, msg remotecontrol.MessageTrigger) {
+	switch msg {
+	case remotecontrol.MessageTriggerBootNotification:
+		if _, err := cs.BootNotification(provisioning.BootReasonTriggered, "model", "vendor"); err != nil {
+			suite.T().Log("BootNotification:", err)
+		}
+
+	case remotecontrol.MessageTriggerStatusNotification:
+		if _, err := cs.StatusNotification(types201.Now(), availability.ConnectorStatusOccupied, 1, 1); err != nil {
+			suite.T().Log("StatusNotification:", err)
+		}
+
+	case remotecontrol.MessageTriggerTransactionEvent:
+		if _, err := cs.TransactionEvent(transactions.TransactionEventUpdated, types201.Now(), transactions.TriggerReasonTrigger, 1,
+			transactions.Transaction{TransactionID: "txn", ChargingState: transactions.ChargingStateCharging},
+			func(request *transactions.TransactionEventRequest) {
+				request.Evse = &types201.EVSE{ID: 1}
+			},
+		); err != nil {
+			suite.T().Log("TransactionEvent:", err)
+		}
+
+	case remotecontrol.MessageTriggerMeterValues:
+		if _, err := cs.MeterValues(1, []types201.MeterValue{
+			{
+				Timestamp: *types201.Now(),
+				SampledValue: []types201.SampledValue{
+					{Measurand: types201.MeasurandPowerActiveImport, Value: 1000},
+					{Measurand: types201.MeasurandEnergyActiveImportRegister, Value: 1.2, UnitOfMeasure: &types201.UnitOfMeasure{Unit: "kWh"}},
+				},
+			},
+		}); err != nil {
+			suite.T().Log("MeterValues:", err)
+		}
+	}
+}
+
+func (suite *ocppTestSuite) TestConnectV201() {
+	cs, handler := suite.startChargingStation("test-v201")
+	suite.Require().NoError(cs.Start(ocppTestUrl))
+	suite.Require().True(cs.IsConnected())
+
+	c, err := NewOCPP(suite.T().Context(), "test-v201", 1, "", "", 0, false, false, false, true, ocppTestConnectTimeout)
+	suite.Require().NoError(err)
+
+	// charging state of the running transaction
+	suite.Eventually(func() bool {
+		status, err := c.Status()
+		return err == nil && status == api.StatusC
+	}, ocpp.Timeout, 10*time.Millisecond)
+
+	// meter values recover the running transaction
+	suite.Require().NoError(c.Connector().TriggerMessageRequest(core.MeterValuesFeatureName))
+	suite.Eventually(func() bool {
+		power, err := c.conn.CurrentPower()
+		return err == nil && power == 1000
+	}, ocpp.Timeout, 10*time.Millisecond)
+
+	energy, err := c.conn.TotalEnergy()
+	suite.Require().NoError(err)
+	suite.Equal(1.2, energy)
+
+	txnId, err := c.Connector().TransactionID()
+	suite.Require().NoError(err)
+	suite.NotZero(txnId)
+
+	// charging profile
+	suite.Require().NoError(c.MaxCurrent(16))
+	profile := <-handler.profileC
+	suite.Require().Len(profile.ChargingSchedule, 1)
+	suite.Equal(16.0, profile.ChargingSchedule[0].ChargingSchedulePeriod[0].Limit)
+
+	// configuration keys
+	conf, err := c.cp.GetConfigurationRequest(ocpp.KeyChargeProfileMaxStackLevel, "Unknown")
+	suite.Require().NoError(err)
+	suite.Equal([]string{"Unknown"}, conf.UnknownKey)
+	suite.Require().Len(conf.ConfigurationKey, 1)
+	suite.Equal("1", *conf.ConfigurationKey[0].Value)
+
+	// transaction ended, ev still connected
+	_, err = cs.TransactionEvent(transactions.TransactionEventEnded, types201.Now(), transactions.TriggerReasonEVCommunicationLost, 2,
+		transactions.Transaction{TransactionID: "txn", ChargingState: transactions.ChargingStateEVConnected})
+	suite.Require().NoError(err)
+
+	status, err := c.Status()
+	suite.Require().NoError(err)
+	suite.Equal(api.StatusB, status)
+
+	txnId, err = c.Connector().TransactionID()
+	suite.Require().NoError(err)
+	suite.Zero(txnId)
+}
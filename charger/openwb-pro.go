@@ -10,6 +10,7 @@ import (
 	"github.com/evcc-io/evcc/charger/openwb/pro"
 	"github.com/evcc-io/evcc/util"
 	"github.com/evcc-io/evcc/util/request"
+	"github.com/evcc-io/evcc/util/supervisor"
 )
 
 func init() {
@@ -76,23 +77,16 @@ func NewOpenWBPro(ctx context.Context, uri string, cache time.Duration) (*OpenWB
 		return res, err
 	}, cache)
 
-	go wb.heartbeat(ctx, log)
+	wb.heartbeat(ctx)
 
 	return wb, nil
 }
 
-func (wb *OpenWBPro) heartbeat(ctx context.Context, log *util.Logger) {
-	for tick := time.Tick(30 * time.Second); ; {
-		select {
-		case <-tick:
-		case <-ctx.Done():
-			return
-		}
-
-		if _, err := wb.statusG.Get(); err != nil {
-			log.ERROR.Printf("heartbeat: %v", err)
-		}
-	}
+func (wb *OpenWBPro) heartbeat(ctx context.Context) {
+	supervisor.Every(ctx, "heartbeat", 30*time.Second, func(context.Context) error {
+		_, err := wb.statusG.Get()
+		return err
+	})
 }
 
 func (wb *OpenWBPro) set(payload string) error {
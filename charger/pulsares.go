@@ -26,6 +26,7 @@ import (
 	"github.com/evcc-io/evcc/api"
 	"github.com/evcc-io/evcc/util"
 	"github.com/evcc-io/evcc/util/modbus"
+	"github.com/evcc-io/evcc/util/supervisor"
 )
 
 // Pulsares charger implementation
@@ -121,24 +122,17 @@ func NewPulsares(ctx context.Context, uri, device, comset string, baudrate int,
 	}
 
 	if t > 0 {
-		go wb.heartbeat(ctx, t/2)
+		wb.heartbeat(ctx, t/2)
 	}
 
 	return wb, nil
 }
 
 func (wb *Pulsares) heartbeat(ctx context.Context, timeout time.Duration) {
-	for tick := time.Tick(timeout); ; {
-		select {
-		case <-tick:
-		case <-ctx.Done():
-			return
-		}
-
-		if _, err := wb.conn.ReadHoldingRegisters(pulsaresRegBackup, 1); err != nil {
-			wb.log.ERROR.Println("heartbeat:", err)
-		}
-	}
+	supervisor.Every(ctx, "heartbeat", timeout, func(ctx context.Context) error {
+		_, err := wb.conn.WithContext(ctx).ReadHoldingRegisters(pulsaresRegBackup, 1)
+		return err
+	})
 }
 
 func (wb *Pulsares) has1p3p() bool {
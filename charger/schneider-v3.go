@@ -26,6 +26,7 @@ import (
 	"github.com/evcc-io/evcc/util"
 	"github.com/evcc-io/evcc/util/modbus"
 	"github.com/evcc-io/evcc/util/sponsor"
+	"github.com/evcc-io/evcc/util/supervisor"
 	"github.com/volkszaehler/mbmd/encoding"
 )
 
@@ -109,24 +110,17 @@ func NewSchneiderV3(ctx context.Context, uri string, id uint8) (api.Charger, err
 		return nil, fmt.Errorf("heartbeat timeout: %w", err)
 	}
 	if u := encoding.Uint16(b); u != 2 {
-		go wb.heartbeat(ctx, 2*time.Second)
+		wb.heartbeat(ctx, 2*time.Second)
 	}
 
 	return wb, nil
 }
 
 func (wb *Schneider) heartbeat(ctx context.Context, timeout time.Duration) {
-	for tick := time.Tick(timeout); ; {
-		select {
-		case <-tick:
-		case <-ctx.Done():
-			return
-		}
-
-		if _, err := wb.conn.WriteSingleRegister(schneiderRegLifebit, 1); err != nil {
-			wb.log.ERROR.Println("heartbeat:", err)
-		}
-	}
+	supervisor.Every(ctx, "heartbeat", timeout, func(ctx context.Context) error {
+		_, err := wb.conn.WithContext(ctx).WriteSingleRegister(schneiderRegLifebit, 1)
+		return err
+	})
 }
 
 // Status implements the api.Charger interface
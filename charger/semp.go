@@ -26,6 +26,7 @@ import (
 	"github.com/evcc-io/evcc/charger/semp"
 	"github.com/evcc-io/evcc/util"
 	"github.com/evcc-io/evcc/util/sponsor"
+	"github.com/evcc-io/evcc/util/supervisor"
 )
 
 // SEMP charger implementation
@@ -149,29 +150,22 @@ func NewSEMP(ctx context.Context, uri, deviceID string, cache time.Duration) (ap
 		return nil, err
 	}
 
-	go wb.heartbeat(ctx)
+	wb.heartbeat(ctx)
 
 	return decorateSEMP(wb, phases1p3p, getPhases, chargedEnergy), nil
 }
 
 // heartbeat ensures that device control updates are sent at least once per minute
 func (wb *SEMP) heartbeat(ctx context.Context) {
-	ticker := time.NewTicker(time.Second)
-	defer ticker.Stop()
-
-	for {
-		select {
-		case <-ticker.C:
-			// Check if we need to send an update
-			if time.Since(wb.conn.Updated()) >= time.Minute {
-				if err := wb.conn.SendDeviceControl(wb.deviceID, wb.calcPower(wb.enabled, wb.current, wb.phases)); err != nil {
-					wb.log.ERROR.Printf("heartbeat: failed to send update: %v", err)
-				}
+	supervisor.Every(ctx, "heartbeat", time.Second, func(ctx context.Context) error {
+		// Check if we need to send an update
+		if time.Since(wb.conn.Updated()) >= time.Minute {
+			if err := wb.conn.SendDeviceControlContext(ctx, wb.deviceID, wb.calcPower(wb.enabled, wb.current, wb.phases)); err != nil {
+				return fmt.Errorf("failed to send update: %w", err)
 			}
-		case <-ctx.Done():
-			return
 		}
-	}
+		return nil
+	})
 }
 
 // getDeviceStatus retrieves device status from cached document
@@ -1,6 +1,7 @@
 package semp
 
 import (
+	"context"
 	"net/http"
 	"strings"
 	"sync"
@@ -56,6 +57,11 @@ func (c *Connection) GetParametersXML() ([]Parameter, error) {
 // SendDeviceControl sends a control message to the SEMP device
 // power is optional - if nil, RecommendedPowerConsumption will be omitted
 func (c *Connection) SendDeviceControl(deviceId string, power int) error {
+	return c.SendDeviceControlContext(context.Background(), deviceId, power)
+}
+
+// SendDeviceControlContext sends a device control message, the request is cancelled with the context
+func (c *Connection) SendDeviceControlContext(ctx context.Context, deviceId string, power int) error {
 	control := DeviceControl{
 		DeviceID:  deviceId,
 		On:        power > 0,
@@ -76,7 +82,7 @@ func (c *Connection) SendDeviceControl(deviceId string, power int) error {
 		return err
 	}
 
-	_, err = c.DoBody(req)
+	_, err = c.DoBody(req.WithContext(ctx))
 	if err == nil {
 		c.mu.Lock()
 		c.updated = time.Now()
@@ -0,0 +1,33 @@
+package charger
+
+import (
+	"cmp"
+
+	"github.com/evcc-io/evcc/api"
+	"github.com/evcc-io/evcc/util"
+	"github.com/evcc-io/evcc/util/sim"
+)
+
+func init() {
+	registry.Add("sim", NewSimFromConfig)
+}
+
+// NewSimFromConfig creates a simulated charger for validating configurations without hardware
+func NewSimFromConfig(other map[string]any) (api.Charger, error) {
+	cc := struct {
+		Env     string // simulated environment shared with meters and vehicles
+		Vehicle string // name of plugged simulated vehicle
+		Phases  int
+	}{
+		Phases: 3,
+	}
+
+	if err := util.DecodeOther(other, &cc); err != nil {
+		return nil, err
+	}
+
+	c := sim.Registered(cmp.Or(cc.Env, "default")).NewCharger(cc.Phases)
+	c.Plug(cc.Vehicle)
+
+	return c, nil
+}
@@ -28,6 +28,7 @@ import (
 	"github.com/evcc-io/evcc/util"
 	"github.com/evcc-io/evcc/util/modbus"
 	"github.com/evcc-io/evcc/util/sponsor"
+	"github.com/evcc-io/evcc/util/supervisor"
 	"github.com/hashicorp/go-version"
 )
 
@@ -148,23 +149,16 @@ func NewVestel(ctx context.Context, uri string, id uint8) (api.Charger, error) {
 	if timeout < time.Second {
 		timeout = time.Second
 	}
-	go wb.heartbeat(ctx, timeout)
+	wb.heartbeat(ctx, timeout)
 
 	return decorateVestel(wb, phasesS, phasesG, identify), nil
 }
 
 func (wb *Vestel) heartbeat(ctx context.Context, timeout time.Duration) {
-	for tick := time.Tick(timeout); ; {
-		select {
-		case <-tick:
-		case <-ctx.Done():
-			return
-		}
-
-		if _, err := wb.conn.WriteSingleRegister(vestelRegAlive, 1); err != nil {
-			wb.log.ERROR.Println("heartbeat:", err)
-		}
-	}
+	supervisor.Every(ctx, "heartbeat", timeout, func(ctx context.Context) error {
+		_, err := wb.conn.WithContext(ctx).WriteSingleRegister(vestelRegAlive, 1)
+		return err
+	})
 }
 
 // Status implements the api.Charger interface
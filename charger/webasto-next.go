@@ -27,6 +27,7 @@ import (
 	"github.com/evcc-io/evcc/util"
 	"github.com/evcc-io/evcc/util/modbus"
 	"github.com/evcc-io/evcc/util/sponsor"
+	"github.com/evcc-io/evcc/util/supervisor"
 )
 
 // WebastoNext charger implementation
@@ -99,24 +100,17 @@ func NewWebastoNext(ctx context.Context, uri string, id uint8) (api.Charger, err
 		return nil, fmt.Errorf("failsafe timeout: %w", err)
 	}
 	if u := binary.BigEndian.Uint16(b); u > 0 {
-		go wb.heartbeat(ctx, time.Duration(u)*time.Second/2)
+		wb.heartbeat(ctx, time.Duration(u)*time.Second/2)
 	}
 
 	return wb, err
 }
 
 func (wb *WebastoNext) heartbeat(ctx context.Context, timeout time.Duration) {
-	for tick := time.Tick(timeout); ; {
-		select {
-		case <-tick:
-		case <-ctx.Done():
-			return
-		}
-
-		if _, err := wb.conn.WriteSingleRegister(tqRegLifeBit, 1); err != nil {
-			wb.log.ERROR.Println("heartbeat:", err)
-		}
-	}
+	supervisor.Every(ctx, "heartbeat", timeout, func(ctx context.Context) error {
+		_, err := wb.conn.WithContext(ctx).WriteSingleRegister(tqRegLifeBit, 1)
+		return err
+	})
 }
 
 // Status implements the api.Charger interface
@@ -27,6 +27,7 @@ import (
 	"github.com/evcc-io/evcc/util"
 	"github.com/evcc-io/evcc/util/modbus"
 	"github.com/evcc-io/evcc/util/sponsor"
+	"github.com/evcc-io/evcc/util/supervisor"
 	"github.com/volkszaehler/mbmd/encoding"
 )
 
@@ -102,7 +103,7 @@ func NewWeidmüller(ctx context.Context, uri string, id uint8) (api.Charger, err
 	}
 
 	// failsafe
-	go wb.heartbeat(ctx, wmHeartbeatInterval)
+	wb.heartbeat(ctx, wmHeartbeatInterval)
 
 	// check presence of energy meter
 	if b, err := wb.conn.ReadHoldingRegisters(wmRegTotalEnergy, 2); err == nil && binary.BigEndian.Uint32(b) > 0 {
@@ -116,17 +117,10 @@ func (wb *Weidmüller) heartbeat(ctx context.Context, timeout time.Duration) {
 	b := make([]byte, 4)
 	binary.BigEndian.PutUint32(b, wmTimeout)
 
-	for tick := time.Tick(timeout); ; {
-		select {
-		case <-tick:
-		case <-ctx.Done():
-			return
-		}
-
-		if _, err := wb.conn.WriteMultipleRegisters(wmRegTimeout, 2, b); err != nil {
-			wb.log.ERROR.Println("heartbeat:", err)
-		}
-	}
+	supervisor.Every(ctx, "heartbeat", timeout, func(ctx context.Context) error {
+		_, err := wb.conn.WithContext(ctx).WriteMultipleRegisters(wmRegTimeout, 2, b)
+		return err
+	})
 }
 
 func (wb *Weidmüller) setCurrent(current uint16) error {
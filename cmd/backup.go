@@ -0,0 +1,14 @@
+package cmd
+
+import (
+	"github.com/spf13/cobra"
+)
+
+var backupCmd = &cobra.Command{
+	Use:   "backup",
+	Short: "Backup and restore configuration and database",
+}
+
+func init() {
+	rootCmd.AddCommand(backupCmd)
+}
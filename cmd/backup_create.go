@@ -0,0 +1,70 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"time"
+
+	"github.com/evcc-io/evcc/server/backup"
+	"github.com/evcc-io/evcc/server/db"
+	"github.com/spf13/cobra"
+)
+
+var backupCreateCmd = &cobra.Command{
+	Use:   "create [file]",
+	Short: "Create backup archive of configuration and database",
+	Args:  cobra.MaximumNArgs(1),
+	Run:   runBackupCreate,
+}
+
+func init() {
+	backupCmd.AddCommand(backupCreateCmd)
+}
+
+func runBackupCreate(cmd *cobra.Command, args []string) {
+	// load config
+	if err := loadConfigFile(&conf, !cmd.Flag(flagIgnoreDatabase).Changed); err != nil {
+		log.FATAL.Fatal(err)
+	}
+
+	// setup persistence
+	if err := configureDatabase(conf.Database); err != nil {
+		log.FATAL.Fatal(err)
+	}
+
+	if db.FilePath == "" {
+		log.FATAL.Fatal("backup requires sqlite database")
+	}
+
+	if err := db.Flush(); err != nil {
+		log.FATAL.Fatal(err)
+	}
+
+	file := "evcc-backup-" + time.Now().Format("2006-01-02--15-04") + ".tar.gz"
+	if len(args) > 0 {
+		file = args[0]
+	}
+
+	snapshot := db.FilePath + ".snapshot"
+	defer os.Remove(snapshot)
+
+	if err := backup.Snapshot(db.Instance, snapshot); err != nil {
+		log.FATAL.Fatal(err)
+	}
+
+	f, err := os.Create(file)
+	if err != nil {
+		log.FATAL.Fatal(err)
+	}
+
+	if err := backup.Create(f, snapshot, viper.ConfigFileUsed()); err != nil {
+		f.Close()
+		log.FATAL.Fatal(err)
+	}
+
+	if err := f.Close(); err != nil {
+		log.FATAL.Fatal(err)
+	}
+
+	fmt.Println("backup created:", file)
+}
@@ -0,0 +1,71 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/AlecAivazis/survey/v2"
+	"github.com/evcc-io/evcc/server/backup"
+	"github.com/mitchellh/go-homedir"
+	"github.com/spf13/cobra"
+)
+
+var backupRestoreCmd = &cobra.Command{
+	Use:   "restore <file>",
+	Short: "Restore configuration and database from backup archive. evcc must not be running.",
+	Args:  cobra.ExactArgs(1),
+	Run:   runBackupRestore,
+}
+
+func init() {
+	backupCmd.AddCommand(backupRestoreCmd)
+}
+
+func runBackupRestore(cmd *cobra.Command, args []string) {
+	// load config
+	if err := loadConfigFile(&conf, false); err != nil {
+		log.FATAL.Fatal(err)
+	}
+
+	if !strings.EqualFold(conf.Database.Type, "sqlite") {
+		log.FATAL.Fatal("restore requires sqlite database")
+	}
+
+	dsn := conf.Database.Dsn
+	if dsn == "" {
+		dsn = userDB
+	}
+
+	dbFile, err := homedir.Expand(dsn)
+	if err != nil {
+		log.FATAL.Fatal(err)
+	}
+
+	prompt := &survey.Confirm{
+		Message: fmt.Sprintf("Overwrite %s?", dbFile),
+		Help:    "Existing files are kept with .bak extension",
+	}
+
+	var confirm bool
+	if err := survey.AskOne(prompt, &confirm); err != nil {
+		log.FATAL.Fatal(err)
+	}
+
+	if !confirm {
+		return
+	}
+
+	f, err := os.Open(args[0])
+	if err != nil {
+		log.FATAL.Fatal(err)
+	}
+	defer f.Close()
+
+	m, err := backup.Restore(f, dbFile, viper.ConfigFileUsed())
+	if err != nil {
+		log.FATAL.Fatal(err)
+	}
+
+	fmt.Printf("restored backup from %s (version %s)\n", m.Created.Local().Format("2006-01-02 15:04"), m.Version)
+}
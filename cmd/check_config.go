@@ -1,33 +1,315 @@
 package cmd
 
 import (
-	_ "embed"
+	"cmp"
+	"context"
+	"errors"
 	"fmt"
+	"net"
 	"os"
+	"slices"
+	"strings"
+	"time"
 
+	"github.com/evcc-io/evcc/api"
+	"github.com/evcc-io/evcc/api/globalconfig"
+	"github.com/evcc-io/evcc/charger"
+	"github.com/evcc-io/evcc/meter"
+	"github.com/evcc-io/evcc/server/db"
+	"github.com/evcc-io/evcc/util"
+	"github.com/evcc-io/evcc/util/config"
+	"github.com/evcc-io/evcc/util/templates"
+	"github.com/evcc-io/evcc/vehicle"
+	"github.com/go-playground/validator/v10"
 	"github.com/spf13/cobra"
 )
 
+const (
+	flagDryRun            = "dry-run"
+	flagDryRunDescription = "Create all devices to verify reachability, register maps and OCPP connectivity"
+)
+
 var checkconfig = &cobra.Command{
 	Use:   "checkconfig",
 	Short: "Check config file for errors",
-	Long: `Check the (specified or default) config file for errors. Note that
-	       checkconfig only checks the config file for parsing errors and does
-		   not check that individual device configurations are valid.`,
+	Long: `Check the (specified or default) config file for errors. Verifies parsing,
+	       device names and references between site, loadpoints and devices.
+	       With --dry-run, all devices are created and queried once without starting evcc.
+	       All problems are reported at once including hints for fixing them.`,
 	Run: runConfigCheck,
 }
 
 func init() {
 	rootCmd.AddCommand(checkconfig)
+	checkconfig.Flags().Bool(flagDryRun, false, flagDryRunDescription)
+	checkconfig.Flags().Duration(flagTimeout, time.Minute, "Device timeout for dry-run")
 }
 
-func runConfigCheck(cmd *cobra.Command, args []string) {
-	err := loadConfigFile(&conf, !cmd.Flag(flagIgnoreDatabase).Changed)
+// configProblem is a config validation finding
+type configProblem struct {
+	Class Class
+	Name  string // optional device name
+	Err   error
+}
+
+func (p configProblem) String() string {
+	res := p.Class.String()
+	if p.Name != "" {
+		res += " [" + p.Name + "]"
+	}
+	res += ": " + p.Err.Error()
+
+	if hint := configHint(p.Class, p.Err); hint != "" {
+		res += "\n  hint: " + hint
+	}
+
+	return res
+}
+
+// errMissingReference is returned for references to undefined devices
+var errMissingReference = errors.New("referenced but not defined")
+
+// configHint returns a fix hint for known errors
+func configHint(class Class, err error) string {
+	var (
+		cfgErr *util.ConfigError
+		valErr validator.ValidationErrors
+		dnsErr *net.DNSError
+		opErr  *net.OpError
+	)
+
+	msg := err.Error()
+
+	switch {
+	case errors.Is(err, errMissingReference):
+		return "add a device with this name or fix the reference"
+	case strings.Contains(msg, "missing name"):
+		return "each device needs a unique name to be referenced by site or loadpoints"
+	case strings.Contains(msg, "duplicate name"):
+		return "device names must be unique across config file and ui configured devices"
+	case strings.Contains(msg, "has invalid keys"):
+		return "check spelling and indentation of the listed keys, see https://docs.evcc.io for the device's parameters"
+	case errors.As(err, &valErr):
+		return "add the missing required parameters"
+	case errors.As(err, &cfgErr):
+		return "check parameter types, e.g. numbers must not be quoted and lists need a leading dash"
+	case strings.Contains(msg, "invalid") && strings.Contains(msg, "type:"):
+		return "check the device type or template name, see `evcc configure` for supported devices"
+	case errors.As(err, &dnsErr):
+		return "host cannot be resolved, check the host name or use an ip address"
+	case errors.As(err, &opErr) && strings.Contains(msg, "connection refused"):
+		return "device is reachable but the port is closed, check port and that the device's local api or modbus tcp is enabled"
+	case strings.Contains(msg, "illegal data address"):
+		return "register not available, check device model, template and modbus id"
+	case strings.Contains(msg, "modbus") && strings.Contains(msg, "exception"):
+		return "device rejected the modbus request, check modbus id and register configuration"
+	case class == ClassCharger && errors.Is(err, api.ErrTimeout):
+		return "for ocpp chargers configure the charge point's backend url as ws://<evcc>:8887/<station id>"
+	case errors.Is(err, api.ErrTimeout), errors.Is(err, os.ErrDeadlineExceeded), errors.Is(err, context.DeadlineExceeded):
+		return "device not responding, check ip address, network and that the device is powered"
+	}
+
+	return ""
+}
+
+// checkNames verifies device names are given, valid and unique and returns all device names
+func checkNames(class Class, static []config.Named, configurable []config.Config) (map[string]bool, []configProblem) {
+	var res []configProblem
+	names := make(map[string]bool)
+
+	for i, cc := range static {
+		if cc.Name == "" {
+			res = append(res, configProblem{class, "", fmt.Errorf("device %d: missing name", i+1)})
+			continue
+		}
+
+		if names[cc.Name] {
+			res = append(res, configProblem{class, cc.Name, errors.New("duplicate name")})
+		}
 
+		if err := nameValid(cc.Name); err != nil {
+			res = append(res, configProblem{class, cc.Name, err})
+		}
+
+		names[cc.Name] = true
+	}
+
+	for _, conf := range configurable {
+		name := conf.Named().Name
+		if names[name] {
+			res = append(res, configProblem{class, name, errors.New("duplicate name")})
+		}
+		names[name] = true
+	}
+
+	return names, res
+}
+
+// checkReferences verifies all devices referenced by site and loadpoints exist
+func checkReferences(class Class, names map[string]bool, refs []string) []configProblem {
+	var res []configProblem
+
+	for _, ref := range slices.Compact(slices.Sorted(slices.Values(refs))) {
+		if ref != "" && !names[ref] {
+			res = append(res, configProblem{class, ref, errMissingReference})
+		}
+	}
+
+	return res
+}
+
+// checkDevice creates the device and queries it once
+func checkDevice(class Class, cc config.Named) error {
+	ctx := util.WithLogger(context.TODO(), util.NewLogger(cc.Name))
+
+	props, err := customDevice(cc.Other)
 	if err != nil {
-		log.FATAL.Println("config invalid:", err)
+		return err
+	}
+
+	switch class {
+	case ClassMeter:
+		dev, err := meter.NewFromConfig(ctx, cc.Type, props)
+		if err == nil {
+			_, err = dev.CurrentPower()
+		}
+		return err
+
+	case ClassCharger:
+		dev, err := charger.NewFromConfig(ctx, cc.Type, props)
+		if err == nil {
+			_, err = dev.Status()
+		}
+		return err
+
+	case ClassVehicle:
+		// vehicles are not queried to avoid waking them up
+		_, err := vehicle.NewFromConfig(ctx, cc.Type, props)
+		return err
+	}
+
+	return nil
+}
+
+// checkDevices creates all devices concurrently, each limited by timeout
+func checkDevices(devices map[Class][]config.Named, timeout time.Duration) []configProblem {
+	resC := make(chan configProblem)
+
+	var count int
+	for class, named := range devices {
+		for _, cc := range named {
+			count++
+
+			go func() {
+				errC := make(chan error, 1)
+				go func() { errC <- checkDevice(class, cc) }()
+
+				var err error
+				select {
+				case err = <-errC:
+				case <-time.After(timeout):
+					err = api.ErrTimeout
+				}
+
+				resC <- configProblem{class, cc.Name, err}
+			}()
+		}
+	}
+
+	var res []configProblem
+	for range count {
+		if p := <-resC; p.Err != nil {
+			res = append(res, p)
+		}
+	}
+
+	return res
+}
+
+// validateConfig returns all problems found in the configuration
+func validateConfig(conf globalconfig.All, dryRun bool, timeout time.Duration) []configProblem {
+	var res []configProblem
+
+	devices := make(map[Class][]config.Named)
+
+	for _, c := range []struct {
+		class  Class
+		tmpl   templates.Class
+		static []config.Named
+		refs   *[]string
+	}{
+		{ClassMeter, templates.Meter, conf.Meters, &references.meter},
+		{ClassCharger, templates.Charger, conf.Chargers, &references.charger},
+		{ClassVehicle, templates.Vehicle, conf.Vehicles, &references.vehicle},
+		{ClassCircuit, templates.Circuit, conf.Circuits, &references.circuit},
+	} {
+		var configurable []config.Config
+		if db.Instance != nil {
+			var err error
+			if configurable, err = config.ConfigurationsByClass(c.tmpl); err != nil {
+				res = append(res, configProblem{ClassDatabase, "", err})
+			}
+		}
+
+		names, problems := checkNames(c.class, c.static, configurable)
+		res = append(res, problems...)
+		res = append(res, checkReferences(c.class, names, *c.refs)...)
+
+		devices[c.class] = append(devices[c.class], c.static...)
+		for _, conf := range configurable {
+			devices[c.class] = append(devices[c.class], conf.Named())
+		}
+	}
+
+	if dryRun {
+		delete(devices, ClassCircuit)
+		res = append(res, checkDevices(devices, timeout)...)
+	}
+
+	slices.SortStableFunc(res, func(a, b configProblem) int {
+		return cmp.Or(cmp.Compare(a.Class, b.Class), cmp.Compare(a.Name, b.Name))
+	})
+
+	return res
+}
+
+func runConfigCheck(cmd *cobra.Command, args []string) {
+	if err := loadConfigFile(&conf, !cmd.Flag(flagIgnoreDatabase).Changed); err != nil {
+		fmt.Println(configProblem{ClassConfigFile, "", err})
 		os.Exit(1)
+	}
+
+	dryRun, _ := cmd.Flags().GetBool(flagDryRun)
+	timeout, _ := cmd.Flags().GetDuration(flagTimeout)
+
+	var problems []configProblem
+
+	// dry-run requires the full environment like mqtt or javascript
+	var err error
+	if dryRun {
+		err = configureEnvironment(cmd, &conf)
 	} else {
+		err = wrapErrorWithClass(ClassDatabase, configureDatabase(conf.Database))
+	}
+	if err != nil {
+		problems = append(problems, configProblem{ClassConfigFile, "", err})
+	}
+
+	if err := collectRefs(conf); err != nil {
+		problems = append(problems, configProblem{ClassSite, "", err})
+	}
+
+	problems = append(problems, validateConfig(conf, dryRun, timeout)...)
+
+	if len(problems) == 0 {
 		fmt.Println("config valid")
+		return
+	}
+
+	for _, p := range problems {
+		fmt.Println(p)
 	}
+
+	fmt.Printf("\nconfig invalid: %d problem(s)\n", len(problems))
+	os.Exit(1)
 }
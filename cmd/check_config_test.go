@@ -0,0 +1,33 @@
+package cmd
+
+import (
+	"errors"
+	"fmt"
+	"testing"
+
+	"github.com/evcc-io/evcc/api"
+	"github.com/evcc-io/evcc/util/config"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCheckConfigNamesAndReferences(t *testing.T) {
+	names, problems := checkNames(ClassMeter, []config.Named{
+		{Name: "grid"},
+		{Name: "pv"},
+		{Name: "pv"},
+		{},
+	}, nil)
+
+	assert.Equal(t, map[string]bool{"grid": true, "pv": true}, names)
+	assert.Len(t, problems, 2)
+
+	problems = checkReferences(ClassMeter, names, []string{"grid", "", "battery", "battery"})
+	assert.Equal(t, []configProblem{{ClassMeter, "battery", errMissingReference}}, problems)
+	assert.Contains(t, problems[0].String(), "hint: add a device")
+}
+
+func TestCheckConfigHint(t *testing.T) {
+	assert.NotEmpty(t, configHint(ClassMeter, errors.New("modbus: exception '2' (illegal data address), function '3'")))
+	assert.Contains(t, configHint(ClassCharger, fmt.Errorf("cannot create charger: %w", api.ErrTimeout)), "ocpp")
+	assert.Empty(t, configHint(ClassMeter, errors.New("foo")))
+}
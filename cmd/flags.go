@@ -68,6 +68,12 @@ const (
 	flagHeartbeat            = "heartbeat"
 	flagHeartbeatDescription = "After command, continue running device heartbeats (if any) until interrupted"
 
+	flagHard            = "hard"
+	flagHardDescription = "Reboot immediately instead of stopping transactions gracefully"
+
+	flagConnector            = "connector"
+	flagConnectorDescription = "Connector id (0 for the charge point as a whole)"
+
 	flagTimeout            = "timeout"
 	flagTimeoutDescription = "Timeout"
 
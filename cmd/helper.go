@@ -10,6 +10,7 @@ import (
 	"github.com/evcc-io/evcc/cmd/shutdown"
 	"github.com/evcc-io/evcc/util"
 	"github.com/evcc-io/evcc/util/config"
+	"github.com/evcc-io/evcc/util/locale"
 	"go.yaml.in/yaml/v4"
 )
 
@@ -30,7 +31,16 @@ func parseLogLevels() {
 		}
 	}
 
+	// constrained devices log warnings by default, more verbose levels are kept for troubleshooting
+	if viper.GetBool("lowfootprint") && strings.EqualFold(level, "info") {
+		level = "warn"
+	}
+
 	util.LogLevel(level, levels)
+
+	if err := util.LogFormat(viper.GetString("logformat")); err != nil {
+		log.WARN.Println(err)
+	}
 }
 
 // unwrap converts a wrapped error into slice of strings
@@ -75,6 +85,12 @@ func joinErrors(errs ...error) error {
 	}
 }
 
+var (
+	msgPortInUse = &locale.Message{ID: "backend.error.portInUse", Other: "could not open port- check that evcc is not already running"}
+	msgFileInUse = &locale.Message{ID: "backend.error.fileInUse", Other: "could not remove file- check that evcc is not already running"}
+)
+
+// wrapFatalError adds an explanation in the system language to well-known errors
 func wrapFatalError(err error) error {
 	if err == nil {
 		return nil
@@ -86,12 +102,12 @@ func wrapFatalError(err error) error {
 	switch {
 	case errors.As(err, &opErr):
 		if opErr.Op == "listen" && strings.Contains(opErr.Error(), "address already in use") {
-			err = fmt.Errorf("could not open port- check that evcc is not already running (%w)", err)
+			err = fmt.Errorf("%s (%w)", locale.Localize("", msgPortInUse, nil), err)
 		}
 
 	case errors.As(err, &pathErr):
 		if pathErr.Op == "remove" && strings.Contains(pathErr.Error(), "operation not permitted") {
-			err = fmt.Errorf("could not remove file- check that evcc is not already running (%w)", err)
+			err = fmt.Errorf("%s (%w)", locale.Localize("", msgFileInUse, nil), err)
 		}
 	}
 
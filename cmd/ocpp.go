@@ -0,0 +1,257 @@
+//go:build !windows
+
+package cmd
+
+import (
+	"bytes"
+	"cmp"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"os"
+	"strings"
+	"text/tabwriter"
+	"time"
+
+	"github.com/evcc-io/evcc/charger/ocpp"
+	"github.com/evcc-io/evcc/server"
+	"github.com/lorenzodonini/ocpp-go/ocpp1.6/core"
+	"github.com/spf13/cobra"
+	"github.com/tv42/httpunix"
+)
+
+// ocppCmd represents the ocpp command
+var ocppCmd = &cobra.Command{
+	Use:   "ocpp",
+	Short: "Manage OCPP charge points of the running instance",
+}
+
+// ocppListCmd represents the ocpp list command
+var ocppListCmd = &cobra.Command{
+	Use:   "list",
+	Short: "List charge points with connectivity and connector status",
+	Run:   runOcppList,
+	Args:  cobra.NoArgs,
+}
+
+// ocppConfigCmd represents the ocpp config command
+var ocppConfigCmd = &cobra.Command{
+	Use:   "config <station id> [key[=value]]",
+	Short: "Get or change charge point configuration",
+	Run:   runOcppConfig,
+	Args:  cobra.RangeArgs(1, 2),
+}
+
+// ocppResetCmd represents the ocpp reset command
+var ocppResetCmd = &cobra.Command{
+	Use:   "reset <station id>",
+	Short: "Reset charge point",
+	Run:   runOcppReset,
+	Args:  cobra.ExactArgs(1),
+}
+
+// ocppTriggerCmd represents the ocpp trigger command
+var ocppTriggerCmd = &cobra.Command{
+	Use:   "trigger <station id> <message>",
+	Short: "Request charge point to send a message, e.g. StatusNotification or MeterValues",
+	Run:   runOcppTrigger,
+	Args:  cobra.ExactArgs(2),
+}
+
+func init() {
+	rootCmd.AddCommand(ocppCmd)
+	ocppCmd.AddCommand(ocppListCmd)
+	ocppCmd.AddCommand(ocppConfigCmd)
+	ocppCmd.AddCommand(ocppResetCmd)
+	ocppResetCmd.Flags().Bool(flagHard, false, flagHardDescription)
+	ocppCmd.AddCommand(ocppTriggerCmd)
+	ocppTriggerCmd.Flags().Int(flagConnector, 0, flagConnectorDescription)
+}
+
+// ocppRequest sends the request to the running instance's unix domain socket
+func ocppRequest(method, path string, body any) (*http.Response, error) {
+	u := &httpunix.Transport{
+		DialTimeout:           100 * time.Millisecond,
+		RequestTimeout:        ocpp.Timeout + time.Second,
+		ResponseHeaderTimeout: ocpp.Timeout + time.Second,
+	}
+
+	u.RegisterLocation(serviceName, server.SocketPath)
+
+	client := http.Client{
+		Transport: u,
+	}
+
+	var b bytes.Buffer
+	if body != nil {
+		if err := json.NewEncoder(&b).Encode(body); err != nil {
+			return nil, err
+		}
+	}
+
+	req, err := http.NewRequest(method, fmt.Sprintf("http+unix://%s%s", serviceName, path), &b)
+	if err != nil {
+		return nil, err
+	}
+
+	resp, err := client.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("evcc not running: %w", err)
+	}
+
+	if resp.StatusCode >= http.StatusBadRequest {
+		defer resp.Body.Close()
+
+		var res struct {
+			Error string `json:"error"`
+		}
+
+		if err := json.NewDecoder(resp.Body).Decode(&res); err != nil || res.Error == "" {
+			return nil, errors.New(resp.Status)
+		}
+
+		return nil, errors.New(res.Error)
+	}
+
+	return resp, nil
+}
+
+func runOcppList(cmd *cobra.Command, args []string) {
+	resp, err := ocppRequest(http.MethodGet, "/ocpp", nil)
+	if err != nil {
+		log.FATAL.Fatal(err)
+	}
+	defer resp.Body.Close()
+
+	var res []ocpp.ChargePointInfo
+	if err := json.NewDecoder(resp.Body).Decode(&res); err != nil {
+		log.FATAL.Fatal(err)
+	}
+
+	w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(w, "STATION\tPROTOCOL\tSTATE\tHEARTBEAT\tFIRMWARE\tCONNECTORS")
+	for _, cp := range res {
+		state := "offline"
+		if cp.Connectivity.Online {
+			state = "online"
+		}
+
+		heartbeat := "-"
+		if hb := cp.Connectivity.LastHeartbeat; !hb.IsZero() {
+			heartbeat = hb.Local().Format(time.DateTime)
+		}
+
+		conns := make([]string, 0, len(cp.Connectors))
+		for _, conn := range cp.Connectors {
+			status := cmp.Or(string(conn.Status), "unknown")
+			if conn.Fault != nil {
+				status += " (" + string(conn.Fault.ErrorCode) + ")"
+			}
+			conns = append(conns, fmt.Sprintf("%d:%s", conn.Connector, status))
+		}
+
+		fmt.Fprintf(w, "%s\t%s\t%s\t%s\t%s\t%s\n", cp.ID, cmp.Or(cp.Protocol, "-"), state, heartbeat, cmp.Or(cp.Firmware, "-"), strings.Join(conns, " "))
+	}
+	w.Flush()
+}
+
+func runOcppConfig(cmd *cobra.Command, args []string) {
+	path := fmt.Sprintf("/ocpp/%s/config", url.PathEscape(args[0]))
+
+	var key string
+	if len(args) > 1 {
+		key = args[1]
+	}
+
+	// change configuration
+	if key, value, ok := strings.Cut(key, "="); ok {
+		resp, err := ocppRequest(http.MethodPut, path+"/"+url.PathEscape(key), struct {
+			Value string `json:"value"`
+		}{
+			Value: value,
+		})
+		if err != nil {
+			log.FATAL.Fatal(err)
+		}
+		resp.Body.Close()
+
+		fmt.Printf("%s: %s\n", key, value)
+		return
+	}
+
+	if key != "" {
+		path += "?key=" + url.QueryEscape(key)
+	}
+
+	resp, err := ocppRequest(http.MethodGet, path, nil)
+	if err != nil {
+		log.FATAL.Fatal(err)
+	}
+	defer resp.Body.Close()
+
+	var res core.GetConfigurationConfirmation
+	if err := json.NewDecoder(resp.Body).Decode(&res); err != nil {
+		log.FATAL.Fatal(err)
+	}
+
+	w := tabwriter.NewWriter(os.Stdout, 0, 0, 1, ' ', 0)
+	for _, kv := range res.ConfigurationKey {
+		var value string
+		if kv.Value != nil {
+			value = *kv.Value
+		}
+
+		var readonly string
+		if kv.Readonly {
+			readonly = "(readonly)"
+		}
+
+		fmt.Fprintf(w, "%s:\t%s\t%s\n", kv.Key, value, readonly)
+	}
+	for _, key := range res.UnknownKey {
+		fmt.Fprintf(w, "%s:\t%s\t\n", key, "(unknown)")
+	}
+	w.Flush()
+}
+
+func runOcppReset(cmd *cobra.Command, args []string) {
+	resetType := core.ResetTypeSoft
+	if cmd.Flag(flagHard).Changed {
+		resetType = core.ResetTypeHard
+	}
+
+	resp, err := ocppRequest(http.MethodPost, fmt.Sprintf("/ocpp/%s/reset", url.PathEscape(args[0])), struct {
+		Type string `json:"type"`
+	}{
+		Type: strings.ToLower(string(resetType)),
+	})
+	if err != nil {
+		log.FATAL.Fatal(err)
+	}
+	resp.Body.Close()
+
+	fmt.Printf("%s: %s reset accepted\n", args[0], strings.ToLower(string(resetType)))
+}
+
+func runOcppTrigger(cmd *cobra.Command, args []string) {
+	connector, err := cmd.Flags().GetInt(flagConnector)
+	if err != nil {
+		log.FATAL.Fatal(err)
+	}
+
+	resp, err := ocppRequest(http.MethodPost, fmt.Sprintf("/ocpp/%s/trigger", url.PathEscape(args[0])), struct {
+		Message   string `json:"message"`
+		Connector int    `json:"connector"`
+	}{
+		Message:   args[1],
+		Connector: connector,
+	})
+	if err != nil {
+		log.FATAL.Fatal(err)
+	}
+	resp.Body.Close()
+
+	fmt.Printf("%s: %s trigger accepted\n", args[0], args[1])
+}
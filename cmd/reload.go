@@ -0,0 +1,308 @@
+package cmd
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"reflect"
+	"slices"
+	"strings"
+	"sync"
+
+	"github.com/evcc-io/evcc/api"
+	"github.com/evcc-io/evcc/api/globalconfig"
+	"github.com/evcc-io/evcc/charger"
+	"github.com/evcc-io/evcc/core"
+	"github.com/evcc-io/evcc/core/loadpoint"
+	"github.com/evcc-io/evcc/meter"
+	"github.com/evcc-io/evcc/util"
+	"github.com/evcc-io/evcc/util/config"
+)
+
+// reloadable are the config file sections that are applied at runtime
+var reloadable = []string{"Meters", "Chargers", "Vehicles", "Site"}
+
+// errRestartRequired is returned for configuration changes that cannot be applied at runtime
+var errRestartRequired = errors.New("restart required")
+
+// reloader applies configuration file changes at runtime.
+//
+// Devices are matched by name: new devices are created, changed devices replaced and
+// removed devices deleted once no longer referenced. Loadpoints are kept and re-resolve
+// their devices, so active charging sessions continue. OCPP chargers with unchanged
+// station id re-use the existing connection. Loadpoint changes are rejected, all other
+// changes require a restart.
+type reloader struct {
+	mu   sync.Mutex
+	conf globalconfig.All // applied configuration file
+	site *core.Site
+
+	// contexts of devices created by reloading
+	meters, chargers, vehicles deviceContexts
+}
+
+// deviceContexts holds the cancel functions of reloaded devices by name
+type deviceContexts map[string]context.CancelFunc
+
+// cancel cancels the context of a replaced or removed device
+func (c deviceContexts) cancel(name string) {
+	if cancel, ok := c[name]; ok {
+		cancel()
+		delete(c, name)
+	}
+}
+
+func newReloader(site *core.Site) (*reloader, error) {
+	r := &reloader{
+		site:     site,
+		meters:   make(deviceContexts),
+		chargers: make(deviceContexts),
+		vehicles: make(deviceContexts),
+	}
+
+	// unmodified copy of the configuration file
+	if err := viper.UnmarshalExact(&r.conf); err != nil {
+		return nil, err
+	}
+
+	return r, nil
+}
+
+// Reload re-reads the configuration file and applies the changes. It returns true if
+// a restart is required to apply all changes.
+func (r *reloader) Reload() (bool, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if r.site == nil {
+		return false, errors.New("site not configured")
+	}
+
+	if err := viper.ReadInConfig(); err != nil {
+		return false, fmt.Errorf("failed reading config file: %w", err)
+	}
+
+	var next globalconfig.All
+	if err := viper.UnmarshalExact(&next); err != nil {
+		return false, fmt.Errorf("failed parsing config file: %w", err)
+	}
+
+	// loadpoints are not re-created at runtime
+	if !reflect.DeepEqual(r.conf.Loadpoints, next.Loadpoints) {
+		return true, fmt.Errorf("loadpoints changed: %w", errRestartRequired)
+	}
+
+	// collect references for filtering used devices, keeping the applied references if invalid
+	prev := references
+	references.meter, references.charger, references.vehicle, references.circuit = nil, nil, nil, nil
+
+	nextMeters, err := r.validate(next)
+	if err != nil {
+		references = prev
+		return false, err
+	}
+
+	oldMeters, err := siteMeters(r.conf.Site)
+	if err != nil {
+		return false, err
+	}
+
+	restart := restartSections(r.conf, next)
+	for _, section := range restart {
+		log.WARN.Printf("reload: %s changed, restart required", section)
+	}
+
+	var errs []error
+
+	// create and replace devices before updating references
+	if err := reloadDevices(config.Meters(), next.Meters, references.meter, r.meters, meterInstance); err != nil {
+		errs = append(errs, &ClassError{ClassMeter, err})
+	}
+
+	if err := reloadDevices(config.Chargers(), next.Chargers, references.charger, r.chargers, chargerInstance); err != nil {
+		errs = append(errs, &ClassError{ClassCharger, err})
+	}
+
+	if err := reloadDevices(config.Vehicles(), next.Vehicles, nil, r.vehicles, vehicleInstance); err != nil {
+		errs = append(errs, &ClassError{ClassVehicle, err})
+	}
+
+	if len(errs) > 0 {
+		return len(restart) > 0, joinErrors(errs...)
+	}
+
+	if !reflect.DeepEqual(oldMeters, nextMeters) {
+		r.site.ReloadMeters(nextMeters)
+	}
+
+	// tear down devices no longer in use
+	pruneDevices(config.Meters(), next.Meters, references.meter, r.meters)
+	pruneDevices(config.Chargers(), next.Chargers, references.charger, r.chargers)
+	pruneDevices(config.Vehicles(), next.Vehicles, nil, r.vehicles)
+
+	r.conf = next
+	log.INFO.Println("reload: config file applied")
+
+	return len(restart) > 0, nil
+}
+
+// validate collects the references of the next configuration and rejects invalid configurations
+// before anything is applied. It returns the site's meter references.
+func (r *reloader) validate(next globalconfig.All) (core.MetersConfig, error) {
+	if err := collectRefs(next); err != nil {
+		return core.MetersConfig{}, err
+	}
+
+	if problems := validateConfig(next, false, 0); len(problems) > 0 {
+		errs := make([]error, 0, len(problems))
+		for _, p := range problems {
+			errs = append(errs, errors.New(p.String()))
+		}
+		return core.MetersConfig{}, joinErrors(errs...)
+	}
+
+	res, err := siteMeters(next.Site)
+	if err != nil {
+		return core.MetersConfig{}, &ClassError{ClassSite, err}
+	}
+
+	return res, nil
+}
+
+// siteMeters decodes the site's meter references
+func siteMeters(site map[string]any) (core.MetersConfig, error) {
+	var cc struct {
+		Meters core.MetersConfig
+		Other  map[string]any `mapstructure:",remain"`
+	}
+
+	err := util.DecodeOther(site, &cc)
+
+	return cc.Meters, err
+}
+
+// restartSections returns the changed configuration file sections that cannot be applied at runtime
+func restartSections(old, next globalconfig.All) []string {
+	var res []string
+
+	o, n := reflect.ValueOf(old), reflect.ValueOf(next)
+	for i := range o.NumField() {
+		name := o.Type().Field(i).Name
+		if slices.Contains(reloadable, name) {
+			continue
+		}
+
+		if !reflect.DeepEqual(o.Field(i).Interface(), n.Field(i).Interface()) {
+			res = append(res, strings.ToLower(name))
+		}
+	}
+
+	// site settings other than meters
+	strip := func(site map[string]any) map[string]any {
+		res := make(map[string]any, len(site))
+		for k, v := range site {
+			if !strings.EqualFold(k, "meters") {
+				res[k] = v
+			}
+		}
+		return res
+	}
+
+	if !reflect.DeepEqual(strip(old.Site), strip(next.Site)) {
+		res = append(res, "site")
+	}
+
+	return res
+}
+
+func meterInstance(ctx context.Context, cc config.Named) (api.Meter, error) {
+	ctx = util.WithLogger(ctx, util.NewLogger(cc.Name))
+	return meter.NewFromConfig(ctx, cc.Type, cc.Other)
+}
+
+func chargerInstance(ctx context.Context, cc config.Named) (api.Charger, error) {
+	ctx = util.WithLogger(ctx, util.NewLogger(cc.Name))
+	return charger.NewFromConfig(ctx, cc.Type, cc.Other)
+}
+
+// reloadDevices creates new and replaces changed static devices. Devices not contained in names are skipped.
+// Devices are created with a context that is cancelled once the device is replaced or removed.
+func reloadDevices[T any](h config.Handler[T], next []config.Named, names []string, ctxs deviceContexts, create func(context.Context, config.Named) (T, error)) error {
+	var errs []error
+
+	for _, cc := range next {
+		if len(names) > 0 && !slices.Contains(names, cc.Name) {
+			continue
+		}
+
+		dev, err := h.ByName(cc.Name)
+		if err == nil && reflect.DeepEqual(dev.Config(), cc) {
+			continue
+		}
+
+		ctx, cancel := context.WithCancel(context.Background())
+
+		instance, err := create(ctx, cc)
+		if err != nil {
+			cancel()
+			errs = append(errs, &DeviceError{cc.Name, err})
+			continue
+		}
+
+		if dev == nil {
+			err = h.Add(config.NewStaticDevice(cc, instance))
+			log.DEBUG.Printf("reload: %s added", cc.Name)
+		} else {
+			err = h.Replace(config.NewStaticDevice(cc, instance))
+			log.DEBUG.Printf("reload: %s replaced", cc.Name)
+		}
+
+		if err != nil {
+			cancel()
+			errs = append(errs, &DeviceError{cc.Name, err})
+			continue
+		}
+
+		ctxs.cancel(cc.Name)
+		ctxs[cc.Name] = cancel
+	}
+
+	return joinErrors(errs...)
+}
+
+// pruneDevices deletes static devices that are no longer configured or referenced
+func pruneDevices[T any](h config.Handler[T], next []config.Named, names []string, ctxs deviceContexts) {
+	for _, dev := range h.Devices() {
+		if _, ok := dev.(config.ConfigurableDevice[T]); ok {
+			continue
+		}
+
+		name := dev.Config().Name
+		if slices.ContainsFunc(next, func(cc config.Named) bool {
+			return cc.Name == name && (len(names) == 0 || slices.Contains(names, name))
+		}) {
+			continue
+		}
+
+		if loadpointUses(name) {
+			log.WARN.Printf("reload: %s still used by loadpoint, restart required", name)
+			continue
+		}
+
+		if err := h.Delete(name); err != nil {
+			log.ERROR.Printf("reload: %v", err)
+			continue
+		}
+
+		ctxs.cancel(name)
+		log.DEBUG.Printf("reload: %s removed", name)
+	}
+}
+
+// loadpointUses checks if the device is referenced by a running loadpoint
+func loadpointUses(name string) bool {
+	return slices.ContainsFunc(config.Loadpoints().Devices(), func(dev config.Device[loadpoint.API]) bool {
+		lp := dev.Instance()
+		return lp != nil && slices.Contains([]string{lp.GetChargerRef(), lp.GetMeterRef(), lp.GetDefaultVehicleRef()}, name)
+	})
+}
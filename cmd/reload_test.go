@@ -0,0 +1,83 @@
+package cmd
+
+import (
+	"context"
+	"testing"
+
+	"github.com/evcc-io/evcc/api"
+	"github.com/evcc-io/evcc/api/globalconfig"
+	"github.com/evcc-io/evcc/util/config"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestReloadRestartSections(t *testing.T) {
+	old := globalconfig.All{
+		Interval: 30,
+		Meters:   []config.Named{{Name: "grid"}},
+		Site:     map[string]any{"title": "home", "meters": map[string]any{"grid": "grid"}},
+	}
+
+	next := old
+	next.Meters = []config.Named{{Name: "grid2"}}
+	next.Site = map[string]any{"title": "home", "meters": map[string]any{"grid": "grid2"}}
+	assert.Empty(t, restartSections(old, next), "devices and site meters are reloadable")
+
+	next.Interval = 10
+	next.Site = map[string]any{"title": "house"}
+	assert.Equal(t, []string{"interval", "site"}, restartSections(old, next))
+}
+
+func TestReloadDevices(t *testing.T) {
+	config.Reset()
+
+	meter := func(name string, power float64) config.Named {
+		return config.Named{
+			Name: name,
+			Type: "custom",
+			Other: map[string]any{
+				"power": map[string]any{"source": "const", "value": power},
+			},
+		}
+	}
+
+	h := config.Meters()
+	ctxs := make(deviceContexts)
+
+	created := make(map[string]context.Context)
+	create := func(ctx context.Context, cc config.Named) (api.Meter, error) {
+		created[cc.Name] = ctx
+		return meterInstance(ctx, cc)
+	}
+
+	conf := []config.Named{meter("grid", 1), meter("pv", 2)}
+	require.NoError(t, reloadDevices(h, conf, nil, ctxs, create))
+	require.Len(t, h.Devices(), 2)
+
+	gridCtx, pvCtx := created["grid"], created["pv"]
+
+	grid, err := h.ByName("grid")
+	require.NoError(t, err)
+
+	// unchanged device is kept, changed device replaced, unreferenced device removed
+	conf = []config.Named{meter("grid", 1), meter("pv", 3)}
+	require.NoError(t, reloadDevices(h, conf, []string{"grid", "pv"}, ctxs, create))
+	assert.Error(t, pvCtx.Err(), "replaced device context cancelled")
+	assert.NoError(t, gridCtx.Err())
+
+	pv, err := h.ByName("pv")
+	require.NoError(t, err)
+	power, err := pv.Instance().CurrentPower()
+	require.NoError(t, err)
+	assert.Equal(t, 3.0, power)
+
+	dev, err := h.ByName("grid")
+	require.NoError(t, err)
+	assert.Same(t, grid, dev)
+
+	pruneDevices(h, conf, []string{"pv"}, ctxs)
+	_, err = h.ByName("grid")
+	assert.Error(t, err)
+	assert.Len(t, h.Devices(), 1)
+	assert.Error(t, gridCtx.Err(), "removed device context cancelled")
+}
@@ -46,6 +46,7 @@ var (
 	ignoreEmpty   = ""                                      // ignore empty keys
 	ignoreLogs    = []string{"log"}                         // ignore log messages, including warn/error
 	ignoreMqtt    = []string{"log", "auth", "releaseNotes"} // excessive size may crash certain brokers
+	ignoreLean    = []string{keys.Forecast}                 // large time series not pushed to the UI in low footprint mode
 
 	viper *vpr.Viper
 
@@ -86,12 +87,18 @@ func init() {
 	rootCmd.PersistentFlags().StringP("log", "l", "info", "Log level (fatal, error, warn, info, debug, trace)")
 	bindP(rootCmd, "log")
 
+	rootCmd.PersistentFlags().String("log-format", "text", "Log format (text, json)")
+	bindP(rootCmd, "logformat", "log-format")
+
 	rootCmd.Flags().Bool("metrics", false, "Expose metrics")
 	bind(rootCmd, "metrics")
 
 	rootCmd.Flags().Bool("profile", false, "Expose pprof profiles")
 	bind(rootCmd, "profile")
 
+	rootCmd.Flags().Bool("low-footprint", false, "Reduce memory and cpu usage on constrained devices")
+	bind(rootCmd, "lowfootprint", "low-footprint")
+
 	rootCmd.Flags().Bool("mcp", false, "Expose MCP service (experimental)")
 	bind(rootCmd, "mcp")
 
@@ -148,6 +155,11 @@ func runRoot(cmd *cobra.Command, args []string) {
 		}
 	}
 
+	// reduce resource usage before creating devices
+	if viper.GetBool("lowfootprint") {
+		configureLowFootprint()
+	}
+
 	// setup environment
 	if err == nil {
 		err = configureEnvironment(cmd, &conf)
@@ -158,6 +170,11 @@ func runRoot(cmd *cobra.Command, args []string) {
 		err = networkSettings(&conf.Network)
 	}
 
+	// bind all listeners to configured addresses, all interfaces on error
+	if err == nil {
+		err = util.SetListenHosts(conf.Network.Listen)
+	}
+
 	// configure plugin external url
 	if err == nil {
 		// network configuration complete, start dependent services like HomeAssistant discovery
@@ -179,15 +196,28 @@ func runRoot(cmd *cobra.Command, args []string) {
 
 	// start serving in background, watch for “routine‐only” errors
 	go func() {
-		if err := wrapFatalError(httpd.Server.ListenAndServe()); err != nil && err != http.ErrServerClosed {
-			log.FATAL.Println(err)
+		ln, err := util.Listen(conf.Network.Port)
+		if err == nil {
+			err = httpd.Server.Serve(ln)
+		}
+		if err != nil && err != http.ErrServerClosed {
+			log.FATAL.Println(wrapFatalError(err))
 			os.Exit(1)
 		}
 	}()
-	log.INFO.Printf("UI listening at :%d", conf.Network.Port)
+	log.INFO.Printf("UI listening at %s", strings.Join(util.ListenAddrs(conf.Network.Port), ", "))
+
+	// serve https
+	if err == nil && conf.Tls.Port != 0 {
+		err = httpd.StartTLS(conf.Tls)
+	}
 
 	// publish to UI
-	go socketHub.Run(pipe.NewDropper(ignoreEmpty).Pipe(tee.Attach()), cache)
+	ignoreUI := []string{ignoreEmpty}
+	if viper.GetBool("lowfootprint") {
+		ignoreUI = append(ignoreUI, ignoreLean...)
+	}
+	go socketHub.Run(pipe.NewDropper(ignoreUI...).Pipe(tee.Attach()), cache)
 
 	// signal ui listening
 	valueChan <- util.Param{Key: keys.StartupCompleted, Val: false}
@@ -224,6 +254,11 @@ func runRoot(cmd *cobra.Command, args []string) {
 		site, err = configureSiteAndLoadpoints(&conf)
 	}
 
+	// setup modbus server
+	if err == nil {
+		err = wrapErrorWithClass(ClassModbusServer, configureModbusServer(conf.ModbusServer, site, cache))
+	}
+
 	// setup influx
 	if err == nil {
 		influx, ierr := configureInflux(&conf.Influx)
@@ -261,7 +296,15 @@ func runRoot(cmd *cobra.Command, args []string) {
 	// setup mqtt publisher
 	if err == nil && conf.Mqtt.Broker != "" && conf.Mqtt.Topic != "" {
 		var mqtt *server.MQTT
-		mqtt, err = server.NewMQTT(strings.Trim(conf.Mqtt.Topic, "/"), site)
+		mqtt, err = server.NewMQTT(strings.Trim(conf.Mqtt.Topic, "/"), conf.Mqtt.Schema, site)
+		if err == nil {
+			if conf.Mqtt.Discovery != "" {
+				mqtt.PublishDiscovery(conf.Mqtt.Discovery, site)
+			}
+			if conf.Mqtt.Homie != "" {
+				err = mqtt.PublishHomie(conf.Mqtt.Homie, site)
+			}
+		}
 		if err == nil {
 			go mqtt.Run(site, pipe.NewDropper(append(ignoreMqtt, ignoreEmpty)...).Pipe(tee.Attach()))
 		}
@@ -297,7 +340,7 @@ func runRoot(cmd *cobra.Command, args []string) {
 	// setup messaging
 	var pushChan chan push.Event
 	if err == nil {
-		pushChan, err = configureMessengers(&conf.Messaging, site.Vehicles(), valueChan, cache)
+		pushChan, err = configureMessengers(&conf.Messaging, site, valueChan, cache)
 		err = wrapErrorWithClass(ClassMessenger, err)
 	}
 
@@ -331,6 +374,33 @@ func runRoot(cmd *cobra.Command, args []string) {
 		once.Do(func() { close(stopC) }) // signal loop to end
 	}()
 
+	// apply config file changes at runtime
+	configReloader, rerr := newReloader(site)
+	if rerr != nil {
+		log.ERROR.Println("reload:", rerr)
+	}
+
+	reload := func() (bool, error) {
+		if configReloader == nil {
+			return false, errors.New("reload not available")
+		}
+		return configReloader.Reload()
+	}
+
+	// reload config file on SIGHUP
+	go func() {
+		signalC := make(chan os.Signal, 1)
+		signal.Notify(signalC, syscall.SIGHUP)
+
+		for range signalC {
+			if restart, err := reload(); err != nil {
+				log.ERROR.Println("reload:", err)
+			} else if restart {
+				log.WARN.Println("reload: restart required to apply all changes")
+			}
+		}
+	}()
+
 	// allow web access for vehicles
 	configureAuth(httpd.Router(), valueChan)
 
@@ -351,20 +421,45 @@ func runRoot(cmd *cobra.Command, args []string) {
 		log.INFO.Println("evcc was stopped by user. OS should restart the service. Or restart manually.")
 		err = errors.New("restart required") // https://gokrazy.org/development/process-interface/
 		once.Do(func() { close(stopC) })     // signal loop to end
-	}, viper.ConfigFileUsed())
+	}, reload, viper.ConfigFileUsed())
 
 	// show and check version, reduce api load during development
 	if util.Version != util.DevVersion {
 		go updater.Run(log, httpd, valueChan)
 	}
 
+	// setup grpc
+	if err == nil {
+		err = configureGrpc(conf.Grpc, site, cache, authObject, tee)
+	}
+
+	// setup remote access
+	if err == nil {
+		err = configureRemote(conf.Remote, httpd.Server.Handler, authObject)
+	}
+
+	// expose loadpoints to roaming platforms
+	if err == nil {
+		err = configureOcpi(conf.Ocpi, site, conf.Network.ExternalURL(), httpd.Router())
+	}
+
+	// public status page for displays
+	if err == nil {
+		err = configureKiosk(conf.Kiosk, cache, httpd.Router())
+	}
+
+	// apply database retention policies
+	if err == nil {
+		err = wrapErrorWithClass(ClassDatabase, configureRetention(conf.Database.Retention))
+	}
+
 	// setup site
 	if err == nil {
 		// set channels
 		site.DumpConfig()
 		site.Prepare(valueChan, pushChan)
 
-		httpd.RegisterSiteHandlers(site, valueChan)
+		httpd.RegisterSiteHandlers(site, valueChan, authObject)
 
 		go func() {
 			site.Run(stopC, conf.Interval)
@@ -5,8 +5,12 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"net"
+	"net/http"
 	"os"
+	"path/filepath"
 	"regexp"
+	"runtime/debug"
 	"slices"
 	"strconv"
 	"strings"
@@ -17,6 +21,7 @@ import (
 	"github.com/evcc-io/evcc/api"
 	"github.com/evcc-io/evcc/api/globalconfig"
 	"github.com/evcc-io/evcc/charger"
+	"github.com/evcc-io/evcc/charger/ocpp"
 	"github.com/evcc-io/evcc/cmd/shutdown"
 	"github.com/evcc-io/evcc/core"
 	"github.com/evcc-io/evcc/core/circuit"
@@ -34,24 +39,33 @@ import (
 	"github.com/evcc-io/evcc/push"
 	"github.com/evcc-io/evcc/server"
 	"github.com/evcc-io/evcc/server/db"
+	"github.com/evcc-io/evcc/server/db/audit"
 	"github.com/evcc-io/evcc/server/db/cache"
+	"github.com/evcc-io/evcc/server/db/journal"
 	"github.com/evcc-io/evcc/server/db/settings"
 	"github.com/evcc-io/evcc/server/eebus"
+	"github.com/evcc-io/evcc/server/kiosk"
 	"github.com/evcc-io/evcc/server/modbus"
+	"github.com/evcc-io/evcc/server/ocpi"
 	"github.com/evcc-io/evcc/server/providerauth"
+	"github.com/evcc-io/evcc/server/remote"
+	"github.com/evcc-io/evcc/server/rpc"
 	"github.com/evcc-io/evcc/tariff"
 	"github.com/evcc-io/evcc/util"
+	"github.com/evcc-io/evcc/util/auth"
 	"github.com/evcc-io/evcc/util/config"
 	_ "github.com/evcc-io/evcc/util/demo"
 	"github.com/evcc-io/evcc/util/locale"
 	"github.com/evcc-io/evcc/util/machine"
 	"github.com/evcc-io/evcc/util/request"
+	"github.com/evcc-io/evcc/util/scheduler"
 	"github.com/evcc-io/evcc/util/sponsor"
 	"github.com/evcc-io/evcc/util/templates"
 	"github.com/evcc-io/evcc/vehicle"
 	"github.com/gorilla/handlers"
 	"github.com/gorilla/mux"
 	"github.com/libp2p/zeroconf/v2"
+	"github.com/mitchellh/go-homedir"
 	"github.com/samber/lo"
 	"github.com/spf13/cast"
 	"github.com/spf13/cobra"
@@ -367,8 +381,8 @@ func configureChargers(static []config.Named, names ...string) error {
 	return eg.Wait()
 }
 
-func vehicleInstance(cc config.Named) (api.Vehicle, error) {
-	ctx := util.WithLogger(context.TODO(), util.NewLogger(cc.Name))
+func vehicleInstance(ctx context.Context, cc config.Named) (api.Vehicle, error) {
+	ctx = util.WithLogger(ctx, util.NewLogger(cc.Name))
 
 	props, err := customDevice(cc.Other)
 
@@ -417,7 +431,7 @@ func configureVehicles(static []config.Named, names ...string) error {
 		}
 
 		eg.Go(func() error {
-			instance, err := vehicleInstance(cc)
+			instance, err := vehicleInstance(context.TODO(), cc)
 			if err != nil {
 				return fmt.Errorf("cannot create vehicle '%s': %w", cc.Name, err)
 			}
@@ -447,7 +461,7 @@ func configureVehicles(static []config.Named, names ...string) error {
 				return nil
 			}
 
-			instance, err := vehicleInstance(cc)
+			instance, err := vehicleInstance(context.TODO(), cc)
 			if err != nil {
 				return fmt.Errorf("cannot create vehicle '%s': %w", cc.Name, err)
 			}
@@ -487,6 +501,18 @@ func configureVehicles(static []config.Named, names ...string) error {
 	return nil
 }
 
+// configureOcppIdentifiers sends the vehicles' identifiers to ocpp charge points for offline authorization
+// and accepts them when authorizing known id tags only
+func configureOcppIdentifiers() {
+	var ids []string
+	for _, dev := range config.Vehicles().Devices() {
+		ids = append(ids, dev.Instance().Identifiers()...)
+	}
+
+	ocpp.SetLocalAuthList(ids)
+	ocpp.SetAuthorizedIdentifiers(ids)
+}
+
 func configureSponsorship(token string) (err error) {
 	if settings.Exists(keys.SponsorToken) {
 		if token, err = settings.String(keys.SponsorToken); err != nil {
@@ -550,6 +576,11 @@ func configureEnvironment(cmd *cobra.Command, conf *globalconfig.All) error {
 		err = wrapErrorWithClass(ClassEEBus, configureEEBus(&conf.EEBus))
 	}
 
+	// setup ocpp central system
+	if err == nil {
+		err = wrapErrorWithClass(ClassCharger, configureOcpp(conf.Ocpp))
+	}
+
 	// setup javascript VMs
 	if err == nil {
 		err = wrapErrorWithClass(ClassJavascript, configureJavascript(conf.Javascript))
@@ -563,49 +594,260 @@ func configureEnvironment(cmd *cobra.Command, conf *globalconfig.All) error {
 	return err
 }
 
-// configureDatabase configures session database
-func configureDatabase(conf globalconfig.DB) error {
-	if conf.Dsn == "" {
-		conf.Dsn = userDB
+// configureOcpp starts the ocpp central system unless using the defaults
+func configureOcpp(conf globalconfig.Ocpp) error {
+	// central system may also be started on demand by chargers
+	shutdown.Register(ocpp.Stop)
+
+	if conf.Port == 0 && len(conf.Listen) == 0 && conf.Prefix == "" && conf.Tls == (globalconfig.OcppTls{}) && conf.Secret == "" && conf.CA == "" && conf.Firmware == "" && conf.Diagnostics == "" && conf.Trace == "" && conf.Authorization == "" && conf.Shutdown == "" && conf.Queue == (globalconfig.OcppQueue{}) {
+		return nil
 	}
 
-	if err := db.NewInstance(conf.Type, conf.Dsn); err != nil {
+	listen, err := util.ResolveHosts(conf.Listen)
+	if err != nil {
 		return err
 	}
 
-	if err := session.Init(); err != nil {
+	authorization, err := ocpp.ParseAuthorizationPolicy(conf.Authorization)
+	if err != nil {
 		return err
 	}
 
-	if err := metrics.Init(); err != nil {
+	shutdownAction, err := ocpp.ParseShutdownAction(conf.Shutdown)
+	if err != nil {
 		return err
 	}
 
-	if err := settings.Init(); err != nil {
+	queuePolicy, err := ocpp.ParseQueuePolicy(conf.Queue.Policy)
+	if err != nil {
 		return err
 	}
 
-	if err := cache.Init(); err != nil {
+	opts := []ocpp.Option{
+		ocpp.WithPort(conf.Port),
+		ocpp.WithListen(listen...),
+		ocpp.WithPrefix(conf.Prefix),
+		ocpp.WithTLS(conf.Tls.Certificate, conf.Tls.Key, conf.Tls.ClientCA),
+		ocpp.WithSecret(conf.Secret),
+		ocpp.WithAuthorization(authorization),
+		ocpp.WithShutdown(shutdownAction),
+		ocpp.WithQueue(conf.Queue.Size, queuePolicy),
+	}
+
+	if conf.CA != "" {
+		dir, err := homedir.Expand(conf.CA)
+		if err != nil {
+			return err
+		}
+
+		ca, err := ocpp.NewCA(dir)
+		if err != nil {
+			return fmt.Errorf("ocpp ca: %w", err)
+		}
+
+		opts = append(opts, ocpp.WithSigner(ca))
+	}
+
+	if conf.Firmware != "" {
+		dir, err := homedir.Expand(conf.Firmware)
+		if err != nil {
+			return err
+		}
+
+		opts = append(opts, ocpp.WithFirmware(dir))
+	}
+
+	if conf.Diagnostics != "" {
+		dir, err := homedir.Expand(conf.Diagnostics)
+		if err != nil {
+			return err
+		}
+
+		opts = append(opts, ocpp.WithDiagnostics(dir))
+	}
+
+	if conf.Trace != "" {
+		dir, err := homedir.Expand(conf.Trace)
+		if err != nil {
+			return err
+		}
+
+		opts = append(opts, ocpp.WithTrace(dir))
+	}
+
+	return ocpp.Start(opts...)
+}
+
+// configureLowFootprint reduces memory and cpu usage on constrained devices
+func configureLowFootprint() {
+	// no in-memory log history for the log viewer
+	util.DisableLogHistory()
+
+	// lengthen tariff and forecast polling
+	scheduler.Default.SetSlowdown(4)
+
+	// collect garbage more aggressively unless configured explicitly
+	if os.Getenv("GOMEMLIMIT") == "" {
+		debug.SetMemoryLimit(96 << 20)
+	}
+}
+
+// configureJournal opens the write-ahead journal for volatile runtime state
+func configureJournal(dbFile string) error {
+	if dbFile == ":memory:" {
+		return nil
+	}
+
+	file, err := homedir.Expand(dbFile)
+	if err != nil {
 		return err
 	}
 
-	if err := config.Init(); err != nil {
+	return journal.NewInstance(strings.TrimSuffix(file, filepath.Ext(file)) + ".journal")
+}
+
+// configureDatabase configures session database
+func configureDatabase(conf globalconfig.DB) error {
+	sqlite := strings.EqualFold(conf.Type, "sqlite")
+
+	if conf.Dsn == "" && sqlite {
+		conf.Dsn = userDB
+	}
+
+	if err := db.NewInstance(conf.Type, conf.Dsn); err != nil {
 		return err
 	}
 
-	persistSettings := func() {
-		if err := settings.Persist(); err != nil {
-			log.ERROR.Println("cannot save settings:", err)
+	// journal volatile state next to the sqlite database, replayed by the init functions below
+	if err := configureJournal(cmp.Or(db.FilePath, userDB)); err != nil {
+		return fmt.Errorf("journal: %w", err)
+	}
+
+	initDatabase := func() error {
+		for _, fn := range []func() error{
+			session.Init,
+			metrics.Init,
+			settings.Init,
+			cache.Init,
+			config.Init,
+			audit.Init,
+		} {
+			if err := fn(); err != nil {
+				return err
+			}
+		}
+
+		return nil
+	}
+
+	if err := initDatabase(); err != nil {
+		return err
+	}
+
+	// copy existing sqlite data into empty external database
+	if !sqlite {
+		migrated, err := migrateDatabase(cmp.Or(conf.Sqlite, userDB))
+		if err != nil {
+			return fmt.Errorf("database migration: %w", err)
+		}
+
+		// reload migrated data
+		if migrated {
+			if err := initDatabase(); err != nil {
+				return err
+			}
+		}
+	}
+
+	flush := func() {
+		if err := db.Flush(); err != nil {
+			log.ERROR.Println("cannot flush database:", err)
+		}
+		if err := journal.Instance.Sync(); err != nil {
+			log.ERROR.Println("cannot sync journal:", err)
 		}
 	}
 
-	// persist unsaved settings on shutdown
-	shutdown.Register(persistSettings)
+	// persist coalesced writes on shutdown
+	shutdown.Register(flush)
 
-	// persist unsaved settings every 30 minutes
+	// persist coalesced writes and sync the journal periodically to reduce storage wear
+	interval := cmp.Or(conf.Flush, time.Minute)
 	go func() {
-		for range time.Tick(time.Minute) {
-			persistSettings()
+		for range time.Tick(interval) {
+			flush()
+		}
+	}()
+
+	return nil
+}
+
+// migrateDatabase copies the sqlite database into the empty database instance
+func migrateDatabase(dsn string) (bool, error) {
+	file, err := homedir.Expand(dsn)
+	if err != nil {
+		return false, err
+	}
+
+	if _, err := os.Stat(file); errors.Is(err, os.ErrNotExist) {
+		return false, nil
+	}
+
+	if empty, err := db.Empty(); err != nil || !empty {
+		return false, err
+	}
+
+	src, err := db.New("sqlite", file)
+	if err != nil {
+		return false, err
+	}
+
+	defer func() {
+		if conn, err := src.DB(); err == nil {
+			conn.Close()
+		}
+	}()
+
+	log.INFO.Printf("migrating database %s to %s", file, db.Instance.Dialector.Name())
+
+	return true, db.Copy(src)
+}
+
+// minMetricsRetention covers the household profile used for optimization
+const minMetricsRetention = 31 * 24 * time.Hour
+
+// configureRetention periodically removes data exceeding the retention periods
+func configureRetention(conf globalconfig.Retention) error {
+	if conf.Metrics > 0 && conf.Metrics < minMetricsRetention {
+		return fmt.Errorf("metrics retention must be at least %v", minMetricsRetention)
+	}
+
+	if conf == (globalconfig.Retention{}) {
+		return nil
+	}
+
+	log := util.NewLogger("db")
+
+	purge := func(name string, retention time.Duration, fn func(time.Time) (int64, error)) {
+		if retention == 0 {
+			return
+		}
+
+		if n, err := fn(time.Now().Add(-retention)); err != nil {
+			log.ERROR.Printf("%s retention: %v", name, err)
+		} else if n > 0 {
+			log.DEBUG.Printf("%s retention: removed %d entries", name, n)
+		}
+	}
+
+	go func() {
+		ticker := time.NewTicker(24 * time.Hour)
+		defer ticker.Stop()
+
+		for ; true; <-ticker.C {
+			purge("metrics", conf.Metrics, metrics.Downsample)
+			purge("sessions", conf.Sessions, session.Purge)
+			purge("audit", conf.Audit, audit.Purge)
 		}
 	}()
 
@@ -653,7 +895,26 @@ func configureMqtt(conf *globalconfig.Mqtt) error {
 
 	log := util.NewLogger("mqtt")
 
+	var source net.Addr
+	if conf.Source != "" {
+		hosts, err := util.ResolveHosts([]string{conf.Source})
+		if err != nil {
+			return fmt.Errorf("mqtt source: %w", err)
+		}
+
+		addr, err := util.SourceAddr(hosts)
+		if err != nil {
+			return fmt.Errorf("mqtt source: %w", err)
+		}
+
+		source = &net.TCPAddr{IP: addr.AsSlice(), Zone: addr.Zone()}
+	}
+
 	instance, err := mqtt.RegisteredClient(log, conf.Broker, conf.User, conf.Password, conf.ClientID, 1, conf.Insecure, conf.CaCert, conf.ClientCert, conf.ClientKey, func(options *paho.ClientOptions) {
+		if source != nil {
+			options.SetDialer(&net.Dialer{Timeout: request.Timeout, LocalAddr: source})
+		}
+
 		if !runAsService || conf.Topic == "" {
 			return
 		}
@@ -798,7 +1059,7 @@ func configureEEBus(conf *eebus.Config) error {
 }
 
 // setup messaging
-func configureMessengers(conf *globalconfig.Messaging, vehicles push.Vehicles, valueChan chan<- util.Param, cache *util.ParamCache) (chan push.Event, error) {
+func configureMessengers(conf *globalconfig.Messaging, site *core.Site, valueChan chan<- util.Param, cache *util.ParamCache) (chan push.Event, error) {
 	// migrate settings
 	if settings.Exists(keys.Messaging) {
 		*conf = globalconfig.Messaging{}
@@ -809,7 +1070,9 @@ func configureMessengers(conf *globalconfig.Messaging, vehicles push.Vehicles, v
 
 	messageChan := make(chan push.Event, 1)
 
-	messageHub, err := push.NewHub(conf.Events, vehicles, cache)
+	lang := cmp.Or(conf.Language, locale.Language)
+
+	messageHub, err := push.NewHub(conf.Events, lang, site.Vehicles(), cache)
 	if err != nil {
 		return messageChan, fmt.Errorf("failed configuring push services: %w", err)
 	}
@@ -820,13 +1083,118 @@ func configureMessengers(conf *globalconfig.Messaging, vehicles push.Vehicles, v
 			return nil, fmt.Errorf("cannot decode push service '%s': %w", conf.Type, err)
 		}
 
+		// per-service templates, language, severity and throttling
+		var cc struct {
+			Events              map[string]globalconfig.MessagingEventTemplate
+			Language            string
+			Severity            push.Severity // minimum event severity
+			push.ThrottleConfig `mapstructure:",squash"`
+		}
+
+		other := make(map[string]any)
+		for _, k := range []string{"events", "language", "severity", "quiet", "dedupe", "digest"} {
+			if v, ok := props[k]; ok {
+				other[k] = v
+				delete(props, k)
+			}
+		}
+
+		if err := util.DecodeOther(other, &cc); err != nil {
+			return messageChan, fmt.Errorf("failed configuring push service %s: %w", conf.Type, err)
+		}
+
 		impl, err := push.NewFromConfig(context.TODO(), conf.Type, props)
 		if err != nil {
 			return messageChan, fmt.Errorf("failed configuring push service %s: %w", conf.Type, err)
 		}
-		messageHub.Add(impl)
+
+		if c, ok := impl.(push.Commander); ok {
+			c.Commands(site, cache)
+		}
+
+		if l, ok := impl.(push.Localizer); ok {
+			l.SetLanguage(cmp.Or(cc.Language, lang))
+		}
+
+		if cc.ThrottleConfig.Configured() {
+			if impl, err = push.NewThrottle(impl, cc.ThrottleConfig); err != nil {
+				return messageChan, fmt.Errorf("failed configuring push service %s: %w", conf.Type, err)
+			}
+		}
+
+		if err := messageHub.AddWithTemplates(impl, cc.Events, cc.Language, cc.Severity); err != nil {
+			return messageChan, fmt.Errorf("failed configuring push service %s: %w", conf.Type, err)
+		}
 	}
 
+	for _, conf := range conf.Webhooks {
+		wh, err := push.NewWebhook(conf)
+		if err != nil {
+			return messageChan, fmt.Errorf("failed configuring webhook %s: %w", conf.Uri, err)
+		}
+		messageHub.AddWebhook(wh)
+	}
+
+	// forward and publish ocpp security events
+	ocpp.SetSecurityEventHandler(func(event ocpp.SecurityEvent) {
+		messageChan <- push.Event{Event: "security", Error: strings.TrimSpace(fmt.Sprintf("%s: %s %s", event.Station, event.Type, event.TechInfo))}
+
+		valueChan <- util.Param{Key: keys.OcppSecurityEvents, Val: ocpp.SecurityEvents()}
+		if event.Critical {
+			valueChan <- util.Param{Key: keys.OcppSecurityWarnings, Val: ocpp.SecurityWarnings()}
+		}
+	})
+
+	// publish ocpp firmware update progress
+	ocpp.SetFirmwareStatusHandler(func(id string, status ocpp.FirmwareStatus) {
+		valueChan <- util.Param{Key: keys.OcppFirmware, Val: ocpp.FirmwareStatuses()}
+	})
+
+	// forward and publish id tags rejected by the ocpp authorization policy
+	ocpp.SetRejectedAuthorizationHandler(func(rejected ocpp.RejectedAuthorization) {
+		messageChan <- push.Event{Event: "rejected", Error: fmt.Sprintf("%s: %s", rejected.Station, rejected.IdTag)}
+		valueChan <- util.Param{Key: keys.OcppRejected, Val: ocpp.RejectedAuthorizations()}
+	})
+
+	// forward and publish ocpp transactions including those not initiated by evcc
+	ocpp.SetTransactionEventHandler(func(event ocpp.TransactionEvent) {
+		details := fmt.Sprintf("%s-%d: transaction %d %s", event.Station, event.Connector, event.TransactionId, event.Event)
+		if event.IdTag != "" {
+			details += " by " + event.IdTag
+		}
+		if event.Event == ocpp.TransactionStopped && event.MeterStop > 0 {
+			details += fmt.Sprintf(", %.1f kWh", event.MeterStop-event.MeterStart)
+		}
+
+		messageChan <- push.Event{Event: "transaction", Error: details}
+		valueChan <- util.Param{Key: keys.OcppTransaction, Val: event}
+	})
+
+	// publish ocpp diagnostics upload progress
+	ocpp.SetDiagnosticsStatusHandler(func(id string, status ocpp.DiagnosticsStatus) {
+		valueChan <- util.Param{Key: keys.OcppDiagnostics, Val: ocpp.DiagnosticsStatuses()}
+	})
+
+	// publish ocpp charge point reset progress
+	ocpp.SetResetStatusHandler(func(id string, status ocpp.ResetStatus) {
+		valueChan <- util.Param{Key: keys.OcppReset, Val: ocpp.ResetStatuses()}
+	})
+
+	// publish ocpp charge point connectivity
+	ocpp.SetConnectivityHandler(func(id string, connectivity ocpp.Connectivity) {
+		valueChan <- util.Param{Key: keys.OcppConnectivity, Val: ocpp.Connectivities()}
+	})
+
+	// publish unconfigured ocpp charge points for adoption
+	ocpp.SetPendingHandler(func(id string) {
+		valueChan <- util.Param{Key: keys.OcppPending, Val: ocpp.PendingChargePoints()}
+	})
+
+	// publish detected ocpp charge point capabilities
+	ocpp.SetCapabilitiesHandler(func(id string, capabilities ocpp.Capabilities) {
+		valueChan <- util.Param{Key: keys.OcppCapabilities, Val: ocpp.ChargePointCapabilities()}
+	})
+
 	go messageHub.Run(messageChan, valueChan)
 
 	return messageChan, nil
@@ -952,6 +1320,8 @@ func configureDevices(conf globalconfig.All) error {
 
 	if err := configureVehicles(conf.Vehicles); err != nil {
 		errs = append(errs, &ClassError{ClassVehicle, err})
+	} else {
+		configureOcppIdentifiers()
 	}
 
 	if err := configureCircuits(&conf.Circuits); err != nil {
@@ -1010,6 +1380,81 @@ func configureModbusProxy(conf *[]globalconfig.ModbusProxy) error {
 	return nil
 }
 
+func configureModbusServer(conf globalconfig.ModbusServer, site *core.Site, cache *util.ParamCache) error {
+	if conf.Port == 0 {
+		return nil
+	}
+
+	return modbus.StartSiteServer(conf.Port, site, cache, conf.Writable)
+}
+
+func configureGrpc(conf globalconfig.Grpc, site *core.Site, cache *util.ParamCache, auth auth.Auth, tee util.TeeAttacher) error {
+	if conf.Port == 0 {
+		return nil
+	}
+
+	srv := rpc.NewServer(site, cache, auth)
+	if err := srv.Listen(conf.Port); err != nil {
+		return err
+	}
+
+	go srv.Run(tee.Attach())
+
+	return nil
+}
+
+// setup remote access via relay
+func configureRemote(conf globalconfig.Remote, handler http.Handler, authObject auth.Auth) error {
+	if conf.Relay == "" {
+		return nil
+	}
+
+	// prevent remote users from claiming the instance
+	if authObject.GetAuthMode() == auth.Disabled || !authObject.IsAdminPasswordConfigured() && !authObject.HasUsers() {
+		return errors.New("remote access requires authentication, set admin password or create users first")
+	}
+
+	tunnel, err := remote.New(conf.Relay, conf.Token, server.RemoteAuthHandler(authObject)(handler))
+	if err != nil {
+		return err
+	}
+
+	go tunnel.Run(context.Background())
+
+	return nil
+}
+
+// setup ocpi charge point operator interface
+func configureOcpi(conf globalconfig.Ocpi, site *core.Site, url string, router *mux.Router) error {
+	if conf.Token == "" {
+		return nil
+	}
+
+	srv, err := ocpi.New(conf, site, url)
+	if err != nil {
+		return fmt.Errorf("ocpi: %w", err)
+	}
+
+	router.PathPrefix("/ocpi").Handler(srv.Handler())
+
+	return nil
+}
+
+func configureKiosk(conf globalconfig.Kiosk, cache *util.ParamCache, router *mux.Router) error {
+	if conf.Token == "" {
+		return nil
+	}
+
+	srv, err := kiosk.New(conf, cache)
+	if err != nil {
+		return fmt.Errorf("kiosk: %w", err)
+	}
+
+	router.PathPrefix("/kiosk").Handler(srv.Handler())
+
+	return nil
+}
+
 func configureSiteAndLoadpoints(conf *globalconfig.All) (*core.Site, error) {
 	// migrate settings
 	if settings.Exists(keys.Interval) {
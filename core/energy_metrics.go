@@ -104,3 +104,27 @@ func (em *EnergyMetrics) Publish(prefix string, p publisher) {
 	p.publish(prefix+"Price", em.Price())
 	p.publish(prefix+"Co2PerKWh", em.Co2PerKWh())
 }
+
+// energyMetricsState is the journaled state of EnergyMetrics
+type energyMetricsState struct {
+	TotalKWh float64  `json:"totalKWh"`
+	SolarKWh float64  `json:"solarKWh"`
+	Price    *float64 `json:"price,omitempty"`
+	Co2      *float64 `json:"co2,omitempty"`
+}
+
+func (em *EnergyMetrics) state() energyMetricsState {
+	return energyMetricsState{
+		TotalKWh: em.totalKWh,
+		SolarKWh: em.solarKWh,
+		Price:    em.price,
+		Co2:      em.co2,
+	}
+}
+
+func (em *EnergyMetrics) restore(s energyMetricsState) {
+	em.totalKWh = s.TotalKWh
+	em.solarKWh = s.SolarKWh
+	em.price = s.Price
+	em.co2 = s.Co2
+}
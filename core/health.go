@@ -7,9 +7,19 @@ import (
 
 // Health is a health checker that needs regular updates to stay healthy
 type Health struct {
-	mux     sync.Mutex
-	updated time.Time
-	timeout time.Duration
+	mux         sync.Mutex
+	updated     time.Time
+	timeout     time.Duration
+	duration    time.Duration // last update cycle
+	maxDuration time.Duration // slowest update cycle
+}
+
+// HealthStatus is the health checker's state
+type HealthStatus struct {
+	Healthy     bool      `json:"healthy"`
+	Updated     time.Time `json:"updated"`
+	Duration    string    `json:"duration"`
+	MaxDuration string    `json:"maxDuration"`
 }
 
 // NewHealth creates new health checker
@@ -40,3 +50,35 @@ func (health *Health) Update() {
 
 	health.updated = time.Now()
 }
+
+// Cycle records the duration of an update cycle
+func (health *Health) Cycle(d time.Duration) {
+	if health == nil {
+		return
+	}
+
+	health.mux.Lock()
+	defer health.mux.Unlock()
+
+	health.duration = d
+	health.maxDuration = max(health.maxDuration, d)
+}
+
+// Status returns the health state including update cycle durations
+func (health *Health) Status() HealthStatus {
+	if health == nil {
+		return HealthStatus{}
+	}
+
+	healthy := health.Healthy()
+
+	health.mux.Lock()
+	defer health.mux.Unlock()
+
+	return HealthStatus{
+		Healthy:     healthy,
+		Updated:     health.updated,
+		Duration:    health.duration.Round(time.Millisecond).String(),
+		MaxDuration: health.maxDuration.Round(time.Millisecond).String(),
+	}
+}
@@ -0,0 +1,23 @@
+package core
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestHealthStatus(t *testing.T) {
+	var nilHealth *Health
+	assert.False(t, nilHealth.Status().Healthy)
+
+	h := NewHealth(time.Minute)
+	h.Cycle(2 * time.Second)
+	h.Cycle(time.Second)
+	h.Update()
+
+	s := h.Status()
+	assert.True(t, s.Healthy)
+	assert.Equal(t, "1s", s.Duration)
+	assert.Equal(t, "2s", s.MaxDuration)
+}
@@ -3,4 +3,6 @@ package keys
 const (
 	AdminPassword = "adminPassword"
 	JwtSecret     = "jwtSecretKey"
+	Users         = "users"
+	ApiTokens     = "apiTokens"
 )
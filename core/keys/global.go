@@ -25,4 +25,16 @@ const (
 	DemoMode           = "demoMode"
 	AuthDisabled       = "authDisabled"
 	AuthProviders      = "authProviders"
+	Ocpi               = "ocpi" // ocpi registration
+
+	OcppSecurityEvents   = "ocppSecurityEvents"   // recent security events per charge point
+	OcppSecurityWarnings = "ocppSecurityWarnings" // unacknowledged critical security events
+	OcppFirmware         = "ocppFirmware"         // firmware update status per charge point
+	OcppDiagnostics      = "ocppDiagnostics"      // diagnostics upload status per charge point
+	OcppRejected         = "ocppRejected"         // id tags rejected by the authorization policy
+	OcppReset            = "ocppReset"            // reset status per charge point
+	OcppConnectivity     = "ocppConnectivity"     // connection state per charge point
+	OcppPending          = "ocppPending"          // unconfigured charge points offered for adoption
+	OcppCapabilities     = "ocppCapabilities"     // detected capability profile per charge point
+	OcppTransaction      = "ocppTransaction"      // latest transaction started or stopped at a charge point
 )
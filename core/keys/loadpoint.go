@@ -31,6 +31,8 @@ const (
 	ChargerSinglePhase  = "chargerSinglePhase"  // api.PhaseDescriber: charger physical phases, sockets only
 	ChargerPhases1p3p   = "chargerPhases1p3p"   // api.PhaseSwitcher: 1p3p chargers
 	ChargerStatusReason = "chargerStatusReason" // either awaiting authorization or disconnect required
+	ChargerUnlock       = "chargerUnlock"       // api.ConnectorUnlocker: charger name for unlocking the connector
+	ChargerFault        = "chargerFault"        // api.FaultDiagnosis: human-readable charger fault
 
 	// loadpoint status
 	Enabled   = "enabled"   // loadpoint enabled
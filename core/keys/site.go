@@ -5,6 +5,7 @@ const (
 	AuxPower              = "auxPower"
 	Circuits              = "circuits"
 	Currency              = "currency"
+	Estimated             = "estimated"
 	Ext                   = "ext"
 	GreenShareHome        = "greenShareHome"
 	GreenShareLoadpoints  = "greenShareLoadpoints"
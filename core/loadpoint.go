@@ -41,6 +41,10 @@ const (
 	evVehicleSoc          = "soc"        // vehicle soc progress
 	evVehicleUnidentified = "guest"      // vehicle unidentified
 	evVehicleAsleep       = "asleep"     // vehicle doesn't charge
+	evChargerFault        = "fault"      // charger status unavailable
+	evPlanCreated         = "plan"       // charging plan created
+	evSmartCostActive     = "smartcost"  // price below smart cost limit
+	evMeterOutage         = "meter"      // grid meter unavailable
 
 	pvTimer   = "pv"
 	pvEnable  = "enable"
@@ -73,6 +77,12 @@ const pollInterval = 60 * time.Minute
 // Task is the task type
 type Task = func()
 
+// busHandler is an event handler subscribed to the loadpoint's event bus
+type busHandler struct {
+	topic string
+	fn    any
+}
+
 // Loadpoint is responsible for controlling charge depending on
 // Soc needs and power availability.
 type Loadpoint struct {
@@ -130,13 +140,18 @@ type Loadpoint struct {
 	phasesSwitched      time.Time // Phase switch timestamp
 	vehicleDetectTicker *clock.Ticker
 	vehicleIdentifier   string
+	chargerFault        bool   // charger status error has been notified
+	chargerFaultText    string // charger fault description has been logged
+	smartCostActive     bool   // price below smart cost limit has been notified
 
 	charger          api.Charger
 	chargeTimer      api.ChargeTimer
 	chargeRater      api.ChargeRater
-	chargedAtStartup float64 // session energy at startup
+	chargedAtStartup float64      // session energy at startup
+	chargerHandlers  []busHandler // charger wrapper event handlers
 
 	circuit        api.Circuit // Circuit
+	powerLimit     float64     // Runtime power limit set by energy manager
 	chargeMeter    api.Meter   // Charger usage meter
 	vehicle        api.Vehicle // Currently active vehicle
 	defaultVehicle api.Vehicle // Default vehicle (disables detection)
@@ -152,6 +167,14 @@ type Loadpoint struct {
 	planSlotEnd      time.Time     // current plan slot end time
 	planActive       bool          // charge plan exists and has a currently active slot
 
+	// connector reservation ahead of plan time
+	reservationID     string
+	reservationExpiry time.Time
+
+	// plan slots followed autonomously by the charger
+	chargingPlanSlots   api.Rates
+	chargingPlanCurrent float64
+
 	// cached state
 	status         api.ChargeStatus // Charger status
 	chargePower    float64          // Charging power
@@ -171,8 +194,10 @@ type Loadpoint struct {
 	progress                *Progress     // Step-wise progress indicator
 
 	// session log
-	db      *session.DB
-	session *session.Session
+	db             *session.DB
+	session        *session.Session
+	lastSession    *session.Session // cleared session, reconciled with late charger meter readings
+	sessionMetered bool             // session energy reported by the charger, kept when stopping the session
 
 	settings settings.Settings
 
@@ -395,8 +420,8 @@ func (lp *Loadpoint) configureChargerType(charger api.Charger) {
 			lp.chargeMeter = mt
 		} else {
 			mt := new(wrapper.ChargeMeter)
-			_ = lp.bus.Subscribe(evChargeCurrent, lp.evChargeCurrentWrappedMeterHandler)
-			_ = lp.bus.Subscribe(evChargeStop, func() { mt.SetPower(0) })
+			lp.subscribeCharger(evChargeCurrent, lp.evChargeCurrentWrappedMeterHandler)
+			lp.subscribeCharger(evChargeStop, func() { mt.SetPower(0) })
 			lp.chargeMeter = mt
 		}
 	}
@@ -412,11 +437,17 @@ func (lp *Loadpoint) configureChargerType(charger api.Charger) {
 			lp.chargedAtStartup = f
 		}
 	} else {
-		rt := wrapper.NewChargeRater(lp.log, lp.chargeMeter)
-		_ = lp.bus.Subscribe(evChargePower, rt.SetChargePower)
-		_ = lp.bus.Subscribe(evVehicleConnect, func() { rt.StartCharge(false) })
-		_ = lp.bus.Subscribe(evChargeStart, func() { rt.StartCharge(true) })
-		_ = lp.bus.Subscribe(evChargeStop, rt.StopCharge)
+		// replaced chargers continue the session's charge rater
+		rt, ok := lp.chargeRater.(*wrapper.ChargeRater)
+		if ok {
+			rt.SetMeter(lp.chargeMeter)
+		} else {
+			rt = wrapper.NewChargeRater(lp.log, lp.chargeMeter)
+		}
+		lp.subscribeCharger(evChargePower, rt.SetChargePower)
+		lp.subscribeCharger(evVehicleConnect, func() { rt.StartCharge(false) })
+		lp.subscribeCharger(evChargeStart, func() { rt.StartCharge(true) })
+		lp.subscribeCharger(evChargeStop, rt.StopCharge)
 		lp.chargeRater = rt
 	}
 
@@ -424,10 +455,13 @@ func (lp *Loadpoint) configureChargerType(charger api.Charger) {
 	if ct, ok := charger.(api.ChargeTimer); ok {
 		lp.chargeTimer = ct
 	} else {
-		ct := wrapper.NewChargeTimer()
-		_ = lp.bus.Subscribe(evVehicleConnect, func() { ct.StartCharge(false) })
-		_ = lp.bus.Subscribe(evChargeStart, func() { ct.StartCharge(true) })
-		_ = lp.bus.Subscribe(evChargeStop, ct.StopCharge)
+		ct, ok := lp.chargeTimer.(*wrapper.ChargeTimer)
+		if !ok {
+			ct = wrapper.NewChargeTimer()
+		}
+		lp.subscribeCharger(evVehicleConnect, func() { ct.StartCharge(false) })
+		lp.subscribeCharger(evChargeStart, func() { ct.StartCharge(true) })
+		lp.subscribeCharger(evChargeStop, ct.StopCharge)
 		lp.chargeTimer = ct
 	}
 
@@ -435,11 +469,39 @@ func (lp *Loadpoint) configureChargerType(charger api.Charger) {
 	lp.wakeUpTimer = NewTimer()
 }
 
+// subscribeCharger subscribes a charger wrapper handler that is removed when the charger is replaced
+func (lp *Loadpoint) subscribeCharger(topic string, fn any) {
+	_ = lp.bus.Subscribe(topic, fn)
+	lp.chargerHandlers = append(lp.chargerHandlers, busHandler{topic: topic, fn: fn})
+}
+
+// unsubscribeCharger removes the charger wrapper handlers.
+// The bus identifies handlers by function only, hence all handlers must be removed before subscribing replacements.
+func (lp *Loadpoint) unsubscribeCharger() {
+	for _, h := range lp.chargerHandlers {
+		_ = lp.bus.Unsubscribe(h.topic, h.fn)
+	}
+	lp.chargerHandlers = nil
+}
+
 // pushEvent sends push messages to clients
 func (lp *Loadpoint) pushEvent(event string) {
+	// loadpoint not yet prepared
+	if lp.pushChan == nil {
+		return
+	}
 	lp.pushChan <- push.Event{Event: event}
 }
 
+// pushEventError sends push messages including the error details to clients
+func (lp *Loadpoint) pushEventError(event string, err error) {
+	// loadpoint not yet prepared
+	if lp.pushChan == nil {
+		return
+	}
+	lp.pushChan <- push.Event{Event: event, Error: err.Error()}
+}
+
 // publish sends values to UI and databases
 func (lp *Loadpoint) publish(key string, val any) {
 	// test helper
@@ -670,6 +732,13 @@ func (lp *Loadpoint) Prepare(site site.API, uiChan chan<- util.Param, pushChan c
 		lp.publish(keys.ChargerIcon, nil)
 	}
 
+	// connector unlocking
+	if _, ok := lp.charger.(api.ConnectorUnlocker); ok {
+		lp.publish(keys.ChargerUnlock, lp.ChargerRef)
+	} else {
+		lp.publish(keys.ChargerUnlock, nil)
+	}
+
 	// vehicle
 	lp.unpublishVehicleIdentity()
 	lp.unpublishVehicle()
@@ -882,6 +951,11 @@ func (lp *Loadpoint) setLimit(current float64) error {
 		current = lp.roundedCurrent(min(currentLimit, currentLimitViaPower))
 	}
 
+	// apply runtime power limit
+	if powerLimit := lp.GetPowerLimit(); powerLimit > 0 {
+		current = lp.roundedCurrent(min(current, powerToCurrent(powerLimit, lp.ActivePhases())))
+	}
+
 	// https://github.com/evcc-io/evcc/issues/16309
 	effMinCurrent := lp.effectiveMinCurrent()
 	if effMaxCurrent := lp.effectiveMaxCurrent(); effMinCurrent > effMaxCurrent {
@@ -1148,6 +1222,31 @@ func (lp *Loadpoint) getStatusChanges() ([]api.ChargeStatus, error) {
 	return res, nil
 }
 
+// publishChargerFault publishes the charger's fault description and logs its changes
+func (lp *Loadpoint) publishChargerFault() {
+	fd, ok := lp.charger.(api.FaultDiagnosis)
+	if !ok {
+		return
+	}
+
+	fault, err := fd.Fault()
+	if err != nil {
+		lp.log.ERROR.Printf("charger fault: %v", err)
+		return
+	}
+
+	if fault != lp.chargerFaultText {
+		if fault != "" {
+			lp.log.WARN.Printf("charger fault: %s", fault)
+		} else {
+			lp.log.INFO.Println("charger fault cleared")
+		}
+		lp.chargerFaultText = fault
+	}
+
+	lp.publish(keys.ChargerFault, fault)
+}
+
 // needsWelcomeCharge checks if either the charger or a vehicle requires a welcome charge
 func (lp *Loadpoint) needsWelcomeCharge() bool {
 	if lp.chargerHasFeature(api.WelcomeCharge) || hasFeature(lp.defaultVehicle, api.WelcomeCharge) {
@@ -1687,6 +1786,10 @@ func (lp *Loadpoint) publishChargeProgress() {
 			if telemetry.Enabled() && added > 0 {
 				telemetry.UpdateEnergy(added, addedGreen)
 			}
+
+			if added > 0 {
+				lp.journalSession()
+			}
 		}
 	} else {
 		lp.log.ERROR.Printf("charge rater: %v", err)
@@ -1861,6 +1964,10 @@ func (lp *Loadpoint) phaseSwitchCompleted() bool {
 func (lp *Loadpoint) Update(sitePower, batteryBoostPower float64, consumption, feedin api.Rates, batteryBuffered, batteryStart bool, greenShare float64, effPrice, effCo2 *float64) {
 	// smart cost
 	smartCostActive, smartCostNextStart := lp.checkSmartLimit(lp.GetSmartCostLimit(), consumption, true)
+	if smartCostActive && !lp.smartCostActive {
+		lp.pushEvent(evSmartCostActive)
+	}
+	lp.smartCostActive = smartCostActive
 	lp.publish(keys.SmartCostActive, smartCostActive)
 	lp.publish(keys.SmartCostNextStart, smartCostNextStart)
 
@@ -1912,11 +2019,21 @@ func (lp *Loadpoint) Update(sitePower, batteryBoostPower float64, consumption, f
 
 	// read and publish status
 	welcomeCharge, err := lp.updateChargerStatus()
+	lp.publishChargerFault()
 	if err != nil {
 		lp.log.ERROR.Println(err)
+
+		// notify once per fault
+		if !lp.chargerFault {
+			lp.chargerFault = true
+			lp.pushEventError(evChargerFault, err)
+		}
+
 		return
 	}
 
+	lp.chargerFault = false
+
 	lp.publish(keys.VehicleWelcomeActive, welcomeCharge)
 	lp.publish(keys.Connected, lp.connected())
 	lp.publish(keys.Charging, lp.charging())
@@ -1958,6 +2075,9 @@ func (lp *Loadpoint) Update(sitePower, batteryBoostPower float64, consumption, f
 	// update and publish plan without being short-circuited by modes etc.
 	plannerActive := lp.plannerActive()
 
+	// reserve charger for the planned vehicle
+	lp.updateReservation()
+
 	// execute loading strategy
 	switch {
 	case !lp.connected():
@@ -187,6 +187,14 @@ type API interface {
 	GetChargePowerFlexibility(rates api.Rates) float64
 	// GetMaxPhaseCurrent returns max phase current
 	GetMaxPhaseCurrent() float64
+	// GetPowerLimit returns the runtime charge power limit
+	GetPowerLimit() float64
+	// SetPowerLimit limits the charge power at runtime without persisting it, zero removes the limit
+	SetPowerLimit(power float64)
+	// SetSessionMeter sets the charger's meter readings of the charging session in kWh
+	SetSessionMeter(meterStart, meterStop float64)
+	// RecordSession stores a finished charging session not observed by the loadpoint, meter readings in kWh
+	RecordSession(created, finished time.Time, identifier string, meterStart, meterStop float64)
 
 	//
 	// charge progress
@@ -506,6 +506,20 @@ func (mr *MockAPIMockRecorder) GetPlanRequiredDuration(goal, maxPower any) *gomo
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetPlanRequiredDuration", reflect.TypeOf((*MockAPI)(nil).GetPlanRequiredDuration), goal, maxPower)
 }
 
+// GetPowerLimit mocks base method.
+func (m *MockAPI) GetPowerLimit() float64 {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetPowerLimit")
+	ret0, _ := ret[0].(float64)
+	return ret0
+}
+
+// GetPowerLimit indicates an expected call of GetPowerLimit.
+func (mr *MockAPIMockRecorder) GetPowerLimit() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetPowerLimit", reflect.TypeOf((*MockAPI)(nil).GetPowerLimit))
+}
+
 // GetPriority mocks base method.
 func (m *MockAPI) GetPriority() int {
 	m.ctrl.T.Helper()
@@ -700,6 +714,18 @@ func (mr *MockAPIMockRecorder) PublishEffectiveValues() *gomock.Call {
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "PublishEffectiveValues", reflect.TypeOf((*MockAPI)(nil).PublishEffectiveValues))
 }
 
+// RecordSession mocks base method.
+func (m *MockAPI) RecordSession(created, finished time.Time, identifier string, meterStart, meterStop float64) {
+	m.ctrl.T.Helper()
+	m.ctrl.Call(m, "RecordSession", created, finished, identifier, meterStart, meterStop)
+}
+
+// RecordSession indicates an expected call of RecordSession.
+func (mr *MockAPIMockRecorder) RecordSession(created, finished, identifier, meterStart, meterStop any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RecordSession", reflect.TypeOf((*MockAPI)(nil).RecordSession), created, finished, identifier, meterStart, meterStop)
+}
+
 // SetBatteryBoost mocks base method.
 func (m *MockAPI) SetBatteryBoost(enable bool) error {
 	m.ctrl.T.Helper()
@@ -914,6 +940,18 @@ func (mr *MockAPIMockRecorder) SetPlanEnergy(arg0, arg1, arg2 any) *gomock.Call
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SetPlanEnergy", reflect.TypeOf((*MockAPI)(nil).SetPlanEnergy), arg0, arg1, arg2)
 }
 
+// SetPowerLimit mocks base method.
+func (m *MockAPI) SetPowerLimit(power float64) {
+	m.ctrl.T.Helper()
+	m.ctrl.Call(m, "SetPowerLimit", power)
+}
+
+// SetPowerLimit indicates an expected call of SetPowerLimit.
+func (mr *MockAPIMockRecorder) SetPowerLimit(power any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SetPowerLimit", reflect.TypeOf((*MockAPI)(nil).SetPowerLimit), power)
+}
+
 // SetPriority mocks base method.
 func (m *MockAPI) SetPriority(arg0 int) {
 	m.ctrl.T.Helper()
@@ -926,6 +964,18 @@ func (mr *MockAPIMockRecorder) SetPriority(arg0 any) *gomock.Call {
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SetPriority", reflect.TypeOf((*MockAPI)(nil).SetPriority), arg0)
 }
 
+// SetSessionMeter mocks base method.
+func (m *MockAPI) SetSessionMeter(meterStart, meterStop float64) {
+	m.ctrl.T.Helper()
+	m.ctrl.Call(m, "SetSessionMeter", meterStart, meterStop)
+}
+
+// SetSessionMeter indicates an expected call of SetSessionMeter.
+func (mr *MockAPIMockRecorder) SetSessionMeter(meterStart, meterStop any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SetSessionMeter", reflect.TypeOf((*MockAPI)(nil).SetSessionMeter), meterStart, meterStop)
+}
+
 // SetSmartCostLimit mocks base method.
 func (m *MockAPI) SetSmartCostLimit(limit *float64) {
 	m.ctrl.T.Helper()
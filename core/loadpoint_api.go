@@ -381,11 +381,23 @@ func (lp *Loadpoint) setPlanEnergy(finishAt time.Time, precondition time.Duratio
 
 // SetPlanEnergy sets plan target energy
 func (lp *Loadpoint) SetPlanEnergy(finishAt time.Time, precondition time.Duration, energy float64) error {
+	created, err := lp.updatePlanEnergy(finishAt, precondition, energy)
+
+	// push outside the loadpoint lock
+	if created {
+		lp.pushEvent(evPlanCreated)
+	}
+
+	return err
+}
+
+// updatePlanEnergy applies the plan energy and returns true if a plan has been created
+func (lp *Loadpoint) updatePlanEnergy(finishAt time.Time, precondition time.Duration, energy float64) (bool, error) {
 	lp.Lock()
 	defer lp.Unlock()
 
 	if !finishAt.IsZero() && finishAt.Before(lp.clock.Now()) {
-		return errors.New("timestamp is in the past")
+		return false, errors.New("timestamp is in the past")
 	}
 
 	lp.log.DEBUG.Printf("set plan energy: %.3gkWh @ %v", energy, finishAt.Round(time.Second).Local())
@@ -394,9 +406,11 @@ func (lp *Loadpoint) SetPlanEnergy(finishAt time.Time, precondition time.Duratio
 	if lp.planEnergy != energy || lp.planPrecondition != precondition || !lp.planTime.Equal(finishAt) {
 		lp.setPlanEnergy(finishAt, precondition, energy)
 		lp.requestUpdate()
+
+		return energy > 0, nil
 	}
 
-	return nil
+	return false, nil
 }
 
 // GetSoc returns the PV mode threshold settings
@@ -826,6 +840,25 @@ func (lp *Loadpoint) SetSmartFeedInPriorityLimit(val *float64) {
 	}
 }
 
+// GetPowerLimit returns the runtime power limit
+func (lp *Loadpoint) GetPowerLimit() float64 {
+	lp.RLock()
+	defer lp.RUnlock()
+	return lp.powerLimit
+}
+
+// SetPowerLimit sets the runtime power limit
+func (lp *Loadpoint) SetPowerLimit(power float64) {
+	lp.Lock()
+	defer lp.Unlock()
+
+	if lp.powerLimit != power {
+		lp.log.DEBUG.Printf("set power limit: %.0fW", power)
+		lp.powerLimit = power
+		lp.requestUpdate()
+	}
+}
+
 // GetCircuit returns the assigned circuit
 func (lp *Loadpoint) GetCircuit() api.Circuit {
 	lp.RLock()
@@ -0,0 +1,72 @@
+package core
+
+import (
+	"slices"
+	"time"
+
+	"github.com/evcc-io/evcc/api"
+)
+
+// chargingPlanSlots returns the plan's remaining slots sorted by start with adjacent slots merged
+func chargingPlanSlots(plan api.Rates, now time.Time) api.Rates {
+	var res api.Rates
+
+	sorted := slices.Clone(plan)
+	sorted.Sort()
+
+	for _, slot := range sorted {
+		if !slot.End.After(now) {
+			continue
+		}
+
+		if n := len(res); n > 0 && !slot.Start.After(res[n-1].End) {
+			if slot.End.After(res[n-1].End) {
+				res[n-1].End = slot.End
+			}
+			continue
+		}
+
+		res = append(res, api.Rate{Start: slot.Start, End: slot.End})
+	}
+
+	return res
+}
+
+// sameChargingPlanSlots compares the slots ignoring the start of already started slots
+func sameChargingPlanSlots(a, b api.Rates, now time.Time) bool {
+	return slices.EqualFunc(a, b, func(a, b api.Rate) bool {
+		started := !a.Start.After(now) && !b.Start.After(now)
+		return a.End.Equal(b.End) && (started || a.Start.Equal(b.Start))
+	})
+}
+
+// updateChargingPlan hands the plan's slots to chargers following the plan autonomously,
+// e.g. while the connection to evcc is interrupted. An empty plan removes the slots.
+func (lp *Loadpoint) updateChargingPlan(plan api.Rates) {
+	cp, ok := lp.charger.(api.ChargingPlanner)
+	if !ok {
+		return
+	}
+
+	now := lp.clock.Now()
+	slots := chargingPlanSlots(plan, now)
+
+	var current float64
+	if len(slots) > 0 {
+		current = lp.effectiveMaxCurrent()
+	}
+
+	if current == lp.chargingPlanCurrent && sameChargingPlanSlots(slots, lp.chargingPlanSlots, now) {
+		return
+	}
+
+	// remember failed attempts to avoid retrying every cycle
+	lp.chargingPlanSlots, lp.chargingPlanCurrent = slots, current
+
+	if err := cp.SetChargingPlan(slots, current); err != nil {
+		lp.log.WARN.Printf("charging plan: %v", err)
+		return
+	}
+
+	lp.log.DEBUG.Printf("charging plan: %d slots at %.3gA", len(slots), current)
+}
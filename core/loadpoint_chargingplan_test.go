@@ -0,0 +1,67 @@
+package core
+
+import (
+	"testing"
+	"time"
+
+	"github.com/benbjohnson/clock"
+	"github.com/evcc-io/evcc/api"
+	"github.com/evcc-io/evcc/util"
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/mock/gomock"
+)
+
+func TestChargingPlanSlots(t *testing.T) {
+	now := time.Now()
+	at := func(h int) time.Time { return now.Add(time.Duration(h) * time.Hour) }
+
+	plan := api.Rates{
+		{Start: at(3), End: at(4), Value: 1},
+		{Start: at(-2), End: at(-1)}, // ended
+		{Start: at(1), End: at(2)},
+		{Start: at(2), End: at(3)}, // adjacent
+		{Start: at(5), End: at(6)},
+	}
+
+	assert.Equal(t, api.Rates{
+		{Start: at(1), End: at(4)},
+		{Start: at(5), End: at(6)},
+	}, chargingPlanSlots(plan, now))
+
+	// started slots may move their start
+	assert.True(t, sameChargingPlanSlots(api.Rates{{Start: at(-1), End: at(1)}}, api.Rates{{Start: now, End: at(1)}}, now))
+	assert.False(t, sameChargingPlanSlots(api.Rates{{Start: at(1), End: at(2)}}, api.Rates{{Start: at(0), End: at(2)}}, now.Add(-time.Minute)))
+}
+
+func TestUpdateChargingPlan(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	clock := clock.NewMock()
+
+	charger := api.NewMockCharger(ctrl)
+	planner := api.NewMockChargingPlanner(ctrl)
+
+	lp := NewLoadpoint(util.NewLogger("foo"), nil)
+	lp.clock = clock
+	lp.charger = struct {
+		api.Charger
+		api.ChargingPlanner
+	}{
+		Charger:         charger,
+		ChargingPlanner: planner,
+	}
+	lp.maxCurrent = 16
+
+	// no plan
+	lp.updateChargingPlan(nil)
+
+	// plan is sent once
+	plan := api.Rates{{Start: clock.Now().Add(time.Hour), End: clock.Now().Add(2 * time.Hour)}}
+
+	planner.EXPECT().SetChargingPlan(plan, 16.0).Return(nil)
+	lp.updateChargingPlan(plan)
+	lp.updateChargingPlan(plan)
+
+	// removed plan clears the slots
+	planner.EXPECT().SetChargingPlan(nil, 0.0).Return(nil)
+	lp.updateChargingPlan(nil)
+}
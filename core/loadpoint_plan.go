@@ -113,6 +113,8 @@ func (lp *Loadpoint) plannerActive() (active bool) {
 		lp.publish(keys.PlanProjectedStart, planStart)
 		lp.publish(keys.PlanProjectedEnd, planEnd)
 		lp.publish(keys.PlanOverrun, planOverrun)
+
+		lp.updateChargingPlan(plan)
 	}()
 
 	// re-check since plannerActive() is called before connected() check in Update()
@@ -0,0 +1,70 @@
+package core
+
+import (
+	"strings"
+	"time"
+
+	"github.com/evcc-io/evcc/api"
+)
+
+// reservationIdentifier returns the vehicle's first identifier usable for reservations
+func reservationIdentifier(v api.Vehicle) string {
+	for _, id := range v.Identifiers() {
+		if id != "" && !strings.Contains(id, "*") {
+			return id
+		}
+	}
+	return ""
+}
+
+// updateReservation reserves the charger for the vehicle until plan time while no vehicle is connected.
+// Reservations are used up by the vehicle connecting and cancelled when the plan is removed.
+func (lp *Loadpoint) updateReservation() {
+	rs, ok := lp.charger.(api.Reserver)
+	if !ok {
+		return
+	}
+
+	var id string
+	var expiry time.Time
+
+	if !lp.connected() {
+		if v := lp.GetVehicle(); v != nil {
+			id = reservationIdentifier(v)
+		}
+
+		if planTime := lp.EffectivePlanTime(); id != "" && lp.clock.Until(planTime) > 0 {
+			expiry = planTime
+		} else {
+			id = ""
+		}
+	}
+
+	if id == lp.reservationID && expiry.Equal(lp.reservationExpiry) {
+		return
+	}
+
+	// reservation no longer required
+	if id == "" {
+		if lp.clock.Until(lp.reservationExpiry) > 0 && !lp.connected() {
+			if err := rs.CancelReservation(); err != nil {
+				lp.log.WARN.Printf("cancel reservation: %v", err)
+			} else {
+				lp.log.DEBUG.Println("reservation: cancelled")
+			}
+		}
+
+		lp.reservationID, lp.reservationExpiry = "", time.Time{}
+		return
+	}
+
+	// remember failed attempts to avoid retrying every cycle
+	lp.reservationID, lp.reservationExpiry = id, expiry
+
+	if err := rs.Reserve(expiry, id); err != nil {
+		lp.log.WARN.Printf("reservation: %v", err)
+		return
+	}
+
+	lp.log.DEBUG.Printf("reservation: %s until %v", id, expiry.Round(time.Second).Local())
+}
@@ -0,0 +1,64 @@
+package core
+
+import (
+	"testing"
+	"time"
+
+	"github.com/benbjohnson/clock"
+	"github.com/evcc-io/evcc/api"
+	"github.com/evcc-io/evcc/util"
+	"go.uber.org/mock/gomock"
+)
+
+func TestUpdateReservation(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	clock := clock.NewMock()
+
+	charger := api.NewMockCharger(ctrl)
+	reserver := api.NewMockReserver(ctrl)
+
+	vehicle := api.NewMockVehicle(ctrl)
+	vehicle.EXPECT().Identifiers().Return([]string{"placeholder*", "12345"}).AnyTimes()
+	vehicle.EXPECT().Capacity().Return(0.0).AnyTimes()
+
+	lp := NewLoadpoint(util.NewLogger("foo"), nil)
+	lp.clock = clock
+	lp.charger = struct {
+		api.Charger
+		api.Reserver
+	}{
+		Charger:  charger,
+		Reserver: reserver,
+	}
+	lp.vehicle = vehicle
+	lp.status = api.StatusA
+
+	// no plan
+	lp.updateReservation()
+
+	// plan reserves charger until plan time, once
+	planTime := clock.Now().Add(8 * time.Hour)
+	lp.planTime, lp.planEnergy = planTime, 10
+
+	reserver.EXPECT().Reserve(planTime, "12345").Return(nil)
+	lp.updateReservation()
+	lp.updateReservation()
+
+	// vehicle connecting uses up the reservation
+	lp.status = api.StatusB
+	lp.updateReservation()
+
+	// plan removed after disconnect cancels nothing
+	lp.status = api.StatusA
+	lp.planTime, lp.planEnergy = time.Time{}, 0
+	lp.updateReservation()
+
+	// plan removed before connecting cancels the reservation
+	lp.planTime, lp.planEnergy = planTime, 10
+	reserver.EXPECT().Reserve(planTime, "12345").Return(nil)
+	lp.updateReservation()
+
+	lp.planTime, lp.planEnergy = time.Time{}, 0
+	reserver.EXPECT().CancelReservation().Return(nil)
+	lp.updateReservation()
+}
@@ -0,0 +1,120 @@
+package core
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/evcc-io/evcc/api"
+	"github.com/evcc-io/evcc/core/loadpoint"
+	"github.com/evcc-io/evcc/core/wrapper"
+	"github.com/evcc-io/evcc/util/config"
+)
+
+// rewire re-resolves the loadpoint's device references after configuration changes.
+// Devices are only replaced if all references are valid.
+func (lp *Loadpoint) rewire() error {
+	lp.Lock()
+	defer lp.Unlock()
+
+	if lp.ChargerRef == "" {
+		return errors.New("missing charger")
+	}
+
+	dev, err := config.Chargers().ByName(lp.ChargerRef)
+	if err != nil {
+		return fmt.Errorf("charger: %w", err)
+	}
+	charger := dev.Instance()
+	if charger == nil {
+		return errors.New("missing charger instance")
+	}
+
+	var meter api.Meter
+	if lp.MeterRef != "" {
+		dev, err := config.Meters().ByName(lp.MeterRef)
+		if err != nil {
+			return fmt.Errorf("meter: %w", err)
+		}
+		if meter = dev.Instance(); meter == nil {
+			return errors.New("missing charge meter instance")
+		}
+	}
+
+	var vehicle api.Vehicle
+	if lp.VehicleRef != "" {
+		dev, err := config.Vehicles().ByName(lp.VehicleRef)
+		if err != nil {
+			return fmt.Errorf("default vehicle: %w", err)
+		}
+		if vehicle = dev.Instance(); vehicle == nil {
+			return errors.New("missing default vehicle instance")
+		}
+	}
+
+	if lp.chargerChanged(charger, meter) {
+		lp.log.DEBUG.Println("charger changed")
+		lp.replaceCharger(charger, meter)
+	}
+
+	if vehicle != lp.defaultVehicle {
+		lp.log.DEBUG.Println("default vehicle changed")
+		lp.defaultVehicle = vehicle
+	}
+
+	return nil
+}
+
+// chargerChanged returns true if charger or charge meter differ from the loadpoint's devices
+func (lp *Loadpoint) chargerChanged(charger api.Charger, meter api.Meter) bool {
+	if charger != lp.charger {
+		return true
+	}
+
+	if meter != nil {
+		return meter != lp.chargeMeter
+	}
+
+	// without external meter the charge meter is either integrated or the dummy meter
+	if mt, ok := charger.(api.Meter); ok {
+		return mt != lp.chargeMeter
+	}
+
+	_, dummy := lp.chargeMeter.(*wrapper.ChargeMeter)
+	return !dummy
+}
+
+// replaceCharger sets up charger and charge meter the same way as on startup.
+// The active session continues with the energy charged so far.
+func (lp *Loadpoint) replaceCharger(charger api.Charger, meter api.Meter) {
+	changed := charger != lp.charger
+	charging := lp.status == api.StatusC // loadpoint is locked
+	rater, timer := lp.chargeRater, lp.chargeTimer
+
+	lp.unsubscribeCharger()
+
+	lp.charger = charger
+	lp.chargeMeter = meter
+	lp.configureChargerType(charger)
+
+	// offset a different charge rater by the session's energy like a resumed session
+	if lp.chargeRater != rater {
+		lp.chargedAtStartup = 0
+		if f, err := lp.chargeRater.ChargedEnergy(); err == nil {
+			lp.chargedAtStartup = f
+		}
+		lp.chargedAtStartup -= lp.energyMetrics.TotalWh() / 1e3
+
+		if rt, ok := lp.chargeRater.(*wrapper.ChargeRater); ok && charging {
+			rt.StartCharge(true)
+		}
+	}
+
+	if ct, ok := lp.chargeTimer.(*wrapper.ChargeTimer); ok && ct != timer && charging {
+		ct.StartCharge(true)
+	}
+
+	// allow replaced charger to access loadpoint
+	if ctrl, ok := charger.(loadpoint.Controller); ok && changed {
+		ctrl.LoadpointControl(lp)
+	}
+}
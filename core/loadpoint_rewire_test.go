@@ -0,0 +1,102 @@
+package core
+
+import (
+	"testing"
+
+	"github.com/evcc-io/evcc/api"
+	"github.com/evcc-io/evcc/core/wrapper"
+	"github.com/evcc-io/evcc/util"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
+)
+
+func TestRewireChargerChanged(t *testing.T) {
+	ctrl := gomock.NewController(t)
+
+	type chargerWithMeter struct {
+		api.Charger
+		api.Meter
+	}
+
+	charger := api.NewMockCharger(ctrl)
+	integrated := &chargerWithMeter{Charger: api.NewMockCharger(ctrl), Meter: api.NewMockMeter(ctrl)}
+	meter := api.NewMockMeter(ctrl)
+
+	// dummy meter
+	lp := &Loadpoint{charger: charger, chargeMeter: new(wrapper.ChargeMeter)}
+	assert.False(t, lp.chargerChanged(charger, nil))
+	assert.True(t, lp.chargerChanged(charger, meter))
+	assert.True(t, lp.chargerChanged(integrated, nil))
+
+	// external meter
+	lp = &Loadpoint{charger: charger, chargeMeter: meter}
+	assert.False(t, lp.chargerChanged(charger, meter))
+	assert.True(t, lp.chargerChanged(charger, nil))
+
+	// integrated meter
+	lp = &Loadpoint{charger: integrated, chargeMeter: integrated}
+	assert.False(t, lp.chargerChanged(integrated, nil))
+	assert.True(t, lp.chargerChanged(integrated, meter))
+}
+
+func TestRewireReplaceCharger(t *testing.T) {
+	ctrl := gomock.NewController(t)
+
+	type chargerWithMeter struct {
+		api.Charger
+		api.Meter
+	}
+
+	lp := NewLoadpoint(util.NewLogger("foo"), nil)
+	lp.charger = api.NewMockCharger(ctrl)
+	lp.configureChargerType(lp.charger)
+
+	dummy, ok := lp.chargeMeter.(*wrapper.ChargeMeter)
+	require.True(t, ok)
+	dummy.SetPower(1000)
+
+	// integrated meter replaces dummy meter and its handlers
+	integrated := &chargerWithMeter{Charger: api.NewMockCharger(ctrl), Meter: api.NewMockMeter(ctrl)}
+	lp.replaceCharger(integrated, nil)
+	assert.Equal(t, integrated, lp.chargeMeter)
+
+	lp.bus.Publish(evChargeStop)
+	f, err := dummy.CurrentPower()
+	require.NoError(t, err)
+	assert.Equal(t, 1000.0, f)
+
+	// dummy meter is subscribed again
+	lp.replaceCharger(api.NewMockCharger(ctrl), nil)
+	dummy, ok = lp.chargeMeter.(*wrapper.ChargeMeter)
+	require.True(t, ok)
+	dummy.SetPower(1000)
+
+	lp.bus.Publish(evChargeStop)
+	f, err = dummy.CurrentPower()
+	require.NoError(t, err)
+	assert.Equal(t, 0.0, f)
+
+	// charge rater and timer handlers
+	assert.Len(t, lp.chargerHandlers, 9)
+
+	// session continues with the charge rater and timer
+	rater, timer := lp.chargeRater, lp.chargeTimer
+	lp.replaceCharger(api.NewMockCharger(ctrl), nil)
+	assert.Same(t, rater, lp.chargeRater)
+	assert.Same(t, timer, lp.chargeTimer)
+
+	// charger's charge rater is offset by the session's energy
+	type chargerWithRater struct {
+		api.Charger
+		api.ChargeRater
+	}
+
+	lp.energyMetrics.Update(5)
+
+	cr := api.NewMockChargeRater(ctrl)
+	cr.EXPECT().ChargedEnergy().Return(2.0, nil).Times(2)
+
+	lp.replaceCharger(&chargerWithRater{Charger: api.NewMockCharger(ctrl), ChargeRater: cr}, nil)
+	assert.Equal(t, -3.0, lp.chargedAtStartup)
+}
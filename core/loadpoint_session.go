@@ -1,10 +1,14 @@
 package core
 
 import (
+	"math"
+	"time"
+
 	"github.com/evcc-io/evcc/api"
 	"github.com/evcc-io/evcc/core/keys"
 	"github.com/evcc-io/evcc/core/session"
 	"github.com/evcc-io/evcc/core/wrapper"
+	"github.com/evcc-io/evcc/server/db/journal"
 	"github.com/jinzhu/now"
 	"github.com/samber/lo"
 )
@@ -35,6 +39,11 @@ func (lp *Loadpoint) createSession() {
 	}
 
 	lp.session = lp.db.New(lp.chargeMeterTotal())
+	lp.sessionMetered = false
+
+	if lp.resumeSession() {
+		return
+	}
 
 	if v := lp.GetVehicle(); v != nil {
 		lp.session.Vehicle = v.GetTitle()
@@ -69,23 +78,33 @@ func (lp *Loadpoint) stopSession() {
 	}
 
 	s.Finished = lp.clock.Now()
-	if meterStop := lp.chargeMeterTotal(); meterStop > 0 {
-		s.MeterStop = &meterStop
+
+	// charger meter readings received before are authoritative
+	if !lp.sessionMetered {
+		if meterStop := lp.chargeMeterTotal(); meterStop > 0 {
+			s.MeterStop = &meterStop
+		}
+		s.ChargedEnergy = lp.energyMetrics.TotalWh() / 1e3
 	}
 
 	s.SolarPercentage = lo.ToPtr(lp.energyMetrics.SolarPercentage())
 	s.Price = lp.energyMetrics.Price()
 	s.PricePerKWh = lp.energyMetrics.PricePerKWh()
 	s.Co2PerKWh = lp.energyMetrics.Co2PerKWh()
-	s.ChargedEnergy = lp.energyMetrics.TotalWh() / 1e3
 	s.ChargeDuration = lo.ToPtr(lp.chargeDuration.Abs())
 
 	lp.db.Persist(s)
+
+	// session boundary, journal is synced
+	if err := journal.Instance.Sync(); err != nil {
+		lp.log.ERROR.Printf("journal: %v", err)
+	}
 }
 
 type sessionOption func(*session.Session)
 
-// updateSession updates any parameter of a charging session and persists the session.
+// updateSession updates any parameter of a charging session. Updates of stored sessions are
+// coalesced and persisted on the next database flush.
 func (lp *Loadpoint) updateSession(opts ...sessionOption) {
 	// test guard
 	if lp.db == nil || lp.session == nil {
@@ -97,7 +116,8 @@ func (lp *Loadpoint) updateSession(opts ...sessionOption) {
 	}
 
 	if !lp.session.Created.IsZero() {
-		lp.db.Persist(lp.session)
+		lp.db.Update(lp.session)
+		lp.journalSession()
 	}
 }
 
@@ -108,9 +128,130 @@ func (lp *Loadpoint) clearSession() {
 		return
 	}
 
+	if lp.session != nil {
+		if err := journal.Instance.Delete(sessionJournalKey(lp.session)); err != nil {
+			lp.log.ERROR.Printf("journal: %v", err)
+		}
+
+		// session boundary, journal is synced
+		if err := journal.Instance.Sync(); err != nil {
+			lp.log.ERROR.Printf("journal: %v", err)
+		}
+
+		if lp.session.ID != 0 {
+			lp.lastSession = lp.session
+		}
+	}
+
 	lp.session = nil
 }
 
+// SetSessionMeter reconciles the current or last stored session with the charger's meter readings in kWh,
+// e.g. of an OCPP transaction. The charger's readings are authoritative for the charged energy.
+func (lp *Loadpoint) SetSessionMeter(meterStart, meterStop float64) {
+	lp.Lock()
+	defer lp.Unlock()
+
+	s := lp.session
+	if s == nil || s.ID == 0 {
+		s = lp.lastSession
+	}
+
+	// test guard
+	if lp.db == nil || s == nil || meterStop <= 0 || meterStop < meterStart {
+		return
+	}
+
+	energy := meterStop - meterStart
+	if diff := energy - s.ChargedEnergy; math.Abs(diff) > max(0.1, 0.05*energy) {
+		lp.log.WARN.Printf("session energy: charger %.3fkWh, measured %.3fkWh", energy, s.ChargedEnergy)
+	}
+
+	s.MeterStart, s.MeterStop = &meterStart, &meterStop
+	s.ChargedEnergy = energy
+
+	// keep the readings if the session is stopped afterwards
+	if s == lp.session {
+		lp.sessionMetered = true
+	}
+
+	lp.db.Persist(s)
+}
+
+// RecordSession stores a finished charging session the loadpoint has not observed, e.g. an OCPP
+// transaction replayed by the charger after being offline. The current session is not affected.
+func (lp *Loadpoint) RecordSession(created, finished time.Time, identifier string, meterStart, meterStop float64) {
+	// test guard
+	if lp.db == nil || created.IsZero() || finished.Before(created) || meterStop < meterStart {
+		return
+	}
+
+	s := lp.db.New(meterStart)
+	s.Created, s.Finished = created, finished
+	s.Identifier = identifier
+	if meterStop > 0 {
+		s.MeterStop = &meterStop
+	}
+	s.ChargedEnergy = meterStop - meterStart
+
+	lp.log.INFO.Printf("recorded offline session: %.3fkWh, %s", s.ChargedEnergy, finished.Sub(created).Round(time.Second))
+
+	lp.db.Persist(s)
+}
+
+// sessionState is the journaled state of the active charging session
+type sessionState struct {
+	ID      uint               `json:"id"`
+	Metrics energyMetricsState `json:"metrics"`
+}
+
+func sessionJournalKey(s *session.Session) string {
+	return "loadpoint." + s.Loadpoint + ".session"
+}
+
+// journalSession journals the active session's energy anchors for resuming after unexpected restarts
+func (lp *Loadpoint) journalSession() {
+	// test guard
+	if lp.db == nil || lp.session == nil || lp.session.ID == 0 {
+		return
+	}
+
+	state := sessionState{
+		ID:      lp.session.ID,
+		Metrics: lp.energyMetrics.state(),
+	}
+
+	if err := journal.Instance.Set(sessionJournalKey(lp.session), state); err != nil {
+		lp.log.ERROR.Printf("journal: %v", err)
+	}
+}
+
+// resumeSession continues the journaled session if it is still unfinished. Session energy
+// is offset by the already charged energy.
+func (lp *Loadpoint) resumeSession() bool {
+	var state sessionState
+	if err := journal.Instance.Get(sessionJournalKey(lp.session), &state); err != nil {
+		return false
+	}
+
+	s, err := lp.db.Resume(state.ID)
+	if err != nil {
+		lp.log.DEBUG.Printf("session %d not resumed: %v", state.ID, err)
+		return false
+	}
+
+	lp.log.INFO.Printf("resuming session %d at %.3fkWh", s.ID, state.Metrics.TotalKWh)
+
+	lp.session = s
+	lp.energyMetrics.restore(state.Metrics)
+	lp.chargedAtStartup -= state.Metrics.TotalKWh
+
+	lp.energyMetrics.Publish("session", lp)
+	lp.publish(keys.ChargedEnergy, lp.GetChargedEnergy())
+
+	return true
+}
+
 func (lp *Loadpoint) resetHeatingSession() {
 	if lp.session == nil || !lp.chargerHasFeature(api.Heating) || !lp.chargerHasFeature(api.IntegratedDevice) {
 		return
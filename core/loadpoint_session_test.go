@@ -1,6 +1,7 @@
 package core
 
 import (
+	"path/filepath"
 	"testing"
 	"time"
 
@@ -8,6 +9,7 @@ import (
 	"github.com/evcc-io/evcc/api"
 	"github.com/evcc-io/evcc/core/session"
 	serverdb "github.com/evcc-io/evcc/server/db"
+	"github.com/evcc-io/evcc/server/db/journal"
 	"github.com/evcc-io/evcc/util"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
@@ -91,6 +93,100 @@ func TestSession(t *testing.T) {
 	t.Logf("session: %+v", s)
 }
 
+func TestSetSessionMeter(t *testing.T) {
+	var err error
+	serverdb.Instance, err = serverdb.New("sqlite", ":memory:")
+	require.NoError(t, err)
+
+	db, err := session.NewStore("foo", serverdb.Instance)
+	require.NoError(t, err)
+
+	lp := &Loadpoint{
+		log:   util.NewLogger("foo"),
+		clock: clock.NewMock(),
+		db:    db,
+	}
+
+	lp.createSession()
+	lp.updateSession(sessionStart(lp))
+	lp.energyMetrics.Update(10e3)
+	lp.stopSession()
+
+	// charger meter readings arriving after disconnect are authoritative
+	lp.clearSession()
+	lp.SetSessionMeter(100, 110.5)
+
+	s, err := db.Sessions()
+	require.NoError(t, err)
+	require.Len(t, s, 1)
+	assert.Equal(t, 10.5, s[0].ChargedEnergy)
+	assert.Equal(t, 100.0, *s[0].MeterStart)
+	assert.Equal(t, 110.5, *s[0].MeterStop)
+
+	// invalid readings are ignored
+	lp.SetSessionMeter(100, 90)
+
+	s, err = db.Sessions()
+	require.NoError(t, err)
+	assert.Equal(t, 10.5, s[0].ChargedEnergy)
+
+	// charger meter readings arriving before the session is stopped are kept
+	lp.createSession()
+	lp.updateSession(sessionStart(lp))
+	lp.energyMetrics.Update(5e3)
+	lp.SetSessionMeter(200, 206)
+	lp.stopSession()
+
+	s, err = db.Sessions()
+	require.NoError(t, err)
+	require.Len(t, s, 2)
+	assert.Equal(t, 6.0, s[1].ChargedEnergy)
+	assert.Equal(t, 206.0, *s[1].MeterStop)
+}
+
+func TestRecordSession(t *testing.T) {
+	var err error
+	serverdb.Instance, err = serverdb.New("sqlite", ":memory:")
+	require.NoError(t, err)
+
+	db, err := session.NewStore("foo", serverdb.Instance)
+	require.NoError(t, err)
+
+	clck := clock.NewMock()
+	lp := &Loadpoint{
+		log:   util.NewLogger("foo"),
+		clock: clck,
+		db:    db,
+	}
+
+	// current session is not affected
+	lp.createSession()
+	lp.updateSession(sessionStart(lp))
+
+	created := clck.Now().Add(-2 * time.Hour)
+	lp.RecordSession(created, created.Add(time.Hour), "tag", 100, 110.5)
+
+	s, err := db.Sessions()
+	require.NoError(t, err)
+	require.Len(t, s, 2)
+
+	rec := s[1]
+	assert.NotEqual(t, lp.session.ID, rec.ID)
+	assert.Equal(t, "foo", rec.Loadpoint)
+	assert.Equal(t, "tag", rec.Identifier)
+	assert.Equal(t, 10.5, rec.ChargedEnergy)
+	assert.Equal(t, 100.0, *rec.MeterStart)
+	assert.Equal(t, 110.5, *rec.MeterStop)
+	assert.True(t, created.Equal(rec.Created))
+
+	// invalid readings are ignored
+	lp.RecordSession(created, created.Add(time.Hour), "", 100, 90)
+
+	s, err = db.Sessions()
+	require.NoError(t, err)
+	assert.Len(t, s, 2)
+}
+
 func TestCloseSessionsOnStartup_emptyDb(t *testing.T) {
 	var err error
 	serverdb.Instance, err = serverdb.New("sqlite", ":memory:")
@@ -258,3 +354,46 @@ func TestResetHeatingSession(t *testing.T) {
 	assert.Equal(t, 1.0, *lp.session.MeterStart)
 	assert.Equal(t, 3.0, *lp.session.MeterStop)
 }
+
+func TestSessionResume(t *testing.T) {
+	require.NoError(t, journal.NewInstance(filepath.Join(t.TempDir(), "evcc.journal")))
+	t.Cleanup(func() { journal.Instance = nil })
+
+	var err error
+	serverdb.Instance, err = serverdb.New("sqlite", ":memory:")
+	require.NoError(t, err)
+
+	db, err := session.NewStore("foo", serverdb.Instance)
+	require.NoError(t, err)
+
+	newLoadpoint := func() *Loadpoint {
+		return &Loadpoint{
+			log:         util.NewLogger("foo"),
+			clock:       clock.NewMock(),
+			db:          db,
+			chargeMeter: &Null{},
+		}
+	}
+
+	lp := newLoadpoint()
+	lp.createSession()
+	lp.updateSession(sessionStart(lp))
+	lp.energyMetrics.Update(1.5)
+	lp.journalSession()
+
+	// restart with charger reporting 2kWh session energy
+	lp = newLoadpoint()
+	lp.chargedAtStartup = 2
+	lp.createSession()
+
+	require.NotNil(t, lp.session)
+	assert.NotZero(t, lp.session.ID)
+	assert.Equal(t, 1500.0, lp.energyMetrics.TotalWh())
+
+	lp.energyMetrics.Update(3 - lp.chargedAtStartup)
+	assert.Equal(t, 2500.0, lp.energyMetrics.TotalWh())
+
+	// disconnect
+	lp.clearSession()
+	assert.Empty(t, journal.Instance.Keys("loadpoint."))
+}
@@ -863,3 +863,60 @@ func TestWelcomeChargeAppliedOnlyOnce(t *testing.T) {
 	welcomeCharge, _ = lp.updateChargerStatus()
 	assert.False(t, welcomeCharge)
 }
+
+func TestSetPlanEnergyPushUnlocked(t *testing.T) {
+	clock := clock.NewMock()
+
+	lp := NewLoadpoint(util.NewLogger("foo"), settings.NewDatabaseSettingsAdapter("foo"))
+	lp.clock = clock
+	lp.chargeMeter = &Null{}
+
+	pushChan := make(chan push.Event)
+	lp.pushChan = pushChan
+
+	done := make(chan error)
+	go func() {
+		done <- lp.SetPlanEnergy(clock.Now().Add(time.Hour), 0, 10)
+	}()
+
+	// loadpoint is not locked while the event is pending
+	assert.Eventually(t, func() bool {
+		if lp.TryLock() {
+			lp.Unlock()
+			_, _, energy := lp.GetPlanEnergy()
+			return energy > 0
+		}
+		return false
+	}, time.Second, time.Millisecond)
+
+	assert.Equal(t, evPlanCreated, (<-pushChan).Event)
+	assert.NoError(t, <-done)
+}
+
+func TestSetLimitPowerLimit(t *testing.T) {
+	Voltage = 230 // V
+
+	ctrl := gomock.NewController(t)
+	charger := api.NewMockCharger(ctrl)
+
+	uiChan, pushChan, lpChan := createChannels(t)
+
+	lp := NewLoadpoint(util.NewLogger("foo"), nil)
+	lp.charger = charger
+	lp.chargeMeter = &Null{}
+	lp.wakeUpTimer = NewTimer()
+	lp.uiChan, lp.pushChan, lp.lpChan = uiChan, pushChan, lpChan
+	lp.phases = 3
+	lp.enabled = true
+
+	// power limit reduces the current
+	lp.SetPowerLimit(3 * Voltage * 10)
+	charger.EXPECT().MaxCurrent(int64(10)).Return(nil)
+	assert.NoError(t, lp.setLimit(maxA))
+
+	// power limit below min current disables the charger
+	lp.SetPowerLimit(1)
+	charger.EXPECT().Enable(false).Return(nil)
+	assert.NoError(t, lp.setLimit(maxA))
+	assert.False(t, lp.enabled)
+}
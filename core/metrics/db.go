@@ -5,18 +5,59 @@ import (
 	"time"
 
 	"github.com/evcc-io/evcc/server/db"
+	"gorm.io/gorm"
 )
 
 type meter struct {
 	Meter     int       `json:"meter" gorm:"column:meter;uniqueIndex:meter_ts"`
 	Timestamp time.Time `json:"ts" gorm:"column:ts;uniqueIndex:meter_ts"`
+	Slot      int       `json:"slot" gorm:"column:slot"` // 15min slot of the day in local time
 	Value     float64   `json:"val" gorm:"column:val"`
 }
 
+// aggregate is the hourly meter value in Wh created by downsampling
+type aggregate struct {
+	Meter     int       `json:"meter" gorm:"column:meter;uniqueIndex:aggregate_meter_ts"`
+	Timestamp time.Time `json:"ts" gorm:"column:ts;uniqueIndex:aggregate_meter_ts"`
+	Slot      int       `json:"slot" gorm:"column:slot"` // first 15min slot of the hour in local time
+	Value     float64   `json:"val" gorm:"column:val"`
+}
+
+func (aggregate) TableName() string {
+	return "meter_aggregates"
+}
+
 var ErrIncomplete = errors.New("meter profile incomplete")
 
 func Init() error {
-	return db.Instance.AutoMigrate(new(meter))
+	err := db.AutoMigrate(new(meter))
+	if err == nil {
+		err = db.AutoMigrate(new(aggregate))
+	}
+	if err == nil {
+		err = migrateSlots()
+	}
+	return err
+}
+
+// migrateSlots sets the slot of values stored before slots were added
+func migrateSlots() error {
+	return db.Instance.Transaction(func(tx *gorm.DB) error {
+		for _, model := range []any{new(meter), new(aggregate)} {
+			var rows []meter
+			if err := tx.Model(model).Where("slot IS NULL").Find(&rows).Error; err != nil {
+				return err
+			}
+
+			for _, r := range rows {
+				if err := tx.Model(model).Where("meter = ? AND ts = ?", r.Meter, r.Timestamp).Update("slot", SlotNum(r.Timestamp)).Error; err != nil {
+					return err
+				}
+			}
+		}
+
+		return nil
+	})
 }
 
 // Persist stores 15min consumption in Wh
@@ -24,52 +65,130 @@ func Persist(ts time.Time, value float64) error {
 	return db.Instance.Create(meter{
 		Meter:     1,
 		Timestamp: ts.Truncate(15 * time.Minute),
+		Slot:      SlotNum(ts),
 		Value:     value,
 	}).Error
 }
 
 // Profile returns a 15min average meter profile in Wh.
 // Profile is sorted by timestamp starting at 00:00. It is guaranteed to contain 96 15min values.
+// Slots are aggregated in local time independent of the database dialect, see https://github.com/evcc-io/evcc/discussions/23759
+// Downsampled hours contribute a quarter of their hourly value to each of their slots.
 func Profile(from time.Time) (*[96]float64, error) {
-	db, err := db.Instance.DB()
+	type slotSum struct {
+		Slot int
+		Val  float64
+		N    float64
+	}
+
+	query := func(model any) ([]slotSum, error) {
+		var res []slotSum
+		err := db.Instance.Model(model).
+			Select("slot, SUM(val) AS val, COUNT(*) AS n").
+			Where("meter = ? AND ts >= ?", 1, from).
+			Group("slot").
+			Scan(&res).Error
+		return res, err
+	}
+
+	rows, err := query(new(meter))
 	if err != nil {
 		return nil, err
 	}
 
-	// Use 'localtime' in strftime to fix https://github.com/evcc-io/evcc/discussions/23759
-	rows, err := db.Query(`SELECT min(ts) AS ts, avg(val) AS val
-		FROM meters
-		WHERE meter = ? AND ts >= ?
-		GROUP BY strftime("%H:%M", ts, 'localtime')
-		ORDER BY strftime("%H:%M", ts, 'localtime') ASC`, 1, from,
-	)
+	aggs, err := query(new(aggregate))
 	if err != nil {
 		return nil, err
 	}
-	defer rows.Close()
-
-	res := make([]float64, 0, 96)
 
-	for rows.Next() {
-		var ts SqlTime
-		var val float64
+	var sum, count [96]float64
+	for _, r := range rows {
+		sum[r.Slot] += r.Val
+		count[r.Slot] += r.N
+	}
 
-		if err := rows.Scan(&ts, &val); err != nil {
-			return nil, err
+	for _, r := range aggs {
+		for i := range 4 {
+			slot := (r.Slot + i) % 96
+			sum[slot] += r.Val / 4
+			count[slot] += r.N
 		}
-
-		res = append(res, val)
 	}
 
-	if err := rows.Err(); err != nil {
-		return nil, err
+	var res [96]float64
+	for i := range res {
+		if count[i] == 0 {
+			return nil, ErrIncomplete
+		}
+		res[i] = sum[i] / count[i]
 	}
 
-	if len(res) != 96 {
-		return nil, ErrIncomplete
+	return &res, nil
+}
+
+// Downsample replaces the 15min values before the given time by hourly aggregates.
+// Values of hours that have already been aggregated are added to the existing aggregate.
+// Returns the number of removed 15min values.
+func Downsample(before time.Time) (int64, error) {
+	// only aggregate complete hours
+	before = before.Truncate(time.Hour)
+
+	var deleted int64
+
+	err := db.Instance.Transaction(func(tx *gorm.DB) error {
+		var rows []meter
+		if err := tx.Where("ts < ?", before).Order("ts").Find(&rows).Error; err != nil {
+			return err
+		}
+
+		if len(rows) == 0 {
+			return nil
+		}
+
+		type key struct {
+			meter int
+			ts    int64
+		}
+
+		var res []aggregate
+		index := make(map[key]int)
+
+		for _, r := range rows {
+			ts := r.Timestamp.Truncate(time.Hour)
+			k := key{r.Meter, ts.Unix()}
+
+			if i, ok := index[k]; ok {
+				res[i].Value += r.Value
+				continue
+			}
+
+			index[k] = len(res)
+			res = append(res, aggregate{Meter: r.Meter, Timestamp: ts, Slot: SlotNum(ts), Value: r.Value})
+		}
+
+		for _, a := range res {
+			if err := addAggregate(tx, a); err != nil {
+				return err
+			}
+		}
+
+		txn := tx.Where("ts < ?", before).Delete(new(meter))
+		deleted = txn.RowsAffected
+
+		return txn.Error
+	})
+
+	return deleted, err
+}
+
+// addAggregate adds the aggregate to an existing aggregate of the same hour or creates it
+func addAggregate(tx *gorm.DB, a aggregate) error {
+	txn := tx.Model(new(aggregate)).Where("meter = ? AND ts = ?", a.Meter, a.Timestamp).Update("val", gorm.Expr("val + ?", a.Value))
+	if txn.Error != nil || txn.RowsAffected > 0 {
+		return txn.Error
 	}
 
-	return (*[96]float64)(res), nil
+	return tx.Create(&a).Error
 }
 
 func SlotNum(ts time.Time) int {
@@ -0,0 +1,103 @@
+package metrics
+
+import (
+	"testing"
+	"time"
+
+	"github.com/evcc-io/evcc/server/db"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestDownsample(t *testing.T) {
+	require.NoError(t, db.NewInstance("sqlite", ":memory:"))
+	require.NoError(t, Init())
+
+	start := time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
+
+	// 3 hours of 15min values
+	for i := range 12 {
+		require.NoError(t, Persist(start.Add(time.Duration(i)*15*time.Minute), float64(i)))
+	}
+
+	// incomplete hours are not aggregated
+	n, err := Downsample(start.Add(2*time.Hour + 30*time.Minute))
+	require.NoError(t, err)
+	assert.Equal(t, int64(8), n)
+
+	var res []aggregate
+	require.NoError(t, db.Instance.Order("ts").Find(&res).Error)
+	require.Len(t, res, 2)
+	assert.True(t, start.Equal(res[0].Timestamp))
+	assert.Equal(t, 0.0+1+2+3, res[0].Value)
+	assert.Equal(t, 4.0+5+6+7, res[1].Value)
+
+	var remaining int64
+	require.NoError(t, db.Instance.Model(new(meter)).Count(&remaining).Error)
+	assert.Equal(t, int64(4), remaining)
+}
+
+func TestDownsampleLate(t *testing.T) {
+	require.NoError(t, db.NewInstance("sqlite", ":memory:"))
+	require.NoError(t, Init())
+
+	start := time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
+
+	require.NoError(t, Persist(start, 1))
+	_, err := Downsample(start.Add(time.Hour))
+	require.NoError(t, err)
+
+	// late value for an hour already aggregated
+	require.NoError(t, Persist(start.Add(15*time.Minute), 2))
+	n, err := Downsample(start.Add(time.Hour))
+	require.NoError(t, err)
+	assert.Equal(t, int64(1), n)
+
+	var res []aggregate
+	require.NoError(t, db.Instance.Find(&res).Error)
+	require.Len(t, res, 1)
+	assert.Equal(t, 3.0, res[0].Value)
+}
+
+func TestProfileAggregates(t *testing.T) {
+	require.NoError(t, db.NewInstance("sqlite", ":memory:"))
+	require.NoError(t, Init())
+
+	from := time.Date(2025, 1, 1, 0, 0, 0, 0, time.Local)
+
+	// first day downsampled, second day raw
+	for i := range 2 * 96 {
+		require.NoError(t, Persist(from.Add(time.Duration(i)*15*time.Minute), 1))
+	}
+
+	_, err := Downsample(from.AddDate(0, 0, 1))
+	require.NoError(t, err)
+
+	res, err := Profile(from)
+	require.NoError(t, err)
+
+	for _, v := range res {
+		assert.Equal(t, 1.0, v)
+	}
+}
+
+func TestMigrateSlots(t *testing.T) {
+	require.NoError(t, db.NewInstance("sqlite", ":memory:"))
+	require.NoError(t, Init())
+
+	ts := time.Date(2025, 1, 1, 1, 30, 0, 0, time.Local)
+
+	// values stored before slots were added
+	require.NoError(t, db.Instance.Exec("INSERT INTO meters (meter, ts, val) VALUES (?, ?, ?)", 1, ts, 1).Error)
+	require.NoError(t, db.Instance.Exec("INSERT INTO meter_aggregates (meter, ts, val) VALUES (?, ?, ?)", 1, ts.Add(time.Hour), 4).Error)
+
+	require.NoError(t, Init())
+
+	var m meter
+	require.NoError(t, db.Instance.First(&m).Error)
+	assert.Equal(t, SlotNum(ts), m.Slot)
+
+	var a aggregate
+	require.NoError(t, db.Instance.First(&a).Error)
+	assert.Equal(t, SlotNum(ts.Add(time.Hour)), a.Slot)
+}
@@ -1,7 +1,13 @@
 package session
 
 import (
+	"fmt"
+	"strconv"
+	"sync"
+	"time"
+
 	"github.com/evcc-io/evcc/server/db"
+	"github.com/evcc-io/evcc/server/db/journal"
 	"github.com/evcc-io/evcc/util"
 	"gorm.io/gorm"
 )
@@ -11,6 +17,9 @@ type DB struct {
 	log  *util.Logger
 	db   *gorm.DB
 	name string
+
+	mu      sync.Mutex
+	pending map[uint]Session // coalesced updates by session id
 }
 
 var (
@@ -18,23 +27,72 @@ var (
 )
 
 func Init() error {
-	err := db.Instance.AutoMigrate(new(Session))
+	err := db.AutoMigrate(new(Session))
+	if err == nil {
+		err = replay()
+	}
 	if err == nil {
 		err = db.Instance.Find(&sessions).Error
 	}
 	return err
 }
 
+// journalKey is the journal key of a coalesced session update
+func journalKey(id uint) string {
+	return "session." + strconv.FormatUint(uint64(id), 10)
+}
+
+// replay persists session updates that were journaled but not flushed before an unexpected restart
+func replay() error {
+	for _, key := range journal.Instance.Keys("session.") {
+		var session Session
+		if err := journal.Instance.Get(key, &session); err != nil {
+			return fmt.Errorf("journal: %w", err)
+		}
+
+		if err := update(db.Instance, &session); err != nil {
+			return err
+		}
+
+		if err := journal.Instance.Delete(key); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// update stores the session if it still exists. Sessions deleted meanwhile, e.g. via the api, are not recreated.
+func update(tx *gorm.DB, session *Session) error {
+	return tx.Model(session).Select("*").Updates(session).Error
+}
+
+// Purge deletes finished sessions created before the given time
+func Purge(before time.Time) (int64, error) {
+	txn := db.Instance.Where("created < ? AND finished > created", before).Delete(new(Session))
+	return txn.RowsAffected, txn.Error
+}
+
+// NewReport creates a report of the sessions finished within the period
+func NewReport(from, to time.Time) (*Report, error) {
+	r := &Report{From: from, To: to}
+	txn := db.Instance.Where("finished >= ? AND finished < ? AND charged_kwh > 0", from, to).Order("created ASC").Find(&r.Sessions)
+	return r, txn.Error
+}
+
 // NewStore creates a session store
-func NewStore(name string, db *gorm.DB) (*DB, error) {
-	err := db.AutoMigrate(new(Session))
+func NewStore(name string, instance *gorm.DB) (*DB, error) {
+	err := instance.AutoMigrate(new(Session))
 
 	sessiondb := &DB{
-		log:  util.NewLogger("db"),
-		db:   db,
-		name: name,
+		log:     util.NewLogger("db"),
+		db:      instance,
+		name:    name,
+		pending: make(map[uint]Session),
 	}
 
+	db.RegisterFlusher(sessiondb.Flush)
+
 	return sessiondb, err
 }
 
@@ -53,9 +111,74 @@ func (s *DB) New(meter float64) *Session {
 
 // Persist creates or updates a transaction in the database
 func (s *DB) Persist(session any) {
-	if err := s.db.Save(session).Error; err != nil {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	var err error
+	if t, ok := session.(*Session); ok && t.ID != 0 {
+		err = update(s.db, t)
+	} else {
+		err = s.db.Save(session).Error
+	}
+
+	if err != nil {
 		s.log.ERROR.Printf("persist: %v", err)
+		return
+	}
+
+	// superseded by this update
+	if t, ok := session.(*Session); ok {
+		delete(s.pending, t.ID)
+		if err := journal.Instance.Delete(journalKey(t.ID)); err != nil {
+			s.log.ERROR.Printf("journal: %v", err)
+		}
+	}
+}
+
+// Update coalesces updates of a running session until the next flush.
+// Sessions not yet stored are persisted immediately.
+func (s *DB) Update(session *Session) {
+	if session.ID == 0 {
+		s.Persist(session)
+		return
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.pending[session.ID] = *session
+
+	if err := journal.Instance.Set(journalKey(session.ID), session); err != nil {
+		s.log.ERROR.Printf("journal: %v", err)
+	}
+}
+
+// Flush persists coalesced session updates
+func (s *DB) Flush() error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	for id, session := range s.pending {
+		if err := update(s.db, &session); err != nil {
+			return err
+		}
+		delete(s.pending, id)
+
+		if err := journal.Instance.Delete(journalKey(id)); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// Resume returns the unfinished session with given id, e.g. for continuing the session after a restart
+func (s *DB) Resume(id uint) (*Session, error) {
+	var res Session
+	if err := s.db.Where("finished = ? AND loadpoint = ?", time.Time{}, s.name).First(&res, id).Error; err != nil {
+		return nil, err
 	}
+	return &res, nil
 }
 
 // Return sessions
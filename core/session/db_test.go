@@ -0,0 +1,94 @@
+package session
+
+import (
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/evcc-io/evcc/server/db"
+	"github.com/evcc-io/evcc/server/db/journal"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gorm.io/gorm"
+)
+
+func TestCoalescedUpdates(t *testing.T) {
+	require.NoError(t, db.NewInstance("sqlite", ":memory:"))
+
+	s, err := NewStore("lp", db.Instance)
+	require.NoError(t, err)
+
+	session := s.New(0)
+	session.Created = time.Now()
+	s.Update(session)
+	require.NotZero(t, session.ID, "new session not persisted")
+
+	session.ChargedEnergy = 1
+	s.Update(session)
+	session.ChargedEnergy = 2
+	s.Update(session)
+
+	var res Session
+	require.NoError(t, db.Instance.First(&res, session.ID).Error)
+	assert.Zero(t, res.ChargedEnergy, "update not coalesced")
+
+	require.NoError(t, db.Flush())
+	require.NoError(t, db.Instance.First(&res, session.ID).Error)
+	assert.Equal(t, 2.0, res.ChargedEnergy)
+	assert.Empty(t, s.pending)
+}
+
+func TestJournalReplay(t *testing.T) {
+	require.NoError(t, journal.NewInstance(filepath.Join(t.TempDir(), "evcc.journal")))
+	t.Cleanup(func() { journal.Instance = nil })
+
+	require.NoError(t, db.NewInstance("sqlite", ":memory:"))
+
+	s, err := NewStore("lp", db.Instance)
+	require.NoError(t, err)
+
+	session := s.New(0)
+	session.Created = time.Now()
+	s.Update(session)
+
+	session.ChargedEnergy = 1
+	s.Update(session)
+
+	// restart without flushing
+	require.NoError(t, Init())
+	assert.Empty(t, journal.Instance.Keys("session."))
+
+	res, err := s.Resume(session.ID)
+	require.NoError(t, err)
+	assert.Equal(t, 1.0, res.ChargedEnergy)
+
+	session.Finished = time.Now()
+	s.Persist(session)
+
+	_, err = s.Resume(session.ID)
+	assert.Error(t, err, "finished session resumed")
+}
+
+func TestDeletedSessionNotRecreated(t *testing.T) {
+	require.NoError(t, db.NewInstance("sqlite", ":memory:"))
+
+	s, err := NewStore("lp", db.Instance)
+	require.NoError(t, err)
+
+	session := s.New(0)
+	session.Created = time.Now()
+	s.Update(session)
+	require.NotZero(t, session.ID)
+
+	session.ChargedEnergy = 1
+	s.Update(session)
+
+	// deleted via api while the update is pending
+	require.NoError(t, db.Instance.Delete(new(Session), session.ID).Error)
+
+	require.NoError(t, db.Flush())
+	assert.ErrorIs(t, db.Instance.First(new(Session), session.ID).Error, gorm.ErrRecordNotFound)
+
+	s.Persist(session)
+	assert.ErrorIs(t, db.Instance.First(new(Session), session.ID).Error, gorm.ErrRecordNotFound)
+}
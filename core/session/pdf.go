@@ -0,0 +1,114 @@
+package session
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"strings"
+
+	"golang.org/x/text/encoding/charmap"
+)
+
+// A4 landscape in points
+const (
+	pdfWidth    = 842
+	pdfHeight   = 595
+	pdfMargin   = 40
+	pdfFontSize = 9
+	pdfLeading  = 13
+)
+
+// pdfDocument is a minimal text-only pdf writer using the standard Helvetica font
+type pdfDocument struct {
+	pages []*bytes.Buffer
+	y     float64
+}
+
+func (d *pdfDocument) newPage() {
+	d.pages = append(d.pages, new(bytes.Buffer))
+	d.y = pdfHeight - pdfMargin
+}
+
+// text writes a line of text at the given x offset. Lines wrap to a new page when the page is full.
+func (d *pdfDocument) text(x float64, size int, bold bool, s string) {
+	if len(d.pages) == 0 {
+		d.newPage()
+	}
+
+	font := "F1"
+	if bold {
+		font = "F2"
+	}
+
+	fmt.Fprintf(d.pages[len(d.pages)-1], "BT /%s %d Tf %.1f %.1f Td (%s) Tj ET\n", font, size, pdfMargin+x, d.y, pdfEscape(s))
+}
+
+// row writes the columns at the given offsets and advances to the next line
+func (d *pdfDocument) row(bold bool, offsets []float64, cols ...string) {
+	if len(d.pages) == 0 || d.y < pdfMargin {
+		d.newPage()
+	}
+
+	for i, col := range cols {
+		d.text(offsets[i], pdfFontSize, bold, col)
+	}
+
+	d.y -= pdfLeading
+}
+
+// line advances by the given number of lines
+func (d *pdfDocument) line(n float64) {
+	d.y -= n * pdfLeading
+}
+
+func pdfEscape(s string) string {
+	if enc, err := charmap.Windows1252.NewEncoder().String(s); err == nil {
+		s = enc
+	}
+	return strings.NewReplacer(`\`, `\\`, `(`, `\(`, `)`, `\)`).Replace(s)
+}
+
+// WriteTo writes the pdf document
+func (d *pdfDocument) WriteTo(w io.Writer) (int64, error) {
+	if len(d.pages) == 0 {
+		d.newPage()
+	}
+
+	var (
+		buf     bytes.Buffer
+		offsets []int
+	)
+
+	obj := func(body string) {
+		offsets = append(offsets, buf.Len())
+		fmt.Fprintf(&buf, "%d 0 obj\n%s\nendobj\n", len(offsets), body)
+	}
+
+	buf.WriteString("%PDF-1.4\n")
+
+	// fixed objects: 1 catalog, 2 pages, 3+4 fonts, then page/content pairs
+	kids := make([]string, len(d.pages))
+	for i := range d.pages {
+		kids[i] = fmt.Sprintf("%d 0 R", 5+2*i)
+	}
+
+	obj("<< /Type /Catalog /Pages 2 0 R >>")
+	obj(fmt.Sprintf("<< /Type /Pages /Kids [%s] /Count %d >>", strings.Join(kids, " "), len(d.pages)))
+	obj("<< /Type /Font /Subtype /Type1 /BaseFont /Helvetica /Encoding /WinAnsiEncoding >>")
+	obj("<< /Type /Font /Subtype /Type1 /BaseFont /Helvetica-Bold /Encoding /WinAnsiEncoding >>")
+
+	for i, page := range d.pages {
+		obj(fmt.Sprintf("<< /Type /Page /Parent 2 0 R /MediaBox [0 0 %d %d] /Resources << /Font << /F1 3 0 R /F2 4 0 R >> >> /Contents %d 0 R >>",
+			pdfWidth, pdfHeight, 6+2*i))
+		obj(fmt.Sprintf("<< /Length %d >>\nstream\n%sendstream", page.Len(), page.String()))
+	}
+
+	xref := buf.Len()
+	fmt.Fprintf(&buf, "xref\n0 %d\n0000000000 65535 f \n", len(offsets)+1)
+	for _, o := range offsets {
+		fmt.Fprintf(&buf, "%010d 00000 n \n", o)
+	}
+	fmt.Fprintf(&buf, "trailer\n<< /Size %d /Root 1 0 R >>\nstartxref\n%d\n%%%%EOF\n", len(offsets)+1, xref)
+
+	return buf.WriteTo(w)
+}
@@ -0,0 +1,255 @@
+package session
+
+import (
+	"context"
+	"encoding/csv"
+	"fmt"
+	"io"
+	"strings"
+	"time"
+
+	"github.com/evcc-io/evcc/api"
+	"github.com/evcc-io/evcc/util/locale"
+	"golang.org/x/text/language"
+	"golang.org/x/text/message"
+)
+
+// Report summarizes charging sessions of a period, e.g. for reimbursement of home charging
+type Report struct {
+	From, To    time.Time
+	Vehicles    []string
+	Identifiers []string
+	Sessions    Sessions
+}
+
+// ReportTotals are the aggregated values of a report
+type ReportTotals struct {
+	Sessions       int
+	ChargedEnergy  float64
+	SolarEnergy    float64
+	GridEnergy     float64
+	ChargeDuration time.Duration
+	Price          float64
+}
+
+var _ api.CsvWriter = (*Report)(nil)
+
+// reportColumns are the message ids of the report columns
+var reportColumns = []string{"created", "finished", "loadpoint", "vehicle", "identifier", "chargedEnergy", "solarEnergy", "gridEnergy", "chargeDuration", "pricePerKWh", "price"}
+
+// english column captions, pdf captions are abbreviated to fit the page
+var (
+	csvColumns = []string{"Created", "Finished", "Loadpoint", "Vehicle", "Identifier", "Charged Energy (kWh)", "Solar Energy (kWh)", "Grid Energy (kWh)", "Charge Duration", "Price/kWh", "Price"}
+	pdfColumns = []string{"Created", "Finished", "Loadpoint", "Vehicle", "Identifier", "Energy (kWh)", "Solar (kWh)", "Grid (kWh)", "Duration", "Price/kWh", "Price"}
+)
+
+var (
+	msgReportTitle       = &locale.Message{ID: "backend.report.title", Other: "Charging Report"}
+	msgReportPeriod      = &locale.Message{ID: "backend.report.period", Other: "Period:"}
+	msgReportAllSessions = &locale.Message{ID: "backend.report.allSessions", Other: "all sessions"}
+	msgReportVehicle     = &locale.Message{ID: "backend.report.vehicle", Other: "Vehicle:"}
+	msgReportIdentifier  = &locale.Message{ID: "backend.report.identifier", Other: "Identifier:"}
+	msgReportCreated     = &locale.Message{ID: "backend.report.created", Other: "Created:"}
+	msgReportTotal       = &locale.Message{ID: "backend.report.total", Other: "Total"}
+)
+
+// solarEnergy returns the session's energy split into solar and grid
+func solarEnergy(s Session) (float64, float64) {
+	var solar float64
+	if s.SolarPercentage != nil {
+		solar = s.ChargedEnergy * *s.SolarPercentage / 100
+	}
+	return solar, s.ChargedEnergy - solar
+}
+
+// Totals returns the aggregated report values
+func (r *Report) Totals() ReportTotals {
+	res := ReportTotals{Sessions: len(r.Sessions)}
+
+	for _, s := range r.Sessions {
+		solar, grid := solarEnergy(s)
+
+		res.ChargedEnergy += s.ChargedEnergy
+		res.SolarEnergy += solar
+		res.GridEnergy += grid
+
+		if s.ChargeDuration != nil {
+			res.ChargeDuration += *s.ChargeDuration
+		}
+		if s.Price != nil {
+			res.Price += *s.Price
+		}
+	}
+
+	return res
+}
+
+// VehicleTotals returns the aggregated report values per vehicle
+func (r *Report) VehicleTotals() map[string]ReportTotals {
+	vehicles := make(map[string]*Report)
+
+	for _, s := range r.Sessions {
+		vr, ok := vehicles[s.Vehicle]
+		if !ok {
+			vr = new(Report)
+			vehicles[s.Vehicle] = vr
+		}
+		vr.Sessions = append(vr.Sessions, s)
+	}
+
+	res := make(map[string]ReportTotals, len(vehicles))
+	for v, vr := range vehicles {
+		res[v] = vr.Totals()
+	}
+
+	return res
+}
+
+// header returns the localized column captions of the format
+func (r *Report) header(lang, format string, captions []string) []string {
+	res := make([]string, 0, len(reportColumns))
+	for i, col := range reportColumns {
+		res = append(res, locale.Localize(lang, &locale.Message{
+			ID:    "backend.report." + format + "." + col,
+			Other: captions[i],
+		}, nil))
+	}
+	return res
+}
+
+func (r *Report) row(mp *message.Printer, s Session) []string {
+	solar, grid := solarEnergy(s)
+
+	var duration string
+	if s.ChargeDuration != nil {
+		duration = s.ChargeDuration.Round(time.Second).String()
+	}
+
+	return []string{
+		formatValue(mp, s.Created, 0),
+		formatValue(mp, s.Finished, 0),
+		s.Loadpoint,
+		s.Vehicle,
+		s.Identifier,
+		formatValue(mp, s.ChargedEnergy, 3),
+		formatValue(mp, solar, 3),
+		formatValue(mp, grid, 3),
+		duration,
+		formatValue(mp, s.PricePerKWh, 3),
+		formatValue(mp, s.Price, 2),
+	}
+}
+
+func (r *Report) totalsRow(lang string, mp *message.Printer) []string {
+	t := r.Totals()
+	return []string{
+		locale.Localize(lang, msgReportTotal, nil), "", "", "", fmt.Sprintf("%d", t.Sessions),
+		formatValue(mp, t.ChargedEnergy, 3),
+		formatValue(mp, t.SolarEnergy, 3),
+		formatValue(mp, t.GridEnergy, 3),
+		t.ChargeDuration.Round(time.Second).String(),
+		"",
+		formatValue(mp, t.Price, 2),
+	}
+}
+
+func reportLanguage(ctx context.Context) (language.Tag, error) {
+	lang := locale.Language
+	if val, ok := ctx.Value(locale.Locale).(string); ok && val != "" {
+		lang = val
+	}
+	return language.Parse(lang)
+}
+
+// WriteCsv implements the api.CsvWriter interface
+func (r *Report) WriteCsv(ctx context.Context, w io.Writer) error {
+	if _, err := w.Write([]byte{0xEF, 0xBB, 0xBF}); err != nil {
+		return err
+	}
+
+	tag, err := reportLanguage(ctx)
+	if err != nil {
+		return err
+	}
+
+	ww := csv.NewWriter(w)
+
+	// set separator according to locale
+	if b, _ := tag.Base(); b.String() == language.German.String() {
+		ww.Comma = ';'
+	}
+
+	mp := message.NewPrinter(tag)
+
+	if err := ww.Write(r.header(tag.String(), "csv", csvColumns)); err != nil {
+		return err
+	}
+
+	for _, s := range r.Sessions {
+		if err := ww.Write(r.row(mp, s)); err != nil {
+			return err
+		}
+	}
+
+	if err := ww.Write(r.totalsRow(tag.String(), mp)); err != nil {
+		return err
+	}
+
+	ww.Flush()
+
+	return ww.Error()
+}
+
+// WritePdf writes the report as pdf document
+func (r *Report) WritePdf(ctx context.Context, w io.Writer) error {
+	tag, err := reportLanguage(ctx)
+	if err != nil {
+		return err
+	}
+
+	lang := tag.String()
+	mp := message.NewPrinter(tag)
+	doc := new(pdfDocument)
+
+	doc.newPage()
+	doc.text(0, 16, true, locale.Localize(lang, msgReportTitle, nil))
+	doc.line(2)
+
+	period := locale.Localize(lang, msgReportAllSessions, nil)
+	if !r.From.IsZero() || !r.To.IsZero() {
+		period = fmt.Sprintf("%s - %s", formatDate(r.From), formatDate(r.To))
+	}
+	doc.row(false, []float64{0, 90}, locale.Localize(lang, msgReportPeriod, nil), period)
+
+	if len(r.Vehicles) > 0 {
+		doc.row(false, []float64{0, 90}, locale.Localize(lang, msgReportVehicle, nil), strings.Join(r.Vehicles, ", "))
+	}
+	if len(r.Identifiers) > 0 {
+		doc.row(false, []float64{0, 90}, locale.Localize(lang, msgReportIdentifier, nil), strings.Join(r.Identifiers, ", "))
+	}
+	doc.row(false, []float64{0, 90}, locale.Localize(lang, msgReportCreated, nil), time.Now().Format("2006-01-02 15:04"))
+	doc.line(1)
+
+	offsets := []float64{0, 85, 170, 250, 340, 420, 490, 560, 630, 690, 730}
+
+	doc.row(true, offsets, r.header(lang, "pdf", pdfColumns)...)
+
+	for _, s := range r.Sessions {
+		row := r.row(mp, s)
+		row[0], row[1] = s.Created.Local().Format("2006-01-02 15:04"), s.Finished.Local().Format("2006-01-02 15:04")
+		doc.row(false, offsets, row...)
+	}
+
+	doc.line(0.5)
+	doc.row(true, offsets, r.totalsRow(lang, mp)...)
+
+	_, err = doc.WriteTo(w)
+	return err
+}
+
+func formatDate(t time.Time) string {
+	if t.IsZero() {
+		return "..."
+	}
+	return t.Local().Format(time.DateOnly)
+}
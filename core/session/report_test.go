@@ -0,0 +1,54 @@
+package session
+
+import (
+	"bytes"
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/evcc-io/evcc/util/locale"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestReport(t *testing.T) {
+	solar := 50.0
+	price := 3.0
+	duration := time.Hour
+
+	r := Report{
+		Vehicles: []string{"Büro (Firmenwagen)"},
+		Sessions: Sessions{
+			{Created: time.Now(), Vehicle: "car", ChargedEnergy: 10, SolarPercentage: &solar, Price: &price, ChargeDuration: &duration},
+			{Created: time.Now(), Vehicle: "car", ChargedEnergy: 5},
+		},
+	}
+
+	totals := r.Totals()
+	assert.Equal(t, 2, totals.Sessions)
+	assert.Equal(t, 15.0, totals.ChargedEnergy)
+	assert.Equal(t, 5.0, totals.SolarEnergy)
+	assert.Equal(t, 10.0, totals.GridEnergy)
+	assert.Equal(t, 3.0, totals.Price)
+	assert.Equal(t, time.Hour, totals.ChargeDuration)
+
+	r.Sessions = append(r.Sessions, Session{Created: time.Now(), Vehicle: "other", ChargedEnergy: 2})
+	vehicles := r.VehicleTotals()
+	assert.Len(t, vehicles, 2)
+	assert.Equal(t, 15.0, vehicles["car"].ChargedEnergy)
+	assert.Equal(t, 1, vehicles["other"].Sessions)
+	r.Sessions = r.Sessions[:2]
+
+	ctx := context.WithValue(context.Background(), locale.Locale, "en")
+
+	var csv bytes.Buffer
+	require.NoError(t, r.WriteCsv(ctx, &csv))
+	assert.Len(t, strings.Split(strings.TrimSpace(csv.String()), "\n"), 4) // header, sessions, total
+
+	var pdf bytes.Buffer
+	require.NoError(t, r.WritePdf(ctx, &pdf))
+	assert.True(t, bytes.HasPrefix(pdf.Bytes(), []byte("%PDF-1.4")))
+	assert.Contains(t, pdf.String(), `\(Firmenwagen\)`)
+	assert.True(t, bytes.HasSuffix(pdf.Bytes(), []byte("%%EOF\n")))
+}
@@ -3,6 +3,7 @@ package settings
 import (
 	"encoding/json"
 	"errors"
+	"reflect"
 	"sync"
 	"time"
 
@@ -38,6 +39,10 @@ func (s *ConfigSettings) set(key string, val any) {
 	s.mu.Lock()
 	defer s.mu.Unlock()
 	data := s.conf.Named().Other
+	// skip unchanged values to avoid database writes
+	if reflect.DeepEqual(data[key], val) {
+		return
+	}
 	data[key] = val
 	if err := s.conf.Update(data); err != nil {
 		s.log.ERROR.Println(err)
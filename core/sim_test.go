@@ -0,0 +1,173 @@
+package core
+
+import (
+	"testing"
+	"time"
+
+	"github.com/benbjohnson/clock"
+	"github.com/evcc-io/evcc/api"
+	"github.com/evcc-io/evcc/core/circuit"
+	"github.com/evcc-io/evcc/core/coordinator"
+	"github.com/evcc-io/evcc/core/metrics"
+	"github.com/evcc-io/evcc/core/planner"
+	"github.com/evcc-io/evcc/core/prioritizer"
+	"github.com/evcc-io/evcc/core/session"
+	"github.com/evcc-io/evcc/core/settings"
+	"github.com/evcc-io/evcc/server/db"
+	"github.com/evcc-io/evcc/tariff"
+	"github.com/evcc-io/evcc/util"
+	"github.com/evcc-io/evcc/util/config"
+	"github.com/evcc-io/evcc/util/sim"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// simulation runs a site and loadpoint against simulated devices
+type simulation struct {
+	t       *testing.T
+	clock   *clock.Mock
+	env     *sim.Environment
+	site    *Site
+	lp      *Loadpoint
+	charger *sim.Charger
+	vehicle *sim.Vehicle
+}
+
+// newSimulation creates a site with grid and pv meter and a 3p loadpoint with a plugged 50kWh vehicle at 20%.
+// Guest vehicles are not known to evcc and charged without soc.
+func newSimulation(t *testing.T, guest bool) *simulation {
+	t.Helper()
+
+	Voltage = sim.Voltage
+
+	require.NoError(t, db.NewInstance("sqlite", ":memory:"))
+	require.NoError(t, session.Init())
+	require.NoError(t, metrics.Init())
+
+	clock := clock.NewMock()
+	clock.Set(time.Date(2025, 6, 1, 0, 0, 0, 0, time.Local))
+
+	env := sim.NewEnvironment(clock)
+	charger := env.NewCharger(3)
+	vehicle := env.NewVehicle("ev", 50, 20)
+	charger.Plug("ev")
+
+	log := util.NewLogger("sim")
+
+	lp := NewLoadpoint(log, settings.NewDatabaseSettingsAdapter(t.Name()+"."))
+	lp.clock = clock
+	lp.charger = charger
+	lp.phases, lp.phasesConfigured = 3, 3
+	lp.configureChargerType(charger)
+
+	site := NewSite()
+	site.log = log
+	site.gridMeter = env.GridMeter()
+	site.pvMeters = []config.Device[api.Meter]{config.NewStaticDevice[api.Meter](config.Named{Name: "pv"}, env.PvMeter())}
+	site.pvEnergy["pv"] = &meterEnergy{clock: clock}
+	site.householdEnergy = &meterEnergy{clock: clock}
+	site.estimator = newEstimator(clock)
+	site.loadpoints = []*Loadpoint{lp}
+	site.tariffs = new(tariff.Tariffs)
+	site.prioritizer = prioritizer.New(log)
+	site.coordinator = coordinator.New(log, nil)
+
+	if !guest {
+		lp.defaultVehicle = vehicle
+		site.coordinator = coordinator.New(log, []api.Vehicle{vehicle})
+	}
+	site.stats = NewStats()
+	site.Health = NewHealth(time.Minute)
+
+	lp.coordinator = coordinator.NewAdapter(lp, site.coordinator)
+	lp.planner = planner.New(log, nil, planner.WithClock(clock))
+
+	uiChan, pushChan, lpChan := createChannels(t)
+	lp.Prepare(site, uiChan, pushChan, lpChan)
+
+	return &simulation{
+		t:       t,
+		clock:   clock,
+		env:     env,
+		site:    site,
+		lp:      lp,
+		charger: charger,
+		vehicle: vehicle,
+	}
+}
+
+// withCircuit limits the loadpoint by a circuit
+func (s *simulation) withCircuit(maxCurrent, maxPower float64) {
+	s.t.Helper()
+
+	c, err := circuit.New(s.site.log, "main", maxCurrent, maxPower, nil, 0)
+	require.NoError(s.t, err)
+
+	s.site.circuit = c
+	s.lp.circuit = c
+}
+
+// run executes site updates every 30s for the given duration
+func (s *simulation) run(d time.Duration) {
+	for end := s.clock.Now().Add(d); s.clock.Now().Before(end); {
+		s.clock.Add(30 * time.Second)
+		s.site.update(s.lp)
+	}
+}
+
+// charging returns the charge current, zero if not charging
+func (s *simulation) charging() float64 {
+	if p, _ := s.charger.CurrentPower(); p == 0 {
+		return 0
+	}
+	return s.charger.Current()
+}
+
+func TestSimulationCloudPassage(t *testing.T) {
+	s := newSimulation(t, false)
+	s.env.SetHomePower(500)
+	s.env.SetPvPower(8000)
+	s.lp.SetMode(api.ModePV)
+
+	s.run(5 * time.Minute)
+	assert.InDelta(t, 7500/3/Voltage, s.charging(), 1, "charging with surplus")
+
+	// short cloud: continue at min current during disable delay
+	s.env.SetPvPower(1000)
+	s.run(2 * time.Minute)
+	assert.Equal(t, 6.0, s.charging(), "min current during disable delay")
+
+	s.env.SetPvPower(8000)
+	s.run(2 * time.Minute)
+	assert.Greater(t, s.charging(), 6.0, "recovered")
+
+	// long cloud: stop after disable delay
+	s.env.SetPvPower(1000)
+	s.run(5 * time.Minute)
+	assert.Zero(t, s.charging(), "stopped")
+}
+
+func TestSimulationFuseLimit(t *testing.T) {
+	s := newSimulation(t, false)
+	s.withCircuit(10, 0)
+	s.lp.SetMode(api.ModeNow)
+
+	s.run(2 * time.Minute)
+	assert.Equal(t, 10.0, s.charging(), "limited by circuit")
+}
+
+func TestSimulationPlanDeadline(t *testing.T) {
+	s := newSimulation(t, true)
+	s.lp.SetMode(api.ModePV)
+
+	// no pv at night, 11kW charge power for 20kWh
+	deadline := s.clock.Now().Add(8 * time.Hour)
+	require.NoError(t, s.lp.SetPlanEnergy(deadline, 0, 20))
+
+	s.run(4 * time.Hour)
+	assert.Zero(t, s.charging(), "waiting for plan start")
+
+	s.run(4 * time.Hour)
+	energy, _ := s.charger.TotalEnergy()
+	assert.InDelta(t, 20, energy, 1, "plan energy charged by deadline")
+}
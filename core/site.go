@@ -3,7 +3,6 @@ package core
 import (
 	"bytes"
 	"context"
-	"errors"
 	"fmt"
 	"math"
 	"strings"
@@ -60,6 +59,7 @@ type measurement struct {
 	Capacity      *float64  `json:"capacity,omitempty"`
 	Soc           *float64  `json:"soc,omitempty"`
 	Controllable  *bool     `json:"controllable,omitempty"`
+	Estimated     bool      `json:"estimated,omitempty"`
 }
 
 var _ api.TitleDescriber = (*measurement)(nil)
@@ -73,8 +73,11 @@ var _ site.API = (*Site)(nil)
 
 // Site is the main configuration container. A site can host multiple loadpoints.
 type Site struct {
-	uiChan       chan<- util.Param // client push messages
+	uiChan       chan<- util.Param                // client push messages
+	uiQueue      *chanx.UnboundedChan[util.Param] // client push message backlog
+	pushChan     chan<- push.Event                // notifications
 	lpUpdateChan chan *Loadpoint
+	rewireChan   chan struct{} // device configuration changed
 
 	*Health
 
@@ -115,6 +118,8 @@ type Site struct {
 
 	// cached state
 	gridPower                float64         // Grid power
+	gridEstimated            bool            // Grid power estimated from home power while grid meter is unavailable
+	homePower                float64         // Home power
 	pvPower                  float64         // PV power
 	excessDCPower            float64         // PV excess DC charge power (hybrid only)
 	auxPower                 float64         // Aux power
@@ -124,6 +129,8 @@ type Site struct {
 	batteryMode              api.BatteryMode // Battery mode (runtime only, not persisted)
 	batteryModeExternal      api.BatteryMode // Battery mode (external, runtime only, not persisted)
 	batteryModeExternalTimer time.Time       // Battery mode timer for external control
+	meterOutage              bool            // Grid meter unavailable
+	estimator                *estimator      // Last known meter readings for estimation during outages
 }
 
 // MetersConfig contains the site's meter configuration
@@ -198,56 +205,15 @@ func (site *Site) Boot(log *util.Logger, loadpoints []*Loadpoint, tariffs *tarif
 		site.circuit = c
 	}
 
-	// grid meter
-	if site.Meters.GridMeterRef != "" {
-		dev, err := config.Meters().ByName(site.Meters.GridMeterRef)
-		if err != nil {
-			return err
-		}
-		site.gridMeter = dev.Instance()
-		if site.gridMeter == nil {
-			return errors.New("missing grid meter instance")
-		}
-	}
-
-	// multiple pv
-	for _, ref := range site.Meters.PVMetersRef {
-		dev, err := config.Meters().ByName(ref)
-		if err != nil {
-			return err
-		}
-		site.pvMeters = append(site.pvMeters, dev)
-
-		// accumulator
-		site.pvEnergy[ref] = &meterEnergy{clock: clock.New()}
-	}
-
-	// multiple batteries
-	for _, ref := range site.Meters.BatteryMetersRef {
-		dev, err := config.Meters().ByName(ref)
-		if err != nil {
-			return err
-		}
-		site.batteryMeters = append(site.batteryMeters, dev)
+	// meters
+	if err := site.resolveMeters(); err != nil {
+		return err
 	}
 
-	// meters used only for monitoring
-	for _, ref := range site.Meters.ExtMetersRef {
-		dev, err := config.Meters().ByName(ref)
-		if err != nil {
-			return err
-		}
-		site.extMeters = append(site.extMeters, dev)
-	}
-
-	// auxiliary meters
-	for _, ref := range site.Meters.AuxMetersRef {
-		dev, err := config.Meters().ByName(ref)
-		if err != nil {
-			return err
-		}
-		site.auxMeters = append(site.auxMeters, dev)
-	}
+	// apply device configuration changes at runtime
+	config.Meters().Subscribe(func(config.Operation, config.Device[api.Meter]) { site.requestRewire() })
+	config.Chargers().Subscribe(func(config.Operation, config.Device[api.Charger]) { site.requestRewire() })
+	config.Loadpoints().Subscribe(func(config.Operation, config.Device[loadpoint.API]) { site.requestRewire() })
 
 	// revert battery mode on shutdown
 	shutdown.Register(func() {
@@ -269,6 +235,8 @@ func NewSite() *Site {
 		pvEnergy:        make(map[string]*meterEnergy),
 		fcstEnergy:      &meterEnergy{clock: clock.New()},
 		householdEnergy: &meterEnergy{clock: clock.New()},
+		estimator:       newEstimator(clock.New()),
+		rewireChan:      make(chan struct{}, 1),
 	}
 
 	return site
@@ -482,6 +450,15 @@ func (site *Site) DumpConfig() {
 	}
 }
 
+// pushEventError sends push messages including the error details to clients
+func (site *Site) pushEventError(event string, err error) {
+	// site not yet prepared
+	if site.pushChan == nil {
+		return
+	}
+	site.pushChan <- push.Event{Event: event, Error: err.Error()}
+}
+
 // publish sends values to UI and databases
 func (site *Site) publish(key string, val any) {
 	// test helper
@@ -509,13 +486,21 @@ func (site *Site) collectMeters(key string, meters []config.Device[api.Meter]) [
 			}
 			return f, err
 		}, modbus.Backoff())
+
+		var estimated bool
 		if err == nil {
 			site.log.DEBUG.Printf("%s %d power: %.0fW", key, i+1, power)
+			site.estimator.Update(dev.Config().Name, power)
 		} else {
 			if b.Len() > 0 {
 				site.log.ERROR.Println("\n" + b.String())
 			}
 			site.log.ERROR.Printf("%s %d power: %v", key, i+1, err)
+
+			// use decaying last known power instead of zero
+			if power, estimated = site.estimator.Estimate(dev.Config().Name); estimated {
+				site.log.WARN.Printf("%s %d power: %.0fW (estimated)", key, i+1, power)
+			}
 		}
 
 		// energy (production)
@@ -529,10 +514,11 @@ func (site *Site) collectMeters(key string, meters []config.Device[api.Meter]) [
 
 		props := deviceProperties(dev)
 		mm[i] = measurement{
-			Title:  props.Title,
-			Icon:   props.Icon,
-			Power:  power,
-			Energy: energy,
+			Title:     props.Title,
+			Icon:      props.Icon,
+			Power:     power,
+			Energy:    energy,
+			Estimated: estimated,
 		}
 	}
 
@@ -572,26 +558,30 @@ func (site *Site) updatePvMeters() {
 		}
 	}
 
-	site.pvPower = lo.SumBy(mm, func(m measurement) float64 {
+	pvPower := lo.SumBy(mm, func(m measurement) float64 {
 		return max(0, m.Power)
 	})
-	site.excessDCPower = lo.SumBy(mm, func(m measurement) float64 {
+	excessDCPower := lo.SumBy(mm, func(m measurement) float64 {
 		return math.Abs(m.ExcessDCPower)
 	})
 	totalEnergy := lo.SumBy(mm, func(m measurement) float64 {
 		return m.Energy
 	})
 
+	site.Lock()
+	site.pvPower, site.excessDCPower = pvPower, excessDCPower
+	site.Unlock()
+
 	if len(site.pvMeters) > 1 {
 		var excessStr string
-		if site.excessDCPower > 0 {
-			excessStr = fmt.Sprintf(" (includes %.0fW excess DC)", site.excessDCPower)
+		if excessDCPower > 0 {
+			excessStr = fmt.Sprintf(" (includes %.0fW excess DC)", excessDCPower)
 		}
 
-		site.log.DEBUG.Printf("pv power: %.0fW"+excessStr, site.pvPower)
+		site.log.DEBUG.Printf("pv power: %.0fW"+excessStr, pvPower)
 	}
 
-	site.publish(keys.PvPower, site.pvPower)
+	site.publish(keys.PvPower, pvPower)
 	site.publish(keys.PvEnergy, totalEnergy)
 	site.publish(keys.Pv, mm)
 
@@ -600,15 +590,11 @@ func (site *Site) updatePvMeters() {
 		// use stored devices, not ui-updated instances!
 		name := dev.Config().Name
 
-		prev := site.pvEnergy[name].AccumulatedEnergy()
 		if mm[i].Energy > 0 {
-			site.log.DEBUG.Printf("!! solar production: accumulate set %s %.3fkWh meter total (was: %s)", name, mm[i].Energy, site.pvEnergy[name])
 			site.pvEnergy[name].AddMeterTotal(mm[i].Energy)
 		} else {
-			site.log.DEBUG.Printf("!! solar production: accumulate add %s %.3fW power (was: %s)", name, mm[i].Energy, site.pvEnergy[name])
 			site.pvEnergy[name].AddPower(mm[i].Power)
 		}
-		site.log.DEBUG.Printf("!! solar production: accumulate moved %s from %.3f to %.3f", name, prev, site.pvEnergy[name].AccumulatedEnergy())
 	}
 
 	// store
@@ -670,25 +656,28 @@ func (site *Site) updateBatteryMeters() []measurement {
 	if totalCapacity == 0 {
 		totalCapacity = float64(len(site.batteryMeters))
 	}
-	site.batterySoc = batterySocAcc / totalCapacity
-	site.batteryCapacity = totalCapacity
+	batterySoc := batterySocAcc / totalCapacity
 
-	site.batteryPower = lo.SumBy(mm, func(m measurement) float64 {
+	batteryPower := lo.SumBy(mm, func(m measurement) float64 {
 		return m.Power
 	})
 	totalEnergy := lo.SumBy(mm, func(m measurement) float64 {
 		return m.Energy
 	})
 
+	site.Lock()
+	site.batterySoc, site.batteryCapacity, site.batteryPower = batterySoc, totalCapacity, batteryPower
+	site.Unlock()
+
 	if len(site.batteryMeters) > 1 {
-		site.log.DEBUG.Printf("battery power: %.0fW", site.batteryPower)
-		site.log.DEBUG.Printf("battery soc: %.0f%%", math.Round(site.batterySoc))
+		site.log.DEBUG.Printf("battery power: %.0fW", batteryPower)
+		site.log.DEBUG.Printf("battery soc: %.0f%%", math.Round(batterySoc))
 	}
 
-	site.publish(keys.BatteryCapacity, site.batteryCapacity)
-	site.publish(keys.BatterySoc, site.batterySoc)
+	site.publish(keys.BatteryCapacity, totalCapacity)
+	site.publish(keys.BatterySoc, batterySoc)
 
-	site.publish(keys.BatteryPower, site.batteryPower)
+	site.publish(keys.BatteryPower, batteryPower)
 	site.publish(keys.BatteryEnergy, totalEnergy)
 	site.publish(keys.Battery, mm)
 
@@ -732,14 +721,40 @@ func (site *Site) updateGridMeter() error {
 
 	var mm measurement
 
-	if res, err := backoff.RetryWithData(site.gridMeter.CurrentPower, modbus.Backoff()); err == nil {
-		mm.Power = res
-		site.gridPower = res
-		site.log.DEBUG.Printf("grid power: %.0fW", res)
-	} else {
-		return fmt.Errorf("grid power: %v", err)
+	res, err := backoff.RetryWithData(site.gridMeter.CurrentPower, modbus.Backoff())
+	if err != nil {
+		err = fmt.Errorf("grid power: %v", err)
+
+		// grid power can be estimated from pv production and last known home power
+		var ok bool
+		if len(site.pvMeters) > 0 {
+			_, ok = site.estimator.Estimate(site.Meters.GridMeterRef)
+		}
+
+		if !ok {
+			site.gridEstimated = false
+			return err
+		}
+
+		site.log.ERROR.Println(err)
+		if !site.gridEstimated {
+			site.log.WARN.Println("grid meter unavailable, estimating grid power")
+		}
+
+		site.gridEstimated = true
+		return nil
 	}
 
+	mm.Power = res
+
+	site.Lock()
+	site.gridPower = res
+	site.Unlock()
+
+	site.gridEstimated = false
+	site.estimator.Update(site.Meters.GridMeterRef, res)
+	site.log.DEBUG.Printf("grid power: %.0fW", res)
+
 	// grid phase currents (signed)
 	if phaseMeter, ok := site.gridMeter.(api.PhaseCurrents); ok {
 		// grid phase powers
@@ -830,10 +845,14 @@ func (site *Site) updateHomeConsumption(homePower float64) {
 //   - the net power exported by the site minus a residual margin
 //     (negative values mean grid: export, battery: charging
 //   - if battery buffer can be used for charging
+//
+// Meters must have been updated before.
 func (site *Site) sitePower(totalChargePower, flexiblePower float64) (float64, bool, bool, error) {
-	if err := site.updateMeters(); err != nil {
-		return 0, false, false, err
-	}
+	// ensure safe default for residual power
+	residualPower := site.GetResidualPower()
+
+	site.Lock()
+	defer site.Unlock()
 
 	// allow using PV as estimate for grid power
 	if site.gridMeter == nil {
@@ -841,8 +860,13 @@ func (site *Site) sitePower(totalChargePower, flexiblePower float64) (float64, b
 		site.publish(keys.Grid, measurement{Power: site.gridPower})
 	}
 
-	// ensure safe default for residual power
-	residualPower := site.GetResidualPower()
+	// assume unchanged home power while grid meter is unavailable
+	if site.gridEstimated {
+		site.gridPower = site.homePower + totalChargePower - site.pvPower - site.batteryPower
+		site.log.DEBUG.Printf("grid power: %.0fW (estimated)", site.gridPower)
+		site.publish(keys.Grid, measurement{Power: site.gridPower, Estimated: true})
+	}
+
 	if len(site.batteryMeters) > 0 && site.batterySoc < site.prioritySoc && residualPower <= 0 {
 		residualPower = 100 // Wsite.publish(keys.PvPower,
 	}
@@ -865,9 +889,6 @@ func (site *Site) sitePower(totalChargePower, flexiblePower float64) (float64, b
 	var batteryBuffered, batteryStart bool
 
 	if len(site.batteryMeters) > 0 {
-		site.RLock()
-		defer site.RUnlock()
-
 		// if battery is charging below prioritySoc give it priority
 		if site.batterySoc < site.prioritySoc && batteryPower < 0 {
 			site.log.DEBUG.Printf("battery has priority at soc %.0f%% (< %.0f%%)", site.batterySoc, site.prioritySoc)
@@ -930,9 +951,16 @@ func (site *Site) update(lp updater) {
 		site.log.WARN.Println("feed-in:", err)
 	}
 
+	// read site meters while updating loadpoints, both are independent device reads
+	metersC := make(chan error, 1)
+	go func() { metersC <- site.updateMeters() }()
+
 	// update loadpoints
 	totalChargePower := site.updateLoadpoints(consumption)
 
+	// circuit meters are read after site meters, devices may be shared
+	err = <-metersC
+
 	// update all circuits' power and currents
 	if site.circuit != nil {
 		if err := site.circuit.Update(site.loadpointsAsCircuitDevices()); err != nil {
@@ -952,29 +980,43 @@ func (site *Site) update(lp updater) {
 		flexiblePower = site.prioritizer.GetChargePowerFlexibility(lp)
 	}
 
-	if sitePower, batteryBuffered, batteryStart, err := site.sitePower(totalChargePower, flexiblePower); err == nil {
+	var sitePower float64
+	var batteryBuffered, batteryStart bool
+	if err == nil {
+		sitePower, batteryBuffered, batteryStart, err = site.sitePower(totalChargePower, flexiblePower)
+	}
+
+	if err == nil {
+		site.RLock()
+		gridPower, pvPower, batteryPower := site.gridPower, site.pvPower, site.batteryPower
+		site.RUnlock()
+
 		// ignore negative pvPower values as that means it is not an energy source but consumption
-		homePower := site.gridPower + max(0, site.pvPower) + site.batteryPower - totalChargePower
+		homePower := gridPower + max(0, pvPower) + batteryPower - totalChargePower
 		homePower = max(homePower, 0)
+		site.homePower = homePower
 		site.publish(keys.HomePower, homePower)
+		site.publish(keys.Estimated, site.gridEstimated || site.estimator.Estimating())
 
-		if homePower > 0 {
+		// don't record estimated consumption
+		if homePower > 0 && !site.gridEstimated {
 			site.updateHomeConsumption(homePower)
 		}
 
 		// add battery charging power to homePower to ignore all consumption which does not occur on loadpoints
 		// fix for: https://github.com/evcc-io/evcc/issues/11032
-		nonChargePower := homePower + max(0, -site.batteryPower)
+		nonChargePower := homePower + max(0, -batteryPower)
 		greenShareHome := site.greenShare(0, homePower)
 		greenShareLoadpoints := site.greenShare(nonChargePower, nonChargePower+totalChargePower)
 
 		// TODO
 		lp.Update(
-			sitePower, max(0, site.batteryPower), consumption, feedin, batteryBuffered, batteryStart,
+			sitePower, max(0, batteryPower), consumption, feedin, batteryBuffered, batteryStart,
 			greenShareLoadpoints, site.effectivePrice(greenShareLoadpoints), site.effectiveCo2(greenShareLoadpoints),
 		)
 
 		site.Health.Update()
+		site.meterOutage = false
 
 		site.publishTariffs(greenShareHome, greenShareLoadpoints)
 
@@ -983,6 +1025,12 @@ func (site *Site) update(lp updater) {
 		}
 	} else {
 		site.log.ERROR.Println(err)
+
+		// notify once per outage
+		if !site.meterOutage {
+			site.meterOutage = true
+			site.pushEventError(evMeterOutage, err)
+		}
 	}
 
 	// smart grid charging
@@ -1040,6 +1088,7 @@ func (site *Site) prepare() {
 	site.publishVehicles()
 	site.publishTariffs(0, 0)
 	vehicle.Publish = site.publishVehicles
+	vehicle.PlanCreated = site.vehiclePlanCreated
 }
 
 // Prepare attaches communication channels to site and loadpoints
@@ -1052,6 +1101,7 @@ func (site *Site) Prepare(uiChan chan<- util.Param, pushChan chan<- push.Event)
 
 	// use ch.In for writing
 	site.uiChan = ch.In
+	site.uiQueue = ch
 
 	// use ch.Out for reading
 	go func() {
@@ -1060,6 +1110,7 @@ func (site *Site) Prepare(uiChan chan<- util.Param, pushChan chan<- push.Event)
 		}
 	}()
 
+	site.pushChan = pushChan
 	site.lpUpdateChan = make(chan *Loadpoint, 1) // 1 capacity to avoid deadlock
 
 	site.prepare()
@@ -1109,14 +1160,29 @@ func (site *Site) Run(stopC chan struct{}, interval time.Duration) {
 		go site.loopLoadpoints(loadpointChan)
 	}
 
-	site.update(<-loadpointChan) // start immediately
+	// update warns if the cycle exceeds the interval, e.g. due to slow devices
+	update := func(lp updater) {
+		start := time.Now()
+		site.update(lp)
+
+		d := time.Since(start)
+		site.Health.Cycle(d)
+
+		if d > interval {
+			site.log.WARN.Printf("update took %v, exceeding interval %v", d.Round(time.Millisecond), interval)
+		}
+	}
+
+	update(<-loadpointChan) // start immediately
 
 	for tick := time.Tick(interval); ; {
 		select {
 		case <-tick:
-			site.update(<-loadpointChan)
+			update(<-loadpointChan)
 		case lp := <-site.lpUpdateChan:
-			site.update(lp)
+			update(lp)
+		case <-site.rewireChan:
+			site.rewire()
 		case <-stopC:
 			return
 		}
@@ -66,6 +66,7 @@ func (site *Site) SetGridMeterRef(ref string) {
 
 	site.Meters.GridMeterRef = ref
 	settings.SetString(keys.GridMeter, ref)
+	site.requestRewire()
 }
 
 // GetPVMeterRefs returns the PvMeterRef
@@ -82,6 +83,7 @@ func (site *Site) SetPVMeterRefs(ref []string) {
 
 	site.Meters.PVMetersRef = ref
 	settings.SetString(keys.PvMeters, strings.Join(filterConfigurable(ref), ","))
+	site.requestRewire()
 }
 
 // GetBatteryMeterRefs returns the BatteryMeterRef
@@ -98,6 +100,7 @@ func (site *Site) SetBatteryMeterRefs(ref []string) {
 
 	site.Meters.BatteryMetersRef = ref
 	settings.SetString(keys.BatteryMeters, strings.Join(filterConfigurable(ref), ","))
+	site.requestRewire()
 }
 
 // GetAuxMeterRefs returns the AuxMeterRef
@@ -114,6 +117,7 @@ func (site *Site) SetAuxMeterRefs(ref []string) {
 
 	site.Meters.AuxMetersRef = ref
 	settings.SetString(keys.AuxMeters, strings.Join(filterConfigurable(ref), ","))
+	site.requestRewire()
 }
 
 // GetExtMeterRefs returns the ExtMeterRef
@@ -130,6 +134,7 @@ func (site *Site) SetExtMeterRefs(ref []string) {
 
 	site.Meters.ExtMetersRef = ref
 	settings.SetString(keys.ExtMeters, strings.Join(filterConfigurable(ref), ","))
+	site.requestRewire()
 }
 
 // Loadpoints returns the loadpoints as api interfaces
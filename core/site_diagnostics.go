@@ -0,0 +1,29 @@
+package core
+
+import "github.com/evcc-io/evcc/util/supervisor"
+
+// SiteDiagnostics contains the control loop state for supportability
+type SiteDiagnostics struct {
+	Loop    HealthStatus        `json:"loop"`
+	Queues  map[string]int      `json:"queues"`
+	Devices []supervisor.Status `json:"devices"`
+}
+
+// Diagnostics returns the control loop health, message queue depths and supervised device tasks
+func (site *Site) Diagnostics() SiteDiagnostics {
+	res := SiteDiagnostics{
+		Loop:    site.Health.Status(),
+		Devices: supervisor.Default.Status(),
+		Queues: map[string]int{
+			"loadpoint": len(site.lpUpdateChan),
+			"push":      len(site.pushChan),
+			"rewire":    len(site.rewireChan),
+		},
+	}
+
+	if site.uiQueue != nil {
+		res.Queues["ui"] = site.uiQueue.Len()
+	}
+
+	return res
+}
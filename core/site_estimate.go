@@ -0,0 +1,74 @@
+package core
+
+import (
+	"math"
+	"sync"
+	"time"
+
+	"github.com/benbjohnson/clock"
+)
+
+const (
+	// maxEstimateAge is the maximum duration meter values are estimated after a meter became unavailable
+	maxEstimateAge = 10 * time.Minute
+
+	// estimateDecay is the time constant of the exponential decay applied to estimated meter power
+	estimateDecay = 5 * time.Minute
+)
+
+type reading struct {
+	power   float64
+	updated time.Time
+}
+
+// estimator substitutes readings of unavailable meters by their last known values
+type estimator struct {
+	mu         sync.Mutex
+	clock      clock.Clock
+	last       map[string]reading
+	estimating map[string]bool
+}
+
+func newEstimator(clock clock.Clock) *estimator {
+	return &estimator{
+		clock:      clock,
+		last:       make(map[string]reading),
+		estimating: make(map[string]bool),
+	}
+}
+
+// Update stores the meter's current power
+func (e *estimator) Update(name string, power float64) {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	e.last[name] = reading{power: power, updated: e.clock.Now()}
+	delete(e.estimating, name)
+}
+
+// Estimate returns the meter's last known power decaying towards zero.
+// It returns false if the last reading is missing or exceeds the maximum age.
+func (e *estimator) Estimate(name string) (float64, bool) {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	r, ok := e.last[name]
+	age := e.clock.Since(r.updated)
+
+	if !ok || age > maxEstimateAge {
+		delete(e.estimating, name)
+		return 0, false
+	}
+
+	e.estimating[name] = true
+
+	return r.power * math.Exp(-float64(age)/float64(estimateDecay)), true
+}
+
+// Estimating returns true if any meter is currently estimated
+func (e *estimator) Estimating() bool {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	return len(e.estimating) > 0
+}
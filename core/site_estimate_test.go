@@ -0,0 +1,77 @@
+package core
+
+import (
+	"errors"
+	"math"
+	"testing"
+	"time"
+
+	"github.com/benbjohnson/clock"
+	"github.com/cenkalti/backoff/v4"
+	"github.com/evcc-io/evcc/api"
+	"github.com/evcc-io/evcc/util"
+	"github.com/evcc-io/evcc/util/config"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
+)
+
+func TestEstimator(t *testing.T) {
+	clock := clock.NewMock()
+	e := newEstimator(clock)
+
+	_, ok := e.Estimate("pv")
+	assert.False(t, ok, "no reading")
+
+	e.Update("pv", 1000)
+	assert.False(t, e.Estimating())
+
+	clock.Add(estimateDecay)
+	power, ok := e.Estimate("pv")
+	assert.True(t, ok)
+	assert.InDelta(t, 1000/math.E, power, 1e-6)
+	assert.True(t, e.Estimating())
+
+	e.Update("pv", 2000)
+	assert.False(t, e.Estimating())
+
+	clock.Add(maxEstimateAge + time.Second)
+	_, ok = e.Estimate("pv")
+	assert.False(t, ok, "reading too old")
+	assert.False(t, e.Estimating())
+}
+
+func TestGridEstimation(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	clock := clock.NewMock()
+
+	grid := api.NewMockMeter(ctrl)
+	pv := api.NewMockMeter(ctrl)
+
+	s := &Site{
+		log:       util.NewLogger("foo"),
+		gridMeter: grid,
+		pvMeters:  []config.Device[api.Meter]{config.NewStaticDevice[api.Meter](config.Named{Name: "pv"}, pv)},
+		estimator: newEstimator(clock),
+		homePower: 500,
+	}
+
+	grid.EXPECT().CurrentPower().Return(-2000.0, nil)
+	require.NoError(t, s.updateGridMeter())
+	assert.False(t, s.gridEstimated)
+
+	grid.EXPECT().CurrentPower().Return(0.0, backoff.Permanent(errors.New("offline"))).Times(2)
+
+	require.NoError(t, s.updateGridMeter())
+	assert.True(t, s.gridEstimated)
+
+	// surplus derived from pv production and last known home power
+	s.pvPower = 3000
+	_, _, _, err := s.sitePower(1000, 0)
+	require.NoError(t, err)
+	assert.Equal(t, -1500.0, s.gridPower)
+
+	clock.Add(maxEstimateAge + time.Second)
+	require.Error(t, s.updateGridMeter())
+	assert.False(t, s.gridEstimated)
+}
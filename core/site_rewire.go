@@ -0,0 +1,108 @@
+package core
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/benbjohnson/clock"
+	"github.com/evcc-io/evcc/api"
+	"github.com/evcc-io/evcc/util/config"
+)
+
+// requestRewire requests re-resolving device references from within the control loop
+func (site *Site) requestRewire() {
+	select {
+	case site.rewireChan <- struct{}{}:
+	default:
+	}
+}
+
+// rewire applies device configuration changes to the running site and loadpoints
+func (site *Site) rewire() {
+	site.Lock()
+	err := site.resolveMeters()
+	site.Unlock()
+
+	if err != nil {
+		site.log.ERROR.Println("meters:", err)
+	}
+
+	for _, lp := range site.loadpoints {
+		if err := lp.rewire(); err != nil {
+			lp.log.ERROR.Println(err)
+		}
+	}
+}
+
+// ReloadMeters applies meter references from the configuration file. Other than the
+// setters, references are not persisted as settings.
+func (site *Site) ReloadMeters(meters MetersConfig) {
+	site.Lock()
+	defer site.Unlock()
+
+	site.Meters = meters
+	site.requestRewire()
+}
+
+// resolveMeters resolves the site's meter references. Meters are only replaced if all references are valid.
+func (site *Site) resolveMeters() error {
+	var gridMeter api.Meter
+
+	if ref := site.Meters.GridMeterRef; ref != "" {
+		dev, err := config.Meters().ByName(ref)
+		if err != nil {
+			return fmt.Errorf("grid meter: %w", err)
+		}
+		gridMeter = dev.Instance()
+		if gridMeter == nil {
+			return errors.New("missing grid meter instance")
+		}
+	}
+
+	resolve := func(refs []string) ([]config.Device[api.Meter], error) {
+		var res []config.Device[api.Meter]
+		for _, ref := range refs {
+			dev, err := config.Meters().ByName(ref)
+			if err != nil {
+				return nil, err
+			}
+			res = append(res, dev)
+		}
+		return res, nil
+	}
+
+	pvMeters, err := resolve(site.Meters.PVMetersRef)
+	if err != nil {
+		return fmt.Errorf("pv meter: %w", err)
+	}
+
+	batteryMeters, err := resolve(site.Meters.BatteryMetersRef)
+	if err != nil {
+		return fmt.Errorf("battery meter: %w", err)
+	}
+
+	extMeters, err := resolve(site.Meters.ExtMetersRef)
+	if err != nil {
+		return fmt.Errorf("ext meter: %w", err)
+	}
+
+	auxMeters, err := resolve(site.Meters.AuxMetersRef)
+	if err != nil {
+		return fmt.Errorf("aux meter: %w", err)
+	}
+
+	site.gridMeter = gridMeter
+	site.pvMeters = pvMeters
+	site.batteryMeters = batteryMeters
+	site.extMeters = extMeters
+	site.auxMeters = auxMeters
+
+	// accumulator
+	for _, ref := range site.Meters.PVMetersRef {
+		if _, ok := site.pvEnergy[ref]; !ok {
+			site.pvEnergy[ref] = &meterEnergy{clock: clock.New()}
+		}
+	}
+
+	return nil
+}
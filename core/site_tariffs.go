@@ -32,7 +32,10 @@ type dailyDetails struct {
 //   - the current green share, calculated for the part of the consumption between powerFrom and powerTo
 //     the consumption below powerFrom will get the available green power first
 func (site *Site) greenShare(powerFrom float64, powerTo float64) float64 {
+	site.RLock()
 	greenPower := math.Max(0, site.pvPower) + math.Max(0, site.batteryPower)
+	site.RUnlock()
+
 	greenPowerAvailable := math.Max(0, greenPower-powerFrom)
 
 	power := powerTo - powerFrom
@@ -12,6 +12,7 @@ import (
 	"github.com/evcc-io/evcc/util/config"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
 )
 
 func TestGreenShare(t *testing.T) {
@@ -192,3 +193,42 @@ func TestUpdateHomeConsumption(t *testing.T) {
 	s.updateHomeConsumption(1e3)
 	require.Equal(t, 0.0, s.householdEnergy.AccumulatedEnergy()) // accumulator reset after 15 minutes
 }
+
+// TestSiteMetersConcurrent is meant to be run with -race
+func TestSiteMetersConcurrent(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	clock := clock.NewMock()
+
+	grid := api.NewMockMeter(ctrl)
+	grid.EXPECT().CurrentPower().Return(-1000.0, nil).AnyTimes()
+
+	pv := api.NewMockMeter(ctrl)
+	pv.EXPECT().CurrentPower().Return(3000.0, nil).AnyTimes()
+
+	s := &Site{
+		log:       util.NewLogger("foo"),
+		gridMeter: grid,
+		pvMeters:  []config.Device[api.Meter]{config.NewStaticDevice[api.Meter](config.Named{Name: "pv"}, pv)},
+		pvEnergy:  map[string]*meterEnergy{"pv": {clock: clock}},
+		estimator: newEstimator(clock),
+	}
+
+	// site meters are read while loadpoints update
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		for range 10 {
+			assert.NoError(t, s.updateMeters())
+		}
+	}()
+
+	for range 10 {
+		s.greenShare(0, 1000)
+	}
+	<-done
+
+	sitePower, _, _, err := s.sitePower(0, 0)
+	require.NoError(t, err)
+	assert.Equal(t, -1000.0, sitePower)
+	assert.Equal(t, 1.0, s.greenShare(0, 1000))
+}
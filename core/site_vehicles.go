@@ -1,6 +1,7 @@
 package core
 
 import (
+	"slices"
 	"time"
 
 	"github.com/evcc-io/evcc/api"
@@ -74,6 +75,15 @@ func (site *Site) publishVehicles() {
 	site.publish(keys.Vehicles, res)
 }
 
+// vehiclePlanCreated notifies the loadpoints of the named vehicle about its new charging plan
+func (site *Site) vehiclePlanCreated(name string) {
+	for _, lp := range site.loadpoints {
+		if v := lp.GetVehicle(); v != nil && vehicle.Settings(lp.log, v).Name() == name {
+			lp.pushEvent(evPlanCreated)
+		}
+	}
+}
+
 // updateVehicles adds or removes a vehicle asynchronously
 func (site *Site) updateVehicles(op config.Operation, dev config.Device[api.Vehicle]) {
 	vehicle := dev.Instance()
@@ -84,8 +94,23 @@ func (site *Site) updateVehicles(op config.Operation, dev config.Device[api.Vehi
 
 	case config.OpDelete:
 		site.coordinator.Delete(vehicle)
+
+	case config.OpUpdate:
+		// replace previous instance
+		instances := config.Instances(config.Vehicles().Devices())
+		for _, v := range site.coordinator.GetVehicles(false) {
+			if !slices.Contains(instances, v) {
+				site.coordinator.Delete(v)
+			}
+		}
+		if !slices.Contains(site.coordinator.GetVehicles(false), vehicle) {
+			site.coordinator.Add(vehicle)
+		}
 	}
 
+	// update default vehicles
+	site.requestRewire()
+
 	// TODO remove vehicle from mqtt
 	site.publishVehicles()
 }
@@ -16,6 +16,9 @@ var _ API = (*adapter)(nil)
 // Publish publishes vehicle updates at site level
 var Publish func()
 
+// PlanCreated notifies the site that a vehicle charging plan has been created
+var PlanCreated func(name string)
+
 type adapter struct {
 	log         *util.Logger
 	name        string
@@ -107,6 +110,10 @@ func (v *adapter) SetPlanSoc(ts time.Time, precondition time.Duration, soc int)
 
 	v.publish()
 
+	if soc > 0 && PlanCreated != nil {
+		PlanCreated(v.name)
+	}
+
 	return nil
 }
 
@@ -106,6 +106,30 @@ func (cr *ChargeRater) ResetCharge() {
 	cr.start = cr.clck.Now()
 }
 
+// SetMeter replaces the meter. Energy charged so far is retained.
+func (cr *ChargeRater) SetMeter(meter api.Meter) {
+	cr.Lock()
+	defer cr.Unlock()
+
+	if cr.charging {
+		if m, ok := cr.meter.(api.MeterEnergy); ok {
+			if f, err := m.TotalEnergy(); err == nil {
+				cr.chargedEnergy += f - cr.startEnergy
+			}
+		}
+
+		if m, ok := meter.(api.MeterEnergy); ok {
+			if f, err := m.TotalEnergy(); err == nil {
+				cr.startEnergy = f
+			}
+		}
+
+		cr.start = cr.clck.Now()
+	}
+
+	cr.meter = meter
+}
+
 // SetChargePower increments consumed energy by amount in kWh since last update
 func (cr *ChargeRater) SetChargePower(power float64) {
 	cr.Lock()
@@ -110,3 +110,35 @@ func TestWrappedMeter(t *testing.T) {
 		t.Errorf("energy: %.1f %v", f, err)
 	}
 }
+
+func TestReplacedMeter(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	type EnergyDecorator struct {
+		api.Meter
+		api.MeterEnergy
+	}
+
+	me1 := api.NewMockMeterEnergy(ctrl)
+	me2 := api.NewMockMeterEnergy(ctrl)
+
+	cr := NewChargeRater(util.NewLogger("foo"), &EnergyDecorator{MeterEnergy: me1})
+	clck := clock.NewMock()
+	cr.clck = clck
+
+	me1.EXPECT().TotalEnergy().Return(2.0, nil)
+	cr.StartCharge(true)
+
+	// 1kWh charged on old meter
+	me1.EXPECT().TotalEnergy().Return(3.0, nil)
+	me2.EXPECT().TotalEnergy().Return(100.0, nil)
+	cr.SetMeter(&EnergyDecorator{MeterEnergy: me2})
+
+	// 2kWh charged on new meter
+	me2.EXPECT().TotalEnergy().Return(102.0, nil)
+
+	if f, err := cr.ChargedEnergy(); f != 3 || err != nil {
+		t.Errorf("energy: %.1f %v", f, err)
+	}
+}
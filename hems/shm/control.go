@@ -0,0 +1,80 @@
+package shm
+
+import (
+	"sync"
+	"time"
+
+	"github.com/evcc-io/evcc/api"
+	"github.com/evcc-io/evcc/core/site"
+	"github.com/evcc-io/evcc/util"
+)
+
+// recommendationTimeout releases control if the energy manager stops sending recommendations
+const recommendationTimeout = 5 * time.Minute
+
+type recommendation struct {
+	on      bool
+	power   float64
+	updated time.Time
+}
+
+// control limits loadpoint power to the energy manager's recommendations.
+// Recommendations are applied to loadpoints in PV modes only, leaving user requests for fast charging untouched.
+type control struct {
+	mu   sync.Mutex
+	log  *util.Logger
+	site site.API
+	recs map[int]recommendation
+}
+
+func newControl(log *util.Logger, site site.API) *control {
+	return &control{
+		log:  log,
+		site: site,
+		recs: make(map[int]recommendation),
+	}
+}
+
+func (c *control) recommend(id int, on bool, power float64) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.log.DEBUG.Printf("recommendation: loadpoint %d on: %t power: %.0fW", id+1, on, power)
+
+	c.recs[id] = recommendation{on: on, power: power, updated: time.Now()}
+}
+
+// apply limits each loadpoint's power to its current recommendation
+func (c *control) apply() {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	for id, lp := range c.site.Loadpoints() {
+		rec, ok := c.recs[id]
+		if ok && time.Since(rec.updated) > recommendationTimeout {
+			delete(c.recs, id)
+			ok = false
+		}
+
+		mode := lp.GetMode()
+		if !ok || (mode != api.ModePV && mode != api.ModeMinPV) {
+			lp.SetPowerLimit(0)
+			continue
+		}
+
+		// zero removes the limit, use minimal limit instead to stop charging
+		limit := 1.0
+		if rec.on {
+			limit = max(rec.power, limit)
+		}
+
+		lp.SetPowerLimit(limit)
+	}
+}
+
+// run releases expired recommendations
+func (c *control) run() {
+	for range time.Tick(time.Minute) {
+		c.apply()
+	}
+}
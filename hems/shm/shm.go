@@ -38,12 +38,14 @@ type SEMP struct {
 	uid  string
 	uri  string
 	site site.API
+
+	control *control // energy manager recommendations, nil if not allowed
 }
 
 type Config struct {
-	AllowControl_ bool   `json:"allowControl,omitempty"` // deprecated
-	VendorId      string `json:"vendorId"`
-	DeviceId      string `json:"deviceId"`
+	AllowControl bool   `json:"allowControl,omitempty"` // follow energy manager recommendations
+	VendorId     string `json:"vendorId"`
+	DeviceId     string `json:"deviceId"`
 }
 
 // NewFromConfig creates a new SEMP instance from configuration and starts it
@@ -84,6 +86,10 @@ func NewFromConfig(cfg Config, hostUri string, site site.API, addr string, route
 		uri:  hostUri,
 	}
 
+	if cfg.AllowControl {
+		s.control = newControl(s.log, site)
+	}
+
 	s.handlers(router)
 
 	go s.run()
@@ -113,6 +119,10 @@ func (s *SEMP) run() {
 		ads = append(ads, ad)
 	}
 
+	if s.control != nil {
+		go s.control.run()
+	}
+
 	for range time.Tick(maxAge * time.Second / 2) {
 		for _, ad := range ads {
 			if err := ad.Alive(); err != nil {
@@ -291,6 +301,17 @@ func (s *SEMP) deviceID(id int) string {
 	return fmt.Sprintf(sempDeviceId, s.vid, ^uint64(0xffff<<48)&(binary.BigEndian.Uint64(did)+uint64(id)))
 }
 
+// loadpointID returns the loadpoint id for the device id
+func (s *SEMP) loadpointID(did string) (int, bool) {
+	for id := range s.site.Loadpoints() {
+		if did == s.deviceID(id) {
+			return id, true
+		}
+	}
+
+	return 0, false
+}
+
 func (s *SEMP) deviceInfo(id int, lp loadpoint.API) DeviceInfo {
 	method := MethodEstimation
 	if lp.HasChargeMeter() {
@@ -339,7 +360,7 @@ func (s *SEMP) deviceStatus(id int, lp loadpoint.API) DeviceStatus {
 
 	res := DeviceStatus{
 		DeviceID:          s.deviceID(id),
-		EMSignalsAccepted: false,
+		EMSignalsAccepted: s.control != nil,
 		PowerInfo: PowerInfo{
 			AveragePower:      int(chargePower),
 			AveragingInterval: 60,
@@ -420,12 +441,31 @@ func (s *SEMP) allPlanningRequest() (res []PlanningRequest) {
 func (s *SEMP) deviceControlHandler(w http.ResponseWriter, r *http.Request) {
 	var msg EM2Device
 
-	if err := xml.NewDecoder(r.Body).Decode(&msg); err == nil {
-		s.log.TRACE.Printf("recv: %+v", msg)
-	} else {
-		s.log.ERROR.Printf("recv: %+v", msg)
+	if err := xml.NewDecoder(r.Body).Decode(&msg); err != nil {
+		s.log.ERROR.Printf("recv: %v", err)
+		w.WriteHeader(http.StatusBadRequest)
+		return
 	}
 
+	s.log.TRACE.Printf("recv: %+v", msg)
+
 	// ignore control requests
-	w.WriteHeader(http.StatusBadRequest)
+	if s.control == nil {
+		w.WriteHeader(http.StatusBadRequest)
+		return
+	}
+
+	for _, dc := range msg.DeviceControl {
+		id, ok := s.loadpointID(dc.DeviceID)
+		if !ok {
+			s.log.WARN.Printf("recv: unknown device id %s", dc.DeviceID)
+			continue
+		}
+
+		s.control.recommend(id, dc.On, dc.RecommendedPowerConsumption)
+	}
+
+	s.control.apply()
+
+	w.WriteHeader(http.StatusOK)
 }
@@ -2,14 +2,12 @@ package goodwe
 
 import (
 	"encoding/binary"
-	"maps"
 	"net"
-	"slices"
 	"sync"
 	"time"
 
-	"github.com/cenkalti/backoff/v4"
 	"github.com/evcc-io/evcc/util"
+	"github.com/evcc-io/evcc/util/scheduler"
 )
 
 var (
@@ -41,7 +39,6 @@ func Instance(log *util.Logger) (*Server, error) {
 	}
 
 	go instance.listen()
-	go instance.readData()
 
 	return instance, nil
 }
@@ -51,6 +48,9 @@ func (m *Server) AddInverter(ip string, timeout time.Duration) *util.Monitor[Inv
 	defer mu.Unlock()
 	monitor := util.NewMonitor[Inverter](timeout)
 	m.inverters[ip] = monitor
+
+	go m.poll(ip)
+
 	return monitor
 }
 
@@ -60,32 +60,27 @@ func (m *Server) GetInverter(ip string) *util.Monitor[Inverter] {
 	return m.inverters[ip]
 }
 
-func (m *Server) readData() {
-	bo := backoff.NewExponentialBackOff(
-		backoff.WithMaxInterval(time.Second),
-		backoff.WithMaxElapsedTime(10*time.Second))
+// poll alternately requests running data and battery soc from the inverter
+func (m *Server) poll(ip string) {
+	frames := [][]byte{
+		{0xF7, 0x03, 0x89, 0x1C, 0x00, 0x7D, 0x7A, 0xE7},
+		{0xF7, 0x03, 0x90, 0x88, 0x00, 0x0D, 0x3D, 0xB3},
+	}
 
-	for {
-		mu.RLock()
-		ips := slices.Collect(maps.Keys(m.inverters))
-		mu.RUnlock()
-
-		for _, ip := range ips {
-			if err := backoff.Retry(func() error {
-				addr, err := net.ResolveUDPAddr("udp", net.JoinHostPort(ip, "8899"))
-				if err == nil {
-					_, err = m.conn.WriteToUDP([]byte{0xF7, 0x03, 0x89, 0x1C, 0x00, 0x7D, 0x7A, 0xE7}, addr)
-				}
-				if err == nil {
-					time.Sleep(5 * time.Second)
-					_, err = m.conn.WriteToUDP([]byte{0xF7, 0x03, 0x90, 0x88, 0x00, 0x0D, 0x3D, 0xB3}, addr)
-				}
-				return err
-			}, bo); err != nil {
-				m.log.ERROR.Println(err)
-			}
+	var frame int
+	scheduler.Run(ip, 5*time.Second, func() error {
+		addr, err := net.ResolveUDPAddr("udp", net.JoinHostPort(ip, "8899"))
+		if err == nil {
+			_, err = m.conn.WriteToUDP(frames[frame], addr)
 		}
-	}
+		if err != nil {
+			m.log.ERROR.Println(err)
+			return err
+		}
+
+		frame = (frame + 1) % len(frames)
+		return nil
+	})
 }
 
 func (m *Server) listen() {
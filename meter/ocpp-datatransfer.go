@@ -0,0 +1,512 @@
+package meter
+
+import (
+	"cmp"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/benbjohnson/clock"
+	"github.com/evcc-io/evcc/api"
+	"github.com/evcc-io/evcc/charger/ocpp"
+	"github.com/evcc-io/evcc/util"
+)
+
+// OCPPDataTransferMeter is a meter fed by vendor specific OCPP DataTransfer messages,
+// e.g. the CT clamp readings sent by MasterPlug charge points.
+type OCPPDataTransferMeter struct {
+	mu        sync.RWMutex
+	units     masterplugUnits
+	connector int
+	phases    int
+	channel   string
+	scale     float64
+	invert    bool
+
+	clock    clock.Clock
+	maxAge   time.Duration
+	staleErr error
+	updated  time.Time
+	data     *dataTransferReading
+}
+
+// dataTransferStaleErrors are the configurable errors returned for outdated readings
+var dataTransferStaleErrors = map[string]error{
+	"timeout":      api.ErrTimeout,
+	"outdated":     api.ErrOutdated,
+	"notavailable": api.ErrNotAvailable,
+}
+
+// dataTransferReading is the decoded measurement. Optional values are nil if not sent by the charge point.
+type dataTransferReading struct {
+	Power     float64
+	Energy    *float64 // kWh
+	Frequency *float64
+	Currents  []float64
+	Voltages  []float64
+	Powers    []float64
+}
+
+var (
+	dataTransferMetersMu sync.Mutex
+	dataTransferMeters   = make(map[string][]*OCPPDataTransferMeter)
+)
+
+func init() {
+	registry.Add("ocppdatatransfer", NewOCPPDataTransferMeterFromConfig)
+
+	ocpp.RegisterDataTransferHandler("MasterPlug", "", masterplugHandler)
+}
+
+// masterplugHandler updates the charge point's meters
+func masterplugHandler(id string, data json.RawMessage) (any, error) {
+	dataTransferMetersMu.Lock()
+	meters := dataTransferMeters[id]
+	dataTransferMetersMu.Unlock()
+
+	if len(meters) == 0 {
+		return nil, nil
+	}
+
+	msg, err := masterplugPayload(data)
+	if err != nil {
+		return nil, err
+	}
+
+	var errs []error
+	for _, m := range meters {
+		// meters of other connectors
+		if m.connector != 0 && m.connector != msg.ConnectorId {
+			continue
+		}
+
+		res, err := msg.reading(m.units, m.phases, m.channel)
+		if err != nil {
+			errs = append(errs, err)
+			continue
+		}
+
+		m.update(res)
+	}
+
+	return nil, errors.Join(errs...)
+}
+
+// NewOCPPDataTransferMeterFromConfig creates a DataTransfer meter from generic config
+func NewOCPPDataTransferMeterFromConfig(other map[string]any) (api.Meter, error) {
+	cc := struct {
+		StationId string
+		Connector int
+		Units     masterplugUnits
+		Heuristic bool
+		Phases    int
+		Channel   string
+		Scale     float64
+		Invert    bool
+		MaxAge    time.Duration
+		Stale     string
+	}{
+		Scale:  1,
+		MaxAge: 2 * time.Minute,
+		Stale:  "timeout",
+		Units: masterplugUnits{
+			Current: "A",
+			Voltage: "V",
+			Power:   "W",
+			Energy:  "Wh",
+		},
+	}
+
+	if err := util.DecodeOther(other, &cc); err != nil {
+		return nil, err
+	}
+
+	if cc.StationId == "" {
+		return nil, errors.New("missing stationid")
+	}
+
+	cc.Units.Heuristic = cc.Heuristic
+
+	staleErr, ok := dataTransferStaleErrors[strings.ToLower(cc.Stale)]
+	if !ok {
+		return nil, fmt.Errorf("invalid stale error: %s", cc.Stale)
+	}
+
+	return NewOCPPDataTransferMeter(cc.StationId, cc.Connector, cc.Units, cc.Phases, cc.Channel, cc.Scale, cc.Invert, cc.MaxAge, staleErr)
+}
+
+// NewOCPPDataTransferMeter creates a DataTransfer meter for the given charge point. Any number of meters
+// can be created per charge point, e.g. for grid and pv channels of the same device. Non-zero connector
+// only receives values of that connector. Currents, powers and energy are multiplied by scale, invert changes
+// the sign of currents and powers, e.g. for grid export. Readings older than maxAge return staleErr, zero maxAge disables the check.
+func NewOCPPDataTransferMeter(id string, connector int, units masterplugUnits, phases int, channel string, scale float64, invert bool, maxAge time.Duration, staleErr error) (*OCPPDataTransferMeter, error) {
+	if phases != 0 && phases != 1 && phases != 3 {
+		return nil, fmt.Errorf("invalid phases: %d", phases)
+	}
+
+	for _, u := range []string{units.Current, units.Voltage, units.Power, units.Energy} {
+		if _, err := unitFactor(u); err != nil {
+			return nil, err
+		}
+	}
+
+	// the central system must be running for receiving messages
+	ocpp.Instance()
+
+	m := &OCPPDataTransferMeter{
+		units:     units,
+		connector: connector,
+		phases:    phases,
+		channel:   channel,
+		scale:     scale,
+		invert:    invert,
+		clock:     clock.New(),
+		maxAge:    maxAge,
+		staleErr:  staleErr,
+	}
+
+	dataTransferMetersMu.Lock()
+	dataTransferMeters[id] = append(dataTransferMeters[id], m)
+	dataTransferMetersMu.Unlock()
+
+	return m, nil
+}
+
+// update applies scale and sign to the reading and stores it
+func (m *OCPPDataTransferMeter) update(data *dataTransferReading) {
+	f := m.scale
+	if m.invert {
+		f = -f
+	}
+
+	data.Power *= f
+	for i := range data.Currents {
+		data.Currents[i] *= f
+	}
+	for i := range data.Powers {
+		data.Powers[i] *= f
+	}
+
+	if data.Energy != nil {
+		e := *data.Energy * m.scale
+		data.Energy = &e
+	}
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	m.data = data
+	m.updated = m.clock.Now()
+}
+
+func (m *OCPPDataTransferMeter) reading() (*dataTransferReading, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	if m.data == nil {
+		return nil, api.ErrNotAvailable
+	}
+
+	if m.maxAge > 0 && m.clock.Since(m.updated) > m.maxAge {
+		return nil, m.staleErr
+	}
+
+	return m.data, nil
+}
+
+// CurrentPower implements the api.Meter interface
+func (m *OCPPDataTransferMeter) CurrentPower() (float64, error) {
+	res, err := m.reading()
+	if err != nil {
+		return 0, err
+	}
+
+	return res.Power, nil
+}
+
+var _ api.MeterEnergy = (*OCPPDataTransferMeter)(nil)
+
+// TotalEnergy implements the api.MeterEnergy interface
+func (m *OCPPDataTransferMeter) TotalEnergy() (float64, error) {
+	res, err := m.reading()
+	if err != nil {
+		return 0, err
+	}
+
+	if res.Energy == nil {
+		return 0, api.ErrNotAvailable
+	}
+
+	return *res.Energy, nil
+}
+
+// Frequency returns the grid frequency
+func (m *OCPPDataTransferMeter) Frequency() (float64, error) {
+	res, err := m.reading()
+	if err != nil {
+		return 0, err
+	}
+
+	if res.Frequency == nil {
+		return 0, api.ErrNotAvailable
+	}
+
+	return *res.Frequency, nil
+}
+
+// phases returns the per-phase values
+func phases(values []float64) (float64, float64, float64, error) {
+	if len(values) == 0 {
+		return 0, 0, 0, api.ErrNotAvailable
+	}
+
+	var res [3]float64
+	copy(res[:], values)
+
+	return res[0], res[1], res[2], nil
+}
+
+var _ api.PhaseCurrents = (*OCPPDataTransferMeter)(nil)
+
+// Currents implements the api.PhaseCurrents interface
+func (m *OCPPDataTransferMeter) Currents() (float64, float64, float64, error) {
+	res, err := m.reading()
+	if err != nil {
+		return 0, 0, 0, err
+	}
+
+	return phases(res.Currents)
+}
+
+var _ api.PhaseVoltages = (*OCPPDataTransferMeter)(nil)
+
+// Voltages implements the api.PhaseVoltages interface
+func (m *OCPPDataTransferMeter) Voltages() (float64, float64, float64, error) {
+	res, err := m.reading()
+	if err != nil {
+		return 0, 0, 0, err
+	}
+
+	return phases(res.Voltages)
+}
+
+var _ api.PhasePowers = (*OCPPDataTransferMeter)(nil)
+
+// Powers implements the api.PhasePowers interface
+func (m *OCPPDataTransferMeter) Powers() (float64, float64, float64, error) {
+	res, err := m.reading()
+	if err != nil {
+		return 0, 0, 0, err
+	}
+
+	return phases(res.Powers)
+}
+
+// masterplugUnits are the units of the MasterPlug values. Units sent with the payload take precedence.
+type masterplugUnits struct {
+	Current, Voltage, Power, Energy string
+	Heuristic                       bool // detect milli units by value range if the payload has no units
+}
+
+// masterplugValues are the values of a CT clamp channel. Single phase devices only send current and voltage.
+type masterplugValues struct {
+	Current   *float64  `json:"current"`
+	Voltage   *float64  `json:"voltage"`
+	Power     *float64  `json:"power"`
+	Energy    *float64  `json:"energy"` // imported
+	Frequency *float64  `json:"frequency"`
+	Currents  []float64 `json:"currents"`
+	Voltages  []float64 `json:"voltages"`
+	Powers    []float64 `json:"powers"`
+	Units     struct {
+		Current, Voltage, Power, Energy string
+	} `json:"units"`
+}
+
+// masterplugData is the MasterPlug DataTransfer payload. Devices with multiple CT clamps send their
+// values per named channel, e.g. grid, pv or house. Units sent at top level apply to all channels.
+// Multi-connector devices identify the connector the values belong to.
+type masterplugData struct {
+	masterplugValues
+	ConnectorId int                         `json:"connectorId"`
+	Channels    map[string]masterplugValues `json:"channels"`
+}
+
+// channel returns the selected channel's values, empty channel selects the top level values
+func (d *masterplugData) channel(name string) (masterplugValues, error) {
+	if name == "" {
+		return d.masterplugValues, nil
+	}
+
+	for k, v := range d.Channels {
+		if strings.EqualFold(k, name) {
+			v.Units.Current = cmp.Or(v.Units.Current, d.Units.Current)
+			v.Units.Voltage = cmp.Or(v.Units.Voltage, d.Units.Voltage)
+			v.Units.Power = cmp.Or(v.Units.Power, d.Units.Power)
+			v.Units.Energy = cmp.Or(v.Units.Energy, d.Units.Energy)
+			return v, nil
+		}
+	}
+
+	return masterplugValues{}, fmt.Errorf("missing channel: %s", name)
+}
+
+// unitFactor returns the factor converting to A, V, W or kWh
+func unitFactor(unit string) (float64, error) {
+	switch strings.ToLower(unit) {
+	case "a", "v", "w", "kwh":
+		return 1, nil
+	case "ma", "mv", "wh":
+		return 1e-3, nil
+	case "kw":
+		return 1e3, nil
+	default:
+		return 0, fmt.Errorf("invalid unit: %s", unit)
+	}
+}
+
+// scaler returns the conversion of the payload's values. Without payload unit, values above limit
+// are considered milli units if the heuristic is enabled.
+func scaler(sent, configured string, heuristic bool, limit float64) (func(float64) float64, error) {
+	if sent == "" && heuristic && limit > 0 {
+		return func(v float64) float64 {
+			if v > limit {
+				return v / 1e3
+			}
+			return v
+		}, nil
+	}
+
+	f, err := unitFactor(cmp.Or(sent, configured))
+	if err != nil {
+		return nil, err
+	}
+
+	return func(v float64) float64 { return v * f }, nil
+}
+
+// mapPhases maps the sent values to the number of phases. Single values of three-phase devices are
+// considered balanced and apply to all phases.
+func mapPhases(values []float64, phases int) []float64 {
+	switch {
+	case phases == 0 || len(values) == 0:
+		return values
+	case len(values) == 1 && phases == 3:
+		return []float64{values[0], values[0], values[0]}
+	default:
+		return values[:min(len(values), phases)]
+	}
+}
+
+// meterParseMasterplug decodes the MasterPlug payload and returns the channel's measurement.
+// Phases limits the phase values to the device's phase count, 0 uses all sent values.
+func meterParseMasterplug(data json.RawMessage, units masterplugUnits, phases int, channel string) (*dataTransferReading, error) {
+	msg, err := masterplugPayload(data)
+	if err != nil {
+		return nil, err
+	}
+
+	return msg.reading(units, phases, channel)
+}
+
+// masterplugPayload decodes the MasterPlug payload which may also be sent as json encoded string
+func masterplugPayload(data json.RawMessage) (*masterplugData, error) {
+	var s string
+	if err := json.Unmarshal(data, &s); err == nil {
+		data = json.RawMessage(s)
+	}
+
+	var res masterplugData
+	if err := json.Unmarshal(data, &res); err != nil {
+		return nil, err
+	}
+
+	return &res, nil
+}
+
+// reading returns the channel's measurement
+func (d *masterplugData) reading(units masterplugUnits, phases int, channel string) (*dataTransferReading, error) {
+	payload, err := d.channel(channel)
+	if err != nil {
+		return nil, err
+	}
+
+	current, err := scaler(payload.Units.Current, units.Current, units.Heuristic, 100)
+	if err != nil {
+		return nil, err
+	}
+	voltage, err := scaler(payload.Units.Voltage, units.Voltage, units.Heuristic, 1000)
+	if err != nil {
+		return nil, err
+	}
+	power, err := scaler(payload.Units.Power, units.Power, false, 0)
+	if err != nil {
+		return nil, err
+	}
+	energy, err := scaler(payload.Units.Energy, units.Energy, false, 0)
+	if err != nil {
+		return nil, err
+	}
+
+	res := &dataTransferReading{
+		Frequency: payload.Frequency,
+	}
+
+	if payload.Energy != nil {
+		e := energy(*payload.Energy)
+		res.Energy = &e
+	}
+
+	currents := payload.Currents
+	if len(currents) == 0 && payload.Current != nil {
+		currents = []float64{*payload.Current}
+	}
+
+	voltages := payload.Voltages
+	if len(voltages) == 0 && payload.Voltage != nil {
+		voltages = []float64{*payload.Voltage}
+	}
+
+	if len(currents) == 0 {
+		return nil, errors.New("missing current")
+	}
+
+	for _, v := range currents {
+		res.Currents = append(res.Currents, current(v))
+	}
+	for _, v := range voltages {
+		res.Voltages = append(res.Voltages, voltage(v))
+	}
+	for _, v := range payload.Powers {
+		res.Powers = append(res.Powers, power(v))
+	}
+
+	res.Currents = mapPhases(res.Currents, phases)
+	res.Voltages = mapPhases(res.Voltages, phases)
+	if len(res.Powers) > phases && phases > 0 {
+		res.Powers = res.Powers[:phases]
+	}
+
+	switch {
+	case payload.Power != nil:
+		res.Power = power(*payload.Power)
+
+	case len(res.Powers) > 0:
+		for _, p := range res.Powers {
+			res.Power += p
+		}
+
+	default:
+		// estimate from phase values
+		for i, c := range res.Currents {
+			if i < len(res.Voltages) {
+				res.Power += c * res.Voltages[i]
+			}
+		}
+	}
+
+	return res, nil
+}
@@ -0,0 +1,189 @@
+package meter
+
+import (
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/benbjohnson/clock"
+	"github.com/evcc-io/evcc/api"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+var masterplugDefaultUnits = masterplugUnits{Current: "A", Voltage: "V", Power: "W", Energy: "Wh"}
+
+func TestMeterParseMasterplug(t *testing.T) {
+	units := masterplugDefaultUnits
+	units.Current = "mA"
+
+	// single phase
+	res, err := meterParseMasterplug(json.RawMessage(`{"current":16000,"voltage":230}`), units, 0, "")
+	require.NoError(t, err)
+	assert.Equal(t, []float64{16}, res.Currents)
+	assert.Equal(t, []float64{230}, res.Voltages)
+	assert.Equal(t, 3680.0, res.Power)
+	assert.Nil(t, res.Energy)
+
+	// three phase, encoded as string
+	res, err = meterParseMasterplug(json.RawMessage(`"{\"currents\":[10,11,12],\"voltages\":[230,231,232],\"powers\":[2300,2500,2700],\"energy\":12345,\"frequency\":50.1}"`), masterplugDefaultUnits, 0, "")
+	require.NoError(t, err)
+	assert.Equal(t, []float64{10, 11, 12}, res.Currents)
+	assert.Equal(t, 7500.0, res.Power)
+	require.NotNil(t, res.Energy)
+	assert.Equal(t, 12.345, *res.Energy)
+	require.NotNil(t, res.Frequency)
+	assert.Equal(t, 50.1, *res.Frequency)
+
+	// explicit power
+	res, err = meterParseMasterplug(json.RawMessage(`{"current":10,"power":-1000}`), masterplugDefaultUnits, 0, "")
+	require.NoError(t, err)
+	assert.Equal(t, -1000.0, res.Power)
+
+	_, err = meterParseMasterplug(json.RawMessage(`{"voltage":230}`), masterplugDefaultUnits, 0, "")
+	assert.Error(t, err)
+}
+
+func TestMeterParseMasterplugUnits(t *testing.T) {
+	// high currents are not mistaken for mA
+	res, err := meterParseMasterplug(json.RawMessage(`{"current":250,"voltage":230}`), masterplugDefaultUnits, 0, "")
+	require.NoError(t, err)
+	assert.Equal(t, []float64{250}, res.Currents)
+
+	// payload units take precedence
+	res, err = meterParseMasterplug(json.RawMessage(`{"current":250,"voltage":230000,"power":57.5,"energy":1.5,"units":{"current":"mA","voltage":"mV","power":"kW","energy":"kWh"}}`), masterplugDefaultUnits, 0, "")
+	require.NoError(t, err)
+	assert.Equal(t, []float64{0.25}, res.Currents)
+	assert.Equal(t, []float64{230}, res.Voltages)
+	assert.Equal(t, 57500.0, res.Power)
+	assert.Equal(t, 1.5, *res.Energy)
+
+	// heuristic
+	units := masterplugDefaultUnits
+	units.Heuristic = true
+
+	res, err = meterParseMasterplug(json.RawMessage(`{"currents":[16000,16],"voltages":[230000,230]}`), units, 0, "")
+	require.NoError(t, err)
+	assert.Equal(t, []float64{16, 16}, res.Currents)
+	assert.Equal(t, []float64{230, 230}, res.Voltages)
+
+	_, err = meterParseMasterplug(json.RawMessage(`{"current":16,"units":{"current":"kA"}}`), masterplugDefaultUnits, 0, "")
+	assert.Error(t, err)
+}
+
+func TestMeterParseMasterplugPhases(t *testing.T) {
+	// balanced three-phase
+	res, err := meterParseMasterplug(json.RawMessage(`{"current":10,"voltage":230}`), masterplugDefaultUnits, 3, "")
+	require.NoError(t, err)
+	assert.Equal(t, []float64{10, 10, 10}, res.Currents)
+	assert.Equal(t, 6900.0, res.Power)
+
+	// single phase
+	res, err = meterParseMasterplug(json.RawMessage(`{"currents":[10,0,0],"voltages":[230,230,230],"powers":[2300,0,0]}`), masterplugDefaultUnits, 1, "")
+	require.NoError(t, err)
+	assert.Equal(t, []float64{10}, res.Currents)
+	assert.Equal(t, []float64{2300.0}, res.Powers)
+	assert.Equal(t, 2300.0, res.Power)
+}
+
+func TestMeterParseMasterplugChannels(t *testing.T) {
+	data := json.RawMessage(`{"units":{"power":"kW"},"channels":{"grid":{"current":10,"power":2.3},"pv":{"current":5,"power":-1.15,"units":{"power":"W"}}}}`)
+
+	res, err := meterParseMasterplug(data, masterplugDefaultUnits, 0, "grid")
+	require.NoError(t, err)
+	assert.Equal(t, 2300.0, res.Power)
+
+	res, err = meterParseMasterplug(data, masterplugDefaultUnits, 0, "PV")
+	require.NoError(t, err)
+	assert.Equal(t, -1.15, res.Power)
+	assert.Equal(t, []float64{5}, res.Currents)
+
+	_, err = meterParseMasterplug(data, masterplugDefaultUnits, 0, "house")
+	assert.Error(t, err)
+}
+
+func TestOCPPDataTransferMeterScale(t *testing.T) {
+	grid := &OCPPDataTransferMeter{scale: 1, invert: true, clock: clock.New()}
+	scaled := &OCPPDataTransferMeter{scale: 2, clock: clock.New()}
+
+	res, err := meterParseMasterplug(json.RawMessage(`{"current":10,"voltage":230,"energy":1000}`), masterplugDefaultUnits, 0, "")
+	require.NoError(t, err)
+	grid.update(res)
+
+	res, err = meterParseMasterplug(json.RawMessage(`{"current":10,"voltage":230,"energy":1000}`), masterplugDefaultUnits, 0, "")
+	require.NoError(t, err)
+	scaled.update(res)
+
+	power, err := grid.CurrentPower()
+	require.NoError(t, err)
+	assert.Equal(t, -2300.0, power)
+
+	energy, err := grid.TotalEnergy()
+	require.NoError(t, err)
+	assert.Equal(t, 1.0, energy)
+
+	power, err = scaled.CurrentPower()
+	require.NoError(t, err)
+	assert.Equal(t, 4600.0, power)
+
+	l1, _, _, err := scaled.Currents()
+	require.NoError(t, err)
+	assert.Equal(t, 20.0, l1)
+}
+
+func TestOCPPDataTransferMeter(t *testing.T) {
+	m := &OCPPDataTransferMeter{scale: 1, clock: clock.New()}
+
+	_, err := m.CurrentPower()
+	assert.Error(t, err)
+
+	res, err := meterParseMasterplug(json.RawMessage(`{"currents":[10,11],"voltages":[230,230]}`), masterplugDefaultUnits, 0, "")
+	require.NoError(t, err)
+	m.update(res)
+
+	l1, l2, l3, err := m.Currents()
+	require.NoError(t, err)
+	assert.Equal(t, []float64{10, 11, 0}, []float64{l1, l2, l3})
+
+	_, err = m.TotalEnergy()
+	assert.Error(t, err)
+}
+
+func TestOCPPDataTransferMeterStale(t *testing.T) {
+	clock := clock.NewMock()
+	m := &OCPPDataTransferMeter{scale: 1, clock: clock, maxAge: time.Minute, staleErr: api.ErrTimeout}
+
+	res, err := meterParseMasterplug(json.RawMessage(`{"current":10,"voltage":230}`), masterplugDefaultUnits, 0, "")
+	require.NoError(t, err)
+	m.update(res)
+
+	_, err = m.CurrentPower()
+	require.NoError(t, err)
+
+	clock.Add(time.Minute + time.Second)
+
+	_, err = m.CurrentPower()
+	assert.ErrorIs(t, err, api.ErrTimeout)
+
+	_, _, _, err = m.Currents()
+	assert.ErrorIs(t, err, api.ErrTimeout)
+}
+
+func TestOCPPDataTransferMeterConnector(t *testing.T) {
+	conn1 := &OCPPDataTransferMeter{connector: 1, units: masterplugDefaultUnits, scale: 1, clock: clock.New()}
+	conn2 := &OCPPDataTransferMeter{connector: 2, units: masterplugDefaultUnits, scale: 1, clock: clock.New()}
+
+	dataTransferMetersMu.Lock()
+	dataTransferMeters["test-connector"] = []*OCPPDataTransferMeter{conn1, conn2}
+	dataTransferMetersMu.Unlock()
+
+	_, err := masterplugHandler("test-connector", json.RawMessage(`{"connectorId":2,"current":10,"power":2300}`))
+	require.NoError(t, err)
+
+	_, err = conn1.CurrentPower()
+	assert.ErrorIs(t, err, api.ErrNotAvailable)
+
+	power, err := conn2.CurrentPower()
+	require.NoError(t, err)
+	assert.Equal(t, 2300.0, power)
+}
@@ -0,0 +1,40 @@
+package meter
+
+import (
+	"cmp"
+	"fmt"
+
+	"github.com/evcc-io/evcc/api"
+	"github.com/evcc-io/evcc/util"
+	"github.com/evcc-io/evcc/util/sim"
+)
+
+func init() {
+	registry.Add("sim", NewSimFromConfig)
+}
+
+// NewSimFromConfig creates a simulated grid or pv meter for validating configurations without hardware
+func NewSimFromConfig(other map[string]any) (api.Meter, error) {
+	var cc struct {
+		Env   string // simulated environment shared with chargers and vehicles
+		Usage string
+		Power float64 // pv production or household consumption for grid usage
+	}
+
+	if err := util.DecodeOther(other, &cc); err != nil {
+		return nil, err
+	}
+
+	env := sim.Registered(cmp.Or(cc.Env, "default"))
+
+	switch cc.Usage {
+	case "grid":
+		env.SetHomePower(cc.Power)
+		return env.GridMeter(), nil
+	case "pv":
+		env.SetPvPower(cc.Power)
+		return env.PvMeter(), nil
+	default:
+		return nil, fmt.Errorf("invalid usage: %s", cc.Usage)
+	}
+}
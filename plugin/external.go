@@ -0,0 +1,129 @@
+package plugin
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/evcc-io/evcc/plugin/external"
+	"github.com/evcc-io/evcc/util"
+	"github.com/evcc-io/evcc/util/request"
+)
+
+// External implements out-of-tree driver process providers and setters
+type External struct {
+	*getter
+	proc    *external.Process
+	method  string
+	timeout time.Duration
+}
+
+func init() {
+	registry.Add("external", NewExternalPluginFromConfig)
+}
+
+// NewExternalPluginFromConfig creates an external driver plugin
+func NewExternalPluginFromConfig(other map[string]any) (Plugin, error) {
+	cc := struct {
+		Cmd     string
+		Config  map[string]any // sent to driver on startup
+		Method  string
+		Scale   float64
+		Timeout time.Duration
+	}{
+		Scale:   1,
+		Timeout: request.Timeout,
+	}
+
+	if err := util.DecodeOther(other, &cc); err != nil {
+		return nil, err
+	}
+
+	if cc.Method == "" {
+		return nil, errors.New("missing method")
+	}
+
+	proc, err := external.Registered(cc.Cmd, cc.Config)
+	if err != nil {
+		return nil, err
+	}
+
+	p := &External{
+		proc:    proc,
+		method:  cc.Method,
+		timeout: cc.Timeout,
+	}
+
+	p.getter = defaultGetters(p, cc.Scale)
+
+	return p, nil
+}
+
+func (p *External) call(params any) (json.RawMessage, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), p.timeout)
+	defer cancel()
+
+	res, err := p.proc.Call(ctx, p.method, params)
+	if err != nil {
+		err = fmt.Errorf("%s: %w", p.method, err)
+	}
+
+	return res, err
+}
+
+var _ StringGetter = (*External)(nil)
+
+// StringGetter returns the driver result. Json strings are unquoted, other values returned as json.
+func (p *External) StringGetter() (func() (string, error), error) {
+	return func() (string, error) {
+		res, err := p.call(nil)
+		if err != nil {
+			return "", err
+		}
+
+		var s string
+		if err := json.Unmarshal(res, &s); err == nil {
+			return s, nil
+		}
+
+		return strings.TrimSpace(string(res)), nil
+	}, nil
+}
+
+func externalSetter[T any](p *External) (func(T) error, error) {
+	return func(val T) error {
+		_, err := p.call(map[string]any{"value": val})
+		return err
+	}, nil
+}
+
+var _ IntSetter = (*External)(nil)
+
+// IntSetter invokes the driver method with int value
+func (p *External) IntSetter(_ string) (func(int64) error, error) {
+	return externalSetter[int64](p)
+}
+
+var _ FloatSetter = (*External)(nil)
+
+// FloatSetter invokes the driver method with float value
+func (p *External) FloatSetter(_ string) (func(float64) error, error) {
+	return externalSetter[float64](p)
+}
+
+var _ BoolSetter = (*External)(nil)
+
+// BoolSetter invokes the driver method with bool value
+func (p *External) BoolSetter(_ string) (func(bool) error, error) {
+	return externalSetter[bool](p)
+}
+
+var _ StringSetter = (*External)(nil)
+
+// StringSetter invokes the driver method with string value
+func (p *External) StringSetter(_ string) (func(string) error, error) {
+	return externalSetter[string](p)
+}
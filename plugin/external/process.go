@@ -0,0 +1,249 @@
+// Package external runs out-of-tree device drivers as child processes.
+//
+// Drivers can be implemented in any language and communicate using line-delimited
+// JSON-RPC 2.0 over stdin and stdout. Each request and response is a single line:
+//
+//	-> {"jsonrpc":"2.0","id":1,"method":"init","params":{"config":{"host":"192.0.2.1"}}}
+//	<- {"jsonrpc":"2.0","id":1,"result":null}
+//	-> {"jsonrpc":"2.0","id":2,"method":"power"}
+//	<- {"jsonrpc":"2.0","id":2,"result":1234.5}
+//	-> {"jsonrpc":"2.0","id":3,"method":"enable","params":{"value":true}}
+//	<- {"jsonrpc":"2.0","id":3,"error":{"code":-32000,"message":"not connected"}}
+//
+// The init method is called once after the driver has started and receives the
+// driver configuration. Method names are defined by the driver. Getters receive no
+// params, setters receive the value as params.value. Output on stderr is logged.
+// Drivers are restarted on the next call if they exit.
+package external
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os/exec"
+	"strings"
+	"sync"
+
+	"github.com/evcc-io/evcc/util"
+	"github.com/kballard/go-shellquote"
+)
+
+var (
+	mu       sync.Mutex
+	registry = make(map[string]*Process)
+)
+
+// Registered returns a shared driver process for the command line. The configuration
+// is sent on startup, differing configurations for the same command are rejected.
+func Registered(cmd string, config map[string]any) (*Process, error) {
+	mu.Lock()
+	defer mu.Unlock()
+
+	args, err := shellquote.Split(cmd)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(args) == 0 {
+		return nil, errors.New("missing cmd")
+	}
+
+	key := strings.Join(args, " ")
+
+	if p, ok := registry[key]; ok {
+		if fmt.Sprint(p.config) != fmt.Sprint(config) {
+			return nil, fmt.Errorf("conflicting config for %s", key)
+		}
+		return p, nil
+	}
+
+	p := &Process{
+		log:    util.NewLogger("external"),
+		args:   args,
+		config: config,
+	}
+
+	registry[key] = p
+
+	return p, nil
+}
+
+// Process is a driver process
+type Process struct {
+	log    *util.Logger
+	args   []string
+	config map[string]any
+
+	mu     sync.Mutex
+	cmd    *exec.Cmd
+	stdin  io.WriteCloser
+	respC  chan response
+	doneC  chan struct{} // closed when stdout is closed
+	quitC  chan struct{} // closed when stopped
+	nextID int64
+}
+
+type request struct {
+	JSONRPC string `json:"jsonrpc"`
+	ID      int64  `json:"id"`
+	Method  string `json:"method"`
+	Params  any    `json:"params,omitempty"`
+}
+
+type response struct {
+	ID     int64           `json:"id"`
+	Result json.RawMessage `json:"result"`
+	Error  *Error          `json:"error"`
+}
+
+// Error is a driver error
+type Error struct {
+	Code    int    `json:"code"`
+	Message string `json:"message"`
+}
+
+func (e *Error) Error() string {
+	return e.Message
+}
+
+// start starts the driver and sends the configuration
+func (p *Process) start(ctx context.Context) error {
+	cmd := exec.Command(p.args[0], p.args[1:]...)
+
+	stdin, err := cmd.StdinPipe()
+	if err != nil {
+		return err
+	}
+
+	stdout, err := cmd.StdoutPipe()
+	if err != nil {
+		return err
+	}
+
+	stderr, err := cmd.StderrPipe()
+	if err != nil {
+		return err
+	}
+
+	if err := cmd.Start(); err != nil {
+		return err
+	}
+
+	p.log.DEBUG.Printf("started: %s", strings.Join(p.args, " "))
+
+	p.cmd = cmd
+	p.stdin = stdin
+	p.respC = make(chan response)
+	p.doneC = make(chan struct{})
+	p.quitC = make(chan struct{})
+
+	go p.logStderr(stderr)
+	go p.readResponses(stdout, p.respC, p.doneC, p.quitC)
+
+	if _, err := p.call(ctx, "init", map[string]any{"config": p.config}); err != nil {
+		p.stop()
+		return fmt.Errorf("init: %w", err)
+	}
+
+	return nil
+}
+
+// stop terminates the driver
+func (p *Process) stop() {
+	if p.cmd == nil {
+		return
+	}
+
+	close(p.quitC)
+	_ = p.stdin.Close()
+	_ = p.cmd.Process.Kill()
+	_ = p.cmd.Wait()
+
+	p.cmd = nil
+}
+
+func (p *Process) logStderr(r io.Reader) {
+	scanner := bufio.NewScanner(r)
+	for scanner.Scan() {
+		p.log.INFO.Println(scanner.Text())
+	}
+}
+
+func (p *Process) readResponses(r io.Reader, respC chan<- response, doneC chan<- struct{}, quitC <-chan struct{}) {
+	defer close(doneC)
+
+	scanner := bufio.NewScanner(r)
+	scanner.Buffer(nil, 1<<20)
+
+	for scanner.Scan() {
+		p.log.TRACE.Println("<-", scanner.Text())
+
+		var res response
+		if err := json.Unmarshal(scanner.Bytes(), &res); err != nil {
+			p.log.ERROR.Printf("invalid response: %v", err)
+			continue
+		}
+
+		select {
+		case respC <- res:
+		case <-quitC:
+			return
+		}
+	}
+}
+
+// call sends the request and waits for the matching response
+func (p *Process) call(ctx context.Context, method string, params any) (json.RawMessage, error) {
+	p.nextID++
+	id := p.nextID
+
+	b, err := json.Marshal(request{JSONRPC: "2.0", ID: id, Method: method, Params: params})
+	if err != nil {
+		return nil, err
+	}
+
+	p.log.TRACE.Println("->", string(b))
+
+	if _, err := p.stdin.Write(append(b, '\n')); err != nil {
+		p.stop()
+		return nil, err
+	}
+
+	for {
+		select {
+		case res := <-p.respC:
+			if res.ID != id {
+				// late response of timed out request
+				continue
+			}
+			if res.Error != nil {
+				return nil, res.Error
+			}
+			return res.Result, nil
+
+		case <-p.doneC:
+			p.stop()
+			return nil, errors.New("driver exited")
+
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		}
+	}
+}
+
+// Call invokes the driver method, starting the driver if not running
+func (p *Process) Call(ctx context.Context, method string, params any) (json.RawMessage, error) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	if p.cmd == nil {
+		if err := p.start(ctx); err != nil {
+			return nil, err
+		}
+	}
+
+	return p.call(ctx, method, params)
+}
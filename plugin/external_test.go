@@ -0,0 +1,98 @@
+package plugin
+
+import (
+	"bufio"
+	"encoding/json"
+	"fmt"
+	"os"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// TestExternalDriver is the driver process started by TestExternalPlugin
+func TestExternalDriver(t *testing.T) {
+	if os.Getenv("EVCC_EXTERNAL_DRIVER") == "" {
+		return
+	}
+
+	var config map[string]any
+
+	scanner := bufio.NewScanner(os.Stdin)
+	for scanner.Scan() {
+		var req struct {
+			ID     int64
+			Method string
+			Params struct {
+				Config map[string]any
+				Value  any
+			}
+		}
+
+		if err := json.Unmarshal(scanner.Bytes(), &req); err != nil {
+			os.Exit(1)
+		}
+
+		var res string
+		switch req.Method {
+		case "init":
+			config = req.Params.Config
+			res = `"result":null`
+		case "power":
+			res = `"result":1234.5`
+		case "host":
+			res = fmt.Sprintf(`"result":%q`, config["host"])
+		case "enable":
+			res = `"result":null`
+			if req.Params.Value != true {
+				res = `"error":{"code":-32000,"message":"failed"}`
+			}
+		default:
+			res = `"error":{"code":-32601,"message":"method not found"}`
+		}
+
+		fmt.Printf(`{"jsonrpc":"2.0","id":%d,%s}`+"\n", req.ID, res)
+	}
+
+	os.Exit(0)
+}
+
+func TestExternalPlugin(t *testing.T) {
+	t.Setenv("EVCC_EXTERNAL_DRIVER", "1")
+
+	cmd := fmt.Sprintf("%s -test.run=^TestExternalDriver$", os.Args[0])
+	conf := map[string]any{"host": "192.0.2.1"}
+
+	p, err := NewExternalPluginFromConfig(map[string]any{"cmd": cmd, "method": "power", "config": conf})
+	require.NoError(t, err)
+
+	fg, err := p.(FloatGetter).FloatGetter()
+	require.NoError(t, err)
+
+	f, err := fg()
+	require.NoError(t, err)
+	assert.Equal(t, 1234.5, f)
+
+	p, err = NewExternalPluginFromConfig(map[string]any{"cmd": cmd, "method": "host", "config": conf})
+	require.NoError(t, err)
+
+	sg, err := p.(StringGetter).StringGetter()
+	require.NoError(t, err)
+
+	s, err := sg()
+	require.NoError(t, err)
+	assert.Equal(t, "192.0.2.1", s)
+
+	p, err = NewExternalPluginFromConfig(map[string]any{"cmd": cmd, "method": "enable", "config": conf})
+	require.NoError(t, err)
+
+	bs, err := p.(BoolSetter).BoolSetter("enable")
+	require.NoError(t, err)
+	assert.NoError(t, bs(true))
+	assert.EqualError(t, bs(false), "enable: failed")
+
+	// shared process requires identical config
+	_, err = NewExternalPluginFromConfig(map[string]any{"cmd": cmd, "method": "power"})
+	assert.Error(t, err)
+}
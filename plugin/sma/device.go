@@ -6,6 +6,7 @@ import (
 	"time"
 
 	"github.com/evcc-io/evcc/util"
+	"github.com/evcc-io/evcc/util/scheduler"
 	"gitlab.com/bboehmke/sunny"
 )
 
@@ -24,11 +25,13 @@ func (d *Device) Run() {
 }
 
 func (d *Device) run() {
-	for range time.Tick(5 * time.Second) {
-		if err := d.UpdateValues(); err != nil {
+	scheduler.Run(d.Address().IP.String(), 5*time.Second, func() error {
+		err := d.UpdateValues()
+		if err != nil {
 			d.log.ERROR.Println(err)
 		}
-	}
+		return err
+	})
 }
 
 func (d *Device) UpdateValues() error {
@@ -0,0 +1,100 @@
+package push
+
+import (
+	"errors"
+	"net/http"
+	"net/url"
+	"strings"
+
+	"github.com/evcc-io/evcc/util"
+	"github.com/evcc-io/evcc/util/request"
+)
+
+func init() {
+	registry.Add("apprise", NewAppriseFromConfig)
+}
+
+// Apprise implements the Apprise API gateway messenger. Messages are either sent to the
+// services of a persistent configuration key or to the given service urls (stateless).
+type Apprise struct {
+	*request.Helper
+	log  *util.Logger
+	uri  string
+	urls string
+	tag  string
+	typ  string
+}
+
+// NewAppriseFromConfig creates new Apprise messenger
+func NewAppriseFromConfig(other map[string]any) (Messenger, error) {
+	var cc struct {
+		URI  string
+		Key  string
+		URLs []string
+		Tag  string
+		Type string
+	}
+
+	if err := util.DecodeOther(other, &cc); err != nil {
+		return nil, err
+	}
+
+	if cc.URI == "" {
+		return nil, errors.New("missing uri")
+	}
+
+	if (cc.Key == "") == (len(cc.URLs) == 0) {
+		return nil, errors.New("either key or urls required")
+	}
+
+	switch cc.Type {
+	case "", "info", "success", "warning", "failure":
+	default:
+		return nil, errors.New("invalid type, must be info, success, warning or failure")
+	}
+
+	log := util.NewLogger("apprise").Redact(cc.Key)
+	log.Redact(cc.URLs...)
+
+	uri := strings.TrimRight(cc.URI, "/") + "/notify"
+	if cc.Key != "" {
+		uri += "/" + url.PathEscape(cc.Key)
+	}
+
+	m := &Apprise{
+		Helper: request.NewHelper(log),
+		log:    log,
+		uri:    uri,
+		urls:   strings.Join(cc.URLs, ","),
+		tag:    cc.Tag,
+		typ:    cc.Type,
+	}
+
+	return m, nil
+}
+
+// Send sends to all services
+func (m *Apprise) Send(title, msg string) {
+	data := struct {
+		URLs  string `json:"urls,omitempty"`
+		Title string `json:"title,omitempty"`
+		Body  string `json:"body"`
+		Type  string `json:"type,omitempty"`
+		Tag   string `json:"tag,omitempty"`
+	}{
+		URLs:  m.urls,
+		Title: title,
+		Body:  msg,
+		Type:  m.typ,
+		Tag:   m.tag,
+	}
+
+	req, err := request.New(http.MethodPost, m.uri, request.MarshalJSON(data), request.JSONEncoding)
+	if err == nil {
+		_, err = m.DoBody(req)
+	}
+
+	if err != nil {
+		m.log.ERROR.Println("send:", err)
+	}
+}
@@ -0,0 +1,63 @@
+package push
+
+import (
+	"bytes"
+	"image"
+	"image/color"
+	"image/draw"
+	"image/png"
+
+	"github.com/spf13/cast"
+)
+
+// Attachment is a file sent along with a message
+type Attachment struct {
+	Name        string
+	ContentType string
+	Data        []byte
+}
+
+// AttachmentSender is implemented by messengers supporting attachments
+type AttachmentSender interface {
+	SendWithAttachment(title, msg string, attachment Attachment)
+}
+
+var (
+	chartSolar      = color.RGBA{0x0f, 0xde, 0x41, 0xff}
+	chartGrid       = color.RGBA{0x93, 0x94, 0x9e, 0xff}
+	chartBackground = color.White
+)
+
+// sessionAttachment renders the charged energy of the finished session as bar chart
+// split into solar and grid share. Returns nil if no energy was charged.
+func sessionAttachment(attr map[string]any) *Attachment {
+	energy := cast.ToFloat64(attr["chargedEnergy"])
+	if energy <= 0 {
+		return nil
+	}
+
+	solar := min(max(cast.ToFloat64(attr["sessionSolarPercentage"]), 0), 100)
+
+	const (
+		width, height = 600, 80
+		margin        = 10
+	)
+
+	img := image.NewRGBA(image.Rect(0, 0, width, height))
+	draw.Draw(img, img.Bounds(), &image.Uniform{chartBackground}, image.Point{}, draw.Src)
+
+	split := margin + int(float64(width-2*margin)*solar/100)
+	draw.Draw(img, image.Rect(margin, margin, split, height-margin), &image.Uniform{chartSolar}, image.Point{}, draw.Src)
+	draw.Draw(img, image.Rect(split, margin, width-margin, height-margin), &image.Uniform{chartGrid}, image.Point{}, draw.Src)
+
+	var b bytes.Buffer
+	if err := png.Encode(&b, img); err != nil {
+		return nil
+	}
+
+	return &Attachment{
+		Name:        "session.png",
+		ContentType: "image/png",
+		Data:        b.Bytes(),
+	}
+}
@@ -5,6 +5,8 @@ import (
 	"fmt"
 	"strings"
 
+	"github.com/evcc-io/evcc/core/site"
+	"github.com/evcc-io/evcc/util"
 	reg "github.com/evcc-io/evcc/util/registry"
 )
 
@@ -13,6 +15,21 @@ type Messenger interface {
 	Send(title, msg string)
 }
 
+// Commander is implemented by messengers accepting commands
+type Commander interface {
+	Commands(site site.API, cache *util.ParamCache)
+}
+
+// Localizer is implemented by messengers generating texts themselves, e.g. reports
+type Localizer interface {
+	SetLanguage(lang string)
+}
+
+// EventSender is implemented by messengers using event details, e.g. for priorities or actions
+type EventSender interface {
+	SendEvent(ev Event, title, msg string)
+}
+
 var registry = reg.New[Messenger]("messenger")
 
 // NewFromConfig creates messenger from configuration
@@ -0,0 +1,59 @@
+package push
+
+import (
+	"fmt"
+	"text/template"
+	"time"
+
+	"github.com/spf13/cast"
+	"golang.org/x/text/language"
+	"golang.org/x/text/message"
+)
+
+// dateLayouts are the localized date/time formats, iso format if not listed
+var dateLayouts = map[language.Base]string{
+	language.MustParseBase("de"): "02.01.2006 15:04",
+	language.MustParseBase("en"): "Jan 2, 2006 15:04",
+	language.MustParseBase("fr"): "02/01/2006 15:04",
+	language.MustParseBase("nl"): "02-01-2006 15:04",
+}
+
+// templateFuncs returns the localized formatting functions for message templates
+//
+//	{{ number .chargedEnergy 1 }}   localized number with decimals, e.g. 1.234,5
+//	{{ kilo .chargedEnergy 1 }}     localized number divided by 1000
+//	{{ duration .chargeDuration }}  duration as 1h05m
+//	{{ datetime .planTime }}        localized date and time
+func templateFuncs(lang language.Tag) template.FuncMap {
+	p := message.NewPrinter(lang)
+
+	layout := time.DateTime[:len(time.DateTime)-3]
+	if base, _ := lang.Base(); dateLayouts[base] != "" {
+		layout = dateLayouts[base]
+	}
+
+	number := func(v any, decimals int) string {
+		return p.Sprintf(fmt.Sprintf("%%.%df", decimals), cast.ToFloat64(v))
+	}
+
+	return template.FuncMap{
+		"number": number,
+		"kilo": func(v any, decimals int) string {
+			return number(cast.ToFloat64(v)/1e3, decimals)
+		},
+		"duration": func(v any) string {
+			d := cast.ToDuration(v).Round(time.Minute)
+			if d < time.Hour {
+				return fmt.Sprintf("%dm", int(d.Minutes()))
+			}
+			return fmt.Sprintf("%dh%02dm", int(d.Hours()), int(d.Minutes())%60)
+		},
+		"datetime": func(v any) string {
+			ts := cast.ToTime(v)
+			if ts.IsZero() {
+				return ""
+			}
+			return ts.Local().Format(layout)
+		},
+	}
+}
@@ -3,19 +3,23 @@ package push
 import (
 	"fmt"
 	"reflect"
+	"slices"
 	"strings"
 	"text/template"
+	"time"
 
 	"github.com/Masterminds/sprig/v3"
 	"github.com/evcc-io/evcc/api/globalconfig"
 	"github.com/evcc-io/evcc/core/vehicle"
 	"github.com/evcc-io/evcc/util"
+	"golang.org/x/text/language"
 )
 
 // Event is a notification event
 type Event struct {
 	Loadpoint *int // optional loadpoint id
 	Event     string
+	Error     string // optional error details
 }
 
 type Vehicles interface {
@@ -26,25 +30,54 @@ type Vehicles interface {
 // Hub subscribes to event notifications and sends them to client devices
 type Hub struct {
 	definitions map[string]globalconfig.MessagingEventTemplate
-	sender      []Messenger
+	language    language.Tag
+	sender      []sender
+	webhooks    []*Webhook
 	cache       *util.ParamCache
 	vehicles    Vehicles
 }
 
-// NewHub creates push hub with definitions and receiver
-func NewHub(cc map[string]globalconfig.MessagingEventTemplate, vv Vehicles, cache *util.ParamCache) (*Hub, error) {
-	// instantiate all event templates
+// sender is a messenger with optional event templates and language overriding the hub's defaults
+type sender struct {
+	Messenger
+	definitions map[string]globalconfig.MessagingEventTemplate
+	language    language.Tag
+	severity    Severity // minimum event severity
+}
+
+// validateTemplates parses all event templates
+func validateTemplates(cc map[string]globalconfig.MessagingEventTemplate) error {
+	funcs := templateFuncs(language.Und)
+
 	for k, v := range cc {
-		if _, err := template.New("out").Funcs(sprig.FuncMap()).Parse(v.Title); err != nil {
-			return nil, fmt.Errorf("invalid event title: %s (%w)", k, err)
+		if _, err := template.New("out").Funcs(sprig.FuncMap()).Funcs(funcs).Parse(v.Title); err != nil {
+			return fmt.Errorf("invalid event title: %s (%w)", k, err)
 		}
-		if _, err := template.New("out").Funcs(sprig.FuncMap()).Parse(v.Msg); err != nil {
-			return nil, fmt.Errorf("invalid event message: %s (%w)", k, err)
+		if _, err := template.New("out").Funcs(sprig.FuncMap()).Funcs(funcs).Parse(v.Msg); err != nil {
+			return fmt.Errorf("invalid event message: %s (%w)", k, err)
+		}
+	}
+
+	return nil
+}
+
+// NewHub creates push hub with definitions and receiver. Template values are formatted according to the language.
+func NewHub(cc map[string]globalconfig.MessagingEventTemplate, lang string, vv Vehicles, cache *util.ParamCache) (*Hub, error) {
+	if err := validateTemplates(cc); err != nil {
+		return nil, err
+	}
+
+	tag := language.Und
+	if lang != "" {
+		var err error
+		if tag, err = language.Parse(lang); err != nil {
+			return nil, fmt.Errorf("invalid language: %s", lang)
 		}
 	}
 
 	h := &Hub{
 		definitions: cc,
+		language:    tag,
 		cache:       cache,
 		vehicles:    vv,
 	}
@@ -53,13 +86,58 @@ func NewHub(cc map[string]globalconfig.MessagingEventTemplate, vv Vehicles, cach
 }
 
 // Add adds a sender to the list of senders
-func (h *Hub) Add(sender Messenger) {
-	h.sender = append(h.sender, sender)
+func (h *Hub) Add(m Messenger) {
+	h.sender = append(h.sender, sender{Messenger: m, language: h.language})
+}
+
+// AddWithTemplates adds a sender with event templates and language overriding the hub's defaults.
+// Events without sender template use the hub's templates. Events below minimum severity are not sent.
+func (h *Hub) AddWithTemplates(m Messenger, cc map[string]globalconfig.MessagingEventTemplate, lang string, severity Severity) error {
+	if err := validateTemplates(cc); err != nil {
+		return err
+	}
+
+	tag := h.language
+	if lang != "" {
+		var err error
+		if tag, err = language.Parse(lang); err != nil {
+			return fmt.Errorf("invalid language: %s", lang)
+		}
+	}
+
+	h.sender = append(h.sender, sender{Messenger: m, definitions: cc, language: tag, severity: severity})
+
+	return nil
 }
 
-// apply applies the event template to the content to produce the actual message
-func (h *Hub) apply(ev Event, tmpl string) (string, error) {
-	attr := make(map[string]any)
+// definition returns the sender's event template
+func (h *Hub) definition(s sender, event string) (globalconfig.MessagingEventTemplate, bool) {
+	if EventSeverity(event) < s.severity {
+		return globalconfig.MessagingEventTemplate{}, false
+	}
+	if d, ok := s.definitions[event]; ok {
+		return d, true
+	}
+	d, ok := h.definitions[event]
+	return d, ok
+}
+
+// AddWebhook adds a webhook to the list of webhooks
+func (h *Hub) AddWebhook(wh *Webhook) {
+	h.webhooks = append(h.webhooks, wh)
+}
+
+// attributes collects the event's template attributes
+func (h *Hub) attributes(ev Event) map[string]any {
+	attr := map[string]any{
+		"event":    ev.Event,
+		"severity": EventSeverity(ev.Event).String(),
+		"time":     time.Now(),
+	}
+
+	if ev.Error != "" {
+		attr["error"] = ev.Error
+	}
 
 	// loadpoint id
 	if ev.Loadpoint != nil {
@@ -94,7 +172,45 @@ func (h *Hub) apply(ev Event, tmpl string) (string, error) {
 		}
 	}
 
-	return util.ReplaceFormatted(tmpl, attr)
+	return attr
+}
+
+// render renders the event template for the sender. Returns false if there is nothing to send.
+func (h *Hub) render(s sender, definition globalconfig.MessagingEventTemplate, attr map[string]any) (string, string, bool, error) {
+	funcs := templateFuncs(s.language)
+
+	title, err := util.ReplaceFormattedFuncs(definition.Title, attr, funcs)
+	if err != nil {
+		return "", "", false, fmt.Errorf("invalid title template: %w", err)
+	}
+
+	msg, err := util.ReplaceFormattedFuncs(definition.Msg, attr, funcs)
+	if err != nil {
+		return "", "", false, fmt.Errorf("invalid message template: %w", err)
+	}
+
+	return title, msg, strings.TrimSpace(msg) != "", nil
+}
+
+// logEvent logs the event according to its severity
+func (h *Hub) logEvent(log *util.Logger, ev Event) {
+	l := log.DEBUG
+	switch EventSeverity(ev.Event) {
+	case SeverityWarn:
+		l = log.WARN
+	case SeverityCritical:
+		l = log.ERROR
+	}
+
+	msg := ev.Event
+	if ev.Loadpoint != nil {
+		msg = fmt.Sprintf("lp-%d %s", *ev.Loadpoint+1, msg)
+	}
+	if ev.Error != "" {
+		msg += ": " + ev.Error
+	}
+
+	l.Println("event", msg)
 }
 
 // Run is the Hub's main publishing loop
@@ -102,12 +218,18 @@ func (h *Hub) Run(events <-chan Event, valueChan chan<- util.Param) {
 	log := util.NewLogger("push")
 
 	for ev := range events {
-		if len(h.sender) == 0 {
-			continue
-		}
+		h.logEvent(log, ev)
 
-		definition, ok := h.definitions[ev.Event]
-		if !ok {
+		notify := slices.ContainsFunc(h.sender, func(s sender) bool {
+			_, ok := h.definition(s, ev.Event)
+			return ok
+		})
+
+		webhooks := slices.DeleteFunc(slices.Clone(h.webhooks), func(wh *Webhook) bool {
+			return !wh.Matches(ev.Event)
+		})
+
+		if !notify && len(webhooks) == 0 {
 			continue
 		}
 
@@ -116,24 +238,49 @@ func (h *Hub) Run(events <-chan Event, valueChan chan<- util.Param) {
 		valueChan <- util.Param{Val: flushC}
 		<-flushC
 
-		title, err := h.apply(ev, definition.Title)
-		if err != nil {
-			log.ERROR.Printf("invalid title template for %s: %v", ev.Event, err)
-			continue
+		attr := h.attributes(ev)
+
+		for _, wh := range webhooks {
+			go wh.Send(ev.Event, attr)
 		}
 
-		msg, err := h.apply(ev, definition.Msg)
-		if err != nil {
-			log.ERROR.Printf("invalid message template for %s: %v", ev.Event, err)
+		if !notify {
 			continue
 		}
 
-		if strings.TrimSpace(msg) == "" {
-			continue
+		// session summary for messengers supporting attachments
+		var attachment *Attachment
+		if ev.Event == "stop" {
+			attachment = sessionAttachment(attr)
 		}
 
-		for _, sender := range h.sender {
-			go sender.Send(title, msg)
+		for _, s := range h.sender {
+			definition, ok := h.definition(s, ev.Event)
+			if !ok {
+				continue
+			}
+
+			title, msg, ok, err := h.render(s, definition, attr)
+			if err != nil {
+				log.ERROR.Printf("%s: %v", ev.Event, err)
+				continue
+			}
+
+			if !ok {
+				continue
+			}
+
+			if as, ok := s.Messenger.(AttachmentSender); ok && attachment != nil {
+				go as.SendWithAttachment(title, msg, *attachment)
+				continue
+			}
+
+			if es, ok := s.Messenger.(EventSender); ok {
+				go es.SendEvent(ev, title, msg)
+				continue
+			}
+
+			go s.Send(title, msg)
 		}
 	}
 }
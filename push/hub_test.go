@@ -0,0 +1,79 @@
+package push
+
+import (
+	"testing"
+	"time"
+
+	"github.com/evcc-io/evcc/api/globalconfig"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestHubTemplates(t *testing.T) {
+	h, err := NewHub(map[string]globalconfig.MessagingEventTemplate{
+		"stop":  {Title: "Finished", Msg: "Charged {{ kilo .chargedEnergy 1 }} kWh in {{ duration .chargeDuration }}"},
+		"start": {Title: "Started", Msg: "Started"},
+	}, "en", nil, nil)
+	require.NoError(t, err)
+
+	require.NoError(t, h.AddWithTemplates(nil, map[string]globalconfig.MessagingEventTemplate{
+		"stop": {Title: "Beendet", Msg: "{{ kilo .chargedEnergy 1 }} kWh geladen"},
+	}, "de", SeverityInfo))
+
+	h.Add(nil)
+
+	attr := map[string]any{
+		"chargedEnergy":  12345.0,
+		"chargeDuration": 95 * time.Minute,
+	}
+
+	// sender template and language
+	d, ok := h.definition(h.sender[0], "stop")
+	require.True(t, ok)
+	title, msg, ok, err := h.render(h.sender[0], d, attr)
+	require.NoError(t, err)
+	assert.True(t, ok)
+	assert.Equal(t, "Beendet", title)
+	assert.Equal(t, "12,3 kWh geladen", msg)
+
+	// fallback to hub template
+	_, ok = h.definition(h.sender[0], "start")
+	assert.True(t, ok)
+
+	// hub template and language
+	d, ok = h.definition(h.sender[1], "stop")
+	require.True(t, ok)
+	_, msg, _, err = h.render(h.sender[1], d, attr)
+	require.NoError(t, err)
+	assert.Equal(t, "Charged 12.3 kWh in 1h35m", msg)
+
+	assert.Error(t, h.AddWithTemplates(nil, nil, "not a language", SeverityInfo))
+	assert.Error(t, h.AddWithTemplates(nil, map[string]globalconfig.MessagingEventTemplate{
+		"stop": {Msg: "{{ invalid"},
+	}, "", SeverityInfo))
+}
+
+func TestHubSeverity(t *testing.T) {
+	h, err := NewHub(map[string]globalconfig.MessagingEventTemplate{
+		"start": {Msg: "Started"},
+		"guest": {Msg: "Guest"},
+		"fault": {Msg: "Fault"},
+	}, "", nil, nil)
+	require.NoError(t, err)
+
+	require.NoError(t, h.AddWithTemplates(nil, nil, "", SeverityWarn))
+
+	for ev, ok := range map[string]bool{
+		"start": false,
+		"guest": true,
+		"fault": true,
+	} {
+		_, res := h.definition(h.sender[0], ev)
+		assert.Equal(t, ok, res, ev)
+	}
+
+	s, err := SeverityString("critical")
+	require.NoError(t, err)
+	assert.Equal(t, SeverityCritical, s)
+	assert.Equal(t, SeverityCritical, EventSeverity("security"))
+}
@@ -0,0 +1,138 @@
+package push
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/evcc-io/evcc/util"
+	"github.com/evcc-io/evcc/util/request"
+)
+
+func init() {
+	registry.Add("matrix", NewMatrixFromConfig)
+}
+
+// Matrix implements the Matrix messenger. For end-to-end encrypted rooms,
+// the uri must point to a pantalaimon proxy instead of the homeserver.
+type Matrix struct {
+	*request.Helper
+	log   *util.Logger
+	uri   string
+	token string
+
+	mu   sync.Mutex
+	room string // room id or alias, resolved to room id on first use
+}
+
+// NewMatrixFromConfig creates new Matrix messenger
+func NewMatrixFromConfig(other map[string]any) (Messenger, error) {
+	var cc struct {
+		URI   string
+		Token string
+		Room  string
+	}
+
+	if err := util.DecodeOther(other, &cc); err != nil {
+		return nil, err
+	}
+
+	if cc.URI == "" {
+		return nil, errors.New("missing uri")
+	}
+
+	if cc.Token == "" {
+		return nil, errors.New("missing token")
+	}
+
+	if !strings.HasPrefix(cc.Room, "!") && !strings.HasPrefix(cc.Room, "#") {
+		return nil, errors.New("room must be a room id (!id:server) or alias (#alias:server)")
+	}
+
+	log := util.NewLogger("matrix").Redact(cc.Token)
+
+	m := &Matrix{
+		Helper: request.NewHelper(log),
+		log:    log,
+		uri:    strings.TrimRight(cc.URI, "/"),
+		token:  cc.Token,
+		room:   cc.Room,
+	}
+
+	return m, nil
+}
+
+func (m *Matrix) headers() map[string]string {
+	return map[string]string{
+		"Authorization": "Bearer " + m.token,
+		"Content-Type":  request.JSONContent,
+		"Accept":        request.JSONContent,
+	}
+}
+
+// roomID resolves the configured room alias
+func (m *Matrix) roomID() (string, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if strings.HasPrefix(m.room, "!") {
+		return m.room, nil
+	}
+
+	uri := fmt.Sprintf("%s/_matrix/client/v3/directory/room/%s", m.uri, url.PathEscape(m.room))
+	req, err := request.New(http.MethodGet, uri, nil, m.headers())
+	if err != nil {
+		return "", err
+	}
+
+	var res struct {
+		RoomID string `json:"room_id"`
+	}
+
+	if err := m.DoJSON(req, &res); err != nil {
+		return "", fmt.Errorf("resolve room: %w", err)
+	}
+
+	m.room = res.RoomID
+
+	return m.room, nil
+}
+
+// Send sends to the room
+func (m *Matrix) Send(title, msg string) {
+	room, err := m.roomID()
+	if err != nil {
+		m.log.ERROR.Println(err)
+		return
+	}
+
+	body := msg
+	if title != "" {
+		body = title + "\n\n" + msg
+	}
+
+	data := struct {
+		MsgType string `json:"msgtype"`
+		Body    string `json:"body"`
+	}{
+		MsgType: "m.text",
+		Body:    body,
+	}
+
+	// transaction id makes retries idempotent
+	txn := fmt.Sprintf("evcc-%d", time.Now().UnixNano())
+	uri := fmt.Sprintf("%s/_matrix/client/v3/rooms/%s/send/m.room.message/%s", m.uri, url.PathEscape(room), txn)
+
+	req, err := request.New(http.MethodPut, uri, request.MarshalJSON(data), m.headers())
+	if err == nil {
+		_, err = m.DoBody(req)
+	}
+
+	if err != nil {
+		m.log.ERROR.Println("send:", err)
+	}
+}
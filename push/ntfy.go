@@ -2,9 +2,12 @@ package push
 
 import (
 	"errors"
+	"fmt"
 	"net/http"
 	"strings"
+	"sync"
 
+	"github.com/evcc-io/evcc/core/site"
 	"github.com/evcc-io/evcc/util"
 	"github.com/evcc-io/evcc/util/request"
 )
@@ -13,20 +16,32 @@ func init() {
 	registry.Add("ntfy", NewNtfyFromConfig)
 }
 
-// Ntfy implements the ntfy messaging aggregator
+// ntfyMaxActions is the maximum number of action buttons per message
+const ntfyMaxActions = 3
+
+// Ntfy implements the ntfy messaging aggregator. The uri can also be a UnifiedPush endpoint.
 type Ntfy struct {
-	log      *util.Logger
-	uri      string
-	priority string
-	tags     string
+	log        *util.Logger
+	uri        string
+	priority   string
+	priorities map[Severity]string
+	tags       string
+	api        string // evcc url for action callbacks
+	token      string // evcc api token for action callbacks
+
+	mu   sync.Mutex
+	site site.API
 }
 
 // NewNtfyFromConfig creates new Ntfy messenger
 func NewNtfyFromConfig(other map[string]any) (Messenger, error) {
 	var cc struct {
-		URI      string
-		Priority string
-		Tags     string
+		URI        string
+		Priority   string
+		Priorities map[string]string // severity to ntfy priority
+		Tags       string
+		URL        string // evcc url for actions
+		Token      string // evcc api token with control scope for actions
 	}
 
 	if err := util.DecodeOther(other, &cc); err != nil {
@@ -37,33 +52,127 @@ func NewNtfyFromConfig(other map[string]any) (Messenger, error) {
 		return nil, errors.New("missing uri")
 	}
 
-	log := util.NewLogger("ntfy")
+	if (cc.URL == "") != (cc.Token == "") {
+		return nil, errors.New("actions require both url and token")
+	}
+
+	log := util.NewLogger("ntfy").Redact(cc.Token)
 	if token, ok := strings.CutPrefix(cc.URI, "https://ntfy.sh/"); ok {
 		log = log.Redact(token)
 	}
 
+	priorities := map[Severity]string{
+		SeverityInfo:     "default",
+		SeverityWarn:     "high",
+		SeverityCritical: "urgent",
+	}
+
+	for k, v := range cc.Priorities {
+		s, err := SeverityString(k)
+		if err != nil {
+			return nil, err
+		}
+		priorities[s] = v
+	}
+
 	m := &Ntfy{
-		log:      log,
-		uri:      cc.URI,
-		priority: cc.Priority,
-		tags:     cc.Tags,
+		log:        log,
+		uri:        cc.URI,
+		priority:   cc.Priority,
+		priorities: priorities,
+		tags:       cc.Tags,
+		api:        strings.TrimRight(cc.URL, "/"),
+		token:      cc.Token,
 	}
 
 	return m, nil
 }
 
+// Commands enables vehicle actions for the site
+func (m *Ntfy) Commands(site site.API, _ *util.ParamCache) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	m.site = site
+}
+
+// action formats an http action button calling the evcc api
+func (m *Ntfy) action(label, path string) string {
+	// commas and semicolons separate actions and fields
+	label = strings.NewReplacer(",", " ", ";", " ").Replace(label)
+	return fmt.Sprintf("http, %s, %s/api/%s, method=POST, headers.Authorization=Bearer %s, clear=true", label, m.api, path, m.token)
+}
+
+// actions returns the action buttons for the event
+func (m *Ntfy) actions(ev Event) []string {
+	if m.api == "" || ev.Loadpoint == nil {
+		return nil
+	}
+
+	lp := fmt.Sprintf("loadpoints/%d", *ev.Loadpoint+1)
+
+	var res []string
+
+	switch ev.Event {
+	case "connect":
+		res = append(res, m.action("Start boost", lp+"/batteryboost/1"), m.action("Fast charge", lp+"/mode/now"))
+
+	case "guest":
+		m.mu.Lock()
+		site := m.site
+		m.mu.Unlock()
+
+		if site == nil {
+			return nil
+		}
+
+		for _, v := range site.Vehicles().Settings() {
+			if len(res) == ntfyMaxActions {
+				break
+			}
+			res = append(res, m.action("Confirm "+v.Instance().GetTitle(), lp+"/vehicle/"+v.Name()))
+		}
+	}
+
+	return res
+}
+
 // Send sends to all receivers
 func (m *Ntfy) Send(title, msg string) {
-	req, err := request.New("POST", m.uri, strings.NewReader(msg), map[string]string{
-		"Priority": m.priority,
+	m.send(title, msg, m.priority, nil)
+}
+
+// SendEvent implements the EventSender interface
+func (m *Ntfy) SendEvent(ev Event, title, msg string) {
+	priority := m.priority
+	if priority == "" {
+		priority = m.priorities[EventSeverity(ev.Event)]
+	}
+
+	m.send(title, msg, priority, m.actions(ev))
+}
+
+func (m *Ntfy) send(title, msg, priority string, actions []string) {
+	headers := map[string]string{
+		"Priority": priority,
 		"Title":    title,
 		"Tags":     m.tags,
-	})
+	}
+
+	if len(actions) > 0 {
+		headers["Actions"] = strings.Join(actions, "; ")
+	}
+
+	req, err := request.New(http.MethodPost, m.uri, strings.NewReader(msg), headers)
 	if err != nil {
 		m.log.ERROR.Printf("ntfy: %v", err)
+		return
 	}
 
-	if _, err := http.DefaultClient.Do(req); err != nil {
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
 		m.log.ERROR.Printf("ntfy: %v", err)
+		return
 	}
+	resp.Body.Close()
 }
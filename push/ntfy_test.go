@@ -0,0 +1,41 @@
+package push
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNtfyEvent(t *testing.T) {
+	var header http.Header
+
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		header = r.Header
+	}))
+	defer srv.Close()
+
+	m, err := NewNtfyFromConfig(map[string]any{
+		"uri":   srv.URL + "/evcc",
+		"url":   "http://evcc.local:7070/",
+		"token": "evcc_token",
+	})
+	require.NoError(t, err)
+
+	lp := 0
+	m.(EventSender).SendEvent(Event{Loadpoint: &lp, Event: "connect"}, "title", "msg")
+
+	assert.Equal(t, "default", header.Get("Priority"))
+	assert.Equal(t, "http, Start boost, http://evcc.local:7070/api/loadpoints/1/batteryboost/1, method=POST, headers.Authorization=Bearer evcc_token, clear=true; "+
+		"http, Fast charge, http://evcc.local:7070/api/loadpoints/1/mode/now, method=POST, headers.Authorization=Bearer evcc_token, clear=true", header.Get("Actions"))
+
+	m.(EventSender).SendEvent(Event{Event: "fault"}, "title", "msg")
+
+	assert.Equal(t, "urgent", header.Get("Priority"))
+	assert.Empty(t, header.Get("Actions"))
+
+	_, err = NewNtfyFromConfig(map[string]any{"uri": srv.URL, "url": "http://evcc.local:7070"})
+	assert.Error(t, err)
+}
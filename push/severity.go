@@ -0,0 +1,27 @@
+package push
+
+//go:generate go tool enumer -type Severity -trimprefix Severity -transform=lower -text
+
+// Severity classifies events by urgency
+type Severity int
+
+const (
+	SeverityInfo     Severity = iota // regular operation like charge start or stop
+	SeverityWarn                     // unexpected but not requiring immediate action
+	SeverityCritical                 // device outage or security event requiring attention
+)
+
+// severities are the event severities, info if not listed
+var severities = map[string]Severity{
+	"guest":    SeverityWarn,
+	"asleep":   SeverityWarn,
+	"fault":    SeverityCritical, // charger status unavailable
+	"meter":    SeverityCritical, // grid or pv meter unavailable
+	"security": SeverityCritical, // ocpp security event
+	"rejected": SeverityWarn,     // ocpp id tag rejected by authorization policy
+}
+
+// EventSeverity returns the event's severity
+func EventSeverity(event string) Severity {
+	return severities[event]
+}
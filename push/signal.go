@@ -0,0 +1,104 @@
+package push
+
+import (
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+
+	"github.com/evcc-io/evcc/util"
+	"github.com/evcc-io/evcc/util/request"
+)
+
+func init() {
+	registry.Add("signal", NewSignalFromConfig)
+}
+
+// Signal implements the Signal messenger using the signal-cli REST api
+type Signal struct {
+	*request.Helper
+	log        *util.Logger
+	uri        string
+	number     string
+	recipients []string
+}
+
+// NewSignalFromConfig creates new Signal messenger
+func NewSignalFromConfig(other map[string]any) (Messenger, error) {
+	var cc struct {
+		URI        string
+		Number     string
+		Recipients []string
+	}
+
+	if err := util.DecodeOther(other, &cc); err != nil {
+		return nil, err
+	}
+
+	if cc.URI == "" {
+		return nil, errors.New("missing uri")
+	}
+
+	if cc.Number == "" {
+		return nil, errors.New("missing number")
+	}
+
+	if len(cc.Recipients) == 0 {
+		return nil, errors.New("missing recipients")
+	}
+
+	log := util.NewLogger("signal").Redact(cc.Number)
+	log.Redact(cc.Recipients...)
+
+	m := &Signal{
+		Helper:     request.NewHelper(log),
+		log:        log,
+		uri:        strings.TrimRight(cc.URI, "/"),
+		number:     cc.Number,
+		recipients: cc.Recipients,
+	}
+
+	return m, nil
+}
+
+// Send sends to all receivers
+func (m *Signal) Send(title, msg string) {
+	m.send(title, msg, nil)
+}
+
+// SendWithAttachment sends to all receivers including the attachment
+func (m *Signal) SendWithAttachment(title, msg string, attachment Attachment) {
+	m.send(title, msg, &attachment)
+}
+
+func (m *Signal) send(title, msg string, attachment *Attachment) {
+	data := struct {
+		Message     string   `json:"message"`
+		Number      string   `json:"number"`
+		Recipients  []string `json:"recipients"`
+		Attachments []string `json:"base64_attachments,omitempty"`
+	}{
+		Message:    msg,
+		Number:     m.number,
+		Recipients: m.recipients,
+	}
+
+	if title != "" {
+		data.Message = title + "\n\n" + msg
+	}
+
+	if attachment != nil {
+		data.Attachments = []string{fmt.Sprintf("data:%s;filename=%s;base64,%s",
+			attachment.ContentType, attachment.Name, base64.StdEncoding.EncodeToString(attachment.Data))}
+	}
+
+	req, err := request.New(http.MethodPost, m.uri+"/v2/send", request.MarshalJSON(data), request.JSONEncoding)
+	if err == nil {
+		_, err = m.DoBody(req)
+	}
+
+	if err != nil {
+		m.log.ERROR.Println("send:", err)
+	}
+}
@@ -0,0 +1,337 @@
+package push
+
+import (
+	"bytes"
+	"context"
+	"crypto/tls"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"maps"
+	"mime"
+	"mime/multipart"
+	"net"
+	"net/smtp"
+	"net/textproto"
+	"slices"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/evcc-io/evcc/core/session"
+	"github.com/evcc-io/evcc/server/db"
+	"github.com/evcc-io/evcc/util"
+	"github.com/evcc-io/evcc/util/locale"
+)
+
+func init() {
+	registry.Add("smtp", NewSMTPFromConfig)
+}
+
+// SMTP implements the email messenger with optional daily or weekly summary reports
+type SMTP struct {
+	log      *util.Logger
+	host     string
+	port     int
+	user     string
+	password string
+	from     string
+	to       []string
+
+	mu   sync.Mutex
+	lang string // report language
+}
+
+var (
+	msgSummaryTitle   = &locale.Message{ID: "backend.summary.title", Other: "Charging summary {from} - {to}"}
+	msgSummaryLine    = &locale.Message{ID: "backend.summary.line", Other: "{name}: {sessions} sessions, {energy} kWh, {solar}% solar, {price} cost"}
+	msgSummaryTotal   = &locale.Message{ID: "backend.summary.total", Other: "Total"}
+	msgSummaryUnknown = &locale.Message{ID: "backend.summary.unknownVehicle", Other: "Unknown vehicle"}
+)
+
+// NewSMTPFromConfig creates new SMTP messenger
+func NewSMTPFromConfig(other map[string]any) (Messenger, error) {
+	cc := struct {
+		Host     string
+		Port     int
+		User     string
+		Password string
+		From     string
+		To       []string
+		Report   string // daily or weekly
+	}{
+		Port: 587,
+	}
+
+	if err := util.DecodeOther(other, &cc); err != nil {
+		return nil, err
+	}
+
+	if cc.Host == "" {
+		return nil, errors.New("missing host")
+	}
+
+	if cc.From == "" || len(cc.To) == 0 {
+		return nil, errors.New("missing from or to address")
+	}
+
+	if cc.Report != "" && !slices.Contains([]string{"daily", "weekly"}, cc.Report) {
+		return nil, fmt.Errorf("invalid report: %s", cc.Report)
+	}
+
+	m := &SMTP{
+		log:      util.NewLogger("smtp").Redact(cc.Password),
+		host:     cc.Host,
+		port:     cc.Port,
+		user:     cc.User,
+		password: cc.Password,
+		from:     cc.From,
+		to:       cc.To,
+	}
+
+	if cc.Report != "" {
+		go m.reports(cc.Report)
+	}
+
+	return m, nil
+}
+
+var _ Localizer = (*SMTP)(nil)
+
+// SetLanguage sets the report language
+func (m *SMTP) SetLanguage(lang string) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	m.lang = lang
+}
+
+// Send sends to all receivers
+func (m *SMTP) Send(title, msg string) {
+	if err := m.send(title, msg, nil); err != nil {
+		m.log.ERROR.Println("send:", err)
+	}
+}
+
+// SendWithAttachment implements the AttachmentSender interface
+func (m *SMTP) SendWithAttachment(title, msg string, attachment Attachment) {
+	if err := m.send(title, msg, &attachment); err != nil {
+		m.log.ERROR.Println("send:", err)
+	}
+}
+
+// message creates the mime message
+func (m *SMTP) message(title, msg string, attachment *Attachment) ([]byte, error) {
+	var b bytes.Buffer
+
+	fmt.Fprintf(&b, "From: %s\r\n", m.from)
+	fmt.Fprintf(&b, "To: %s\r\n", strings.Join(m.to, ", "))
+	fmt.Fprintf(&b, "Subject: %s\r\n", mime.QEncoding.Encode("utf-8", title))
+	fmt.Fprintf(&b, "Date: %s\r\n", time.Now().Format(time.RFC1123Z))
+	fmt.Fprint(&b, "MIME-Version: 1.0\r\n")
+
+	if attachment == nil {
+		fmt.Fprint(&b, "Content-Type: text/plain; charset=utf-8\r\n\r\n")
+		b.WriteString(strings.ReplaceAll(msg, "\n", "\r\n"))
+		return b.Bytes(), nil
+	}
+
+	mw := multipart.NewWriter(&b)
+	fmt.Fprintf(&b, "Content-Type: multipart/mixed; boundary=%s\r\n\r\n", mw.Boundary())
+
+	w, err := mw.CreatePart(textproto.MIMEHeader{"Content-Type": {"text/plain; charset=utf-8"}})
+	if err != nil {
+		return nil, err
+	}
+	if _, err := w.Write([]byte(strings.ReplaceAll(msg, "\n", "\r\n"))); err != nil {
+		return nil, err
+	}
+
+	if w, err = mw.CreatePart(textproto.MIMEHeader{
+		"Content-Type":              {attachment.ContentType},
+		"Content-Transfer-Encoding": {"base64"},
+		"Content-Disposition":       {mime.FormatMediaType("attachment", map[string]string{"filename": attachment.Name})},
+	}); err != nil {
+		return nil, err
+	}
+
+	enc := base64.NewEncoder(base64.StdEncoding, w)
+	if _, err := enc.Write(attachment.Data); err != nil {
+		return nil, err
+	}
+	if err := enc.Close(); err != nil {
+		return nil, err
+	}
+
+	if err := mw.Close(); err != nil {
+		return nil, err
+	}
+
+	return b.Bytes(), nil
+}
+
+// send delivers the message using implicit tls on port 465 or starttls if supported by the server
+func (m *SMTP) send(title, msg string, attachment *Attachment) error {
+	data, err := m.message(title, msg, attachment)
+	if err != nil {
+		return err
+	}
+
+	addr := net.JoinHostPort(m.host, strconv.Itoa(m.port))
+	tlsConfig := &tls.Config{ServerName: m.host}
+
+	var conn net.Conn
+	if m.port == 465 {
+		conn, err = tls.Dial("tcp", addr, tlsConfig)
+	} else {
+		conn, err = net.DialTimeout("tcp", addr, 30*time.Second)
+	}
+	if err != nil {
+		return err
+	}
+
+	c, err := smtp.NewClient(conn, m.host)
+	if err != nil {
+		conn.Close()
+		return err
+	}
+	defer c.Close()
+
+	if ok, _ := c.Extension("STARTTLS"); ok && m.port != 465 {
+		if err := c.StartTLS(tlsConfig); err != nil {
+			return err
+		}
+	}
+
+	if m.user != "" {
+		if err := c.Auth(smtp.PlainAuth("", m.user, m.password, m.host)); err != nil {
+			return err
+		}
+	}
+
+	if err := c.Mail(m.from); err != nil {
+		return err
+	}
+
+	for _, to := range m.to {
+		if err := c.Rcpt(to); err != nil {
+			return err
+		}
+	}
+
+	w, err := c.Data()
+	if err != nil {
+		return err
+	}
+
+	if _, err := w.Write(data); err != nil {
+		return err
+	}
+
+	if err := w.Close(); err != nil {
+		return err
+	}
+
+	return c.Quit()
+}
+
+// reportPeriod returns the last completed report period before now
+func reportPeriod(now time.Time, period string) (time.Time, time.Time) {
+	to := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, now.Location())
+
+	if period == "weekly" {
+		// weeks start on monday
+		to = to.AddDate(0, 0, -(int(to.Weekday())+6)%7)
+		return to.AddDate(0, 0, -7), to
+	}
+
+	return to.AddDate(0, 0, -1), to
+}
+
+// reports sends the summary report after each completed period
+func (m *SMTP) reports(period string) {
+	for {
+		_, to := reportPeriod(time.Now(), period)
+
+		next := to.AddDate(0, 0, 1)
+		if period == "weekly" {
+			next = to.AddDate(0, 0, 7)
+		}
+
+		time.Sleep(time.Until(next))
+
+		if err := m.report(reportPeriod(time.Now(), period)); err != nil {
+			m.log.ERROR.Println("report:", err)
+		}
+	}
+}
+
+// report sends the summary of the period's charging sessions including the pdf report
+func (m *SMTP) report(from, to time.Time) error {
+	if db.Instance == nil {
+		return errors.New("database offline")
+	}
+
+	r, err := session.NewReport(from, to)
+	if err != nil {
+		return err
+	}
+
+	m.mu.Lock()
+	lang := m.lang
+	m.mu.Unlock()
+
+	title := locale.Localize(lang, msgSummaryTitle, map[string]any{
+		"from": from.Format(time.DateOnly),
+		"to":   to.AddDate(0, 0, -1).Format(time.DateOnly),
+	})
+
+	var b bytes.Buffer
+	ctx := context.WithValue(context.Background(), locale.Locale, lang)
+	if err := r.WritePdf(ctx, &b); err != nil {
+		return err
+	}
+
+	return m.send(title, reportSummary(lang, r), &Attachment{
+		Name:        "report-" + from.Format(time.DateOnly) + ".pdf",
+		ContentType: "application/pdf",
+		Data:        b.Bytes(),
+	})
+}
+
+// reportSummary renders the report totals and per-vehicle breakdown as text
+func reportSummary(lang string, r *session.Report) string {
+	var b strings.Builder
+
+	line := func(name string, t session.ReportTotals) {
+		var solar float64
+		if t.ChargedEnergy > 0 {
+			solar = 100 * t.SolarEnergy / t.ChargedEnergy
+		}
+		b.WriteString(locale.Localize(lang, msgSummaryLine, map[string]any{
+			"name":     name,
+			"sessions": t.Sessions,
+			"energy":   fmt.Sprintf("%.1f", t.ChargedEnergy),
+			"solar":    fmt.Sprintf("%.0f", solar),
+			"price":    fmt.Sprintf("%.2f", t.Price),
+		}) + "\n")
+	}
+
+	line(locale.Localize(lang, msgSummaryTotal, nil), r.Totals())
+
+	vehicles := r.VehicleTotals()
+	if len(vehicles) > 0 {
+		b.WriteString("\n")
+	}
+
+	for _, v := range slices.Sorted(maps.Keys(vehicles)) {
+		name := v
+		if name == "" {
+			name = locale.Localize(lang, msgSummaryUnknown, nil)
+		}
+		line(name, vehicles[v])
+	}
+
+	return b.String()
+}
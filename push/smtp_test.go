@@ -0,0 +1,41 @@
+package push
+
+import (
+	"testing"
+	"time"
+
+	"github.com/evcc-io/evcc/core/session"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSMTPReportPeriod(t *testing.T) {
+	// wednesday
+	now := time.Date(2025, 10, 15, 8, 0, 0, 0, time.Local)
+
+	from, to := reportPeriod(now, "daily")
+	assert.Equal(t, time.Date(2025, 10, 14, 0, 0, 0, 0, time.Local), from)
+	assert.Equal(t, time.Date(2025, 10, 15, 0, 0, 0, 0, time.Local), to)
+
+	from, to = reportPeriod(now, "weekly")
+	assert.Equal(t, time.Date(2025, 10, 6, 0, 0, 0, 0, time.Local), from)
+	assert.Equal(t, time.Date(2025, 10, 13, 0, 0, 0, 0, time.Local), to)
+
+	// sunday
+	_, to = reportPeriod(time.Date(2025, 10, 19, 8, 0, 0, 0, time.Local), "weekly")
+	assert.Equal(t, time.Date(2025, 10, 13, 0, 0, 0, 0, time.Local), to)
+}
+
+func TestSMTPReportSummary(t *testing.T) {
+	solar := 50.0
+
+	r := &session.Report{
+		Sessions: session.Sessions{
+			{Vehicle: "car", ChargedEnergy: 10, SolarPercentage: &solar},
+			{Vehicle: "bike", ChargedEnergy: 2},
+		},
+	}
+
+	assert.Equal(t, "Total: 2 sessions, 12.0 kWh, 42% solar, 0.00 cost\n\n"+
+		"bike: 1 sessions, 2.0 kWh, 0% solar, 0.00 cost\n"+
+		"car: 1 sessions, 10.0 kWh, 50% solar, 0.00 cost\n", reportSummary("", r))
+}
@@ -4,8 +4,10 @@ import (
 	"context"
 	"errors"
 	"strconv"
+	"strings"
 	"sync"
 
+	"github.com/evcc-io/evcc/core/site"
 	"github.com/evcc-io/evcc/util"
 	"github.com/go-telegram/bot"
 	"github.com/go-telegram/bot/models"
@@ -19,15 +21,20 @@ func init() {
 type Telegram struct {
 	log *util.Logger
 	sync.Mutex
-	bot   *bot.Bot
-	chats map[int64]struct{}
+	bot       *bot.Bot
+	chats     map[int64]struct{}
+	operators map[int64]struct{} // chats allowed to control
+	site      site.API
+	cache     *util.ParamCache
+	lang      string // command reply language
 }
 
 // NewTelegramFromConfig creates new pushover messenger
 func NewTelegramFromConfig(ctx context.Context, other map[string]any) (Messenger, error) {
 	var cc struct {
-		Token string
-		Chats []int64
+		Token     string
+		Chats     []int64
+		Operators []int64
 	}
 
 	if err := util.DecodeOther(other, &cc); err != nil {
@@ -37,8 +44,9 @@ func NewTelegramFromConfig(ctx context.Context, other map[string]any) (Messenger
 	log := util.NewLogger("telegram").Redact(cc.Token)
 
 	m := &Telegram{
-		log:   log,
-		chats: make(map[int64]struct{}),
+		log:       log,
+		chats:     make(map[int64]struct{}),
+		operators: make(map[int64]struct{}),
 	}
 
 	bot, err := bot.New(cc.Token, bot.WithDefaultHandler(m.handler), bot.WithErrorsHandler(func(err error) {
@@ -59,21 +67,32 @@ func NewTelegramFromConfig(ctx context.Context, other map[string]any) (Messenger
 		m.chats[chat] = struct{}{}
 	}
 
+	for _, chat := range cc.Operators {
+		log.Redact(strconv.FormatInt(chat, 10))
+		m.operators[chat] = struct{}{}
+	}
+
 	return m, nil
 }
 
-// handler captures ids of all chats that bot participates in
+// handler captures ids of all chats that bot participates in and answers commands
 func (m *Telegram) handler(ctx context.Context, b *bot.Bot, update *models.Update) {
 	if update.Message == nil {
 		return
 	}
 
 	m.Lock()
-	defer m.Unlock()
+	_, chat := m.chats[update.Message.Chat.ID]
+	_, operator := m.operators[update.Message.Chat.ID]
+	m.Unlock()
 
-	if _, ok := m.chats[update.Message.Chat.ID]; !ok {
+	if !chat && !operator {
 		m.log.INFO.Printf("new chat id: %d", update.Message.Chat.ID)
 	}
+
+	if strings.HasPrefix(update.Message.Text, "/") {
+		m.command(ctx, b, update.Message)
+	}
 }
 
 // Send sends to all receivers
@@ -0,0 +1,260 @@
+package push
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/evcc-io/evcc/api"
+	"github.com/evcc-io/evcc/core/keys"
+	"github.com/evcc-io/evcc/core/loadpoint"
+	"github.com/evcc-io/evcc/core/site"
+	"github.com/evcc-io/evcc/server/db/audit"
+	"github.com/evcc-io/evcc/util"
+	"github.com/evcc-io/evcc/util/locale"
+	"github.com/go-telegram/bot"
+	"github.com/go-telegram/bot/models"
+	"github.com/spf13/cast"
+)
+
+var (
+	msgTelegramHelp = &locale.Message{ID: "backend.telegram.help", Other: `/status - site and loadpoint status
+/mode [loadpoint] off|now|minpv|pv - set charge mode
+/boost [loadpoint] [off] - start or stop battery boost
+/vehicle [loadpoint] [name] - assign vehicle or list vehicles`}
+	msgTelegramPv        = &locale.Message{ID: "backend.telegram.pv", Other: "PV"}
+	msgTelegramGrid      = &locale.Message{ID: "backend.telegram.grid", Other: "Grid"}
+	msgTelegramHome      = &locale.Message{ID: "backend.telegram.home", Other: "Home"}
+	msgTelegramBattery   = &locale.Message{ID: "backend.telegram.battery", Other: "Battery"}
+	msgTelegramLoadpoint = &locale.Message{ID: "backend.telegram.loadpoint", Other: "Loadpoint {id}"}
+	msgTelegramCharging  = &locale.Message{ID: "backend.telegram.charging", Other: "charging"}
+	msgTelegramConnected = &locale.Message{ID: "backend.telegram.connected", Other: "connected"}
+)
+
+var _ Localizer = (*Telegram)(nil)
+
+// SetLanguage sets the command reply language
+func (m *Telegram) SetLanguage(lang string) {
+	m.Lock()
+	defer m.Unlock()
+
+	m.lang = lang
+}
+
+// Commands enables bot commands for the site
+func (m *Telegram) Commands(site site.API, cache *util.ParamCache) {
+	m.Lock()
+	defer m.Unlock()
+
+	m.site = site
+	m.cache = cache
+}
+
+// command handles bot commands and sends the reply
+func (m *Telegram) command(ctx context.Context, b *bot.Bot, msg *models.Message) {
+	reply, err := m.execute(msg.Chat.ID, msg.Text)
+	if err != nil {
+		reply = err.Error()
+	}
+
+	if _, err := b.SendMessage(ctx, &bot.SendMessageParams{
+		ChatID: msg.Chat.ID,
+		Text:   reply,
+	}); err != nil {
+		m.log.ERROR.Println("send:", err)
+	}
+}
+
+// execute authorizes and executes the command text for the chat
+func (m *Telegram) execute(chat int64, text string) (string, error) {
+	m.Lock()
+	site, cache, lang := m.site, m.cache, m.lang
+	_, reader := m.chats[chat]
+	_, operator := m.operators[chat]
+	m.Unlock()
+
+	if site == nil {
+		return "", errors.New("not ready")
+	}
+
+	if !reader && !operator {
+		return "", fmt.Errorf("unauthorized chat: %d", chat)
+	}
+
+	fields := strings.Fields(text)
+	cmd := strings.ToLower(strings.SplitN(fields[0], "@", 2)[0]) // strip bot name
+	args := fields[1:]
+
+	switch cmd {
+	case "/start", "/help":
+		return locale.Localize(lang, msgTelegramHelp, nil), nil
+	case "/status":
+		return m.status(lang, site, cache), nil
+	}
+
+	if !operator {
+		return "", errors.New("not authorized for control")
+	}
+
+	var (
+		res string
+		err error
+	)
+
+	switch cmd {
+	case "/mode":
+		res, err = m.mode(site, args)
+	case "/boost":
+		res, err = m.boost(site, args)
+	case "/vehicle":
+		res, err = m.vehicle(site, args)
+	default:
+		return "", fmt.Errorf("unknown command: %s\n\n%s", cmd, locale.Localize(lang, msgTelegramHelp, nil))
+	}
+
+	audit.Log(audit.SourceTelegram, strconv.FormatInt(chat, 10), cmd, strings.Join(args, " "), err)
+
+	return res, err
+}
+
+// loadpoint returns the loadpoint and remaining arguments. The loadpoint id may be omitted for single loadpoints.
+func (m *Telegram) loadpoint(site site.API, args []string) (int, loadpoint.API, []string, error) {
+	lps := site.Loadpoints()
+
+	if len(args) > 0 {
+		if id, err := strconv.Atoi(args[0]); err == nil {
+			if id < 1 || id > len(lps) {
+				return 0, nil, nil, fmt.Errorf("invalid loadpoint: %d", id)
+			}
+			return id, lps[id-1], args[1:], nil
+		}
+	}
+
+	if len(lps) != 1 {
+		return 0, nil, nil, errors.New("missing loadpoint")
+	}
+
+	return 1, lps[0], args, nil
+}
+
+func (m *Telegram) status(lang string, site site.API, cache *util.ParamCache) string {
+	var b strings.Builder
+
+	if title := site.GetTitle(); title != "" {
+		fmt.Fprintln(&b, title)
+	}
+
+	kw := func(w float64) string {
+		return fmt.Sprintf("%.1f kW", w/1e3)
+	}
+
+	// grid measurement is published as struct
+	var grid struct {
+		Power float64 `json:"power"`
+	}
+	if v := cache.Get(keys.Grid).Val; v != nil {
+		if bytes, err := json.Marshal(v); err == nil {
+			_ = json.Unmarshal(bytes, &grid)
+		}
+	}
+
+	text := func(msg *locale.Message) string {
+		return locale.Localize(lang, msg, nil)
+	}
+
+	fmt.Fprintf(&b, "%s: %s\n", text(msgTelegramPv), kw(cast.ToFloat64(cache.Get(keys.PvPower).Val)))
+	fmt.Fprintf(&b, "%s: %s\n", text(msgTelegramGrid), kw(grid.Power))
+	fmt.Fprintf(&b, "%s: %s\n", text(msgTelegramHome), kw(cast.ToFloat64(cache.Get(keys.HomePower).Val)))
+
+	if soc := cache.Get(keys.BatterySoc).Val; soc != nil {
+		fmt.Fprintf(&b, "%s: %s (%.0f%%)\n", text(msgTelegramBattery), kw(cast.ToFloat64(cache.Get(keys.BatteryPower).Val)), cast.ToFloat64(soc))
+	}
+
+	for i, lp := range site.Loadpoints() {
+		title := lp.GetTitle()
+		if title == "" {
+			title = locale.Localize(lang, msgTelegramLoadpoint, map[string]any{"id": i + 1})
+		}
+
+		fmt.Fprintf(&b, "\n%d %s: %s", i+1, title, lp.GetMode())
+
+		switch lp.GetStatus() {
+		case api.StatusC:
+			fmt.Fprintf(&b, ", %s %s", text(msgTelegramCharging), kw(lp.GetChargePower()))
+		case api.StatusB:
+			fmt.Fprintf(&b, ", %s", text(msgTelegramConnected))
+		}
+
+		if v := lp.GetVehicle(); v != nil {
+			fmt.Fprintf(&b, ", %s %.0f%%", v.GetTitle(), lp.GetSoc())
+		}
+	}
+
+	return b.String()
+}
+
+func (m *Telegram) mode(site site.API, args []string) (string, error) {
+	id, lp, args, err := m.loadpoint(site, args)
+	if err != nil {
+		return "", err
+	}
+
+	if len(args) != 1 {
+		return "", errors.New("missing mode")
+	}
+
+	mode, err := api.ChargeModeString(args[0])
+	if err != nil {
+		return "", err
+	}
+
+	lp.SetMode(mode)
+
+	return fmt.Sprintf("loadpoint %d: mode %s", id, mode), nil
+}
+
+func (m *Telegram) boost(site site.API, args []string) (string, error) {
+	id, lp, args, err := m.loadpoint(site, args)
+	if err != nil {
+		return "", err
+	}
+
+	enable := len(args) == 0 || !strings.EqualFold(args[0], "off")
+	if err := lp.SetBatteryBoost(enable); err != nil {
+		return "", err
+	}
+
+	if !enable {
+		return fmt.Sprintf("loadpoint %d: boost stopped", id), nil
+	}
+
+	return fmt.Sprintf("loadpoint %d: boost started", id), nil
+}
+
+func (m *Telegram) vehicle(site site.API, args []string) (string, error) {
+	id, lp, args, err := m.loadpoint(site, args)
+	if err != nil {
+		return "", err
+	}
+
+	if len(args) == 0 {
+		var names []string
+		for _, v := range site.Vehicles().Settings() {
+			names = append(names, v.Name())
+		}
+
+		return "vehicles: " + strings.Join(names, ", "), nil
+	}
+
+	v, err := site.Vehicles().ByName(args[0])
+	if err != nil {
+		return "", err
+	}
+
+	lp.SetVehicle(v.Instance())
+
+	return fmt.Sprintf("loadpoint %d: vehicle %s", id, v.Instance().GetTitle()), nil
+}
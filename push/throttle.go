@@ -0,0 +1,224 @@
+package push
+
+import (
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/benbjohnson/clock"
+	"github.com/evcc-io/evcc/util"
+)
+
+// QuietHours is the daily period during which messages are held back, e.g. 22:00 to 07:00
+type QuietHours struct {
+	From, To string
+}
+
+// ThrottleConfig is the per-messenger delivery configuration
+type ThrottleConfig struct {
+	Quiet  QuietHours    // messages are delivered as digest after quiet hours
+	Dedupe time.Duration // identical messages within this period are suppressed
+	Digest time.Duration // messages following the first message within this period are delivered as digest
+}
+
+// Configured returns true if any throttling is configured
+func (c ThrottleConfig) Configured() bool {
+	return c.Quiet != QuietHours{} || c.Dedupe > 0 || c.Digest > 0
+}
+
+type notification struct {
+	title, msg string
+}
+
+// Throttle wraps a messenger with quiet hours, deduplication and digesting of bursts
+type Throttle struct {
+	Messenger
+	log   *util.Logger
+	clock clock.Clock
+
+	from, to time.Duration // quiet hours as time of day
+	dedupe   time.Duration
+	digest   time.Duration
+
+	mu      sync.Mutex
+	seen    map[notification]time.Time
+	until   time.Time // end of current digest window
+	pending []notification
+	timer   *clock.Timer
+}
+
+// NewThrottle creates a throttled messenger
+func NewThrottle(m Messenger, conf ThrottleConfig) (*Throttle, error) {
+	t := &Throttle{
+		Messenger: m,
+		log:       util.NewLogger("push"),
+		clock:     clock.New(),
+		dedupe:    conf.Dedupe,
+		digest:    conf.Digest,
+		seen:      make(map[notification]time.Time),
+	}
+
+	if conf.Quiet != (QuietHours{}) {
+		var err error
+		if t.from, err = timeOfDay(conf.Quiet.From); err != nil {
+			return nil, fmt.Errorf("quiet hours: %w", err)
+		}
+		if t.to, err = timeOfDay(conf.Quiet.To); err != nil {
+			return nil, fmt.Errorf("quiet hours: %w", err)
+		}
+		if t.from == t.to {
+			return nil, fmt.Errorf("quiet hours: empty period")
+		}
+	}
+
+	return t, nil
+}
+
+func timeOfDay(s string) (time.Duration, error) {
+	ts, err := time.Parse("15:04", s)
+	if err != nil {
+		return 0, fmt.Errorf("invalid time: %s", s)
+	}
+	return time.Duration(ts.Hour())*time.Hour + time.Duration(ts.Minute())*time.Minute, nil
+}
+
+// quietUntil returns the end of the quiet hours or zero time if not quiet
+func (t *Throttle) quietUntil(now time.Time) time.Time {
+	if t.from == t.to {
+		return time.Time{}
+	}
+
+	midnight := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, now.Location())
+	tod := now.Sub(midnight)
+
+	switch {
+	case t.from < t.to && tod >= t.from && tod < t.to:
+		return midnight.Add(t.to)
+	case t.from > t.to && tod >= t.from:
+		return midnight.AddDate(0, 0, 1).Add(t.to)
+	case t.from > t.to && tod < t.to:
+		return midnight.Add(t.to)
+	}
+
+	return time.Time{}
+}
+
+// schedule flushes pending messages at the given time
+func (t *Throttle) schedule(ts time.Time) {
+	if t.timer != nil {
+		t.timer.Stop()
+	}
+	t.timer = t.clock.AfterFunc(ts.Sub(t.clock.Now()), t.flush)
+}
+
+// Send implements the Messenger interface
+func (t *Throttle) Send(title, msg string) {
+	if t.hold(notification{title, msg}) {
+		return
+	}
+
+	t.Messenger.Send(title, msg)
+}
+
+// SendWithAttachment implements the AttachmentSender interface. Attachments of held messages are dropped.
+func (t *Throttle) SendWithAttachment(title, msg string, attachment Attachment) {
+	if t.hold(notification{title, msg}) {
+		return
+	}
+
+	if as, ok := t.Messenger.(AttachmentSender); ok {
+		as.SendWithAttachment(title, msg, attachment)
+		return
+	}
+
+	t.Messenger.Send(title, msg)
+}
+
+// SendEvent implements the EventSender interface. Event details of held messages are dropped.
+func (t *Throttle) SendEvent(ev Event, title, msg string) {
+	if t.hold(notification{title, msg}) {
+		return
+	}
+
+	if es, ok := t.Messenger.(EventSender); ok {
+		es.SendEvent(ev, title, msg)
+		return
+	}
+
+	t.Messenger.Send(title, msg)
+}
+
+// hold returns true if the message must not be sent immediately
+func (t *Throttle) hold(m notification) bool {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	now := t.clock.Now()
+
+	if t.dedupe > 0 {
+		for k, ts := range t.seen {
+			if now.Sub(ts) >= t.dedupe {
+				delete(t.seen, k)
+			}
+		}
+
+		if _, ok := t.seen[m]; ok {
+			t.log.DEBUG.Printf("suppressing duplicate message: %s", m.title)
+			return true
+		}
+
+		t.seen[m] = now
+	}
+
+	if until := t.quietUntil(now); !until.IsZero() {
+		if len(t.pending) == 0 || t.until.Before(until) {
+			t.until = until
+			t.schedule(until)
+		}
+		t.pending = append(t.pending, m)
+		return true
+	}
+
+	if t.digest > 0 {
+		if now.Before(t.until) {
+			t.pending = append(t.pending, m)
+			return true
+		}
+
+		// first message of burst opens digest window
+		t.until = now.Add(t.digest)
+		t.schedule(t.until)
+	}
+
+	return false
+}
+
+// flush sends pending messages, combined as digest if more than one
+func (t *Throttle) flush() {
+	t.mu.Lock()
+	pending := t.pending
+	t.pending = nil
+	t.mu.Unlock()
+
+	switch len(pending) {
+	case 0:
+		return
+	case 1:
+		t.Messenger.Send(pending[0].title, pending[0].msg)
+		return
+	}
+
+	var b strings.Builder
+	for i, m := range pending {
+		if i > 0 {
+			b.WriteString("\n")
+		}
+		if m.title != "" {
+			b.WriteString(m.title + ": ")
+		}
+		b.WriteString(m.msg)
+	}
+
+	t.Messenger.Send(fmt.Sprintf("%d notifications", len(pending)), b.String())
+}
@@ -0,0 +1,84 @@
+package push
+
+import (
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/benbjohnson/clock"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type recorder struct {
+	mu   sync.Mutex
+	sent []notification
+}
+
+func (r *recorder) Send(title, msg string) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.sent = append(r.sent, notification{title, msg})
+}
+
+func (r *recorder) reset() []notification {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	res := r.sent
+	r.sent = nil
+	return res
+}
+
+func TestThrottleDedupeDigest(t *testing.T) {
+	rec := new(recorder)
+
+	th, err := NewThrottle(rec, ThrottleConfig{Dedupe: time.Hour, Digest: 5 * time.Minute})
+	require.NoError(t, err)
+
+	clk := clock.NewMock()
+	th.clock = clk
+
+	th.Send("fault", "charger offline")
+	assert.Equal(t, []notification{{"fault", "charger offline"}}, rec.reset())
+
+	// burst is digested, duplicates suppressed
+	th.Send("fault", "charger offline")
+	th.Send("fault", "charger error")
+	th.Send("stop", "charging finished")
+	assert.Empty(t, rec.reset())
+
+	clk.Add(5 * time.Minute)
+	assert.Equal(t, []notification{{"2 notifications", "fault: charger error\nstop: charging finished"}}, rec.reset())
+
+	// duplicate allowed after dedupe period
+	clk.Add(time.Hour)
+	th.Send("fault", "charger offline")
+	assert.Len(t, rec.reset(), 1)
+}
+
+func TestThrottleQuietHours(t *testing.T) {
+	rec := new(recorder)
+
+	th, err := NewThrottle(rec, ThrottleConfig{Quiet: QuietHours{From: "22:00", To: "07:00"}})
+	require.NoError(t, err)
+
+	clk := clock.NewMock()
+	clk.Set(time.Date(2025, 1, 1, 21, 0, 0, 0, time.Local))
+	th.clock = clk
+
+	th.Send("a", "before quiet hours")
+	assert.Len(t, rec.reset(), 1)
+
+	clk.Add(2 * time.Hour)
+	th.Send("b", "during quiet hours")
+	assert.Empty(t, rec.reset())
+
+	clk.Add(7*time.Hour + 59*time.Minute)
+	assert.Empty(t, rec.reset())
+
+	clk.Add(time.Minute)
+	assert.Equal(t, []notification{{"b", "during quiet hours"}}, rec.reset())
+
+	_, err = NewThrottle(rec, ThrottleConfig{Quiet: QuietHours{From: "22:00", To: "7"}})
+	assert.Error(t, err)
+}
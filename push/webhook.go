@@ -0,0 +1,216 @@
+package push
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"slices"
+	"strconv"
+	"strings"
+	"text/template"
+	"time"
+
+	"github.com/Masterminds/sprig/v3"
+	"github.com/cenkalti/backoff/v4"
+	"github.com/evcc-io/evcc/api/globalconfig"
+	"github.com/evcc-io/evcc/util"
+	"github.com/evcc-io/evcc/util/request"
+)
+
+func init() {
+	registry.Add("webhook", NewWebhookMessengerFromConfig)
+}
+
+const (
+	// WebhookTimestampHeader is the unix timestamp of the request
+	WebhookTimestampHeader = "X-Evcc-Timestamp"
+	// WebhookSignatureHeader is the hex encoded HMAC-SHA256 of timestamp and body, formatted as sha256=<signature>
+	WebhookSignatureHeader = "X-Evcc-Signature"
+)
+
+// Webhook sends event attributes to an http endpoint
+type Webhook struct {
+	*request.Helper
+	log     *util.Logger
+	dlq     *util.Logger // undeliverable payloads
+	uri     string
+	method  string
+	events  []string
+	headers map[string]string
+	body    string
+	secret  string
+	retries int
+}
+
+// NewWebhook creates a webhook from configuration
+func NewWebhook(cc globalconfig.Webhook) (*Webhook, error) {
+	if cc.Uri == "" {
+		return nil, errors.New("missing uri")
+	}
+
+	if cc.Method == "" {
+		cc.Method = http.MethodPost
+	}
+
+	if cc.Timeout == 0 {
+		cc.Timeout = request.Timeout
+	}
+
+	if cc.Body != "" {
+		if _, err := template.New("out").Funcs(sprig.FuncMap()).Parse(cc.Body); err != nil {
+			return nil, fmt.Errorf("invalid body: %w", err)
+		}
+	}
+
+	log := util.NewLogger("webhook").Redact(cc.Secret)
+
+	wh := &Webhook{
+		Helper:  request.NewHelper(log),
+		log:     log,
+		dlq:     util.NewLogger("deadletter"),
+		uri:     cc.Uri,
+		method:  strings.ToUpper(cc.Method),
+		events:  cc.Events,
+		headers: cc.Headers,
+		body:    cc.Body,
+		secret:  cc.Secret,
+		retries: cc.Retries,
+	}
+
+	wh.Client.Timeout = cc.Timeout
+
+	return wh, nil
+}
+
+// Matches returns true if the webhook is configured for the event
+func (wh *Webhook) Matches(event string) bool {
+	return len(wh.events) == 0 || slices.Contains(wh.events, event)
+}
+
+// payload creates the request body from the event attributes
+func (wh *Webhook) payload(event string, attr map[string]any) (string, error) {
+	kv := make(map[string]any, len(attr)+1)
+	for k, v := range attr {
+		kv[k] = v
+	}
+	kv["event"] = event
+
+	return wh.render(kv)
+}
+
+// render creates the request body from the body template or as json
+func (wh *Webhook) render(kv map[string]any) (string, error) {
+	if wh.body != "" {
+		return util.ReplaceFormatted(wh.body, kv)
+	}
+
+	b, err := json.Marshal(kv)
+	return string(b), err
+}
+
+// Signature returns the HMAC-SHA256 signature of timestamp and body. Receivers verify the signature
+// by computing it from the timestamp header and raw body and should reject outdated timestamps.
+func Signature(secret, timestamp, body string) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write([]byte(timestamp + "." + body))
+	return "sha256=" + hex.EncodeToString(mac.Sum(nil))
+}
+
+// Send sends the event to the webhook endpoint, retrying on failure
+func (wh *Webhook) Send(event string, attr map[string]any) {
+	body, err := wh.payload(event, attr)
+	if err != nil {
+		wh.log.ERROR.Printf("%s: invalid payload: %v", event, err)
+		return
+	}
+
+	wh.deliver(event, body)
+}
+
+// deliver sends the body to the webhook endpoint, retrying on failure.
+// Undeliverable payloads are logged to the dead letter log.
+func (wh *Webhook) deliver(event, body string) {
+	bo := backoff.WithMaxRetries(backoff.NewExponentialBackOff(
+		backoff.WithInitialInterval(time.Second),
+	), uint64(wh.retries))
+
+	if err := backoff.Retry(func() error {
+		headers := map[string]string{
+			"Content-Type": request.JSONContent,
+		}
+		for k, v := range wh.headers {
+			headers[k] = v
+		}
+
+		// sign each attempt with current timestamp
+		if wh.secret != "" {
+			ts := strconv.FormatInt(time.Now().Unix(), 10)
+			headers[WebhookTimestampHeader] = ts
+			headers[WebhookSignatureHeader] = Signature(wh.secret, ts, body)
+		}
+
+		req, err := request.New(wh.method, wh.uri, strings.NewReader(body), headers)
+		if err != nil {
+			return backoff.Permanent(err)
+		}
+
+		resp, err := wh.Do(req)
+		if err != nil {
+			return err
+		}
+		defer resp.Body.Close()
+
+		if resp.StatusCode >= http.StatusBadRequest {
+			err := request.NewStatusError(resp)
+			// don't retry client errors except rate limiting
+			if resp.StatusCode < http.StatusInternalServerError && resp.StatusCode != http.StatusTooManyRequests {
+				return backoff.Permanent(err)
+			}
+			return err
+		}
+
+		return nil
+	}, bo); err != nil {
+		wh.log.ERROR.Printf("%s: %v", event, err)
+		wh.dlq.ERROR.Printf("%s %s %s: %s", wh.method, wh.uri, event, body)
+	}
+}
+
+// WebhookMessenger sends title and message to an http endpoint
+type WebhookMessenger struct {
+	wh *Webhook
+}
+
+// NewWebhookMessengerFromConfig creates new webhook messenger
+func NewWebhookMessengerFromConfig(other map[string]any) (Messenger, error) {
+	var cc globalconfig.Webhook
+
+	if err := util.DecodeOther(other, &cc); err != nil {
+		return nil, err
+	}
+
+	wh, err := NewWebhook(cc)
+	if err != nil {
+		return nil, err
+	}
+
+	return &WebhookMessenger{wh: wh}, nil
+}
+
+// Send sends the message as json or using the body template
+func (m *WebhookMessenger) Send(title, msg string) {
+	body, err := m.wh.render(map[string]any{
+		"title": title,
+		"msg":   msg,
+	})
+	if err != nil {
+		m.wh.log.ERROR.Printf("invalid payload: %v", err)
+		return
+	}
+
+	m.wh.deliver("message", body)
+}
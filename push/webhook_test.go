@@ -0,0 +1,36 @@
+package push
+
+import (
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestWebhookSignature(t *testing.T) {
+	var body, ts, sig string
+
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		b, _ := io.ReadAll(r.Body)
+		body = string(b)
+		ts = r.Header.Get(WebhookTimestampHeader)
+		sig = r.Header.Get(WebhookSignatureHeader)
+	}))
+	defer srv.Close()
+
+	m, err := NewWebhookMessengerFromConfig(map[string]any{
+		"uri":    srv.URL,
+		"secret": "secret",
+	})
+	require.NoError(t, err)
+
+	m.Send("title", "msg")
+
+	assert.JSONEq(t, `{"title":"title","msg":"msg"}`, body)
+	assert.NotEmpty(t, ts)
+	assert.Equal(t, Signature("secret", ts, body), sig)
+	assert.NotEqual(t, Signature("other", ts, body), sig)
+}
@@ -0,0 +1,231 @@
+package backup
+
+import (
+	"archive/tar"
+	"bytes"
+	"compress/gzip"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/evcc-io/evcc/util"
+	"gorm.io/gorm"
+)
+
+const (
+	manifestFile = "manifest.json"
+	databaseFile = "evcc.db"
+	configFile   = "evcc.yaml"
+
+	// maxFileSize limits extracted files to protect against decompression bombs
+	maxFileSize = 1 << 30
+)
+
+// Manifest describes the archive contents
+type Manifest struct {
+	Version string    `json:"version"`
+	Created time.Time `json:"created"`
+	Files   []string  `json:"files"`
+}
+
+// Snapshot creates a consistent copy of the open database
+func Snapshot(db *gorm.DB, file string) error {
+	_ = os.Remove(file)
+	return db.Exec("VACUUM INTO ?", file).Error
+}
+
+// Create writes a tar.gz archive containing the database and the optional config file.
+// The database file must not be modified while the archive is created, see Snapshot.
+func Create(w io.Writer, dbFile, cfgFile string) error {
+	gw := gzip.NewWriter(w)
+	tw := tar.NewWriter(gw)
+
+	m := Manifest{
+		Version: util.Version,
+		Created: time.Now(),
+	}
+
+	files := map[string]string{databaseFile: dbFile}
+	if cfgFile != "" {
+		files[configFile] = cfgFile
+	}
+
+	for _, name := range []string{databaseFile, configFile} {
+		if files[name] != "" {
+			m.Files = append(m.Files, name)
+		}
+	}
+
+	b, err := json.MarshalIndent(m, "", "  ")
+	if err != nil {
+		return err
+	}
+
+	if err := writeEntry(tw, manifestFile, int64(len(b)), m.Created, bytes.NewReader(b)); err != nil {
+		return err
+	}
+
+	for _, name := range m.Files {
+		if err := addFile(tw, name, files[name]); err != nil {
+			return err
+		}
+	}
+
+	if err := tw.Close(); err != nil {
+		return err
+	}
+
+	return gw.Close()
+}
+
+func addFile(tw *tar.Writer, name, file string) error {
+	f, err := os.Open(file)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+
+	fi, err := f.Stat()
+	if err != nil {
+		return err
+	}
+
+	return writeEntry(tw, name, fi.Size(), fi.ModTime(), f)
+}
+
+func writeEntry(tw *tar.Writer, name string, size int64, modTime time.Time, r io.Reader) error {
+	if err := tw.WriteHeader(&tar.Header{
+		Name:    name,
+		Mode:    0o600,
+		Size:    size,
+		ModTime: modTime,
+	}); err != nil {
+		return err
+	}
+
+	_, err := io.Copy(tw, r)
+	return err
+}
+
+// Restore extracts the archive, replacing the database and, if cfgFile is not empty, the config file.
+// Existing files are kept with .bak extension.
+func Restore(r io.Reader, dbFile, cfgFile string) (Manifest, error) {
+	var m Manifest
+
+	gr, err := gzip.NewReader(r)
+	if err != nil {
+		return m, fmt.Errorf("invalid archive: %w", err)
+	}
+	defer gr.Close()
+
+	tmp, err := os.MkdirTemp(filepath.Dir(dbFile), "restore")
+	if err != nil {
+		return m, err
+	}
+	defer os.RemoveAll(tmp)
+
+	// extract to temp dir first to not leave partial results
+	tr := tar.NewReader(gr)
+	for {
+		hdr, err := tr.Next()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			return m, fmt.Errorf("invalid archive: %w", err)
+		}
+
+		switch hdr.Name {
+		case manifestFile:
+			if err := json.NewDecoder(io.LimitReader(tr, maxFileSize)).Decode(&m); err != nil {
+				return m, fmt.Errorf("invalid manifest: %w", err)
+			}
+
+		case databaseFile, configFile:
+			if err := extract(tr, filepath.Join(tmp, hdr.Name)); err != nil {
+				return m, err
+			}
+
+		default:
+			return m, fmt.Errorf("unexpected file: %s", hdr.Name)
+		}
+	}
+
+	if m.Created.IsZero() {
+		return m, errors.New("invalid archive: missing manifest")
+	}
+
+	targets := map[string]string{databaseFile: dbFile, configFile: cfgFile}
+
+	for _, name := range m.Files {
+		target := targets[name]
+		if target == "" {
+			continue
+		}
+
+		src := filepath.Join(tmp, name)
+		if _, err := os.Stat(src); err != nil {
+			return m, fmt.Errorf("invalid archive: missing %s", name)
+		}
+
+		if err := replace(src, target); err != nil {
+			return m, err
+		}
+	}
+
+	return m, nil
+}
+
+func extract(r io.Reader, file string) error {
+	f, err := os.OpenFile(file, os.O_CREATE|os.O_WRONLY|os.O_TRUNC, 0o600)
+	if err != nil {
+		return err
+	}
+
+	if _, err := io.Copy(f, io.LimitReader(r, maxFileSize)); err != nil {
+		f.Close()
+		return err
+	}
+
+	return f.Close()
+}
+
+// replace moves src to target, keeping the previous target as backup
+func replace(src, target string) error {
+	if _, err := os.Stat(target); err == nil {
+		if err := copyFile(target, target+".bak"); err != nil {
+			return fmt.Errorf("backup %s: %w", target, err)
+		}
+	}
+
+	// rename may fail across file systems
+	if err := os.Rename(src, target); err == nil {
+		return nil
+	}
+
+	return copyFile(src, target)
+}
+
+func copyFile(src, dst string) error {
+	in, err := os.Open(src)
+	if err != nil {
+		return err
+	}
+	defer in.Close()
+
+	out, err := os.OpenFile(dst, os.O_CREATE|os.O_WRONLY|os.O_TRUNC, 0o600)
+	if err != nil {
+		return err
+	}
+
+	if _, err := io.Copy(out, in); err != nil {
+		out.Close()
+		return err
+	}
+
+	return out.Close()
+}
@@ -0,0 +1,44 @@
+package backup
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCreateRestore(t *testing.T) {
+	src := t.TempDir()
+	dbFile := filepath.Join(src, "evcc.db")
+	cfgFile := filepath.Join(src, "evcc.yaml")
+
+	require.NoError(t, os.WriteFile(dbFile, []byte("db"), 0o600))
+	require.NoError(t, os.WriteFile(cfgFile, []byte("cfg"), 0o600))
+
+	var buf bytes.Buffer
+	require.NoError(t, Create(&buf, dbFile, cfgFile))
+
+	dst := t.TempDir()
+	dstDb := filepath.Join(dst, "evcc.db")
+	require.NoError(t, os.WriteFile(dstDb, []byte("old"), 0o600))
+
+	m, err := Restore(&buf, dstDb, "")
+	require.NoError(t, err)
+	assert.Equal(t, []string{databaseFile, configFile}, m.Files)
+
+	b, err := os.ReadFile(dstDb)
+	require.NoError(t, err)
+	assert.Equal(t, "db", string(b))
+
+	b, err = os.ReadFile(dstDb + ".bak")
+	require.NoError(t, err)
+	assert.Equal(t, "old", string(b))
+}
+
+func TestRestoreInvalid(t *testing.T) {
+	_, err := Restore(bytes.NewReader([]byte("foo")), filepath.Join(t.TempDir(), "evcc.db"), "")
+	assert.Error(t, err)
+}
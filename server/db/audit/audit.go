@@ -0,0 +1,94 @@
+package audit
+
+import (
+	"time"
+
+	"github.com/evcc-io/evcc/server/db"
+	"github.com/evcc-io/evcc/util"
+)
+
+// Sources of audited actions
+const (
+	SourceApi  = "api"
+	SourceMqtt = "mqtt"
+	SourceGrpc = "grpc"
+	SourceOcpi = "ocpi"
+
+	SourceTelegram = "telegram"
+)
+
+// Entry is a single audited configuration or control action
+type Entry struct {
+	ID      uint      `json:"id" gorm:"primarykey"`
+	Created time.Time `json:"created" gorm:"index"`
+	Source  string    `json:"source"`
+	User    string    `json:"user,omitempty"`
+	Action  string    `json:"action"`
+	Value   string    `json:"value,omitempty"`
+	Error   string    `json:"error,omitempty"`
+}
+
+func (Entry) TableName() string {
+	return "audit"
+}
+
+var log = util.NewLogger("audit")
+
+func Init() error {
+	return db.AutoMigrate(new(Entry))
+}
+
+// Log records an action. Without database, the action is only logged.
+func Log(source, user, action, value string, err error) {
+	entry := Entry{
+		Created: time.Now(),
+		Source:  source,
+		User:    user,
+		Action:  action,
+		Value:   value,
+	}
+
+	if err != nil {
+		entry.Error = err.Error()
+	}
+
+	log.DEBUG.Printf("%s %s: %s %s", source, user, action, value)
+
+	if db.Instance == nil {
+		return
+	}
+
+	if err := db.Instance.Create(&entry).Error; err != nil {
+		log.ERROR.Println(err)
+	}
+}
+
+// Entries returns the audited actions in the given time range, newest first.
+// Zero times and limit are ignored.
+func Entries(from, to time.Time, source string, limit int) ([]Entry, error) {
+	res := []Entry{}
+
+	tx := db.Instance.Order("created desc, id desc")
+
+	if !from.IsZero() {
+		tx = tx.Where("created >= ?", from)
+	}
+	if !to.IsZero() {
+		tx = tx.Where("created < ?", to)
+	}
+	if source != "" {
+		tx = tx.Where("source = ?", source)
+	}
+	if limit > 0 {
+		tx = tx.Limit(limit)
+	}
+
+	err := tx.Find(&res).Error
+	return res, err
+}
+
+// Purge deletes entries created before the given time
+func Purge(before time.Time) (int64, error) {
+	txn := db.Instance.Where("created < ?", before).Delete(new(Entry))
+	return txn.RowsAffected, txn.Error
+}
@@ -18,7 +18,7 @@ type Cache struct {
 }
 
 func Init() error {
-	return db.Instance.AutoMigrate(new(Cache))
+	return db.AutoMigrate(new(Cache))
 }
 
 func Put(key string, value any) error {
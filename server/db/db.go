@@ -9,6 +9,8 @@ import (
 	"github.com/evcc-io/evcc/util"
 	"github.com/glebarez/sqlite"
 	"github.com/mitchellh/go-homedir"
+	"gorm.io/driver/mysql"
+	"gorm.io/driver/postgres"
 	"gorm.io/gorm"
 )
 
@@ -27,8 +29,6 @@ func New(driver, dsn string) (*gorm.DB, error) {
 			return nil, err
 		}
 
-		// Store the expanded file path for later use
-		FilePath = file
 		if err := os.MkdirAll(filepath.Dir(file), 0700); err != nil {
 			return nil, err
 		}
@@ -37,12 +37,15 @@ func New(driver, dsn string) (*gorm.DB, error) {
 
 		// avoid busy errors
 		dialect = sqlite.Open(file + "?_pragma=busy_timeout(5000)")
-	// case "postgres":
-	// 	dialect = postgres.Open(dsn)
-	// case "mysql":
-	// 	dialect = mysql.Open(dsn)
+	case "postgres":
+		dialect = postgres.Open(dsn)
+	case "mysql":
+		dialect = mysql.New(mysql.Config{
+			DSN:               dsn,
+			DefaultStringSize: 256, // fit string primary keys into index size limits
+		})
 	default:
-		return nil, fmt.Errorf("invalid database type: %s not in [sqlite]", driver)
+		return nil, fmt.Errorf("invalid database type: %s not in [sqlite, postgres, mysql]", driver)
 	}
 
 	return gorm.Open(dialect, &gorm.Config{
@@ -51,7 +54,16 @@ func New(driver, dsn string) (*gorm.DB, error) {
 }
 
 func NewInstance(driver, dsn string) (err error) {
-	Instance, err = New(strings.ToLower(driver), dsn)
+	driver = strings.ToLower(driver)
+
+	Instance, err = New(driver, dsn)
+
+	// store the expanded file path for backups
+	FilePath = ""
+	if err == nil && driver == "sqlite" {
+		FilePath, err = homedir.Expand(dsn)
+	}
+
 	return
 }
 
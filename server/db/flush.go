@@ -0,0 +1,32 @@
+package db
+
+import (
+	"errors"
+	"sync"
+)
+
+var (
+	flushMu  sync.Mutex
+	flushers []func() error
+)
+
+// RegisterFlusher registers a function persisting coalesced writes
+func RegisterFlusher(fn func() error) {
+	flushMu.Lock()
+	defer flushMu.Unlock()
+
+	flushers = append(flushers, fn)
+}
+
+// Flush persists all coalesced writes
+func Flush() error {
+	flushMu.Lock()
+	defer flushMu.Unlock()
+
+	var errs []error
+	for _, fn := range flushers {
+		errs = append(errs, fn())
+	}
+
+	return errors.Join(errs...)
+}
@@ -0,0 +1,319 @@
+// Package journal persists volatile runtime state using a write-ahead journal.
+//
+// Every change is appended to the journal file, so state survives unexpected restarts of
+// the process. To reduce storage wear, changes are synced to disk only by Sync, which is
+// called periodically and at session boundaries, and on Close. Consumers delete entries
+// once the state has been checkpointed to the database or is no longer needed. The file
+// is compacted when it grows well beyond the number of live entries.
+package journal
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"errors"
+	"io"
+	"maps"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"sync"
+)
+
+// minCompact is the minimum number of records before the journal is compacted
+const minCompact = 100
+
+var ErrNotFound = errors.New("not found")
+
+// Instance is the default journal. All methods are no-ops if it has not been opened.
+var Instance *Journal
+
+// NewInstance opens the default journal
+func NewInstance(path string) (err error) {
+	Instance, err = Open(path)
+	return
+}
+
+// record is a single journal line. A missing value deletes the key.
+type record struct {
+	Key   string          `json:"k"`
+	Value json.RawMessage `json:"v,omitempty"`
+}
+
+// Journal is an append-only key value store
+type Journal struct {
+	mu      sync.Mutex
+	path    string
+	file    *os.File
+	entries map[string]json.RawMessage
+	records int
+	dirty   bool // records written but not synced
+}
+
+// Open opens the journal file and replays its records. Replay stops at the first
+// incomplete record, which happens if writing was interrupted.
+func Open(path string) (*Journal, error) {
+	if err := os.MkdirAll(filepath.Dir(path), 0o700); err != nil {
+		return nil, err
+	}
+
+	j := &Journal{
+		path:    path,
+		entries: make(map[string]json.RawMessage),
+	}
+
+	if err := j.replay(); err != nil {
+		return nil, err
+	}
+
+	// drop torn records and superseded values
+	if err := j.compact(); err != nil {
+		return nil, err
+	}
+
+	return j, nil
+}
+
+func (j *Journal) replay() error {
+	f, err := os.Open(j.path)
+	if errors.Is(err, os.ErrNotExist) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+
+	r := bufio.NewReader(f)
+	for {
+		line, err := r.ReadBytes('\n')
+		if errors.Is(err, io.EOF) {
+			// incomplete trailing record
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+
+		var rec record
+		if err := json.Unmarshal(line, &rec); err != nil || rec.Key == "" {
+			return nil
+		}
+
+		j.apply(rec)
+	}
+}
+
+func (j *Journal) apply(rec record) {
+	if rec.Value == nil {
+		delete(j.entries, rec.Key)
+	} else {
+		j.entries[rec.Key] = rec.Value
+	}
+}
+
+// compact rewrites the journal containing only live entries. Lock must be held.
+func (j *Journal) compact() error {
+	if j.file != nil {
+		if err := j.file.Close(); err != nil {
+			return err
+		}
+		j.file = nil
+	}
+
+	tmp := j.path + ".tmp"
+	f, err := os.OpenFile(tmp, os.O_CREATE|os.O_TRUNC|os.O_WRONLY, 0o600)
+	if err != nil {
+		return err
+	}
+
+	var b bytes.Buffer
+	for _, key := range slices.Sorted(maps.Keys(j.entries)) {
+		if err := encode(&b, record{Key: key, Value: j.entries[key]}); err != nil {
+			f.Close()
+			return err
+		}
+	}
+
+	if _, err = f.Write(b.Bytes()); err == nil {
+		err = f.Sync()
+	}
+	if err := errors.Join(err, f.Close()); err != nil {
+		return err
+	}
+
+	if err := os.Rename(tmp, j.path); err != nil {
+		return err
+	}
+
+	j.records, j.dirty = len(j.entries), false
+	j.file, err = os.OpenFile(j.path, os.O_APPEND|os.O_WRONLY, 0o600)
+
+	return err
+}
+
+func encode(b *bytes.Buffer, rec record) error {
+	line, err := json.Marshal(rec)
+	if err == nil {
+		b.Write(line)
+		b.WriteByte('\n')
+	}
+	return err
+}
+
+// append writes the records before applying them. Lock must be held.
+func (j *Journal) append(recs ...record) error {
+	if j.file == nil {
+		return os.ErrClosed
+	}
+
+	var b bytes.Buffer
+	for _, rec := range recs {
+		if err := encode(&b, rec); err != nil {
+			return err
+		}
+	}
+
+	if _, err := j.file.Write(b.Bytes()); err != nil {
+		return err
+	}
+	j.dirty = true
+
+	for _, rec := range recs {
+		j.apply(rec)
+	}
+
+	j.records += len(recs)
+	if j.records > minCompact && j.records > 4*len(j.entries) {
+		return j.compact()
+	}
+
+	return nil
+}
+
+// Set stores the json-encoded value. Unchanged values are not written.
+func (j *Journal) Set(key string, val any) error {
+	if j == nil {
+		return nil
+	}
+
+	b, err := json.Marshal(val)
+	if err != nil {
+		return err
+	}
+
+	j.mu.Lock()
+	defer j.mu.Unlock()
+
+	if v, ok := j.entries[key]; ok && bytes.Equal(v, b) {
+		return nil
+	}
+
+	return j.append(record{Key: key, Value: b})
+}
+
+// Get decodes the stored value
+func (j *Journal) Get(key string, val any) error {
+	if j == nil {
+		return ErrNotFound
+	}
+
+	j.mu.Lock()
+	defer j.mu.Unlock()
+
+	b, ok := j.entries[key]
+	if !ok {
+		return ErrNotFound
+	}
+
+	return json.Unmarshal(b, val)
+}
+
+// Delete removes the keys
+func (j *Journal) Delete(keys ...string) error {
+	if j == nil {
+		return nil
+	}
+
+	j.mu.Lock()
+	defer j.mu.Unlock()
+
+	var recs []record
+	for _, key := range keys {
+		if _, ok := j.entries[key]; ok {
+			recs = append(recs, record{Key: key})
+		}
+	}
+
+	if len(recs) == 0 {
+		return nil
+	}
+
+	return j.append(recs...)
+}
+
+// Keys returns the sorted keys with given prefix
+func (j *Journal) Keys(prefix string) []string {
+	if j == nil {
+		return nil
+	}
+
+	j.mu.Lock()
+	defer j.mu.Unlock()
+
+	var res []string
+	for key := range j.entries {
+		if strings.HasPrefix(key, prefix) {
+			res = append(res, key)
+		}
+	}
+	slices.Sort(res)
+
+	return res
+}
+
+// Sync syncs the records written since the last sync to disk
+func (j *Journal) Sync() error {
+	if j == nil {
+		return nil
+	}
+
+	j.mu.Lock()
+	defer j.mu.Unlock()
+
+	return j.sync()
+}
+
+// sync syncs the journal file if changed. Lock must be held.
+func (j *Journal) sync() error {
+	if j.file == nil || !j.dirty {
+		return nil
+	}
+
+	if err := j.file.Sync(); err != nil {
+		return err
+	}
+	j.dirty = false
+
+	return nil
+}
+
+// Close syncs and closes the journal file
+func (j *Journal) Close() error {
+	if j == nil {
+		return nil
+	}
+
+	j.mu.Lock()
+	defer j.mu.Unlock()
+
+	if j.file == nil {
+		return nil
+	}
+
+	err := errors.Join(j.sync(), j.file.Close())
+	j.file = nil
+
+	return err
+}
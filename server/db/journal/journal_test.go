@@ -0,0 +1,92 @@
+package journal
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestReplay(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "evcc.journal")
+
+	j, err := Open(path)
+	require.NoError(t, err)
+
+	require.NoError(t, j.Set("a", 1))
+	require.NoError(t, j.Set("b", "foo"))
+	require.NoError(t, j.Set("a", 2))
+	require.NoError(t, j.Delete("b"))
+	require.NoError(t, j.Close())
+
+	// torn write
+	f, err := os.OpenFile(path, os.O_APPEND|os.O_WRONLY, 0o600)
+	require.NoError(t, err)
+	_, err = f.WriteString(`{"k":"c","v":`)
+	require.NoError(t, err)
+	require.NoError(t, f.Close())
+
+	j, err = Open(path)
+	require.NoError(t, err)
+	defer j.Close()
+
+	assert.Equal(t, []string{"a"}, j.Keys(""))
+
+	var a int
+	require.NoError(t, j.Get("a", &a))
+	assert.Equal(t, 2, a)
+
+	var b string
+	assert.ErrorIs(t, j.Get("b", &b), ErrNotFound)
+
+	// torn record is removed
+	require.NoError(t, j.Set("d", true))
+	j2, err := Open(path)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"a", "d"}, j2.Keys(""))
+}
+
+func TestCompact(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "evcc.journal")
+
+	j, err := Open(path)
+	require.NoError(t, err)
+	defer j.Close()
+
+	for i := range 2 * minCompact {
+		require.NoError(t, j.Set("a", i))
+	}
+
+	assert.LessOrEqual(t, j.records, minCompact+1)
+
+	var a int
+	require.NoError(t, j.Get("a", &a))
+	assert.Equal(t, 2*minCompact-1, a)
+}
+
+func TestNilJournal(t *testing.T) {
+	var j *Journal
+
+	assert.NoError(t, j.Set("a", 1))
+	assert.NoError(t, j.Delete("a"))
+	assert.ErrorIs(t, j.Get("a", new(int)), ErrNotFound)
+	assert.Empty(t, j.Keys(""))
+}
+
+func TestSync(t *testing.T) {
+	j, err := Open(filepath.Join(t.TempDir(), "evcc.journal"))
+	require.NoError(t, err)
+	defer j.Close()
+
+	require.NoError(t, j.Set("a", 1))
+	assert.True(t, j.dirty)
+
+	require.NoError(t, j.Sync())
+	assert.False(t, j.dirty)
+
+	// unchanged values are not written
+	require.NoError(t, j.Set("a", 1))
+	assert.False(t, j.dirty)
+}
@@ -0,0 +1,86 @@
+package db
+
+import (
+	"fmt"
+	"reflect"
+	"slices"
+
+	"gorm.io/gorm"
+)
+
+// models are the registered database models, used for copying between databases
+var models []any
+
+// AutoMigrate creates or updates the schema of the model and registers it for copying
+func AutoMigrate(model any) error {
+	if !slices.ContainsFunc(models, func(m any) bool {
+		return reflect.TypeOf(m) == reflect.TypeOf(model)
+	}) {
+		models = append(models, model)
+	}
+
+	return Instance.AutoMigrate(model)
+}
+
+// Empty returns true if none of the registered models has data
+func Empty() (bool, error) {
+	for _, model := range models {
+		var count int64
+		if err := Instance.Model(model).Count(&count).Error; err != nil || count > 0 {
+			return false, err
+		}
+	}
+
+	return true, nil
+}
+
+// Copy copies the data of all registered models from src into the database instance.
+// Tables missing in src are skipped. Data is copied in a single transaction.
+func Copy(src *gorm.DB) error {
+	return Instance.Transaction(func(tx *gorm.DB) error {
+		for _, model := range models {
+			if !src.Migrator().HasTable(model) {
+				continue
+			}
+
+			rows := reflect.New(reflect.SliceOf(reflect.TypeOf(model).Elem()))
+			if err := src.Model(model).Find(rows.Interface()).Error; err != nil {
+				return err
+			}
+
+			if rows.Elem().Len() == 0 {
+				continue
+			}
+
+			if err := tx.CreateInBatches(rows.Interface(), 500).Error; err != nil {
+				return err
+			}
+
+			if err := resetSequence(tx, model); err != nil {
+				return err
+			}
+		}
+
+		return nil
+	})
+}
+
+// resetSequence updates postgres auto increment sequences after inserting explicit ids
+func resetSequence(tx *gorm.DB, model any) error {
+	if tx.Dialector.Name() != "postgres" {
+		return nil
+	}
+
+	stmt := &gorm.Statement{DB: tx}
+	if err := stmt.Parse(model); err != nil {
+		return err
+	}
+
+	field := stmt.Schema.PrioritizedPrimaryField
+	if field == nil || !field.AutoIncrement {
+		return nil
+	}
+
+	query := fmt.Sprintf(`SELECT setval(pg_get_serial_sequence('%[1]s', '%[2]s'), MAX(%[2]s)) FROM %[1]s`, stmt.Schema.Table, field.DBName)
+	return tx.Exec(query).Error
+}
@@ -0,0 +1,54 @@
+package db
+
+import (
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type testEntry struct {
+	ID      uint `gorm:"primarykey"`
+	Created time.Time
+	Value   string
+}
+
+func TestCopy(t *testing.T) {
+	models = nil
+
+	src, err := New("sqlite", filepath.Join(t.TempDir(), "src.db"))
+	require.NoError(t, err)
+	require.NoError(t, src.AutoMigrate(new(testEntry)))
+
+	now := time.Now().Truncate(time.Second)
+	require.NoError(t, src.Create([]testEntry{{Created: now, Value: "a"}, {Created: now, Value: "b"}}).Error)
+
+	require.NoError(t, NewInstance("sqlite", filepath.Join(t.TempDir(), "dst.db")))
+	require.NoError(t, AutoMigrate(new(testEntry)))
+	require.NoError(t, AutoMigrate(new(testEntry)))
+	assert.Len(t, models, 1)
+
+	empty, err := Empty()
+	require.NoError(t, err)
+	assert.True(t, empty)
+
+	require.NoError(t, Copy(src))
+
+	var res []testEntry
+	require.NoError(t, Instance.Order("id").Find(&res).Error)
+	require.Len(t, res, 2)
+	assert.Equal(t, uint(2), res[1].ID)
+	assert.Equal(t, "b", res[1].Value)
+	assert.True(t, now.Equal(res[0].Created))
+
+	// new entries continue after copied ids
+	require.NoError(t, Instance.Create(&testEntry{Value: "c"}).Error)
+	require.NoError(t, Instance.Order("id").Find(&res).Error)
+	assert.Equal(t, uint(3), res[2].ID)
+
+	empty, err = Empty()
+	require.NoError(t, err)
+	assert.False(t, empty)
+}
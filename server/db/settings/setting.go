@@ -14,13 +14,17 @@ import (
 	"time"
 
 	"github.com/evcc-io/evcc/server/db"
+	"github.com/evcc-io/evcc/server/db/journal"
 	"github.com/evcc-io/evcc/util"
-	"github.com/samber/lo"
 	"go.yaml.in/yaml/v4"
+	"gorm.io/gorm"
 )
 
 var ErrNotFound = errors.New("not found")
 
+// journalPrefix is the journal key prefix of settings not yet persisted
+const journalPrefix = "settings."
+
 // setting is a settings entry
 type setting struct {
 	dirty bool
@@ -29,35 +33,65 @@ type setting struct {
 }
 
 var (
-	mu       sync.RWMutex
-	settings []setting
+	mu           sync.RWMutex
+	settings     []setting
+	registerOnce sync.Once
 )
 
 func Init() error {
-	err := db.Instance.AutoMigrate(new(setting))
+	err := db.AutoMigrate(new(setting))
 	if err == nil {
 		err = db.Instance.Find(&settings).Error
 	}
+	if err == nil {
+		replay()
+		registerOnce.Do(func() { db.RegisterFlusher(Persist) })
+	}
 	return err
 }
 
+// replay restores changed settings that were journaled but not persisted before an unexpected restart
+func replay() {
+	for _, key := range journal.Instance.Keys(journalPrefix) {
+		var val string
+		if err := journal.Instance.Get(key, &val); err == nil {
+			SetString(strings.TrimPrefix(key, journalPrefix), val)
+		}
+	}
+}
+
+// Persist writes all changed settings in a single transaction
 func Persist() error {
 	mu.Lock()
 	defer mu.Unlock()
 
-	if dirty := lo.FilterMap(settings, func(s setting, _ int) (*setting, bool) {
-		return &s, s.dirty
-	}); len(dirty) > 0 {
-		if err := db.Instance.Save(dirty).Error; err != nil {
-			return err
+	var dirty []setting
+	for _, s := range settings {
+		if s.dirty {
+			dirty = append(dirty, s)
 		}
+	}
 
-		for _, s := range dirty {
-			s.dirty = false
+	if len(dirty) == 0 {
+		return nil
+	}
+
+	if err := db.Instance.Transaction(func(tx *gorm.DB) error {
+		return tx.Save(dirty).Error
+	}); err != nil {
+		return err
+	}
+
+	keys := make([]string, 0, len(dirty))
+	for i := range settings {
+		if settings[i].dirty {
+			keys = append(keys, journalPrefix+settings[i].Key)
 		}
+		settings[i].dirty = false
 	}
 
-	return nil
+	// persisted settings no longer need to be journaled
+	return journal.Instance.Delete(keys...)
 }
 
 func All() []setting {
@@ -91,7 +125,7 @@ func Delete(key string) error {
 		settings = slices.Delete(settings, idx, idx+1)
 	}
 
-	return nil
+	return journal.Instance.Delete(journalPrefix + key)
 }
 
 func SetString(key string, val string) {
@@ -103,6 +137,13 @@ func SetString(key string, val string) {
 	} else if settings[idx].Value != val {
 		settings[idx].dirty = true
 		settings[idx].Value = val
+	} else {
+		return
+	}
+
+	// journal change until the next database flush
+	if err := journal.Instance.Set(journalPrefix+key, val); err != nil {
+		util.NewLogger("db").ERROR.Printf("journal: %v", err)
 	}
 }
 
@@ -2,8 +2,11 @@ package settings
 
 import (
 	"math"
+	"path/filepath"
 	"testing"
 
+	"github.com/evcc-io/evcc/server/db"
+	"github.com/evcc-io/evcc/server/db/journal"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 )
@@ -31,3 +34,47 @@ func TestFloat(t *testing.T) {
 	require.NoError(t, err)
 	assert.Equal(t, v, res)
 }
+
+func TestPersist(t *testing.T) {
+	require.NoError(t, db.NewInstance("sqlite", ":memory:"))
+	require.NoError(t, Init())
+
+	SetString("persist", "foo")
+	require.NoError(t, db.Flush())
+
+	mu.RLock()
+	for _, s := range settings {
+		assert.False(t, s.dirty, s.Key)
+	}
+	mu.RUnlock()
+
+	var res []setting
+	require.NoError(t, db.Instance.Find(&res, "key = ?", "persist").Error)
+	require.Len(t, res, 1)
+	assert.Equal(t, "foo", res[0].Value)
+}
+
+func TestJournalReplay(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "evcc.journal")
+	require.NoError(t, journal.NewInstance(path))
+	t.Cleanup(func() { journal.Instance = nil })
+
+	require.NoError(t, db.NewInstance("sqlite", ":memory:"))
+	require.NoError(t, Init())
+
+	SetString("journaled", "foo")
+
+	// restart without flushing
+	require.NoError(t, journal.Instance.Close())
+	require.NoError(t, journal.NewInstance(path))
+	settings = nil
+
+	require.NoError(t, Init())
+
+	res, err := String("journaled")
+	require.NoError(t, err)
+	assert.Equal(t, "foo", res)
+
+	require.NoError(t, db.Flush())
+	assert.Empty(t, journal.Instance.Keys(journalPrefix))
+}
@@ -0,0 +1,89 @@
+package server
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"time"
+)
+
+// eventsKeepalive is the interval for sending comments to keep proxies from closing idle streams
+const eventsKeepalive = 30 * time.Second
+
+// ServeEvents streams the socket messages as server-sent events for clients that cannot use websockets.
+// Messages and filters are identical to the websocket:
+//
+//	/events?topics=chargePower,gridPower&loadpoints=0,1&delta=true
+func (h *SocketHub) ServeEvents(w http.ResponseWriter, r *http.Request) {
+	filter, err := parseSocketFilter(r.URL.Query())
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	rc := http.NewResponseController(w)
+
+	// stream is long-lived, override server write timeout
+	if err := rc.SetWriteDeadline(time.Time{}); err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "text/event-stream")
+	w.Header().Set("Cache-Control", "no-cache")
+	w.Header().Set("Connection", "keep-alive")
+	w.Header().Set("X-Accel-Buffering", "no") // disable nginx buffering
+	w.WriteHeader(http.StatusOK)
+
+	if err := rc.Flush(); err != nil {
+		return
+	}
+
+	_ = h.subscribeEvents(r.Context(), w, rc, filter)
+}
+
+func (h *SocketHub) subscribeEvents(ctx context.Context, w http.ResponseWriter, rc *http.ResponseController, filter socketFilter) error {
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	s := &socketSubscriber{
+		send:      make(chan []byte, 1024),
+		closeSlow: cancel,
+		filter:    filter,
+		last:      make(map[string]string),
+	}
+
+	h.addSubscriber(s)
+	defer h.deleteSubscriber(s)
+
+	// send welcome message
+	h.register <- s
+
+	write := func(format string, a ...any) error {
+		if err := rc.SetWriteDeadline(time.Now().Add(socketWriteTimeout)); err != nil {
+			return err
+		}
+		if _, err := fmt.Fprintf(w, format, a...); err != nil {
+			return err
+		}
+		return rc.Flush()
+	}
+
+	keepalive := time.NewTicker(eventsKeepalive)
+	defer keepalive.Stop()
+
+	for {
+		select {
+		case msg := <-s.send:
+			if err := write("data: %s\n\n", msg); err != nil {
+				return err
+			}
+		case <-keepalive.C:
+			if err := write(": keepalive\n\n"); err != nil {
+				return err
+			}
+		case <-ctx.Done():
+			return ctx.Err()
+		}
+	}
+}
@@ -60,6 +60,9 @@ func NewHTTPd(addr string, hub *SocketHub, customCssFile string) *HTTPd {
 	// websocket
 	router.HandleFunc("/ws", socketHandler(hub))
 
+	// server-sent events
+	router.Methods(http.MethodGet).Path("/events").HandlerFunc(hub.ServeEvents)
+
 	// static - individual handlers per root and folders
 	static := router.PathPrefix("/").Subrouter()
 	static.Use(handlers.CompressHandler)
@@ -118,7 +121,7 @@ func (s *HTTPd) Router() *mux.Router {
 }
 
 // RegisterSiteHandlers connects the http handlers to the site
-func (s *HTTPd) RegisterSiteHandlers(site site.API, valueChan chan<- util.Param) {
+func (s *HTTPd) RegisterSiteHandlers(site site.API, valueChan chan<- util.Param, auth auth.Auth) {
 	router := s.Server.Handler.(*mux.Router)
 
 	// api
@@ -128,6 +131,8 @@ func (s *HTTPd) RegisterSiteHandlers(site site.API, valueChan chan<- util.Param)
 	api.Use(handlers.CORS(
 		handlers.AllowedHeaders([]string{"Content-Type"}),
 	))
+	api.Use(auditHandler(auth, true))
+	api.Use(ensureControlHandler(auth))
 
 	// site api
 	smartCostLimit := func(lp loadpoint.API, limit *float64) {
@@ -153,9 +158,9 @@ func (s *HTTPd) RegisterSiteHandlers(site site.API, valueChan chan<- util.Param)
 		"smartfeedin":             {"POST", "/smartfeedinprioritylimit/{value:-?[0-9.]+}", updateSmartCostLimit(site, smartFeedInPriorityLimit)},
 		"smartfeedindelete":       {"DELETE", "/smartfeedinprioritylimit", updateSmartCostLimit(site, smartFeedInPriorityLimit)},
 		"tariff":                  {"GET", "/tariff/{tariff:[a-z]+}", tariffHandler(site)},
-		"sessions":                {"GET", "/sessions", sessionHandler},
-		"updatesession":           {"PUT", "/session/{id:[0-9]+}", updateSessionHandler},
-		"deletesession":           {"DELETE", "/session/{id:[0-9]+}", deleteSessionHandler},
+		"sessions":                {"GET", "/sessions", deprecatedHandler(apiV2Prefix+"/sessions", sessionHandler)},
+		"updatesession":           {"PUT", "/session/{id:[0-9]+}", deprecatedHandler(apiV2Prefix+"/sessions", updateSessionHandler)},
+		"deletesession":           {"DELETE", "/session/{id:[0-9]+}", deprecatedHandler(apiV2Prefix+"/sessions", deleteSessionHandler)},
 		"telemetry2":              {"POST", "/settings/telemetry/{value:[01truefalse]+}", boolHandler(telemetry.Enable, telemetry.Enabled)},
 	}
 
@@ -163,6 +168,27 @@ func (s *HTTPd) RegisterSiteHandlers(site site.API, valueChan chan<- util.Param)
 		api.Methods(r.Methods()...).Path(r.Pattern).Handler(r.HandlerFunc)
 	}
 
+	// api v2
+	v2 := router.PathPrefix(apiV2Prefix).Subrouter()
+	v2.Use(jsonHandler)
+	v2.Use(handlers.CompressHandler)
+	v2.Use(handlers.CORS(
+		handlers.AllowedHeaders([]string{"Content-Type"}),
+	))
+	v2.Use(auditHandler(auth, true))
+	v2.Use(ensureControlHandler(auth))
+
+	for _, r := range map[string]route{
+		"sessions":       {"GET", "/sessions", sessionsV2Handler},
+		"sessionssum":    {"GET", "/sessions/summary", sessionsSummaryV2Handler},
+		"sessionsreport": {"GET", "/sessions/report", sessionsReportV2Handler},
+		"session":        {"GET", "/sessions/{id:[0-9]+}", sessionV2Handler},
+		"updatesessions": {"PATCH", "/sessions", updateSessionsV2Handler},
+		"deletesessions": {"DELETE", "/sessions", deleteSessionsV2Handler},
+	} {
+		v2.Methods(r.Methods()...).Path(r.Pattern).Handler(r.HandlerFunc)
+	}
+
 	// vehicle api
 	vehicles := map[string]route{
 		"minsoc":         {"POST", "/vehicles/{name:[a-zA-Z0-9_.:-]+}/minsoc/{value:[0-9]+}", minSocHandler(site)},
@@ -182,6 +208,15 @@ func (s *HTTPd) RegisterSiteHandlers(site site.API, valueChan chan<- util.Param)
 		api.Methods(r.Methods()...).Path(r.Pattern).Handler(r.HandlerFunc)
 	}
 
+	// charger api
+	chargers := map[string]route{
+		"unlock": {"POST", "/chargers/{name:[a-zA-Z0-9_.:-]+}/unlock", unlockConnectorHandler},
+	}
+
+	for _, r := range chargers {
+		api.Methods(r.Methods()...).Path(r.Pattern).Handler(r.HandlerFunc)
+	}
+
 	// loadpoint api
 	// TODO any loadpoint
 	for id, lp := range site.Loadpoints() {
@@ -221,7 +256,7 @@ func (s *HTTPd) RegisterSiteHandlers(site site.API, valueChan chan<- util.Param)
 }
 
 // RegisterSystemHandler provides system level handlers
-func (s *HTTPd) RegisterSystemHandler(site *core.Site, valueChan chan<- util.Param, cache *util.ParamCache, auth auth.Auth, shutdown func(), configFile string) {
+func (s *HTTPd) RegisterSystemHandler(site *core.Site, valueChan chan<- util.Param, cache *util.ParamCache, auth auth.Auth, shutdown func(), reload func() (bool, error), configFile string) {
 	router := s.Server.Handler.(*mux.Router)
 
 	// api
@@ -231,6 +266,7 @@ func (s *HTTPd) RegisterSystemHandler(site *core.Site, valueChan chan<- util.Par
 	api.Use(handlers.CORS(
 		handlers.AllowedHeaders([]string{"Content-Type"}),
 	))
+	api.Use(auditHandler(auth, false))
 
 	if site == nil {
 		// If site is nil, create a new empty site. Settings will be loaded during this process and
@@ -262,11 +298,38 @@ func (s *HTTPd) RegisterSystemHandler(site *core.Site, valueChan chan<- util.Par
 			"auth":     {"GET", "/status", authStatusHandler(auth)},
 			"login":    {"POST", "/login", loginHandler(auth)},
 			"logout":   {"POST", "/logout", logoutHandler},
+			"user":     {"GET", "/user", userHandler(auth)},
+			"sessions": {"DELETE", "/sessions", revokeOwnSessionsHandler(auth)},
 		}
 
 		for _, r := range routes {
 			api.Methods(r.Methods()...).Path(r.Pattern).Handler(r.HandlerFunc)
 		}
+
+		// api/auth/users
+		users := api.PathPrefix("/users").Subrouter()
+		users.Use(ensureAuthHandler(auth))
+
+		for _, r := range map[string]route{
+			"users":          {"GET", "", usersHandler(auth)},
+			"updateuser":     {"POST", "", updateUserHandler(auth)},
+			"deleteuser":     {"DELETE", "/{name:[a-zA-Z0-9_.@-]+}", deleteUserHandler(auth)},
+			"revokesessions": {"DELETE", "/{name:[a-zA-Z0-9_.@-]+}/sessions", revokeSessionsHandler(auth)},
+		} {
+			users.Methods(r.Methods()...).Path(r.Pattern).Handler(r.HandlerFunc)
+		}
+
+		// api/auth/tokens
+		tokens := api.PathPrefix("/tokens").Subrouter()
+		tokens.Use(ensureAuthHandler(auth))
+
+		for _, r := range map[string]route{
+			"apitokens":      {"GET", "", apiTokensHandler(auth)},
+			"createapitoken": {"POST", "", createApiTokenHandler(auth)},
+			"revokeapitoken": {"DELETE", "/{id:[0-9a-f]+}", revokeApiTokenHandler(auth)},
+		} {
+			tokens.Methods(r.Methods()...).Path(r.Pattern).Handler(r.HandlerFunc)
+		}
 	}
 
 	{ // api/config
@@ -351,12 +414,43 @@ func (s *HTTPd) RegisterSystemHandler(site *core.Site, valueChan chan<- util.Par
 
 		// system api
 		routes := map[string]route{
-			"log":        {"GET", "/log", logHandler},
-			"logareas":   {"GET", "/log/areas", logAreasHandler},
-			"clearcache": {"DELETE", "/cache", clearCacheHandler},
-			"backup":     {"POST", "/backup", getBackup(auth)},
-			"restore":    {"POST", "/restore", restoreDatabase(auth, shutdown)},
-			"reset":      {"POST", "/reset", resetDatabase(auth, shutdown)},
+			"log":          {"GET", "/log", logHandler},
+			"audit":        {"GET", "/audit", auditLogHandler},
+			"logareas":     {"GET", "/log/areas", logAreasHandler},
+			"loglevels":    {"GET", "/log/levels", logLevelsHandler},
+			"loglevel":     {"POST", "/log/levels/{area}/{level:[a-z]+}", logLevelHandler},
+			"resetlevel":   {"DELETE", "/log/levels/{area}", logLevelHandler},
+			"clearcache":   {"DELETE", "/cache", clearCacheHandler},
+			"diagnostics":  {"GET", "/diagnostics", diagnosticsHandler(site)},
+			"ocppsecurity": {"GET", "/ocpp/security", ocppSecurityHandler},
+			"ocppwarnings": {"DELETE", "/ocpp/security/warnings", ocppAcknowledgeHandler(valueChan)},
+			"ocppconfig":   {"GET", "/ocpp/{id}/config", ocppConfigHandler},
+			"ocppchange":   {"PUT", "/ocpp/{id}/config/{key}", ocppChangeConfigHandler},
+			"ocppfirmware": {"GET", "/ocpp/firmware", ocppFirmwareHandler},
+			"ocppupdate":   {"POST", "/ocpp/{id}/firmware", ocppUpdateFirmwareHandler},
+			"ocppdiag":     {"GET", "/ocpp/diagnostics", ocppDiagnosticsHandler},
+			"ocppdiagfile": {"GET", "/ocpp/diagnostics/{id}/{file}", ocppDiagnosticsFileHandler},
+			"ocppgetdiag":  {"POST", "/ocpp/{id}/diagnostics", ocppGetDiagnosticsHandler},
+			"ocppreserve":  {"POST", "/ocpp/{id}/reservation", ocppReserveHandler},
+			"ocppcancel":   {"DELETE", "/ocpp/{id}/reservation/{reservation:[0-9]+}", ocppCancelReservationHandler},
+			"ocppschedule": {"GET", "/ocpp/{id}/schedule", ocppScheduleHandler},
+			"ocppavail":    {"PUT", "/ocpp/{id}/availability", ocppAvailabilityHandler},
+			"ocppresets":   {"GET", "/ocpp/reset", ocppResetsHandler},
+			"ocppreset":    {"POST", "/ocpp/{id}/reset", ocppResetHandler},
+			"ocpppending":  {"GET", "/ocpp/pending", ocppPendingHandler},
+			"ocppcaps":     {"GET", "/ocpp/capabilities", ocppCapabilitiesHandler},
+			"ocpptrace":    {"GET", "/ocpp/{id}/trace", ocppTraceHandler},
+			"ocppmessages": {"GET", "/ocpp/{id}/messages", ocppMessagesHandler},
+			"ocpp":         {"GET", "/ocpp", ocppChargePointsHandler},
+			"ocppconns":    {"GET", "/ocpp/{id}/connectors", ocppConnectorsHandler},
+			"ocpptrigger":  {"POST", "/ocpp/{id}/trigger", ocppTriggerHandler},
+			"ocppunlock":   {"POST", "/ocpp/{id}/unlock", ocppUnlockHandler},
+			"backup":       {"POST", "/backup", getBackup(auth)},
+			"restore":      {"POST", "/restore", restoreDatabase(auth, shutdown)},
+			"archive":      {"POST", "/backup/archive", getBackupArchive(auth, configFile)},
+			"unarchive":    {"POST", "/restore/archive", restoreBackupArchive(auth, configFile, shutdown)},
+			"reset":        {"POST", "/reset", resetDatabase(auth, shutdown)},
+			"reload":       {"POST", "/reload", reloadHandler(reload)},
 			"shutdown": {"POST", "/shutdown", func(w http.ResponseWriter, r *http.Request) {
 				shutdown()
 				w.WriteHeader(http.StatusNoContent)
@@ -366,5 +460,8 @@ func (s *HTTPd) RegisterSystemHandler(site *core.Site, valueChan chan<- util.Par
 		for _, r := range routes {
 			api.Methods(r.Methods()...).Path(r.Pattern).Handler(r.HandlerFunc)
 		}
+
+		// runtime profiles
+		api.PathPrefix("/debug/pprof/").Handler(http.StripPrefix("/api/system", pprofHandler()))
 	}
 }
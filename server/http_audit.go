@@ -0,0 +1,111 @@
+package server
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/evcc-io/evcc/server/db/audit"
+	"github.com/evcc-io/evcc/util/auth"
+	"github.com/gorilla/mux"
+)
+
+// maxAuditBody is the maximum request body size recorded in the audit log
+const maxAuditBody = 1024
+
+type statusWriter struct {
+	http.ResponseWriter
+	status int
+}
+
+func (w *statusWriter) WriteHeader(status int) {
+	if w.status == 0 {
+		w.status = status
+	}
+	w.ResponseWriter.WriteHeader(status)
+}
+
+func (w *statusWriter) Write(b []byte) (int, error) {
+	if w.status == 0 {
+		w.status = http.StatusOK
+	}
+	return w.ResponseWriter.Write(b)
+}
+
+// auditHandler records state changing requests in the audit log.
+// Request bodies are only recorded if withBody is set since they may contain secrets.
+func auditHandler(authObject auth.Auth, withBody bool) mux.MiddlewareFunc {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if r.Method == http.MethodGet || r.Method == http.MethodOptions || r.Method == http.MethodHead {
+				next.ServeHTTP(w, r)
+				return
+			}
+
+			var value string
+			if withBody && r.Body != nil {
+				b, _ := io.ReadAll(io.LimitReader(r.Body, maxAuditBody))
+				r.Body = io.NopCloser(io.MultiReader(bytes.NewReader(b), r.Body))
+				value = string(b)
+			}
+
+			sw := &statusWriter{ResponseWriter: w}
+			next.ServeHTTP(sw, r)
+
+			user, _, _ := authObject.ValidateUserJwtToken(jwtFromRequest(r))
+
+			var err error
+			if sw.status >= http.StatusBadRequest {
+				err = fmt.Errorf("%d %s", sw.status, http.StatusText(sw.status))
+			}
+
+			audit.Log(audit.SourceApi, user, r.Method+" "+r.URL.Path, value, err)
+		})
+	}
+}
+
+// auditLogHandler returns the audit log
+//
+//	/api/system/audit?from=2025-01-01T00:00:00Z&to=2025-02-01T00:00:00Z&source=mqtt&limit=100
+func auditLogHandler(w http.ResponseWriter, r *http.Request) {
+	q := r.URL.Query()
+
+	var from, to time.Time
+	var limit int
+
+	for _, p := range []struct {
+		key string
+		val *time.Time
+	}{
+		{"from", &from},
+		{"to", &to},
+	} {
+		if s := q.Get(p.key); s != "" {
+			t, err := time.Parse(time.RFC3339, s)
+			if err != nil {
+				jsonError(w, http.StatusBadRequest, fmt.Errorf("invalid %s: %w", p.key, err))
+				return
+			}
+			*p.val = t
+		}
+	}
+
+	if s := q.Get("limit"); s != "" {
+		var err error
+		if limit, err = strconv.Atoi(s); err != nil || limit < 0 {
+			jsonError(w, http.StatusBadRequest, fmt.Errorf("invalid limit: %s", s))
+			return
+		}
+	}
+
+	res, err := audit.Entries(from, to, q.Get("source"), limit)
+	if err != nil {
+		jsonError(w, http.StatusInternalServerError, err)
+		return
+	}
+
+	jsonWrite(w, res)
+}
@@ -0,0 +1,55 @@
+package server
+
+import (
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/evcc-io/evcc/server/db"
+	"github.com/evcc-io/evcc/server/db/audit"
+	"github.com/evcc-io/evcc/util/auth"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestAuditHandler(t *testing.T) {
+	require.NoError(t, db.NewInstance("sqlite", ":memory:"))
+	require.NoError(t, audit.Init())
+
+	h := auditHandler(auth.New(), true)(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		b, _ := io.ReadAll(r.Body)
+		if string(b) == "invalid" {
+			w.WriteHeader(http.StatusBadRequest)
+		}
+	}))
+
+	for _, req := range []*http.Request{
+		httptest.NewRequest(http.MethodGet, "/api/state", nil),
+		httptest.NewRequest(http.MethodPost, "/api/loadpoints/1/mode/pv", nil),
+		httptest.NewRequest(http.MethodPost, "/api/vehicles/foo/plan/repeating", strings.NewReader("invalid")),
+	} {
+		h.ServeHTTP(httptest.NewRecorder(), req)
+	}
+
+	w := httptest.NewRecorder()
+	auditLogHandler(w, httptest.NewRequest(http.MethodGet, "/api/system/audit?source=api&limit=10", nil))
+	require.Equal(t, http.StatusOK, w.Code)
+
+	var res []audit.Entry
+	require.NoError(t, json.NewDecoder(w.Body).Decode(&res))
+	require.Len(t, res, 2)
+
+	// newest first
+	assert.Equal(t, "POST /api/vehicles/foo/plan/repeating", res[0].Action)
+	assert.Equal(t, "invalid", res[0].Value)
+	assert.Equal(t, "400 Bad Request", res[0].Error)
+	assert.Equal(t, "POST /api/loadpoints/1/mode/pv", res[1].Action)
+	assert.Empty(t, res[1].Error)
+
+	w = httptest.NewRecorder()
+	auditLogHandler(w, httptest.NewRequest(http.MethodGet, "/api/system/audit?from=yesterday", nil))
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+}
@@ -3,6 +3,7 @@ package server
 import (
 	"encoding/json"
 	"net/http"
+	"path"
 	"strings"
 	"time"
 
@@ -18,9 +19,31 @@ type updatePasswordRequest struct {
 }
 
 type loginRequest struct {
+	Username string `json:"username,omitempty"` // empty for admin
 	Password string `json:"password"`
 }
 
+type userRequest struct {
+	Name     string    `json:"name"`
+	Password string    `json:"password,omitempty"`
+	Role     auth.Role `json:"role"`
+}
+
+type userResponse struct {
+	Name string    `json:"name"`
+	Role auth.Role `json:"role"`
+}
+
+type apiTokenRequest struct {
+	Name  string     `json:"name"`
+	Scope auth.Scope `json:"scope"`
+}
+
+type apiTokenResponse struct {
+	auth.ApiToken
+	Token string `json:"token,omitempty"` // only returned on creation
+}
+
 func updatePasswordHandler(authObject auth.Auth) http.HandlerFunc {
 	return func(w http.ResponseWriter, r *http.Request) {
 		if authObject.GetAuthMode() == auth.Locked {
@@ -116,13 +139,13 @@ func loginHandler(authObject auth.Auth) http.HandlerFunc {
 			return
 		}
 
-		if !authObject.IsAdminPasswordValid(req.Password) {
+		if _, ok := authObject.IsUserPasswordValid(req.Username, req.Password); !ok {
 			http.Error(w, "Invalid password", http.StatusUnauthorized)
 			return
 		}
 
 		lifetime := time.Hour * 24 * 90 // 90 day valid
-		tokenString, err := authObject.GenerateJwtToken(lifetime)
+		tokenString, err := authObject.GenerateUserJwtToken(req.Username, lifetime)
 		if err != nil {
 			http.Error(w, "Failed to generate JWT token.", http.StatusInternalServerError)
 			return
@@ -147,7 +170,103 @@ func logoutHandler(w http.ResponseWriter, r *http.Request) {
 	})
 }
 
+// userHandler returns the logged-in user and role
+func userHandler(authObject auth.Auth) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		name, role, err := authObject.ValidateUserJwtToken(jwtFromRequest(r))
+		if err != nil {
+			jsonError(w, http.StatusUnauthorized, err)
+			return
+		}
+
+		jsonWrite(w, userResponse{Name: name, Role: role})
+	}
+}
+
+// revokeOwnSessionsHandler logs out the current user from all devices
+func revokeOwnSessionsHandler(authObject auth.Auth) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		name, _, err := authObject.ValidateUserJwtToken(jwtFromRequest(r))
+		if err != nil {
+			jsonError(w, http.StatusUnauthorized, err)
+			return
+		}
+
+		if err := authObject.RevokeSessions(name); err != nil {
+			jsonError(w, http.StatusInternalServerError, err)
+			return
+		}
+
+		logoutHandler(w, r)
+		w.WriteHeader(http.StatusNoContent)
+	}
+}
+
+// usersHandler returns all user accounts
+func usersHandler(authObject auth.Auth) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		res := []userResponse{}
+		for _, u := range authObject.Users() {
+			res = append(res, userResponse{Name: u.Name, Role: u.Role})
+		}
+
+		jsonWrite(w, res)
+	}
+}
+
+// updateUserHandler creates or updates a user account
+func updateUserHandler(authObject auth.Auth) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		if authObject.GetAuthMode() == auth.Locked {
+			http.Error(w, "Forbidden in demo mode", http.StatusForbidden)
+			return
+		}
+
+		var req userRequest
+		if err := jsonDecoder(r.Body).Decode(&req); err != nil {
+			jsonError(w, http.StatusBadRequest, err)
+			return
+		}
+
+		if err := authObject.SetUser(req.Name, req.Password, req.Role); err != nil {
+			jsonError(w, http.StatusBadRequest, err)
+			return
+		}
+
+		jsonWrite(w, userResponse{Name: req.Name, Role: req.Role})
+	}
+}
+
+// deleteUserHandler removes a user account
+func deleteUserHandler(authObject auth.Auth) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		if err := authObject.RemoveUser(mux.Vars(r)["name"]); err != nil {
+			jsonError(w, http.StatusBadRequest, err)
+			return
+		}
+
+		w.WriteHeader(http.StatusNoContent)
+	}
+}
+
+// revokeSessionsHandler logs out the given user from all devices
+func revokeSessionsHandler(authObject auth.Auth) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		if err := authObject.RevokeSessions(mux.Vars(r)["name"]); err != nil {
+			jsonError(w, http.StatusBadRequest, err)
+			return
+		}
+
+		w.WriteHeader(http.StatusNoContent)
+	}
+}
+
 func ensureAuthHandler(authObject auth.Auth) mux.MiddlewareFunc {
+	return ensureRoleHandler(authObject, auth.RoleAdmin)
+}
+
+// ensureRoleHandler requires a valid jwt token with at least the given role
+func ensureRoleHandler(authObject auth.Auth, role auth.Role) mux.MiddlewareFunc {
 	return func(next http.Handler) http.Handler {
 		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
 			if authObject.GetAuthMode() == auth.Disabled {
@@ -161,14 +280,127 @@ func ensureAuthHandler(authObject auth.Auth) mux.MiddlewareFunc {
 			}
 
 			// check jwt token
-			ok, err := authObject.ValidateJwtToken(jwtFromRequest(r))
-			if !ok || err != nil {
+			_, userRole, err := authObject.ValidateUserJwtToken(jwtFromRequest(r))
+			if err != nil {
 				http.Error(w, "Unauthorized", http.StatusUnauthorized)
 				return
 			}
 
+			if !userRole.Includes(role) {
+				http.Error(w, "Forbidden", http.StatusForbidden)
+				return
+			}
+
 			// all clear, continue
 			next.ServeHTTP(w, r)
 		})
 	}
 }
+
+// apiTokensHandler returns all api tokens
+func apiTokensHandler(authObject auth.Auth) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		res := []apiTokenResponse{}
+		for _, t := range authObject.ApiTokens() {
+			res = append(res, apiTokenResponse{ApiToken: t})
+		}
+
+		jsonWrite(w, res)
+	}
+}
+
+// createApiTokenHandler creates an api token
+func createApiTokenHandler(authObject auth.Auth) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		if authObject.GetAuthMode() == auth.Locked {
+			http.Error(w, "Forbidden in demo mode", http.StatusForbidden)
+			return
+		}
+
+		var req apiTokenRequest
+		if err := jsonDecoder(r.Body).Decode(&req); err != nil {
+			jsonError(w, http.StatusBadRequest, err)
+			return
+		}
+
+		token, res, err := authObject.CreateApiToken(req.Name, req.Scope)
+		if err != nil {
+			jsonError(w, http.StatusBadRequest, err)
+			return
+		}
+
+		jsonWrite(w, apiTokenResponse{ApiToken: res, Token: token})
+	}
+}
+
+// revokeApiTokenHandler revokes an api token
+func revokeApiTokenHandler(authObject auth.Auth) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		if err := authObject.RevokeApiToken(mux.Vars(r)["id"]); err != nil {
+			jsonError(w, http.StatusBadRequest, err)
+			return
+		}
+
+		w.WriteHeader(http.StatusNoContent)
+	}
+}
+
+// ensureControlHandler restricts state changing requests to operators once user accounts have been created.
+// Without user accounts, control remains open for backwards compatibility.
+func ensureControlHandler(authObject auth.Auth) mux.MiddlewareFunc {
+	ensureRole := ensureRoleHandler(authObject, auth.RoleOperator)
+
+	return func(next http.Handler) http.Handler {
+		protected := ensureRole(next)
+
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if r.Method == http.MethodGet || r.Method == http.MethodOptions || !authObject.HasUsers() {
+				next.ServeHTTP(w, r)
+				return
+			}
+
+			protected.ServeHTTP(w, r)
+		})
+	}
+}
+
+// remotePublicRequest checks if the relayed request is required for logging in,
+// i.e. loading the user interface and the login endpoints
+func remotePublicRequest(r *http.Request) bool {
+	p := path.Clean(r.URL.Path)
+
+	switch {
+	case r.Method == http.MethodPost && p == "/api/auth/login",
+		r.Method == http.MethodGet && p == "/api/auth/status":
+		return true
+	case r.Method != http.MethodGet:
+		return false
+	case p == "/" || p == "/custom.css":
+		return true
+	}
+
+	for _, dir := range []string{"/assets/", "/meta/", "/i18n/"} {
+		if strings.HasPrefix(p, dir) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// RemoteAuthHandler requires a valid token for every request relayed from remote except for logging in.
+// Unlike local requests, state is not readable and control is not open without user accounts.
+func RemoteAuthHandler(authObject auth.Auth) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if !remotePublicRequest(r) {
+				if _, _, err := authObject.ValidateUserJwtToken(jwtFromRequest(r)); err != nil {
+					http.Error(w, "Unauthorized", http.StatusUnauthorized)
+					return
+				}
+			}
+
+			next.ServeHTTP(w, r)
+		})
+	}
+}
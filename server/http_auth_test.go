@@ -0,0 +1,53 @@
+package server
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/evcc-io/evcc/server/db"
+	"github.com/evcc-io/evcc/server/db/settings"
+	"github.com/evcc-io/evcc/util/auth"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRemoteAuthHandler(t *testing.T) {
+	require.NoError(t, db.NewInstance("sqlite", ":memory:"))
+	require.NoError(t, settings.Init())
+
+	authObject := auth.New()
+	require.NoError(t, authObject.SetAdminPassword("secret"))
+
+	token, err := authObject.GenerateJwtToken(time.Hour)
+	require.NoError(t, err)
+
+	h := RemoteAuthHandler(authObject)(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}))
+
+	for _, tc := range []struct {
+		method, path, token string
+		status              int
+	}{
+		{http.MethodGet, "/", "", http.StatusOK},
+		{http.MethodGet, "/assets/index.js", "", http.StatusOK},
+		{http.MethodPost, "/api/auth/login", "", http.StatusOK},
+		{http.MethodGet, "/api/auth/status", "", http.StatusOK},
+		{http.MethodGet, "/api/state", "", http.StatusUnauthorized},
+		{http.MethodGet, "/events", "", http.StatusUnauthorized},
+		{http.MethodGet, "/assets/../api/state", "", http.StatusUnauthorized},
+		{http.MethodPost, "/api/loadpoints/1/mode/pv", "invalid", http.StatusUnauthorized},
+		{http.MethodGet, "/api/state", token, http.StatusOK},
+		{http.MethodPost, "/api/loadpoints/1/mode/pv", token, http.StatusOK},
+	} {
+		req := httptest.NewRequest(tc.method, "/", nil)
+		req.URL.Path = tc.path
+		if tc.token != "" {
+			req.Header.Set("Authorization", "Bearer "+tc.token)
+		}
+
+		w := httptest.NewRecorder()
+		h.ServeHTTP(w, req)
+		assert.Equal(t, tc.status, w.Code, tc.path)
+	}
+}
@@ -0,0 +1,32 @@
+package server
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/evcc-io/evcc/api"
+	"github.com/evcc-io/evcc/util/config"
+	"github.com/gorilla/mux"
+)
+
+// unlockConnectorHandler unlocks the charger's connector to release a stuck cable
+func unlockConnectorHandler(w http.ResponseWriter, r *http.Request) {
+	dev, err := config.Chargers().ByName(mux.Vars(r)["name"])
+	if err != nil {
+		jsonError(w, http.StatusNotFound, err)
+		return
+	}
+
+	c, ok := dev.Instance().(api.ConnectorUnlocker)
+	if !ok {
+		jsonError(w, http.StatusBadRequest, errors.New("charger does not support unlocking"))
+		return
+	}
+
+	if err := c.UnlockConnector(); err != nil {
+		jsonError(w, http.StatusBadRequest, err)
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
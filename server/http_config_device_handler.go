@@ -317,7 +317,10 @@ func newDeviceHandler(w http.ResponseWriter, r *http.Request) {
 	// prevent context from being cancelled
 	close(done)
 
-	setConfigDirty()
+	// circuit hierarchy is only built on startup
+	if class == templates.Circuit {
+		setConfigDirty()
+	}
 
 	res := struct {
 		ID   int    `json:"id"`
@@ -344,7 +347,12 @@ func updateDevice[T any](ctx context.Context, id int, class templates.Class, req
 		return errors.New("not configurable")
 	}
 
-	return configurable.Update(merged, instance, config.WithProperties(req.Properties))
+	if err := configurable.Update(merged, instance, config.WithProperties(req.Properties)); err != nil {
+		return err
+	}
+
+	// notify running site of the replaced instance
+	return h.Update(config.NameForID(id))
 }
 
 // updateDeviceHandler updates database device's configuration by class
@@ -389,7 +397,9 @@ func updateDeviceHandler(w http.ResponseWriter, r *http.Request) {
 		}, config.Circuits(), force)
 	}
 
-	setConfigDirty()
+	if class == templates.Circuit {
+		setConfigDirty()
+	}
 
 	if err != nil {
 		cancel()
@@ -533,9 +543,9 @@ func deleteDeviceHandler(site site.API) func(w http.ResponseWriter, r *http.Requ
 					lp.SetCircuitRef("")
 				}
 			}
-		}
 
-		setConfigDirty()
+			setConfigDirty()
+		}
 
 		if err != nil {
 			jsonError(w, http.StatusBadRequest, err)
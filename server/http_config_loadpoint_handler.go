@@ -2,6 +2,7 @@ package server
 
 import (
 	"errors"
+	"fmt"
 	"io"
 	"net/http"
 	"strconv"
@@ -190,6 +191,62 @@ func newLoadpointHandler() http.HandlerFunc {
 	}
 }
 
+// validateLoadpointStaticConfig decodes the static config and validates its device references
+func validateLoadpointStaticConfig(static map[string]any) (loadpoint.StaticConfig, error) {
+	var cc struct {
+		loadpoint.StaticConfig `mapstructure:",squash"`
+		Other                  map[string]any `mapstructure:",remain"`
+	}
+
+	if err := util.DecodeOther(static, &cc); err != nil {
+		return cc.StaticConfig, err
+	}
+
+	if cc.Charger == "" {
+		return cc.StaticConfig, errors.New("missing charger")
+	}
+	if _, err := config.Chargers().ByName(cc.Charger); err != nil {
+		return cc.StaticConfig, fmt.Errorf("charger: %w", err)
+	}
+	if cc.Meter != "" {
+		if _, err := config.Meters().ByName(cc.Meter); err != nil {
+			return cc.StaticConfig, fmt.Errorf("meter: %w", err)
+		}
+	}
+	if cc.Vehicle != "" {
+		if _, err := config.Vehicles().ByName(cc.Vehicle); err != nil {
+			return cc.StaticConfig, fmt.Errorf("default vehicle: %w", err)
+		}
+	}
+	if cc.Circuit != "" {
+		if _, err := config.Circuits().ByName(cc.Circuit); err != nil {
+			return cc.StaticConfig, fmt.Errorf("circuit: %w", err)
+		}
+	}
+
+	return cc.StaticConfig, nil
+}
+
+// applyLoadpointStaticConfig applies the validated device references to the running loadpoint
+func applyLoadpointStaticConfig(lp loadpoint.API, cc loadpoint.StaticConfig) bool {
+	if cc.Charger != lp.GetChargerRef() {
+		lp.SetChargerRef(cc.Charger)
+	}
+	if cc.Meter != lp.GetMeterRef() {
+		lp.SetMeterRef(cc.Meter)
+	}
+	if cc.Vehicle != lp.GetDefaultVehicleRef() {
+		lp.SetDefaultVehicleRef(cc.Vehicle)
+	}
+
+	circuitChanged := cc.Circuit != lp.GetCircuitRef()
+	if circuitChanged {
+		lp.SetCircuitRef(cc.Circuit)
+	}
+
+	return circuitChanged
+}
+
 // updateLoadpointHandler returns a device configurations by class
 func updateLoadpointHandler() http.HandlerFunc {
 	h := config.Loadpoints()
@@ -232,6 +289,12 @@ func updateLoadpointHandler() http.HandlerFunc {
 
 		instance := dev.Instance()
 
+		cc, err := validateLoadpointStaticConfig(other)
+		if err != nil {
+			jsonError(w, http.StatusBadRequest, err)
+			return
+		}
+
 		if err := configurable.Update(other, instance); err != nil {
 			jsonError(w, http.StatusBadRequest, err)
 			return
@@ -243,7 +306,19 @@ func updateLoadpointHandler() http.HandlerFunc {
 			return
 		}
 
-		setConfigDirty()
+		// device references are persisted as settings once the config is stored
+		circuitChanged := applyLoadpointStaticConfig(instance, cc)
+
+		// circuit hierarchy is only built on startup
+		if circuitChanged {
+			setConfigDirty()
+		}
+
+		// notify running site of changed device references
+		if err := h.Update(dev.Config().Name); err != nil {
+			jsonError(w, http.StatusBadRequest, err)
+			return
+		}
 
 		w.WriteHeader(http.StatusOK)
 	}
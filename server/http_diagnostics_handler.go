@@ -0,0 +1,94 @@
+package server
+
+import (
+	"context"
+	"net/http"
+	"net/http/pprof"
+	"runtime"
+	"time"
+
+	"github.com/evcc-io/evcc/core"
+	"github.com/evcc-io/evcc/server/db"
+)
+
+var started = time.Now()
+
+type memoryDiagnostics struct {
+	HeapAlloc   uint64 `json:"heapAlloc"`
+	HeapObjects uint64 `json:"heapObjects"`
+	Sys         uint64 `json:"sys"`
+	NumGC       uint32 `json:"numGC"`
+	PauseTotal  string `json:"pauseTotal"`
+}
+
+type databaseDiagnostics struct {
+	Latency string `json:"latency,omitempty"`
+	Error   string `json:"error,omitempty"`
+}
+
+// databaseLatency measures the database round trip
+func databaseLatency(ctx context.Context) databaseDiagnostics {
+	if db.Instance == nil {
+		return databaseDiagnostics{Error: "database offline"}
+	}
+
+	sqlDB, err := db.Instance.DB()
+	if err != nil {
+		return databaseDiagnostics{Error: err.Error()}
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, 5*time.Second)
+	defer cancel()
+
+	start := time.Now()
+	if err := sqlDB.PingContext(ctx); err != nil {
+		return databaseDiagnostics{Error: err.Error()}
+	}
+
+	return databaseDiagnostics{Latency: time.Since(start).Round(time.Microsecond).String()}
+}
+
+// diagnosticsHandler returns runtime, control loop and database diagnostics
+func diagnosticsHandler(site *core.Site) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		var ms runtime.MemStats
+		runtime.ReadMemStats(&ms)
+
+		res := struct {
+			Uptime     string                `json:"uptime"`
+			Goroutines int                   `json:"goroutines"`
+			Memory     memoryDiagnostics     `json:"memory"`
+			Site       *core.SiteDiagnostics `json:"site,omitempty"`
+			Database   databaseDiagnostics   `json:"database"`
+		}{
+			Uptime:     time.Since(started).Round(time.Second).String(),
+			Goroutines: runtime.NumGoroutine(),
+			Memory: memoryDiagnostics{
+				HeapAlloc:   ms.HeapAlloc,
+				HeapObjects: ms.HeapObjects,
+				Sys:         ms.Sys,
+				NumGC:       ms.NumGC,
+				PauseTotal:  time.Duration(ms.PauseTotalNs).String(),
+			},
+			Database: databaseLatency(r.Context()),
+		}
+
+		if site != nil {
+			d := site.Diagnostics()
+			res.Site = &d
+		}
+
+		jsonWrite(w, res)
+	}
+}
+
+// pprofHandler serves the runtime profiles at /debug/pprof/
+func pprofHandler() http.Handler {
+	mux := http.NewServeMux()
+	mux.HandleFunc("/debug/pprof/", pprof.Index)
+	mux.HandleFunc("/debug/pprof/cmdline", pprof.Cmdline)
+	mux.HandleFunc("/debug/pprof/profile", pprof.Profile)
+	mux.HandleFunc("/debug/pprof/symbol", pprof.Symbol)
+	mux.HandleFunc("/debug/pprof/trace", pprof.Trace)
+	return mux
+}
@@ -0,0 +1,539 @@
+package server
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/coder/websocket"
+	"github.com/evcc-io/evcc/charger/ocpp"
+	"github.com/evcc-io/evcc/core/keys"
+	"github.com/evcc-io/evcc/util"
+	"github.com/evcc-io/evcc/util/config"
+	"github.com/gorilla/mux"
+	"github.com/lorenzodonini/ocpp-go/ocpp1.6/core"
+)
+
+// ocppSecurityHandler returns the recent security events per charge point and the unacknowledged warnings
+func ocppSecurityHandler(w http.ResponseWriter, r *http.Request) {
+	res := struct {
+		Events   map[string][]ocpp.SecurityEvent `json:"events"`
+		Warnings []ocpp.SecurityEvent            `json:"warnings"`
+	}{
+		Events:   ocpp.SecurityEvents(),
+		Warnings: ocpp.SecurityWarnings(),
+	}
+
+	jsonWrite(w, res)
+}
+
+// ocppAcknowledgeHandler acknowledges the critical security event warnings
+func ocppAcknowledgeHandler(valueChan chan<- util.Param) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		ocpp.AcknowledgeSecurityWarnings()
+		valueChan <- util.Param{Key: keys.OcppSecurityWarnings, Val: ocpp.SecurityWarnings()}
+
+		w.WriteHeader(http.StatusNoContent)
+	}
+}
+
+// ocppConfigHandler returns the charge point's configuration, optionally limited to the requested keys
+func ocppConfigHandler(w http.ResponseWriter, r *http.Request) {
+	cp, err := ocpp.ChargepointByID(mux.Vars(r)["id"])
+	if err != nil {
+		jsonError(w, http.StatusNotFound, err)
+		return
+	}
+
+	res, err := cp.GetConfigurationRequest(r.URL.Query()["key"]...)
+	if err != nil {
+		jsonError(w, http.StatusBadRequest, err)
+		return
+	}
+
+	jsonWrite(w, res)
+}
+
+// ocppChangeConfigHandler changes a configuration key of the charge point
+func ocppChangeConfigHandler(w http.ResponseWriter, r *http.Request) {
+	vars := mux.Vars(r)
+
+	cp, err := ocpp.ChargepointByID(vars["id"])
+	if err != nil {
+		jsonError(w, http.StatusNotFound, err)
+		return
+	}
+
+	var req struct {
+		Value string `json:"value"`
+	}
+
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		jsonError(w, http.StatusBadRequest, err)
+		return
+	}
+
+	if err := cp.ChangeConfigurationRequest(vars["key"], req.Value); err != nil {
+		jsonError(w, http.StatusBadRequest, err)
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// ocppFirmwareHandler returns the firmware update status per charge point
+func ocppFirmwareHandler(w http.ResponseWriter, r *http.Request) {
+	jsonWrite(w, ocpp.FirmwareStatuses())
+}
+
+// ocppUpdateFirmwareHandler starts the charge point's firmware update from a url or a locally hosted file
+func ocppUpdateFirmwareHandler(w http.ResponseWriter, r *http.Request) {
+	id := mux.Vars(r)["id"]
+
+	cp, err := ocpp.ChargepointByID(id)
+	if err != nil {
+		jsonError(w, http.StatusNotFound, err)
+		return
+	}
+
+	var req struct {
+		Location      string    `json:"location"`
+		File          string    `json:"file"`
+		RetrieveDate  time.Time `json:"retrieveDate"`
+		Retries       int       `json:"retries"`
+		RetryInterval int       `json:"retryInterval"` // seconds
+	}
+
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		jsonError(w, http.StatusBadRequest, err)
+		return
+	}
+
+	if (req.Location == "") == (req.File == "") {
+		jsonError(w, http.StatusBadRequest, errors.New("either location or file required"))
+		return
+	}
+
+	if req.File != "" {
+		if req.Location, err = ocpp.Instance().FirmwareURL(id, req.File); err != nil {
+			jsonError(w, http.StatusBadRequest, err)
+			return
+		}
+	}
+
+	if req.RetrieveDate.IsZero() {
+		req.RetrieveDate = time.Now()
+	}
+
+	if err := cp.UpdateFirmwareRequest(req.Location, req.RetrieveDate, req.Retries, time.Duration(req.RetryInterval)*time.Second); err != nil {
+		jsonError(w, http.StatusBadRequest, err)
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// ocppDiagnosticsHandler returns the diagnostics upload status per charge point and the uploaded files
+func ocppDiagnosticsHandler(w http.ResponseWriter, r *http.Request) {
+	files, err := ocpp.DiagnosticsFiles()
+	if err != nil {
+		jsonError(w, http.StatusInternalServerError, err)
+		return
+	}
+
+	res := struct {
+		Status map[string]ocpp.DiagnosticsStatus `json:"status"`
+		Files  []ocpp.DiagnosticsFile            `json:"files"`
+	}{
+		Status: ocpp.DiagnosticsStatuses(),
+		Files:  files,
+	}
+
+	jsonWrite(w, res)
+}
+
+// ocppDiagnosticsFileHandler downloads an uploaded diagnostics file
+func ocppDiagnosticsFileHandler(w http.ResponseWriter, r *http.Request) {
+	vars := mux.Vars(r)
+
+	file, err := ocpp.DiagnosticsFileName(vars["id"], vars["file"])
+	if err != nil {
+		jsonError(w, http.StatusNotFound, err)
+		return
+	}
+
+	w.Header().Set("Content-Disposition", fmt.Sprintf("attachment; filename=%q", vars["file"]))
+	http.ServeFile(w, r, file)
+}
+
+// ocppGetDiagnosticsHandler requests the charge point to upload its diagnostics, by default to the local upload endpoint
+func ocppGetDiagnosticsHandler(w http.ResponseWriter, r *http.Request) {
+	id := mux.Vars(r)["id"]
+
+	cp, err := ocpp.ChargepointByID(id)
+	if err != nil {
+		jsonError(w, http.StatusNotFound, err)
+		return
+	}
+
+	var req struct {
+		Location      string    `json:"location"`
+		Start         time.Time `json:"start"`
+		Stop          time.Time `json:"stop"`
+		Retries       int       `json:"retries"`
+		RetryInterval int       `json:"retryInterval"` // seconds
+	}
+
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil && !errors.Is(err, io.EOF) {
+		jsonError(w, http.StatusBadRequest, err)
+		return
+	}
+
+	if req.Location == "" {
+		if req.Location, err = ocpp.Instance().DiagnosticsURL(id); err != nil {
+			jsonError(w, http.StatusBadRequest, err)
+			return
+		}
+	}
+
+	file, err := cp.GetDiagnosticsRequest(req.Location, req.Start, req.Stop, req.Retries, time.Duration(req.RetryInterval)*time.Second)
+	if err != nil {
+		jsonError(w, http.StatusBadRequest, err)
+		return
+	}
+
+	jsonWrite(w, struct {
+		File string `json:"file"`
+	}{
+		File: file,
+	})
+}
+
+// ocppReserveHandler reserves a charge point connector for an id tag or a vehicle's identifier until expiry
+func ocppReserveHandler(w http.ResponseWriter, r *http.Request) {
+	cp, err := ocpp.ChargepointByID(mux.Vars(r)["id"])
+	if err != nil {
+		jsonError(w, http.StatusNotFound, err)
+		return
+	}
+
+	var req struct {
+		Connector int       `json:"connector"`
+		IdTag     string    `json:"idTag"`
+		Vehicle   string    `json:"vehicle"`
+		Expiry    time.Time `json:"expiry"`
+	}
+
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		jsonError(w, http.StatusBadRequest, err)
+		return
+	}
+
+	if req.Vehicle != "" {
+		dev, err := config.Vehicles().ByName(req.Vehicle)
+		if err != nil {
+			jsonError(w, http.StatusBadRequest, err)
+			return
+		}
+
+		for _, id := range dev.Instance().Identifiers() {
+			if !strings.Contains(id, "*") {
+				req.IdTag = id
+				break
+			}
+		}
+	}
+
+	if req.IdTag == "" {
+		jsonError(w, http.StatusBadRequest, errors.New("missing id tag"))
+		return
+	}
+
+	if !req.Expiry.After(time.Now()) {
+		jsonError(w, http.StatusBadRequest, errors.New("expiry must be in the future"))
+		return
+	}
+
+	reservationId := ocpp.NewReservationID()
+	if err := cp.ReserveNowRequest(req.Connector, req.Expiry, req.IdTag, reservationId); err != nil {
+		jsonError(w, http.StatusBadRequest, err)
+		return
+	}
+
+	jsonWrite(w, struct {
+		ReservationId int `json:"reservationId"`
+	}{
+		ReservationId: reservationId,
+	})
+}
+
+// ocppCancelReservationHandler cancels a charge point reservation
+func ocppCancelReservationHandler(w http.ResponseWriter, r *http.Request) {
+	vars := mux.Vars(r)
+
+	cp, err := ocpp.ChargepointByID(vars["id"])
+	if err != nil {
+		jsonError(w, http.StatusNotFound, err)
+		return
+	}
+
+	reservationId, err := strconv.Atoi(vars["reservation"])
+	if err != nil {
+		jsonError(w, http.StatusBadRequest, err)
+		return
+	}
+
+	if err := cp.CancelReservationRequest(reservationId); err != nil {
+		jsonError(w, http.StatusBadRequest, err)
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// ocppScheduleHandler returns the composite schedule the charge point applies to the connector, connector 0 being the charge point as a whole
+func ocppScheduleHandler(w http.ResponseWriter, r *http.Request) {
+	cp, err := ocpp.ChargepointByID(mux.Vars(r)["id"])
+	if err != nil {
+		jsonError(w, http.StatusNotFound, err)
+		return
+	}
+
+	connector, duration := 0, time.Hour
+
+	if s := r.URL.Query().Get("connector"); s != "" {
+		if connector, err = strconv.Atoi(s); err != nil {
+			jsonError(w, http.StatusBadRequest, err)
+			return
+		}
+	}
+
+	if s := r.URL.Query().Get("duration"); s != "" {
+		if duration, err = time.ParseDuration(s); err != nil {
+			jsonError(w, http.StatusBadRequest, err)
+			return
+		}
+	}
+
+	res, err := cp.GetCompositeScheduleRequest(connector, int(duration.Seconds()))
+	if err != nil {
+		jsonError(w, http.StatusBadRequest, err)
+		return
+	}
+
+	jsonWrite(w, res)
+}
+
+// ocppAvailabilityHandler takes a charge point connector out of service or back into operation, connector 0 being the charge point as a whole
+func ocppAvailabilityHandler(w http.ResponseWriter, r *http.Request) {
+	cp, err := ocpp.ChargepointByID(mux.Vars(r)["id"])
+	if err != nil {
+		jsonError(w, http.StatusNotFound, err)
+		return
+	}
+
+	var req struct {
+		Connector int  `json:"connector"`
+		Operative bool `json:"operative"`
+	}
+
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		jsonError(w, http.StatusBadRequest, err)
+		return
+	}
+
+	availability := core.AvailabilityTypeInoperative
+	if req.Operative {
+		availability = core.AvailabilityTypeOperative
+	}
+
+	if err := cp.ChangeAvailabilityRequest(req.Connector, availability); err != nil {
+		jsonError(w, http.StatusBadRequest, err)
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// ocppChargePointsHandler returns the configured charge points with their connectivity and connector status
+func ocppChargePointsHandler(w http.ResponseWriter, r *http.Request) {
+	jsonWrite(w, ocpp.ChargePoints())
+}
+
+// ocppConnectorsHandler returns the charge point's connector status
+func ocppConnectorsHandler(w http.ResponseWriter, r *http.Request) {
+	cp, err := ocpp.ChargepointByID(mux.Vars(r)["id"])
+	if err != nil {
+		jsonError(w, http.StatusNotFound, err)
+		return
+	}
+
+	jsonWrite(w, cp.Connectors())
+}
+
+// ocppTriggerHandler requests the charge point to send the given message
+func ocppTriggerHandler(w http.ResponseWriter, r *http.Request) {
+	cp, err := ocpp.ChargepointByID(mux.Vars(r)["id"])
+	if err != nil {
+		jsonError(w, http.StatusNotFound, err)
+		return
+	}
+
+	var req struct {
+		Message   string `json:"message"`
+		Connector int    `json:"connector"`
+	}
+
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		jsonError(w, http.StatusBadRequest, err)
+		return
+	}
+
+	message, err := ocpp.ParseMessageTrigger(req.Message)
+	if err != nil {
+		jsonError(w, http.StatusBadRequest, err)
+		return
+	}
+
+	if err := cp.TriggerMessageRequest(req.Connector, message); err != nil {
+		jsonError(w, http.StatusBadRequest, err)
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// ocppUnlockHandler unlocks the charge point's connector
+func ocppUnlockHandler(w http.ResponseWriter, r *http.Request) {
+	cp, err := ocpp.ChargepointByID(mux.Vars(r)["id"])
+	if err != nil {
+		jsonError(w, http.StatusNotFound, err)
+		return
+	}
+
+	var req struct {
+		Connector int `json:"connector"`
+	}
+
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		jsonError(w, http.StatusBadRequest, err)
+		return
+	}
+
+	if req.Connector < 1 {
+		jsonError(w, http.StatusBadRequest, errors.New("invalid connector"))
+		return
+	}
+
+	if err := cp.UnlockConnectorRequest(req.Connector); err != nil {
+		jsonError(w, http.StatusBadRequest, err)
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// ocppPendingHandler returns the unconfigured charge points that have connected
+func ocppPendingHandler(w http.ResponseWriter, r *http.Request) {
+	jsonWrite(w, ocpp.PendingChargePoints())
+}
+
+// ocppCapabilitiesHandler returns the capability profile per charge point
+func ocppCapabilitiesHandler(w http.ResponseWriter, r *http.Request) {
+	jsonWrite(w, ocpp.ChargePointCapabilities())
+}
+
+// ocppTraceHandler returns the charge point's recent messages
+func ocppTraceHandler(w http.ResponseWriter, r *http.Request) {
+	jsonWrite(w, ocpp.Traces(mux.Vars(r)["id"]))
+}
+
+// ocppMessagesHandler streams the charge point's recent and live messages via websocket
+func ocppMessagesHandler(w http.ResponseWriter, r *http.Request) {
+	id := mux.Vars(r)["id"]
+
+	conn, err := websocket.Accept(w, r, socketAcceptOptions(r))
+	if err != nil {
+		log.ERROR.Println(err)
+		return
+	}
+	defer conn.Close(websocket.StatusInternalError, "")
+
+	// subscribe before sending the recent messages to not miss any
+	ch, cancel := ocpp.SubscribeTraces(id)
+	defer cancel()
+
+	ctx := conn.CloseRead(r.Context())
+
+	var last time.Time
+	write := func(msg ocpp.TraceMessage) error {
+		b, err := json.Marshal(msg)
+		if err != nil {
+			return err
+		}
+		last = msg.Time
+		return writeTimeout(ctx, socketWriteTimeout, conn, b)
+	}
+
+	for _, msg := range ocpp.Traces(id) {
+		if err := write(msg); err != nil {
+			return
+		}
+	}
+
+	for {
+		select {
+		case msg := <-ch:
+			// already sent as recent message
+			if !msg.Time.After(last) {
+				continue
+			}
+			if err := write(msg); err != nil {
+				return
+			}
+		case <-ctx.Done():
+			return
+		}
+	}
+}
+
+// ocppResetsHandler returns the latest reset status per charge point
+func ocppResetsHandler(w http.ResponseWriter, r *http.Request) {
+	jsonWrite(w, ocpp.ResetStatuses())
+}
+
+// ocppResetHandler soft or hard resets the charge point
+func ocppResetHandler(w http.ResponseWriter, r *http.Request) {
+	cp, err := ocpp.ChargepointByID(mux.Vars(r)["id"])
+	if err != nil {
+		jsonError(w, http.StatusNotFound, err)
+		return
+	}
+
+	var req struct {
+		Type string `json:"type"`
+	}
+
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil && !errors.Is(err, io.EOF) {
+		jsonError(w, http.StatusBadRequest, err)
+		return
+	}
+
+	resetType, err := ocpp.ParseResetType(req.Type)
+	if err != nil {
+		jsonError(w, http.StatusBadRequest, err)
+		return
+	}
+
+	if err := cp.Reset(resetType); err != nil {
+		jsonError(w, http.StatusBadRequest, err)
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
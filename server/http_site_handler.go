@@ -17,8 +17,8 @@ import (
 	"github.com/evcc-io/evcc/core/loadpoint"
 	"github.com/evcc-io/evcc/core/site"
 	"github.com/evcc-io/evcc/server/assets"
+	"github.com/evcc-io/evcc/server/backup"
 	"github.com/evcc-io/evcc/server/db"
-	"github.com/evcc-io/evcc/server/db/settings"
 	"github.com/evcc-io/evcc/util"
 	"github.com/evcc-io/evcc/util/auth"
 	"github.com/evcc-io/evcc/util/encode"
@@ -295,11 +295,55 @@ func logAreasHandler(w http.ResponseWriter, r *http.Request) {
 	jsonWrite(w, logstash.Areas())
 }
 
+func logLevelsHandler(w http.ResponseWriter, r *http.Request) {
+	jsonWrite(w, util.LogLevels())
+}
+
+// logLevelHandler changes an area's log level at runtime, deleting resets to the default level
+func logLevelHandler(w http.ResponseWriter, r *http.Request) {
+	vars := mux.Vars(r)
+
+	var level string
+	if r.Method != http.MethodDelete {
+		level = vars["level"]
+	}
+
+	if err := util.SetLogLevel(vars["area"], level); err != nil {
+		jsonError(w, http.StatusBadRequest, err)
+		return
+	}
+
+	jsonWrite(w, util.LogLevels()[vars["area"]])
+}
+
 func clearCacheHandler(w http.ResponseWriter, r *http.Request) {
 	util.ResetCached()
 	jsonWrite(w, "OK")
 }
 
+// reloadHandler applies configuration file changes at runtime
+func reloadHandler(reload func() (bool, error)) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		restartRequired, err := reload()
+		if err != nil {
+			jsonError(w, http.StatusBadRequest, err)
+			return
+		}
+
+		if restartRequired {
+			setConfigDirty()
+		}
+
+		res := struct {
+			RestartRequired bool `json:"restartRequired"`
+		}{
+			RestartRequired: restartRequired,
+		}
+
+		jsonWrite(w, res)
+	}
+}
+
 func logHandler(w http.ResponseWriter, r *http.Request) {
 	a := r.URL.Query()["area"]
 	l := logstash.LogLevelToThreshold(r.URL.Query().Get("level"))
@@ -346,7 +390,7 @@ func getBackup(authObject auth.Auth) http.HandlerFunc {
 			return
 		}
 
-		if err := settings.Persist(); err != nil {
+		if err := db.Flush(); err != nil {
 			http.Error(w, "Synching DB failed", http.StatusInternalServerError)
 			return
 		}
@@ -377,6 +421,95 @@ func getBackup(authObject auth.Auth) http.HandlerFunc {
 	}
 }
 
+// getBackupArchive returns an archive of database and config file
+func getBackupArchive(authObject auth.Auth, configFile string) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		var req loginRequest
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+
+		if !adminPasswordValid(authObject, req.Password) {
+			http.Error(w, "Invalid password", http.StatusUnauthorized)
+			return
+		}
+
+		if db.FilePath == "" {
+			http.Error(w, "Backup requires sqlite database", http.StatusBadRequest)
+			return
+		}
+
+		if err := db.Flush(); err != nil {
+			http.Error(w, "Synching DB failed", http.StatusInternalServerError)
+			return
+		}
+
+		snapshot := db.FilePath + ".snapshot"
+		defer os.Remove(snapshot)
+
+		if err := backup.Snapshot(db.Instance, snapshot); err != nil {
+			http.Error(w, "Could not snapshot DB: "+err.Error(), http.StatusInternalServerError)
+			return
+		}
+
+		filename := "evcc-backup-" + time.Now().Format("2006-01-02--15-04") + ".tar.gz"
+
+		w.Header().Set("Content-Type", "application/gzip")
+		w.Header().Set("Content-Disposition", `attachment; filename="`+filename+`"`)
+
+		if err := backup.Create(w, snapshot, configFile); err != nil {
+			http.Error(w, "Error creating archive: "+err.Error(), http.StatusInternalServerError)
+		}
+	}
+}
+
+// restoreBackupArchive restores database and config file from an archive and restarts evcc
+func restoreBackupArchive(authObject auth.Auth, configFile string, shutdown func()) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		if err := r.ParseMultipartForm(32 << 20); err != nil {
+			http.Error(w, "Failed to parse form: "+err.Error(), http.StatusBadRequest)
+			return
+		}
+
+		if !adminPasswordValid(authObject, r.FormValue("password")) {
+			http.Error(w, "Invalid password", http.StatusUnauthorized)
+			return
+		}
+
+		if db.FilePath == "" {
+			http.Error(w, "Restore requires sqlite database", http.StatusBadRequest)
+			return
+		}
+
+		file, _, err := r.FormFile("file")
+		if err != nil {
+			http.Error(w, "Failed to get uploaded file: "+err.Error(), http.StatusBadRequest)
+			return
+		}
+		defer file.Close()
+
+		db.Flush()
+
+		// close db connection to avoid corruption
+		if err := db.Close(); err != nil {
+			jsonError(w, http.StatusInternalServerError, err)
+			return
+		}
+
+		// restart in any case since the database has been closed
+		defer shutdown()
+
+		m, err := backup.Restore(file, db.FilePath, configFile)
+		if err != nil {
+			jsonError(w, http.StatusBadRequest, err)
+			return
+		}
+
+		jsonWrite(w, m)
+	}
+}
+
 // createLocalDatabaseBackup creates a local backup in case of catastrophic error in reset or restore
 func createLocalDatabaseBackup() error {
 	backupPath := db.FilePath + ".bak"
@@ -423,7 +556,7 @@ func restoreDatabase(authObject auth.Auth, shutdown func()) http.HandlerFunc {
 		}
 		defer file.Close()
 
-		settings.Persist()
+		db.Flush()
 
 		// close db connection to avoid corruption
 		if err := db.Close(); err != nil {
@@ -473,7 +606,7 @@ func resetDatabase(authObject auth.Auth, shutdown func()) http.HandlerFunc {
 			return
 		}
 
-		settings.Persist()
+		db.Flush()
 
 		if err := createLocalDatabaseBackup(); err != nil {
 			jsonError(w, http.StatusInternalServerError, err)
@@ -0,0 +1,49 @@
+package server
+
+import (
+	"bytes"
+	"mime/multipart"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/evcc-io/evcc/server/db"
+	"github.com/evcc-io/evcc/server/db/settings"
+	"github.com/evcc-io/evcc/util/auth"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestBackupArchiveRequiresSqlite(t *testing.T) {
+	require.NoError(t, db.NewInstance("sqlite", ":memory:"))
+	require.NoError(t, settings.Init())
+
+	authObject := auth.New()
+	require.NoError(t, authObject.SetAdminPassword("secret"))
+
+	// databases without file, e.g. postgres or mysql
+	defer func(path string) { db.FilePath = path }(db.FilePath)
+	db.FilePath = ""
+
+	req := httptest.NewRequest(http.MethodPost, "/api/system/backup/archive", strings.NewReader(`{"password":"secret"}`))
+	w := httptest.NewRecorder()
+	getBackupArchive(authObject, "")(w, req)
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+
+	var body bytes.Buffer
+	mw := multipart.NewWriter(&body)
+	require.NoError(t, mw.WriteField("password", "secret"))
+	require.NoError(t, mw.Close())
+
+	req = httptest.NewRequest(http.MethodPost, "/api/system/restore/archive", &body)
+	req.Header.Set("Content-Type", mw.FormDataContentType())
+	w = httptest.NewRecorder()
+	restoreBackupArchive(authObject, "", func() {
+		require.Fail(t, "shutdown")
+	})(w, req)
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+
+	// database is still usable
+	require.NoError(t, db.Instance.Exec("SELECT 1").Error)
+}
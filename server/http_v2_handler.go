@@ -0,0 +1,374 @@
+package server
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"slices"
+	"strconv"
+	"time"
+
+	"github.com/evcc-io/evcc/core/session"
+	"github.com/evcc-io/evcc/server/db"
+	"github.com/evcc-io/evcc/util/locale"
+	"github.com/gorilla/mux"
+	"gorm.io/gorm"
+)
+
+const (
+	apiV2Prefix = "/api/v2"
+
+	defaultPageLimit = 100
+	maxPageLimit     = 1000
+)
+
+// v2Result is the envelope for all API v2 responses
+type v2Result struct {
+	Result any `json:"result"`
+}
+
+// v2Page is a paginated API v2 result
+type v2Page[T any] struct {
+	Items  T     `json:"items"`
+	Total  int64 `json:"total"`
+	Limit  int   `json:"limit"`
+	Offset int   `json:"offset"`
+}
+
+// v2SessionSummary is an aggregated group of charging sessions
+type v2SessionSummary struct {
+	Key             string  `json:"key" gorm:"column:group_key"` // key is reserved in mysql
+	Sessions        int64   `json:"sessions"`
+	ChargedEnergy   float64 `json:"chargedEnergy"`
+	ChargeDuration  float64 `json:"chargeDuration"`
+	Price           float64 `json:"price"`
+	SolarPercentage float64 `json:"solarPercentage"`
+}
+
+func v2Write(w http.ResponseWriter, data any) {
+	jsonWrite(w, v2Result{Result: data})
+}
+
+// deprecatedHandler marks legacy routes with RFC 8594 deprecation headers pointing to the successor
+func deprecatedHandler(successor string, h http.HandlerFunc) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Deprecation", "true")
+		w.Header().Set("Link", fmt.Sprintf("<%s>; rel=\"successor-version\"", successor))
+		h(w, r)
+	}
+}
+
+// pagination parses limit and offset query parameters
+func pagination(q url.Values) (int, int, error) {
+	limit, offset := defaultPageLimit, 0
+
+	if s := q.Get("limit"); s != "" {
+		v, err := strconv.Atoi(s)
+		if err != nil || v < 1 || v > maxPageLimit {
+			return 0, 0, fmt.Errorf("invalid limit: %s", s)
+		}
+		limit = v
+	}
+
+	if s := q.Get("offset"); s != "" {
+		v, err := strconv.Atoi(s)
+		if err != nil || v < 0 {
+			return 0, 0, fmt.Errorf("invalid offset: %s", s)
+		}
+		offset = v
+	}
+
+	return limit, offset, nil
+}
+
+// parseQueryTime parses RFC3339 or date-only query parameters
+func parseQueryTime(s string) (time.Time, error) {
+	if t, err := time.Parse(time.RFC3339, s); err == nil {
+		return t, nil
+	}
+	return time.ParseInLocation(time.DateOnly, s, time.Local)
+}
+
+// sessionFilter applies the common session query filters
+func sessionFilter(q url.Values) (func(*gorm.DB) *gorm.DB, error) {
+	var (
+		from, to time.Time
+		err      error
+	)
+
+	if s := q.Get("from"); s != "" {
+		if from, err = parseQueryTime(s); err != nil {
+			return nil, fmt.Errorf("invalid from: %s", s)
+		}
+	}
+
+	if s := q.Get("to"); s != "" {
+		if to, err = parseQueryTime(s); err != nil {
+			return nil, fmt.Errorf("invalid to: %s", s)
+		}
+	}
+
+	return func(tx *gorm.DB) *gorm.DB {
+		tx = tx.Where("charged_kwh >= ?", 0.05)
+
+		if !from.IsZero() {
+			tx = tx.Where("created >= ?", from)
+		}
+		if !to.IsZero() {
+			tx = tx.Where("created < ?", to)
+		}
+
+		for _, field := range []string{"loadpoint", "vehicle", "identifier"} {
+			if vals := q[field]; len(vals) > 0 {
+				tx = tx.Where(field+" IN ?", vals)
+			}
+		}
+
+		return tx
+	}, nil
+}
+
+// sessionsV2Handler returns a filtered and paginated list of charging sessions
+func sessionsV2Handler(w http.ResponseWriter, r *http.Request) {
+	if db.Instance == nil {
+		jsonError(w, http.StatusBadRequest, errors.New("database offline"))
+		return
+	}
+
+	q := r.URL.Query()
+
+	limit, offset, err := pagination(q)
+	if err != nil {
+		jsonError(w, http.StatusBadRequest, err)
+		return
+	}
+
+	filter, err := sessionFilter(q)
+	if err != nil {
+		jsonError(w, http.StatusBadRequest, err)
+		return
+	}
+
+	order := "created DESC"
+	switch sort := q.Get("sort"); sort {
+	case "", "-created":
+	case "created":
+		order = "created ASC"
+	default:
+		jsonError(w, http.StatusBadRequest, fmt.Errorf("invalid sort: %s", sort))
+		return
+	}
+
+	res := v2Page[session.Sessions]{
+		Items:  session.Sessions{},
+		Limit:  limit,
+		Offset: offset,
+	}
+
+	if txn := db.Instance.Model(new(session.Session)).Scopes(filter).Count(&res.Total); txn.Error != nil {
+		jsonError(w, http.StatusInternalServerError, txn.Error)
+		return
+	}
+
+	if txn := db.Instance.Scopes(filter).Order(order).Limit(limit).Offset(offset).Find(&res.Items); txn.Error != nil {
+		jsonError(w, http.StatusInternalServerError, txn.Error)
+		return
+	}
+
+	v2Write(w, res)
+}
+
+// sessionsSummaryV2Handler returns charging session aggregates grouped by vehicle, loadpoint or identifier
+func sessionsSummaryV2Handler(w http.ResponseWriter, r *http.Request) {
+	if db.Instance == nil {
+		jsonError(w, http.StatusBadRequest, errors.New("database offline"))
+		return
+	}
+
+	q := r.URL.Query()
+
+	group := q.Get("groupBy")
+	if group == "" {
+		group = "vehicle"
+	}
+
+	if !slices.Contains([]string{"vehicle", "loadpoint", "identifier"}, group) {
+		jsonError(w, http.StatusBadRequest, fmt.Errorf("invalid groupBy: %s", group))
+		return
+	}
+
+	filter, err := sessionFilter(q)
+	if err != nil {
+		jsonError(w, http.StatusBadRequest, err)
+		return
+	}
+
+	res := []v2SessionSummary{}
+
+	if txn := db.Instance.Model(new(session.Session)).Scopes(filter).
+		Select(group + " AS group_key, COUNT(*) AS sessions, SUM(charged_kwh) AS charged_energy, " +
+			"COALESCE(SUM(charge_duration), 0) / 1e9 AS charge_duration, COALESCE(SUM(price), 0) AS price, " +
+			"COALESCE(SUM(charged_kwh * solar_percentage) / NULLIF(SUM(CASE WHEN solar_percentage IS NULL THEN 0 ELSE charged_kwh END), 0), 0) AS solar_percentage").
+		Group(group).Order(group).Scan(&res); txn.Error != nil {
+		jsonError(w, http.StatusInternalServerError, txn.Error)
+		return
+	}
+
+	v2Write(w, res)
+}
+
+// sessionsReportV2Handler returns a csv or pdf report of the filtered charging sessions
+func sessionsReportV2Handler(w http.ResponseWriter, r *http.Request) {
+	if db.Instance == nil {
+		jsonError(w, http.StatusBadRequest, errors.New("database offline"))
+		return
+	}
+
+	q := r.URL.Query()
+
+	format := q.Get("format")
+	if format == "" {
+		format = "csv"
+	}
+
+	if format != "csv" && format != "pdf" {
+		jsonError(w, http.StatusBadRequest, fmt.Errorf("invalid format: %s", format))
+		return
+	}
+
+	filter, err := sessionFilter(q)
+	if err != nil {
+		jsonError(w, http.StatusBadRequest, err)
+		return
+	}
+
+	report := session.Report{
+		Vehicles:    q["vehicle"],
+		Identifiers: q["identifier"],
+	}
+
+	// already validated by filter
+	report.From, _ = parseQueryTime(q.Get("from"))
+	report.To, _ = parseQueryTime(q.Get("to"))
+
+	if txn := db.Instance.Scopes(filter).Order("created ASC").Find(&report.Sessions); txn.Error != nil {
+		jsonError(w, http.StatusInternalServerError, txn.Error)
+		return
+	}
+
+	ctx := context.WithValue(context.Background(), locale.Locale, requestLanguage(r))
+
+	filename := "report"
+	if !report.From.IsZero() {
+		filename += "-" + report.From.Format(time.DateOnly)
+	}
+
+	if format == "csv" {
+		csvResult(ctx, w, &report, filename)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/pdf")
+	w.Header().Set("Content-Disposition", `attachment; filename="`+filename+`.pdf"`)
+
+	if err := report.WritePdf(ctx, w); err != nil {
+		jsonError(w, http.StatusInternalServerError, err)
+	}
+}
+
+// deleteSessionsV2Handler removes multiple sessions by id
+func deleteSessionsV2Handler(w http.ResponseWriter, r *http.Request) {
+	if db.Instance == nil {
+		jsonError(w, http.StatusBadRequest, errors.New("database offline"))
+		return
+	}
+
+	var req struct {
+		IDs []uint `json:"ids"`
+	}
+
+	if err := jsonDecoder(r.Body).Decode(&req); err != nil {
+		jsonError(w, http.StatusBadRequest, err)
+		return
+	}
+
+	if len(req.IDs) == 0 {
+		jsonError(w, http.StatusBadRequest, errors.New("missing ids"))
+		return
+	}
+
+	txn := db.Instance.Delete(new(session.Session), req.IDs)
+	if txn.Error != nil {
+		jsonError(w, http.StatusBadRequest, txn.Error)
+		return
+	}
+
+	v2Write(w, txn.RowsAffected)
+}
+
+// updateSessionsV2Handler assigns vehicle and/or loadpoint to multiple sessions
+func updateSessionsV2Handler(w http.ResponseWriter, r *http.Request) {
+	if db.Instance == nil {
+		jsonError(w, http.StatusBadRequest, errors.New("database offline"))
+		return
+	}
+
+	var req struct {
+		IDs       []uint  `json:"ids"`
+		Vehicle   *string `json:"vehicle"`
+		Loadpoint *string `json:"loadpoint"`
+	}
+
+	if err := jsonDecoder(r.Body).Decode(&req); err != nil {
+		jsonError(w, http.StatusBadRequest, err)
+		return
+	}
+
+	if len(req.IDs) == 0 {
+		jsonError(w, http.StatusBadRequest, errors.New("missing ids"))
+		return
+	}
+
+	data := make(map[string]any)
+	if req.Vehicle != nil {
+		data["vehicle"] = *req.Vehicle
+	}
+	if req.Loadpoint != nil {
+		data["loadpoint"] = *req.Loadpoint
+	}
+
+	if len(data) == 0 {
+		jsonError(w, http.StatusBadRequest, errors.New("nothing to update"))
+		return
+	}
+
+	txn := db.Instance.Model(new(session.Session)).Where("id IN ?", req.IDs).Updates(data)
+	if txn.Error != nil {
+		jsonError(w, http.StatusBadRequest, txn.Error)
+		return
+	}
+
+	v2Write(w, txn.RowsAffected)
+}
+
+// sessionV2Handler returns a single session
+func sessionV2Handler(w http.ResponseWriter, r *http.Request) {
+	if db.Instance == nil {
+		jsonError(w, http.StatusBadRequest, errors.New("database offline"))
+		return
+	}
+
+	var res session.Session
+	if txn := db.Instance.First(&res, mux.Vars(r)["id"]); txn.Error != nil {
+		status := http.StatusInternalServerError
+		if errors.Is(txn.Error, gorm.ErrRecordNotFound) {
+			status = http.StatusNotFound
+		}
+		jsonError(w, status, txn.Error)
+		return
+	}
+
+	v2Write(w, res)
+}
@@ -0,0 +1,88 @@
+package server
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/evcc-io/evcc/core/session"
+	"github.com/evcc-io/evcc/server/db"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSessionsV2(t *testing.T) {
+	require.NoError(t, db.NewInstance("sqlite", ":memory:"))
+	require.NoError(t, session.Init())
+
+	now := time.Now().Truncate(time.Second)
+	for i, v := range []string{"a", "b", "a", "a"} {
+		require.NoError(t, db.Instance.Create(&session.Session{
+			Created:       now.Add(time.Duration(i) * time.Hour),
+			Vehicle:       v,
+			ChargedEnergy: float64(i + 1),
+		}).Error)
+	}
+
+	get := func(query string) (int, v2Page[session.Sessions]) {
+		w := httptest.NewRecorder()
+		sessionsV2Handler(w, httptest.NewRequest(http.MethodGet, "/api/v2/sessions?"+query, nil))
+
+		var res struct {
+			Result v2Page[session.Sessions]
+		}
+		if w.Code == http.StatusOK {
+			require.NoError(t, json.NewDecoder(w.Body).Decode(&res))
+		}
+		return w.Code, res.Result
+	}
+
+	code, res := get("vehicle=a&limit=2")
+	assert.Equal(t, http.StatusOK, code)
+	assert.Equal(t, int64(3), res.Total)
+	require.Len(t, res.Items, 2)
+	assert.Equal(t, 4.0, res.Items[0].ChargedEnergy, "newest first")
+
+	code, res = get("vehicle=a&limit=2&offset=2")
+	assert.Equal(t, http.StatusOK, code)
+	require.Len(t, res.Items, 1)
+	assert.Equal(t, 1.0, res.Items[0].ChargedEnergy)
+
+	code, res = get("sort=created&vehicle=b")
+	assert.Equal(t, http.StatusOK, code)
+	require.Len(t, res.Items, 1)
+	assert.Equal(t, "b", res.Items[0].Vehicle)
+
+	code, _ = get("limit=0")
+	assert.Equal(t, http.StatusBadRequest, code)
+
+	code, _ = get("from=yesterday")
+	assert.Equal(t, http.StatusBadRequest, code)
+}
+
+func TestSessionsSummaryV2(t *testing.T) {
+	require.NoError(t, db.NewInstance("sqlite", ":memory:"))
+	require.NoError(t, session.Init())
+
+	for i, v := range []string{"a", "b", "a"} {
+		require.NoError(t, db.Instance.Create(&session.Session{
+			Created:       time.Now(),
+			Vehicle:       v,
+			ChargedEnergy: float64(i + 1),
+		}).Error)
+	}
+
+	w := httptest.NewRecorder()
+	sessionsSummaryV2Handler(w, httptest.NewRequest(http.MethodGet, "/api/v2/sessions/summary?groupBy=vehicle", nil))
+	require.Equal(t, http.StatusOK, w.Code, w.Body.String())
+
+	var res struct {
+		Result []v2SessionSummary
+	}
+	require.NoError(t, json.NewDecoder(w.Body).Decode(&res))
+	require.Len(t, res.Result, 2)
+	assert.Equal(t, v2SessionSummary{Key: "a", Sessions: 2, ChargedEnergy: 4}, res.Result[0])
+	assert.Equal(t, v2SessionSummary{Key: "b", Sessions: 1, ChargedEnergy: 2}, res.Result[1])
+}
@@ -0,0 +1,166 @@
+package kiosk
+
+import (
+	"crypto/subtle"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"html/template"
+	"net"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/evcc-io/evcc/api/globalconfig"
+	"github.com/evcc-io/evcc/util"
+	"github.com/gorilla/mux"
+)
+
+// defaultLimit is the default number of requests per minute and client
+const defaultLimit = 30
+
+// Server provides the public read-only status page for kiosk displays.
+// Only power flows and charger availability are exposed.
+type Server struct {
+	log   *util.Logger
+	token string
+	cache *util.ParamCache
+
+	mu      sync.Mutex
+	limit   int
+	window  time.Time
+	clients map[string]int
+}
+
+// New creates the kiosk server
+func New(conf globalconfig.Kiosk, cache *util.ParamCache) (*Server, error) {
+	if len(conf.Token) < 16 {
+		return nil, errors.New("token must have at least 16 characters")
+	}
+
+	if conf.Limit <= 0 {
+		conf.Limit = defaultLimit
+	}
+
+	return &Server{
+		log:     util.NewLogger("kiosk"),
+		token:   conf.Token,
+		cache:   cache,
+		limit:   conf.Limit,
+		clients: make(map[string]int),
+	}, nil
+}
+
+// Handler returns the http handler serving the /kiosk path
+//
+//	/kiosk/{token}              html status page
+//	/kiosk/{token}?format=json  json status
+func (s *Server) Handler() http.Handler {
+	router := mux.NewRouter().StrictSlash(true)
+	router.Use(s.rateLimit, s.authorize)
+	router.Methods(http.MethodGet).Path("/kiosk/{token}").HandlerFunc(s.statusHandler)
+
+	return router
+}
+
+func (s *Server) authorize(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if subtle.ConstantTimeCompare([]byte(mux.Vars(r)["token"]), []byte(s.token)) != 1 {
+			http.Error(w, http.StatusText(http.StatusNotFound), http.StatusNotFound)
+			return
+		}
+
+		next.ServeHTTP(w, r)
+	})
+}
+
+// rateLimit restricts the number of requests per minute and client address, including invalid tokens
+func (s *Server) rateLimit(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		client, _, err := net.SplitHostPort(r.RemoteAddr)
+		if err != nil {
+			client = r.RemoteAddr
+		}
+
+		if !s.allow(client, time.Now()) {
+			w.Header().Set("Retry-After", "60")
+			http.Error(w, http.StatusText(http.StatusTooManyRequests), http.StatusTooManyRequests)
+			return
+		}
+
+		next.ServeHTTP(w, r)
+	})
+}
+
+func (s *Server) allow(client string, now time.Time) bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if window := now.Truncate(time.Minute); !window.Equal(s.window) {
+		s.window = window
+		clear(s.clients)
+	}
+
+	s.clients[client]++
+
+	return s.clients[client] <= s.limit
+}
+
+func (s *Server) statusHandler(w http.ResponseWriter, r *http.Request) {
+	res := status(s.cache)
+
+	w.Header().Set("Cache-Control", "no-store")
+	w.Header().Set("Access-Control-Allow-Origin", "*")
+
+	if r.URL.Query().Get("format") == "json" {
+		w.Header().Set("Content-Type", "application/json")
+		_ = json.NewEncoder(w).Encode(res)
+		return
+	}
+
+	w.Header().Set("Content-Type", "text/html; charset=utf-8")
+	if err := page.Execute(w, res); err != nil {
+		s.log.ERROR.Println(err)
+	}
+}
+
+var page = template.Must(template.New("kiosk").Funcs(template.FuncMap{
+	"kw": func(w float64) string {
+		return fmt.Sprintf("%.1f kW", w/1e3)
+	},
+}).Parse(`<!DOCTYPE html>
+<html>
+<head>
+<meta charset="utf-8">
+<meta name="viewport" content="width=device-width, initial-scale=1">
+<meta http-equiv="refresh" content="30">
+<title>{{ or .Title "evcc" }}</title>
+<style>
+body { font-family: sans-serif; margin: 2em; background: #fff; color: #28293e; }
+table { border-collapse: collapse; font-size: 1.5em; }
+td { padding: 0.25em 1em 0.25em 0; }
+.available { color: #0fde41; }
+.occupied { color: #93949e; }
+.charging { color: #0ba631; }
+</style>
+</head>
+<body>
+<h1>{{ or .Title "evcc" }}</h1>
+<table>
+<tr><td>PV</td><td>{{ kw .PvPower }}</td></tr>
+<tr><td>Grid</td><td>{{ kw .GridPower }}</td></tr>
+<tr><td>Home</td><td>{{ kw .HomePower }}</td></tr>
+{{- if .BatterySoc }}
+<tr><td>Battery</td><td>{{ kw .BatteryPower }} ({{ .BatterySoc }}%)</td></tr>
+{{- end }}
+</table>
+<h2>Charging points</h2>
+<table>
+{{- range .Loadpoints }}
+<tr><td>{{ .Title }}</td><td class="{{ .Status }}">{{ .Status }}</td><td>{{ if eq .Status "charging" }}{{ kw .ChargePower }}{{ end }}</td></tr>
+{{- end }}
+</table>
+<p><small>{{ .Updated.Format "15:04:05" }}</small></p>
+</body>
+</html>
+`))
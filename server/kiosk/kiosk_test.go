@@ -0,0 +1,87 @@
+package kiosk
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/evcc-io/evcc/api/globalconfig"
+	"github.com/evcc-io/evcc/core/keys"
+	"github.com/evcc-io/evcc/util"
+	"github.com/samber/lo"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const token = "0123456789abcdef"
+
+func TestStatus(t *testing.T) {
+	cache := util.NewParamCache()
+	for _, p := range []util.Param{
+		{Key: keys.SiteTitle, Val: "Home"},
+		{Key: keys.PvPower, Val: 5000.0},
+		{Key: keys.Grid, Val: struct{ Power float64 }{Power: -1000}},
+		{Key: keys.BatterySoc, Val: 80.0},
+		{Key: "vehicleTitle", Loadpoint: lo.ToPtr(0), Val: "secret"},
+		{Key: keys.Title, Loadpoint: lo.ToPtr(0), Val: "Garage"},
+		{Key: keys.Connected, Loadpoint: lo.ToPtr(0), Val: true},
+		{Key: keys.Charging, Loadpoint: lo.ToPtr(0), Val: true},
+		{Key: keys.ChargePower, Loadpoint: lo.ToPtr(0), Val: 11000.0},
+		{Key: keys.Title, Loadpoint: lo.ToPtr(1), Val: "Carport"},
+		{Key: keys.Connected, Loadpoint: lo.ToPtr(1), Val: false},
+	} {
+		cache.Add(p.UniqueID(), p)
+	}
+
+	s, err := New(globalconfig.Kiosk{Token: token}, cache)
+	require.NoError(t, err)
+
+	w := httptest.NewRecorder()
+	s.Handler().ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/kiosk/"+token+"?format=json", nil))
+	require.Equal(t, http.StatusOK, w.Code)
+	assert.NotContains(t, w.Body.String(), "secret")
+
+	var res Status
+	require.NoError(t, json.NewDecoder(w.Body).Decode(&res))
+
+	assert.Equal(t, "Home", res.Title)
+	assert.Equal(t, 5000.0, res.PvPower)
+	assert.Equal(t, -1000.0, res.GridPower)
+	assert.Equal(t, []Loadpoint{
+		{Title: "Garage", Status: Charging, ChargePower: 11000},
+		{Title: "Carport", Status: Available},
+	}, res.Loadpoints)
+
+	w = httptest.NewRecorder()
+	s.Handler().ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/kiosk/"+token, nil))
+	assert.Contains(t, w.Body.String(), "11.0 kW")
+}
+
+func TestAuthorization(t *testing.T) {
+	_, err := New(globalconfig.Kiosk{Token: "short"}, util.NewParamCache())
+	assert.Error(t, err)
+
+	s, err := New(globalconfig.Kiosk{Token: token}, util.NewParamCache())
+	require.NoError(t, err)
+
+	w := httptest.NewRecorder()
+	s.Handler().ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/kiosk/invalid", nil))
+	assert.Equal(t, http.StatusNotFound, w.Code)
+}
+
+func TestRateLimit(t *testing.T) {
+	s, err := New(globalconfig.Kiosk{Token: token, Limit: 2}, util.NewParamCache())
+	require.NoError(t, err)
+
+	now := time.Now().Truncate(time.Minute)
+
+	assert.True(t, s.allow("a", now))
+	assert.True(t, s.allow("a", now))
+	assert.False(t, s.allow("a", now))
+	assert.True(t, s.allow("b", now))
+
+	// next window
+	assert.True(t, s.allow("a", now.Add(time.Minute)))
+}
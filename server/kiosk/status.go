@@ -0,0 +1,114 @@
+package kiosk
+
+import (
+	"encoding/json"
+	"maps"
+	"slices"
+	"time"
+
+	"github.com/evcc-io/evcc/core/keys"
+	"github.com/evcc-io/evcc/util"
+	"github.com/spf13/cast"
+)
+
+// Loadpoint status
+const (
+	Available = "available"
+	Occupied  = "occupied"
+	Charging  = "charging"
+)
+
+// Status is the public subset of the site state
+type Status struct {
+	Title        string      `json:"title,omitempty"`
+	PvPower      float64     `json:"pvPower"`
+	GridPower    float64     `json:"gridPower"`
+	HomePower    float64     `json:"homePower"`
+	BatteryPower float64     `json:"batteryPower"`
+	BatterySoc   float64     `json:"batterySoc"`
+	Loadpoints   []Loadpoint `json:"loadpoints"`
+	Updated      time.Time   `json:"updated"`
+}
+
+// Loadpoint is the public subset of the loadpoint state
+type Loadpoint struct {
+	Title       string  `json:"title"`
+	Status      string  `json:"status"`
+	ChargePower float64 `json:"chargePower"`
+}
+
+// status collects the public values from the cache. Values not explicitly listed are never exposed.
+func status(cache *util.ParamCache) Status {
+	res := Status{
+		Loadpoints: []Loadpoint{},
+		Updated:    time.Now(),
+	}
+
+	type loadpoint struct {
+		Loadpoint
+		connected, charging bool
+	}
+
+	lps := make(map[int]*loadpoint)
+
+	for _, p := range cache.All() {
+		if p.Loadpoint != nil {
+			lp, ok := lps[*p.Loadpoint]
+			if !ok {
+				lp = new(loadpoint)
+				lps[*p.Loadpoint] = lp
+			}
+
+			switch p.Key {
+			case keys.Title:
+				lp.Title = cast.ToString(p.Val)
+			case keys.Connected:
+				lp.connected = cast.ToBool(p.Val)
+			case keys.Charging:
+				lp.charging = cast.ToBool(p.Val)
+			case keys.ChargePower:
+				lp.ChargePower = cast.ToFloat64(p.Val)
+			}
+
+			continue
+		}
+
+		switch p.Key {
+		case keys.SiteTitle:
+			res.Title = cast.ToString(p.Val)
+		case keys.PvPower:
+			res.PvPower = cast.ToFloat64(p.Val)
+		case keys.Grid:
+			// grid measurement is published as struct
+			var grid struct {
+				Power float64 `json:"power"`
+			}
+			if b, err := json.Marshal(p.Val); err == nil {
+				_ = json.Unmarshal(b, &grid)
+			}
+			res.GridPower = grid.Power
+		case keys.HomePower:
+			res.HomePower = cast.ToFloat64(p.Val)
+		case keys.BatteryPower:
+			res.BatteryPower = cast.ToFloat64(p.Val)
+		case keys.BatterySoc:
+			res.BatterySoc = cast.ToFloat64(p.Val)
+		}
+	}
+
+	for _, id := range slices.Sorted(maps.Keys(lps)) {
+		lp := lps[id]
+
+		lp.Status = Available
+		switch {
+		case lp.charging:
+			lp.Status = Charging
+		case lp.connected:
+			lp.Status = Occupied
+		}
+
+		res.Loadpoints = append(res.Loadpoints, lp.Loadpoint)
+	}
+
+	return res
+}
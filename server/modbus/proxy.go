@@ -3,7 +3,7 @@ package modbus
 import (
 	"context"
 	"fmt"
-	"net"
+	"strings"
 
 	"github.com/andig/mbserver"
 	"github.com/evcc-io/evcc/api"
@@ -28,12 +28,12 @@ func StartProxy(port int, config modbus.Settings, readOnly ReadOnlyMode) error {
 		conn:     conn,
 	}
 
-	l, err := net.Listen("tcp", fmt.Sprintf(":%d", port))
+	l, err := util.Listen(port)
 	if err != nil {
 		return err
 	}
 
-	h.log.DEBUG.Printf("modbus proxy for %s listening at :%d", config.String(), port)
+	h.log.DEBUG.Printf("modbus proxy for %s listening at %s", config.String(), strings.Join(util.ListenAddrs(port), ", "))
 
 	srv, err := mbserver.New(h, mbserver.Logger(&logger{log: h.log}))
 	if err != nil {
@@ -0,0 +1,335 @@
+package modbus
+
+import (
+	"encoding/binary"
+	"encoding/json"
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+
+	"github.com/andig/mbserver"
+	"github.com/evcc-io/evcc/api"
+	"github.com/evcc-io/evcc/core/keys"
+	"github.com/evcc-io/evcc/core/loadpoint"
+	"github.com/evcc-io/evcc/core/site"
+	"github.com/evcc-io/evcc/util"
+)
+
+// Register map of the site server. All registers are available as input and holding registers.
+// Values are big-endian float32 (two registers) unless stated otherwise.
+const (
+	RegVersion        = 0  // uint16 register map version
+	RegLoadpoints     = 1  // uint16 number of loadpoints
+	RegGridPower      = 2  // W
+	RegGridEnergy     = 4  // kWh
+	RegPvPower        = 6  // W
+	RegPvEnergy       = 8  // kWh
+	RegHomePower      = 10 // W
+	RegBatteryPower   = 12 // W
+	RegBatterySoc     = 14 // %
+	RegTariffGrid     = 16 // currency/kWh
+	RegTariffFeedIn   = 18 // currency/kWh
+	RegBufferSoc      = 20 // %, writable
+	RegPrioritySoc    = 22 // %, writable
+	RegResidualPower  = 24 // W, writable
+	RegLoadpointBase  = 100
+	RegLoadpointBlock = 50
+
+	// loadpoint registers relative to RegLoadpointBase + RegLoadpointBlock * index
+	RegLpChargePower   = 0  // W
+	RegLpChargedEnergy = 2  // Wh
+	RegLpVehicleSoc    = 4  // %
+	RegLpConnected     = 6  // uint16 bool
+	RegLpCharging      = 7  // uint16 bool
+	RegLpEnabled       = 8  // uint16 bool
+	RegLpPhasesActive  = 9  // uint16
+	RegLpMode          = 10 // uint16 0=off 1=now 2=minpv 3=pv, writable
+	RegLpMinCurrent    = 11 // A, writable
+	RegLpMaxCurrent    = 13 // A, writable
+	RegLpLimitSoc      = 15 // uint16 %, writable
+
+	siteRegisterVersion = 1
+)
+
+var modes = []api.ChargeMode{api.ModeOff, api.ModeNow, api.ModeMinPV, api.ModePV}
+
+type register struct {
+	addr  uint16
+	float bool // float32 or uint16
+	get   func() float64
+	set   func(float64) error // nil for read-only registers
+}
+
+func (r register) size() uint16 {
+	if r.float {
+		return 2
+	}
+	return 1
+}
+
+// siteHandler serves site and loadpoint values
+type siteHandler struct {
+	mbserver.DummyHandler
+	log      *util.Logger
+	site     site.API
+	cache    *util.ParamCache
+	writable bool
+}
+
+// StartSiteServer starts a modbus tcp server exposing site and loadpoint data
+func StartSiteServer(port int, site site.API, cache *util.ParamCache, writable bool) error {
+	h := &siteHandler{
+		log:      util.NewLogger(fmt.Sprintf("mbserver-%d", port)),
+		site:     site,
+		cache:    cache,
+		writable: writable,
+	}
+
+	l, err := util.Listen(port)
+	if err != nil {
+		return err
+	}
+
+	h.log.DEBUG.Printf("modbus server listening at %s", strings.Join(util.ListenAddrs(port), ", "))
+
+	srv, err := mbserver.New(h, mbserver.Logger(&logger{log: h.log}))
+	if err != nil {
+		return err
+	}
+
+	return srv.Start(l)
+}
+
+// value returns a cached value as float. Structured values are accessed using dotted keys.
+func (h *siteHandler) value(lp *int, key string) float64 {
+	key, field, _ := strings.Cut(key, ".")
+
+	id := key
+	if lp != nil {
+		id = strconv.Itoa(*lp) + "." + key
+	}
+
+	val := h.cache.Get(id).Val
+
+	if field != "" {
+		b, err := json.Marshal(val)
+		if err != nil {
+			return 0
+		}
+
+		var res map[string]any
+		if err := json.Unmarshal(b, &res); err != nil {
+			return 0
+		}
+
+		val = res[field]
+	}
+
+	switch v := val.(type) {
+	case float64:
+		return v
+	case *float64:
+		if v != nil {
+			return *v
+		}
+	case int:
+		return float64(v)
+	case int64:
+		return float64(v)
+	case bool:
+		if v {
+			return 1
+		}
+	}
+
+	return 0
+}
+
+func (h *siteHandler) cached(lp *int, key string) func() float64 {
+	return func() float64 {
+		return h.value(lp, key)
+	}
+}
+
+func (h *siteHandler) registers() []register {
+	lps := h.site.Loadpoints()
+
+	res := []register{
+		{addr: RegVersion, get: func() float64 { return siteRegisterVersion }},
+		{addr: RegLoadpoints, get: func() float64 { return float64(len(lps)) }},
+		{addr: RegGridPower, float: true, get: h.cached(nil, keys.Grid+".power")},
+		{addr: RegGridEnergy, float: true, get: h.cached(nil, keys.Grid+".energy")},
+		{addr: RegPvPower, float: true, get: h.cached(nil, keys.PvPower)},
+		{addr: RegPvEnergy, float: true, get: h.cached(nil, keys.PvEnergy)},
+		{addr: RegHomePower, float: true, get: h.cached(nil, keys.HomePower)},
+		{addr: RegBatteryPower, float: true, get: h.cached(nil, keys.BatteryPower)},
+		{addr: RegBatterySoc, float: true, get: h.cached(nil, keys.BatterySoc)},
+		{addr: RegTariffGrid, float: true, get: h.cached(nil, keys.TariffGrid)},
+		{addr: RegTariffFeedIn, float: true, get: h.cached(nil, keys.TariffFeedIn)},
+		{addr: RegBufferSoc, float: true, get: h.site.GetBufferSoc, set: h.site.SetBufferSoc},
+		{addr: RegPrioritySoc, float: true, get: h.site.GetPrioritySoc, set: h.site.SetPrioritySoc},
+		{addr: RegResidualPower, float: true, get: h.site.GetResidualPower, set: h.site.SetResidualPower},
+	}
+
+	for i, lp := range lps {
+		res = append(res, h.loadpointRegisters(i, lp)...)
+	}
+
+	return res
+}
+
+func (h *siteHandler) loadpointRegisters(id int, lp loadpoint.API) []register {
+	base := uint16(RegLoadpointBase + RegLoadpointBlock*id)
+
+	return []register{
+		{addr: base + RegLpChargePower, float: true, get: h.cached(&id, keys.ChargePower)},
+		{addr: base + RegLpChargedEnergy, float: true, get: h.cached(&id, keys.ChargedEnergy)},
+		{addr: base + RegLpVehicleSoc, float: true, get: h.cached(&id, keys.VehicleSoc)},
+		{addr: base + RegLpConnected, get: h.cached(&id, keys.Connected)},
+		{addr: base + RegLpCharging, get: h.cached(&id, keys.Charging)},
+		{addr: base + RegLpEnabled, get: h.cached(&id, keys.Enabled)},
+		{addr: base + RegLpPhasesActive, get: h.cached(&id, keys.PhasesActive)},
+		{
+			addr: base + RegLpMode,
+			get: func() float64 {
+				for i, m := range modes {
+					if m == lp.GetMode() {
+						return float64(i)
+					}
+				}
+				return 0
+			},
+			set: func(v float64) error {
+				if int(v) >= len(modes) {
+					return mbserver.ErrIllegalDataValue
+				}
+				lp.SetMode(modes[int(v)])
+				return nil
+			},
+		},
+		{addr: base + RegLpMinCurrent, float: true, get: lp.GetMinCurrent, set: lp.SetMinCurrent},
+		{addr: base + RegLpMaxCurrent, float: true, get: lp.GetMaxCurrent, set: lp.SetMaxCurrent},
+		{
+			addr: base + RegLpLimitSoc,
+			get:  func() float64 { return float64(lp.GetLimitSoc()) },
+			set: func(v float64) error {
+				if v > 100 {
+					return mbserver.ErrIllegalDataValue
+				}
+				lp.SetLimitSoc(int(v))
+				return nil
+			},
+		},
+	}
+}
+
+// validRange checks that the requested range does not exceed the register address space
+func validRange(addr, qty uint16) bool {
+	return int(addr)+int(qty) <= math.MaxUint16+1
+}
+
+// overlaps checks if the register overlaps the requested range without uint16 wrap around
+func (r register) overlaps(addr, qty uint16) bool {
+	return int(r.addr)+int(r.size()) > int(addr) && int(r.addr) < int(addr)+int(qty)
+}
+
+// read encodes all registers in the requested range. Unmapped registers read as zero.
+func (h *siteHandler) read(addr, qty uint16) ([]uint16, error) {
+	if !validRange(addr, qty) {
+		return nil, mbserver.ErrIllegalDataAddress
+	}
+
+	b := make([]byte, 2*int(qty))
+
+	for _, r := range h.registers() {
+		if !r.overlaps(addr, qty) {
+			continue
+		}
+
+		val := make([]byte, 4)
+		if r.float {
+			binary.BigEndian.PutUint32(val, math.Float32bits(float32(r.get())))
+		} else {
+			binary.BigEndian.PutUint16(val, uint16(r.get()))
+		}
+
+		// copy overlapping part only
+		for i := range int(r.size()) {
+			if reg := int(r.addr) + i; reg >= int(addr) && reg < int(addr)+int(qty) {
+				copy(b[2*(reg-int(addr)):], val[2*i:2*i+2])
+			}
+		}
+	}
+
+	return bytesAsUint16(b), nil
+}
+
+// write decodes and applies all registers in the requested range. Registers must be written completely.
+func (h *siteHandler) write(addr uint16, args []uint16) error {
+	if len(args) > math.MaxUint16 || !validRange(addr, uint16(len(args))) {
+		return mbserver.ErrIllegalDataAddress
+	}
+
+	qty := uint16(len(args))
+	b := asBytes(args)
+
+	var found bool
+	for _, r := range h.registers() {
+		if !r.overlaps(addr, qty) {
+			continue
+		}
+
+		if r.set == nil || r.addr < addr || int(r.addr)+int(r.size()) > int(addr)+int(qty) {
+			return mbserver.ErrIllegalDataAddress
+		}
+
+		offset := 2 * (int(r.addr) - int(addr))
+
+		var val float64
+		if r.float {
+			val = float64(math.Float32frombits(binary.BigEndian.Uint32(b[offset:])))
+		} else {
+			val = float64(binary.BigEndian.Uint16(b[offset:]))
+		}
+
+		if math.IsNaN(val) || val < 0 && r.addr != RegResidualPower {
+			return mbserver.ErrIllegalDataValue
+		}
+
+		h.log.DEBUG.Printf("write: addr %d val %v", r.addr, val)
+
+		if err := r.set(val); err != nil {
+			h.log.ERROR.Printf("write: addr %d: %v", r.addr, err)
+			return mbserver.ErrIllegalDataValue
+		}
+
+		found = true
+	}
+
+	if !found {
+		return mbserver.ErrIllegalDataAddress
+	}
+
+	return nil
+}
+
+func (h *siteHandler) HandleInputRegisters(req *mbserver.InputRegistersRequest) ([]uint16, error) {
+	h.log.TRACE.Printf("read input: id %d addr %d qty %d", req.UnitId, req.Addr, req.Quantity)
+	return h.read(req.Addr, req.Quantity)
+}
+
+func (h *siteHandler) HandleHoldingRegisters(req *mbserver.HoldingRegistersRequest) ([]uint16, error) {
+	if req.IsWrite {
+		if !h.writable {
+			h.log.TRACE.Printf("deny: write holdings: id %d addr %d qty %d val %0x", req.UnitId, req.Addr, req.Quantity, asBytes(req.Args))
+			return nil, mbserver.ErrIllegalFunction
+		}
+
+		h.log.TRACE.Printf("write holdings: id %d addr %d qty %d val %0x", req.UnitId, req.Addr, req.Quantity, asBytes(req.Args))
+		return req.Args, h.write(req.Addr, req.Args)
+	}
+
+	h.log.TRACE.Printf("read holding: id %d addr %d qty %d", req.UnitId, req.Addr, req.Quantity)
+	return h.read(req.Addr, req.Quantity)
+}
@@ -0,0 +1,72 @@
+package modbus
+
+import (
+	"math"
+	"testing"
+
+	"github.com/andig/mbserver"
+	"github.com/evcc-io/evcc/core/loadpoint"
+	"github.com/evcc-io/evcc/core/site"
+	"github.com/evcc-io/evcc/util"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type testSite struct {
+	site.API
+	bufferSoc float64
+}
+
+func (s *testSite) Loadpoints() []loadpoint.API    { return nil }
+func (s *testSite) GetBufferSoc() float64          { return s.bufferSoc }
+func (s *testSite) SetBufferSoc(v float64) error   { s.bufferSoc = v; return nil }
+func (s *testSite) GetPrioritySoc() float64        { return 0 }
+func (s *testSite) SetPrioritySoc(float64) error   { return nil }
+func (s *testSite) GetResidualPower() float64      { return 0 }
+func (s *testSite) SetResidualPower(float64) error { return nil }
+
+func TestSiteRegisters(t *testing.T) {
+	cache := util.NewParamCache()
+	cache.Add("grid", util.Param{Key: "grid", Val: struct {
+		Power float64 `json:"power"`
+	}{Power: -1500}})
+	cache.Add("pvPower", util.Param{Key: "pvPower", Val: 4200.0})
+
+	s := &testSite{bufferSoc: 80}
+	h := &siteHandler{log: util.NewLogger("foo"), site: s, cache: cache}
+
+	u, err := h.read(RegVersion, 8)
+	require.NoError(t, err)
+	assert.Equal(t, uint16(siteRegisterVersion), u[RegVersion])
+	assert.Equal(t, float32(-1500), math.Float32frombits(uint32(u[RegGridPower])<<16|uint32(u[RegGridPower+1])))
+	assert.Equal(t, float32(4200), math.Float32frombits(uint32(u[RegPvPower])<<16|uint32(u[RegPvPower+1])))
+
+	// partial float read
+	u, err = h.read(RegPvPower+1, 1)
+	require.NoError(t, err)
+	assert.Equal(t, uint16(math.Float32bits(4200)), u[0])
+
+	// range exceeding the address space
+	_, err = h.read(math.MaxUint16, 2)
+	assert.Equal(t, mbserver.ErrIllegalDataAddress, err)
+	assert.Equal(t, mbserver.ErrIllegalDataAddress, h.write(math.MaxUint16, []uint16{0, 0}))
+
+	// range ending at the last register
+	u, err = h.read(math.MaxUint16-1, 2)
+	require.NoError(t, err)
+	assert.Equal(t, []uint16{0, 0}, u)
+
+	// read-only server
+	_, err = h.HandleHoldingRegisters(&mbserver.HoldingRegistersRequest{IsWrite: true, Addr: RegBufferSoc, Quantity: 2, Args: []uint16{0, 0}})
+	assert.Equal(t, mbserver.ErrIllegalFunction, err)
+
+	h.writable = true
+	bits := math.Float32bits(50)
+	_, err = h.HandleHoldingRegisters(&mbserver.HoldingRegistersRequest{IsWrite: true, Addr: RegBufferSoc, Quantity: 2, Args: []uint16{uint16(bits >> 16), uint16(bits)}})
+	require.NoError(t, err)
+	assert.Equal(t, 50.0, s.bufferSoc)
+
+	// incomplete and read-only registers
+	assert.Equal(t, mbserver.ErrIllegalDataAddress, h.write(RegBufferSoc, []uint16{0}))
+	assert.Equal(t, mbserver.ErrIllegalDataAddress, h.write(RegPvPower, []uint16{0, 0}))
+}
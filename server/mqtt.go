@@ -9,6 +9,7 @@ import (
 	"time"
 
 	"github.com/evcc-io/evcc/api"
+	"github.com/evcc-io/evcc/api/globalconfig"
 	"github.com/evcc-io/evcc/cmd/shutdown"
 	"github.com/evcc-io/evcc/core/loadpoint"
 	"github.com/evcc-io/evcc/core/site"
@@ -23,19 +24,29 @@ type MQTT struct {
 	log       *util.Logger
 	Handler   *mqtt.Client
 	root      string
+	topics    topicSchema
+	setters   map[string]func(string) error // setter functions by topic
+	homie     map[string]string             // homie property topics by state topic
 	publisher func(topic string, retained bool, payload string)
 }
 
 // NewMQTT creates MQTT server
-func NewMQTT(root string, site site.API) (*MQTT, error) {
+func NewMQTT(root string, schema globalconfig.MqttSchema, site site.API) (*MQTT, error) {
+	topics, err := newTopicSchema(root, schema)
+	if err != nil {
+		return nil, fmt.Errorf("mqtt: %w", err)
+	}
+
 	m := &MQTT{
 		log:     util.NewLogger("mqtt"),
 		Handler: mqtt.Instance,
 		root:    root,
+		topics:  topics,
+		setters: make(map[string]func(string) error),
 	}
 	m.publisher = m.publishString
 
-	err := m.Handler.Cleanup(m.root, true)
+	err = m.Handler.Cleanup(m.root, true)
 	if err == nil {
 		err = m.Listen(site)
 	}
@@ -180,22 +191,20 @@ func (m *MQTT) publish(topic string, retained bool, payload any) {
 }
 
 func (m *MQTT) Listen(site site.API) error {
-	if err := m.listenSiteSetters(m.root+"/site", site); err != nil {
+	if err := m.listenSiteSetters(m.topics.Site(), site); err != nil {
 		return err
 	}
 
 	// loadpoint setters
 	for id, lp := range site.Loadpoints() {
-		topic := fmt.Sprintf("%s/loadpoints/%d", m.root, id+1)
-		if err := m.listenLoadpointSetters(topic, site, lp); err != nil {
+		if err := m.listenLoadpointSetters(m.topics.Loadpoint(id+1), site, lp); err != nil {
 			return err
 		}
 	}
 
 	// vehicle setters
 	for _, vehicle := range site.Vehicles().Settings() {
-		topic := fmt.Sprintf("%s/vehicles/%s", m.root, vehicle.Name())
-		if err := m.listenVehicleSetters(topic, vehicle); err != nil {
+		if err := m.listenVehicleSetters(m.topics.Vehicle(vehicle.Name()), vehicle); err != nil {
 			return err
 		}
 	}
@@ -228,7 +237,7 @@ func (m *MQTT) listenSiteSetters(topic string, site site.API) error {
 			return site.SetBatteryModeExternal(*m)
 		})},
 	} {
-		if err := m.Handler.ListenSetter(topic+"/"+s.topic, s.fun); err != nil {
+		if err := m.listenSetter(topic+"/"+s.topic, s.fun); err != nil {
 			return err
 		}
 	}
@@ -277,7 +286,7 @@ func (m *MQTT) listenLoadpointSetters(topic string, site site.API, lp loadpoint.
 			return err
 		}},
 	} {
-		if err := m.Handler.ListenSetter(topic+"/"+s.topic, s.fun); err != nil {
+		if err := m.listenSetter(topic+"/"+s.topic, s.fun); err != nil {
 			return err
 		}
 	}
@@ -302,7 +311,7 @@ func (m *MQTT) listenVehicleSetters(topic string, v vehicle.API) error {
 			return err
 		}},
 	} {
-		if err := m.Handler.ListenSetter(topic+"/"+s.topic, s.fun); err != nil {
+		if err := m.listenSetter(topic+"/"+s.topic, s.fun); err != nil {
 			return err
 		}
 	}
@@ -313,17 +322,15 @@ func (m *MQTT) listenVehicleSetters(topic string, v vehicle.API) error {
 // Run starts the MQTT publisher for the MQTT API
 func (m *MQTT) Run(site site.API, in <-chan util.Param) {
 	// number of loadpoints
-	topic := fmt.Sprintf("%s/loadpoints", m.root)
-	m.publish(topic, true, len(site.Loadpoints()))
+	m.publish(m.topics.Loadpoints(), true, len(site.Loadpoints()))
 
 	// number of vehicles
-	topic = fmt.Sprintf("%s/vehicles", m.root)
-	m.publish(topic, true, len(site.Vehicles().Settings()))
+	m.publish(m.topics.Vehicles(), true, len(site.Vehicles().Settings()))
 
 	for i := range 10 {
-		m.publish(fmt.Sprintf("%s/site/pv/%d", m.root, i), true, nil)
-		m.publish(fmt.Sprintf("%s/site/battery/%d", m.root, i), true, nil)
-		m.publish(fmt.Sprintf("%s/site/vehicles/%d", m.root, i), true, nil)
+		m.publish(fmt.Sprintf("%s/pv/%d", m.topics.Site(), i), true, nil)
+		m.publish(fmt.Sprintf("%s/battery/%d", m.topics.Site(), i), true, nil)
+		m.publish(fmt.Sprintf("%s/vehicles/%d", m.topics.Site(), i), true, nil)
 	}
 
 	// alive indicator
@@ -331,23 +338,32 @@ func (m *MQTT) Run(site site.API, in <-chan util.Param) {
 
 	// publish
 	for p := range in {
+		// alive indicator
+		if time.Since(updated) > time.Second {
+			updated = time.Now()
+			m.publish(fmt.Sprintf("%s/updated", m.root), true, updated.Unix())
+		}
+
 		switch {
 		case p.Loadpoint != nil:
-			id := *p.Loadpoint + 1
-			topic = fmt.Sprintf("%s/loadpoints/%d/%s", m.root, id, p.Key)
+			m.publish(fmt.Sprintf("%s/%s", m.topics.Loadpoint(*p.Loadpoint+1), p.Key), true, p.Val)
 		case p.Key == "vehicles":
-			topic = fmt.Sprintf("%s/vehicles", m.root)
+			m.publishVehicles(p.Val)
 		default:
-			topic = fmt.Sprintf("%s/site/%s", m.root, p.Key)
+			m.publish(fmt.Sprintf("%s/%s", m.topics.Site(), p.Key), true, p.Val)
 		}
+	}
+}
 
-		// alive indicator
-		if time.Since(updated) > time.Second {
-			updated = time.Now()
-			m.publish(fmt.Sprintf("%s/updated", m.root), true, updated.Unix())
-		}
+// publishVehicles publishes the vehicle states by name to the vehicle topics
+func (m *MQTT) publishVehicles(payload any) {
+	val := reflect.ValueOf(payload)
+	if val.Kind() != reflect.Map {
+		m.log.ERROR.Printf("invalid vehicles: %T", payload)
+		return
+	}
 
-		// value
-		m.publish(topic, true, p.Val)
+	for iter := val.MapRange(); iter.Next(); {
+		m.publish(m.topics.Vehicle(iter.Key().String()), true, iter.Value().Interface())
 	}
 }
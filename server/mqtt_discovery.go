@@ -0,0 +1,294 @@
+package server
+
+import (
+	"cmp"
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"github.com/evcc-io/evcc/api"
+	"github.com/evcc-io/evcc/core/site"
+	"github.com/evcc-io/evcc/util"
+)
+
+// haDevice is the Home Assistant device description
+type haDevice struct {
+	Identifiers  []string `json:"identifiers"`
+	Name         string   `json:"name"`
+	Manufacturer string   `json:"manufacturer,omitempty"`
+	Model        string   `json:"model,omitempty"`
+	SwVersion    string   `json:"sw_version,omitempty"`
+	ViaDevice    string   `json:"via_device,omitempty"`
+}
+
+// haEntity is the Home Assistant discovery payload
+type haEntity struct {
+	Name              string   `json:"name"`
+	UniqueID          string   `json:"unique_id"`
+	ObjectID          string   `json:"object_id"`
+	StateTopic        string   `json:"state_topic"`
+	CommandTopic      string   `json:"command_topic,omitempty"`
+	AvailabilityTopic string   `json:"availability_topic,omitempty"`
+	DeviceClass       string   `json:"device_class,omitempty"`
+	StateClass        string   `json:"state_class,omitempty"`
+	UnitOfMeasurement string   `json:"unit_of_measurement,omitempty"`
+	Icon              string   `json:"icon,omitempty"`
+	Options           []string `json:"options,omitempty"`
+	Min               *float64 `json:"min,omitempty"`
+	Max               *float64 `json:"max,omitempty"`
+	Step              float64  `json:"step,omitempty"`
+	Mode              string   `json:"mode,omitempty"`
+	PayloadOn         string   `json:"payload_on,omitempty"`
+	PayloadOff        string   `json:"payload_off,omitempty"`
+	EntityCategory    string   `json:"entity_category,omitempty"`
+	Device            haDevice `json:"device"`
+}
+
+// haComponent is a discoverable entity with its Home Assistant component type
+type haComponent struct {
+	component string // sensor, binary_sensor, number, select, switch
+	key       string // evcc topic key relative to the device root topic
+	setter    string // evcc setter key if different from the topic key
+	entity    haEntity
+	settable  bool
+}
+
+// setterKey returns the evcc setter key relative to the device root topic
+func (c haComponent) setterKey() string {
+	return cmp.Or(c.setter, c.key)
+}
+
+func haSensor(key, name, deviceClass, stateClass, unit string) haComponent {
+	return haComponent{
+		component: "sensor",
+		key:       key,
+		entity: haEntity{
+			Name:              name,
+			DeviceClass:       deviceClass,
+			StateClass:        stateClass,
+			UnitOfMeasurement: unit,
+		},
+	}
+}
+
+func haBinarySensor(key, name, deviceClass string) haComponent {
+	return haComponent{
+		component: "binary_sensor",
+		key:       key,
+		entity: haEntity{
+			Name:        name,
+			DeviceClass: deviceClass,
+			PayloadOn:   "true",
+			PayloadOff:  "false",
+		},
+	}
+}
+
+func haNumber(key, name, unit string, lo, hi, step float64) haComponent {
+	return haComponent{
+		component: "number",
+		key:       key,
+		settable:  true,
+		entity: haEntity{
+			Name:              name,
+			UnitOfMeasurement: unit,
+			Min:               &lo,
+			Max:               &hi,
+			Step:              step,
+			Mode:              "box",
+		},
+	}
+}
+
+func haSelect(key, name string, options ...string) haComponent {
+	return haComponent{
+		component: "select",
+		key:       key,
+		settable:  true,
+		entity: haEntity{
+			Name:    name,
+			Options: options,
+		},
+	}
+}
+
+// haSelectSetter is a select whose setter differs from its state topic
+func haSelectSetter(key, setter, name string, options ...string) haComponent {
+	c := haSelect(key, name, options...)
+	c.setter = setter
+	return c
+}
+
+func haSwitch(key, name string) haComponent {
+	return haComponent{
+		component: "switch",
+		key:       key,
+		settable:  true,
+		entity: haEntity{
+			Name:       name,
+			PayloadOn:  "true",
+			PayloadOff: "false",
+		},
+	}
+}
+
+// haObjectID converts names into Home Assistant compatible object ids
+func haObjectID(s ...string) string {
+	return strings.Map(func(r rune) rune {
+		switch {
+		case r >= 'a' && r <= 'z', r >= '0' && r <= '9':
+			return r
+		case r >= 'A' && r <= 'Z':
+			return r + 'a' - 'A'
+		default:
+			return '_'
+		}
+	}, strings.Join(s, "_"))
+}
+
+// publishDiscoveryDevice publishes the discovery configuration for all components of a single device
+func (m *MQTT) publishDiscoveryDevice(prefix, root string, dev haDevice, components []haComponent) {
+	for _, c := range components {
+		e := c.entity
+
+		e.ObjectID = haObjectID(dev.Identifiers[0], c.key)
+		e.UniqueID = e.ObjectID
+		e.StateTopic = fmt.Sprintf("%s/%s", root, c.key)
+		e.AvailabilityTopic = fmt.Sprintf("%s/status", m.root)
+		e.Device = dev
+
+		if c.settable {
+			e.CommandTopic = fmt.Sprintf("%s/%s/set", root, c.setterKey())
+		}
+
+		b, err := json.Marshal(e)
+		if err != nil {
+			m.log.ERROR.Printf("discovery: %v", err)
+			continue
+		}
+
+		topic := fmt.Sprintf("%s/%s/%s/%s/config", prefix, c.component, dev.Identifiers[0], haObjectID(c.key))
+		m.publisher(topic, true, string(b))
+	}
+}
+
+// siteComponents returns the site entities including configured pv and battery meters
+func siteComponents(site site.API) []haComponent {
+	res := []haComponent{
+		haSensor("grid/power", "Grid power", "power", "measurement", "W"),
+		haSensor("grid/energy", "Grid energy", "energy", "total_increasing", "kWh"),
+		haSensor("pvPower", "PV power", "power", "measurement", "W"),
+		haSensor("pvEnergy", "PV energy", "energy", "total_increasing", "kWh"),
+		haSensor("homePower", "Home power", "power", "measurement", "W"),
+		haSensor("batteryPower", "Battery power", "power", "measurement", "W"),
+		haSensor("batterySoc", "Battery soc", "battery", "measurement", "%"),
+		haSensor("tariffGrid", "Grid tariff", "", "measurement", ""),
+		haSensor("tariffFeedIn", "Feed-in tariff", "", "measurement", ""),
+		haSensor("greenShareHome", "Green share home", "", "measurement", ""),
+		haNumber("bufferSoc", "Buffer soc", "%", 0, 100, 1),
+		haNumber("bufferStartSoc", "Buffer start soc", "%", 0, 100, 1),
+		haNumber("prioritySoc", "Priority soc", "%", 0, 100, 1),
+		haNumber("residualPower", "Residual power", "W", -10000, 10000, 1),
+		haSwitch("batteryDischargeControl", "Battery discharge control"),
+	}
+
+	for i := range site.GetPVMeterRefs() {
+		res = append(res,
+			haSensor(fmt.Sprintf("pv/%d/power", i+1), fmt.Sprintf("PV %d power", i+1), "power", "measurement", "W"),
+		)
+	}
+
+	for i := range site.GetBatteryMeterRefs() {
+		res = append(res,
+			haSensor(fmt.Sprintf("battery/%d/power", i+1), fmt.Sprintf("Battery %d power", i+1), "power", "measurement", "W"),
+			haSensor(fmt.Sprintf("battery/%d/soc", i+1), fmt.Sprintf("Battery %d soc", i+1), "battery", "measurement", "%"),
+		)
+	}
+
+	return res
+}
+
+// loadpointComponents returns the loadpoint entities
+func loadpointComponents() []haComponent {
+	modes := []string{string(api.ModeOff), string(api.ModeNow), string(api.ModeMinPV), string(api.ModePV)}
+
+	return []haComponent{
+		haSensor("chargePower", "Charge power", "power", "measurement", "W"),
+		haSensor("chargedEnergy", "Charged energy", "energy", "total", "Wh"),
+		haSensor("chargeTotalImport", "Charge total import", "energy", "total_increasing", "kWh"),
+		haSensor("chargeDuration", "Charge duration", "duration", "measurement", "s"),
+		haSensor("vehicleSoc", "Vehicle soc", "battery", "measurement", "%"),
+		haSensor("vehicleRange", "Vehicle range", "distance", "measurement", "km"),
+		haSensor("vehicleTitle", "Vehicle", "", "", ""),
+		haSensor("phasesActive", "Active phases", "", "measurement", ""),
+		haBinarySensor("connected", "Connected", "plug"),
+		haBinarySensor("charging", "Charging", "battery_charging"),
+		haBinarySensor("enabled", "Enabled", "power"),
+		haSelect("mode", "Mode", modes...),
+		haSelectSetter("phasesConfigured", "phases", "Phases", "0", "1", "3"),
+		haNumber("limitSoc", "Limit soc", "%", 0, 100, 5),
+		haNumber("limitEnergy", "Limit energy", "kWh", 0, 200, 1),
+		haNumber("minCurrent", "Min current", "A", 0, 32, 1),
+		haNumber("maxCurrent", "Max current", "A", 0, 32, 1),
+		haNumber("priority", "Priority", "", 0, 10, 1),
+		haSwitch("batteryBoost", "Battery boost"),
+	}
+}
+
+// vehicleComponents returns the vehicle entities
+func vehicleComponents() []haComponent {
+	return []haComponent{
+		haNumber("minSoc", "Min soc", "%", 0, 100, 5),
+		haNumber("limitSoc", "Limit soc", "%", 0, 100, 5),
+	}
+}
+
+// PublishDiscovery publishes Home Assistant MQTT discovery messages for site, loadpoints and vehicles
+func (m *MQTT) PublishDiscovery(prefix string, site site.API) {
+	prefix = strings.Trim(prefix, "/")
+	id := haObjectID(m.root)
+
+	siteDevice := haDevice{
+		Identifiers:  []string{id},
+		Name:         "evcc",
+		Manufacturer: "evcc.io",
+		Model:        "evcc",
+		SwVersion:    util.FormattedVersion(),
+	}
+
+	if title := site.GetTitle(); title != "" {
+		siteDevice.Name = title
+	}
+
+	m.publishDiscoveryDevice(prefix, m.topics.Site(), siteDevice, siteComponents(site))
+
+	// loadpoints
+	for i, lp := range site.Loadpoints() {
+		dev := haDevice{
+			Identifiers: []string{fmt.Sprintf("%s_loadpoint_%d", id, i+1)},
+			Name:        lp.GetTitle(),
+			ViaDevice:   id,
+		}
+
+		if dev.Name == "" {
+			dev.Name = fmt.Sprintf("Loadpoint %d", i+1)
+		}
+
+		m.publishDiscoveryDevice(prefix, m.topics.Loadpoint(i+1), dev, loadpointComponents())
+	}
+
+	// vehicles
+	for _, v := range site.Vehicles().Settings() {
+		dev := haDevice{
+			Identifiers: []string{fmt.Sprintf("%s_vehicle_%s", id, haObjectID(v.Name()))},
+			Name:        v.Instance().GetTitle(),
+			ViaDevice:   id,
+		}
+
+		if dev.Name == "" {
+			dev.Name = v.Name()
+		}
+
+		m.publishDiscoveryDevice(prefix, m.topics.Vehicle(v.Name()), dev, vehicleComponents())
+	}
+}
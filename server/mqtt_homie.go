@@ -0,0 +1,194 @@
+package server
+
+import (
+	"fmt"
+	"strings"
+	"unicode"
+
+	"github.com/evcc-io/evcc/cmd/shutdown"
+	"github.com/evcc-io/evcc/core/site"
+)
+
+// homieBase is the Homie convention root topic
+const homieBase = "homie"
+
+// homieNode is a Homie node with its evcc state topic and properties
+type homieNode struct {
+	id, name, root string
+	components     []haComponent
+}
+
+// homieID converts evcc keys into Homie compatible ids, e.g. grid/power to grid-power
+func homieID(s string) string {
+	var b strings.Builder
+
+	hyphen := func() {
+		if b.Len() > 0 && !strings.HasSuffix(b.String(), "-") {
+			b.WriteRune('-')
+		}
+	}
+
+	for _, r := range s {
+		switch {
+		case r >= 'a' && r <= 'z', r >= '0' && r <= '9':
+			b.WriteRune(r)
+		case r >= 'A' && r <= 'Z':
+			hyphen()
+			b.WriteRune(unicode.ToLower(r))
+		default:
+			hyphen()
+		}
+	}
+
+	return strings.TrimSuffix(b.String(), "-")
+}
+
+// homieDatatype returns Homie datatype and format of the discovery component
+func homieDatatype(c haComponent) (string, string) {
+	switch c.component {
+	case "binary_sensor", "switch":
+		return "boolean", ""
+	case "select":
+		return "enum", strings.Join(c.entity.Options, ",")
+	case "number":
+		return "float", fmt.Sprintf("%g:%g", *c.entity.Min, *c.entity.Max)
+	}
+
+	if c.entity.StateClass == "" {
+		return "string", ""
+	}
+
+	return "float", ""
+}
+
+// publishHomieDevice publishes the Homie device description and registers the property topics
+// for mirroring evcc values. Returns the settable property topics by evcc state topic.
+func (m *MQTT) publishHomieDevice(device, name string, nodes []homieNode) map[string]string {
+	base := fmt.Sprintf("%s/%s", homieBase, device)
+	publish := func(topic, payload string) {
+		m.publisher(base+topic, true, payload)
+	}
+
+	publish("/$state", "init")
+	publish("/$homie", "4.0")
+	publish("/$name", name)
+	publish("/$extensions", "")
+
+	ids := make([]string, 0, len(nodes))
+	for _, n := range nodes {
+		ids = append(ids, n.id)
+	}
+	publish("/$nodes", strings.Join(ids, ","))
+
+	if m.homie == nil {
+		m.homie = make(map[string]string)
+	}
+
+	res := make(map[string]string)
+
+	for _, n := range nodes {
+		node := "/" + n.id
+		publish(node+"/$name", n.name)
+
+		props := make([]string, 0, len(n.components))
+		for _, c := range n.components {
+			prop := node + "/" + homieID(c.key)
+			props = append(props, homieID(c.key))
+
+			datatype, format := homieDatatype(c)
+			publish(prop+"/$name", c.entity.Name)
+			publish(prop+"/$datatype", datatype)
+
+			if format != "" {
+				publish(prop+"/$format", format)
+			}
+			if unit := c.entity.UnitOfMeasurement; unit != "" {
+				publish(prop+"/$unit", unit)
+			}
+			if c.settable {
+				publish(prop+"/$settable", "true")
+				res[n.root+"/"+c.setterKey()] = base + prop
+			}
+
+			m.homie[n.root+"/"+c.key] = base + prop
+		}
+
+		publish(node+"/$properties", strings.Join(props, ","))
+	}
+
+	publish("/$state", "ready")
+
+	return res
+}
+
+// PublishHomie publishes site, loadpoints and vehicles according to the Homie convention.
+// Values are mirrored from the evcc topics, settable properties are forwarded to the evcc setters.
+func (m *MQTT) PublishHomie(device string, site site.API) error {
+	device = homieID(device)
+	base := fmt.Sprintf("%s/%s", homieBase, device)
+
+	if err := m.Handler.Cleanup(base, true); err != nil {
+		return fmt.Errorf("homie: %w", err)
+	}
+
+	name := site.GetTitle()
+	if name == "" {
+		name = "evcc"
+	}
+
+	nodes := []homieNode{{id: "site", name: name, root: m.topics.Site(), components: siteComponents(site)}}
+
+	for i, lp := range site.Loadpoints() {
+		title := lp.GetTitle()
+		if title == "" {
+			title = fmt.Sprintf("Loadpoint %d", i+1)
+		}
+
+		nodes = append(nodes, homieNode{
+			id:         fmt.Sprintf("loadpoint-%d", i+1),
+			name:       title,
+			root:       m.topics.Loadpoint(i + 1),
+			components: loadpointComponents(),
+		})
+	}
+
+	for _, v := range site.Vehicles().Settings() {
+		title := v.Instance().GetTitle()
+		if title == "" {
+			title = v.Name()
+		}
+
+		nodes = append(nodes, homieNode{
+			id:         "vehicle-" + homieID(v.Name()),
+			name:       title,
+			root:       m.topics.Vehicle(v.Name()),
+			components: vehicleComponents(),
+		})
+	}
+
+	settable := m.publishHomieDevice(device, name, nodes)
+
+	for topic, prop := range settable {
+		if fun, ok := m.setters[topic]; ok {
+			if err := m.listenSetter(prop, fun); err != nil {
+				return fmt.Errorf("homie: %w", err)
+			}
+		}
+	}
+
+	// mirror values to homie properties
+	publisher := m.publisher
+	m.publisher = func(topic string, retained bool, payload string) {
+		publisher(topic, retained, payload)
+
+		if prop, ok := m.homie[topic]; ok {
+			publisher(prop, retained, payload)
+		}
+	}
+
+	shutdown.Register(func() {
+		m.Handler.Publish(base+"/$state", true, "disconnected")
+	})
+
+	return nil
+}
@@ -0,0 +1,92 @@
+package server
+
+import (
+	"cmp"
+	"errors"
+	"strconv"
+	"strings"
+
+	"github.com/evcc-io/evcc/api/globalconfig"
+)
+
+// default topic layout
+const (
+	defaultSiteTopic      = "{root}/site"
+	defaultLoadpointTopic = "{root}/loadpoints/{id}"
+	defaultVehicleTopic   = "{root}/vehicles/{name}"
+)
+
+// topicSchema is the MQTT topic layout for site, loadpoints and vehicles
+type topicSchema struct {
+	root, site, loadpoint, vehicle string
+}
+
+// newTopicSchema validates the configured topic layout. Topics must start with {root}
+// to allow cleaning up retained values on startup and shutdown.
+func newTopicSchema(root string, conf globalconfig.MqttSchema) (topicSchema, error) {
+	res := topicSchema{
+		root:      root,
+		site:      strings.TrimRight(cmp.Or(conf.Site, defaultSiteTopic), "/"),
+		loadpoint: strings.TrimRight(cmp.Or(conf.Loadpoint, defaultLoadpointTopic), "/"),
+		vehicle:   strings.TrimRight(cmp.Or(conf.Vehicle, defaultVehicleTopic), "/"),
+	}
+
+	for _, topic := range []string{res.site, res.loadpoint, res.vehicle} {
+		if topic != "{root}" && !strings.HasPrefix(topic, "{root}/") {
+			return res, errors.New("schema topics must start with {root}")
+		}
+	}
+
+	if !strings.Contains(res.loadpoint, "{id}") {
+		return res, errors.New("schema loadpoint topic must contain {id}")
+	}
+
+	if !strings.Contains(res.vehicle, "{name}") {
+		return res, errors.New("schema vehicle topic must contain {name}")
+	}
+
+	if res.site == res.loadpoint || res.site == res.vehicle {
+		return res, errors.New("schema topics must be unique")
+	}
+
+	r := strings.NewReplacer("{root}", root)
+	res.site = r.Replace(res.site)
+	res.loadpoint = r.Replace(res.loadpoint)
+	res.vehicle = r.Replace(res.vehicle)
+
+	return res, nil
+}
+
+// Site returns the site topic
+func (s topicSchema) Site() string {
+	return s.site
+}
+
+// Loadpoint returns the topic of the 1-based loadpoint id
+func (s topicSchema) Loadpoint(id int) string {
+	return strings.ReplaceAll(s.loadpoint, "{id}", strconv.Itoa(id))
+}
+
+// Vehicle returns the topic of the named vehicle
+func (s topicSchema) Vehicle(name string) string {
+	return strings.ReplaceAll(s.vehicle, "{name}", name)
+}
+
+// Loadpoints returns the topic of the number of loadpoints
+func (s topicSchema) Loadpoints() string {
+	return s.parent(s.loadpoint, "{id}", "loadpoints")
+}
+
+// Vehicles returns the topic of the number of vehicles
+func (s topicSchema) Vehicles() string {
+	return s.parent(s.vehicle, "{name}", "vehicles")
+}
+
+// parent returns the topic level containing the placeholder level. Defaults to the root level
+// if the placeholder is not a separate level or its parent is the site topic.
+func (s topicSchema) parent(topic, placeholder, def string) string {
+	if parent, ok := strings.CutSuffix(topic, "/"+placeholder); ok && !strings.Contains(parent, placeholder) && parent != s.site {
+		return parent
+	}
+	return s.root + "/" + def
+}
@@ -5,6 +5,7 @@ import (
 	"strconv"
 	"time"
 
+	"github.com/evcc-io/evcc/server/db/audit"
 	"github.com/evcc-io/evcc/util"
 	"github.com/spf13/cast"
 )
@@ -63,3 +64,14 @@ func boolSetter(set func(bool) error) func(string) error {
 func durationSetter(set func(time.Duration) error) func(string) error {
 	return setterFunc(util.ParseDuration, set)
 }
+
+// listenSetter subscribes to the setter topic and records received values in the audit log
+func (m *MQTT) listenSetter(topic string, fun func(string) error) error {
+	m.setters[topic] = fun
+
+	return m.Handler.ListenSetter(topic, func(payload string) error {
+		err := fun(payload)
+		audit.Log(audit.SourceMqtt, "", topic, payload, err)
+		return err
+	})
+}
@@ -1,14 +1,17 @@
 package server
 
 import (
+	"encoding/json"
 	"math"
 	"slices"
 	"strconv"
 	"testing"
 	"time"
 
+	"github.com/evcc-io/evcc/api/globalconfig"
 	"github.com/samber/lo"
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 	"github.com/stretchr/testify/suite"
 )
 
@@ -123,3 +126,126 @@ func (suite *mqttSuite) TestGrid() {
 	suite.Equal(append(topics, "test/currents/1", "test/currents/2", "test/currents/3"), suite.topics, "topics")
 	suite.Equal([]string{"0", "", "3", "", "", "1", "2", "3"}, suite.payloads, "payloads")
 }
+
+func (suite *mqttSuite) TestVehicles() {
+	topics, err := newTopicSchema("evcc", globalconfig.MqttSchema{Vehicle: "{root}/car/{name}"})
+	suite.Require().NoError(err)
+
+	defer func(t topicSchema) { suite.MQTT.topics = t }(suite.MQTT.topics)
+	suite.MQTT.topics = topics
+
+	suite.publishVehicles(map[string]struct{ Soc int }{"ev": {Soc: 50}})
+	suite.Equal([]string{"evcc/car/ev/soc"}, suite.topics, "topics")
+	suite.Equal([]string{"50"}, suite.payloads, "payloads")
+}
+
+func (suite *mqttSuite) TestDiscovery() {
+	suite.MQTT.root = "evcc"
+	defer func() { suite.MQTT.root = "" }()
+
+	dev := haDevice{Identifiers: []string{"evcc_loadpoint_1"}, Name: "Garage"}
+	suite.publishDiscoveryDevice("homeassistant", "evcc/loadpoints/1", dev, []haComponent{
+		haSelect("mode", "Mode", "off", "pv"),
+	})
+
+	suite.Require().Len(suite.topics, 1)
+	suite.Equal("homeassistant/select/evcc_loadpoint_1/mode/config", suite.topics[0])
+
+	var e haEntity
+	suite.Require().NoError(json.Unmarshal([]byte(suite.payloads[0]), &e))
+	suite.Equal("evcc/loadpoints/1/mode", e.StateTopic)
+	suite.Equal("evcc/loadpoints/1/mode/set", e.CommandTopic)
+	suite.Equal("evcc/status", e.AvailabilityTopic)
+	suite.Equal("evcc_loadpoint_1_mode", e.UniqueID)
+	suite.Equal([]string{"off", "pv"}, e.Options)
+
+	// state and setter topics differ
+	suite.publishDiscoveryDevice("homeassistant", "evcc/loadpoints/1", dev, []haComponent{
+		haSelectSetter("phasesConfigured", "phases", "Phases", "0", "1", "3"),
+	})
+
+	suite.Require().NoError(json.Unmarshal([]byte(suite.payloads[1]), &e))
+	suite.Equal("evcc/loadpoints/1/phasesConfigured", e.StateTopic)
+	suite.Equal("evcc/loadpoints/1/phases/set", e.CommandTopic)
+}
+
+func (suite *mqttSuite) TestHomie() {
+	defer func() { suite.MQTT.homie = nil }()
+
+	settable := suite.publishHomieDevice("evcc", "Home", []homieNode{{
+		id:   "loadpoint-1",
+		name: "Garage",
+		root: "evcc/loadpoints/1",
+		components: []haComponent{
+			haSelect("mode", "Mode", "off", "pv"),
+			haSensor("chargePower", "Charge power", "power", "measurement", "W"),
+		},
+	}})
+
+	payload := func(topic string) string {
+		i := slices.Index(suite.topics, topic)
+		suite.Require().GreaterOrEqual(i, 0, topic)
+		return suite.payloads[i]
+	}
+
+	suite.Equal("4.0", payload("homie/evcc/$homie"))
+	suite.Equal("ready", payload("homie/evcc/$state"))
+	suite.Equal("loadpoint-1", payload("homie/evcc/$nodes"))
+	suite.Equal("mode,charge-power", payload("homie/evcc/loadpoint-1/$properties"))
+	suite.Equal("enum", payload("homie/evcc/loadpoint-1/mode/$datatype"))
+	suite.Equal("off,pv", payload("homie/evcc/loadpoint-1/mode/$format"))
+	suite.Equal("true", payload("homie/evcc/loadpoint-1/mode/$settable"))
+	suite.Equal("float", payload("homie/evcc/loadpoint-1/charge-power/$datatype"))
+	suite.Equal("W", payload("homie/evcc/loadpoint-1/charge-power/$unit"))
+
+	suite.Equal(map[string]string{"evcc/loadpoints/1/mode": "homie/evcc/loadpoint-1/mode"}, settable)
+	suite.Equal("homie/evcc/loadpoint-1/charge-power", suite.MQTT.homie["evcc/loadpoints/1/chargePower"])
+}
+
+func TestHomieID(t *testing.T) {
+	for in, out := range map[string]string{
+		"pvPower":       "pv-power",
+		"grid/power":    "grid-power",
+		"battery/1/soc": "battery-1-soc",
+		"My_Car":        "my-car",
+	} {
+		assert.Equal(t, out, homieID(in), in)
+	}
+}
+
+func TestTopicSchema(t *testing.T) {
+	s, err := newTopicSchema("evcc", globalconfig.MqttSchema{})
+	require.NoError(t, err)
+	assert.Equal(t, "evcc/site", s.Site())
+	assert.Equal(t, "evcc/loadpoints/1", s.Loadpoint(1))
+	assert.Equal(t, "evcc/vehicles/ev", s.Vehicle("ev"))
+	assert.Equal(t, "evcc/loadpoints", s.Loadpoints())
+	assert.Equal(t, "evcc/vehicles", s.Vehicles())
+
+	s, err = newTopicSchema("home/evcc", globalconfig.MqttSchema{
+		Site:      "{root}",
+		Loadpoint: "{root}/charger{id}/",
+	})
+	require.NoError(t, err)
+	assert.Equal(t, "home/evcc", s.Site())
+	assert.Equal(t, "home/evcc/charger2", s.Loadpoint(2))
+	assert.Equal(t, "home/evcc/loadpoints", s.Loadpoints())
+	assert.Equal(t, "home/evcc/vehicles", s.Vehicles())
+
+	s, err = newTopicSchema("evcc", globalconfig.MqttSchema{
+		Loadpoint: "{root}/charger/{id}",
+		Vehicle:   "{root}/car/{name}",
+	})
+	require.NoError(t, err)
+	assert.Equal(t, "evcc/charger", s.Loadpoints())
+	assert.Equal(t, "evcc/car", s.Vehicles())
+
+	for _, conf := range []globalconfig.MqttSchema{
+		{Site: "other/site"},
+		{Loadpoint: "{root}/loadpoint"},
+		{Vehicle: "{root}/vehicle"},
+	} {
+		_, err := newTopicSchema("evcc", conf)
+		assert.Error(t, err, conf)
+	}
+}
@@ -0,0 +1,498 @@
+package ocpi
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/evcc-io/evcc/api"
+	"github.com/evcc-io/evcc/core/loadpoint"
+	"github.com/evcc-io/evcc/core/session"
+	"github.com/evcc-io/evcc/server/db/audit"
+	"github.com/evcc-io/evcc/util/request"
+	"github.com/gorilla/mux"
+)
+
+const (
+	defaultLimit   = 100
+	commandTimeout = 30 // seconds
+)
+
+var (
+	errUnknownLocation = errors.New("unknown location")
+	errUnknownEvse     = errors.New("unknown evse")
+)
+
+func evseStatus(status api.ChargeStatus) string {
+	switch status {
+	case api.StatusA:
+		return "AVAILABLE"
+	case api.StatusB, api.StatusC:
+		return "CHARGING"
+	case api.StatusE:
+		return "OUTOFORDER"
+	default:
+		return "UNKNOWN"
+	}
+}
+
+func powerType(lp loadpoint.API) string {
+	if lp.GetPhasesConfigured() == 1 {
+		return "AC_1_PHASE"
+	}
+	return "AC_3_PHASE"
+}
+
+// evseUid returns the loadpoint's 1-based id
+func evseUid(id int) string {
+	return strconv.Itoa(id + 1)
+}
+
+func (s *Server) evseId(id int) string {
+	return fmt.Sprintf("%s*%s*E%d", s.conf.CountryCode, s.conf.PartyId, id+1)
+}
+
+func (s *Server) evse(id int, lp loadpoint.API, now time.Time) Evse {
+	return Evse{
+		Uid:    evseUid(id),
+		EvseId: s.evseId(id),
+		Status: evseStatus(lp.GetStatus()),
+		Connectors: []Connector{{
+			Id:          "1",
+			Standard:    "IEC_62196_T2",
+			Format:      "SOCKET",
+			PowerType:   powerType(lp),
+			MaxVoltage:  230,
+			MaxAmperage: int(lp.GetMaxCurrent()),
+			LastUpdated: now,
+		}},
+		LastUpdated: now,
+	}
+}
+
+func (s *Server) location() Location {
+	now := time.Now().UTC().Truncate(time.Second)
+	loc := s.conf.Location
+
+	res := Location{
+		CountryCode: s.conf.CountryCode,
+		PartyId:     s.conf.PartyId,
+		Id:          loc.Id,
+		Publish:     true,
+		Name:        loc.Name,
+		Address:     loc.Address,
+		City:        loc.City,
+		PostalCode:  loc.PostalCode,
+		Country:     loc.Country,
+		Coordinates: GeoLocation{Latitude: loc.Latitude, Longitude: loc.Longitude},
+		TimeZone:    time.Local.String(),
+		LastUpdated: now,
+	}
+
+	for id, lp := range s.site.Loadpoints() {
+		res.Evses = append(res.Evses, s.evse(id, lp, now))
+	}
+
+	return res
+}
+
+// loadpoint returns loadpoint and index by evse uid
+func (s *Server) loadpoint(uid string) (loadpoint.API, int, error) {
+	id, err := strconv.Atoi(uid)
+	lps := s.site.Loadpoints()
+
+	if err != nil || id < 1 || id > len(lps) {
+		return nil, 0, errUnknownEvse
+	}
+
+	return lps[id-1], id - 1, nil
+}
+
+// loadpointByTitle returns the loadpoint index of a session
+func (s *Server) loadpointByTitle(title string) (int, bool) {
+	for id, lp := range s.site.Loadpoints() {
+		if lp.GetTitle() == title {
+			return id, true
+		}
+	}
+	return 0, false
+}
+
+func (s *Server) locationsHandler(w http.ResponseWriter, r *http.Request) {
+	writePage(w, r, []Location{s.location()})
+}
+
+func (s *Server) locationHandler(w http.ResponseWriter, r *http.Request) {
+	if mux.Vars(r)["location"] != s.conf.Location.Id {
+		writeError(w, StatusUnknown, errUnknownLocation)
+		return
+	}
+
+	writeData(w, s.location())
+}
+
+func (s *Server) evseHandler(w http.ResponseWriter, r *http.Request) {
+	vars := mux.Vars(r)
+
+	if vars["location"] != s.conf.Location.Id {
+		writeError(w, StatusUnknown, errUnknownLocation)
+		return
+	}
+
+	lp, id, err := s.loadpoint(vars["evse"])
+	if err != nil {
+		writeError(w, StatusUnknown, err)
+		return
+	}
+
+	writeData(w, s.evse(id, lp, time.Now().UTC().Truncate(time.Second)))
+}
+
+// writePage writes the offset/limit page of the result including pagination headers
+func writePage[T any](w http.ResponseWriter, r *http.Request, res []T) {
+	q := r.URL.Query()
+
+	offset, _ := strconv.Atoi(q.Get("offset"))
+	limit, err := strconv.Atoi(q.Get("limit"))
+	if err != nil || limit <= 0 || limit > defaultLimit {
+		limit = defaultLimit
+	}
+
+	total := len(res)
+	offset = min(max(offset, 0), total)
+	end := min(offset+limit, total)
+
+	w.Header().Set("X-Total-Count", strconv.Itoa(total))
+	w.Header().Set("X-Limit", strconv.Itoa(limit))
+
+	if end < total {
+		u := *r.URL
+		q.Set("offset", strconv.Itoa(end))
+		q.Set("limit", strconv.Itoa(limit))
+		u.RawQuery = q.Encode()
+		w.Header().Set("Link", fmt.Sprintf(`<%s>; rel="next"`, u.String()))
+	}
+
+	writeData(w, res[offset:end])
+}
+
+// dateRange parses the optional date_from and date_to filters
+func dateRange(r *http.Request) (time.Time, time.Time, error) {
+	var from, to time.Time
+
+	for _, f := range []struct {
+		key string
+		t   *time.Time
+	}{
+		{"date_from", &from},
+		{"date_to", &to},
+	} {
+		if s := r.URL.Query().Get(f.key); s != "" {
+			t, err := time.Parse(time.RFC3339, s)
+			if err != nil {
+				return from, to, fmt.Errorf("invalid %s: %w", f.key, err)
+			}
+			*f.t = t
+		}
+	}
+
+	return from, to, nil
+}
+
+func lastUpdated(s session.Session) time.Time {
+	if !s.Finished.IsZero() {
+		return s.Finished
+	}
+	return s.Created
+}
+
+// filteredSessions returns sessions last updated within the requested date range
+func (s *Server) filteredSessions(r *http.Request, completed bool) (session.Sessions, error) {
+	from, to, err := dateRange(r)
+	if err != nil {
+		return nil, err
+	}
+
+	all, err := s.sessions()
+	if err != nil {
+		return nil, err
+	}
+
+	var res session.Sessions
+	for _, sess := range all {
+		ts := lastUpdated(sess)
+
+		if completed && sess.Finished.IsZero() ||
+			!from.IsZero() && ts.Before(from) ||
+			!to.IsZero() && !ts.Before(to) {
+			continue
+		}
+
+		res = append(res, sess)
+	}
+
+	return res, nil
+}
+
+func (s *Server) cdrToken(sess session.Session) CdrToken {
+	uid := sess.Identifier
+	typ := "RFID"
+	if uid == "" {
+		uid, typ = "unknown", "OTHER"
+	}
+
+	return CdrToken{
+		CountryCode: s.conf.CountryCode,
+		PartyId:     s.conf.PartyId,
+		Uid:         uid,
+		Type:        typ,
+		ContractId:  uid,
+	}
+}
+
+// session returns the OCPI session, false if the session's loadpoint is unknown
+func (s *Server) session(sess session.Session) (Session, bool) {
+	id, ok := s.loadpointByTitle(sess.Loadpoint)
+	if !ok {
+		return Session{}, false
+	}
+
+	res := Session{
+		CountryCode:   s.conf.CountryCode,
+		PartyId:       s.conf.PartyId,
+		Id:            strconv.FormatUint(uint64(sess.ID), 10),
+		StartDateTime: sess.Created.UTC(),
+		Kwh:           sess.ChargedEnergy,
+		CdrToken:      s.cdrToken(sess),
+		AuthMethod:    "WHITELIST",
+		LocationId:    s.conf.Location.Id,
+		EvseUid:       evseUid(id),
+		ConnectorId:   "1",
+		Currency:      s.conf.Currency,
+		Status:        "ACTIVE",
+		LastUpdated:   lastUpdated(sess).UTC(),
+	}
+
+	if !sess.Finished.IsZero() {
+		end := sess.Finished.UTC()
+		res.EndDateTime = &end
+		res.Status = "COMPLETED"
+	}
+
+	if sess.Price != nil {
+		res.TotalCost = &Price{ExclVat: *sess.Price}
+	}
+
+	return res, true
+}
+
+// cdr returns the OCPI charge detail record, false if the session's loadpoint is unknown
+func (s *Server) cdr(sess session.Session) (Cdr, bool) {
+	id, ok := s.loadpointByTitle(sess.Loadpoint)
+	if !ok {
+		return Cdr{}, false
+	}
+
+	lp := s.site.Loadpoints()[id]
+	loc := s.conf.Location
+
+	res := Cdr{
+		CountryCode:   s.conf.CountryCode,
+		PartyId:       s.conf.PartyId,
+		Id:            strconv.FormatUint(uint64(sess.ID), 10),
+		StartDateTime: sess.Created.UTC(),
+		EndDateTime:   sess.Finished.UTC(),
+		SessionId:     strconv.FormatUint(uint64(sess.ID), 10),
+		CdrToken:      s.cdrToken(sess),
+		AuthMethod:    "WHITELIST",
+		CdrLocation: CdrLocation{
+			Id:                 loc.Id,
+			Name:               loc.Name,
+			Address:            loc.Address,
+			City:               loc.City,
+			PostalCode:         loc.PostalCode,
+			Country:            loc.Country,
+			Coordinates:        GeoLocation{Latitude: loc.Latitude, Longitude: loc.Longitude},
+			EvseUid:            evseUid(id),
+			EvseId:             s.evseId(id),
+			ConnectorId:        "1",
+			ConnectorStandard:  "IEC_62196_T2",
+			ConnectorFormat:    "SOCKET",
+			ConnectorPowerType: powerType(lp),
+		},
+		Currency: s.conf.Currency,
+		ChargingPeriods: []ChargingPeriod{{
+			StartDateTime: sess.Created.UTC(),
+			Dimensions:    []CdrDimension{{Type: "ENERGY", Volume: sess.ChargedEnergy}},
+		}},
+		TotalEnergy: sess.ChargedEnergy,
+		TotalTime:   sess.Finished.Sub(sess.Created).Hours(),
+		LastUpdated: sess.Finished.UTC(),
+	}
+
+	if sess.Price != nil {
+		res.TotalCost.ExclVat = *sess.Price
+	}
+
+	return res, true
+}
+
+func (s *Server) sessionsHandler(w http.ResponseWriter, r *http.Request) {
+	sessions, err := s.filteredSessions(r, false)
+	if err != nil {
+		writeError(w, StatusInvalidParams, err)
+		return
+	}
+
+	res := make([]Session, 0, len(sessions))
+	for _, sess := range sessions {
+		if ocpiSession, ok := s.session(sess); ok {
+			res = append(res, ocpiSession)
+		}
+	}
+
+	writePage(w, r, res)
+}
+
+func (s *Server) cdrsHandler(w http.ResponseWriter, r *http.Request) {
+	sessions, err := s.filteredSessions(r, true)
+	if err != nil {
+		writeError(w, StatusInvalidParams, err)
+		return
+	}
+
+	res := make([]Cdr, 0, len(sessions))
+	for _, sess := range sessions {
+		if cdr, ok := s.cdr(sess); ok {
+			res = append(res, cdr)
+		}
+	}
+
+	writePage(w, r, res)
+}
+
+// commandHandler executes START_SESSION and STOP_SESSION. Charging continues
+// to be controlled by the loadpoint's charge mode.
+func (s *Server) commandHandler(w http.ResponseWriter, r *http.Request) {
+	var (
+		lp          loadpoint.API
+		mode        api.ChargeMode
+		responseUrl string
+	)
+
+	command := mux.Vars(r)["command"]
+
+	switch command {
+	case "START_SESSION":
+		var req StartSession
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			writeError(w, StatusInvalidParams, err)
+			return
+		}
+
+		if req.LocationId != s.conf.Location.Id {
+			writeData(w, CommandResponse{Result: "REJECTED"})
+			return
+		}
+
+		var err error
+		if lp, _, err = s.loadpoint(req.EvseUid); err != nil {
+			writeData(w, CommandResponse{Result: "REJECTED"})
+			return
+		}
+
+		mode, responseUrl = api.ModeNow, req.ResponseUrl
+
+	case "STOP_SESSION":
+		var req StopSession
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			writeError(w, StatusInvalidParams, err)
+			return
+		}
+
+		sess, err := s.activeSession(req.SessionId)
+		if err != nil {
+			writeData(w, CommandResponse{Result: "UNKNOWN_SESSION"})
+			return
+		}
+
+		id, ok := s.loadpointByTitle(sess.Loadpoint)
+		if !ok {
+			writeError(w, StatusUnknown, errUnknownEvse)
+			return
+		}
+
+		lp = s.site.Loadpoints()[id]
+
+		mode, responseUrl = api.ModeOff, req.ResponseUrl
+
+	default:
+		writeData(w, CommandResponse{Result: "NOT_SUPPORTED"})
+		return
+	}
+
+	writeData(w, CommandResponse{Result: "ACCEPTED", Timeout: commandTimeout})
+
+	go func() {
+		result := "ACCEPTED"
+		if lp.GetStatus() == api.StatusA {
+			result = "FAILED"
+		} else {
+			lp.SetMode(mode)
+		}
+
+		var err error
+		if result != "ACCEPTED" {
+			err = errors.New(result)
+		}
+
+		s.mu.Lock()
+		platform := s.reg.RemoteUrl
+		s.mu.Unlock()
+
+		audit.Log(audit.SourceOcpi, platform, command, lp.GetTitle()+": "+mode.String(), err)
+
+		s.sendCommandResult(responseUrl, result)
+	}()
+}
+
+func (s *Server) activeSession(id string) (session.Session, error) {
+	sessions, err := s.sessions()
+	if err != nil {
+		return session.Session{}, err
+	}
+
+	for _, sess := range sessions {
+		if strconv.FormatUint(uint64(sess.ID), 10) == id && sess.Finished.IsZero() {
+			return sess, nil
+		}
+	}
+
+	return session.Session{}, errors.New("unknown session")
+}
+
+// sendCommandResult posts the asynchronous command result to the platform
+func (s *Server) sendCommandResult(url, result string) {
+	if url == "" {
+		return
+	}
+
+	s.mu.Lock()
+	token := s.reg.RemoteToken
+	s.mu.Unlock()
+
+	req, err := request.New(http.MethodPost, url, request.MarshalJSON(CommandResult{Result: result}), request.JSONEncoding, map[string]string{
+		"Authorization": "Token " + token,
+	})
+	if err == nil {
+		var res Response
+		err = s.helper.DoJSON(req, &res)
+	}
+
+	if err != nil {
+		s.log.ERROR.Println("command result:", err)
+	}
+}
@@ -0,0 +1,260 @@
+package ocpi
+
+import (
+	"crypto/rand"
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/evcc-io/evcc/api/globalconfig"
+	"github.com/evcc-io/evcc/core/keys"
+	"github.com/evcc-io/evcc/core/session"
+	"github.com/evcc-io/evcc/core/site"
+	"github.com/evcc-io/evcc/server/db"
+	"github.com/evcc-io/evcc/server/db/settings"
+	"github.com/evcc-io/evcc/util"
+	"github.com/evcc-io/evcc/util/request"
+	"github.com/gorilla/mux"
+)
+
+// Server implements the OCPI 2.2 charge point operator (CPO) interface.
+// Locations, sessions and CDRs are provided as sender, commands are received.
+type Server struct {
+	log      *util.Logger
+	conf     globalconfig.Ocpi
+	site     site.API
+	url      string // base url of the ocpi api
+	helper   *request.Helper
+	sessions func() (session.Sessions, error)
+
+	mu  sync.Mutex
+	reg registration
+}
+
+// New creates the OCPI server. The url is the externally reachable evcc url.
+func New(conf globalconfig.Ocpi, site site.API, url string) (*Server, error) {
+	if conf.Token == "" {
+		return nil, errors.New("missing token")
+	}
+
+	if len(conf.CountryCode) != 2 || len(conf.PartyId) != 3 {
+		return nil, errors.New("invalid country code or party id")
+	}
+
+	if conf.Location.Id == "" {
+		conf.Location.Id = "1"
+	}
+
+	if conf.Currency == "" {
+		conf.Currency = "EUR"
+	}
+
+	log := util.NewLogger("ocpi")
+
+	s := &Server{
+		log:      log,
+		conf:     conf,
+		site:     site,
+		url:      strings.TrimRight(url, "/") + "/ocpi",
+		helper:   request.NewHelper(log),
+		sessions: dbSessions,
+	}
+
+	if settings.Exists(keys.Ocpi) {
+		if err := settings.Json(keys.Ocpi, &s.reg); err != nil {
+			return nil, err
+		}
+	}
+
+	return s, nil
+}
+
+func dbSessions() (session.Sessions, error) {
+	var res session.Sessions
+	if db.Instance == nil {
+		return res, nil
+	}
+
+	err := db.Instance.Order("created").Find(&res).Error
+	return res, err
+}
+
+// Handler returns the http handler serving the /ocpi path
+func (s *Server) Handler() http.Handler {
+	router := mux.NewRouter().StrictSlash(true)
+	router.Use(s.authorize)
+
+	router.Methods(http.MethodGet).Path("/ocpi/versions").HandlerFunc(s.versionsHandler)
+
+	api := router.PathPrefix("/ocpi/" + Version).Subrouter()
+	api.Methods(http.MethodGet).Path("").HandlerFunc(s.versionDetailsHandler)
+	api.Methods(http.MethodGet).Path("/credentials").HandlerFunc(s.getCredentialsHandler)
+	api.Methods(http.MethodPost, http.MethodPut).Path("/credentials").HandlerFunc(s.registerHandler)
+	api.Methods(http.MethodDelete).Path("/credentials").HandlerFunc(s.unregisterHandler)
+	api.Methods(http.MethodGet).Path("/locations").HandlerFunc(s.locationsHandler)
+	api.Methods(http.MethodGet).Path("/locations/{location}").HandlerFunc(s.locationHandler)
+	api.Methods(http.MethodGet).Path("/locations/{location}/{evse}").HandlerFunc(s.evseHandler)
+	api.Methods(http.MethodGet).Path("/sessions").HandlerFunc(s.sessionsHandler)
+	api.Methods(http.MethodGet).Path("/cdrs").HandlerFunc(s.cdrsHandler)
+	api.Methods(http.MethodPost).Path("/commands/{command}").HandlerFunc(s.commandHandler)
+
+	return router
+}
+
+// authorize validates the credentials token. Before registration, token A is accepted.
+func (s *Server) authorize(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		token, ok := strings.CutPrefix(r.Header.Get("Authorization"), "Token ")
+		if !ok || !s.validToken(token) {
+			w.WriteHeader(http.StatusUnauthorized)
+			writeResponse(w, StatusClientError, "unauthorized", nil)
+			return
+		}
+
+		next.ServeHTTP(w, r)
+	})
+}
+
+func (s *Server) validToken(token string) bool {
+	s.mu.Lock()
+	expected := s.reg.Token
+	s.mu.Unlock()
+
+	if expected == "" {
+		expected = s.conf.Token
+	}
+
+	// OCPI 2.2 tokens are base64 encoded
+	if b, err := base64.StdEncoding.DecodeString(token); err == nil && string(b) == expected {
+		return true
+	}
+
+	return token != "" && token == expected
+}
+
+func (s *Server) registered() bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.reg.Token != ""
+}
+
+func writeResponse(w http.ResponseWriter, status int, msg string, data any) {
+	w.Header().Set("Content-Type", "application/json")
+
+	_ = json.NewEncoder(w).Encode(Response{
+		Data:          data,
+		StatusCode:    status,
+		StatusMessage: msg,
+		Timestamp:     time.Now().UTC().Truncate(time.Second),
+	})
+}
+
+func writeData(w http.ResponseWriter, data any) {
+	writeResponse(w, StatusSuccess, "", data)
+}
+
+// writeError writes client errors with http status 200 as required by OCPI, unknown objects as 404
+func writeError(w http.ResponseWriter, status int, err error) {
+	if status == StatusUnknown {
+		w.WriteHeader(http.StatusNotFound)
+	}
+	writeResponse(w, status, err.Error(), nil)
+}
+
+func (s *Server) versionsHandler(w http.ResponseWriter, r *http.Request) {
+	writeData(w, []VersionInfo{{
+		Version: Version,
+		Url:     s.url + "/" + Version,
+	}})
+}
+
+func (s *Server) versionDetailsHandler(w http.ResponseWriter, r *http.Request) {
+	url := s.url + "/" + Version
+
+	writeData(w, VersionDetails{
+		Version: Version,
+		Endpoints: []Endpoint{
+			{Identifier: "credentials", Role: "SENDER", Url: url + "/credentials"},
+			{Identifier: "locations", Role: "SENDER", Url: url + "/locations"},
+			{Identifier: "sessions", Role: "SENDER", Url: url + "/sessions"},
+			{Identifier: "cdrs", Role: "SENDER", Url: url + "/cdrs"},
+			{Identifier: "commands", Role: "RECEIVER", Url: url + "/commands"},
+		},
+	})
+}
+
+func (s *Server) credentials() Credentials {
+	s.mu.Lock()
+	token := s.reg.Token
+	s.mu.Unlock()
+
+	return Credentials{
+		Token: token,
+		Url:   s.url + "/versions",
+		Roles: []CredentialsRole{{
+			Role:            "CPO",
+			PartyId:         s.conf.PartyId,
+			CountryCode:     s.conf.CountryCode,
+			BusinessDetails: BusinessDetails{Name: s.conf.Location.Name},
+		}},
+	}
+}
+
+func (s *Server) getCredentialsHandler(w http.ResponseWriter, r *http.Request) {
+	writeData(w, s.credentials())
+}
+
+// registerHandler exchanges credentials. The platform's token B is stored and a new token C is issued.
+func (s *Server) registerHandler(w http.ResponseWriter, r *http.Request) {
+	if r.Method == http.MethodPost && s.registered() {
+		w.WriteHeader(http.StatusMethodNotAllowed)
+		writeResponse(w, StatusClientError, "already registered", nil)
+		return
+	}
+
+	var req Credentials
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil || req.Token == "" || req.Url == "" {
+		writeError(w, StatusInvalidParams, errors.New("invalid credentials"))
+		return
+	}
+
+	b := make([]byte, 16)
+	if _, err := rand.Read(b); err != nil {
+		writeError(w, StatusServerError, err)
+		return
+	}
+
+	s.mu.Lock()
+	s.reg = registration{
+		Token:       hex.EncodeToString(b),
+		RemoteToken: req.Token,
+		RemoteUrl:   req.Url,
+	}
+	err := settings.SetJson(keys.Ocpi, s.reg)
+	s.mu.Unlock()
+
+	if err != nil {
+		writeError(w, StatusServerError, err)
+		return
+	}
+
+	s.log.INFO.Println("registered:", req.Url)
+
+	writeData(w, s.credentials())
+}
+
+func (s *Server) unregisterHandler(w http.ResponseWriter, r *http.Request) {
+	s.mu.Lock()
+	s.reg = registration{}
+	settings.SetString(keys.Ocpi, "")
+	s.mu.Unlock()
+
+	s.log.INFO.Println("unregistered")
+
+	writeData(w, nil)
+}
@@ -0,0 +1,177 @@
+package ocpi
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/evcc-io/evcc/api"
+	"github.com/evcc-io/evcc/api/globalconfig"
+	"github.com/evcc-io/evcc/core/keys"
+	"github.com/evcc-io/evcc/core/loadpoint"
+	"github.com/evcc-io/evcc/core/session"
+	"github.com/evcc-io/evcc/core/site"
+	"github.com/evcc-io/evcc/server/db/settings"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
+)
+
+type testSite struct {
+	site.API
+	lps []loadpoint.API
+}
+
+func (s *testSite) Loadpoints() []loadpoint.API {
+	return s.lps
+}
+
+func newTestServer(t *testing.T) *Server {
+	ctrl := gomock.NewController(t)
+
+	lp := loadpoint.NewMockAPI(ctrl)
+	lp.EXPECT().GetTitle().Return("Garage").AnyTimes()
+	lp.EXPECT().GetStatus().Return(api.StatusA).AnyTimes()
+	lp.EXPECT().GetMaxCurrent().Return(16.0).AnyTimes()
+	lp.EXPECT().GetPhasesConfigured().Return(3).AnyTimes()
+
+	s, err := New(globalconfig.Ocpi{
+		Token:       "token-a",
+		CountryCode: "DE",
+		PartyId:     "EVC",
+	}, &testSite{lps: []loadpoint.API{lp}}, "https://evcc.example.org/")
+	require.NoError(t, err)
+
+	price := 3.0
+	created := time.Date(2025, 1, 1, 10, 0, 0, 0, time.UTC)
+
+	s.sessions = func() (session.Sessions, error) {
+		return session.Sessions{
+			{ID: 1, Created: created, Finished: created.Add(2 * time.Hour), Loadpoint: "Garage", Identifier: "04AB", ChargedEnergy: 10, Price: &price},
+			{ID: 2, Created: created.Add(24 * time.Hour), Loadpoint: "Garage", ChargedEnergy: 1},
+			{ID: 3, Created: created.Add(24 * time.Hour), Loadpoint: "Carport", ChargedEnergy: 1},
+		}, nil
+	}
+
+	return s
+}
+
+func do(t *testing.T, h http.Handler, method, path, token string, body string) (*httptest.ResponseRecorder, Response) {
+	req := httptest.NewRequest(method, path, strings.NewReader(body))
+	if token != "" {
+		req.Header.Set("Authorization", "Token "+token)
+	}
+
+	w := httptest.NewRecorder()
+	h.ServeHTTP(w, req)
+
+	var res Response
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &res))
+
+	return w, res
+}
+
+func TestAuthorization(t *testing.T) {
+	h := newTestServer(t).Handler()
+
+	w, _ := do(t, h, http.MethodGet, "/ocpi/versions", "", "")
+	assert.Equal(t, http.StatusUnauthorized, w.Code)
+
+	w, res := do(t, h, http.MethodGet, "/ocpi/versions", base64.StdEncoding.EncodeToString([]byte("token-a")), "")
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Equal(t, StatusSuccess, res.StatusCode)
+}
+
+func TestRegistration(t *testing.T) {
+	s := newTestServer(t)
+	h := s.Handler()
+
+	t.Cleanup(func() { settings.SetString(keys.Ocpi, "") })
+
+	_, res := do(t, h, http.MethodPost, "/ocpi/2.2/credentials", "token-a", `{"token":"token-b","url":"https://hub.example.org/versions"}`)
+	require.Equal(t, StatusSuccess, res.StatusCode)
+
+	b, _ := json.Marshal(res.Data)
+	var creds Credentials
+	require.NoError(t, json.Unmarshal(b, &creds))
+
+	assert.NotEmpty(t, creds.Token)
+	assert.Equal(t, "https://evcc.example.org/ocpi/versions", creds.Url)
+	assert.Equal(t, "token-b", s.reg.RemoteToken)
+
+	// token a is replaced by token c
+	w, _ := do(t, h, http.MethodGet, "/ocpi/2.2/locations", "token-a", "")
+	assert.Equal(t, http.StatusUnauthorized, w.Code)
+
+	w, _ = do(t, h, http.MethodGet, "/ocpi/2.2/locations", creds.Token, "")
+	assert.Equal(t, http.StatusOK, w.Code)
+}
+
+func TestLocations(t *testing.T) {
+	h := newTestServer(t).Handler()
+
+	w, res := do(t, h, http.MethodGet, "/ocpi/2.2/locations/1/1", "token-a", "")
+	require.Equal(t, http.StatusOK, w.Code)
+
+	b, _ := json.Marshal(res.Data)
+	var evse Evse
+	require.NoError(t, json.Unmarshal(b, &evse))
+
+	assert.Equal(t, "DE*EVC*E1", evse.EvseId)
+	assert.Equal(t, "AVAILABLE", evse.Status)
+	assert.Equal(t, 16, evse.Connectors[0].MaxAmperage)
+
+	w, res = do(t, h, http.MethodGet, "/ocpi/2.2/locations/1/2", "token-a", "")
+	assert.Equal(t, http.StatusNotFound, w.Code)
+	assert.Equal(t, StatusUnknown, res.StatusCode)
+}
+
+func TestSessionsAndCdrs(t *testing.T) {
+	h := newTestServer(t).Handler()
+
+	w, res := do(t, h, http.MethodGet, "/ocpi/2.2/sessions?limit=1", "token-a", "")
+	assert.Equal(t, "2", w.Header().Get("X-Total-Count"))
+	assert.Contains(t, w.Header().Get("Link"), "offset=1")
+
+	b, _ := json.Marshal(res.Data)
+	var sessions []Session
+	require.NoError(t, json.Unmarshal(b, &sessions))
+	require.Len(t, sessions, 1)
+	assert.Equal(t, "COMPLETED", sessions[0].Status)
+	assert.Equal(t, "04AB", sessions[0].CdrToken.Uid)
+
+	_, res = do(t, h, http.MethodGet, "/ocpi/2.2/cdrs?date_from=2025-01-01T00:00:00Z", "token-a", "")
+
+	b, _ = json.Marshal(res.Data)
+	var cdrs []Cdr
+	require.NoError(t, json.Unmarshal(b, &cdrs))
+	require.Len(t, cdrs, 1)
+	assert.Equal(t, 10.0, cdrs[0].TotalEnergy)
+	assert.Equal(t, 3.0, cdrs[0].TotalCost.ExclVat)
+	assert.Equal(t, 2.0, cdrs[0].TotalTime)
+
+	_, res = do(t, h, http.MethodGet, "/ocpi/2.2/cdrs?date_from=invalid", "token-a", "")
+	assert.Equal(t, StatusInvalidParams, res.StatusCode)
+}
+
+func TestCommands(t *testing.T) {
+	h := newTestServer(t).Handler()
+
+	_, res := do(t, h, http.MethodPost, "/ocpi/2.2/commands/UNLOCK_CONNECTOR", "token-a", `{}`)
+	assert.Equal(t, map[string]any{"result": "NOT_SUPPORTED", "timeout": 0.0}, res.Data)
+
+	_, res = do(t, h, http.MethodPost, "/ocpi/2.2/commands/START_SESSION", "token-a", `{"location_id":"1","evse_uid":"3"}`)
+	assert.Equal(t, "REJECTED", res.Data.(map[string]any)["result"])
+
+	_, res = do(t, h, http.MethodPost, "/ocpi/2.2/commands/STOP_SESSION", "token-a", `{"session_id":"1"}`)
+	assert.Equal(t, "UNKNOWN_SESSION", res.Data.(map[string]any)["result"])
+
+	// session of a removed loadpoint
+	w, res := do(t, h, http.MethodPost, "/ocpi/2.2/commands/STOP_SESSION", "token-a", `{"session_id":"3"}`)
+	assert.Equal(t, http.StatusNotFound, w.Code)
+	assert.Equal(t, StatusUnknown, res.StatusCode)
+}
@@ -0,0 +1,198 @@
+package ocpi
+
+import "time"
+
+// Version is the supported OCPI version
+const Version = "2.2"
+
+// OCPI status codes
+const (
+	StatusSuccess       = 1000
+	StatusClientError   = 2000
+	StatusInvalidParams = 2001
+	StatusUnknown       = 2003
+	StatusServerError   = 3000
+)
+
+// Response is the OCPI response envelope
+type Response struct {
+	Data          any       `json:"data,omitempty"`
+	StatusCode    int       `json:"status_code"`
+	StatusMessage string    `json:"status_message,omitempty"`
+	Timestamp     time.Time `json:"timestamp"`
+}
+
+type VersionInfo struct {
+	Version string `json:"version"`
+	Url     string `json:"url"`
+}
+
+type VersionDetails struct {
+	Version   string     `json:"version"`
+	Endpoints []Endpoint `json:"endpoints"`
+}
+
+type Endpoint struct {
+	Identifier string `json:"identifier"`
+	Role       string `json:"role"` // SENDER, RECEIVER
+	Url        string `json:"url"`
+}
+
+type Credentials struct {
+	Token string            `json:"token"`
+	Url   string            `json:"url"`
+	Roles []CredentialsRole `json:"roles"`
+}
+
+type CredentialsRole struct {
+	Role            string          `json:"role"`
+	BusinessDetails BusinessDetails `json:"business_details"`
+	PartyId         string          `json:"party_id"`
+	CountryCode     string          `json:"country_code"`
+}
+
+type BusinessDetails struct {
+	Name string `json:"name"`
+}
+
+type GeoLocation struct {
+	Latitude  string `json:"latitude"`
+	Longitude string `json:"longitude"`
+}
+
+type Location struct {
+	CountryCode string      `json:"country_code"`
+	PartyId     string      `json:"party_id"`
+	Id          string      `json:"id"`
+	Publish     bool        `json:"publish"`
+	Name        string      `json:"name,omitempty"`
+	Address     string      `json:"address"`
+	City        string      `json:"city"`
+	PostalCode  string      `json:"postal_code,omitempty"`
+	Country     string      `json:"country"`
+	Coordinates GeoLocation `json:"coordinates"`
+	Evses       []Evse      `json:"evses"`
+	TimeZone    string      `json:"time_zone"`
+	LastUpdated time.Time   `json:"last_updated"`
+}
+
+type Evse struct {
+	Uid         string      `json:"uid"`
+	EvseId      string      `json:"evse_id,omitempty"`
+	Status      string      `json:"status"`
+	Connectors  []Connector `json:"connectors"`
+	LastUpdated time.Time   `json:"last_updated"`
+}
+
+type Connector struct {
+	Id          string    `json:"id"`
+	Standard    string    `json:"standard"`
+	Format      string    `json:"format"`
+	PowerType   string    `json:"power_type"`
+	MaxVoltage  int       `json:"max_voltage"`
+	MaxAmperage int       `json:"max_amperage"`
+	LastUpdated time.Time `json:"last_updated"`
+}
+
+type CdrToken struct {
+	CountryCode string `json:"country_code"`
+	PartyId     string `json:"party_id"`
+	Uid         string `json:"uid"`
+	Type        string `json:"type"`
+	ContractId  string `json:"contract_id"`
+}
+
+type Price struct {
+	ExclVat float64 `json:"excl_vat"`
+}
+
+type Session struct {
+	CountryCode   string     `json:"country_code"`
+	PartyId       string     `json:"party_id"`
+	Id            string     `json:"id"`
+	StartDateTime time.Time  `json:"start_date_time"`
+	EndDateTime   *time.Time `json:"end_date_time,omitempty"`
+	Kwh           float64    `json:"kwh"`
+	CdrToken      CdrToken   `json:"cdr_token"`
+	AuthMethod    string     `json:"auth_method"`
+	LocationId    string     `json:"location_id"`
+	EvseUid       string     `json:"evse_uid"`
+	ConnectorId   string     `json:"connector_id"`
+	Currency      string     `json:"currency"`
+	TotalCost     *Price     `json:"total_cost,omitempty"`
+	Status        string     `json:"status"`
+	LastUpdated   time.Time  `json:"last_updated"`
+}
+
+type CdrLocation struct {
+	Id                 string      `json:"id"`
+	Name               string      `json:"name,omitempty"`
+	Address            string      `json:"address"`
+	City               string      `json:"city"`
+	PostalCode         string      `json:"postal_code,omitempty"`
+	Country            string      `json:"country"`
+	Coordinates        GeoLocation `json:"coordinates"`
+	EvseUid            string      `json:"evse_uid"`
+	EvseId             string      `json:"evse_id"`
+	ConnectorId        string      `json:"connector_id"`
+	ConnectorStandard  string      `json:"connector_standard"`
+	ConnectorFormat    string      `json:"connector_format"`
+	ConnectorPowerType string      `json:"connector_power_type"`
+}
+
+type CdrDimension struct {
+	Type   string  `json:"type"`
+	Volume float64 `json:"volume"`
+}
+
+type ChargingPeriod struct {
+	StartDateTime time.Time      `json:"start_date_time"`
+	Dimensions    []CdrDimension `json:"dimensions"`
+}
+
+type Cdr struct {
+	CountryCode     string           `json:"country_code"`
+	PartyId         string           `json:"party_id"`
+	Id              string           `json:"id"`
+	StartDateTime   time.Time        `json:"start_date_time"`
+	EndDateTime     time.Time        `json:"end_date_time"`
+	SessionId       string           `json:"session_id"`
+	CdrToken        CdrToken         `json:"cdr_token"`
+	AuthMethod      string           `json:"auth_method"`
+	CdrLocation     CdrLocation      `json:"cdr_location"`
+	Currency        string           `json:"currency"`
+	ChargingPeriods []ChargingPeriod `json:"charging_periods"`
+	TotalCost       Price            `json:"total_cost"`
+	TotalEnergy     float64          `json:"total_energy"`
+	TotalTime       float64          `json:"total_time"` // hours
+	LastUpdated     time.Time        `json:"last_updated"`
+}
+
+type StartSession struct {
+	ResponseUrl string `json:"response_url"`
+	LocationId  string `json:"location_id"`
+	EvseUid     string `json:"evse_uid,omitempty"`
+}
+
+type StopSession struct {
+	ResponseUrl string `json:"response_url"`
+	SessionId   string `json:"session_id"`
+}
+
+// CommandResponse is the synchronous command response
+type CommandResponse struct {
+	Result  string `json:"result"` // ACCEPTED, NOT_SUPPORTED, REJECTED, UNKNOWN_SESSION
+	Timeout int    `json:"timeout"`
+}
+
+// CommandResult is the asynchronous command result sent to the response url
+type CommandResult struct {
+	Result string `json:"result"` // ACCEPTED, FAILED, EVSE_OCCUPIED, ...
+}
+
+// registration is the persisted credentials exchange
+type registration struct {
+	Token       string `json:"token"`       // token C, used by the platform
+	RemoteToken string `json:"remoteToken"` // token B, used for calls to the platform
+	RemoteUrl   string `json:"remoteUrl"`
+}
@@ -0,0 +1,218 @@
+package remote
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/coder/websocket"
+	"github.com/evcc-io/evcc/util"
+	"github.com/evcc-io/evcc/util/request"
+)
+
+const (
+	// maxMessageSize limits relayed requests, large enough for backup archives
+	maxMessageSize = 64 << 20
+
+	// maxConcurrent limits concurrently handled requests, further requests are rejected
+	maxConcurrent = 4
+
+	retryDelay = 30 * time.Second
+)
+
+// Tunnel keeps an outbound websocket connection to a relay and serves relayed
+// http requests using the local handler. Requests are not trusted by the relay,
+// the handler is expected to require authentication for every request.
+//
+// Each websocket message contains a request id followed by a newline and the
+// raw http/1.1 request. Responses are returned using the same framing.
+type Tunnel struct {
+	log     *util.Logger
+	url     string
+	token   string
+	handler http.Handler
+}
+
+// New creates a relay tunnel
+func New(url, token string, handler http.Handler) (*Tunnel, error) {
+	if !strings.HasPrefix(url, "wss://") && !strings.HasPrefix(url, "ws://") {
+		return nil, fmt.Errorf("invalid relay url: %s", url)
+	}
+
+	if token == "" {
+		return nil, errors.New("missing relay token")
+	}
+
+	t := &Tunnel{
+		log:     util.NewLogger("remote"),
+		url:     url,
+		token:   token,
+		handler: handler,
+	}
+
+	return t, nil
+}
+
+// Run connects to the relay and reconnects until the context is cancelled
+func (t *Tunnel) Run(ctx context.Context) {
+	for {
+		if err := t.serve(ctx); err != nil && ctx.Err() == nil {
+			t.log.ERROR.Println(err)
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-time.After(retryDelay):
+		}
+	}
+}
+
+func (t *Tunnel) serve(ctx context.Context) error {
+	dialCtx, cancel := context.WithTimeout(ctx, request.Timeout)
+	conn, _, err := websocket.Dial(dialCtx, t.url, &websocket.DialOptions{
+		HTTPHeader: http.Header{"Authorization": {"Bearer " + t.token}},
+	})
+	cancel()
+
+	if err != nil {
+		return err
+	}
+	defer conn.CloseNow()
+
+	conn.SetReadLimit(maxMessageSize)
+
+	t.log.INFO.Println("connected to relay")
+
+	sem := make(chan struct{}, maxConcurrent)
+
+	for {
+		typ, b, err := conn.Read(ctx)
+		if err != nil {
+			return err
+		}
+
+		if typ != websocket.MessageBinary {
+			continue
+		}
+
+		select {
+		case sem <- struct{}{}:
+		default:
+			t.reject(ctx, conn, b)
+			continue
+		}
+
+		go func() {
+			defer func() { <-sem }()
+
+			id, res := t.handle(ctx, b)
+			if id == "" {
+				return
+			}
+
+			if err := conn.Write(ctx, websocket.MessageBinary, frame(id, res)); err != nil {
+				t.log.DEBUG.Println("write:", err)
+			}
+		}()
+	}
+}
+
+// reject answers the framed request as unavailable without handling it
+func (t *Tunnel) reject(ctx context.Context, conn *websocket.Conn, b []byte) {
+	id, _, ok := bytes.Cut(b, []byte("\n"))
+	if !ok || len(id) == 0 {
+		return
+	}
+
+	t.log.DEBUG.Println("too many requests, rejected")
+
+	if err := conn.Write(ctx, websocket.MessageBinary, frame(string(id), response(http.StatusServiceUnavailable, nil, nil))); err != nil {
+		t.log.DEBUG.Println("write:", err)
+	}
+}
+
+// frame prefixes the payload with the request id
+func frame(id string, payload []byte) []byte {
+	return append([]byte(id+"\n"), payload...)
+}
+
+// handle executes the framed request and returns id and raw response
+func (t *Tunnel) handle(ctx context.Context, b []byte) (string, []byte) {
+	id, payload, ok := bytes.Cut(b, []byte("\n"))
+	if !ok || len(id) == 0 {
+		t.log.DEBUG.Println("invalid frame")
+		return "", nil
+	}
+
+	req, err := http.ReadRequest(bufio.NewReader(bytes.NewReader(payload)))
+	if err != nil {
+		return string(id), response(http.StatusBadRequest, nil, []byte(err.Error()))
+	}
+
+	// streaming connections cannot be relayed
+	if req.Header.Get("Upgrade") != "" {
+		return string(id), response(http.StatusNotImplemented, nil, nil)
+	}
+
+	req.RemoteAddr = "relay"
+	t.log.TRACE.Println(req.Method, req.URL)
+
+	rw := &responseWriter{header: make(http.Header)}
+	t.handler.ServeHTTP(rw, req.WithContext(ctx))
+
+	return string(id), response(rw.status, rw.header, rw.body.Bytes())
+}
+
+// response serializes the http response
+func response(status int, header http.Header, body []byte) []byte {
+	if status == 0 {
+		status = http.StatusOK
+	}
+
+	if header == nil {
+		header = make(http.Header)
+	}
+
+	res := &http.Response{
+		StatusCode:    status,
+		ProtoMajor:    1,
+		ProtoMinor:    1,
+		Header:        header,
+		ContentLength: int64(len(body)),
+		Body:          io.NopCloser(bytes.NewReader(body)),
+	}
+
+	var buf bytes.Buffer
+	_ = res.Write(&buf)
+
+	return buf.Bytes()
+}
+
+// responseWriter buffers the handler response
+type responseWriter struct {
+	header http.Header
+	status int
+	body   bytes.Buffer
+}
+
+func (w *responseWriter) Header() http.Header {
+	return w.header
+}
+
+func (w *responseWriter) WriteHeader(status int) {
+	if w.status == 0 {
+		w.status = status
+	}
+}
+
+func (w *responseWriter) Write(b []byte) (int, error) {
+	w.WriteHeader(http.StatusOK)
+	return w.body.Write(b)
+}
@@ -0,0 +1,155 @@
+package remote
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	"testing"
+
+	"github.com/coder/websocket"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestTunnel(t *testing.T) {
+	handler := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "relay", r.RemoteAddr)
+		w.Header().Set("Content-Type", "text/plain")
+		w.WriteHeader(http.StatusCreated)
+		_, _ = w.Write([]byte(r.Method + " " + r.URL.Path))
+	})
+
+	resC := make(chan []byte, 1)
+
+	relay := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "Bearer secret", r.Header.Get("Authorization"))
+
+		conn, err := websocket.Accept(w, r, nil)
+		require.NoError(t, err)
+		defer conn.CloseNow()
+
+		req, _ := http.NewRequest(http.MethodPost, "/api/state", strings.NewReader("{}"))
+
+		var buf bytes.Buffer
+		require.NoError(t, req.Write(&buf))
+		require.NoError(t, conn.Write(r.Context(), websocket.MessageBinary, frame("42", buf.Bytes())))
+
+		_, b, err := conn.Read(r.Context())
+		require.NoError(t, err)
+		resC <- b
+	}))
+	defer relay.Close()
+
+	tunnel, err := New("ws"+strings.TrimPrefix(relay.URL, "http"), "secret", handler)
+	require.NoError(t, err)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	go tunnel.Run(ctx)
+
+	id, payload, ok := bytes.Cut(<-resC, []byte("\n"))
+	require.True(t, ok)
+	assert.Equal(t, "42", string(id))
+
+	res, err := http.ReadResponse(bufio.NewReader(bytes.NewReader(payload)), nil)
+	require.NoError(t, err)
+	assert.Equal(t, http.StatusCreated, res.StatusCode)
+	assert.Equal(t, "text/plain", res.Header.Get("Content-Type"))
+
+	body, err := io.ReadAll(res.Body)
+	require.NoError(t, err)
+	assert.Equal(t, "POST /api/state", string(body))
+}
+
+func TestTunnelUpgrade(t *testing.T) {
+	tunnel, err := New("wss://relay", "secret", http.NotFoundHandler())
+	require.NoError(t, err)
+
+	req, _ := http.NewRequest(http.MethodGet, "/ws", nil)
+	req.Header.Set("Upgrade", "websocket")
+
+	var buf bytes.Buffer
+	require.NoError(t, req.Write(&buf))
+
+	id, b := tunnel.handle(context.Background(), frame("1", buf.Bytes()))
+	assert.Equal(t, "1", id)
+
+	res, err := http.ReadResponse(bufio.NewReader(bytes.NewReader(b)), nil)
+	require.NoError(t, err)
+	assert.Equal(t, http.StatusNotImplemented, res.StatusCode)
+}
+
+func TestTunnelConfig(t *testing.T) {
+	_, err := New("https://relay", "secret", nil)
+	assert.Error(t, err)
+
+	_, err = New("wss://relay", "", nil)
+	assert.Error(t, err)
+}
+
+func TestTunnelConcurrency(t *testing.T) {
+	release := make(chan struct{})
+	handler := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		<-release
+	})
+
+	resC := make(chan []byte, maxConcurrent+1)
+
+	relay := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		conn, err := websocket.Accept(w, r, nil)
+		require.NoError(t, err)
+		defer conn.CloseNow()
+
+		req, _ := http.NewRequest(http.MethodGet, "/api/state", nil)
+
+		var buf bytes.Buffer
+		require.NoError(t, req.Write(&buf))
+
+		for i := range maxConcurrent + 1 {
+			require.NoError(t, conn.Write(r.Context(), websocket.MessageBinary, frame(strconv.Itoa(i), buf.Bytes())))
+		}
+
+		for range maxConcurrent + 1 {
+			_, b, err := conn.Read(r.Context())
+			if err != nil {
+				return
+			}
+			resC <- b
+		}
+	}))
+	defer relay.Close()
+
+	tunnel, err := New("ws"+strings.TrimPrefix(relay.URL, "http"), "secret", handler)
+	require.NoError(t, err)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	go tunnel.Run(ctx)
+
+	// request exceeding the limit is rejected while the others are handled
+	id, payload, ok := bytes.Cut(<-resC, []byte("\n"))
+	require.True(t, ok)
+	assert.Equal(t, strconv.Itoa(maxConcurrent), string(id))
+
+	res, err := http.ReadResponse(bufio.NewReader(bytes.NewReader(payload)), nil)
+	require.NoError(t, err)
+	assert.Equal(t, http.StatusServiceUnavailable, res.StatusCode)
+
+	close(release)
+
+	for range maxConcurrent {
+		_, payload, ok := bytes.Cut(<-resC, []byte("\n"))
+		require.True(t, ok)
+
+		res, err := http.ReadResponse(bufio.NewReader(bytes.NewReader(payload)), nil)
+		require.NoError(t, err)
+		assert.Equal(t, http.StatusOK, res.StatusCode)
+	}
+}
@@ -0,0 +1,257 @@
+package rpc
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"slices"
+	"strings"
+	"sync"
+
+	"github.com/evcc-io/evcc/api"
+	"github.com/evcc-io/evcc/api/proto/pb"
+	"github.com/evcc-io/evcc/core/loadpoint"
+	"github.com/evcc-io/evcc/core/site"
+	"github.com/evcc-io/evcc/server/db/audit"
+	"github.com/evcc-io/evcc/util"
+	"github.com/evcc-io/evcc/util/auth"
+	"github.com/evcc-io/evcc/util/encode"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+)
+
+// subscriberBuffer is the number of updates queued per subscriber before it is dropped as too slow
+const subscriberBuffer = 256
+
+var enc = encode.NewEncoder(encode.WithDuration())
+
+type subscriber struct {
+	req  *pb.SubscribeRequest
+	send chan *pb.Update
+}
+
+// Server implements the Site grpc service
+type Server struct {
+	pb.UnimplementedSiteServer
+	log   *util.Logger
+	site  site.API
+	auth  auth.Auth
+	cache *util.ParamCache
+
+	mu          sync.Mutex
+	subscribers map[*subscriber]struct{}
+}
+
+// NewServer creates the Site grpc service
+func NewServer(site site.API, cache *util.ParamCache, auth auth.Auth) *Server {
+	return &Server{
+		log:         util.NewLogger("grpc"),
+		site:        site,
+		auth:        auth,
+		cache:       cache,
+		subscribers: make(map[*subscriber]struct{}),
+	}
+}
+
+// Listen starts serving the Site service on the given port
+func (s *Server) Listen(port int) error {
+	ln, err := util.Listen(port)
+	if err != nil {
+		return err
+	}
+
+	srv := grpc.NewServer(grpc.UnaryInterceptor(s.authorize))
+	pb.RegisterSiteServer(srv, s)
+
+	go func() {
+		if err := srv.Serve(ln); err != nil {
+			s.log.ERROR.Println(err)
+		}
+	}()
+
+	s.log.INFO.Printf("listening at %s", strings.Join(util.ListenAddrs(port), ", "))
+
+	return nil
+}
+
+// Run distributes site updates to subscribers
+func (s *Server) Run(in <-chan util.Param) {
+	for p := range in {
+		u, err := update(p)
+		if err != nil {
+			continue
+		}
+
+		s.mu.Lock()
+		for sub := range s.subscribers {
+			if !matches(sub.req, u) {
+				continue
+			}
+
+			select {
+			case sub.send <- u:
+			default:
+				// drop slow subscriber
+				delete(s.subscribers, sub)
+				close(sub.send)
+			}
+		}
+		s.mu.Unlock()
+	}
+}
+
+// authorize restricts control rpcs to operators once user accounts have been created.
+// Control rpcs are recorded in the audit log.
+func (s *Server) authorize(ctx context.Context, req any, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (any, error) {
+	var token string
+	if md, ok := metadata.FromIncomingContext(ctx); ok {
+		if v := md.Get("authorization"); len(v) > 0 {
+			token = strings.TrimPrefix(v[0], "Bearer ")
+		}
+	}
+
+	user, role, err := s.auth.ValidateUserJwtToken(token)
+
+	if s.auth.GetAuthMode() != auth.Disabled && s.auth.HasUsers() {
+		if err != nil {
+			return nil, status.Error(codes.Unauthenticated, "unauthorized")
+		}
+
+		if !role.Includes(auth.RoleOperator) {
+			return nil, status.Error(codes.PermissionDenied, "forbidden")
+		}
+	}
+
+	res, err := handler(ctx, req)
+	audit.Log(audit.SourceGrpc, user, info.FullMethod, fmt.Sprint(req), err)
+
+	return res, err
+}
+
+// Subscribe streams the current state followed by all changes
+func (s *Server) Subscribe(req *pb.SubscribeRequest, stream pb.Site_SubscribeServer) error {
+	sub := &subscriber{
+		req:  req,
+		send: make(chan *pb.Update, subscriberBuffer),
+	}
+
+	s.mu.Lock()
+	s.subscribers[sub] = struct{}{}
+	s.mu.Unlock()
+
+	defer func() {
+		s.mu.Lock()
+		if _, ok := s.subscribers[sub]; ok {
+			delete(s.subscribers, sub)
+			close(sub.send)
+		}
+		s.mu.Unlock()
+	}()
+
+	for _, p := range s.cache.All() {
+		if u, err := update(p); err == nil && matches(req, u) {
+			if err := stream.Send(u); err != nil {
+				return err
+			}
+		}
+	}
+
+	for {
+		select {
+		case <-stream.Context().Done():
+			return nil
+		case u, ok := <-sub.send:
+			if !ok {
+				return status.Error(codes.ResourceExhausted, "subscriber too slow")
+			}
+			if err := stream.Send(u); err != nil {
+				return err
+			}
+		}
+	}
+}
+
+func (s *Server) loadpoint(id int32) (loadpoint.API, error) {
+	lps := s.site.Loadpoints()
+	if id < 1 || int(id) > len(lps) {
+		return nil, status.Errorf(codes.NotFound, "loadpoint not found: %d", id)
+	}
+	return lps[id-1], nil
+}
+
+// SetMode sets the loadpoint charge mode
+func (s *Server) SetMode(ctx context.Context, req *pb.ModeRequest) (*pb.ControlReply, error) {
+	lp, err := s.loadpoint(req.Loadpoint)
+	if err != nil {
+		return nil, err
+	}
+
+	mode, err := api.ChargeModeString(req.Mode)
+	if err != nil {
+		return nil, status.Error(codes.InvalidArgument, err.Error())
+	}
+
+	lp.SetMode(mode)
+
+	return new(pb.ControlReply), nil
+}
+
+// SetLimitSoc sets the loadpoint session limit soc
+func (s *Server) SetLimitSoc(ctx context.Context, req *pb.LimitSocRequest) (*pb.ControlReply, error) {
+	lp, err := s.loadpoint(req.Loadpoint)
+	if err != nil {
+		return nil, err
+	}
+
+	lp.SetLimitSoc(int(req.Soc))
+
+	return new(pb.ControlReply), nil
+}
+
+// SetLimitEnergy sets the loadpoint session limit energy
+func (s *Server) SetLimitEnergy(ctx context.Context, req *pb.LimitEnergyRequest) (*pb.ControlReply, error) {
+	lp, err := s.loadpoint(req.Loadpoint)
+	if err != nil {
+		return nil, err
+	}
+
+	lp.SetLimitEnergy(req.Energy)
+
+	return new(pb.ControlReply), nil
+}
+
+// SetBufferSoc sets the site battery buffer soc
+func (s *Server) SetBufferSoc(ctx context.Context, req *pb.SocRequest) (*pb.ControlReply, error) {
+	if err := s.site.SetBufferSoc(req.Soc); err != nil {
+		return nil, status.Error(codes.InvalidArgument, err.Error())
+	}
+
+	return new(pb.ControlReply), nil
+}
+
+func update(p util.Param) (*pb.Update, error) {
+	b, err := json.Marshal(enc.Encode(p.Val))
+	if err != nil {
+		return nil, err
+	}
+
+	u := &pb.Update{
+		Key:   p.Key,
+		Value: string(b),
+	}
+
+	if p.Loadpoint != nil {
+		u.Loadpoint = int32(*p.Loadpoint + 1)
+	}
+
+	return u, nil
+}
+
+func matches(req *pb.SubscribeRequest, u *pb.Update) bool {
+	if len(req.Keys) > 0 && !slices.Contains(req.Keys, u.Key) {
+		return false
+	}
+	return len(req.Loadpoints) == 0 || slices.Contains(req.Loadpoints, u.Loadpoint)
+}
@@ -0,0 +1,32 @@
+package rpc
+
+import (
+	"testing"
+
+	"github.com/evcc-io/evcc/api/proto/pb"
+	"github.com/evcc-io/evcc/util"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestUpdate(t *testing.T) {
+	id := 0
+	u, err := update(util.Param{Loadpoint: &id, Key: "chargePower", Val: 1000.0})
+	require.NoError(t, err)
+	assert.Equal(t, int32(1), u.Loadpoint)
+	assert.Equal(t, "1000", u.Value)
+
+	u, err = update(util.Param{Key: "gridPower", Val: -500.0})
+	require.NoError(t, err)
+	assert.Equal(t, int32(0), u.Loadpoint)
+}
+
+func TestMatches(t *testing.T) {
+	u := &pb.Update{Loadpoint: 2, Key: "chargePower"}
+
+	assert.True(t, matches(&pb.SubscribeRequest{}, u))
+	assert.True(t, matches(&pb.SubscribeRequest{Keys: []string{"chargePower"}}, u))
+	assert.False(t, matches(&pb.SubscribeRequest{Keys: []string{"gridPower"}}, u))
+	assert.True(t, matches(&pb.SubscribeRequest{Loadpoints: []int32{2}}, u))
+	assert.False(t, matches(&pb.SubscribeRequest{Loadpoints: []int32{1}}, u))
+}
@@ -21,6 +21,32 @@ const (
 type socketSubscriber struct {
 	send      chan []byte
 	closeSlow func()
+	filter    socketFilter
+	last      map[string]string // last sent values for delta updates
+}
+
+// message filters the encoded values and returns the message or nil if nothing is to be sent.
+// Must only be called from the hub's Run loop.
+func (s *socketSubscriber) message(values map[string]string) []byte {
+	msg := make(map[string]json.RawMessage, len(values))
+
+	for k, v := range values {
+		if s.filter.delta {
+			if prev, ok := s.last[k]; ok && prev == v {
+				continue
+			}
+			s.last[k] = v
+		}
+
+		msg[k] = json.RawMessage(v)
+	}
+
+	if len(msg) == 0 {
+		return nil
+	}
+
+	b, _ := json.Marshal(msg)
+	return b
 }
 
 func writeTimeout(ctx context.Context, timeout time.Duration, c *websocket.Conn, msg []byte) error {
@@ -46,29 +72,40 @@ func NewSocketHub() *SocketHub {
 	}
 }
 
-// ServeWebsocket handles websocket requests from the peer.
-func (h *SocketHub) ServeWebsocket(w http.ResponseWriter, r *http.Request) {
-	acceptOptions := &websocket.AcceptOptions{
+// socketAcceptOptions returns the websocket options for the requesting browser
+func socketAcceptOptions(r *http.Request) *websocket.AcceptOptions {
+	res := &websocket.AcceptOptions{
 		InsecureSkipVerify: true,
 	}
 
 	// https://github.com/nhooyr/websocket/issues/218
 	ua := strings.ToLower(r.Header.Get("User-Agent"))
 	if strings.Contains(ua, "safari") && !strings.Contains(ua, "chrome") && !strings.Contains(ua, "android") {
-		acceptOptions.CompressionMode = websocket.CompressionDisabled
+		res.CompressionMode = websocket.CompressionDisabled
+	}
+
+	return res
+}
+
+// ServeWebsocket handles websocket requests from the peer.
+func (h *SocketHub) ServeWebsocket(w http.ResponseWriter, r *http.Request) {
+	filter, err := parseSocketFilter(r.URL.Query())
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
 	}
 
-	conn, err := websocket.Accept(w, r, acceptOptions)
+	conn, err := websocket.Accept(w, r, socketAcceptOptions(r))
 	if err != nil {
 		log.ERROR.Println(err)
 		return
 	}
 	defer conn.Close(websocket.StatusInternalError, "")
 
-	_ = h.subscribe(r.Context(), conn)
+	_ = h.subscribe(r.Context(), conn, filter)
 }
 
-func (h *SocketHub) subscribe(ctx context.Context, conn *websocket.Conn) error {
+func (h *SocketHub) subscribe(ctx context.Context, conn *websocket.Conn, filter socketFilter) error {
 	ctx = conn.CloseRead(ctx)
 
 	s := &socketSubscriber{
@@ -76,6 +113,8 @@ func (h *SocketHub) subscribe(ctx context.Context, conn *websocket.Conn) error {
 		closeSlow: func() {
 			conn.Close(websocket.StatusPolicyViolation, "connection too slow to keep up with messages")
 		},
+		filter: filter,
+		last:   make(map[string]string),
 	}
 
 	h.addSubscriber(s)
@@ -111,18 +150,25 @@ func (h *SocketHub) deleteSubscriber(s *socketSubscriber) {
 }
 
 func (h *SocketHub) welcome(subscriber *socketSubscriber, params []util.Param) {
-	msg := make(map[string]json.RawMessage, len(params))
+	values := make(map[string]string, len(params))
 
 	for _, p := range params {
+		if !subscriber.filter.matches(p) {
+			continue
+		}
+
 		k := p.Key
 		if p.Loadpoint != nil {
 			k = "loadpoints." + p.UniqueID()
 		}
 
-		msg[k] = json.RawMessage(socketEncode(p.Val))
+		values[k] = socketEncode(p.Val)
 	}
 
-	b, _ := json.Marshal(msg)
+	b := subscriber.message(values)
+	if b == nil {
+		b = []byte("{}")
+	}
 
 	// should not block
 	subscriber.send <- b
@@ -136,7 +182,7 @@ func (h *SocketHub) broadcast(p util.Param) {
 		return
 	}
 
-	msg := make(map[string]json.RawMessage)
+	values := make(map[string]string)
 
 	k := p.Key
 	if p.Loadpoint != nil {
@@ -151,15 +197,32 @@ func (h *SocketHub) broadcast(p util.Param) {
 		}
 
 		for _, shard := range shards {
-			msg[k+"."+shard.Key] = json.RawMessage(socketEncode(shard.Value))
+			values[k+"."+shard.Key] = socketEncode(shard.Value)
 		}
 	} else {
-		msg[k] = json.RawMessage(socketEncode(p.Val))
+		values[k] = socketEncode(p.Val)
 	}
 
-	b, _ := json.Marshal(msg)
+	// shared message for unfiltered subscribers
+	var all []byte
 
 	for s := range h.subscribers {
+		var b []byte
+
+		switch {
+		case s.filter.empty():
+			if all == nil {
+				all = s.message(values)
+			}
+			b = all
+		case s.filter.matches(p):
+			b = s.message(values)
+		}
+
+		if b == nil {
+			continue
+		}
+
 		select {
 		case s.send <- b:
 		default:
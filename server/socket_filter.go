@@ -0,0 +1,76 @@
+package server
+
+import (
+	"fmt"
+	"net/url"
+	"slices"
+	"strconv"
+	"strings"
+
+	"github.com/evcc-io/evcc/util"
+)
+
+// socketFilter restricts the messages sent to a websocket subscriber
+type socketFilter struct {
+	topics     []string // keys, all if empty
+	loadpoints []int    // 1-based loadpoint ids, 0 is site, all if empty
+	delta      bool     // only send changed values
+}
+
+// parseSocketFilter parses the subscription from the request query
+//
+//	/ws?topics=chargePower,gridPower&loadpoints=0,1&delta=true
+func parseSocketFilter(q url.Values) (socketFilter, error) {
+	var res socketFilter
+
+	for _, topic := range strings.Split(q.Get("topics"), ",") {
+		if topic = strings.TrimSpace(topic); topic != "" {
+			res.topics = append(res.topics, topic)
+		}
+	}
+
+	for _, s := range strings.Split(q.Get("loadpoints"), ",") {
+		if s = strings.TrimSpace(s); s == "" {
+			continue
+		}
+
+		id, err := strconv.Atoi(s)
+		if err != nil || id < 0 {
+			return res, fmt.Errorf("invalid loadpoint: %s", s)
+		}
+
+		res.loadpoints = append(res.loadpoints, id)
+	}
+
+	if s := q.Get("delta"); s != "" {
+		var err error
+		if res.delta, err = strconv.ParseBool(s); err != nil {
+			return res, fmt.Errorf("invalid delta: %s", s)
+		}
+	}
+
+	return res, nil
+}
+
+// empty returns true if all messages are sent unmodified
+func (f socketFilter) empty() bool {
+	return len(f.topics) == 0 && len(f.loadpoints) == 0 && !f.delta
+}
+
+// matches returns true if the param is subscribed
+func (f socketFilter) matches(p util.Param) bool {
+	if len(f.topics) > 0 && !slices.Contains(f.topics, p.Key) {
+		return false
+	}
+
+	if len(f.loadpoints) == 0 {
+		return true
+	}
+
+	var id int
+	if p.Loadpoint != nil {
+		id = *p.Loadpoint + 1
+	}
+
+	return slices.Contains(f.loadpoints, id)
+}
@@ -1,10 +1,16 @@
 package server
 
 import (
+	"bufio"
 	"math"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
 	"testing"
 	"time"
 
+	"github.com/evcc-io/evcc/util"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 )
@@ -49,3 +55,69 @@ func TestEncodeSlice(t *testing.T) {
 		assert.Equal(t, tc.out, out)
 	}
 }
+
+func TestSocketFilter(t *testing.T) {
+	f, err := parseSocketFilter(url.Values{"topics": {"chargePower,gridPower"}, "loadpoints": {"0,2"}})
+	require.NoError(t, err)
+
+	lp := func(id int) *int { return &id }
+
+	assert.True(t, f.matches(util.Param{Key: "gridPower"}))
+	assert.False(t, f.matches(util.Param{Key: "pvPower"}))
+	assert.True(t, f.matches(util.Param{Key: "chargePower", Loadpoint: lp(1)}))
+	assert.False(t, f.matches(util.Param{Key: "chargePower", Loadpoint: lp(0)}))
+
+	_, err = parseSocketFilter(url.Values{"loadpoints": {"x"}})
+	assert.Error(t, err)
+
+	f, err = parseSocketFilter(url.Values{})
+	require.NoError(t, err)
+	assert.True(t, f.empty())
+}
+
+func TestSocketDelta(t *testing.T) {
+	s := &socketSubscriber{
+		filter: socketFilter{delta: true},
+		last:   make(map[string]string),
+	}
+
+	assert.JSONEq(t, `{"a":1,"b":2}`, string(s.message(map[string]string{"a": "1", "b": "2"})))
+	assert.JSONEq(t, `{"b":3}`, string(s.message(map[string]string{"a": "1", "b": "3"})))
+	assert.Nil(t, s.message(map[string]string{"a": "1"}))
+}
+
+func TestEvents(t *testing.T) {
+	hub := NewSocketHub()
+	cache := util.NewParamCache()
+	cache.Add("pvPower", util.Param{Key: "pvPower", Val: 1000.0})
+
+	in := make(chan util.Param)
+	defer close(in)
+	go hub.Run(in, cache)
+
+	srv := httptest.NewServer(http.HandlerFunc(hub.ServeEvents))
+	defer srv.Close()
+
+	resp, err := http.Get(srv.URL + "?topics=pvPower,gridPower")
+	require.NoError(t, err)
+	defer resp.Body.Close()
+
+	assert.Equal(t, "text/event-stream", resp.Header.Get("Content-Type"))
+
+	scanner := bufio.NewScanner(resp.Body)
+	next := func() string {
+		for scanner.Scan() {
+			if data, ok := strings.CutPrefix(scanner.Text(), "data: "); ok {
+				return data
+			}
+		}
+		return ""
+	}
+
+	// welcome message
+	assert.JSONEq(t, `{"pvPower":1000}`, next())
+
+	in <- util.Param{Key: "homePower", Val: 500.0}
+	in <- util.Param{Key: "gridPower", Val: -500.0}
+	assert.JSONEq(t, `{"gridPower":-500}`, next())
+}
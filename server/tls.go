@@ -0,0 +1,200 @@
+package server
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"errors"
+	"fmt"
+	"math/big"
+	"net"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/evcc-io/evcc/api/globalconfig"
+	"github.com/evcc-io/evcc/util"
+	"github.com/mitchellh/go-homedir"
+	"golang.org/x/crypto/acme"
+	"golang.org/x/crypto/acme/autocert"
+)
+
+const tlsCacheDir = "~/.evcc/certs"
+
+// StartTLS serves the router via https using the configured certificate source
+func (s *HTTPd) StartTLS(conf globalconfig.Tls) error {
+	log := util.NewLogger("https")
+
+	tlsConfig, err := newTLSConfig(log, conf)
+	if err != nil {
+		return fmt.Errorf("tls: %w", err)
+	}
+
+	srv := &http.Server{
+		Addr:         fmt.Sprintf(":%d", conf.Port),
+		Handler:      s.Handler,
+		TLSConfig:    tlsConfig,
+		ReadTimeout:  s.ReadTimeout,
+		WriteTimeout: s.WriteTimeout,
+		IdleTimeout:  s.IdleTimeout,
+		ErrorLog:     log.ERROR,
+	}
+
+	ln, err := util.Listen(conf.Port)
+	if err != nil {
+		return err
+	}
+
+	go func() {
+		if err := srv.ServeTLS(ln, "", ""); err != nil && err != http.ErrServerClosed {
+			log.ERROR.Println(err)
+		}
+	}()
+
+	log.INFO.Printf("UI listening at %s (https)", strings.Join(util.ListenAddrs(conf.Port), ", "))
+
+	return nil
+}
+
+func newTLSConfig(log *util.Logger, conf globalconfig.Tls) (*tls.Config, error) {
+	if conf.Cache == "" {
+		conf.Cache = tlsCacheDir
+	}
+
+	cache, err := homedir.Expand(conf.Cache)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := os.MkdirAll(cache, 0o700); err != nil {
+		return nil, err
+	}
+
+	switch {
+	case conf.Certificate != "" || conf.Key != "":
+		cert, err := tls.LoadX509KeyPair(conf.Certificate, conf.Key)
+		if err != nil {
+			return nil, err
+		}
+
+		return &tls.Config{Certificates: []tls.Certificate{cert}}, nil
+
+	case len(conf.Acme.Domains) == 0:
+		log.WARN.Println("no acme domains configured, using self-signed certificate")
+
+		cert, err := selfSignedCertificate(filepath.Join(cache, "selfsigned.pem"))
+		if err != nil {
+			return nil, err
+		}
+
+		return &tls.Config{Certificates: []tls.Certificate{cert}}, nil
+
+	case conf.Acme.Challenge == "dns":
+		if conf.Acme.DnsHook == "" {
+			return nil, errors.New("acme: dns challenge requires dnsHook")
+		}
+
+		m, err := newDNSManager(log, conf.Acme, cache)
+		if err != nil {
+			return nil, err
+		}
+
+		return &tls.Config{GetCertificate: m.GetCertificate}, nil
+
+	case conf.Acme.Challenge == "", conf.Acme.Challenge == "http", conf.Acme.Challenge == "tls-alpn":
+		m := &autocert.Manager{
+			Prompt:     autocert.AcceptTOS,
+			Cache:      autocert.DirCache(cache),
+			HostPolicy: autocert.HostWhitelist(conf.Acme.Domains...),
+			Email:      conf.Acme.Email,
+		}
+
+		if conf.Acme.Directory != "" {
+			m.Client = &acme.Client{DirectoryURL: conf.Acme.Directory}
+		}
+
+		// http-01 challenges are validated on port 80
+		if conf.Acme.Challenge != "tls-alpn" {
+			go func() {
+				ln, err := util.Listen(80)
+				if err == nil {
+					err = http.Serve(ln, m.HTTPHandler(nil))
+				}
+				if err != nil {
+					log.ERROR.Println("acme http challenge:", err)
+				}
+			}()
+		}
+
+		return m.TLSConfig(), nil
+
+	default:
+		return nil, fmt.Errorf("acme: invalid challenge: %s", conf.Acme.Challenge)
+	}
+}
+
+// selfSignedCertificate loads the certificate from file or creates a new one for all local addresses
+func selfSignedCertificate(file string) (tls.Certificate, error) {
+	if b, err := os.ReadFile(file); err == nil {
+		if cert, err := tls.X509KeyPair(b, b); err == nil && cert.Leaf != nil && time.Until(cert.Leaf.NotAfter) > 30*24*time.Hour {
+			return cert, nil
+		}
+	}
+
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		return tls.Certificate{}, err
+	}
+
+	serial, err := rand.Int(rand.Reader, new(big.Int).Lsh(big.NewInt(1), 128))
+	if err != nil {
+		return tls.Certificate{}, err
+	}
+
+	template := &x509.Certificate{
+		SerialNumber:          serial,
+		Subject:               pkix.Name{CommonName: "evcc"},
+		NotBefore:             time.Now().Add(-time.Hour),
+		NotAfter:              time.Now().AddDate(5, 0, 0),
+		KeyUsage:              x509.KeyUsageDigitalSignature,
+		ExtKeyUsage:           []x509.ExtKeyUsage{x509.ExtKeyUsageServerAuth},
+		BasicConstraintsValid: true,
+		DNSNames:              []string{"localhost", "evcc.local"},
+		IPAddresses:           []net.IP{net.IPv4(127, 0, 0, 1), net.IPv6loopback},
+	}
+
+	if host, err := os.Hostname(); err == nil {
+		template.DNSNames = append(template.DNSNames, host)
+	}
+
+	for _, ip := range util.LocalIPs() {
+		template.IPAddresses = append(template.IPAddresses, ip.IP)
+	}
+
+	der, err := x509.CreateCertificate(rand.Reader, template, template, &key.PublicKey, key)
+	if err != nil {
+		return tls.Certificate{}, err
+	}
+
+	keyDer, err := x509.MarshalECPrivateKey(key)
+	if err != nil {
+		return tls.Certificate{}, err
+	}
+
+	b := append(
+		pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der}),
+		pem.EncodeToMemory(&pem.Block{Type: "EC PRIVATE KEY", Bytes: keyDer})...,
+	)
+
+	if err := os.WriteFile(file, b, 0o600); err != nil {
+		return tls.Certificate{}, err
+	}
+
+	return tls.X509KeyPair(b, b)
+}
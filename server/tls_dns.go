@@ -0,0 +1,248 @@
+package server
+
+import (
+	"context"
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/tls"
+	"crypto/x509"
+	"encoding/pem"
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"sync"
+	"time"
+
+	"github.com/evcc-io/evcc/api/globalconfig"
+	"github.com/evcc-io/evcc/util"
+	"golang.org/x/crypto/acme"
+)
+
+const dnsRenewBefore = 30 * 24 * time.Hour
+
+// dnsManager obtains and renews certificates using the acme dns-01 challenge.
+// TXT records are created by an external hook called as `hook present|cleanup <fqdn> <value>`.
+type dnsManager struct {
+	mu     sync.RWMutex
+	log    *util.Logger
+	conf   globalconfig.Acme
+	client *acme.Client
+	file   string
+	cert   *tls.Certificate
+}
+
+func newDNSManager(log *util.Logger, conf globalconfig.Acme, cache string) (*dnsManager, error) {
+	key, err := loadOrCreateKey(filepath.Join(cache, "acme_account.pem"))
+	if err != nil {
+		return nil, err
+	}
+
+	m := &dnsManager{
+		log:    log,
+		conf:   conf,
+		client: &acme.Client{Key: key, DirectoryURL: conf.Directory},
+		file:   filepath.Join(cache, "dns_"+conf.Domains[0]+".pem"),
+	}
+
+	if b, err := os.ReadFile(m.file); err == nil {
+		if cert, err := tls.X509KeyPair(b, b); err == nil {
+			m.cert = &cert
+		}
+	}
+
+	// obtain certificate synchronously on first start
+	if m.cert == nil {
+		if err := m.renew(); err != nil {
+			return nil, err
+		}
+	}
+
+	go m.run()
+
+	return m, nil
+}
+
+// GetCertificate implements tls.Config.GetCertificate
+func (m *dnsManager) GetCertificate(*tls.ClientHelloInfo) (*tls.Certificate, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	return m.cert, nil
+}
+
+// run checks the certificate for renewal at startup and periodically afterwards
+func (m *dnsManager) run() {
+	ticker := time.NewTicker(12 * time.Hour)
+	defer ticker.Stop()
+
+	for ; true; <-ticker.C {
+		m.mu.RLock()
+		expiry := m.cert.Leaf.NotAfter
+		m.mu.RUnlock()
+
+		if time.Until(expiry) > dnsRenewBefore {
+			continue
+		}
+
+		if err := m.renew(); err != nil {
+			m.log.ERROR.Println("certificate renewal:", err)
+		}
+	}
+}
+
+func (m *dnsManager) renew() error {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Minute)
+	defer cancel()
+
+	acct := &acme.Account{}
+	if m.conf.Email != "" {
+		acct.Contact = []string{"mailto:" + m.conf.Email}
+	}
+
+	if _, err := m.client.Register(ctx, acct, acme.AcceptTOS); err != nil && !errors.Is(err, acme.ErrAccountAlreadyExists) {
+		return fmt.Errorf("register: %w", err)
+	}
+
+	order, err := m.client.AuthorizeOrder(ctx, acme.DomainIDs(m.conf.Domains...))
+	if err != nil {
+		return fmt.Errorf("order: %w", err)
+	}
+
+	for _, u := range order.AuthzURLs {
+		if err := m.authorize(ctx, u); err != nil {
+			return err
+		}
+	}
+
+	if order, err = m.client.WaitOrder(ctx, order.URI); err != nil {
+		return fmt.Errorf("order: %w", err)
+	}
+
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		return err
+	}
+
+	csr, err := x509.CreateCertificateRequest(rand.Reader, &x509.CertificateRequest{DNSNames: m.conf.Domains}, key)
+	if err != nil {
+		return err
+	}
+
+	chain, _, err := m.client.CreateOrderCert(ctx, order.FinalizeURL, csr, true)
+	if err != nil {
+		return fmt.Errorf("certificate: %w", err)
+	}
+
+	keyDer, err := x509.MarshalECPrivateKey(key)
+	if err != nil {
+		return err
+	}
+
+	var b []byte
+	for _, der := range chain {
+		b = append(b, pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der})...)
+	}
+	b = append(b, pem.EncodeToMemory(&pem.Block{Type: "EC PRIVATE KEY", Bytes: keyDer})...)
+
+	cert, err := tls.X509KeyPair(b, b)
+	if err != nil {
+		return err
+	}
+
+	if err := os.WriteFile(m.file, b, 0o600); err != nil {
+		return err
+	}
+
+	m.mu.Lock()
+	m.cert = &cert
+	m.mu.Unlock()
+
+	m.log.INFO.Printf("certificate valid until %v", cert.Leaf.NotAfter.Format(time.DateOnly))
+
+	return nil
+}
+
+func (m *dnsManager) authorize(ctx context.Context, uri string) error {
+	z, err := m.client.GetAuthorization(ctx, uri)
+	if err != nil {
+		return err
+	}
+
+	if z.Status == acme.StatusValid {
+		return nil
+	}
+
+	var chal *acme.Challenge
+	for _, c := range z.Challenges {
+		if c.Type == "dns-01" {
+			chal = c
+			break
+		}
+	}
+
+	if chal == nil {
+		return fmt.Errorf("no dns-01 challenge for %s", z.Identifier.Value)
+	}
+
+	value, err := m.client.DNS01ChallengeRecord(chal.Token)
+	if err != nil {
+		return err
+	}
+
+	fqdn := "_acme-challenge." + z.Identifier.Value
+
+	if err := m.hook(ctx, "present", fqdn, value); err != nil {
+		return err
+	}
+
+	defer func() {
+		if err := m.hook(context.Background(), "cleanup", fqdn, value); err != nil {
+			m.log.WARN.Println(err)
+		}
+	}()
+
+	if _, err := m.client.Accept(ctx, chal); err != nil {
+		return fmt.Errorf("accept: %w", err)
+	}
+
+	if _, err := m.client.WaitAuthorization(ctx, z.URI); err != nil {
+		return fmt.Errorf("authorize %s: %w", z.Identifier.Value, err)
+	}
+
+	return nil
+}
+
+func (m *dnsManager) hook(ctx context.Context, action, fqdn, value string) error {
+	out, err := exec.CommandContext(ctx, m.conf.DnsHook, action, fqdn, value).CombinedOutput()
+	if err != nil {
+		return fmt.Errorf("dns hook %s: %w: %s", action, err, out)
+	}
+	return nil
+}
+
+func loadOrCreateKey(file string) (crypto.Signer, error) {
+	if b, err := os.ReadFile(file); err == nil {
+		if block, _ := pem.Decode(b); block != nil {
+			return x509.ParseECPrivateKey(block.Bytes)
+		}
+	}
+
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		return nil, err
+	}
+
+	der, err := x509.MarshalECPrivateKey(key)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := os.WriteFile(file, pem.EncodeToMemory(&pem.Block{Type: "EC PRIVATE KEY", Bytes: der}), 0o600); err != nil {
+		return nil, err
+	}
+
+	return key, nil
+}
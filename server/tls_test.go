@@ -0,0 +1,22 @@
+package server
+
+import (
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSelfSignedCertificate(t *testing.T) {
+	file := filepath.Join(t.TempDir(), "selfsigned.pem")
+
+	cert, err := selfSignedCertificate(file)
+	require.NoError(t, err)
+	assert.Contains(t, cert.Leaf.DNSNames, "localhost")
+
+	// reuse existing certificate
+	cert2, err := selfSignedCertificate(file)
+	require.NoError(t, err)
+	assert.Equal(t, cert.Leaf.SerialNumber, cert2.Leaf.SerialNumber)
+}
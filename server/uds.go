@@ -9,6 +9,7 @@ import (
 
 	"github.com/evcc-io/evcc/cmd/shutdown"
 	"github.com/evcc-io/evcc/core/site"
+	"github.com/gorilla/mux"
 )
 
 // SocketPath is the unix domain socket path
@@ -23,7 +24,8 @@ func removeIfExists(file string) {
 	}
 }
 
-// HealthListener attaches listener to unix domain socket and runs listener
+// HealthListener attaches listener to unix domain socket and runs listener.
+// Besides health checks, the socket serves the local ocpp cli commands.
 func HealthListener(site site.API) {
 	removeIfExists(SocketPath)
 
@@ -32,9 +34,14 @@ func HealthListener(site site.API) {
 		log.FATAL.Fatal(err)
 	}
 
-	mux := http.NewServeMux()
-	httpd := http.Server{Handler: mux}
-	mux.HandleFunc("/health", healthHandler(site))
+	router := mux.NewRouter()
+	httpd := http.Server{Handler: router}
+	router.HandleFunc("/health", healthHandler(site))
+	router.Methods(http.MethodGet).Path("/ocpp/{id}/config").HandlerFunc(ocppConfigHandler)
+	router.Methods(http.MethodPut).Path("/ocpp/{id}/config/{key}").HandlerFunc(ocppChangeConfigHandler)
+	router.Methods(http.MethodGet).Path("/ocpp").HandlerFunc(ocppChargePointsHandler)
+	router.Methods(http.MethodPost).Path("/ocpp/{id}/reset").HandlerFunc(ocppResetHandler)
+	router.Methods(http.MethodPost).Path("/ocpp/{id}/trigger").HandlerFunc(ocppTriggerHandler)
 
 	go func() { _ = httpd.Serve(l) }()
 
@@ -13,6 +13,7 @@ import (
 	"github.com/evcc-io/evcc/tariff/amber"
 	"github.com/evcc-io/evcc/util"
 	"github.com/evcc-io/evcc/util/request"
+	"github.com/evcc-io/evcc/util/scheduler"
 	"github.com/evcc-io/evcc/util/transport"
 )
 
@@ -79,7 +80,7 @@ func NewAmberFromConfig(other map[string]any) (api.Tariff, error) {
 func (t *Amber) run(done chan error) {
 	var once sync.Once
 
-	for tick := time.Tick(time.Minute); ; <-tick {
+	scheduler.Run(t.uri, time.Minute, func() error {
 		var res []amber.PriceInfo
 
 		if err := backoff.Retry(func() error {
@@ -88,7 +89,7 @@ func (t *Amber) run(done chan error) {
 			once.Do(func() { done <- err })
 
 			t.log.ERROR.Println(err)
-			continue
+			return err
 		}
 
 		// Create and sort time-ordered list of all Amber intervals
@@ -129,7 +130,7 @@ func (t *Amber) run(done chan error) {
 		if len(intervals) == 0 {
 			mergeRates(t.data, nil)
 			once.Do(func() { close(done) })
-			continue
+			return nil
 		}
 
 		// Sort intervals by start time to ensure correct processing
@@ -145,7 +146,8 @@ func (t *Amber) run(done chan error) {
 
 		mergeRates(t.data, data)
 		once.Do(func() { close(done) })
-	}
+		return nil
+	})
 }
 
 // buildSlotRates converts Amber intervals into 15-minute slots using bucket sharding
@@ -12,6 +12,7 @@ import (
 	"github.com/evcc-io/evcc/tariff/awattar"
 	"github.com/evcc-io/evcc/util"
 	"github.com/evcc-io/evcc/util/request"
+	"github.com/evcc-io/evcc/util/scheduler"
 )
 
 type Awattar struct {
@@ -58,7 +59,7 @@ func (t *Awattar) run(done chan error) {
 
 	client := request.NewHelper(t.log)
 
-	for tick := time.Tick(time.Hour); ; <-tick {
+	scheduler.Run(t.uri, time.Hour, func() error {
 		var res awattar.Prices
 
 		// Awattar publishes prices for next day around 13:00 CET/CEST, so up to 35h of price data are available
@@ -73,7 +74,7 @@ func (t *Awattar) run(done chan error) {
 			once.Do(func() { done <- err })
 
 			t.log.ERROR.Println(err)
-			continue
+			return err
 		}
 
 		data := make(api.Rates, 0, len(res.Data))
@@ -88,7 +89,8 @@ func (t *Awattar) run(done chan error) {
 
 		mergeRates(t.data, data)
 		once.Do(func() { close(done) })
-	}
+		return nil
+	})
 }
 
 // Rates implements the api.Tariff interface
@@ -14,6 +14,7 @@ import (
 	"github.com/evcc-io/evcc/util"
 	"github.com/evcc-io/evcc/util/oauth"
 	"github.com/evcc-io/evcc/util/request"
+	"github.com/evcc-io/evcc/util/scheduler"
 	"github.com/evcc-io/evcc/util/transport"
 	"github.com/fatih/structs"
 	"github.com/jinzhu/now"
@@ -103,7 +104,7 @@ func (t *EdfTempo) RefreshToken(_ *oauth2.Token) (*oauth2.Token, error) {
 func (t *EdfTempo) run(done chan error) {
 	var once sync.Once
 
-	for tick := time.Tick(time.Hour); ; <-tick {
+	scheduler.Run("https://digital.iservices.rte-france.com", time.Hour, func() error {
 		var res struct {
 			Data struct {
 				Values []struct {
@@ -127,7 +128,7 @@ func (t *EdfTempo) run(done chan error) {
 			once.Do(func() { done <- err })
 
 			t.log.ERROR.Println(err)
-			continue
+			return err
 		}
 
 		data := make(api.Rates, 0, 24*len(res.Data.Values))
@@ -144,7 +145,8 @@ func (t *EdfTempo) run(done chan error) {
 
 		mergeRates(t.data, data)
 		once.Do(func() { close(done) })
-	}
+		return nil
+	})
 }
 
 // Rates implements the api.Tariff interface
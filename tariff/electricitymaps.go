@@ -12,6 +12,7 @@ import (
 	"github.com/evcc-io/evcc/api"
 	"github.com/evcc-io/evcc/util"
 	"github.com/evcc-io/evcc/util/request"
+	"github.com/evcc-io/evcc/util/scheduler"
 	"github.com/evcc-io/evcc/util/transport"
 )
 
@@ -78,7 +79,7 @@ func (t *ElectricityMaps) run(done chan error) {
 
 	uri := fmt.Sprintf("%s/carbon-intensity/forecast?zone=%s", t.uri, t.zone)
 
-	for tick := time.Tick(time.Hour); ; <-tick {
+	scheduler.Run(uri, time.Hour, func() error {
 		var res CarbonIntensity
 
 		if err := backoff.Retry(func() error {
@@ -91,7 +92,7 @@ func (t *ElectricityMaps) run(done chan error) {
 			once.Do(func() { done <- err })
 
 			t.log.ERROR.Println(err)
-			continue
+			return err
 		}
 
 		data := make(api.Rates, 0, len(res.Forecast))
@@ -106,7 +107,8 @@ func (t *ElectricityMaps) run(done chan error) {
 
 		mergeRates(t.data, data)
 		once.Do(func() { close(done) })
-	}
+		return nil
+	})
 }
 
 func (t *ElectricityMaps) Rates() (api.Rates, error) {
@@ -14,6 +14,7 @@ import (
 	"github.com/evcc-io/evcc/tariff/elering"
 	"github.com/evcc-io/evcc/util"
 	"github.com/evcc-io/evcc/util/request"
+	"github.com/evcc-io/evcc/util/scheduler"
 )
 
 type Elering struct {
@@ -61,7 +62,7 @@ func (t *Elering) run(done chan error) {
 	var once sync.Once
 	client := request.NewHelper(t.log)
 
-	for tick := time.Tick(time.Hour); ; <-tick {
+	scheduler.Run(elering.URI, time.Hour, func() error {
 		var res elering.NpsPrice
 
 		ts := time.Now().Truncate(time.Hour)
@@ -75,7 +76,7 @@ func (t *Elering) run(done chan error) {
 			once.Do(func() { done <- err })
 
 			t.log.ERROR.Println(err)
-			continue
+			return err
 		}
 
 		data := make(api.Rates, 0, len(res.Data[t.region]))
@@ -92,7 +93,8 @@ func (t *Elering) run(done chan error) {
 
 		mergeRates(t.data, data)
 		once.Do(func() { close(done) })
-	}
+		return nil
+	})
 }
 
 // Rates implements the api.Tariff interface
@@ -14,6 +14,7 @@ import (
 	"github.com/evcc-io/evcc/tariff/entsoe"
 	"github.com/evcc-io/evcc/util"
 	"github.com/evcc-io/evcc/util/request"
+	"github.com/evcc-io/evcc/util/scheduler"
 	"github.com/evcc-io/evcc/util/transport"
 )
 
@@ -86,7 +87,7 @@ func (t *Entsoe) run(done chan error) {
 	var once sync.Once
 
 	// Data updated by ESO every half hour, but we only need data every hour to stay current.
-	for tick := time.Tick(time.Hour); ; <-tick {
+	scheduler.Run(entsoe.BaseURI, time.Hour, func() error {
 		var tr entsoe.PublicationMarketDocument
 
 		if err := backoff.Retry(func() error {
@@ -128,13 +129,13 @@ func (t *Entsoe) run(done chan error) {
 			once.Do(func() { done <- err })
 
 			t.log.ERROR.Println(err)
-			continue
+			return err
 		}
 
 		if len(tr.TimeSeries) == 0 {
 			once.Do(func() { done <- entsoe.ErrInvalidData })
 			t.log.ERROR.Println(entsoe.ErrInvalidData)
-			continue
+			return entsoe.ErrInvalidData
 		}
 
 		// extract desired series
@@ -142,7 +143,7 @@ func (t *Entsoe) run(done chan error) {
 		if err != nil {
 			once.Do(func() { done <- err })
 			t.log.ERROR.Println(err)
-			continue
+			return err
 		}
 
 		data := make(api.Rates, 0, len(res))
@@ -157,7 +158,8 @@ func (t *Entsoe) run(done chan error) {
 
 		mergeRates(t.data, data)
 		once.Do(func() { close(done) })
-	}
+		return nil
+	})
 }
 
 // Rates implements the api.Tariff interface
@@ -12,6 +12,7 @@ import (
 	"github.com/evcc-io/evcc/tariff/corrently"
 	"github.com/evcc-io/evcc/util"
 	"github.com/evcc-io/evcc/util/request"
+	"github.com/evcc-io/evcc/util/scheduler"
 	"golang.org/x/oauth2"
 )
 
@@ -59,7 +60,7 @@ func (t *GrünStromIndex) run(done chan error) {
 	var once sync.Once
 	uri := fmt.Sprintf("https://api.corrently.io/v2.0/gsi/prediction?zip=%s", t.zip)
 
-	for tick := time.Tick(time.Hour); ; <-tick {
+	scheduler.Run(uri, time.Hour, func() error {
 		var res corrently.Forecast
 
 		err := backoff.Retry(func() error {
@@ -78,7 +79,7 @@ func (t *GrünStromIndex) run(done chan error) {
 			once.Do(func() { done <- err })
 
 			t.log.ERROR.Println(err)
-			continue
+			return err
 		}
 
 		data := make(api.Rates, 0, len(res.Forecast))
@@ -92,7 +93,8 @@ func (t *GrünStromIndex) run(done chan error) {
 
 		mergeRates(t.data, data)
 		once.Do(func() { close(done) })
-	}
+		return nil
+	})
 }
 
 // Rates implements the api.Tariff interface
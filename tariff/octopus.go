@@ -14,6 +14,7 @@ import (
 	octoRest "github.com/evcc-io/evcc/tariff/octopus/rest"
 	"github.com/evcc-io/evcc/util"
 	"github.com/evcc-io/evcc/util/request"
+	"github.com/evcc-io/evcc/util/scheduler"
 )
 
 type Octopus struct {
@@ -149,7 +150,7 @@ func (t *Octopus) run(done chan error) {
 	}
 
 	// TODO tick every 15 minutes if GraphQL is available to poll for Intelligent slots.
-	for tick := time.Tick(time.Hour); ; <-tick {
+	scheduler.Run(restQueryUri, time.Hour, func() error {
 		var res octoRest.UnitRates
 
 		if err := backoff.Retry(func() error {
@@ -158,7 +159,7 @@ func (t *Octopus) run(done chan error) {
 			once.Do(func() { done <- err })
 
 			t.log.ERROR.Println(err)
-			continue
+			return err
 		}
 
 		data := make(api.Rates, 0, len(res.Results))
@@ -190,7 +191,8 @@ func (t *Octopus) run(done chan error) {
 
 		mergeRates(t.data, data)
 		once.Do(func() { close(done) })
-	}
+		return nil
+	})
 }
 
 // Rates implements the api.Tariff interface
@@ -16,6 +16,7 @@ import (
 	"github.com/evcc-io/evcc/util"
 	"github.com/evcc-io/evcc/util/oauth"
 	"github.com/evcc-io/evcc/util/request"
+	"github.com/evcc-io/evcc/util/scheduler"
 	"github.com/evcc-io/evcc/util/transport"
 	"github.com/jinzhu/now"
 	"golang.org/x/oauth2"
@@ -179,12 +180,12 @@ func (t *Ostrom) RefreshToken(_ *oauth2.Token) (*oauth2.Token, error) {
 func (t *Ostrom) runStatic(done chan error) {
 	var once sync.Once
 
-	for tick := time.Tick(time.Hour); ; <-tick {
+	scheduler.Run(ostrom.URI_API, time.Hour, func() error {
 		price, err := t.getFixedPrice()
 		if err != nil {
 			once.Do(func() { done <- err })
 			t.log.ERROR.Println(err)
-			continue
+			return err
 		}
 
 		data := make(api.Rates, 48)
@@ -199,7 +200,8 @@ func (t *Ostrom) runStatic(done chan error) {
 
 		mergeRates(t.data, data)
 		once.Do(func() { close(done) })
-	}
+		return nil
+	})
 }
 
 // This function calls th ostrom API to query the
@@ -207,7 +209,7 @@ func (t *Ostrom) runStatic(done chan error) {
 func (t *Ostrom) run(done chan error) {
 	var once sync.Once
 
-	for tick := time.Tick(time.Hour); ; <-tick {
+	scheduler.Run(ostrom.URI_API, time.Hour, func() error {
 		var res ostrom.Prices
 
 		start := now.BeginningOfDay()
@@ -226,7 +228,7 @@ func (t *Ostrom) run(done chan error) {
 		}, bo()); err != nil {
 			once.Do(func() { done <- err })
 			t.log.ERROR.Println(err)
-			continue
+			return err
 		}
 
 		data := make(api.Rates, 0, 48)
@@ -241,7 +243,8 @@ func (t *Ostrom) run(done chan error) {
 
 		mergeRates(t.data, data)
 		once.Do(func() { close(done) })
-	}
+		return nil
+	})
 }
 
 // Rates implements the api.Tariff interface
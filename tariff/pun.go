@@ -17,6 +17,7 @@ import (
 	"github.com/evcc-io/evcc/api"
 	"github.com/evcc-io/evcc/util"
 	"github.com/evcc-io/evcc/util/request"
+	"github.com/evcc-io/evcc/util/scheduler"
 )
 
 type Pun struct {
@@ -71,7 +72,7 @@ func NewPunFromConfig(other map[string]any) (api.Tariff, error) {
 func (t *Pun) run(done chan error) {
 	var once sync.Once
 
-	for tick := time.Tick(time.Hour); ; <-tick {
+	scheduler.Run("https://gme.mercatoelettrico.org", time.Hour, func() error {
 		// get today data
 		today, err := backoff.RetryWithData(func() (api.Rates, error) {
 			res, err := t.getData(time.Now())
@@ -80,7 +81,7 @@ func (t *Pun) run(done chan error) {
 		if err != nil {
 			once.Do(func() { done <- err })
 			t.log.ERROR.Println(err)
-			continue
+			return err
 		}
 
 		// get tomorrow data
@@ -91,7 +92,7 @@ func (t *Pun) run(done chan error) {
 		if err != nil {
 			once.Do(func() { done <- err })
 			t.log.ERROR.Println(err)
-			continue
+			return err
 		}
 
 		// merge today and tomorrow data
@@ -99,7 +100,8 @@ func (t *Pun) run(done chan error) {
 
 		mergeRates(t.data, data)
 		once.Do(func() { close(done) })
-	}
+		return nil
+	})
 }
 
 // Rates implements the api.Tariff interface
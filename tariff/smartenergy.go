@@ -10,6 +10,7 @@ import (
 	"github.com/evcc-io/evcc/tariff/smartenergy"
 	"github.com/evcc-io/evcc/util"
 	"github.com/evcc-io/evcc/util/request"
+	"github.com/evcc-io/evcc/util/scheduler"
 )
 
 type SmartEnergy struct {
@@ -50,7 +51,7 @@ func (t *SmartEnergy) run(done chan error) {
 	var once sync.Once
 	client := request.NewHelper(t.log)
 
-	for tick := time.Tick(time.Hour); ; <-tick {
+	scheduler.Run(smartenergy.URI, time.Hour, func() error {
 		var res smartenergy.Prices
 
 		if err := backoff.Retry(func() error {
@@ -59,7 +60,7 @@ func (t *SmartEnergy) run(done chan error) {
 			once.Do(func() { done <- err })
 
 			t.log.ERROR.Println(err)
-			continue
+			return err
 		}
 
 		data := make(api.Rates, 0, len(res.Data))
@@ -78,7 +79,8 @@ func (t *SmartEnergy) run(done chan error) {
 
 		mergeRates(t.data, data)
 		once.Do(func() { close(done) })
-	}
+		return nil
+	})
 }
 
 // Rates implements the api.Tariff interface
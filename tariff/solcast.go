@@ -12,6 +12,7 @@ import (
 	"github.com/evcc-io/evcc/tariff/solcast"
 	"github.com/evcc-io/evcc/util"
 	"github.com/evcc-io/evcc/util/request"
+	"github.com/evcc-io/evcc/util/scheduler"
 	"github.com/evcc-io/evcc/util/transport"
 	"github.com/jinzhu/now"
 )
@@ -77,12 +78,12 @@ func NewSolcastFromConfig(other map[string]any) (api.Tariff, error) {
 func (t *Solcast) run(interval time.Duration, done chan error) {
 	var once sync.Once
 
-	for ; true; <-time.Tick(interval) {
+	scheduler.Run("https://api.solcast.com.au", interval, func() error {
 		// ensure we don't run when not needed, but execute once at startup
 		select {
 		case <-t.data.Done():
 			if !t.fromTo.IsActive(time.Now().Hour()) {
-				continue
+				return nil
 			}
 		default:
 		}
@@ -95,7 +96,7 @@ func (t *Solcast) run(interval time.Duration, done chan error) {
 		}, bo()); err != nil {
 			once.Do(func() { done <- err })
 			t.log.ERROR.Println(err)
-			continue
+			return err
 		}
 
 		once.Do(func() { close(done) })
@@ -123,7 +124,8 @@ func (t *Solcast) run(interval time.Duration, done chan error) {
 
 		mergeRatesAfter(t.data, data, beginningOfDay())
 		once.Do(func() { close(done) })
-	}
+		return nil
+	})
 }
 
 // Rates implements the api.Tariff interface
@@ -2,6 +2,7 @@ package tariff
 
 import (
 	"encoding/json"
+	"errors"
 	"fmt"
 	"net/http"
 	"slices"
@@ -13,6 +14,7 @@ import (
 	"github.com/evcc-io/evcc/api"
 	"github.com/evcc-io/evcc/util"
 	"github.com/evcc-io/evcc/util/request"
+	"github.com/evcc-io/evcc/util/scheduler"
 )
 
 // Supported regions
@@ -86,20 +88,21 @@ func (t *Stekker) run(done chan error) {
 	var once sync.Once
 	client := request.NewHelper(t.log)
 
-	for tick := time.Tick(time.Hour); ; <-tick {
+	scheduler.Run(stekkerURI, time.Hour, func() error {
 		url := fmt.Sprintf("%s?advanced_view=&region=%s&unit=MWh", stekkerURI, t.region)
 		resp, err := client.Get(url)
 		if err != nil {
 			once.Do(func() { done <- err })
 			t.log.ERROR.Println("http error:", err)
-			continue
+			return err
 		}
 
 		if resp.StatusCode != http.StatusOK {
-			once.Do(func() { done <- fmt.Errorf("http status %d", resp.StatusCode) })
-			t.log.ERROR.Printf("http status %d", resp.StatusCode)
+			err := fmt.Errorf("http status %d", resp.StatusCode)
+			once.Do(func() { done <- err })
+			t.log.ERROR.Println(err)
 			resp.Body.Close()
-			continue
+			return err
 		}
 
 		doc, err := goquery.NewDocumentFromReader(resp.Body)
@@ -107,15 +110,16 @@ func (t *Stekker) run(done chan error) {
 			resp.Body.Close()
 			once.Do(func() { done <- err })
 			t.log.ERROR.Println("parse error:", err)
-			continue
+			return err
 		}
 		resp.Body.Close()
 
 		val, ok := doc.Find("[data-epex-forecast-graph-data-value]").Attr("data-epex-forecast-graph-data-value")
 		if !ok {
-			once.Do(func() { done <- fmt.Errorf("no forecast attribute found") })
-			t.log.ERROR.Println("no forecast attribute found")
-			continue
+			err := errors.New("no forecast attribute found")
+			once.Do(func() { done <- err })
+			t.log.ERROR.Println(err)
+			return err
 		}
 
 		raw := strings.ReplaceAll(val, "&quot;", "\"")
@@ -124,7 +128,7 @@ func (t *Stekker) run(done chan error) {
 		if err := json.Unmarshal([]byte(raw), &data); err != nil {
 			once.Do(func() { done <- err })
 			t.log.ERROR.Println("unmarshal error:", err)
-			continue
+			return err
 		}
 
 		var res api.Rates
@@ -159,7 +163,8 @@ func (t *Stekker) run(done chan error) {
 
 		mergeRates(t.data, res)
 		once.Do(func() { close(done) })
-	}
+		return nil
+	})
 }
 
 // Rates implements api.Tariff
@@ -12,6 +12,7 @@ import (
 	"github.com/evcc-io/evcc/api"
 	"github.com/evcc-io/evcc/plugin"
 	"github.com/evcc-io/evcc/util"
+	"github.com/evcc-io/evcc/util/scheduler"
 )
 
 type Tariff struct {
@@ -89,7 +90,7 @@ func NewConfigurableFromConfig(ctx context.Context, other map[string]any) (api.T
 func (t *Tariff) run(forecastG func() (string, error), done chan error, interval time.Duration) {
 	var once sync.Once
 
-	for tick := time.Tick(interval); ; <-tick {
+	scheduler.Run("", interval, func() error {
 		var data api.Rates
 		if err := backoff.Retry(func() error {
 			s, err := forecastG()
@@ -111,7 +112,7 @@ func (t *Tariff) run(forecastG func() (string, error), done chan error, interval
 			once.Do(func() { done <- err })
 
 			t.log.ERROR.Println(err)
-			continue
+			return err
 		}
 
 		// only prune rates older than current period
@@ -122,7 +123,8 @@ func (t *Tariff) run(forecastG func() (string, error), done chan error, interval
 		mergeRatesAfter(t.data, data, periodStart)
 
 		once.Do(func() { close(done) })
-	}
+		return nil
+	})
 }
 
 func (t *Tariff) forecastRates() (api.Rates, error) {
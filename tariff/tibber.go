@@ -11,6 +11,7 @@ import (
 	"github.com/evcc-io/evcc/meter/tibber"
 	"github.com/evcc-io/evcc/util"
 	"github.com/evcc-io/evcc/util/request"
+	"github.com/evcc-io/evcc/util/scheduler"
 	"github.com/hasura/go-graphql-client"
 )
 
@@ -76,7 +77,7 @@ func (t *Tibber) run(done chan error) {
 		"id": graphql.ID(t.homeID),
 	}
 
-	for tick := time.Tick(time.Hour); ; <-tick {
+	scheduler.Run(tibber.URI, time.Hour, func() error {
 		var res struct {
 			Viewer struct {
 				Home struct {
@@ -95,7 +96,7 @@ func (t *Tibber) run(done chan error) {
 			once.Do(func() { done <- err })
 
 			t.log.ERROR.Println(err)
-			continue
+			return err
 		}
 
 		pi := res.Viewer.Home.CurrentSubscription.PriceInfo
@@ -103,7 +104,8 @@ func (t *Tibber) run(done chan error) {
 
 		mergeRates(t.data, data)
 		once.Do(func() { close(done) })
-	}
+		return nil
+	})
 }
 
 func (t *Tibber) rates(pi []tibber.Price) api.Rates {
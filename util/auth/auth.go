@@ -4,6 +4,7 @@ import (
 	"crypto/rand"
 	"encoding/hex"
 	"errors"
+	"strings"
 	"time"
 
 	"github.com/evcc-io/evcc/core/keys"
@@ -33,6 +34,21 @@ type Auth interface {
 	IsAdminPasswordConfigured() bool
 	SetAuthMode(AuthMode)
 	GetAuthMode() AuthMode
+
+	// user accounts
+	HasUsers() bool
+	Users() []User
+	SetUser(name, password string, role Role) error
+	RemoveUser(name string) error
+	RevokeSessions(name string) error
+	IsUserPasswordValid(name, password string) (Role, bool)
+	GenerateUserJwtToken(name string, lifetime time.Duration) (string, error)
+	ValidateUserJwtToken(string) (string, Role, error)
+
+	// api tokens
+	ApiTokens() []ApiToken
+	CreateApiToken(name string, scope Scope) (string, ApiToken, error)
+	RevokeApiToken(id string) error
 }
 
 type auth struct {
@@ -122,9 +138,20 @@ func (a *auth) getJwtSecret() ([]byte, error) {
 
 // GenerateJwtToken generates an admin user JWT token with the given lifetime
 func (a *auth) GenerateJwtToken(lifetime time.Duration) (string, error) {
+	return a.GenerateUserJwtToken(admin, lifetime)
+}
+
+// GenerateUserJwtToken generates a JWT token for the given user with the given lifetime
+func (a *auth) GenerateUserJwtToken(name string, lifetime time.Duration) (string, error) {
+	if name == "" {
+		name = admin
+	}
+
+	now := time.Now()
 	claims := &jwt.RegisteredClaims{
-		Subject:   admin,
-		ExpiresAt: jwt.NewNumericDate(time.Now().Add(lifetime)),
+		Subject:   name,
+		IssuedAt:  jwt.NewNumericDate(now),
+		ExpiresAt: jwt.NewNumericDate(now.Add(lifetime)),
 	}
 
 	if jwtSecret, err := a.getJwtSecret(); err != nil {
@@ -135,22 +162,56 @@ func (a *auth) GenerateJwtToken(lifetime time.Duration) (string, error) {
 	}
 }
 
-// ValidateJwtToken validates the given JWT token
+// ValidateJwtToken validates the given admin JWT token
 func (a *auth) ValidateJwtToken(tokenString string) (bool, error) {
-	jwtSecret, err := a.getJwtSecret()
+	_, role, err := a.ValidateUserJwtToken(tokenString)
 	if err != nil {
 		return false, err
 	}
 
+	return role == RoleAdmin, nil
+}
+
+// ValidateUserJwtToken validates the given JWT or api token and returns user name and role.
+// Api tokens are reported as token:<name> with the role equivalent to the token's scope.
+func (a *auth) ValidateUserJwtToken(tokenString string) (string, Role, error) {
+	if strings.HasPrefix(tokenString, apiTokenPrefix) {
+		t, err := a.validateApiToken(tokenString)
+		if err != nil {
+			return "", 0, err
+		}
+
+		return "token:" + t.Name, t.Scope.Role(), nil
+	}
+
+	jwtSecret, err := a.getJwtSecret()
+	if err != nil {
+		return "", 0, err
+	}
+
 	// read token
 	var claims jwt.RegisteredClaims
 	if _, err := jwt.ParseWithClaims(tokenString, &claims, func(token *jwt.Token) (any, error) {
 		return jwtSecret, nil
-	}, jwt.WithSubject(admin)); err != nil {
-		return false, err
+	}); err != nil {
+		return "", 0, err
+	}
+
+	if claims.Subject == admin {
+		return admin, RoleAdmin, nil
+	}
+
+	// role is taken from the current account to apply changes immediately
+	u, ok := a.getUser(claims.Subject)
+	if !ok {
+		return "", 0, errors.New("unknown user")
+	}
+
+	if claims.IssuedAt == nil || claims.IssuedAt.Before(u.NotBefore.Truncate(time.Second)) {
+		return "", 0, errors.New("session revoked")
 	}
 
-	return true, nil
+	return u.Name, u.Role, nil
 }
 
 func (a *auth) SetAuthMode(authMode AuthMode) {
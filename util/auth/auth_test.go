@@ -76,3 +76,103 @@ func TestJwtToken(t *testing.T) {
 	ok, err := auth.ValidateJwtToken(tokenString)
 	assert.True(t, ok && err == nil, "token is invalid")
 }
+
+func TestUsers(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mock := settings.NewMockAPI(ctrl)
+	auth := NewMock(mock)
+
+	var users string
+	mock.EXPECT().String(keys.JwtSecret).Return("somesecret", nil).AnyTimes()
+	mock.EXPECT().String(keys.Users).DoAndReturn(func(string) (string, error) { return users, nil }).AnyTimes()
+	mock.EXPECT().SetString(keys.Users, gomock.Any()).Do(func(_ string, s string) { users = s }).AnyTimes()
+
+	assert.False(t, auth.HasUsers())
+	assert.Error(t, auth.SetUser("admin", "pw", RoleViewer), "reserved name")
+	assert.Error(t, auth.SetUser("tenant", "", RoleViewer), "empty password")
+	assert.NoError(t, auth.SetUser("tenant", "pw", RoleViewer))
+	assert.True(t, auth.HasUsers())
+	assert.Empty(t, auth.Users()[0].Hash)
+
+	role, ok := auth.IsUserPasswordValid("tenant", "pw")
+	assert.True(t, ok)
+	assert.Equal(t, RoleViewer, role)
+
+	_, ok = auth.IsUserPasswordValid("tenant", "wrong")
+	assert.False(t, ok)
+
+	token, err := auth.GenerateUserJwtToken("tenant", time.Hour)
+	assert.NoError(t, err)
+
+	// role changes apply to existing sessions
+	assert.NoError(t, auth.SetUser("tenant", "", RoleOperator))
+	name, role, err := auth.ValidateUserJwtToken(token)
+	assert.NoError(t, err)
+	assert.Equal(t, "tenant", name)
+	assert.Equal(t, RoleOperator, role)
+
+	// viewer token is not an admin token
+	ok, err = auth.ValidateJwtToken(token)
+	assert.False(t, ok)
+	assert.NoError(t, err)
+
+	assert.NoError(t, auth.RemoveUser("tenant"))
+	_, _, err = auth.ValidateUserJwtToken(token)
+	assert.Error(t, err)
+}
+
+func TestRole(t *testing.T) {
+	for _, r := range []Role{RoleViewer, RoleOperator, RoleAdmin} {
+		res, err := RoleString(r.String())
+		assert.NoError(t, err)
+		assert.Equal(t, r, res)
+	}
+
+	assert.True(t, RoleAdmin.Includes(RoleOperator))
+	assert.False(t, RoleViewer.Includes(RoleOperator))
+}
+
+func TestApiTokens(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mock := settings.NewMockAPI(ctrl)
+	auth := NewMock(mock)
+
+	var tokens string
+	mock.EXPECT().String(keys.ApiTokens).DoAndReturn(func(string) (string, error) { return tokens, nil }).AnyTimes()
+	mock.EXPECT().SetString(keys.ApiTokens, gomock.Any()).Do(func(_ string, s string) { tokens = s }).AnyTimes()
+
+	_, _, err := auth.CreateApiToken("dashboard", 0)
+	assert.Error(t, err, "invalid scope")
+	_, _, err = auth.CreateApiToken("my dashboard", ScopeRead)
+	assert.Error(t, err, "invalid name")
+
+	token, res, err := auth.CreateApiToken("dashboard", ScopeControl)
+	assert.NoError(t, err)
+	assert.NotEmpty(t, token)
+	assert.Empty(t, res.Hash)
+
+	_, _, err = auth.CreateApiToken("dashboard", ScopeRead)
+	assert.Error(t, err, "duplicate name")
+
+	assert.Len(t, auth.ApiTokens(), 1)
+	assert.Empty(t, auth.ApiTokens()[0].Hash)
+	assert.NotContains(t, tokens, token)
+
+	name, role, err := auth.ValidateUserJwtToken(token)
+	assert.NoError(t, err)
+	assert.Equal(t, "token:dashboard", name)
+	assert.Equal(t, RoleOperator, role)
+
+	_, _, err = auth.ValidateUserJwtToken(token + "0")
+	assert.Error(t, err)
+
+	assert.NoError(t, auth.RevokeApiToken(res.ID))
+	assert.Error(t, auth.RevokeApiToken(res.ID))
+
+	_, _, err = auth.ValidateUserJwtToken(token)
+	assert.Error(t, err)
+}
@@ -0,0 +1,53 @@
+package auth
+
+import (
+	"fmt"
+	"strings"
+)
+
+// Role is the user's permission level. Higher roles include all permissions of lower roles.
+type Role int
+
+const (
+	RoleViewer   Role = iota + 1 // read-only ui access
+	RoleOperator                 // control loadpoints, vehicles and battery
+	RoleAdmin                    // configuration and system management
+)
+
+var roleNames = map[Role]string{
+	RoleViewer:   "viewer",
+	RoleOperator: "operator",
+	RoleAdmin:    "admin",
+}
+
+func (r Role) String() string {
+	if s, ok := roleNames[r]; ok {
+		return s
+	}
+	return fmt.Sprintf("Role(%d)", r)
+}
+
+// RoleString converts a role name into role
+func RoleString(s string) (Role, error) {
+	for r, name := range roleNames {
+		if strings.EqualFold(name, s) {
+			return r, nil
+		}
+	}
+	return 0, fmt.Errorf("invalid role: %s", s)
+}
+
+// Includes returns true if the role grants the permissions of the required role
+func (r Role) Includes(required Role) bool {
+	return r >= required
+}
+
+func (r Role) MarshalText() ([]byte, error) {
+	return []byte(r.String()), nil
+}
+
+func (r *Role) UnmarshalText(text []byte) error {
+	var err error
+	*r, err = RoleString(string(text))
+	return err
+}
@@ -0,0 +1,187 @@
+package auth
+
+import (
+	"crypto/sha256"
+	"crypto/subtle"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/evcc-io/evcc/core/keys"
+)
+
+// apiTokenPrefix distinguishes api tokens from jwt session tokens
+const apiTokenPrefix = "evcc_"
+
+// Scope is the permission granted to an api token
+type Scope int
+
+const (
+	ScopeRead    Scope = iota + 1 // read-only access
+	ScopeControl                  // control loadpoints, vehicles and battery
+	ScopeConfig                   // configuration and system management
+)
+
+var scopeNames = map[Scope]string{
+	ScopeRead:    "read",
+	ScopeControl: "control",
+	ScopeConfig:  "config",
+}
+
+func (s Scope) String() string {
+	if name, ok := scopeNames[s]; ok {
+		return name
+	}
+	return fmt.Sprintf("Scope(%d)", s)
+}
+
+// ScopeString converts a scope name into scope
+func ScopeString(s string) (Scope, error) {
+	for sc, name := range scopeNames {
+		if strings.EqualFold(name, s) {
+			return sc, nil
+		}
+	}
+	return 0, fmt.Errorf("invalid scope: %s", s)
+}
+
+// Role returns the user role equivalent to the scope
+func (s Scope) Role() Role {
+	switch s {
+	case ScopeRead:
+		return RoleViewer
+	case ScopeControl:
+		return RoleOperator
+	case ScopeConfig:
+		return RoleAdmin
+	}
+	return 0
+}
+
+func (s Scope) MarshalText() ([]byte, error) {
+	return []byte(s.String()), nil
+}
+
+func (s *Scope) UnmarshalText(text []byte) error {
+	var err error
+	*s, err = ScopeString(string(text))
+	return err
+}
+
+// ApiToken is a long-lived token for integrations. Only the token hash is stored.
+type ApiToken struct {
+	ID      string    `json:"id"`
+	Name    string    `json:"name"`
+	Scope   Scope     `json:"scope"`
+	Hash    string    `json:"hash,omitempty"`
+	Created time.Time `json:"created"`
+}
+
+func (a *auth) getApiTokens() []ApiToken {
+	var res []ApiToken
+	if s, err := a.settings.String(keys.ApiTokens); err == nil && s != "" {
+		_ = json.Unmarshal([]byte(s), &res)
+	}
+	return res
+}
+
+func (a *auth) setApiTokens(tokens []ApiToken) error {
+	b, err := json.Marshal(tokens)
+	if err != nil {
+		return err
+	}
+	a.settings.SetString(keys.ApiTokens, string(b))
+	return nil
+}
+
+func hashApiToken(token string) string {
+	hash := sha256.Sum256([]byte(token))
+	return hex.EncodeToString(hash[:])
+}
+
+// ApiTokens returns all api tokens excluding token hashes
+func (a *auth) ApiTokens() []ApiToken {
+	res := a.getApiTokens()
+	for i := range res {
+		res[i].Hash = ""
+	}
+	return res
+}
+
+// CreateApiToken creates an api token with the given scope. The token is only returned once.
+func (a *auth) CreateApiToken(name string, scope Scope) (string, ApiToken, error) {
+	if !userNameRE.MatchString(name) {
+		return "", ApiToken{}, fmt.Errorf("invalid token name: %s", name)
+	}
+
+	if _, ok := scopeNames[scope]; !ok {
+		return "", ApiToken{}, fmt.Errorf("invalid scope: %d", scope)
+	}
+
+	tokens := a.getApiTokens()
+	if slices.ContainsFunc(tokens, func(t ApiToken) bool {
+		return strings.EqualFold(t.Name, name)
+	}) {
+		return "", ApiToken{}, fmt.Errorf("token already exists: %s", name)
+	}
+
+	id, err := a.generateRandomKey(4)
+	if err != nil {
+		return "", ApiToken{}, err
+	}
+
+	secret, err := a.generateRandomKey(32)
+	if err != nil {
+		return "", ApiToken{}, err
+	}
+
+	token := apiTokenPrefix + secret
+
+	res := ApiToken{
+		ID:      id,
+		Name:    name,
+		Scope:   scope,
+		Hash:    hashApiToken(token),
+		Created: time.Now(),
+	}
+
+	if err := a.setApiTokens(append(tokens, res)); err != nil {
+		return "", ApiToken{}, err
+	}
+
+	res.Hash = ""
+
+	return token, res, nil
+}
+
+// RevokeApiToken removes the api token with the given id
+func (a *auth) RevokeApiToken(id string) error {
+	tokens := a.getApiTokens()
+
+	res := slices.DeleteFunc(slices.Clone(tokens), func(t ApiToken) bool {
+		return t.ID == id
+	})
+
+	if len(res) == len(tokens) {
+		return fmt.Errorf("token not found: %s", id)
+	}
+
+	return a.setApiTokens(res)
+}
+
+// validateApiToken returns the matching api token
+func (a *auth) validateApiToken(token string) (ApiToken, error) {
+	hash := hashApiToken(token)
+
+	for _, t := range a.getApiTokens() {
+		if subtle.ConstantTimeCompare([]byte(t.Hash), []byte(hash)) == 1 {
+			return t, nil
+		}
+	}
+
+	return ApiToken{}, errors.New("invalid api token")
+}
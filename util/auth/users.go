@@ -0,0 +1,157 @@
+package auth
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"regexp"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/evcc-io/evcc/core/keys"
+	"golang.org/x/crypto/bcrypt"
+)
+
+var userNameRE = regexp.MustCompile(`^[a-zA-Z0-9_.@-]+$`)
+
+// User is a user account
+type User struct {
+	Name      string    `json:"name"`
+	Role      Role      `json:"role"`
+	Hash      string    `json:"hash,omitempty"`
+	NotBefore time.Time `json:"notBefore,omitzero"` // sessions issued before are revoked
+}
+
+func (a *auth) getUsers() []User {
+	var res []User
+	if s, err := a.settings.String(keys.Users); err == nil && s != "" {
+		_ = json.Unmarshal([]byte(s), &res)
+	}
+	return res
+}
+
+func (a *auth) setUsers(users []User) error {
+	b, err := json.Marshal(users)
+	if err != nil {
+		return err
+	}
+	a.settings.SetString(keys.Users, string(b))
+	return nil
+}
+
+func (a *auth) getUser(name string) (User, bool) {
+	for _, u := range a.getUsers() {
+		if strings.EqualFold(u.Name, name) {
+			return u, true
+		}
+	}
+	return User{}, false
+}
+
+// HasUsers checks if additional user accounts are configured
+func (a *auth) HasUsers() bool {
+	return len(a.getUsers()) > 0
+}
+
+// Users returns all user accounts excluding password hashes
+func (a *auth) Users() []User {
+	res := a.getUsers()
+	for i := range res {
+		res[i].Hash = ""
+	}
+	return res
+}
+
+// SetUser creates or updates a user account. Empty password keeps the existing password.
+func (a *auth) SetUser(name, password string, role Role) error {
+	if strings.EqualFold(name, admin) {
+		return errors.New("reserved user name")
+	}
+
+	if !userNameRE.MatchString(name) {
+		return fmt.Errorf("invalid user name: %s", name)
+	}
+
+	if _, ok := roleNames[role]; !ok {
+		return fmt.Errorf("invalid role: %d", role)
+	}
+
+	users := a.getUsers()
+	idx := slices.IndexFunc(users, func(u User) bool {
+		return strings.EqualFold(u.Name, name)
+	})
+
+	if idx < 0 {
+		if password == "" {
+			return errors.New("password cannot be empty")
+		}
+
+		users = append(users, User{Name: name})
+		idx = len(users) - 1
+	}
+
+	if password != "" {
+		hashed, err := a.hashPassword(password)
+		if err != nil {
+			return err
+		}
+
+		users[idx].Hash = hashed
+		users[idx].NotBefore = time.Now()
+	}
+
+	users[idx].Role = role
+
+	return a.setUsers(users)
+}
+
+// RemoveUser removes a user account
+func (a *auth) RemoveUser(name string) error {
+	users := a.getUsers()
+
+	res := slices.DeleteFunc(slices.Clone(users), func(u User) bool {
+		return strings.EqualFold(u.Name, name)
+	})
+
+	if len(res) == len(users) {
+		return fmt.Errorf("user not found: %s", name)
+	}
+
+	return a.setUsers(res)
+}
+
+// RevokeSessions invalidates all sessions of the user. Revoking admin sessions logs out all users.
+func (a *auth) RevokeSessions(name string) error {
+	if strings.EqualFold(name, admin) {
+		a.settings.SetString(keys.JwtSecret, "")
+		return nil
+	}
+
+	users := a.getUsers()
+	idx := slices.IndexFunc(users, func(u User) bool {
+		return strings.EqualFold(u.Name, name)
+	})
+
+	if idx < 0 {
+		return fmt.Errorf("user not found: %s", name)
+	}
+
+	users[idx].NotBefore = time.Now()
+
+	return a.setUsers(users)
+}
+
+// IsUserPasswordValid checks the user's password and returns the user's role
+func (a *auth) IsUserPasswordValid(name, password string) (Role, bool) {
+	if name == "" || strings.EqualFold(name, admin) {
+		return RoleAdmin, a.IsAdminPasswordValid(password)
+	}
+
+	u, ok := a.getUser(name)
+	if !ok || u.Hash == "" {
+		return 0, false
+	}
+
+	return u.Role, bcrypt.CompareHashAndPassword([]byte(u.Hash), []byte(password)) == nil
+}
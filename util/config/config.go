@@ -77,7 +77,7 @@ func (d *Config) Delete() error {
 }
 
 func Init() error {
-	return db.Instance.AutoMigrate(new(Config))
+	return db.AutoMigrate(new(Config))
 }
 
 // NameForID returns a unique config name for the given id
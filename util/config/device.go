@@ -1,6 +1,9 @@
 package config
 
-import "sync"
+import (
+	"io"
+	"sync"
+)
 
 type Device[T any] interface {
 	Config() Named
@@ -78,11 +81,15 @@ func (d *configurableDevice[T]) Properties() Properties {
 
 func (d *configurableDevice[T]) Update(config map[string]any, instance T, opt ...func(*Config)) error {
 	d.mu.Lock()
-	defer d.mu.Unlock()
 	if err := d.config.Update(config, opt...); err != nil {
+		d.mu.Unlock()
 		return err
 	}
+	old := d.instance
 	d.instance = instance
+	d.mu.Unlock()
+
+	closeInstance(old)
 	return nil
 }
 
@@ -91,3 +98,10 @@ func (d *configurableDevice[T]) Delete() error {
 	defer d.mu.Unlock()
 	return d.config.Delete()
 }
+
+// closeInstance releases the resources held by a replaced or deleted instance
+func closeInstance(instance any) {
+	if c, ok := instance.(io.Closer); ok {
+		_ = c.Close()
+	}
+}
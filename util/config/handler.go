@@ -17,6 +17,7 @@ type Operation string
 const (
 	OpAdd    Operation = "add"
 	OpDelete Operation = "del"
+	OpUpdate Operation = "upd"
 )
 
 func (cp *handler[T]) Subscribe(fn func(Operation, Device[T])) {
@@ -54,7 +55,7 @@ func (cp *handler[T]) Add(dev Device[T]) error {
 	return nil
 }
 
-// Delete deletes device
+// Delete deletes device and closes its instance
 func (cp *handler[T]) Delete(name string) error {
 	cp.mu.Lock()
 
@@ -64,6 +65,40 @@ func (cp *handler[T]) Delete(name string) error {
 			cp.mu.Unlock()
 
 			bus.Publish(cp.topic, OpDelete, dev)
+			closeInstance(dev.Instance())
+			return nil
+		}
+	}
+	cp.mu.Unlock()
+
+	return fmt.Errorf("not found: %s", name)
+}
+
+// Update notifies subscribers that the device's config or instance has changed
+func (cp *handler[T]) Update(name string) error {
+	dev, err := cp.ByName(name)
+	if err != nil {
+		return err
+	}
+
+	bus.Publish(cp.topic, OpUpdate, dev)
+
+	return nil
+}
+
+// Replace replaces the device of the same name, notifies subscribers and closes the replaced instance
+func (cp *handler[T]) Replace(dev Device[T]) error {
+	name := dev.Config().Name
+
+	cp.mu.Lock()
+
+	for i, d := range cp.devices {
+		if name == d.Config().Name {
+			cp.devices[i] = dev
+			cp.mu.Unlock()
+
+			bus.Publish(cp.topic, OpUpdate, dev)
+			closeInstance(d.Instance())
 			return nil
 		}
 	}
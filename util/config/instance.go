@@ -33,6 +33,8 @@ type Handler[T any] interface {
 	Devices() []Device[T]
 	Add(dev Device[T]) error
 	Delete(name string) error
+	Update(name string) error
+	Replace(dev Device[T]) error
 	ByName(name string) (Device[T], error)
 }
 
@@ -48,13 +48,19 @@ func FormatValue(format string, val any) string {
 
 // ReplaceFormatted replaces all occurrences of ${key} with formatted val from the kv map
 func ReplaceFormatted(s string, kv map[string]any) (string, error) {
+	return ReplaceFormattedFuncs(s, kv, nil)
+}
+
+// ReplaceFormattedFuncs is ReplaceFormatted with additional template functions
+func ReplaceFormattedFuncs(s string, kv map[string]any, funcs template.FuncMap) (string, error) {
 	// Enhanced golang template logic
 	tpl, err := template.New("base").
 		Funcs(sprig.TxtFuncMap()).
 		Funcs(map[string]any{
 			"timeRound": timeRound,
 			"addDate":   addDate,
-		}).Parse(s)
+		}).
+		Funcs(funcs).Parse(s)
 	if err != nil {
 		return s, err
 	}
@@ -0,0 +1,250 @@
+package util
+
+import (
+	"errors"
+	"fmt"
+	"net"
+	"net/netip"
+	"slices"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// listenHosts are the addresses all listeners are bound to, empty for all interfaces
+var listenHosts []string
+
+// SetListenHosts configures the addresses all listeners are bound to.
+// Hosts are IPv4 or IPv6 addresses or interface names. Empty hosts bind all interfaces.
+func SetListenHosts(hosts []string) error {
+	res, err := ResolveHosts(hosts)
+	if err != nil {
+		return err
+	}
+
+	listenHosts = res
+	return nil
+}
+
+// ResolveHosts converts addresses and interface names into IP addresses.
+// Link-local IPv6 addresses of interfaces are qualified with the interface's zone.
+func ResolveHosts(hosts []string) ([]string, error) {
+	var res []string
+
+	for _, host := range hosts {
+		host = strings.TrimSuffix(strings.TrimPrefix(strings.TrimSpace(host), "["), "]")
+		if host == "" {
+			continue
+		}
+
+		if addr, err := netip.ParseAddr(host); err == nil {
+			res = append(res, addr.String())
+			continue
+		}
+
+		iface, err := net.InterfaceByName(host)
+		if err != nil {
+			return nil, fmt.Errorf("invalid address or interface: %s", host)
+		}
+
+		addrs, err := iface.Addrs()
+		if err != nil {
+			return nil, err
+		}
+
+		var found bool
+		for _, a := range addrs {
+			ipnet, ok := a.(*net.IPNet)
+			if !ok {
+				continue
+			}
+
+			addr, ok := netip.AddrFromSlice(ipnet.IP)
+			if !ok {
+				continue
+			}
+
+			addr = addr.Unmap()
+			if addr.Is6() && addr.IsLinkLocalUnicast() {
+				addr = addr.WithZone(iface.Name)
+			}
+
+			res = append(res, addr.String())
+			found = true
+		}
+
+		if !found {
+			return nil, fmt.Errorf("interface has no addresses: %s", host)
+		}
+	}
+
+	return slices.Compact(res), nil
+}
+
+// SourceAddr returns the address used for outgoing connections from the given hosts.
+// Global unicast addresses are preferred over link-local addresses of the same interface.
+func SourceAddr(hosts []string) (netip.Addr, error) {
+	var res netip.Addr
+
+	for _, host := range hosts {
+		addr, err := netip.ParseAddr(host)
+		if err != nil {
+			return netip.Addr{}, err
+		}
+
+		if addr.IsGlobalUnicast() {
+			return addr, nil
+		}
+
+		if !res.IsValid() {
+			res = addr
+		}
+	}
+
+	if !res.IsValid() {
+		return res, errors.New("no address")
+	}
+
+	return res, nil
+}
+
+// ListenAddrs returns the listen addresses for the given port
+func ListenAddrs(port int) []string {
+	if len(listenHosts) == 0 {
+		return []string{":" + strconv.Itoa(port)}
+	}
+
+	res := make([]string, 0, len(listenHosts))
+	for _, host := range listenHosts {
+		res = append(res, net.JoinHostPort(host, strconv.Itoa(port)))
+	}
+
+	return res
+}
+
+// IsListenAddr checks if the local address of a connection matches the configured listen hosts.
+// Used for servers that cannot be bound to specific addresses.
+func IsListenAddr(addr net.Addr) bool {
+	if len(listenHosts) == 0 {
+		return true
+	}
+
+	return MatchAddr(addr, listenHosts)
+}
+
+// MatchAddr checks if the ip of a tcp address matches any of the given ip addresses
+func MatchAddr(addr net.Addr, hosts []string) bool {
+	tcp, ok := addr.(*net.TCPAddr)
+	if !ok {
+		return false
+	}
+
+	ip, ok := netip.AddrFromSlice(tcp.IP)
+	if !ok {
+		return false
+	}
+
+	ip = ip.Unmap().WithZone(tcp.Zone)
+
+	return slices.ContainsFunc(hosts, func(host string) bool {
+		addr, err := netip.ParseAddr(host)
+		return err == nil && addr == ip
+	})
+}
+
+// Listen opens a tcp listener for the given port on all configured listen hosts
+func Listen(port int) (net.Listener, error) {
+	var res []net.Listener
+
+	for _, addr := range ListenAddrs(port) {
+		ln, err := net.Listen("tcp", addr)
+		if err != nil {
+			for _, ln := range res {
+				ln.Close()
+			}
+			return nil, err
+		}
+
+		res = append(res, ln)
+	}
+
+	if len(res) == 1 {
+		return res[0], nil
+	}
+
+	return newMultiListener(res), nil
+}
+
+// multiListener accepts connections from multiple listeners
+type multiListener struct {
+	listeners []net.Listener
+	conns     chan net.Conn
+	done      chan struct{}
+	once      sync.Once
+}
+
+func newMultiListener(listeners []net.Listener) *multiListener {
+	ml := &multiListener{
+		listeners: listeners,
+		conns:     make(chan net.Conn),
+		done:      make(chan struct{}),
+	}
+
+	for _, ln := range listeners {
+		go ml.accept(ln)
+	}
+
+	return ml
+}
+
+func (ml *multiListener) accept(ln net.Listener) {
+	for {
+		conn, err := ln.Accept()
+		if err != nil {
+			if errors.Is(err, net.ErrClosed) {
+				return
+			}
+
+			// back off on temporary errors
+			time.Sleep(10 * time.Millisecond)
+			continue
+		}
+
+		select {
+		case ml.conns <- conn:
+		case <-ml.done:
+			conn.Close()
+			return
+		}
+	}
+}
+
+// Accept implements net.Listener
+func (ml *multiListener) Accept() (net.Conn, error) {
+	select {
+	case conn := <-ml.conns:
+		return conn, nil
+	case <-ml.done:
+		return nil, net.ErrClosed
+	}
+}
+
+// Close implements net.Listener
+func (ml *multiListener) Close() error {
+	var err error
+
+	ml.once.Do(func() {
+		close(ml.done)
+		for _, ln := range ml.listeners {
+			err = errors.Join(err, ln.Close())
+		}
+	})
+
+	return err
+}
+
+// Addr implements net.Listener and returns the first listener's address
+func (ml *multiListener) Addr() net.Addr {
+	return ml.listeners[0].Addr()
+}
@@ -0,0 +1,66 @@
+package util
+
+import (
+	"net"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestResolveHosts(t *testing.T) {
+	res, err := ResolveHosts([]string{"127.0.0.1", "[::1]", " ::1 ", ""})
+	require.NoError(t, err)
+	assert.Equal(t, []string{"127.0.0.1", "::1"}, res)
+
+	_, err = ResolveHosts([]string{"no-such-interface"})
+	assert.Error(t, err)
+}
+
+func TestSourceAddr(t *testing.T) {
+	res, err := SourceAddr([]string{"fe80::1%eth0", "2001:db8::1", "192.168.0.1"})
+	require.NoError(t, err)
+	assert.Equal(t, "2001:db8::1", res.String())
+
+	res, err = SourceAddr([]string{"fe80::1%eth0"})
+	require.NoError(t, err)
+	assert.Equal(t, "fe80::1%eth0", res.String())
+
+	_, err = SourceAddr(nil)
+	assert.Error(t, err)
+}
+
+func TestListen(t *testing.T) {
+	defer func() { listenHosts = nil }()
+
+	assert.Equal(t, []string{":7070"}, ListenAddrs(7070))
+	assert.True(t, IsListenAddr(&net.TCPAddr{IP: net.ParseIP("192.168.0.1")}))
+
+	require.NoError(t, SetListenHosts([]string{"127.0.0.1", "127.0.0.2"}))
+	assert.Equal(t, []string{"127.0.0.1:7070", "127.0.0.2:7070"}, ListenAddrs(7070))
+	assert.True(t, IsListenAddr(&net.TCPAddr{IP: net.ParseIP("127.0.0.2")}))
+	assert.False(t, IsListenAddr(&net.TCPAddr{IP: net.ParseIP("192.168.0.1")}))
+
+	ln, err := Listen(0)
+	require.NoError(t, err)
+	defer ln.Close()
+
+	ml, ok := ln.(*multiListener)
+	require.True(t, ok)
+
+	// connections on all listeners are accepted
+	for _, l := range ml.listeners {
+		conn, err := net.Dial("tcp", l.Addr().String())
+		require.NoError(t, err)
+		defer conn.Close()
+
+		accepted, err := ln.Accept()
+		require.NoError(t, err)
+		assert.Equal(t, conn.LocalAddr().String(), accepted.RemoteAddr().String())
+		accepted.Close()
+	}
+
+	require.NoError(t, ln.Close())
+	_, err = ln.Accept()
+	assert.ErrorIs(t, err, net.ErrClosed)
+}
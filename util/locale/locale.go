@@ -14,7 +14,10 @@ import (
 	"golang.org/x/text/language"
 )
 
-type Config = i18n.LocalizeConfig
+type (
+	Config  = i18n.LocalizeConfig
+	Message = i18n.Message
+)
 
 var (
 	Locale internal.ContextKey
@@ -49,20 +52,25 @@ func Init() error {
 			Sessions struct {
 				CSV map[string]string `json:"csv"`
 			} `json:"sessions"`
+			Backend map[string]any `json:"backend"`
 		}
 
 		if err := json.Unmarshal(b, &s); err != nil {
 			return err
 		}
 
-		if len(s.Sessions.CSV) > 0 {
-			m := make([]*i18n.Message, 0, len(s.Sessions.CSV))
-			for k, v := range s.Sessions.CSV {
-				m = append(m, &i18n.Message{
-					ID:    "sessions.csv." + k,
-					Other: v,
-				})
-			}
+		m := make([]*i18n.Message, 0, len(s.Sessions.CSV))
+		for k, v := range s.Sessions.CSV {
+			m = append(m, &i18n.Message{
+				ID:    "sessions.csv." + k,
+				Other: v,
+			})
+		}
+
+		// backend-generated texts
+		m = append(m, flatten("backend", s.Backend)...)
+
+		if len(m) > 0 {
 
 			languageTag := language.Make(strings.TrimSuffix(d.Name(), filepath.Ext(d.Name())))
 			if err := Bundle.AddMessages(languageTag, m...); err != nil {
@@ -82,3 +90,40 @@ func Init() error {
 
 	return nil
 }
+
+// flatten converts nested messages into dot-separated message ids
+func flatten(prefix string, messages map[string]any) []*i18n.Message {
+	var res []*i18n.Message
+
+	for k, v := range messages {
+		id := prefix + "." + k
+
+		switch v := v.(type) {
+		case string:
+			res = append(res, &i18n.Message{ID: id, Other: v})
+		case map[string]any:
+			res = append(res, flatten(id, v)...)
+		}
+	}
+
+	return res
+}
+
+// Localize returns the message in the given language, falling back to the system language
+// and the message's english default. Placeholders like {name} are replaced by data.
+func Localize(lang string, msg *Message, data map[string]any) string {
+	res := msg.Other
+
+	if Bundle != nil {
+		localizer := i18n.NewLocalizer(Bundle, lang, Language)
+		if s, err := localizer.Localize(&Config{DefaultMessage: msg}); err == nil {
+			res = s
+		}
+	}
+
+	for k, v := range data {
+		res = strings.ReplaceAll(res, "{"+k+"}", fmt.Sprint(v))
+	}
+
+	return res
+}
@@ -14,3 +14,21 @@ func TestLocales(t *testing.T) {
 	require.NoError(t, Init())
 	assert.Less(t, 1, len(Bundle.LanguageTags()))
 }
+
+func TestLocalize(t *testing.T) {
+	msg := &Message{ID: "backend.summary.unknownVehicle", Other: "Unknown {what}"}
+
+	// not initialized
+	Bundle = nil
+	assert.Equal(t, "Unknown vehicle", Localize("de", msg, map[string]any{"what": "vehicle"}))
+
+	assets.I18n = os.DirFS("../../i18n")
+	require.NoError(t, Init())
+
+	assert.Equal(t, "Unbekanntes Fahrzeug", Localize("de", msg, nil))
+	assert.Equal(t, "Unknown vehicle", Localize("en", msg, nil))
+
+	assert.Equal(t, "Ladepunkt 2", Localize("de", &Message{ID: "backend.telegram.loadpoint", Other: "Loadpoint {id}"}, map[string]any{"id": 2}))
+	// unknown message
+	assert.Equal(t, "foo", Localize("de", &Message{ID: "backend.foo", Other: "foo"}, nil))
+}
@@ -1,13 +1,19 @@
 package util
 
 import (
+	"encoding/json"
+	"errors"
+	"fmt"
 	"io"
 	"log"
 	"os"
 	"regexp"
+	"slices"
 	"strconv"
 	"strings"
 	"sync"
+	"sync/atomic"
+	"time"
 
 	"github.com/evcc-io/evcc/util/logstash"
 	jww "github.com/spf13/jwalterweatherman"
@@ -21,6 +27,12 @@ var (
 
 	// OutThreshold is the default console log level
 	OutThreshold = jww.LevelInfo
+
+	// jsonLogs enables structured console output
+	jsonLogs atomic.Bool
+
+	// noLogHistory disables capturing log messages in memory for the log viewer
+	noLogHistory bool
 )
 
 // LogAreaPadding of log areas
@@ -30,7 +42,8 @@ var LogAreaPadding = 6
 type Logger struct {
 	*jww.Notepad
 	*Redactor
-	lp int
+	area string
+	lp   int
 }
 
 // NewLogger creates a logger with the given log area and adds it to the registry
@@ -58,14 +71,24 @@ func newLogger(area string, lp int) *Logger {
 
 	level := logLevelForArea(area)
 	redactor := new(Redactor)
+	console := &consoleWriter{
+		prefix: "[" + padded + "] ",
+		area:   area,
+		lp:     lp,
+	}
 	notepad := jww.NewNotepad(
 		level, jww.LevelTrace,
-		&redactWriter{os.Stdout, redactor}, &redactWriter{logstash.DefaultHandler, redactor},
+		&redactWriter{console, redactor}, &redactWriter{logstash.DefaultHandler, redactor},
 		padded, log.Ldate|log.Ltime)
 
+	if noLogHistory {
+		disableHistory(notepad)
+	}
+
 	logger := &Logger{
 		Notepad:  notepad,
 		Redactor: redactor,
+		area:     area,
 		lp:       lp,
 	}
 
@@ -79,6 +102,11 @@ func newLogger(area string, lp int) *Logger {
 	return logger
 }
 
+// Area returns the logger's log area
+func (l *Logger) Area() string {
+	return l.area
+}
+
 // Redact adds items for redaction
 func (l *Logger) Redact(items ...string) *Logger {
 	l.Redactor.Redact(items...)
@@ -117,6 +145,127 @@ func LogLevel(defaultLevel string, areaLevels map[string]string) {
 	})
 }
 
+// SetLogLevel changes the console log level of the area at runtime. An empty level
+// resets the area to the default level.
+func SetLogLevel(area, level string) error {
+	loggersMux.Lock()
+	defer loggersMux.Unlock()
+
+	area = strings.ToLower(area)
+
+	if level == "" {
+		delete(levels, area)
+	} else {
+		if !slices.Contains([]string{"fatal", "error", "warn", "info", "debug", "trace"}, strings.ToLower(level)) {
+			return fmt.Errorf("invalid log level: %s", level)
+		}
+		levels[area] = logstash.LogLevelToThreshold(level)
+	}
+
+	for name, logger := range loggers {
+		if strings.ToLower(name) != area {
+			continue
+		}
+
+		logger.SetStdoutThreshold(logLevelForArea(name))
+
+		// changing the threshold re-creates the level loggers
+		if uiChan != nil {
+			captureLogger(logger)
+		}
+	}
+
+	return nil
+}
+
+// LogLevels returns the console log level of all areas
+func LogLevels() map[string]string {
+	loggersMux.Lock()
+	defer loggersMux.Unlock()
+
+	res := make(map[string]string, len(loggers))
+	for name, logger := range loggers {
+		res[name] = strings.ToLower(logger.GetStdoutThreshold().String())
+	}
+
+	return res
+}
+
+// LogFormat sets the console log format, either text or json
+func LogFormat(format string) error {
+	switch strings.ToLower(format) {
+	case "", "text":
+		jsonLogs.Store(false)
+	case "json":
+		jsonLogs.Store(true)
+	default:
+		return fmt.Errorf("invalid log format: %s", format)
+	}
+
+	return nil
+}
+
+// consoleWriter writes log lines to stdout as text or json
+type consoleWriter struct {
+	prefix string
+	area   string
+	lp     int
+}
+
+func (w *consoleWriter) Write(p []byte) (int, error) {
+	if !jsonLogs.Load() {
+		return os.Stdout.Write(p)
+	}
+
+	b, err := w.json(p)
+	if err != nil {
+		return os.Stdout.Write(p)
+	}
+
+	if _, err := os.Stdout.Write(b); err != nil {
+		return 0, err
+	}
+
+	return len(p), nil
+}
+
+// json converts a log line into a json object
+func (w *consoleWriter) json(p []byte) ([]byte, error) {
+	s, ok := strings.CutPrefix(string(p), w.prefix)
+	if !ok {
+		return nil, errors.New("invalid prefix")
+	}
+
+	// level, date and time
+	level, s, _ := strings.Cut(s, " ")
+	if len(s) < 20 {
+		return nil, errors.New("invalid timestamp")
+	}
+
+	ts, err := time.ParseInLocation("2006/01/02 15:04:05", s[:19], time.Local)
+	if err != nil {
+		return nil, err
+	}
+
+	entry := struct {
+		Time      time.Time `json:"time"`
+		Level     string    `json:"level"`
+		Area      string    `json:"area"`
+		Loadpoint int       `json:"lp,omitempty"`
+		Message   string    `json:"msg"`
+	}{
+		Time:      ts,
+		Level:     strings.ToLower(level),
+		Area:      w.area,
+		Loadpoint: w.lp,
+		Message:   strings.TrimSuffix(s[20:], "\n"),
+	}
+
+	b, err := json.Marshal(entry)
+
+	return append(b, '\n'), err
+}
+
 var uiChan chan<- Param
 
 type uiWriter struct {
@@ -150,6 +299,29 @@ func (w *uiWriter) Write(p []byte) (n int, err error) {
 
 // CaptureLogs appends uiWriter to relevant log levels for
 // loggers created before uiChan is initialized
+// DisableLogHistory stops capturing log messages in memory. Messages below the console
+// log level are no longer formatted at all.
+func DisableLogHistory() {
+	loggersMux.Lock()
+	defer loggersMux.Unlock()
+
+	noLogHistory = true
+
+	for _, logger := range loggers {
+		disableHistory(logger.Notepad)
+
+		// changing the output re-creates the level loggers
+		if uiChan != nil {
+			captureLogger(logger)
+		}
+	}
+}
+
+func disableHistory(n *jww.Notepad) {
+	n.SetLogOutput(io.Discard)
+	n.SetLogThreshold(jww.LevelFatal)
+}
+
 func CaptureLogs(c chan<- Param) {
 	loggersMux.Lock()
 	defer loggersMux.Unlock()
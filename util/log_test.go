@@ -1,6 +1,8 @@
 package util
 
 import (
+	"encoding/json"
+	"strings"
 	"testing"
 
 	"github.com/evcc-io/evcc/util/logstash"
@@ -14,3 +16,32 @@ func TestLogger(t *testing.T) {
 
 	require.Len(t, logstash.All(nil, jww.LevelTrace, 0), 1)
 }
+
+func TestLogLevels(t *testing.T) {
+	NewLogger("flaky")
+
+	require.NoError(t, SetLogLevel("flaky", "trace"))
+	require.Equal(t, "trace", LogLevels()["flaky"])
+
+	require.NoError(t, SetLogLevel("flaky", ""))
+	require.Equal(t, strings.ToLower(OutThreshold.String()), LogLevels()["flaky"])
+
+	require.Error(t, SetLogLevel("flaky", "verbose"))
+}
+
+func TestLogJson(t *testing.T) {
+	w := &consoleWriter{prefix: "[lp-1  ] ", area: "lp-1", lp: 1}
+
+	b, err := w.json([]byte("[lp-1  ] WARN 2026/10/15 12:34:56 charger: timeout\n"))
+	require.NoError(t, err)
+
+	var res map[string]any
+	require.NoError(t, json.Unmarshal(b, &res))
+	require.Equal(t, "warn", res["level"])
+	require.Equal(t, "lp-1", res["area"])
+	require.Equal(t, float64(1), res["lp"])
+	require.Equal(t, "charger: timeout", res["msg"])
+
+	_, err = w.json([]byte("unexpected"))
+	require.Error(t, err)
+}
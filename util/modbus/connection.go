@@ -1,9 +1,11 @@
 package modbus
 
 import (
+	"context"
 	"fmt"
 	"time"
 
+	"github.com/evcc-io/evcc/util/supervisor"
 	"github.com/volkszaehler/mbmd/meters"
 )
 
@@ -14,6 +16,7 @@ type Connection struct {
 	slaveID uint8 // duplicated from meters.Connection
 	logical meters.Logger
 	delay   time.Duration
+	ctx     context.Context
 }
 
 func (c *Connection) Addr() string {
@@ -28,6 +31,14 @@ func (c *Connection) Delay(delay time.Duration) {
 	c.delay = delay
 }
 
+// WithContext returns a copy of the connection that fails requests once the context is cancelled.
+// Requests already sent cannot be aborted.
+func (c *Connection) WithContext(ctx context.Context) *Connection {
+	res := *c
+	res.ctx = ctx
+	return &res
+}
+
 func (c *Connection) Clone(slaveID uint8) *Connection {
 	return &Connection{
 		slaveID:    slaveID,
@@ -51,6 +62,12 @@ func (c *Connection) Timeout(timeout time.Duration) {
 }
 
 func (c *Connection) exec(fun func() ([]byte, error)) ([]byte, error) {
+	if c.ctx != nil && c.ctx.Err() != nil {
+		return nil, c.ctx.Err()
+	}
+
+	defer supervisor.Track("modbus " + c.Addr())()
+
 	return c.WithLogger(c.logical, func() ([]byte, error) {
 		time.Sleep(c.delay)
 
@@ -10,6 +10,7 @@ import (
 	"time"
 
 	"github.com/evcc-io/evcc/util"
+	"github.com/evcc-io/evcc/util/supervisor"
 	"github.com/prometheus/client_golang/prometheus"
 )
 
@@ -112,7 +113,9 @@ func (r *roundTripper) RoundTrip(req *http.Request) (*http.Response, error) {
 	}
 
 	startTime := time.Now()
+	done := supervisor.Track("http " + req.URL.Host)
 	resp, err := r.base.RoundTrip(req)
+	done()
 
 	reqMetric.WithLabelValues(req.URL.Hostname()).Observe(time.Since(startTime).Seconds())
 
@@ -0,0 +1,135 @@
+// Package scheduler runs periodic device and service polls.
+//
+// Polls are staggered by randomly jittering their intervals, limited per host and
+// back off on consecutive errors. This avoids bursts of simultaneous requests against
+// cloud apis and gateways shared by multiple devices.
+package scheduler
+
+import (
+	"math/rand/v2"
+	"net/url"
+	"sync"
+	"time"
+)
+
+const (
+	// Jitter is the maximum relative deviation from the poll interval
+	Jitter = 0.1
+
+	// MaxBackoff is the maximum interval multiplier applied on consecutive errors
+	MaxBackoff = 4
+
+	// Concurrency is the default number of concurrent polls per host
+	Concurrency = 2
+)
+
+// Default is the default scheduler
+var Default = New(Concurrency)
+
+// Scheduler limits concurrent polls per host
+type Scheduler struct {
+	mu       sync.Mutex
+	limit    int
+	slowdown float64
+	hosts    map[string]chan struct{}
+}
+
+// New creates a scheduler allowing limit concurrent polls per host
+func New(limit int) *Scheduler {
+	return &Scheduler{
+		limit:    max(limit, 1),
+		slowdown: 1,
+		hosts:    make(map[string]chan struct{}),
+	}
+}
+
+// SetSlowdown multiplies all poll intervals by factor, e.g. for reducing load on constrained devices
+func (s *Scheduler) SetSlowdown(factor float64) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.slowdown = max(factor, 1)
+}
+
+func (s *Scheduler) interval(interval time.Duration) time.Duration {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return time.Duration(float64(interval) * s.slowdown)
+}
+
+// Run polls using the default scheduler
+func Run(uri string, interval time.Duration, fn func() error) {
+	Default.Run(uri, interval, fn)
+}
+
+// Host returns the host of the uri used for limiting concurrent polls.
+// If the uri has no host, the uri itself is used.
+func Host(uri string) string {
+	if u, err := url.Parse(uri); err == nil && u.Host != "" {
+		return u.Hostname()
+	}
+	return uri
+}
+
+// semaphore returns the host's semaphore
+func (s *Scheduler) semaphore(host string) chan struct{} {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	sem, ok := s.hosts[host]
+	if !ok {
+		sem = make(chan struct{}, s.limit)
+		s.hosts[host] = sem
+	}
+
+	return sem
+}
+
+// Do calls fn limited by the concurrency of the uri's host. Empty uris are not limited.
+func (s *Scheduler) Do(uri string, fn func() error) error {
+	if uri == "" {
+		return fn()
+	}
+
+	sem := s.semaphore(Host(uri))
+
+	sem <- struct{}{}
+	defer func() { <-sem }()
+
+	return fn()
+}
+
+// Run calls fn immediately and then periodically. It only returns if interval is not positive.
+func (s *Scheduler) Run(uri string, interval time.Duration, fn func() error) {
+	var failures int
+
+	for {
+		if err := s.Do(uri, fn); err != nil {
+			failures++
+		} else {
+			failures = 0
+		}
+
+		if interval <= 0 {
+			return
+		}
+
+		time.Sleep(Delay(s.interval(interval), failures))
+	}
+}
+
+// Delay returns the randomly jittered interval, multiplied for consecutive failures
+func Delay(interval time.Duration, failures int) time.Duration {
+	factor := 1
+	for range failures {
+		if factor >= MaxBackoff {
+			break
+		}
+		factor *= 2
+	}
+
+	jitter := 1 + Jitter*(2*rand.Float64()-1)
+
+	return time.Duration(float64(interval) * float64(factor) * jitter)
+}
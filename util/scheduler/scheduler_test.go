@@ -0,0 +1,71 @@
+package scheduler
+
+import (
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestDelay(t *testing.T) {
+	for _, tc := range []struct {
+		failures int
+		factor   float64
+	}{
+		{0, 1},
+		{1, 2},
+		{2, 4},
+		{5, MaxBackoff},
+	} {
+		for range 100 {
+			d := Delay(time.Minute, tc.failures)
+			assert.InDelta(t, tc.factor*float64(time.Minute), float64(d), tc.factor*Jitter*float64(time.Minute), "failures %d", tc.failures)
+		}
+	}
+}
+
+func TestSlowdown(t *testing.T) {
+	s := New(1)
+	assert.Equal(t, time.Minute, s.interval(time.Minute))
+
+	s.SetSlowdown(4)
+	assert.Equal(t, 4*time.Minute, s.interval(time.Minute))
+
+	s.SetSlowdown(0)
+	assert.Equal(t, time.Minute, s.interval(time.Minute), "never speed up")
+}
+
+func TestHost(t *testing.T) {
+	assert.Equal(t, "api.example.com", Host("https://api.example.com:443/v1/prices?foo=bar"))
+	assert.Equal(t, "192.0.2.1", Host("tcp://192.0.2.1:502"))
+	assert.Equal(t, "tibber", Host("tibber"))
+}
+
+func TestConcurrency(t *testing.T) {
+	s := New(2)
+
+	var running, peak atomic.Int32
+	var wg sync.WaitGroup
+
+	for range 10 {
+		wg.Go(func() {
+			_ = s.Do("https://api.example.com/a", func() error {
+				n := running.Add(1)
+				for {
+					p := peak.Load()
+					if n <= p || peak.CompareAndSwap(p, n) {
+						break
+					}
+				}
+				time.Sleep(time.Millisecond)
+				running.Add(-1)
+				return nil
+			})
+		})
+	}
+
+	wg.Wait()
+	assert.LessOrEqual(t, peak.Load(), int32(2))
+}
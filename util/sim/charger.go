@@ -0,0 +1,145 @@
+package sim
+
+import (
+	"time"
+
+	"github.com/evcc-io/evcc/api"
+)
+
+// Charger is a simulated charger with integrated meter
+type Charger struct {
+	env     *Environment
+	plugged string // vehicle name
+	enabled bool
+	current float64
+	phases  int
+	energy  float64 // kWh
+}
+
+var _ api.Charger = (*Charger)(nil)
+
+// vehicle returns the plugged vehicle. Lock must be held.
+func (c *Charger) vehicle() *Vehicle {
+	if c.plugged == "" {
+		return nil
+	}
+	return c.env.vehicles[c.plugged]
+}
+
+// power returns the charge power. Lock must be held.
+func (c *Charger) power() float64 {
+	if v := c.vehicle(); v == nil || !c.enabled || v.soc >= 100 {
+		return 0
+	}
+	return c.current * float64(c.phases) * Voltage
+}
+
+// advance integrates charged energy. Lock must be held.
+func (c *Charger) advance(dt time.Duration) {
+	energy := c.power() * dt.Hours() / 1e3
+	c.energy += energy
+
+	if v := c.vehicle(); v != nil && v.capacity > 0 {
+		v.soc = min(100, v.soc+100*energy/v.capacity)
+	}
+}
+
+// Plug connects the named vehicle
+func (c *Charger) Plug(vehicle string) {
+	c.env.mu.Lock()
+	defer c.env.mu.Unlock()
+
+	c.env.advance()
+	c.plugged = vehicle
+}
+
+// Unplug disconnects the vehicle
+func (c *Charger) Unplug() {
+	c.Plug("")
+}
+
+// Status implements the api.Charger interface
+func (c *Charger) Status() (api.ChargeStatus, error) {
+	c.env.mu.Lock()
+	defer c.env.mu.Unlock()
+
+	c.env.advance()
+
+	switch {
+	case c.vehicle() == nil:
+		return api.StatusA, nil
+	case c.power() > 0:
+		return api.StatusC, nil
+	default:
+		return api.StatusB, nil
+	}
+}
+
+// Enabled implements the api.Charger interface
+func (c *Charger) Enabled() (bool, error) {
+	c.env.mu.Lock()
+	defer c.env.mu.Unlock()
+
+	return c.enabled, nil
+}
+
+// Enable implements the api.Charger interface
+func (c *Charger) Enable(enable bool) error {
+	c.env.mu.Lock()
+	defer c.env.mu.Unlock()
+
+	c.env.advance()
+	c.enabled = enable
+
+	return nil
+}
+
+// MaxCurrent implements the api.Charger interface
+func (c *Charger) MaxCurrent(current int64) error {
+	return c.MaxCurrentMillis(float64(current))
+}
+
+var _ api.ChargerEx = (*Charger)(nil)
+
+// MaxCurrentMillis implements the api.ChargerEx interface
+func (c *Charger) MaxCurrentMillis(current float64) error {
+	c.env.mu.Lock()
+	defer c.env.mu.Unlock()
+
+	c.env.advance()
+	c.current = current
+
+	return nil
+}
+
+// Current returns the offered current
+func (c *Charger) Current() float64 {
+	c.env.mu.Lock()
+	defer c.env.mu.Unlock()
+
+	return c.current
+}
+
+var _ api.Meter = (*Charger)(nil)
+
+// CurrentPower implements the api.Meter interface
+func (c *Charger) CurrentPower() (float64, error) {
+	c.env.mu.Lock()
+	defer c.env.mu.Unlock()
+
+	c.env.advance()
+
+	return c.power(), nil
+}
+
+var _ api.MeterEnergy = (*Charger)(nil)
+
+// TotalEnergy implements the api.MeterEnergy interface
+func (c *Charger) TotalEnergy() (float64, error) {
+	c.env.mu.Lock()
+	defer c.env.mu.Unlock()
+
+	c.env.advance()
+
+	return c.energy, nil
+}
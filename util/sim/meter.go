@@ -0,0 +1,23 @@
+package sim
+
+import "github.com/evcc-io/evcc/api"
+
+// Meter is a simulated grid or pv meter
+type Meter func() float64
+
+var _ api.Meter = (Meter)(nil)
+
+// CurrentPower implements the api.Meter interface
+func (m Meter) CurrentPower() (float64, error) {
+	return m(), nil
+}
+
+// GridMeter returns the environment's grid meter
+func (e *Environment) GridMeter() Meter {
+	return e.GridPower
+}
+
+// PvMeter returns the environment's pv meter
+func (e *Environment) PvMeter() Meter {
+	return e.PvPower
+}
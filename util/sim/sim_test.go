@@ -0,0 +1,49 @@
+package sim
+
+import (
+	"testing"
+	"time"
+
+	"github.com/benbjohnson/clock"
+	"github.com/evcc-io/evcc/api"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCharging(t *testing.T) {
+	clock := clock.NewMock()
+	env := NewEnvironment(clock)
+	env.SetHomePower(500)
+	env.SetPvPower(2000)
+
+	c := env.NewCharger(1)
+	v := env.NewVehicle("ev", 10, 50)
+
+	status, err := c.Status()
+	require.NoError(t, err)
+	assert.Equal(t, api.StatusA, status)
+
+	c.Plug("ev")
+	require.NoError(t, c.MaxCurrent(10))
+	require.NoError(t, c.Enable(true))
+
+	status, _ = c.Status()
+	assert.Equal(t, api.StatusC, status)
+	assert.Equal(t, float64(500-2000+10*Voltage), env.GridPower())
+
+	clock.Add(time.Hour)
+
+	energy, _ := c.TotalEnergy()
+	assert.Equal(t, 2.3, energy)
+
+	soc, _ := v.Soc()
+	assert.InDelta(t, 73, soc, 1e-9)
+
+	// full vehicle stops charging
+	clock.Add(2 * time.Hour)
+	soc, _ = v.Soc()
+	assert.Equal(t, 100.0, soc)
+
+	status, _ = c.Status()
+	assert.Equal(t, api.StatusB, status)
+}
@@ -0,0 +1,65 @@
+package sim
+
+import (
+	"github.com/evcc-io/evcc/api"
+)
+
+// Vehicle is a simulated vehicle charged by simulated chargers
+type Vehicle struct {
+	env      *Environment
+	title    string
+	capacity float64 // kWh
+	soc      float64 // %
+}
+
+var _ api.Vehicle = (*Vehicle)(nil)
+
+// Soc implements the api.Vehicle interface
+func (v *Vehicle) Soc() (float64, error) {
+	v.env.mu.Lock()
+	defer v.env.mu.Unlock()
+
+	v.env.advance()
+
+	return v.soc, nil
+}
+
+// Capacity implements the api.Vehicle interface
+func (v *Vehicle) Capacity() float64 {
+	return v.capacity
+}
+
+// GetTitle implements the api.Vehicle interface
+func (v *Vehicle) GetTitle() string {
+	return v.title
+}
+
+// SetTitle implements the api.Vehicle interface
+func (v *Vehicle) SetTitle(title string) {
+	v.title = title
+}
+
+// Icon implements the api.Vehicle interface
+func (v *Vehicle) Icon() string {
+	return "car"
+}
+
+// Features implements the api.Vehicle interface
+func (v *Vehicle) Features() []api.Feature {
+	return nil
+}
+
+// Phases implements the api.Vehicle interface
+func (v *Vehicle) Phases() int {
+	return 0
+}
+
+// Identifiers implements the api.Vehicle interface
+func (v *Vehicle) Identifiers() []string {
+	return nil
+}
+
+// OnIdentified implements the api.Vehicle interface
+func (v *Vehicle) OnIdentified() api.ActionConfig {
+	return api.ActionConfig{}
+}
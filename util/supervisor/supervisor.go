@@ -0,0 +1,302 @@
+// Package supervisor restarts wedged device tasks.
+//
+// Device handlers run periodic tasks like heartbeats in background goroutines. A single
+// hanging Modbus read or http request would silently stop such a task forever. The
+// supervisor detects calls exceeding their timeout, cancels the task's context and
+// restarts it with exponential backoff once the blocking call has returned. Blocking
+// device calls outside of tasks are tracked and reported while wedged. Restarts and
+// wedged calls are reported by Status.
+package supervisor
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"slices"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/evcc-io/evcc/util"
+)
+
+const (
+	// MinTimeout is the minimum duration of a call before the task is considered wedged
+	MinTimeout = time.Minute
+
+	// MinBackoff is the delay before the first restart
+	MinBackoff = 5 * time.Second
+
+	// MaxBackoff is the maximum delay on consecutive restarts
+	MaxBackoff = 5 * time.Minute
+)
+
+// ErrWedged indicates a call that did not return within the timeout
+var ErrWedged = errors.New("wedged")
+
+// Default is the default supervisor
+var Default = New()
+
+// Every runs fn periodically using the default supervisor
+func Every(ctx context.Context, name string, interval time.Duration, fn func(context.Context) error) {
+	Default.Every(ctx, name, interval, fn)
+}
+
+// Now runs fn immediately and periodically using the default supervisor
+func Now(ctx context.Context, name string, interval time.Duration, fn func(context.Context) error) {
+	Default.Now(ctx, name, interval, fn)
+}
+
+// Track tracks a blocking call using the default supervisor
+func Track(name string) func() {
+	return Default.Track(name)
+}
+
+// Status is the supervised task's state
+type Status struct {
+	Name      string    `json:"name"`
+	Healthy   bool      `json:"healthy"`
+	Restarts  int       `json:"restarts"`
+	Restarted time.Time `json:"restarted,omitzero"`
+	Error     string    `json:"error,omitempty"` // last restart reason
+}
+
+type task struct {
+	Status
+	interval  time.Duration
+	immediate bool
+	fn        func(context.Context) error
+}
+
+type call struct {
+	name    string
+	started time.Time
+}
+
+// Supervisor runs and restarts periodic tasks
+type Supervisor struct {
+	mu    sync.Mutex
+	log   *util.Logger
+	tasks []*task
+	calls []*call
+
+	minTimeout, minBackoff, maxBackoff time.Duration
+}
+
+// New creates a supervisor
+func New() *Supervisor {
+	return &Supervisor{
+		log:        util.NewLogger("supervisor"),
+		minTimeout: MinTimeout,
+		minBackoff: MinBackoff,
+		maxBackoff: MaxBackoff,
+	}
+}
+
+// Every calls fn after each interval until the context is cancelled. The task is
+// restarted if a call takes longer than three intervals or MinTimeout, whichever is
+// longer, or if it panics. The context passed to fn is cancelled when the task is
+// restarted. Errors returned by fn are logged. If the context carries a logger, its
+// area prefixes the name.
+func (s *Supervisor) Every(ctx context.Context, name string, interval time.Duration, fn func(context.Context) error) {
+	s.start(ctx, name, interval, false, fn)
+}
+
+// Now is like Every but calls fn immediately when started or restarted
+func (s *Supervisor) Now(ctx context.Context, name string, interval time.Duration, fn func(context.Context) error) {
+	s.start(ctx, name, interval, true, fn)
+}
+
+func (s *Supervisor) start(ctx context.Context, name string, interval time.Duration, immediate bool, fn func(context.Context) error) {
+	if log, ok := ctx.Value(util.CtxLogger).(*util.Logger); ok {
+		name = log.Area() + " " + name
+	}
+
+	t := &task{
+		Status:    Status{Name: name, Healthy: true},
+		interval:  interval,
+		immediate: immediate,
+		fn:        fn,
+	}
+
+	s.mu.Lock()
+	s.tasks = append(s.tasks, t)
+	s.mu.Unlock()
+
+	go s.run(ctx, t)
+}
+
+// Track registers a blocking device call. The returned function must be called once the call has returned.
+// Calls are reported by Status while exceeding MinTimeout.
+func (s *Supervisor) Track(name string) func() {
+	c := &call{name: name, started: time.Now()}
+
+	s.mu.Lock()
+	s.calls = append(s.calls, c)
+	s.mu.Unlock()
+
+	return func() {
+		s.mu.Lock()
+		s.calls = slices.DeleteFunc(s.calls, func(cc *call) bool { return cc == c })
+		s.mu.Unlock()
+	}
+}
+
+// Status returns the state of all running tasks and wedged calls
+func (s *Supervisor) Status() []Status {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	res := make([]Status, 0, len(s.tasks))
+	for _, t := range s.tasks {
+		res = append(res, t.Status)
+	}
+
+	for _, c := range s.calls {
+		if d := time.Since(c.started); d > s.minTimeout && !slices.ContainsFunc(res, func(st Status) bool { return st.Name == c.name }) {
+			res = append(res, Status{
+				Name:  c.name,
+				Error: fmt.Sprintf("%v: no response for %v", ErrWedged, d.Truncate(time.Second)),
+			})
+		}
+	}
+
+	return res
+}
+
+// backoff returns the delay before restarting after consecutive failures
+func (s *Supervisor) backoff(failures int) time.Duration {
+	d := s.minBackoff
+	for range failures - 1 {
+		if d >= s.maxBackoff {
+			break
+		}
+		d *= 2
+	}
+
+	return min(d, s.maxBackoff)
+}
+
+func (s *Supervisor) run(ctx context.Context, t *task) {
+	defer func() {
+		s.mu.Lock()
+		s.tasks = slices.DeleteFunc(s.tasks, func(tt *task) bool { return tt == t })
+		s.mu.Unlock()
+	}()
+
+	timeout := max(3*t.interval, s.minTimeout)
+
+	var failures int
+	for {
+		started := time.Now()
+
+		done, err := s.supervise(ctx, t, timeout)
+		if ctx.Err() != nil {
+			return
+		}
+
+		// task has recovered since last restart
+		if time.Since(started) > s.maxBackoff {
+			failures = 0
+		}
+		failures++
+
+		delay := s.backoff(failures)
+		s.log.WARN.Printf("%s: %v, restarting in %v", t.Name, err, delay)
+
+		s.mu.Lock()
+		t.Healthy = false
+		t.Restarts++
+		t.Restarted = time.Now()
+		t.Error = err.Error()
+		s.mu.Unlock()
+
+		select {
+		case <-time.After(delay):
+		case <-ctx.Done():
+			return
+		}
+
+		// wedged call must return before restarting
+		select {
+		case <-done:
+		default:
+			s.log.WARN.Printf("%s: waiting for wedged call to return", t.Name)
+
+			select {
+			case <-done:
+			case <-ctx.Done():
+				return
+			}
+		}
+	}
+}
+
+// supervise runs the task until the context is cancelled or the task fails.
+// The returned channel is closed once the task's loop has returned.
+func (s *Supervisor) supervise(ctx context.Context, t *task, timeout time.Duration) (<-chan struct{}, error) {
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	// start of the running call
+	var busy atomic.Int64
+
+	errC := make(chan error, 1)
+	done := make(chan struct{})
+
+	go func() {
+		defer close(done)
+		errC <- s.loop(ctx, t, &busy)
+	}()
+
+	for tick := time.Tick(timeout / 4); ; {
+		select {
+		case err := <-errC:
+			return done, err
+
+		case <-tick:
+			if start := busy.Load(); start > 0 && time.Since(time.Unix(0, start)) > timeout {
+				return done, fmt.Errorf("%w: no response for %v", ErrWedged, timeout)
+			}
+		}
+	}
+}
+
+// loop calls fn periodically
+func (s *Supervisor) loop(ctx context.Context, t *task, busy *atomic.Int64) (err error) {
+	defer func() {
+		if r := recover(); r != nil {
+			err = fmt.Errorf("panic: %v", r)
+		}
+	}()
+
+	tick := time.NewTicker(t.interval)
+	defer tick.Stop()
+
+	for first := t.immediate; ; first = false {
+		if !first {
+			select {
+			case <-tick.C:
+			case <-ctx.Done():
+				return nil
+			}
+		}
+
+		busy.Store(time.Now().UnixNano())
+		err := t.fn(ctx)
+		busy.Store(0)
+
+		// abandoned after restart
+		if ctx.Err() != nil {
+			return nil
+		}
+
+		if err != nil {
+			s.log.ERROR.Printf("%s: %v", t.Name, err)
+		}
+
+		s.mu.Lock()
+		t.Healthy = true
+		s.mu.Unlock()
+	}
+}
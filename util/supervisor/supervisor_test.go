@@ -0,0 +1,168 @@
+package supervisor
+
+import (
+	"context"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func testSupervisor() *Supervisor {
+	s := New()
+	s.minTimeout = 50 * time.Millisecond
+	s.minBackoff = 10 * time.Millisecond
+	s.maxBackoff = time.Second
+	return s
+}
+
+func TestBackoff(t *testing.T) {
+	s := New()
+
+	for _, tc := range []struct {
+		failures int
+		delay    time.Duration
+	}{
+		{1, MinBackoff},
+		{2, 2 * MinBackoff},
+		{3, 4 * MinBackoff},
+		{100, MaxBackoff},
+	} {
+		assert.Equal(t, tc.delay, s.backoff(tc.failures), "failures %d", tc.failures)
+	}
+}
+
+func TestWedged(t *testing.T) {
+	s := testSupervisor()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	// wedged call returns once its context is cancelled
+	var calls atomic.Int32
+	s.Every(ctx, "heartbeat", time.Millisecond, func(ctx context.Context) error {
+		if calls.Add(1) == 1 {
+			<-ctx.Done()
+		}
+		return nil
+	})
+
+	require.Eventually(t, func() bool {
+		return calls.Load() > 2
+	}, time.Second, time.Millisecond, "restarted")
+
+	status := s.Status()
+	require.Len(t, status, 1)
+	assert.Equal(t, "heartbeat", status[0].Name)
+	assert.Equal(t, 1, status[0].Restarts)
+	assert.True(t, status[0].Healthy)
+	assert.Contains(t, status[0].Error, ErrWedged.Error())
+}
+
+func TestWedgedRestartWaits(t *testing.T) {
+	s := testSupervisor()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	release := make(chan struct{})
+
+	var calls atomic.Int32
+	s.Every(ctx, "heartbeat", time.Millisecond, func(context.Context) error {
+		if calls.Add(1) == 1 {
+			<-release
+		}
+		return nil
+	})
+
+	require.Eventually(t, func() bool {
+		return s.Status()[0].Restarts == 1
+	}, time.Second, time.Millisecond, "wedged")
+
+	// not restarted while the wedged call is running
+	time.Sleep(50 * time.Millisecond)
+	assert.Equal(t, int32(1), calls.Load())
+
+	close(release)
+
+	require.Eventually(t, func() bool {
+		return calls.Load() > 2
+	}, time.Second, time.Millisecond, "restarted")
+}
+
+func TestNow(t *testing.T) {
+	s := testSupervisor()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	called := make(chan struct{}, 1)
+	s.Now(ctx, "watchdog", time.Hour, func(context.Context) error {
+		called <- struct{}{}
+		return nil
+	})
+
+	select {
+	case <-called:
+	case <-time.After(time.Second):
+		require.Fail(t, "not called immediately")
+	}
+}
+
+func TestTrack(t *testing.T) {
+	s := testSupervisor()
+
+	done := s.Track("modbus")
+	assert.Empty(t, s.Status(), "running call")
+
+	time.Sleep(2 * s.minTimeout)
+
+	status := s.Status()
+	require.Len(t, status, 1)
+	assert.Equal(t, "modbus", status[0].Name)
+	assert.False(t, status[0].Healthy)
+	assert.Contains(t, status[0].Error, ErrWedged.Error())
+
+	done()
+	assert.Empty(t, s.Status())
+}
+
+func TestPanic(t *testing.T) {
+	s := testSupervisor()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	var calls atomic.Int32
+	s.Every(ctx, "panic", time.Millisecond, func(context.Context) error {
+		if calls.Add(1) == 1 {
+			panic("boom")
+		}
+		return nil
+	})
+
+	require.Eventually(t, func() bool {
+		return calls.Load() > 2
+	}, time.Second, time.Millisecond, "restarted")
+
+	status := s.Status()
+	require.Len(t, status, 1)
+	assert.Equal(t, 1, status[0].Restarts)
+	assert.Equal(t, "panic: boom", status[0].Error)
+}
+
+func TestCancel(t *testing.T) {
+	s := testSupervisor()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	s.Every(ctx, "heartbeat", time.Millisecond, func(context.Context) error { return nil })
+	require.Len(t, s.Status(), 1)
+
+	cancel()
+
+	require.Eventually(t, func() bool {
+		return len(s.Status()) == 0
+	}, time.Second, time.Millisecond, "removed")
+}
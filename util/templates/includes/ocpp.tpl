@@ -3,6 +3,12 @@ type: ocpp
 {{- if .stationid }}
 stationid: {{ .stationid }}
 {{- end }}
+{{- if .user }}
+user: {{ .user }}
+{{- end }}
+{{- if .password }}
+password: {{ .password }}
+{{- end }}
 {{- if ne .connector "1" }}
 connector: {{ .connector }}
 {{- end }}
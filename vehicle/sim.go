@@ -0,0 +1,37 @@
+package vehicle
+
+import (
+	"cmp"
+	"errors"
+
+	"github.com/evcc-io/evcc/api"
+	"github.com/evcc-io/evcc/util"
+	"github.com/evcc-io/evcc/util/sim"
+)
+
+func init() {
+	registry.Add("sim", NewSimFromConfig)
+}
+
+// NewSimFromConfig creates a simulated vehicle for validating configurations without hardware
+func NewSimFromConfig(other map[string]any) (api.Vehicle, error) {
+	cc := struct {
+		Env      string // simulated environment shared with chargers and meters
+		Title    string // referenced by simulated chargers
+		Capacity float64
+		Soc      float64
+	}{
+		Capacity: 50,
+		Soc:      20,
+	}
+
+	if err := util.DecodeOther(other, &cc); err != nil {
+		return nil, err
+	}
+
+	if cc.Title == "" {
+		return nil, errors.New("missing title")
+	}
+
+	return sim.Registered(cmp.Or(cc.Env, "default")).NewVehicle(cc.Title, cc.Capacity, cc.Soc), nil
+}